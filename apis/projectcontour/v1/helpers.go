@@ -14,7 +14,10 @@
 package v1
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 )
 
 // AuthorizationConfigured returns whether authorization  is
@@ -213,3 +216,42 @@ func truncateLongMessage(message string) string {
 	}
 	return message
 }
+
+// hstsPreloadMinMaxAge is the minimum max-age accepted by browser
+// HSTS preload lists.
+const hstsPreloadMinMaxAge = 365 * 24 * time.Hour
+
+// Validate checks that the HSTS policy has a valid, non-negative max age
+// and, if preload is requested, that it meets the preload list requirements.
+func (h *HSTSPolicy) Validate() error {
+	maxAge, err := time.ParseDuration(h.MaxAge)
+	if err != nil {
+		return fmt.Errorf("invalid max age %q: %w", h.MaxAge, err)
+	}
+	if maxAge < 0 {
+		return fmt.Errorf("invalid max age %q: must not be negative", h.MaxAge)
+	}
+
+	if h.Preload {
+		if !h.IncludeSubDomains {
+			return errors.New("preload requires includeSubDomains to be set")
+		}
+		if maxAge < hstsPreloadMinMaxAge {
+			return fmt.Errorf("preload requires a max age of at least %s, got %q", hstsPreloadMinMaxAge, h.MaxAge)
+		}
+	}
+
+	return nil
+}
+
+// Validate checks that the CSP policy has directives and that they
+// can be sent as a single header value.
+func (c *CSPPolicy) Validate() error {
+	if strings.TrimSpace(c.Directives) == "" {
+		return errors.New("directives must not be empty")
+	}
+	if strings.ContainsAny(c.Directives, "\r\n") {
+		return errors.New("directives must not contain line breaks")
+	}
+	return nil
+}
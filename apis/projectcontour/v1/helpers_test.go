@@ -985,3 +985,91 @@ Miss Bingley immediately fixed her eyes on his face, and desired he would tell h
 
 He listened to her with perfect indifference while she chose to entertain herself in this manner; and as his composure convinced her that all was safe, her wit flowed long.
 `
+
+func TestHSTSPolicyValidate(t *testing.T) {
+	tests := map[string]struct {
+		policy  HSTSPolicy
+		wantErr bool
+	}{
+		"valid max age": {
+			policy: HSTSPolicy{MaxAge: "1h"},
+		},
+		"zero max age": {
+			policy: HSTSPolicy{MaxAge: "0"},
+		},
+		"include subdomains": {
+			policy: HSTSPolicy{MaxAge: "1h", IncludeSubDomains: true},
+		},
+		"preload": {
+			policy: HSTSPolicy{MaxAge: "8760h", IncludeSubDomains: true, Preload: true},
+		},
+		"empty max age": {
+			policy:  HSTSPolicy{},
+			wantErr: true,
+		},
+		"unparseable max age": {
+			policy:  HSTSPolicy{MaxAge: "one year"},
+			wantErr: true,
+		},
+		"negative max age": {
+			policy:  HSTSPolicy{MaxAge: "-1h"},
+			wantErr: true,
+		},
+		"preload without include subdomains": {
+			policy:  HSTSPolicy{MaxAge: "8760h", Preload: true},
+			wantErr: true,
+		},
+		"preload with short max age": {
+			policy:  HSTSPolicy{MaxAge: "24h", IncludeSubDomains: true, Preload: true},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tc.policy.Validate()
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCSPPolicyValidate(t *testing.T) {
+	tests := map[string]struct {
+		policy  CSPPolicy
+		wantErr bool
+	}{
+		"single directive": {
+			policy: CSPPolicy{Directives: "default-src 'self'"},
+		},
+		"multiple directives": {
+			policy: CSPPolicy{Directives: "default-src 'self'; img-src *; frame-ancestors 'none'"},
+		},
+		"empty directives": {
+			policy:  CSPPolicy{},
+			wantErr: true,
+		},
+		"whitespace directives": {
+			policy:  CSPPolicy{Directives: "  \t "},
+			wantErr: true,
+		},
+		"line break in directives": {
+			policy:  CSPPolicy{Directives: "default-src 'self'\r\nX-Injected: true"},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tc.policy.Validate()
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
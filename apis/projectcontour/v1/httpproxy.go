@@ -74,6 +74,11 @@ type MatchCondition struct {
 	// QueryParameter specifies the query parameter condition to match.
 	// +optional
 	QueryParameter *QueryParameterMatchCondition `json:"queryParameter,omitempty"`
+
+	// Source filters the requests of the route by their client address.
+	// Unlike the other conditions, it does not select the route.
+	// +optional
+	Source *SourceFilter `json:"source,omitempty"`
 }
 
 // HeaderMatchCondition specifies how to conditionally match against HTTP
@@ -161,6 +166,24 @@ type QueryParameterMatchCondition struct {
 	Present bool `json:"present,omitempty"`
 }
 
+// SourceFilter allows the requests of a route from the addresses of
+// the clients making them. The client address is determined the same
+// way as for X-Forwarded-For handling, so when Envoy is configured to
+// trust a number of proxy hops in front of it, the address those
+// proxies report is used.
+//
+// Envoy cannot select between routes based on the client address, so a
+// request from outside the listed ranges is rejected with a 403 response
+// rather than being matched against later routes. Routes that only
+// differ by their source filters are therefore invalid.
+type SourceFilter struct {
+	// CIDRs is a list of address ranges in CIDR notation, e.g.
+	// "10.0.0.0/8" or "2001:db8::/32". The filter allows requests
+	// whose client address falls within any of the ranges.
+	// +kubebuilder:validation:MinItems=1
+	CIDRs []string `json:"cidrs"`
+}
+
 // ExtensionServiceReference names an ExtensionService resource.
 type ExtensionServiceReference struct {
 	// API version of the referent.
@@ -277,6 +300,14 @@ type VirtualHost struct {
 	// +optional
 	TLS *TLS `json:"tls,omitempty"`
 
+	// RequireTLS rejects plaintext HTTP requests for this virtual host
+	// with a 426 Upgrade Required response rather than redirecting them
+	// to HTTPS. Requires TLS to be configured on the virtual host. When
+	// set, route-level permitInsecure settings have no effect.
+	//
+	// +optional
+	RequireTLS bool `json:"requireTLS,omitempty"`
+
 	// This field configures an extension service to perform
 	// authorization for this virtual host. Authorization can
 	// only be configured on virtual hosts that have TLS enabled.
@@ -289,6 +320,24 @@ type VirtualHost struct {
 	// Specifies the cross-origin policy to apply to the VirtualHost.
 	// +optional
 	CORSPolicy *CORSPolicy `json:"corsPolicy,omitempty"`
+	// Specifies the HTTP Strict Transport Security policy to apply
+	// to HTTPS responses from the VirtualHost. Overrides the global
+	// HSTS policy, if any. Requires TLS to be configured on the
+	// virtual host.
+	// +optional
+	HSTSPolicy *HSTSPolicy `json:"hstsPolicy,omitempty"`
+	// Specifies the Content Security Policy to return on responses
+	// from the VirtualHost. Overrides the global CSP policy, if any,
+	// and may itself be overridden per route.
+	// +optional
+	CSPPolicy *CSPPolicy `json:"cspPolicy,omitempty"`
+	// Specifies the name of a response header, such as
+	// x-upstream-cluster, that Envoy sets to the name of the
+	// upstream cluster that served the request. Overrides the
+	// global upstream cluster header, if any.
+	// +kubebuilder:validation:Pattern="^[a-zA-Z0-9!#$%&'*+.^_`|~-]+$"
+	// +optional
+	UpstreamClusterHeader string `json:"upstreamClusterHeader,omitempty"`
 	// The policy for rate limiting on the virtual host.
 	// +optional
 	RateLimitPolicy *RateLimitPolicy `json:"rateLimitPolicy,omitempty"`
@@ -419,6 +468,40 @@ type TLS struct {
 // +kubebuilder:validation:Pattern="^[a-zA-Z0-9!#$%&'*+.^_`|~-]+$"
 type CORSHeaderValue string
 
+// HSTSPolicy defines the HTTP Strict Transport Security (HSTS) policy
+// that is advertised to clients in the Strict-Transport-Security
+// response header. See RFC 6797.
+type HSTSPolicy struct {
+	// MaxAge is how long clients should remember that the virtual host
+	// is only to be accessed over HTTPS. MaxAge durations are expressed
+	// in the Go [Duration format](https://godoc.org/time#ParseDuration)
+	// and are advertised with a granularity of seconds.
+	// Valid time units are "s", "m", "h". A value of 0 instructs
+	// clients to forget any previously advertised policy.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^(((\d*(\.\d*)?h)|(\d*(\.\d*)?m)|(\d*(\.\d*)?s))+|0)$`
+	MaxAge string `json:"maxAge"`
+	// IncludeSubDomains applies the policy to all subdomains of the
+	// virtual host as well.
+	// +optional
+	IncludeSubDomains bool `json:"includeSubDomains,omitempty"`
+	// Preload signals consent to have the virtual host included in
+	// browser HSTS preload lists. Requires IncludeSubDomains and a
+	// MaxAge of at least one year (8760h).
+	// +optional
+	Preload bool `json:"preload,omitempty"`
+}
+
+// CSPPolicy defines the Content Security Policy (CSP) returned to
+// clients in the Content-Security-Policy response header.
+type CSPPolicy struct {
+	// Directives is the policy returned as the header value, for
+	// example "default-src 'self'; img-src *".
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Directives string `json:"directives"`
+}
+
 // CORSPolicy allows setting the CORS policy
 type CORSPolicy struct {
 	// Specifies whether the resource allows credentials.
@@ -495,6 +578,13 @@ type Route struct {
 	// The load balancing policy for this route.
 	// +optional
 	LoadBalancerPolicy *LoadBalancerPolicy `json:"loadBalancerPolicy,omitempty"`
+	// IsolateConnectionPool gives this route its own upstream clusters,
+	// and so its own connection pools, rather than sharing them with
+	// other routes that proxy to the same services. This keeps a slow
+	// route from exhausting the connections available to other routes.
+	// Requires at least one service.
+	// +optional
+	IsolateConnectionPool bool `json:"isolateConnectionPool,omitempty"`
 	// The policy for rewriting the path of the request URL
 	// after the request has been routed to a Service.
 	//
@@ -507,6 +597,12 @@ type Route struct {
 	// Rewriting the 'Host' header is not supported.
 	// +optional
 	ResponseHeadersPolicy *HeadersPolicy `json:"responseHeadersPolicy,omitempty"`
+	// The Content Security Policy to return on responses from this
+	// route. Overrides the virtual host and global CSP policies. A
+	// Content-Security-Policy header set by the ResponseHeadersPolicy
+	// takes precedence over this field.
+	// +optional
+	CSPPolicy *CSPPolicy `json:"cspPolicy,omitempty"`
 	// The policies for rewriting Set-Cookie header attributes. Note that
 	// rewritten cookie names must be unique in this list. Order rewrite
 	// policies are specified in does not matter.
@@ -929,6 +1025,28 @@ type Service struct {
 	// Slow start will gradually increase amount of traffic to a newly added endpoint.
 	// +optional
 	SlowStartPolicy *SlowStartPolicy `json:"slowStartPolicy,omitempty"`
+	// IgnoreNewHostsUntilFirstHC keeps new endpoints of the service from
+	// receiving traffic until they pass their first active health check.
+	// Requires a health check policy.
+	// +optional
+	IgnoreNewHostsUntilFirstHC bool `json:"ignoreNewHostsUntilFirstHC,omitempty"`
+	// Priority is the failover priority level of the service. Services with
+	// priority 0 receive the route's traffic; the endpoints of services with
+	// a higher priority are only used once the lower priorities are
+	// unhealthy. Priorities within a route must start at 0 and must not
+	// skip any levels.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=127
+	Priority uint32 `json:"priority,omitempty"`
+	// AltStatName overrides the name under which the statistics of the
+	// Envoy cluster for this service are reported, so that they remain
+	// stable when the service is renamed. It may only contain
+	// alphanumerics, '-' and '_'.
+	// +optional
+	// +kubebuilder:validation:MaxLength=60
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_-]+$`
+	AltStatName string `json:"altStatName,omitempty"`
 }
 
 // HTTPHealthCheckPolicy defines health checks on the upstream service.
@@ -1047,6 +1165,24 @@ type RetryPolicy struct {
 	// This field is only respected when you include `retriable-status-codes` in the `RetryOn` field.
 	// +optional
 	RetriableStatusCodes []uint32 `json:"retriableStatusCodes,omitempty"`
+	// Budget limits the concurrent retries to the route's services to a
+	// percentage of their active requests, so that retries cannot overload
+	// a service that is already failing. When set, the budget replaces the
+	// max-retries circuit breaker of the services.
+	// +optional
+	Budget *RetryBudget `json:"budget,omitempty"`
+}
+
+// RetryBudget defines the retry budget of the services of a route.
+type RetryBudget struct {
+	// Percent is the percentage of active requests that may be retries.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	Percent uint32 `json:"percent"`
+	// MinRetryConcurrency is the number of concurrent retries that are
+	// allowed regardless of the budget. If unset, Envoy allows 3.
+	// +optional
+	MinRetryConcurrency uint32 `json:"minRetryConcurrency,omitempty"`
 }
 
 // ReplacePrefix describes a path prefix replacement.
@@ -123,6 +123,21 @@ func (in *CORSPolicy) DeepCopy() *CORSPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CSPPolicy) DeepCopyInto(out *CSPPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CSPPolicy.
+func (in *CSPPolicy) DeepCopy() *CSPPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(CSPPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CertificateDelegation) DeepCopyInto(out *CertificateDelegation) {
 	*out = *in
@@ -321,6 +336,21 @@ func (in *GlobalRateLimitPolicy) DeepCopy() *GlobalRateLimitPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HSTSPolicy) DeepCopyInto(out *HSTSPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HSTSPolicy.
+func (in *HSTSPolicy) DeepCopy() *HSTSPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(HSTSPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPDirectResponsePolicy) DeepCopyInto(out *HTTPDirectResponsePolicy) {
 	*out = *in
@@ -720,6 +750,11 @@ func (in *MatchCondition) DeepCopyInto(out *MatchCondition) {
 		*out = new(QueryParameterMatchCondition)
 		**out = **in
 	}
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(SourceFilter)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MatchCondition.
@@ -974,6 +1009,21 @@ func (in *RequestHeaderValueMatchDescriptor) DeepCopy() *RequestHeaderValueMatch
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryBudget) DeepCopyInto(out *RetryBudget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryBudget.
+func (in *RetryBudget) DeepCopy() *RetryBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RetryPolicy) DeepCopyInto(out *RetryPolicy) {
 	*out = *in
@@ -987,6 +1037,11 @@ func (in *RetryPolicy) DeepCopyInto(out *RetryPolicy) {
 		*out = make([]uint32, len(*in))
 		copy(*out, *in)
 	}
+	if in.Budget != nil {
+		in, out := &in.Budget, &out.Budget
+		*out = new(RetryBudget)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryPolicy.
@@ -1056,6 +1111,11 @@ func (in *Route) DeepCopyInto(out *Route) {
 		*out = new(HeadersPolicy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CSPPolicy != nil {
+		in, out := &in.CSPPolicy, &out.CSPPolicy
+		*out = new(CSPPolicy)
+		**out = **in
+	}
 	if in.CookieRewritePolicies != nil {
 		in, out := &in.CookieRewritePolicies, &out.CookieRewritePolicies
 		*out = make([]CookieRewritePolicy, len(*in))
@@ -1162,6 +1222,26 @@ func (in *SlowStartPolicy) DeepCopy() *SlowStartPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceFilter) DeepCopyInto(out *SourceFilter) {
+	*out = *in
+	if in.CIDRs != nil {
+		in, out := &in.CIDRs, &out.CIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceFilter.
+func (in *SourceFilter) DeepCopy() *SourceFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SubCondition) DeepCopyInto(out *SubCondition) {
 	*out = *in
@@ -1420,6 +1500,16 @@ func (in *VirtualHost) DeepCopyInto(out *VirtualHost) {
 		*out = new(CORSPolicy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HSTSPolicy != nil {
+		in, out := &in.HSTSPolicy, &out.HSTSPolicy
+		*out = new(HSTSPolicy)
+		**out = **in
+	}
+	if in.CSPPolicy != nil {
+		in, out := &in.CSPPolicy, &out.CSPPolicy
+		*out = new(CSPPolicy)
+		**out = **in
+	}
 	if in.RateLimitPolicy != nil {
 		in, out := &in.RateLimitPolicy, &out.RateLimitPolicy
 		*out = new(RateLimitPolicy)
@@ -62,6 +62,13 @@ type ContourConfigurationSpec struct {
 	// +optional
 	EnableExternalNameService *bool `json:"enableExternalNameService,omitempty"`
 
+	// UseEndpointSlices makes Contour watch EndpointSlices, rather
+	// than Endpoints, to discover the endpoints of Services.
+	//
+	// Contour's default is false.
+	// +optional
+	UseEndpointSlices *bool `json:"useEndpointSlices,omitempty"`
+
 	// GlobalExternalAuthorization allows envoys external authorization filter
 	// to be enabled for all virtual hosts.
 	// +optional
@@ -139,6 +146,41 @@ type GatewayConfig struct {
 	// Exactly one of ControllerName or GatewayRef must be set.
 	// +optional
 	GatewayRef *NamespacedName `json:"gatewayRef,omitempty"`
+
+	// AllowedHostnameSuffixes restricts the hostnames of the routes
+	// attached to the Gateway to those under one of the suffixes. For
+	// example, "example.com" allows "example.com", "foo.example.com"
+	// and "*.example.com". Routes that would match any other hostname,
+	// including routes without hostnames on listeners without one, are
+	// not accepted. If unset, routes may match any hostname.
+	// +optional
+	AllowedHostnameSuffixes []string `json:"allowedHostnameSuffixes,omitempty"`
+
+	// DisabledRouteKinds are the route kinds that are not supported.
+	// Routes of these kinds are not accepted, and listeners that list
+	// them in their allowed kinds have a ResolvedRefs: false condition.
+	// Values: `HTTPRoute`, `TLSRoute`, `GRPCRoute`.
+	// +optional
+	DisabledRouteKinds []string `json:"disabledRouteKinds,omitempty"`
+
+	// DefaultBackend is the Service that requests to the Gateway's HTTP
+	// listeners are routed to when no route matches them. Routes that
+	// match all paths of a hostname take precedence over it. If unset,
+	// Envoy responds with a 404.
+	// +optional
+	DefaultBackend *GatewayDefaultBackend `json:"defaultBackend,omitempty"`
+}
+
+// GatewayDefaultBackend is a reference to a port of a Service.
+type GatewayDefaultBackend struct {
+	// Namespace is the namespace of the Service.
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the Service.
+	Name string `json:"name"`
+
+	// Port is the port of the Service.
+	Port int32 `json:"port"`
 }
 
 // TLS holds TLS file config details.
@@ -341,6 +383,15 @@ type EnvoyListenerConfig struct {
 	// +optional
 	DisableMergeSlashes *bool `json:"disableMergeSlashes,omitempty"`
 
+	// SetRequestIDInResponse returns the ID of each request in the
+	// X-Request-Id response header, so that clients can correlate
+	// their requests with the access logs, where it is logged as
+	// %REQ(X-REQUEST-ID)%.
+	//
+	// Contour's default is false.
+	// +optional
+	SetRequestIDInResponse *bool `json:"setRequestIDInResponse,omitempty"`
+
 	// Defines the action to be applied to the Server header on the response path.
 	// When configured as overwrite, overwrites any Server header with "envoy".
 	// When configured as append_if_absent, if a Server header is present, pass it through, otherwise set it to "envoy".
@@ -366,6 +417,79 @@ type EnvoyListenerConfig struct {
 	// TLS holds various configurable Envoy TLS listener values.
 	// +optional
 	TLS *EnvoyTLS `json:"tls,omitempty"`
+
+	// KeepAlive configures the keepalive of downstream connections
+	// to the HTTP and HTTPS listeners.
+	// +optional
+	KeepAlive *EnvoyListenerKeepAlive `json:"keepAlive,omitempty"`
+
+	// HTTP2MaxConcurrentStreams is the maximum number of concurrent
+	// streams allowed on a downstream HTTP/2 connection to the HTTP and
+	// HTTPS listeners. Streams opened beyond the limit are refused with
+	// a RST_STREAM frame.
+	//
+	// If unset, Envoy's default of 2147483647 is used.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=2147483647
+	// +optional
+	HTTP2MaxConcurrentStreams *uint32 `json:"http2MaxConcurrentStreams,omitempty"`
+}
+
+// EnvoyListenerKeepAlive configures the TCP and HTTP/2 keepalive of
+// downstream connections.
+type EnvoyListenerKeepAlive struct {
+	// TCP configures the TCP keepalive probes sent on idle
+	// downstream connections.
+	// +optional
+	TCP *TCPKeepAlive `json:"tcp,omitempty"`
+
+	// HTTP2 configures the HTTP/2 PING frames sent on downstream
+	// HTTP/2 connections.
+	// +optional
+	HTTP2 *HTTP2KeepAlive `json:"http2,omitempty"`
+}
+
+// TCPKeepAlive configures TCP keepalive probes.
+type TCPKeepAlive struct {
+	// IdleTime is the number of seconds a connection needs to be
+	// idle before TCP keepalive probes are sent.
+	//
+	// Contour's default is 45.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	IdleTime int32 `json:"idleTime,omitempty"`
+
+	// ProbeInterval is the number of seconds between TCP keepalive
+	// probes.
+	//
+	// Contour's default is 5.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ProbeInterval int32 `json:"probeInterval,omitempty"`
+
+	// MaxProbes is the number of unacknowledged TCP keepalive probes
+	// after which the connection is closed.
+	//
+	// Contour's default is 9.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxProbes int32 `json:"maxProbes,omitempty"`
+}
+
+// HTTP2KeepAlive configures HTTP/2 PING frames.
+type HTTP2KeepAlive struct {
+	// Interval is how often a PING frame is sent on an HTTP/2
+	// connection. Must be a valid Go duration string.
+	// +kubebuilder:validation:MinLength=1
+	Interval string `json:"interval"`
+
+	// Timeout is how long to wait for the acknowledgement of a PING
+	// frame before closing the connection. Must be a valid Go
+	// duration string.
+	//
+	// Contour's default is 20s.
+	// +optional
+	Timeout string `json:"timeout,omitempty"`
 }
 
 // EnvoyTLS describes tls parameters for Envoy listneners.
@@ -532,6 +656,36 @@ type TimeoutParameters struct {
 	// for more information.
 	// +optional
 	ConnectTimeout *string `json:"connectTimeout,omitempty"`
+
+	// TimeoutResponse customizes the response Envoy sends to the client
+	// when a request to the upstream service times out. If not set, Envoy
+	// returns its default 504 response.
+	//
+	// See https://www.envoyproxy.io/docs/envoy/latest/configuration/http/http_conn_man/local_reply
+	// for more information.
+	// +optional
+	TimeoutResponse *TimeoutResponse `json:"timeoutResponse,omitempty"`
+}
+
+// TimeoutResponse defines the local reply returned by Envoy when an
+// upstream request times out.
+type TimeoutResponse struct {
+	// StatusCode is the HTTP status code of the response.
+	// Defaults to 504.
+	// +kubebuilder:validation:Minimum=400
+	// +kubebuilder:validation:Maximum=599
+	// +optional
+	StatusCode *int `json:"statusCode,omitempty"`
+
+	// Body is the content of the response body. If not set,
+	// Envoy's default body is used.
+	// +optional
+	Body string `json:"body,omitempty"`
+
+	// ContentType is the value of the Content-Type header of the
+	// response. Requires Body to be set. Defaults to "text/plain".
+	// +optional
+	ContentType string `json:"contentType,omitempty"`
 }
 
 // ClusterDNSFamilyType is the Ip family to use for resolving DNS
@@ -586,6 +740,64 @@ type ClusterParameters struct {
 	// Other values will produce an error.
 	// +optional
 	DNSLookupFamily ClusterDNSFamilyType `json:"dnsLookupFamily,omitempty"`
+
+	// LocalityWeightedLB enables locality weighted load balancing for
+	// service clusters. Endpoints are grouped into localities by the
+	// topology.kubernetes.io/zone label of the node they run on.
+	//
+	// Contour's default is disabled.
+	// +optional
+	LocalityWeightedLB *LocalityWeightedLBConfig `json:"localityWeightedLB,omitempty"`
+
+	// TopologyAwareRouting enables zone aware routing for service
+	// clusters, so that each Envoy prefers the endpoints in its own zone
+	// and falls back to the endpoints in other zones when its zone does
+	// not have enough ready endpoints. Endpoints are grouped into zones
+	// by their EndpointSlice topology hints. Envoy must be bootstrapped
+	// with --topology-aware-routing and started with its zone.
+	// Cannot be combined with LocalityWeightedLB.
+	//
+	// Contour's default is disabled.
+	// +optional
+	TopologyAwareRouting *TopologyAwareRoutingConfig `json:"topologyAwareRouting,omitempty"`
+
+	// EndpointWeightLabel is the name of a pod label holding the load
+	// balancing weight of the pod's endpoints, an integer between 1
+	// and 65535. Endpoints of pods without the label have a weight of 1.
+	//
+	// Contour's default is unset, which gives every endpoint the same weight.
+	// +optional
+	EndpointWeightLabel string `json:"endpointWeightLabel,omitempty"`
+
+	// DefaultLoadBalancerPolicy is the load balancer strategy of the
+	// clusters whose route or service does not set one.
+	//
+	// Values: `RoundRobin` (default), `WeightedLeastRequest`, `Random`.
+	//
+	// Other values will produce an error.
+	// +kubebuilder:validation:Enum=RoundRobin;WeightedLeastRequest;Random
+	// +optional
+	DefaultLoadBalancerPolicy string `json:"defaultLoadBalancerPolicy,omitempty"`
+}
+
+// LocalityWeightedLBConfig defines how traffic is weighted across zones.
+type LocalityWeightedLBConfig struct {
+	// ZoneWeights sets the load balancing weight of each zone. Zones
+	// without a weight are weighted by their number of endpoints.
+	// +optional
+	ZoneWeights map[string]uint32 `json:"zoneWeights,omitempty"`
+}
+
+// TopologyAwareRoutingConfig defines how Envoy routes requests to the
+// endpoints in its own zone.
+type TopologyAwareRoutingConfig struct {
+	// MinClusterSize is the number of endpoints a cluster needs for
+	// Envoy to route its requests zone aware. Clusters with fewer
+	// endpoints are balanced across all zones.
+	//
+	// Envoy's default is 6.
+	// +optional
+	MinClusterSize uint32 `json:"minClusterSize,omitempty"`
 }
 
 // HTTPProxyConfig defines parameters on HTTPProxy.
@@ -674,12 +886,42 @@ type PolicyConfig struct {
 	// Contour's default is false.
 	// +optional
 	ApplyToIngress *bool `json:"applyToIngress,omitempty"`
+
+	// ApplyToGatewayAPI determines if the Policies will apply to
+	// Gateway API routes.
+	//
+	// Contour's default is false.
+	// +optional
+	ApplyToGatewayAPI *bool `json:"applyToGatewayAPI,omitempty"`
+
+	// HSTSPolicy defines the HTTP Strict Transport Security policy
+	// applied to HTTPS responses from all HTTPProxy virtual hosts that
+	// do not set their own.
+	// +optional
+	HSTSPolicy *contour_api_v1.HSTSPolicy `json:"hstsPolicy,omitempty"`
+
+	// CSPPolicy defines the Content Security Policy returned on
+	// responses from all HTTPProxy routes that do not set their own,
+	// either on the route or on the virtual host.
+	// +optional
+	CSPPolicy *contour_api_v1.CSPPolicy `json:"cspPolicy,omitempty"`
+
+	// UpstreamClusterHeader is the name of a response header that
+	// Envoy sets to the name of the upstream cluster that served the
+	// request, on all HTTPProxy virtual hosts that do not set their own.
+	// +optional
+	UpstreamClusterHeader string `json:"upstreamClusterHeader,omitempty"`
 }
 
 type HeadersPolicy struct {
 	// +optional
 	Set map[string]string `json:"set,omitempty"`
 
+	// Add appends the headers to any existing values rather
+	// than replacing them.
+	// +optional
+	Add map[string]string `json:"add,omitempty"`
+
 	// +optional
 	Remove []string `json:"remove,omitempty"`
 }
@@ -15,8 +15,12 @@ package v1alpha1
 
 import (
 	"fmt"
+	"math"
+	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 // Validate configuration that is not already covered by CRD validation.
@@ -38,6 +42,20 @@ func (c *ContourConfigurationSpec) Validate() error {
 	if c.Gateway != nil {
 		validateFuncs = append(validateFuncs, c.Gateway.Validate)
 	}
+	if c.Policy != nil && c.Policy.HSTSPolicy != nil {
+		validateFuncs = append(validateFuncs, c.Policy.HSTSPolicy.Validate)
+	}
+	if c.Policy != nil && c.Policy.CSPPolicy != nil {
+		validateFuncs = append(validateFuncs, c.Policy.CSPPolicy.Validate)
+	}
+	if c.Policy != nil && c.Policy.UpstreamClusterHeader != "" {
+		validateFuncs = append(validateFuncs, func() error {
+			if msgs := validation.IsHTTPHeaderName(c.Policy.UpstreamClusterHeader); len(msgs) != 0 {
+				return fmt.Errorf("invalid upstream cluster header %q: %v", c.Policy.UpstreamClusterHeader, msgs)
+			}
+			return nil
+		})
+	}
 
 	for _, validate := range validateFuncs {
 		if err := validate(); err != nil {
@@ -97,6 +115,55 @@ func (e *EnvoyConfig) Validate() error {
 		}
 	}
 
+	// Cluster.DefaultLoadBalancerPolicy
+	if e.Cluster != nil {
+		switch e.Cluster.DefaultLoadBalancerPolicy {
+		case "", "RoundRobin", "WeightedLeastRequest", "Random":
+		default:
+			return fmt.Errorf("invalid default load balancer policy %q", e.Cluster.DefaultLoadBalancerPolicy)
+		}
+	}
+
+	// Cluster.EndpointWeightLabel
+	if e.Cluster != nil && e.Cluster.EndpointWeightLabel != "" {
+		if msgs := validation.IsQualifiedName(e.Cluster.EndpointWeightLabel); len(msgs) != 0 {
+			return fmt.Errorf("invalid endpoint weight label %q: %v", e.Cluster.EndpointWeightLabel, msgs)
+		}
+	}
+
+	// Cluster.LocalityWeightedLB
+	if e.Cluster != nil && e.Cluster.LocalityWeightedLB != nil {
+		if err := e.Cluster.LocalityWeightedLB.Validate(); err != nil {
+			return err
+		}
+	}
+
+	// Cluster.TopologyAwareRouting
+	if e.Cluster != nil && e.Cluster.TopologyAwareRouting != nil && e.Cluster.LocalityWeightedLB != nil {
+		return fmt.Errorf("cannot enable both topology aware routing and locality weighted load balancing")
+	}
+
+	// Timeouts.TimeoutResponse
+	if e.Timeouts != nil && e.Timeouts.TimeoutResponse != nil {
+		if err := e.Timeouts.TimeoutResponse.Validate(); err != nil {
+			return err
+		}
+	}
+
+	// Listener.KeepAlive
+	if e.Listener != nil && e.Listener.KeepAlive != nil {
+		if err := e.Listener.KeepAlive.Validate(); err != nil {
+			return err
+		}
+	}
+
+	// Listener.HTTP2MaxConcurrentStreams
+	if e.Listener != nil && e.Listener.HTTP2MaxConcurrentStreams != nil {
+		if err := ValidateHTTP2MaxConcurrentStreams(*e.Listener.HTTP2MaxConcurrentStreams); err != nil {
+			return err
+		}
+	}
+
 	// Envoy TLS configuration
 	if e.Listener != nil && e.Listener.TLS != nil {
 		return e.Listener.TLS.Validate()
@@ -105,6 +172,62 @@ func (e *EnvoyConfig) Validate() error {
 	return nil
 }
 
+// Validate ensures the TimeoutResponse configuration is valid.
+func (t *TimeoutResponse) Validate() error {
+	if t.StatusCode != nil && (*t.StatusCode < 400 || *t.StatusCode > 599) {
+		return fmt.Errorf("invalid timeout response status code %d: must be between 400 and 599", *t.StatusCode)
+	}
+
+	if t.ContentType != "" && t.Body == "" {
+		return fmt.Errorf("invalid timeout response: content type %q requires a body", t.ContentType)
+	}
+
+	if strings.ContainsAny(t.ContentType, "\r\n") {
+		return fmt.Errorf("invalid timeout response: content type must not contain line breaks")
+	}
+
+	return nil
+}
+
+// ValidateHTTP2MaxConcurrentStreams ensures the maximum number of
+// concurrent streams on a downstream HTTP/2 connection is in the range
+// accepted by Envoy.
+func ValidateHTTP2MaxConcurrentStreams(streams uint32) error {
+	if streams < 1 || streams > math.MaxInt32 {
+		return fmt.Errorf("invalid listener HTTP/2 max concurrent streams %d: must be between 1 and %d", streams, math.MaxInt32)
+	}
+	return nil
+}
+
+// Validate ensures the EnvoyListenerKeepAlive configuration is valid.
+func (k *EnvoyListenerKeepAlive) Validate() error {
+	if k.TCP != nil && (k.TCP.IdleTime < 0 || k.TCP.ProbeInterval < 0 || k.TCP.MaxProbes < 0) {
+		return fmt.Errorf("invalid listener TCP keepalive: values must not be negative")
+	}
+
+	if k.HTTP2 != nil {
+		interval, err := time.ParseDuration(k.HTTP2.Interval)
+		if err != nil {
+			return fmt.Errorf("invalid listener HTTP/2 keepalive interval %q: %v", k.HTTP2.Interval, err)
+		}
+		if interval <= 0 {
+			return fmt.Errorf("invalid listener HTTP/2 keepalive interval %q: must be positive", k.HTTP2.Interval)
+		}
+
+		if k.HTTP2.Timeout != "" {
+			timeout, err := time.ParseDuration(k.HTTP2.Timeout)
+			if err != nil {
+				return fmt.Errorf("invalid listener HTTP/2 keepalive timeout %q: %v", k.HTTP2.Timeout, err)
+			}
+			if timeout < time.Millisecond {
+				return fmt.Errorf("invalid listener HTTP/2 keepalive timeout %q: must be at least 1ms", k.HTTP2.Timeout)
+			}
+		}
+	}
+
+	return nil
+}
+
 // Validate ensures EnvoyTLS configuration is valid.
 func (e *EnvoyTLS) Validate() error {
 	if e.MinimumProtocolVersion != "" && e.MinimumProtocolVersion != "1.2" && e.MinimumProtocolVersion != "1.3" {
@@ -156,6 +279,29 @@ func (g *GatewayConfig) Validate() error {
 		return fmt.Errorf("invalid gateway configuration: exactly one of controller name or gateway ref must be specified")
 	}
 
+	for _, suffix := range g.AllowedHostnameSuffixes {
+		if errs := validation.IsDNS1123Subdomain(suffix); errs != nil {
+			return fmt.Errorf("invalid gateway configuration: invalid allowed hostname suffix %q: %s", suffix, strings.Join(errs, ", "))
+		}
+	}
+
+	for _, kind := range g.DisabledRouteKinds {
+		switch kind {
+		case "HTTPRoute", "TLSRoute", "GRPCRoute":
+		default:
+			return fmt.Errorf("invalid gateway configuration: invalid disabled route kind %q, must be HTTPRoute, TLSRoute or GRPCRoute", kind)
+		}
+	}
+
+	if b := g.DefaultBackend; b != nil {
+		if len(b.Namespace) == 0 || len(b.Name) == 0 {
+			return fmt.Errorf("invalid gateway configuration: default backend namespace and name must be specified")
+		}
+		if b.Port < 1 || b.Port > 65535 {
+			return fmt.Errorf("invalid gateway configuration: invalid default backend port %d", b.Port)
+		}
+	}
+
 	return nil
 }
 
@@ -219,3 +365,13 @@ func endpointsInConfict(health *HealthConfig, metrics *MetricsConfig) error {
 	}
 	return nil
 }
+
+// Validate ensures that every zone weight is a valid load balancing weight.
+func (l *LocalityWeightedLBConfig) Validate() error {
+	for zone, weight := range l.ZoneWeights {
+		if weight == 0 {
+			return fmt.Errorf("invalid locality weighted load balancing weight for zone %q: must be greater than 0", zone)
+		}
+	}
+	return nil
+}
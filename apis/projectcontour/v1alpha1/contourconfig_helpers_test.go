@@ -16,6 +16,7 @@ package v1alpha1_test
 import (
 	"testing"
 
+	contour_api_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
 	"github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -142,6 +143,61 @@ func TestContourConfigurationSpecValidate(t *testing.T) {
 		require.Error(t, c.Validate())
 	})
 
+	t.Run("listener keepalive validation", func(t *testing.T) {
+		c := v1alpha1.ContourConfigurationSpec{
+			Envoy: &v1alpha1.EnvoyConfig{
+				Listener: &v1alpha1.EnvoyListenerConfig{
+					KeepAlive: &v1alpha1.EnvoyListenerKeepAlive{
+						TCP: &v1alpha1.TCPKeepAlive{
+							IdleTime: 30,
+						},
+					},
+				},
+			},
+		}
+		require.NoError(t, c.Validate())
+
+		c.Envoy.Listener.KeepAlive.TCP.MaxProbes = -1
+		require.Error(t, c.Validate())
+
+		c.Envoy.Listener.KeepAlive.TCP.MaxProbes = 3
+		c.Envoy.Listener.KeepAlive.HTTP2 = &v1alpha1.HTTP2KeepAlive{
+			Interval: "30s",
+		}
+		require.NoError(t, c.Validate())
+
+		c.Envoy.Listener.KeepAlive.HTTP2.Timeout = "5s"
+		require.NoError(t, c.Validate())
+
+		c.Envoy.Listener.KeepAlive.HTTP2.Timeout = "0s"
+		require.Error(t, c.Validate())
+
+		c.Envoy.Listener.KeepAlive.HTTP2.Timeout = ""
+		c.Envoy.Listener.KeepAlive.HTTP2.Interval = "thirty seconds"
+		require.Error(t, c.Validate())
+
+		c.Envoy.Listener.KeepAlive.HTTP2.Interval = "0s"
+		require.Error(t, c.Validate())
+	})
+
+	t.Run("listener HTTP/2 max concurrent streams validation", func(t *testing.T) {
+		streams := uint32(100)
+		c := v1alpha1.ContourConfigurationSpec{
+			Envoy: &v1alpha1.EnvoyConfig{
+				Listener: &v1alpha1.EnvoyListenerConfig{
+					HTTP2MaxConcurrentStreams: &streams,
+				},
+			},
+		}
+		require.NoError(t, c.Validate())
+
+		streams = 0
+		require.Error(t, c.Validate())
+
+		streams = 1 << 31
+		require.Error(t, c.Validate())
+	})
+
 	t.Run("gateway validation", func(t *testing.T) {
 		c := v1alpha1.ContourConfigurationSpec{
 			Gateway: &v1alpha1.GatewayConfig{},
@@ -157,6 +213,169 @@ func TestContourConfigurationSpecValidate(t *testing.T) {
 		c.Gateway.ControllerName = "foo"
 		c.Gateway.GatewayRef = &v1alpha1.NamespacedName{Namespace: "ns", Name: "name"}
 		require.Error(t, c.Validate())
+
+		c.Gateway.ControllerName = ""
+		c.Gateway.AllowedHostnameSuffixes = []string{"example.com", "projectcontour.io"}
+		require.NoError(t, c.Validate())
+
+		c.Gateway.AllowedHostnameSuffixes = []string{"*.example.com"}
+		require.Error(t, c.Validate())
+
+		c.Gateway.AllowedHostnameSuffixes = nil
+		c.Gateway.DisabledRouteKinds = []string{"TLSRoute", "GRPCRoute"}
+		require.NoError(t, c.Validate())
+
+		c.Gateway.DisabledRouteKinds = []string{"TCPRoute"}
+		require.Error(t, c.Validate())
+
+		c.Gateway.DisabledRouteKinds = nil
+		c.Gateway.DefaultBackend = &v1alpha1.GatewayDefaultBackend{Namespace: "ns", Name: "default-backend", Port: 80}
+		require.NoError(t, c.Validate())
+
+		c.Gateway.DefaultBackend = &v1alpha1.GatewayDefaultBackend{Namespace: "ns", Port: 80}
+		require.Error(t, c.Validate())
+
+		c.Gateway.DefaultBackend = &v1alpha1.GatewayDefaultBackend{Namespace: "ns", Name: "default-backend"}
+		require.Error(t, c.Validate())
+	})
+
+	t.Run("upstream cluster header validation", func(t *testing.T) {
+		c := v1alpha1.ContourConfigurationSpec{
+			Policy: &v1alpha1.PolicyConfig{
+				UpstreamClusterHeader: "x-upstream-cluster",
+			},
+		}
+		require.NoError(t, c.Validate())
+
+		c.Policy.UpstreamClusterHeader = "x upstream cluster"
+		require.Error(t, c.Validate())
+	})
+
+	t.Run("hsts policy validation", func(t *testing.T) {
+		c := v1alpha1.ContourConfigurationSpec{
+			Policy: &v1alpha1.PolicyConfig{
+				HSTSPolicy: &contour_api_v1.HSTSPolicy{
+					MaxAge: "8760h",
+				},
+			},
+		}
+		require.NoError(t, c.Validate())
+
+		c.Policy.HSTSPolicy.MaxAge = "-1s"
+		require.Error(t, c.Validate())
+
+		c.Policy.HSTSPolicy.MaxAge = "8760h"
+		c.Policy.HSTSPolicy.Preload = true
+		require.Error(t, c.Validate())
+
+		c.Policy.HSTSPolicy.IncludeSubDomains = true
+		require.NoError(t, c.Validate())
+	})
+
+	t.Run("csp policy validation", func(t *testing.T) {
+		c := v1alpha1.ContourConfigurationSpec{
+			Policy: &v1alpha1.PolicyConfig{
+				CSPPolicy: &contour_api_v1.CSPPolicy{
+					Directives: "default-src 'self'",
+				},
+			},
+		}
+		require.NoError(t, c.Validate())
+
+		c.Policy.CSPPolicy.Directives = " "
+		require.Error(t, c.Validate())
+	})
+
+	t.Run("timeout response validation", func(t *testing.T) {
+		c := v1alpha1.ContourConfigurationSpec{
+			Envoy: &v1alpha1.EnvoyConfig{
+				Timeouts: &v1alpha1.TimeoutParameters{
+					TimeoutResponse: &v1alpha1.TimeoutResponse{
+						Body: "upstream timed out",
+					},
+				},
+			},
+		}
+		require.NoError(t, c.Validate())
+
+		statusCode := 503
+		c.Envoy.Timeouts.TimeoutResponse.StatusCode = &statusCode
+		c.Envoy.Timeouts.TimeoutResponse.ContentType = "text/html"
+		require.NoError(t, c.Validate())
+
+		statusCode = 200
+		require.Error(t, c.Validate())
+
+		c.Envoy.Timeouts.TimeoutResponse.StatusCode = nil
+		c.Envoy.Timeouts.TimeoutResponse.ContentType = "text/html\r\nX-Foo: bar"
+		require.Error(t, c.Validate())
+
+		c.Envoy.Timeouts.TimeoutResponse.ContentType = "text/html"
+		c.Envoy.Timeouts.TimeoutResponse.Body = ""
+		require.Error(t, c.Validate())
+	})
+
+	t.Run("locality weighted lb validation", func(t *testing.T) {
+		c := v1alpha1.ContourConfigurationSpec{
+			Envoy: &v1alpha1.EnvoyConfig{
+				Cluster: &v1alpha1.ClusterParameters{
+					DNSLookupFamily:    v1alpha1.AutoClusterDNSFamily,
+					LocalityWeightedLB: &v1alpha1.LocalityWeightedLBConfig{},
+				},
+			},
+		}
+		require.NoError(t, c.Validate())
+
+		c.Envoy.Cluster.LocalityWeightedLB.ZoneWeights = map[string]uint32{"zone-a": 2}
+		require.NoError(t, c.Validate())
+
+		c.Envoy.Cluster.LocalityWeightedLB.ZoneWeights["zone-b"] = 0
+		require.Error(t, c.Validate())
+	})
+
+	t.Run("topology aware routing validation", func(t *testing.T) {
+		c := v1alpha1.ContourConfigurationSpec{
+			Envoy: &v1alpha1.EnvoyConfig{
+				Cluster: &v1alpha1.ClusterParameters{
+					DNSLookupFamily:      v1alpha1.AutoClusterDNSFamily,
+					TopologyAwareRouting: &v1alpha1.TopologyAwareRoutingConfig{MinClusterSize: 1},
+				},
+			},
+		}
+		require.NoError(t, c.Validate())
+
+		c.Envoy.Cluster.LocalityWeightedLB = &v1alpha1.LocalityWeightedLBConfig{}
+		require.Error(t, c.Validate())
+	})
+
+	t.Run("endpoint weight label validation", func(t *testing.T) {
+		c := v1alpha1.ContourConfigurationSpec{
+			Envoy: &v1alpha1.EnvoyConfig{
+				Cluster: &v1alpha1.ClusterParameters{
+					DNSLookupFamily:     v1alpha1.AutoClusterDNSFamily,
+					EndpointWeightLabel: "projectcontour.io/weight",
+				},
+			},
+		}
+		require.NoError(t, c.Validate())
+
+		c.Envoy.Cluster.EndpointWeightLabel = "not a label"
+		require.Error(t, c.Validate())
+	})
+
+	t.Run("default load balancer policy validation", func(t *testing.T) {
+		c := v1alpha1.ContourConfigurationSpec{
+			Envoy: &v1alpha1.EnvoyConfig{
+				Cluster: &v1alpha1.ClusterParameters{
+					DNSLookupFamily:           v1alpha1.AutoClusterDNSFamily,
+					DefaultLoadBalancerPolicy: "WeightedLeastRequest",
+				},
+			},
+		}
+		require.NoError(t, c.Validate())
+
+		c.Envoy.Cluster.DefaultLoadBalancerPolicy = "Cookie"
+		require.Error(t, c.Validate())
 	})
 }
 
@@ -67,6 +67,12 @@ type ContourDeploymentSpec struct {
 	// +optional
 	RuntimeSettings *ContourConfigurationSpec `json:"runtimeSettings,omitempty"`
 
+	// XDSServer specifies settings for the xDS connection that Envoy
+	// uses to get its configuration from Contour.
+	//
+	// +optional
+	XDSServer *XDSServerSettings `json:"xdsServer,omitempty"`
+
 	// ResourceLabels is a set of labels to add to the provisioned Contour resources.
 	// +optional
 	ResourceLabels map[string]string `json:"resourceLabels,omitempty"`
@@ -91,6 +97,13 @@ type ContourSettings struct {
 	// +optional
 	NodePlacement *NodePlacement `json:"nodePlacement,omitempty"`
 
+	// PodLabels defines labels to add to the Contour pods. They are not
+	// added to the Deployment's selector, so can be changed later, and
+	// must not use the "app" key that the selector uses.
+	//
+	// +optional
+	PodLabels map[string]string `json:"podLabels,omitempty"`
+
 	// KubernetesLogLevel Enable Kubernetes client debug logging with log level. If unset,
 	// defaults to 0.
 	//
@@ -99,6 +112,48 @@ type ContourSettings struct {
 	// +optional
 	KubernetesLogLevel uint8 `json:"kubernetesLogLevel,omitempty"`
 
+	// KubernetesClientQPS is the number of queries per second that
+	// Contour's Kubernetes client may make to the API server. If unset,
+	// defaults to the client-go default of 5.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	KubernetesClientQPS int32 `json:"kubernetesClientQPS,omitempty"`
+
+	// KubernetesClientBurst is the number of queries that Contour's
+	// Kubernetes client may make to the API server in a burst above
+	// KubernetesClientQPS. If unset, defaults to the client-go default
+	// of 10. When set, must not be lower than KubernetesClientQPS.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	KubernetesClientBurst int32 `json:"kubernetesClientBurst,omitempty"`
+
+	// UseEndpointSlices is whether Contour discovers the endpoints of
+	// Services from EndpointSlices. Set it to false on clusters where
+	// EndpointSlices are not available, so that Contour watches
+	// Endpoints instead.
+	//
+	// If unset, defaults to true.
+	//
+	// +optional
+	UseEndpointSlices *bool `json:"useEndpointSlices,omitempty"`
+
+	// EnableExternalNameService is whether the routes attached to the
+	// Gateway can use ExternalName Services as backends. ExternalName
+	// Services can make Envoy send requests to arbitrary hosts, including
+	// ones that are only reachable from inside the cluster, so they are
+	// disabled unless explicitly enabled. The routes that use them while
+	// they are disabled have a ResolvedRefs condition of false.
+	// See https://github.com/projectcontour/contour/security/advisories/GHSA-5ph6-qq5x-7jwc
+	// for the details.
+	//
+	// If unset, the setting from RuntimeSettings is used, which
+	// defaults to false.
+	//
+	// +optional
+	EnableExternalNameService *bool `json:"enableExternalNameService,omitempty"`
+
 	// LogLevel sets the log level for Contour
 	// Allowed values are "info", "debug".
 	//
@@ -114,6 +169,31 @@ type ContourSettings struct {
 	// Deployment describes the settings for running contour as a `Deployment`.
 	// +optional
 	Deployment *DeploymentSettings `json:"deployment,omitempty"`
+
+	// AllowedHostnameSuffixes restricts the hostnames of the routes
+	// attached to the Gateway to those under one of the suffixes. For
+	// example, "example.com" allows "example.com", "foo.example.com"
+	// and "*.example.com". Routes that would match any other hostname
+	// are not accepted. If unset, routes may match any hostname.
+	//
+	// +optional
+	AllowedHostnameSuffixes []string `json:"allowedHostnameSuffixes,omitempty"`
+
+	// DisabledRouteKinds are the Gateway API route kinds the Gateway does
+	// not support. Routes of these kinds are not programmed and get an
+	// Accepted: false condition, and the Gateway's listeners do not allow
+	// them.
+	//
+	// Values: `HTTPRoute`, `TLSRoute`, `GRPCRoute`.
+	// +optional
+	DisabledRouteKinds []string `json:"disabledRouteKinds,omitempty"`
+
+	// ExtraInitContainers are run, in order, before the init containers
+	// Contour manages in the Contour pods. Their names must be unique
+	// and must not be "contour".
+	//
+	// +optional
+	ExtraInitContainers []corev1.Container `json:"extraInitContainers,omitempty"`
 }
 
 // DeploymentSettings contains settings for Deployment resources.
@@ -175,22 +255,87 @@ type EnvoySettings struct {
 	// +optional
 	ExtraVolumeMounts []corev1.VolumeMount `json:"extraVolumeMounts,omitempty"`
 
+	// ExtraInitContainers are run, in order, before the init containers
+	// Contour manages in the Envoy pods. Their names must be unique and
+	// must not be "envoy-initconfig", "envoy" or "shutdown-manager".
+	// They can share files with the Envoy container through extraVolumes.
+	//
+	// +optional
+	ExtraInitContainers []corev1.Container `json:"extraInitContainers,omitempty"`
+
 	// PodAnnotations defines annotations to add to the Envoy pods.
 	// +optional
 	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
 
+	// PodLabels defines labels to add to the Envoy pods. They are not
+	// added to the workload's selector, so can be changed later, and
+	// must not use the "app" key that the selector uses.
+	// +optional
+	PodLabels map[string]string `json:"podLabels,omitempty"`
+
 	// Compute Resources required by envoy container.
 	// Cannot be updated.
 	// More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
 	// +optional
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 
+	// PodSecurityContext holds the pod-level security attributes of the
+	// Envoy pods. Fields that are unset keep their defaults: Envoy runs as
+	// non-root user and group 65534, and fsGroup 65534 owns mounted volumes
+	// so that Envoy can read certificates from Secret volumes.
+	// More info: https://kubernetes.io/docs/tasks/configure-pod-container/security-context/
+	// +optional
+	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+
+	// ReadOnlyRootFilesystem runs the containers of the Envoy pods with
+	// a read-only root filesystem. The provisioner mounts an emptyDir
+	// volume at /tmp in the Envoy container, in addition to the volumes
+	// for Envoy's configuration and admin socket, which are always
+	// writable mounts.
+	//
+	// +optional
+	ReadOnlyRootFilesystem bool `json:"readOnlyRootFilesystem,omitempty"`
+
 	// LogLevel sets the log level for Envoy.
 	// Allowed values are "trace", "debug", "info", "warn", "error", "critical", "off".
 	//
 	// +optional
 	LogLevel LogLevel `json:"logLevel,omitempty"`
 
+	// ExtraArgs are additional command-line arguments appended to the
+	// Envoy container's args, for Envoy flags the ContourDeployment
+	// does not otherwise expose. A flag and its value can be given as a
+	// single argument, e.g. "--log-format %v". Flags managed by the
+	// provisioner, such as "--config-path", "--log-level" or "--base-id",
+	// are rejected.
+	//
+	// +optional
+	ExtraArgs []string `json:"extraArgs,omitempty"`
+
+	// DrainStrategy is how Envoy drains connections when its pod shuts down.
+	// With "gradual", the share of requests that close their connection
+	// grows from none to all over Envoy's drain time, spreading the load of
+	// reconnecting clients. With "immediate", every connection is closed as
+	// soon as draining starts.
+	//
+	// If unset, defaults to "immediate".
+	//
+	// +kubebuilder:validation:Enum=gradual;immediate
+	// +optional
+	DrainStrategy DrainStrategy `json:"drainStrategy,omitempty"`
+
+	// Shutdown configures how Envoy pods drain their connections when
+	// they are stopped, for example during a rolling update.
+	//
+	// +optional
+	Shutdown *EnvoyShutdownSettings `json:"shutdown,omitempty"`
+
+	// ShutdownManager configures the shutdown-manager sidecar container
+	// of Envoy pods.
+	//
+	// +optional
+	ShutdownManager *ShutdownManagerSettings `json:"shutdownManager,omitempty"`
+
 	// DaemonSet describes the settings for running envoy as a `DaemonSet`.
 	// if `WorkloadType` is `Deployment`,it's must be nil
 	// +optional
@@ -200,6 +345,122 @@ type EnvoySettings struct {
 	// if `WorkloadType` is `DaemonSet`,it's must be nil
 	// +optional
 	Deployment *DeploymentSettings `json:"deployment,omitempty"`
+
+	// MinReadySeconds is the minimum number of seconds a new Envoy pod
+	// must be ready, without any of its containers crashing, before it
+	// is considered available. Rollouts of the Envoy DaemonSet or
+	// Deployment wait for new pods to be available before continuing,
+	// giving Envoy time to receive its configuration from Contour
+	// before older pods are replaced. If unset, defaults to 0.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinReadySeconds int32 `json:"minReadySeconds,omitempty"`
+
+	// DefaultResponseHeaders defines the headers set, added or removed
+	// on every response from the Gateway. They are rendered into the
+	// global response headers policy of the generated ContourConfiguration,
+	// taking precedence over the same headers in RuntimeSettings, and
+	// apply to Gateway API routes as well as HTTPProxies.
+	// +optional
+	DefaultResponseHeaders *HeadersPolicy `json:"defaultResponseHeaders,omitempty"`
+
+	// DefaultLoadBalancerPolicy is the load balancer strategy of the
+	// Gateway's clusters whose route or service does not set one. It is
+	// rendered into the generated ContourConfiguration, taking precedence
+	// over the same setting in RuntimeSettings.
+	//
+	// Values: `RoundRobin` (default), `WeightedLeastRequest`, `Random`.
+	// +kubebuilder:validation:Enum=RoundRobin;WeightedLeastRequest;Random
+	// +optional
+	DefaultLoadBalancerPolicy string `json:"defaultLoadBalancerPolicy,omitempty"`
+
+	// Listener configures the Gateway's Envoy listeners.
+	// +optional
+	Listener *EnvoyListenerSettings `json:"listener,omitempty"`
+
+	// Metrics configures the stats of the Envoy pods, which are
+	// rendered into Envoy's bootstrap configuration.
+	//
+	// +optional
+	Metrics *EnvoyMetricsSettings `json:"metrics,omitempty"`
+
+	// DefaultBackend is the Service that requests to the Gateway's HTTP
+	// listeners are routed to when no route matches them, for example to
+	// serve a custom 404 page. Routes that match all paths of a hostname,
+	// including HTTP to HTTPS redirects, take precedence over it. It is
+	// not used for TLS listeners. If unset, Envoy responds with a 404.
+	//
+	// +optional
+	DefaultBackend *EnvoyDefaultBackend `json:"defaultBackend,omitempty"`
+}
+
+// EnvoyDefaultBackend is a reference to a port of a Service in the
+// namespace of the Gateway.
+type EnvoyDefaultBackend struct {
+	// Name is the name of the Service.
+	//
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Port is the port of the Service.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+}
+
+// EnvoyMetricsSettings configures the stats of Envoy.
+type EnvoyMetricsSettings struct {
+	// FlushInterval is how often Envoy flushes its stats to its sinks
+	// and updates its histograms, in the format accepted by Go's
+	// time.ParseDuration, e.g. "10s". It must be at least 1s and less
+	// than 5m. If unset, Envoy's default of 5s is used.
+	//
+	// +optional
+	FlushInterval string `json:"flushInterval,omitempty"`
+
+	// HistogramBuckets overrides Envoy's default bucket boundaries of the
+	// histograms whose names match. The first matching entry is used.
+	//
+	// +optional
+	HistogramBuckets []EnvoyHistogramBuckets `json:"histogramBuckets,omitempty"`
+}
+
+// EnvoyHistogramBuckets sets the bucket boundaries of the histograms
+// whose names match Regex.
+type EnvoyHistogramBuckets struct {
+	// Regex is a regular expression matched against the full Envoy stat
+	// name of histograms, e.g. `cluster\..*\.upstream_rq_time`.
+	//
+	// +kubebuilder:validation:MinLength=1
+	Regex string `json:"regex"`
+
+	// Buckets are the upper bounds of the buckets, in the unit of the
+	// histogram, as positive decimal numbers in ascending order, e.g.
+	// ["0.5", "1", "5"].
+	//
+	// +kubebuilder:validation:MinItems=1
+	Buckets []string `json:"buckets"`
+}
+
+// EnvoyListenerSettings configures the Envoy listeners of a Gateway.
+type EnvoyListenerSettings struct {
+	// KeepAlive configures the TCP and HTTP/2 keepalive of downstream
+	// connections. It is rendered into the generated ContourConfiguration,
+	// taking precedence over the same setting in RuntimeSettings.
+	// +optional
+	KeepAlive *EnvoyListenerKeepAlive `json:"keepAlive,omitempty"`
+
+	// HTTP2MaxConcurrentStreams caps the number of concurrent streams on
+	// each downstream HTTP/2 connection to the Gateway's listeners, to
+	// protect Envoy from clients opening too many streams. It is rendered
+	// into the generated ContourConfiguration, taking precedence over the
+	// same setting in RuntimeSettings.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=2147483647
+	// +optional
+	HTTP2MaxConcurrentStreams *uint32 `json:"http2MaxConcurrentStreams,omitempty"`
 }
 
 // WorkloadType is the type of Kubernetes workload to use for a component.
@@ -213,6 +474,83 @@ const (
 	WorkloadTypeDeployment = "Deployment"
 )
 
+// ShutdownManagerSettings configures the shutdown-manager sidecar of
+// Envoy pods.
+type ShutdownManagerSettings struct {
+	// Enabled is whether Envoy pods run the shutdown-manager sidecar.
+	// When false, the sidecar and the preStop hooks that gracefully drain
+	// Envoy are omitted, and Envoy's connections are closed as soon as its
+	// pod is stopped.
+	//
+	// If unset, defaults to true.
+	//
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// EnvoyShutdownSettings configures the shutdown of Envoy pods, which
+// is run by the preStop hook of their shutdown-manager container.
+// Durations are in the format accepted by Go's time.ParseDuration,
+// e.g. "90s" or "5m".
+type EnvoyShutdownSettings struct {
+	// DrainTimeout is the longest time an Envoy pod is given to drain its
+	// connections before it is stopped. It sets the pod's termination grace
+	// period and Envoy's drain time, over which the gradual drain strategy
+	// spreads closing connections.
+	//
+	// If unset, the termination grace period is 300s and Envoy's drain
+	// time is Envoy's default.
+	//
+	// +optional
+	DrainTimeout string `json:"drainTimeout,omitempty"`
+
+	// DrainDelay is how long to wait before Envoy starts draining
+	// connections, for example to let load balancers notice that
+	// the pod is going away.
+	//
+	// If unset, defaults to 0s.
+	//
+	// +optional
+	DrainDelay string `json:"drainDelay,omitempty"`
+
+	// CheckDelay is how long to wait after Envoy starts draining
+	// before polling its open connections.
+	//
+	// If unset, defaults to 0s.
+	//
+	// +optional
+	CheckDelay string `json:"checkDelay,omitempty"`
+
+	// CheckInterval is how often Envoy's open connections are polled.
+	//
+	// If unset, defaults to 5s.
+	//
+	// +optional
+	CheckInterval string `json:"checkInterval,omitempty"`
+
+	// MinOpenConnections is the number of open connections at or below
+	// which Envoy is stopped, without waiting for the drain timeout.
+	//
+	// If unset, defaults to 0.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinOpenConnections *int32 `json:"minOpenConnections,omitempty"`
+}
+
+// DrainStrategy is the strategy Envoy uses to drain connections.
+type DrainStrategy string
+
+const (
+	// DrainStrategyGradual closes an increasing share of connections
+	// over Envoy's drain time.
+	DrainStrategyGradual DrainStrategy = "gradual"
+
+	// DrainStrategyImmediate closes all connections as soon as
+	// draining starts.
+	DrainStrategyImmediate DrainStrategy = "immediate"
+)
+
 // NetworkPublishing defines the schema for publishing to a network.
 type NetworkPublishing struct {
 	// NetworkPublishingType is the type of publishing strategy to use. Valid values are:
@@ -263,11 +601,99 @@ type NetworkPublishing struct {
 	// +optional
 	ExternalTrafficPolicy corev1.ServiceExternalTrafficPolicyType `json:"externalTrafficPolicy,omitempty"`
 
+	// SessionAffinity is the session affinity of the Envoy service.
+	// With "ClientIP", the connections from a client are sent to the
+	// same Envoy pod. Supported for the LoadBalancerService,
+	// NodePortService and ClusterIPService types.
+	//
+	// If unset, defaults to "None".
+	//
+	// +kubebuilder:validation:Enum=ClientIP;None
+	// +optional
+	SessionAffinity corev1.ServiceAffinity `json:"sessionAffinity,omitempty"`
+
+	// SessionAffinityTimeoutSeconds is how long the connections from
+	// a client stick to the same Envoy pod after the last one. It is
+	// only valid with the "ClientIP" session affinity.
+	//
+	// If unset, defaults to 10800 (3 hours).
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=86400
+	// +optional
+	SessionAffinityTimeoutSeconds int32 `json:"sessionAffinityTimeoutSeconds,omitempty"`
+
 	// ServiceAnnotations is the annotations to add to
 	// the provisioned Envoy service.
 	//
 	// +optional
 	ServiceAnnotations map[string]string `json:"serviceAnnotations,omitempty"`
+
+	// ProxyProtocol configures the Envoy HTTP and HTTPS listeners to
+	// expect a PROXY protocol header on every connection, as sent by
+	// load balancers that prepend it to preserve the client address.
+	// Connections without the header are rejected. Envoy's admin,
+	// health and metrics ports are not affected.
+	//
+	// If unset, defaults to false.
+	//
+	// +optional
+	ProxyProtocol bool `json:"proxyProtocol,omitempty"`
+
+	// ExternalHostname is the hostname the Gateway advertises in its
+	// status.addresses, as a Hostname address, instead of the address
+	// assigned to the Envoy service. It is useful when the Gateway is
+	// reached through a DNS name managed outside of the cluster.
+	//
+	// It overrides the Ingress statusAddress of RuntimeSettings.
+	//
+	// +optional
+	ExternalHostname string `json:"externalHostname,omitempty"`
+
+	// Ports customizes the ports of the Envoy service. Each entry
+	// applies to the service port of the Envoy listener it names;
+	// listeners without an entry keep the default port settings.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=listener
+	Ports []EnvoyServicePort `json:"ports,omitempty"`
+}
+
+// EnvoyServicePort customizes the Envoy service port of a listener.
+type EnvoyServicePort struct {
+	// Listener is the Envoy listener the port forwards to. Valid
+	// values are "http" and "https".
+	//
+	// +kubebuilder:validation:Enum=http;https
+	Listener string `json:"listener"`
+
+	// Name is the name of the port on the Envoy service and
+	// containers. If unset, the listener name is used.
+	//
+	// +optional
+	// +kubebuilder:validation:MaxLength=15
+	Name string `json:"name,omitempty"`
+
+	// TargetPort is the container port Envoy's listener binds to,
+	// which the service port forwards traffic to.
+	//
+	// If unset, defaults to 8080 for the http listener and 8443
+	// for the https listener.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	TargetPort int32 `json:"targetPort,omitempty"`
+
+	// NodePort is the node port of the service port when the
+	// NetworkPublishingType is NodePortService. It overrides the
+	// node port taken from the Gateway listener's port.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	NodePort int32 `json:"nodePort,omitempty"`
 }
 
 // NetworkPublishingType is a way to publish network endpoints.
@@ -313,8 +739,66 @@ type NodePlacement struct {
 	//
 	// +optional
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// TopologySpreadConstraints describe how the pods are spread across
+	// topology domains such as zones or nodes. A constraint without a
+	// labelSelector selects the pods of the workload it is set on.
+	//
+	// The default is an empty list.
+	//
+	// See https://kubernetes.io/docs/concepts/scheduling-eviction/topology-spread-constraints/
+	// for additional details.
+	//
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+}
+
+// XDSServerSettings contains settings for the xDS connection between
+// Contour and Envoy.
+type XDSServerSettings struct {
+	// TLS configures the certificates that the provisioner generates
+	// for Contour and Envoy to authenticate each other on the xDS
+	// connection.
+	//
+	// +optional
+	TLS *XDSServerTLSSettings `json:"tls,omitempty"`
+}
+
+// XDSServerTLSSettings configures the generated xDS certificates.
+// Changing these settings regenerates the certificates.
+type XDSServerTLSSettings struct {
+	// KeyType is the type of the private keys to generate, RSA for
+	// 2048-bit RSA keys or ECDSA for P-256 ECDSA keys.
+	//
+	// If unset, defaults to RSA.
+	//
+	// +kubebuilder:validation:Enum=RSA;ECDSA
+	// +optional
+	KeyType XDSServerKeyType `json:"keyType,omitempty"`
+
+	// CertificateLifetime is the number of days the certificates are
+	// valid for. The provisioner regenerates them once less than a
+	// third of their lifetime remains.
+	//
+	// If unset, defaults to 365.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	CertificateLifetime int32 `json:"certificateLifetime,omitempty"`
 }
 
+// XDSServerKeyType is the type of the private keys of the xDS
+// certificates.
+type XDSServerKeyType string
+
+const (
+	// XDSServerKeyTypeRSA generates 2048-bit RSA keys.
+	XDSServerKeyTypeRSA XDSServerKeyType = "RSA"
+
+	// XDSServerKeyTypeECDSA generates P-256 ECDSA keys.
+	XDSServerKeyTypeECDSA XDSServerKeyType = "ECDSA"
+)
+
 // ContourDeploymentStatus defines the observed state of a ContourDeployment resource.
 type ContourDeploymentStatus struct {
 	// Conditions describe the current conditions of the ContourDeployment resource.
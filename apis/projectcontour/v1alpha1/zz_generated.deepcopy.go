@@ -51,6 +51,16 @@ func (in AccessLogJSONFields) DeepCopy() AccessLogJSONFields {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterParameters) DeepCopyInto(out *ClusterParameters) {
 	*out = *in
+	if in.LocalityWeightedLB != nil {
+		in, out := &in.LocalityWeightedLB, &out.LocalityWeightedLB
+		*out = new(LocalityWeightedLBConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TopologyAwareRouting != nil {
+		in, out := &in.TopologyAwareRouting, &out.TopologyAwareRouting
+		*out = new(TopologyAwareRoutingConfig)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterParameters.
@@ -165,6 +175,11 @@ func (in *ContourConfigurationSpec) DeepCopyInto(out *ContourConfigurationSpec)
 		*out = new(bool)
 		**out = **in
 	}
+	if in.UseEndpointSlices != nil {
+		in, out := &in.UseEndpointSlices, &out.UseEndpointSlices
+		*out = new(bool)
+		**out = **in
+	}
 	if in.GlobalExternalAuthorization != nil {
 		in, out := &in.GlobalExternalAuthorization, &out.GlobalExternalAuthorization
 		*out = new(v1.AuthorizationServer)
@@ -296,6 +311,11 @@ func (in *ContourDeploymentSpec) DeepCopyInto(out *ContourDeploymentSpec) {
 		*out = new(ContourConfigurationSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.XDSServer != nil {
+		in, out := &in.XDSServer, &out.XDSServer
+		*out = new(XDSServerSettings)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ResourceLabels != nil {
 		in, out := &in.ResourceLabels, &out.ResourceLabels
 		*out = make(map[string]string, len(*in))
@@ -345,12 +365,46 @@ func (in *ContourSettings) DeepCopyInto(out *ContourSettings) {
 		*out = new(NodePlacement)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PodLabels != nil {
+		in, out := &in.PodLabels, &out.PodLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.UseEndpointSlices != nil {
+		in, out := &in.UseEndpointSlices, &out.UseEndpointSlices
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnableExternalNameService != nil {
+		in, out := &in.EnableExternalNameService, &out.EnableExternalNameService
+		*out = new(bool)
+		**out = **in
+	}
 	in.Resources.DeepCopyInto(&out.Resources)
 	if in.Deployment != nil {
 		in, out := &in.Deployment, &out.Deployment
 		*out = new(DeploymentSettings)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AllowedHostnameSuffixes != nil {
+		in, out := &in.AllowedHostnameSuffixes, &out.AllowedHostnameSuffixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DisabledRouteKinds != nil {
+		in, out := &in.DisabledRouteKinds, &out.DisabledRouteKinds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExtraInitContainers != nil {
+		in, out := &in.ExtraInitContainers, &out.ExtraInitContainers
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContourSettings.
@@ -474,7 +528,7 @@ func (in *EnvoyConfig) DeepCopyInto(out *EnvoyConfig) {
 	if in.Cluster != nil {
 		in, out := &in.Cluster, &out.Cluster
 		*out = new(ClusterParameters)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Network != nil {
 		in, out := &in.Network, &out.Network
@@ -493,6 +547,41 @@ func (in *EnvoyConfig) DeepCopy() *EnvoyConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvoyDefaultBackend) DeepCopyInto(out *EnvoyDefaultBackend) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvoyDefaultBackend.
+func (in *EnvoyDefaultBackend) DeepCopy() *EnvoyDefaultBackend {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvoyDefaultBackend)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvoyHistogramBuckets) DeepCopyInto(out *EnvoyHistogramBuckets) {
+	*out = *in
+	if in.Buckets != nil {
+		in, out := &in.Buckets, &out.Buckets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvoyHistogramBuckets.
+func (in *EnvoyHistogramBuckets) DeepCopy() *EnvoyHistogramBuckets {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvoyHistogramBuckets)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EnvoyListener) DeepCopyInto(out *EnvoyListener) {
 	*out = *in
@@ -526,11 +615,26 @@ func (in *EnvoyListenerConfig) DeepCopyInto(out *EnvoyListenerConfig) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.SetRequestIDInResponse != nil {
+		in, out := &in.SetRequestIDInResponse, &out.SetRequestIDInResponse
+		*out = new(bool)
+		**out = **in
+	}
 	if in.TLS != nil {
 		in, out := &in.TLS, &out.TLS
 		*out = new(EnvoyTLS)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.KeepAlive != nil {
+		in, out := &in.KeepAlive, &out.KeepAlive
+		*out = new(EnvoyListenerKeepAlive)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTP2MaxConcurrentStreams != nil {
+		in, out := &in.HTTP2MaxConcurrentStreams, &out.HTTP2MaxConcurrentStreams
+		*out = new(uint32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvoyListenerConfig.
@@ -543,6 +647,56 @@ func (in *EnvoyListenerConfig) DeepCopy() *EnvoyListenerConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvoyListenerKeepAlive) DeepCopyInto(out *EnvoyListenerKeepAlive) {
+	*out = *in
+	if in.TCP != nil {
+		in, out := &in.TCP, &out.TCP
+		*out = new(TCPKeepAlive)
+		**out = **in
+	}
+	if in.HTTP2 != nil {
+		in, out := &in.HTTP2, &out.HTTP2
+		*out = new(HTTP2KeepAlive)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvoyListenerKeepAlive.
+func (in *EnvoyListenerKeepAlive) DeepCopy() *EnvoyListenerKeepAlive {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvoyListenerKeepAlive)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvoyListenerSettings) DeepCopyInto(out *EnvoyListenerSettings) {
+	*out = *in
+	if in.KeepAlive != nil {
+		in, out := &in.KeepAlive, &out.KeepAlive
+		*out = new(EnvoyListenerKeepAlive)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTP2MaxConcurrentStreams != nil {
+		in, out := &in.HTTP2MaxConcurrentStreams, &out.HTTP2MaxConcurrentStreams
+		*out = new(uint32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvoyListenerSettings.
+func (in *EnvoyListenerSettings) DeepCopy() *EnvoyListenerSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvoyListenerSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EnvoyLogging) DeepCopyInto(out *EnvoyLogging) {
 	*out = *in
@@ -563,6 +717,43 @@ func (in *EnvoyLogging) DeepCopy() *EnvoyLogging {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvoyMetricsSettings) DeepCopyInto(out *EnvoyMetricsSettings) {
+	*out = *in
+	if in.HistogramBuckets != nil {
+		in, out := &in.HistogramBuckets, &out.HistogramBuckets
+		*out = make([]EnvoyHistogramBuckets, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvoyMetricsSettings.
+func (in *EnvoyMetricsSettings) DeepCopy() *EnvoyMetricsSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvoyMetricsSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvoyServicePort) DeepCopyInto(out *EnvoyServicePort) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvoyServicePort.
+func (in *EnvoyServicePort) DeepCopy() *EnvoyServicePort {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvoyServicePort)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EnvoySettings) DeepCopyInto(out *EnvoySettings) {
 	*out = *in
@@ -590,6 +781,13 @@ func (in *EnvoySettings) DeepCopyInto(out *EnvoySettings) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ExtraInitContainers != nil {
+		in, out := &in.ExtraInitContainers, &out.ExtraInitContainers
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.PodAnnotations != nil {
 		in, out := &in.PodAnnotations, &out.PodAnnotations
 		*out = make(map[string]string, len(*in))
@@ -597,7 +795,34 @@ func (in *EnvoySettings) DeepCopyInto(out *EnvoySettings) {
 			(*out)[key] = val
 		}
 	}
+	if in.PodLabels != nil {
+		in, out := &in.PodLabels, &out.PodLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	in.Resources.DeepCopyInto(&out.Resources)
+	if in.PodSecurityContext != nil {
+		in, out := &in.PodSecurityContext, &out.PodSecurityContext
+		*out = new(corev1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExtraArgs != nil {
+		in, out := &in.ExtraArgs, &out.ExtraArgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Shutdown != nil {
+		in, out := &in.Shutdown, &out.Shutdown
+		*out = new(EnvoyShutdownSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ShutdownManager != nil {
+		in, out := &in.ShutdownManager, &out.ShutdownManager
+		*out = new(ShutdownManagerSettings)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.DaemonSet != nil {
 		in, out := &in.DaemonSet, &out.DaemonSet
 		*out = new(DaemonSetSettings)
@@ -608,6 +833,26 @@ func (in *EnvoySettings) DeepCopyInto(out *EnvoySettings) {
 		*out = new(DeploymentSettings)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DefaultResponseHeaders != nil {
+		in, out := &in.DefaultResponseHeaders, &out.DefaultResponseHeaders
+		*out = new(HeadersPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Listener != nil {
+		in, out := &in.Listener, &out.Listener
+		*out = new(EnvoyListenerSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(EnvoyMetricsSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DefaultBackend != nil {
+		in, out := &in.DefaultBackend, &out.DefaultBackend
+		*out = new(EnvoyDefaultBackend)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvoySettings.
@@ -620,6 +865,26 @@ func (in *EnvoySettings) DeepCopy() *EnvoySettings {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvoyShutdownSettings) DeepCopyInto(out *EnvoyShutdownSettings) {
+	*out = *in
+	if in.MinOpenConnections != nil {
+		in, out := &in.MinOpenConnections, &out.MinOpenConnections
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvoyShutdownSettings.
+func (in *EnvoyShutdownSettings) DeepCopy() *EnvoyShutdownSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvoyShutdownSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EnvoyTLS) DeepCopyInto(out *EnvoyTLS) {
 	*out = *in
@@ -784,6 +1049,21 @@ func (in *GatewayConfig) DeepCopyInto(out *GatewayConfig) {
 		*out = new(NamespacedName)
 		**out = **in
 	}
+	if in.AllowedHostnameSuffixes != nil {
+		in, out := &in.AllowedHostnameSuffixes, &out.AllowedHostnameSuffixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DisabledRouteKinds != nil {
+		in, out := &in.DisabledRouteKinds, &out.DisabledRouteKinds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DefaultBackend != nil {
+		in, out := &in.DefaultBackend, &out.DefaultBackend
+		*out = new(GatewayDefaultBackend)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayConfig.
@@ -796,6 +1076,36 @@ func (in *GatewayConfig) DeepCopy() *GatewayConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayDefaultBackend) DeepCopyInto(out *GatewayDefaultBackend) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayDefaultBackend.
+func (in *GatewayDefaultBackend) DeepCopy() *GatewayDefaultBackend {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayDefaultBackend)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTP2KeepAlive) DeepCopyInto(out *HTTP2KeepAlive) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTP2KeepAlive.
+func (in *HTTP2KeepAlive) DeepCopy() *HTTP2KeepAlive {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTP2KeepAlive)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPProxyConfig) DeepCopyInto(out *HTTPProxyConfig) {
 	*out = *in
@@ -836,6 +1146,13 @@ func (in *HeadersPolicy) DeepCopyInto(out *HeadersPolicy) {
 			(*out)[key] = val
 		}
 	}
+	if in.Add != nil {
+		in, out := &in.Add, &out.Add
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.Remove != nil {
 		in, out := &in.Remove, &out.Remove
 		*out = make([]string, len(*in))
@@ -888,6 +1205,28 @@ func (in *IngressConfig) DeepCopy() *IngressConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalityWeightedLBConfig) DeepCopyInto(out *LocalityWeightedLBConfig) {
+	*out = *in
+	if in.ZoneWeights != nil {
+		in, out := &in.ZoneWeights, &out.ZoneWeights
+		*out = make(map[string]uint32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalityWeightedLBConfig.
+func (in *LocalityWeightedLBConfig) DeepCopy() *LocalityWeightedLBConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalityWeightedLBConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MetricsConfig) DeepCopyInto(out *MetricsConfig) {
 	*out = *in
@@ -973,6 +1312,11 @@ func (in *NetworkPublishing) DeepCopyInto(out *NetworkPublishing) {
 			(*out)[key] = val
 		}
 	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]EnvoyServicePort, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPublishing.
@@ -1002,6 +1346,13 @@ func (in *NodePlacement) DeepCopyInto(out *NodePlacement) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]corev1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePlacement.
@@ -1032,6 +1383,21 @@ func (in *PolicyConfig) DeepCopyInto(out *PolicyConfig) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.ApplyToGatewayAPI != nil {
+		in, out := &in.ApplyToGatewayAPI, &out.ApplyToGatewayAPI
+		*out = new(bool)
+		**out = **in
+	}
+	if in.HSTSPolicy != nil {
+		in, out := &in.HSTSPolicy, &out.HSTSPolicy
+		*out = new(v1.HSTSPolicy)
+		**out = **in
+	}
+	if in.CSPPolicy != nil {
+		in, out := &in.CSPPolicy, &out.CSPPolicy
+		*out = new(v1.CSPPolicy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyConfig.
@@ -1075,6 +1441,41 @@ func (in *RateLimitServiceConfig) DeepCopy() *RateLimitServiceConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShutdownManagerSettings) DeepCopyInto(out *ShutdownManagerSettings) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShutdownManagerSettings.
+func (in *ShutdownManagerSettings) DeepCopy() *ShutdownManagerSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(ShutdownManagerSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TCPKeepAlive) DeepCopyInto(out *TCPKeepAlive) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPKeepAlive.
+func (in *TCPKeepAlive) DeepCopy() *TCPKeepAlive {
+	if in == nil {
+		return nil
+	}
+	out := new(TCPKeepAlive)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TLS) DeepCopyInto(out *TLS) {
 	*out = *in
@@ -1133,6 +1534,11 @@ func (in *TimeoutParameters) DeepCopyInto(out *TimeoutParameters) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.TimeoutResponse != nil {
+		in, out := &in.TimeoutResponse, &out.TimeoutResponse
+		*out = new(TimeoutResponse)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeoutParameters.
@@ -1145,6 +1551,41 @@ func (in *TimeoutParameters) DeepCopy() *TimeoutParameters {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeoutResponse) DeepCopyInto(out *TimeoutResponse) {
+	*out = *in
+	if in.StatusCode != nil {
+		in, out := &in.StatusCode, &out.StatusCode
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeoutResponse.
+func (in *TimeoutResponse) DeepCopy() *TimeoutResponse {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeoutResponse)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopologyAwareRoutingConfig) DeepCopyInto(out *TopologyAwareRoutingConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologyAwareRoutingConfig.
+func (in *TopologyAwareRoutingConfig) DeepCopy() *TopologyAwareRoutingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TopologyAwareRoutingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *XDSServerConfig) DeepCopyInto(out *XDSServerConfig) {
 	*out = *in
@@ -1164,3 +1605,38 @@ func (in *XDSServerConfig) DeepCopy() *XDSServerConfig {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *XDSServerSettings) DeepCopyInto(out *XDSServerSettings) {
+	*out = *in
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(XDSServerTLSSettings)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XDSServerSettings.
+func (in *XDSServerSettings) DeepCopy() *XDSServerSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(XDSServerSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *XDSServerTLSSettings) DeepCopyInto(out *XDSServerTLSSettings) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XDSServerTLSSettings.
+func (in *XDSServerTLSSettings) DeepCopy() *XDSServerTLSSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(XDSServerTLSSettings)
+	in.DeepCopyInto(out)
+	return out
+}
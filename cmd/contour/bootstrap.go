@@ -14,8 +14,17 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"strings"
+
 	"github.com/alecthomas/kingpin/v2"
 	"github.com/projectcontour/contour/internal/envoy"
+	"github.com/projectcontour/contour/internal/k8s"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
 // registerBootstrap registers the bootstrap subcommand and flags
@@ -33,11 +42,72 @@ func registerBootstrap(app *kingpin.Application) (*kingpin.CmdClause, *envoy.Boo
 	bootstrap.Flag("envoy-cert-file", "Client certificate filename for Envoy secure xDS gRPC communication.").Envar("ENVOY_CERT_FILE").StringVar(&config.GrpcClientCert)
 	bootstrap.Flag("envoy-key-file", "Client key filename for Envoy secure xDS gRPC communication.").Envar("ENVOY_KEY_FILE").StringVar(&config.GrpcClientKey)
 	bootstrap.Flag("namespace", "The namespace the Envoy container will run in.").Envar("CONTOUR_NAMESPACE").Default("projectcontour").StringVar(&config.Namespace)
+	bootstrap.Flag("node-name", "The node Envoy runs on, whose zone label sets Envoy's zone for topology aware routing.").StringVar(&config.NodeName)
 	bootstrap.Flag("overload-max-heap", "Defines the maximum heap size in bytes until overload manager stops accepting new connections.").Uint64Var(&config.MaximumHeapSizeBytes)
 	bootstrap.Flag("resources-dir", "Directory where configuration files will be written to.").StringVar(&config.ResourcesDir)
+	bootstrap.Flag("stats-flush-interval", "How often Envoy flushes its stats.").DurationVar(&config.StatsFlushInterval)
+	bootstrap.Flag("stats-histogram-buckets", "Buckets of the histograms whose names match, as <regex>=<bucket>,<bucket>,... (repeatable).").SetValue((*histogramBucketsValue)(&config.HistogramBuckets))
+	bootstrap.Flag("topology-aware-routing", "Add the local cluster Envoy needs to route requests zone aware.").BoolVar(&config.TopologyAwareRouting)
 	bootstrap.Flag("xds-address", "xDS gRPC API address.").StringVar(&config.XDSAddress)
 	bootstrap.Flag("xds-port", "xDS gRPC API port.").IntVar(&config.XDSGRPCPort)
 	bootstrap.Flag("xds-resource-version", "The versions of the xDS resources to request from Contour.").Default("v3").StringVar((*string)(&config.XDSResourceVersion))
 
 	return bootstrap, &config
 }
+
+// setZoneFromNode sets the zone of config to the zone label of the
+// node Envoy runs on, if the node is named.
+func setZoneFromNode(config *envoy.BootstrapConfig, log logrus.FieldLogger) error {
+	if config.NodeName == "" {
+		return nil
+	}
+
+	coreClient, err := k8s.NewCoreClient("", true)
+	if err != nil {
+		return err
+	}
+
+	if config.Zone, err = nodeZone(context.Background(), coreClient, config.NodeName); err != nil {
+		return err
+	}
+	if config.Zone == "" {
+		log.WithField("node", config.NodeName).Warnf("node has no %s label, Envoy routes requests regardless of zones", corev1.LabelTopologyZone)
+	}
+
+	return nil
+}
+
+// nodeZone returns the zone label of the named node, or an
+// empty string if the node has no zone label.
+func nodeZone(ctx context.Context, client kubernetes.Interface, name string) (string, error) {
+	node, err := client.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get node %s: %w", name, err)
+	}
+	return node.Labels[corev1.LabelTopologyZone], nil
+}
+
+// histogramBucketsValue is a repeatable kingpin flag
+// value that parses histogram buckets.
+type histogramBucketsValue []envoy.HistogramBuckets
+
+func (v *histogramBucketsValue) Set(s string) error {
+	hb, err := envoy.ParseHistogramBuckets(s)
+	if err != nil {
+		return err
+	}
+	*v = append(*v, hb)
+	return nil
+}
+
+func (v *histogramBucketsValue) String() string {
+	var s []string
+	for _, hb := range *v {
+		s = append(s, fmt.Sprintf("%s=%v", hb.Regex, hb.Buckets))
+	}
+	return strings.Join(s, " ")
+}
+
+func (v *histogramBucketsValue) IsCumulative() bool {
+	return true
+}
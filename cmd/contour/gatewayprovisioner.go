@@ -14,18 +14,27 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/projectcontour/contour/internal/provisioner"
+	"github.com/projectcontour/contour/internal/provisioner/bootstrap"
 	"github.com/projectcontour/contour/internal/provisioner/controller"
 	"github.com/projectcontour/contour/internal/provisioner/parse"
 	"github.com/projectcontour/contour/pkg/config"
 
 	"github.com/alecthomas/kingpin/v2"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
@@ -39,6 +48,8 @@ func registerGatewayProvisioner(app *kingpin.Application) (*kingpin.CmdClause, *
 		leaderElection:        false,
 		leaderElectionID:      "0d879e31.projectcontour.io",
 		gatewayControllerName: "projectcontour.io/gateway-controller",
+		webhookCertDir:        "/tmp/k8s-webhook-server/serving-certs",
+		webhookPort:           0,
 	}
 
 	cmd.Flag("contour-image", "The container image used for the managed Contour.").
@@ -56,6 +67,19 @@ func registerGatewayProvisioner(app *kingpin.Application) (*kingpin.CmdClause, *
 		Default(provisionerConfig.gatewayControllerName).
 		StringVar(&provisionerConfig.gatewayControllerName)
 
+	cmd.Flag("init", "Create or update the gateway provisioner's prerequisites, then exit instead of running the controllers.").
+		BoolVar(&provisionerConfig.init)
+
+	cmd.Flag("init-gatewayclass", "In --init mode, the name of a GatewayClass to create for --gateway-controller-name.").
+		StringVar(&provisionerConfig.initGatewayClass)
+
+	cmd.Flag("init-manifests", "In --init mode, a YAML file or directory of CRDs, RBAC and other manifests to apply.").
+		StringVar(&provisionerConfig.initManifests)
+
+	cmd.Flag("leader-election-id", "The name of the Lease that leader election uses to hold the leader lock.").
+		Default(provisionerConfig.leaderElectionID).
+		StringVar(&provisionerConfig.leaderElectionID)
+
 	cmd.Flag("leader-election-namespace", "The namespace in which the leader election resource will be created.").
 		Default(config.GetenvOr("CONTOUR_PROVISIONER_NAMESPACE", "projectcontour")).
 		StringVar(&provisionerConfig.leaderElectionNamespace)
@@ -64,6 +88,14 @@ func registerGatewayProvisioner(app *kingpin.Application) (*kingpin.CmdClause, *
 		Default(provisionerConfig.metricsBindAddress).
 		StringVar(&provisionerConfig.metricsBindAddress)
 
+	cmd.Flag("webhook-cert-dir", "The directory holding the tls.crt and tls.key of the ContourDeployment validating webhook.").
+		Default(provisionerConfig.webhookCertDir).
+		StringVar(&provisionerConfig.webhookCertDir)
+
+	cmd.Flag("webhook-port", "The port the ContourDeployment validating webhook is served on. It can be set to 0 to disable the webhook.").
+		Default(strconv.Itoa(provisionerConfig.webhookPort)).
+		IntVar(&provisionerConfig.webhookPort)
+
 	return cmd, provisionerConfig
 }
 
@@ -84,8 +116,9 @@ type gatewayProvisionerConfig struct {
 	// the gateway provisioner.
 	leaderElection bool
 
-	// leaderElectionID determines the name of the configmap that leader election will
-	// use for holding the leader lock.
+	// leaderElectionID determines the name of the Lease that leader election will
+	// use for holding the leader lock. Provisioners with the same leaderElectionID
+	// and leaderElectionNamespace compete for the same lock.
 	leaderElectionID string
 
 	// leaderElectionNamespace determines the namespace in which the leader
@@ -95,11 +128,60 @@ type gatewayProvisionerConfig struct {
 	// gatewayControllerName defines the controller string that this gateway provisioner instance
 	// will process GatewayClasses and Gateways for.
 	gatewayControllerName string
+
+	// init, if true, makes the gateway provisioner create its prerequisites
+	// and exit, rather than run the controllers.
+	init bool
+
+	// initManifests is the path of a YAML file, or a directory of YAML
+	// files, to apply in init mode.
+	initManifests string
+
+	// initGatewayClass is the name of a GatewayClass to create for
+	// gatewayControllerName in init mode.
+	initGatewayClass string
+
+	// webhookPort is the port that the ContourDeployment validating
+	// webhook is served on. The webhook is disabled when it is 0.
+	webhookPort int
+
+	// webhookCertDir is the directory holding the serving certificate
+	// and key of the webhook.
+	webhookCertDir string
 }
 
 func runGatewayProvisioner(config *gatewayProvisionerConfig) {
 	setupLog := ctrl.Log.WithName("setup")
 
+	// The controller name is matched against GatewayClasses, so a
+	// name they can't have would silently reconcile nothing.
+	if err := parse.GatewayControllerName(config.gatewayControllerName); err != nil {
+		setupLog.Error(err, "invalid gateway controller name", "value", config.gatewayControllerName)
+		os.Exit(1)
+	}
+
+	if config.init {
+		scheme, err := provisioner.CreateScheme()
+		if err != nil {
+			setupLog.Error(err, "error creating runtime scheme")
+			os.Exit(1)
+		}
+
+		cli, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+		if err != nil {
+			setupLog.Error(err, "failed to create client")
+			os.Exit(1)
+		}
+
+		setupLog.Info("initializing contour gateway provisioner prerequisites")
+		if err := initGatewayProvisioner(ctrl.SetupSignalHandler(), cli, config); err != nil {
+			setupLog.Error(err, "failed to initialize contour gateway provisioner prerequisites")
+			os.Exit(1)
+		}
+		setupLog.Info("contour gateway provisioner prerequisites initialized")
+		return
+	}
+
 	for _, image := range []string{config.contourImage, config.envoyImage} {
 		// Parse will not handle short digests.
 		if err := parse.Image(image); err != nil {
@@ -108,6 +190,7 @@ func runGatewayProvisioner(config *gatewayProvisionerConfig) {
 		}
 	}
 
+	setupLog.Info("using gateway controller name", "value", config.gatewayControllerName)
 	setupLog.Info("using contour", "image", config.contourImage)
 	setupLog.Info("using envoy", "image", config.envoyImage)
 
@@ -124,22 +207,68 @@ func runGatewayProvisioner(config *gatewayProvisionerConfig) {
 	}
 }
 
-// createManager creates a new manager from restConfig and provisionerConfig.
-func createManager(restConfig *rest.Config, provisionerConfig *gatewayProvisionerConfig) (manager.Manager, error) {
-	scheme, err := provisioner.CreateScheme()
-	if err != nil {
-		return nil, fmt.Errorf("error creating runtime scheme: %w", err)
+// initGatewayProvisioner applies the configured manifests and then
+// creates the configured GatewayClass, if they are set.
+func initGatewayProvisioner(ctx context.Context, cli client.Client, config *gatewayProvisionerConfig) error {
+	if config.initManifests == "" && config.initGatewayClass == "" {
+		return fmt.Errorf("--init requires --init-manifests or --init-gatewayclass")
+	}
+
+	if config.initManifests != "" {
+		objs, err := bootstrap.LoadManifests(config.initManifests)
+		if err != nil {
+			return fmt.Errorf("failed to load manifests: %w", err)
+		}
+		if err := bootstrap.EnsureObjects(ctx, cli, objs); err != nil {
+			return fmt.Errorf("failed to apply manifests: %w", err)
+		}
 	}
 
-	mgr, err := ctrl.NewManager(restConfig, manager.Options{
+	if config.initGatewayClass != "" {
+		// The Gateway API CRDs may have only just been applied, so
+		// wait for the API server to start serving them.
+		var ensureErr error
+		err := wait.PollImmediateWithContext(ctx, time.Second, time.Minute, func(ctx context.Context) (bool, error) {
+			ensureErr = bootstrap.EnsureGatewayClass(ctx, cli, config.initGatewayClass, config.gatewayControllerName)
+			if ensureErr != nil && meta.IsNoMatchError(ensureErr) {
+				return false, nil
+			}
+			return true, ensureErr
+		})
+		if err != nil {
+			if ensureErr != nil {
+				err = ensureErr
+			}
+			return fmt.Errorf("failed to ensure gatewayclass %q: %w", config.initGatewayClass, err)
+		}
+	}
+
+	return nil
+}
+
+// managerOptions returns the options of the gateway provisioner's manager.
+func managerOptions(scheme *runtime.Scheme, provisionerConfig *gatewayProvisionerConfig) manager.Options {
+	return manager.Options{
 		Scheme:                     scheme,
 		LeaderElection:             provisionerConfig.leaderElection,
-		LeaderElectionResourceLock: "leases",
+		LeaderElectionResourceLock: resourcelock.LeasesResourceLock,
 		LeaderElectionID:           provisionerConfig.leaderElectionID,
 		LeaderElectionNamespace:    provisionerConfig.leaderElectionNamespace,
 		MetricsBindAddress:         provisionerConfig.metricsBindAddress,
+		Port:                       provisionerConfig.webhookPort,
+		CertDir:                    provisionerConfig.webhookCertDir,
 		Logger:                     ctrl.Log.WithName("contour-gateway-provisioner"),
-	})
+	}
+}
+
+// createManager creates a new manager from restConfig and provisionerConfig.
+func createManager(restConfig *rest.Config, provisionerConfig *gatewayProvisionerConfig) (manager.Manager, error) {
+	scheme, err := provisioner.CreateScheme()
+	if err != nil {
+		return nil, fmt.Errorf("error creating runtime scheme: %w", err)
+	}
+
+	mgr, err := ctrl.NewManager(restConfig, managerOptions(scheme, provisionerConfig))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create manager: %w", err)
 	}
@@ -151,5 +280,12 @@ func createManager(restConfig *rest.Config, provisionerConfig *gatewayProvisione
 	if _, err := controller.NewGatewayController(mgr, provisionerConfig.gatewayControllerName, provisionerConfig.contourImage, provisionerConfig.envoyImage); err != nil {
 		return nil, fmt.Errorf("failed to create gateway controller: %w", err)
 	}
+
+	// The webhook server is only started when a webhook is registered.
+	if provisionerConfig.webhookPort != 0 {
+		if err := controller.NewContourDeploymentWebhook(mgr); err != nil {
+			return nil, fmt.Errorf("failed to create contourdeployment webhook: %w", err)
+		}
+	}
 	return mgr, nil
 }
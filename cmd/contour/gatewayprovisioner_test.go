@@ -0,0 +1,189 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/projectcontour/contour/internal/provisioner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+const initManifests = `---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: projectcontour
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: contour-gateway-provisioner
+  namespace: projectcontour
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: contour-gateway-provisioner
+rules:
+- apiGroups:
+  - gateway.networking.k8s.io
+  resources:
+  - gatewayclasses
+  verbs:
+  - get
+`
+
+func TestInitGatewayProvisioner(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "provisioner.yaml"), []byte(initManifests), 0o600))
+
+	scheme, err := provisioner.CreateScheme()
+	require.NoError(t, err)
+	cli := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	config := &gatewayProvisionerConfig{
+		gatewayControllerName: "projectcontour.io/gateway-controller",
+		init:                  true,
+		initManifests:         dir,
+		initGatewayClass:      "contour",
+	}
+
+	// Running init a second time, e.g. when the Job is retried,
+	// updates the existing resources rather than failing.
+	for i := 0; i < 2; i++ {
+		require.NoError(t, initGatewayProvisioner(context.Background(), cli, config))
+
+		require.NoError(t, cli.Get(context.Background(), client.ObjectKey{Name: "projectcontour"}, &corev1.Namespace{}))
+		require.NoError(t, cli.Get(context.Background(), client.ObjectKey{Namespace: "projectcontour", Name: "contour-gateway-provisioner"}, &corev1.ServiceAccount{}))
+
+		clusterRole := &rbacv1.ClusterRole{}
+		require.NoError(t, cli.Get(context.Background(), client.ObjectKey{Name: "contour-gateway-provisioner"}, clusterRole))
+		require.Len(t, clusterRole.Rules, 1)
+		assert.Equal(t, []string{"gatewayclasses"}, clusterRole.Rules[0].Resources)
+
+		gatewayClass := &gatewayv1beta1.GatewayClass{}
+		require.NoError(t, cli.Get(context.Background(), client.ObjectKey{Name: "contour"}, gatewayClass))
+		assert.Equal(t, gatewayv1beta1.GatewayController("projectcontour.io/gateway-controller"), gatewayClass.Spec.ControllerName)
+	}
+
+	// A GatewayClass of the same name for another controller is an error.
+	config.gatewayControllerName = "example.com/other-controller"
+	assert.Error(t, initGatewayProvisioner(context.Background(), cli, config))
+
+	// Init mode needs something to do.
+	assert.Error(t, initGatewayProvisioner(context.Background(), cli, &gatewayProvisionerConfig{init: true}))
+}
+
+func TestGatewayProvisionerControllerName(t *testing.T) {
+	app := kingpin.New("contour", "")
+	_, config := registerGatewayProvisioner(app)
+
+	_, err := app.Parse([]string{"gateway-provisioner", "--gateway-controller-name=example.com/contour-fork", "--init", "--init-gatewayclass=contour-fork"})
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/contour-fork", config.gatewayControllerName)
+
+	// The GatewayClass created in init mode is for the custom controller.
+	scheme, err := provisioner.CreateScheme()
+	require.NoError(t, err)
+	cli := fake.NewClientBuilder().WithScheme(scheme).Build()
+	require.NoError(t, initGatewayProvisioner(context.Background(), cli, config))
+
+	gatewayClass := &gatewayv1beta1.GatewayClass{}
+	require.NoError(t, cli.Get(context.Background(), client.ObjectKey{Name: "contour-fork"}, gatewayClass))
+	assert.Equal(t, gatewayv1beta1.GatewayController("example.com/contour-fork"), gatewayClass.Spec.ControllerName)
+}
+
+func TestGatewayProvisionerLeaderElectionLock(t *testing.T) {
+	parse := func(args ...string) *gatewayProvisionerConfig {
+		app := kingpin.New("contour", "")
+		_, config := registerGatewayProvisioner(app)
+		_, err := app.Parse(append([]string{"gateway-provisioner", "--enable-leader-election"}, args...))
+		require.NoError(t, err)
+		return config
+	}
+
+	opts := managerOptions(nil, parse())
+	assert.Equal(t, "0d879e31.projectcontour.io", opts.LeaderElectionID)
+	assert.Equal(t, "projectcontour", opts.LeaderElectionNamespace)
+
+	first := managerOptions(nil, parse("--leader-election-namespace=contour-system", "--leader-election-id=first.projectcontour.io"))
+	second := managerOptions(nil, parse("--leader-election-namespace=contour-system", "--leader-election-id=second.projectcontour.io"))
+	assert.Equal(t, "first.projectcontour.io", first.LeaderElectionID)
+	assert.Equal(t, "contour-system", first.LeaderElectionNamespace)
+
+	// Provisioners with distinct lock names both become leaders, while
+	// one sharing the lock of another has to wait for it.
+	clientset := k8sfake.NewSimpleClientset()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	elect := func(identity string, opts manager.Options) <-chan struct{} {
+		lock, err := resourcelock.New(opts.LeaderElectionResourceLock, opts.LeaderElectionNamespace, opts.LeaderElectionID,
+			clientset.CoreV1(), clientset.CoordinationV1(), resourcelock.ResourceLockConfig{Identity: identity})
+		require.NoError(t, err)
+
+		leading := make(chan struct{})
+		elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+			Lock:          lock,
+			LeaseDuration: 15 * time.Second,
+			RenewDeadline: 10 * time.Second,
+			RetryPeriod:   100 * time.Millisecond,
+			Callbacks: leaderelection.LeaderCallbacks{
+				OnStartedLeading: func(context.Context) { close(leading) },
+				OnStoppedLeading: func() {},
+			},
+		})
+		require.NoError(t, err)
+		go elector.Run(ctx)
+		return leading
+	}
+
+	leaders := []<-chan struct{}{elect("first", first), elect("second", second)}
+	for _, leading := range leaders {
+		select {
+		case <-leading:
+		case <-time.After(10 * time.Second):
+			require.FailNow(t, "expected every provisioner with its own lock to become leader")
+		}
+	}
+
+	contender := elect("contender", first)
+	select {
+	case <-contender:
+		assert.Fail(t, "expected the provisioner sharing a held lock not to become leader")
+	case <-time.After(time.Second):
+	}
+
+	for _, name := range []string{"first.projectcontour.io", "second.projectcontour.io"} {
+		_, err := clientset.CoordinationV1().Leases("contour-system").Get(context.Background(), name, metav1.GetOptions{})
+		assert.NoError(t, err)
+	}
+}
@@ -50,6 +50,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
+	discovery_v1 "k8s.io/api/discovery/v1"
 	networking_v1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -349,6 +350,7 @@ func (s *Server) doServe() error {
 		DefaultHTTPVersions:          parseDefaultHTTPVersions(contourConfiguration.Envoy.DefaultHTTPVersions),
 		AllowChunkedLength:           !*contourConfiguration.Envoy.Listener.DisableAllowChunkedLength,
 		MergeSlashes:                 !*contourConfiguration.Envoy.Listener.DisableMergeSlashes,
+		SetRequestIDInResponse:       *contourConfiguration.Envoy.Listener.SetRequestIDInResponse,
 		ServerHeaderTransformation:   contourConfiguration.Envoy.Listener.ServerHeaderTransformation,
 		XffNumTrustedHops:            *contourConfiguration.Envoy.Network.XffNumTrustedHops,
 		ConnectionBalancer:           contourConfiguration.Envoy.Listener.ConnectionBalancer,
@@ -362,17 +364,49 @@ func (s *Server) doServe() error {
 		return err
 	}
 
+	if err := setListenerKeepAlive(&listenerConfig, contourConfiguration.Envoy.Listener.KeepAlive); err != nil {
+		return err
+	}
+
+	if streams := contourConfiguration.Envoy.Listener.HTTP2MaxConcurrentStreams; streams != nil {
+		listenerConfig.HTTP2MaxConcurrentStreams = *streams
+	}
+
 	contourMetrics := metrics.NewMetrics(s.registry)
 
 	// Endpoints updates are handled directly by the EndpointsTranslator
 	// due to their high update rate and their orthogonal nature.
 	endpointHandler := xdscache_v3.NewEndpointsTranslator(s.log.WithField("context", "endpointstranslator"))
 
+	localityWeightedLB := contourConfiguration.Envoy.Cluster.LocalityWeightedLB
+	if localityWeightedLB != nil {
+		endpointHandler.EnableLocalityWeighting(localityWeightedLB.ZoneWeights)
+	}
+
+	var topologyAwareMinClusterSize uint32
+	topologyAwareRouting := contourConfiguration.Envoy.Cluster.TopologyAwareRouting
+	if topologyAwareRouting != nil {
+		endpointHandler.EnableTopologyAwareRouting(types.NamespacedName{
+			Namespace: contourConfiguration.Envoy.Service.Namespace,
+			Name:      contourConfiguration.Envoy.Service.Name,
+		})
+		topologyAwareMinClusterSize = topologyAwareRouting.MinClusterSize
+	}
+
+	endpointWeightLabel := contourConfiguration.Envoy.Cluster.EndpointWeightLabel
+	if endpointWeightLabel != "" {
+		endpointHandler.EnableEndpointWeights(endpointWeightLabel)
+	}
+
 	resources := []xdscache.ResourceCache{
 		xdscache_v3.NewListenerCache(listenerConfig, *contourConfiguration.Envoy.Metrics, *contourConfiguration.Envoy.Health, *contourConfiguration.Envoy.Network.EnvoyAdminPort),
 		xdscache_v3.NewSecretsCache(envoy_v3.StatsSecrets(contourConfiguration.Envoy.Metrics.TLS)),
 		&xdscache_v3.RouteCache{},
-		&xdscache_v3.ClusterCache{},
+		&xdscache_v3.ClusterCache{
+			LocalityWeightedLB:          localityWeightedLB != nil,
+			TopologyAwareRouting:        topologyAwareRouting != nil,
+			TopologyAwareMinClusterSize: topologyAwareMinClusterSize,
+		},
 		endpointHandler,
 		&xdscache_v3.RuntimeCache{},
 	}
@@ -412,9 +446,14 @@ func (s *Server) doServe() error {
 
 	var gatewayControllerName string
 	var gatewayRef *types.NamespacedName
+	var gatewayAllowedHostnameSuffixes []string
+	var gatewayDisabledRouteKinds []string
+	var gatewayDefaultBackend *dag.GatewayDefaultBackend
 
 	if contourConfiguration.Gateway != nil {
 		gatewayControllerName = contourConfiguration.Gateway.ControllerName
+		gatewayAllowedHostnameSuffixes = contourConfiguration.Gateway.AllowedHostnameSuffixes
+		gatewayDisabledRouteKinds = contourConfiguration.Gateway.DisabledRouteKinds
 
 		if contourConfiguration.Gateway.GatewayRef != nil {
 			gatewayRef = &types.NamespacedName{
@@ -422,6 +461,13 @@ func (s *Server) doServe() error {
 				Name:      contourConfiguration.Gateway.GatewayRef.Name,
 			}
 		}
+
+		if b := contourConfiguration.Gateway.DefaultBackend; b != nil {
+			gatewayDefaultBackend = &dag.GatewayDefaultBackend{
+				Service: types.NamespacedName{Namespace: b.Namespace, Name: b.Name},
+				Port:    int(b.Port),
+			}
+		}
 	}
 
 	builder := s.getDAGBuilder(dagBuilderConfig{
@@ -429,9 +475,13 @@ func (s *Server) doServe() error {
 		rootNamespaces:                     contourConfiguration.HTTPProxy.RootNamespaces,
 		gatewayControllerName:              gatewayControllerName,
 		gatewayRef:                         gatewayRef,
+		gatewayAllowedHostnameSuffixes:     gatewayAllowedHostnameSuffixes,
+		gatewayDisabledRouteKinds:          gatewayDisabledRouteKinds,
+		gatewayDefaultBackend:              gatewayDefaultBackend,
 		disablePermitInsecure:              *contourConfiguration.HTTPProxy.DisablePermitInsecure,
 		enableExternalNameService:          *contourConfiguration.EnableExternalNameService,
 		dnsLookupFamily:                    contourConfiguration.Envoy.Cluster.DNSLookupFamily,
+		defaultLoadBalancerPolicy:          contourConfiguration.Envoy.Cluster.DefaultLoadBalancerPolicy,
 		headersPolicy:                      contourConfiguration.Policy,
 		clientCert:                         clientCert,
 		fallbackCert:                       fallbackCert,
@@ -501,12 +551,43 @@ func (s *Server) doServe() error {
 		s.log.WithError(err).WithField("resource", "secrets").Fatal("failed to create informer")
 	}
 
-	// Inform on endpoints.
-	if err := informOnResource(&corev1.Endpoints{}, &contour.EventRecorder{
+	// Inform on endpoints, or on the EndpointSlices that replace them
+	// if enabled.
+	var (
+		endpointsObj      client.Object = &corev1.Endpoints{}
+		endpointsResource               = "endpoints"
+	)
+	if *contourConfiguration.UseEndpointSlices {
+		endpointsObj = &discovery_v1.EndpointSlice{}
+		endpointsResource = "endpointslices"
+	}
+	if err := informOnResource(endpointsObj, &contour.EventRecorder{
 		Next:    endpointHandler,
 		Counter: contourMetrics.EventHandlerOperations,
 	}, s.mgr.GetCache()); err != nil {
-		s.log.WithError(err).WithField("resource", "endpoints").Fatal("failed to create informer")
+		s.log.WithError(err).WithField("resource", endpointsResource).Fatal("failed to create informer")
+	}
+
+	// Inform on nodes to find the zones of endpoints when locality
+	// weighted load balancing or topology aware routing is enabled.
+	if localityWeightedLB != nil || topologyAwareRouting != nil {
+		if err := informOnResource(&corev1.Node{}, &contour.EventRecorder{
+			Next:    endpointHandler,
+			Counter: contourMetrics.EventHandlerOperations,
+		}, s.mgr.GetCache()); err != nil {
+			s.log.WithError(err).WithField("resource", "nodes").Fatal("failed to create informer")
+		}
+	}
+
+	// Inform on pods to find the weights of endpoints when an
+	// endpoint weight label is configured.
+	if endpointWeightLabel != "" {
+		if err := informOnResource(&corev1.Pod{}, &contour.EventRecorder{
+			Next:    endpointHandler,
+			Counter: contourMetrics.EventHandlerOperations,
+		}, s.mgr.GetCache()); err != nil {
+			s.log.WithError(err).WithField("resource", "pods").Fatal("failed to create informer")
+		}
 	}
 
 	// Register our event handler with the manager.
@@ -906,9 +987,13 @@ type dagBuilderConfig struct {
 	rootNamespaces                     []string
 	gatewayControllerName              string
 	gatewayRef                         *types.NamespacedName
+	gatewayAllowedHostnameSuffixes     []string
+	gatewayDisabledRouteKinds          []string
+	gatewayDefaultBackend              *dag.GatewayDefaultBackend
 	disablePermitInsecure              bool
 	enableExternalNameService          bool
 	dnsLookupFamily                    contour_api_v1alpha1.ClusterDNSFamilyType
+	defaultLoadBalancerPolicy          string
 	headersPolicy                      *contour_api_v1alpha1.PolicyConfig
 	clientCert                         *types.NamespacedName
 	fallbackCert                       *types.NamespacedName
@@ -925,9 +1010,13 @@ type dagBuilderConfig struct {
 func (s *Server) getDAGBuilder(dbc dagBuilderConfig) *dag.Builder {
 
 	var (
-		requestHeadersPolicy       dag.HeadersPolicy
-		responseHeadersPolicy      dag.HeadersPolicy
-		applyHeaderPolicyToIngress bool
+		requestHeadersPolicy          dag.HeadersPolicy
+		responseHeadersPolicy         dag.HeadersPolicy
+		applyHeaderPolicyToIngress    bool
+		applyHeaderPolicyToGatewayAPI bool
+		hstsPolicy                    *contour_api_v1.HSTSPolicy
+		cspPolicy                     *contour_api_v1.CSPPolicy
+		upstreamClusterHeader         string
 	)
 
 	if dbc.headersPolicy != nil {
@@ -938,6 +1027,12 @@ func (s *Server) getDAGBuilder(dbc dagBuilderConfig) *dag.Builder {
 					requestHeadersPolicy.Set[k] = v
 				}
 			}
+			if dbc.headersPolicy.RequestHeadersPolicy.Add != nil {
+				requestHeadersPolicy.Add = make(map[string]string)
+				for k, v := range dbc.headersPolicy.RequestHeadersPolicy.Add {
+					requestHeadersPolicy.Add[k] = v
+				}
+			}
 			if dbc.headersPolicy.RequestHeadersPolicy.Remove != nil {
 				requestHeadersPolicy.Remove = make([]string, 0, len(dbc.headersPolicy.RequestHeadersPolicy.Remove))
 				requestHeadersPolicy.Remove = append(requestHeadersPolicy.Remove, dbc.headersPolicy.RequestHeadersPolicy.Remove...)
@@ -951,6 +1046,12 @@ func (s *Server) getDAGBuilder(dbc dagBuilderConfig) *dag.Builder {
 					responseHeadersPolicy.Set[k] = v
 				}
 			}
+			if dbc.headersPolicy.ResponseHeadersPolicy.Add != nil {
+				responseHeadersPolicy.Add = make(map[string]string)
+				for k, v := range dbc.headersPolicy.ResponseHeadersPolicy.Add {
+					responseHeadersPolicy.Add[k] = v
+				}
+			}
 			if dbc.headersPolicy.ResponseHeadersPolicy.Remove != nil {
 				responseHeadersPolicy.Remove = make([]string, 0, len(dbc.headersPolicy.ResponseHeadersPolicy.Remove))
 				responseHeadersPolicy.Remove = append(responseHeadersPolicy.Remove, dbc.headersPolicy.ResponseHeadersPolicy.Remove...)
@@ -958,6 +1059,10 @@ func (s *Server) getDAGBuilder(dbc dagBuilderConfig) *dag.Builder {
 		}
 
 		applyHeaderPolicyToIngress = *dbc.headersPolicy.ApplyToIngress
+		applyHeaderPolicyToGatewayAPI = ref.Val(dbc.headersPolicy.ApplyToGatewayAPI, false)
+		hstsPolicy = dbc.headersPolicy.HSTSPolicy
+		cspPolicy = dbc.headersPolicy.CSPPolicy
+		upstreamClusterHeader = dbc.headersPolicy.UpstreamClusterHeader
 	}
 
 	var requestHeadersPolicyIngress dag.HeadersPolicy
@@ -968,6 +1073,14 @@ func (s *Server) getDAGBuilder(dbc dagBuilderConfig) *dag.Builder {
 		responseHeadersPolicyIngress = responseHeadersPolicy
 	}
 
+	var requestHeadersPolicyGatewayAPI *dag.HeadersPolicy
+	var responseHeadersPolicyGatewayAPI *dag.HeadersPolicy
+
+	if applyHeaderPolicyToGatewayAPI {
+		requestHeadersPolicyGatewayAPI = &requestHeadersPolicy
+		responseHeadersPolicyGatewayAPI = &responseHeadersPolicy
+	}
+
 	s.log.Debugf("EnableExternalNameService is set to %t", dbc.enableExternalNameService)
 
 	// Get the appropriate DAG processors.
@@ -987,13 +1100,15 @@ func (s *Server) getDAGBuilder(dbc dagBuilderConfig) *dag.Builder {
 			RequestHeadersPolicy:      &requestHeadersPolicyIngress,
 			ResponseHeadersPolicy:     &responseHeadersPolicyIngress,
 			ConnectTimeout:            dbc.connectTimeout,
+			DefaultLoadBalancerPolicy: dbc.defaultLoadBalancerPolicy,
 		},
 		&dag.ExtensionServiceProcessor{
 			// Note that ExtensionService does not support ExternalName, if it does get added,
 			// need to bring EnableExternalNameService in here too.
-			FieldLogger:       s.log.WithField("context", "ExtensionServiceProcessor"),
-			ClientCertificate: dbc.clientCert,
-			ConnectTimeout:    dbc.connectTimeout,
+			FieldLogger:               s.log.WithField("context", "ExtensionServiceProcessor"),
+			ClientCertificate:         dbc.clientCert,
+			ConnectTimeout:            dbc.connectTimeout,
+			DefaultLoadBalancerPolicy: dbc.defaultLoadBalancerPolicy,
 		},
 		&dag.HTTPProxyProcessor{
 			EnableExternalNameService:   dbc.enableExternalNameService,
@@ -1005,6 +1120,10 @@ func (s *Server) getDAGBuilder(dbc dagBuilderConfig) *dag.Builder {
 			ResponseHeadersPolicy:       &responseHeadersPolicy,
 			ConnectTimeout:              dbc.connectTimeout,
 			GlobalExternalAuthorization: dbc.globalExternalAuthorizationService,
+			HSTSPolicy:                  hstsPolicy,
+			CSPPolicy:                   cspPolicy,
+			UpstreamClusterHeader:       upstreamClusterHeader,
+			DefaultLoadBalancerPolicy:   dbc.defaultLoadBalancerPolicy,
 		},
 	}
 
@@ -1013,6 +1132,12 @@ func (s *Server) getDAGBuilder(dbc dagBuilderConfig) *dag.Builder {
 			EnableExternalNameService: dbc.enableExternalNameService,
 			FieldLogger:               s.log.WithField("context", "GatewayAPIProcessor"),
 			ConnectTimeout:            dbc.connectTimeout,
+			RequestHeadersPolicy:      requestHeadersPolicyGatewayAPI,
+			ResponseHeadersPolicy:     responseHeadersPolicyGatewayAPI,
+			DefaultLoadBalancerPolicy: dbc.defaultLoadBalancerPolicy,
+			AllowedHostnameSuffixes:   dbc.gatewayAllowedHostnameSuffixes,
+			DisabledRouteKinds:        dbc.gatewayDisabledRouteKinds,
+			DefaultBackend:            dbc.gatewayDefaultBackend,
 		})
 	}
 
@@ -94,6 +94,9 @@ func TestGetDAGBuilder(t *testing.T) {
 					"res-set-key-1": "res-set-val-1",
 					"res-set-key-2": "res-set-val-2",
 				},
+				Add: map[string]string{
+					"res-add-key-1": "res-add-val-1",
+				},
 				Remove: []string{"res-remove-key-1", "res-remove-key-2"},
 			},
 			ApplyToIngress: ref.To(false),
@@ -109,6 +112,7 @@ func TestGetDAGBuilder(t *testing.T) {
 		assert.EqualValues(t, policy.RequestHeadersPolicy.Set, httpProxyProcessor.RequestHeadersPolicy.Set)
 		assert.ElementsMatch(t, policy.RequestHeadersPolicy.Remove, httpProxyProcessor.RequestHeadersPolicy.Remove)
 		assert.EqualValues(t, policy.ResponseHeadersPolicy.Set, httpProxyProcessor.ResponseHeadersPolicy.Set)
+		assert.EqualValues(t, policy.ResponseHeadersPolicy.Add, httpProxyProcessor.ResponseHeadersPolicy.Add)
 		assert.ElementsMatch(t, policy.ResponseHeadersPolicy.Remove, httpProxyProcessor.ResponseHeadersPolicy.Remove)
 
 		ingressProcessor := mustGetIngressProcessor(t, got)
@@ -272,6 +272,45 @@ func parseDefaultHTTPVersions(versions []contour_api_v1alpha1.HTTPVersionType) [
 	return parsed
 }
 
+// defaultHTTP2KeepaliveTimeout is how long Envoy waits for the
+// acknowledgement of an HTTP/2 PING frame when no timeout is configured.
+const defaultHTTP2KeepaliveTimeout = 20 * time.Second
+
+// setListenerKeepAlive applies the keepalive of downstream connections
+// to the listener configuration.
+func setListenerKeepAlive(cfg *xdscache_v3.ListenerConfig, keepAlive *contour_api_v1alpha1.EnvoyListenerKeepAlive) error {
+	if keepAlive == nil {
+		return nil
+	}
+
+	if tcp := keepAlive.TCP; tcp != nil {
+		cfg.TCPKeepalive = envoy_v3.TCPKeepalive{
+			IdleTime:      int64(tcp.IdleTime),
+			ProbeInterval: int64(tcp.ProbeInterval),
+			ProbeCount:    int64(tcp.MaxProbes),
+		}
+	}
+
+	if http2 := keepAlive.HTTP2; http2 != nil {
+		interval, err := time.ParseDuration(http2.Interval)
+		if err != nil {
+			return fmt.Errorf("failed to parse HTTP/2 keepalive interval: %w", err)
+		}
+
+		timeout := defaultHTTP2KeepaliveTimeout
+		if http2.Timeout != "" {
+			if timeout, err = time.ParseDuration(http2.Timeout); err != nil {
+				return fmt.Errorf("failed to parse HTTP/2 keepalive timeout: %w", err)
+			}
+		}
+
+		cfg.HTTP2KeepaliveInterval = interval
+		cfg.HTTP2KeepaliveTimeout = timeout
+	}
+
+	return nil
+}
+
 func (ctx *serveContext) convertToContourConfigurationSpec() contour_api_v1alpha1.ContourConfigurationSpec {
 	ingress := &contour_api_v1alpha1.IngressConfig{}
 	if len(ctx.ingressClassName) > 0 {
@@ -282,7 +321,9 @@ func (ctx *serveContext) convertToContourConfigurationSpec() contour_api_v1alpha
 	var gatewayConfig *contour_api_v1alpha1.GatewayConfig
 	if ctx.Config.GatewayConfig != nil {
 		gatewayConfig = &contour_api_v1alpha1.GatewayConfig{
-			ControllerName: ctx.Config.GatewayConfig.ControllerName,
+			ControllerName:          ctx.Config.GatewayConfig.ControllerName,
+			AllowedHostnameSuffixes: ctx.Config.GatewayConfig.AllowedHostnameSuffixes,
+			DisabledRouteKinds:      ctx.Config.GatewayConfig.DisabledRouteKinds,
 		}
 
 		if ctx.Config.GatewayConfig.GatewayRef != nil {
@@ -291,6 +332,14 @@ func (ctx *serveContext) convertToContourConfigurationSpec() contour_api_v1alpha
 				Name:      ctx.Config.GatewayConfig.GatewayRef.Name,
 			}
 		}
+
+		if ctx.Config.GatewayConfig.DefaultBackend != nil {
+			gatewayConfig.DefaultBackend = &contour_api_v1alpha1.GatewayDefaultBackend{
+				Namespace: ctx.Config.GatewayConfig.DefaultBackend.Namespace,
+				Name:      ctx.Config.GatewayConfig.DefaultBackend.Name,
+				Port:      ctx.Config.GatewayConfig.DefaultBackend.Port,
+			}
+		}
 	}
 
 	var cipherSuites []string
@@ -353,6 +402,15 @@ func (ctx *serveContext) convertToContourConfigurationSpec() contour_api_v1alpha
 	if len(ctx.Config.Timeouts.ConnectTimeout) > 0 {
 		timeoutParams.ConnectTimeout = ref.To(ctx.Config.Timeouts.ConnectTimeout)
 	}
+	if timeoutResponse := ctx.Config.Timeouts.TimeoutResponse; timeoutResponse != nil {
+		timeoutParams.TimeoutResponse = &contour_api_v1alpha1.TimeoutResponse{
+			Body:        timeoutResponse.Body,
+			ContentType: timeoutResponse.ContentType,
+		}
+		if timeoutResponse.StatusCode != 0 {
+			timeoutParams.TimeoutResponse.StatusCode = ref.To(timeoutResponse.StatusCode)
+		}
+	}
 
 	var dnsLookupFamily contour_api_v1alpha1.ClusterDNSFamilyType
 	switch ctx.Config.Cluster.DNSLookupFamily {
@@ -366,6 +424,20 @@ func (ctx *serveContext) convertToContourConfigurationSpec() contour_api_v1alpha
 		dnsLookupFamily = contour_api_v1alpha1.AllClusterDNSFamily
 	}
 
+	var localityWeightedLB *contour_api_v1alpha1.LocalityWeightedLBConfig
+	if ctx.Config.Cluster.LocalityWeightedLB.Enabled {
+		localityWeightedLB = &contour_api_v1alpha1.LocalityWeightedLBConfig{
+			ZoneWeights: ctx.Config.Cluster.LocalityWeightedLB.ZoneWeights,
+		}
+	}
+
+	var topologyAwareRouting *contour_api_v1alpha1.TopologyAwareRoutingConfig
+	if ctx.Config.Cluster.TopologyAwareRouting.Enabled {
+		topologyAwareRouting = &contour_api_v1alpha1.TopologyAwareRoutingConfig{
+			MinClusterSize: ctx.Config.Cluster.TopologyAwareRouting.MinClusterSize,
+		}
+	}
+
 	var rateLimitService *contour_api_v1alpha1.RateLimitServiceConfig
 	if ctx.Config.RateLimitService.ExtensionService != "" {
 
@@ -423,15 +495,34 @@ func (ctx *serveContext) convertToContourConfigurationSpec() contour_api_v1alpha
 	policy := &contour_api_v1alpha1.PolicyConfig{
 		RequestHeadersPolicy: &contour_api_v1alpha1.HeadersPolicy{
 			Set:    ctx.Config.Policy.RequestHeadersPolicy.Set,
+			Add:    ctx.Config.Policy.RequestHeadersPolicy.Add,
 			Remove: ctx.Config.Policy.RequestHeadersPolicy.Remove,
 		},
 		ResponseHeadersPolicy: &contour_api_v1alpha1.HeadersPolicy{
 			Set:    ctx.Config.Policy.ResponseHeadersPolicy.Set,
+			Add:    ctx.Config.Policy.ResponseHeadersPolicy.Add,
 			Remove: ctx.Config.Policy.ResponseHeadersPolicy.Remove,
 		},
-		ApplyToIngress: ref.To(ctx.Config.Policy.ApplyToIngress),
+		ApplyToIngress:    ref.To(ctx.Config.Policy.ApplyToIngress),
+		ApplyToGatewayAPI: ref.To(ctx.Config.Policy.ApplyToGatewayAPI),
+	}
+
+	if ctx.Config.Policy.HSTSPolicy != nil {
+		policy.HSTSPolicy = &contour_api_v1.HSTSPolicy{
+			MaxAge:            ctx.Config.Policy.HSTSPolicy.MaxAge,
+			IncludeSubDomains: ctx.Config.Policy.HSTSPolicy.IncludeSubDomains,
+			Preload:           ctx.Config.Policy.HSTSPolicy.Preload,
+		}
 	}
 
+	if ctx.Config.Policy.CSPPolicy != nil {
+		policy.CSPPolicy = &contour_api_v1.CSPPolicy{
+			Directives: ctx.Config.Policy.CSPPolicy.Directives,
+		}
+	}
+
+	policy.UpstreamClusterHeader = ctx.Config.Policy.UpstreamClusterHeader
+
 	var clientCertificate *contour_api_v1alpha1.NamespacedName
 	if len(ctx.Config.TLS.ClientCertificate.Name) > 0 {
 		clientCertificate = &contour_api_v1alpha1.NamespacedName{
@@ -484,6 +575,7 @@ func (ctx *serveContext) convertToContourConfigurationSpec() contour_api_v1alpha
 				UseProxyProto:              &ctx.useProxyProto,
 				DisableAllowChunkedLength:  &ctx.Config.DisableAllowChunkedLength,
 				DisableMergeSlashes:        &ctx.Config.DisableMergeSlashes,
+				SetRequestIDInResponse:     &ctx.Config.SetRequestIDInResponse,
 				ServerHeaderTransformation: serverHeaderTransformation,
 				ConnectionBalancer:         ctx.Config.Listener.ConnectionBalancer,
 				TLS: &contour_api_v1alpha1.EnvoyTLS{
@@ -520,7 +612,11 @@ func (ctx *serveContext) convertToContourConfigurationSpec() contour_api_v1alpha
 			DefaultHTTPVersions: defaultHTTPVersions,
 			Timeouts:            timeoutParams,
 			Cluster: &contour_api_v1alpha1.ClusterParameters{
-				DNSLookupFamily: dnsLookupFamily,
+				DNSLookupFamily:           dnsLookupFamily,
+				LocalityWeightedLB:        localityWeightedLB,
+				TopologyAwareRouting:      topologyAwareRouting,
+				DefaultLoadBalancerPolicy: ctx.Config.Cluster.DefaultLoadBalancerPolicy,
+				EndpointWeightLabel:       ctx.Config.Cluster.EndpointWeightLabel,
 			},
 			Network: &contour_api_v1alpha1.NetworkParameters{
 				XffNumTrustedHops: &ctx.Config.Network.XffNumTrustedHops,
@@ -534,6 +630,7 @@ func (ctx *serveContext) convertToContourConfigurationSpec() contour_api_v1alpha
 			FallbackCertificate:   fallbackCertificate,
 		},
 		EnableExternalNameService:   &ctx.Config.EnableExternalNameService,
+		UseEndpointSlices:           &ctx.Config.UseEndpointSlices,
 		GlobalExternalAuthorization: globalExtAuth,
 		RateLimitService:            rateLimitService,
 		Policy:                      policy,
@@ -33,7 +33,9 @@ import (
 	envoy_v3 "github.com/projectcontour/contour/internal/envoy/v3"
 	"github.com/projectcontour/contour/internal/fixture"
 	"github.com/projectcontour/contour/internal/ref"
+	xdscache_v3 "github.com/projectcontour/contour/internal/xdscache/v3"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 )
 
@@ -363,6 +365,61 @@ func TestParseHTTPVersions(t *testing.T) {
 	}
 }
 
+func TestSetListenerKeepAlive(t *testing.T) {
+	cases := map[string]struct {
+		keepAlive *contour_api_v1alpha1.EnvoyListenerKeepAlive
+		want      xdscache_v3.ListenerConfig
+		wantErr   bool
+	}{
+		"unset": {},
+		"tcp keepalive": {
+			keepAlive: &contour_api_v1alpha1.EnvoyListenerKeepAlive{
+				TCP: &contour_api_v1alpha1.TCPKeepAlive{IdleTime: 60, MaxProbes: 3},
+			},
+			want: xdscache_v3.ListenerConfig{
+				TCPKeepalive: envoy_v3.TCPKeepalive{IdleTime: 60, ProbeCount: 3},
+			},
+		},
+		"http/2 keepalive with the default timeout": {
+			keepAlive: &contour_api_v1alpha1.EnvoyListenerKeepAlive{
+				HTTP2: &contour_api_v1alpha1.HTTP2KeepAlive{Interval: "30s"},
+			},
+			want: xdscache_v3.ListenerConfig{
+				HTTP2KeepaliveInterval: 30 * time.Second,
+				HTTP2KeepaliveTimeout:  20 * time.Second,
+			},
+		},
+		"http/2 keepalive with a timeout": {
+			keepAlive: &contour_api_v1alpha1.EnvoyListenerKeepAlive{
+				HTTP2: &contour_api_v1alpha1.HTTP2KeepAlive{Interval: "1m", Timeout: "5s"},
+			},
+			want: xdscache_v3.ListenerConfig{
+				HTTP2KeepaliveInterval: time.Minute,
+				HTTP2KeepaliveTimeout:  5 * time.Second,
+			},
+		},
+		"invalid http/2 keepalive interval": {
+			keepAlive: &contour_api_v1alpha1.EnvoyListenerKeepAlive{
+				HTTP2: &contour_api_v1alpha1.HTTP2KeepAlive{Interval: "often"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got xdscache_v3.ListenerConfig
+			err := setListenerKeepAlive(&got, tc.keepAlive)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
 func TestConvertServeContext(t *testing.T) {
 	defaultContext := func() *serveContext {
 		ctx := newServeContext()
@@ -410,6 +467,7 @@ func TestConvertServeContext(t *testing.T) {
 					UseProxyProto:              ref.To(false),
 					DisableAllowChunkedLength:  ref.To(false),
 					DisableMergeSlashes:        ref.To(false),
+					SetRequestIDInResponse:     ref.To(false),
 					ServerHeaderTransformation: contour_api_v1alpha1.OverwriteServerHeader,
 					TLS: &contour_api_v1alpha1.EnvoyTLS{
 						MinimumProtocolVersion: "",
@@ -483,12 +541,14 @@ func TestConvertServeContext(t *testing.T) {
 				FallbackCertificate:   nil,
 			},
 			EnableExternalNameService:   ref.To(false),
+			UseEndpointSlices:           ref.To(false),
 			RateLimitService:            nil,
 			GlobalExternalAuthorization: nil,
 			Policy: &contour_api_v1alpha1.PolicyConfig{
 				RequestHeadersPolicy:  &contour_api_v1alpha1.HeadersPolicy{},
 				ResponseHeadersPolicy: &contour_api_v1alpha1.HeadersPolicy{},
 				ApplyToIngress:        ref.To(false),
+				ApplyToGatewayAPI:     ref.To(false),
 			},
 			Metrics: &contour_api_v1alpha1.MetricsConfig{
 				Address: "0.0.0.0",
@@ -518,9 +578,11 @@ func TestConvertServeContext(t *testing.T) {
 					},
 					ResponseHeadersPolicy: config.HeadersPolicy{
 						Set:    map[string]string{"custom-response-header-set": "foo-bar", "Host": "response-bar.com"},
+						Add:    map[string]string{"custom-response-header-add": "foo-bar"},
 						Remove: []string{"custom-response-header-remove"},
 					},
-					ApplyToIngress: true,
+					ApplyToIngress:    true,
+					ApplyToGatewayAPI: true,
 				}
 				return ctx
 			},
@@ -532,13 +594,75 @@ func TestConvertServeContext(t *testing.T) {
 					},
 					ResponseHeadersPolicy: &contour_api_v1alpha1.HeadersPolicy{
 						Set:    map[string]string{"custom-response-header-set": "foo-bar", "Host": "response-bar.com"},
+						Add:    map[string]string{"custom-response-header-add": "foo-bar"},
 						Remove: []string{"custom-response-header-remove"},
 					},
-					ApplyToIngress: ref.To(true),
+					ApplyToIngress:    ref.To(true),
+					ApplyToGatewayAPI: ref.To(true),
+				}
+				return cfg
+			},
+		},
+		"hsts policy": {
+			getServeContext: func(ctx *serveContext) *serveContext {
+				ctx.Config.Policy.HSTSPolicy = &config.HSTSPolicy{
+					MaxAge:            "8760h",
+					IncludeSubDomains: true,
+					Preload:           true,
+				}
+				return ctx
+			},
+			getContourConfiguration: func(cfg contour_api_v1alpha1.ContourConfigurationSpec) contour_api_v1alpha1.ContourConfigurationSpec {
+				cfg.Policy.HSTSPolicy = &contour_api_v1.HSTSPolicy{
+					MaxAge:            "8760h",
+					IncludeSubDomains: true,
+					Preload:           true,
 				}
 				return cfg
 			},
 		},
+		"csp policy": {
+			getServeContext: func(ctx *serveContext) *serveContext {
+				ctx.Config.Policy.CSPPolicy = &config.CSPPolicy{
+					Directives: "default-src 'self'",
+				}
+				return ctx
+			},
+			getContourConfiguration: func(cfg contour_api_v1alpha1.ContourConfigurationSpec) contour_api_v1alpha1.ContourConfigurationSpec {
+				cfg.Policy.CSPPolicy = &contour_api_v1.CSPPolicy{
+					Directives: "default-src 'self'",
+				}
+				return cfg
+			},
+		},
+		"timeout response": {
+			getServeContext: func(ctx *serveContext) *serveContext {
+				ctx.Config.Timeouts.TimeoutResponse = &config.TimeoutResponse{
+					StatusCode:  503,
+					Body:        "<h1>Timed out</h1>",
+					ContentType: "text/html",
+				}
+				return ctx
+			},
+			getContourConfiguration: func(cfg contour_api_v1alpha1.ContourConfigurationSpec) contour_api_v1alpha1.ContourConfigurationSpec {
+				cfg.Envoy.Timeouts.TimeoutResponse = &contour_api_v1alpha1.TimeoutResponse{
+					StatusCode:  ref.To(503),
+					Body:        "<h1>Timed out</h1>",
+					ContentType: "text/html",
+				}
+				return cfg
+			},
+		},
+		"upstream cluster header": {
+			getServeContext: func(ctx *serveContext) *serveContext {
+				ctx.Config.Policy.UpstreamClusterHeader = "x-upstream-cluster"
+				return ctx
+			},
+			getContourConfiguration: func(cfg contour_api_v1alpha1.ContourConfigurationSpec) contour_api_v1alpha1.ContourConfigurationSpec {
+				cfg.Policy.UpstreamClusterHeader = "x-upstream-cluster"
+				return cfg
+			},
+		},
 		"ingress": {
 			getServeContext: func(ctx *serveContext) *serveContext {
 				ctx.ingressClassName = "coolclass"
@@ -567,6 +691,62 @@ func TestConvertServeContext(t *testing.T) {
 				return cfg
 			},
 		},
+		"gatewayapi - allowed hostname suffixes": {
+			getServeContext: func(ctx *serveContext) *serveContext {
+				ctx.Config.GatewayConfig = &config.GatewayParameters{
+					ControllerName:          "projectcontour.io/gateway-controller",
+					AllowedHostnameSuffixes: []string{"example.com"},
+				}
+				return ctx
+			},
+			getContourConfiguration: func(cfg contour_api_v1alpha1.ContourConfigurationSpec) contour_api_v1alpha1.ContourConfigurationSpec {
+				cfg.Gateway = &contour_api_v1alpha1.GatewayConfig{
+					ControllerName:          "projectcontour.io/gateway-controller",
+					AllowedHostnameSuffixes: []string{"example.com"},
+				}
+				return cfg
+			},
+		},
+		"gatewayapi - disabled route kinds": {
+			getServeContext: func(ctx *serveContext) *serveContext {
+				ctx.Config.GatewayConfig = &config.GatewayParameters{
+					ControllerName:     "projectcontour.io/gateway-controller",
+					DisabledRouteKinds: []string{"TLSRoute"},
+				}
+				return ctx
+			},
+			getContourConfiguration: func(cfg contour_api_v1alpha1.ContourConfigurationSpec) contour_api_v1alpha1.ContourConfigurationSpec {
+				cfg.Gateway = &contour_api_v1alpha1.GatewayConfig{
+					ControllerName:     "projectcontour.io/gateway-controller",
+					DisabledRouteKinds: []string{"TLSRoute"},
+				}
+				return cfg
+			},
+		},
+		"gatewayapi - default backend": {
+			getServeContext: func(ctx *serveContext) *serveContext {
+				ctx.Config.GatewayConfig = &config.GatewayParameters{
+					ControllerName: "projectcontour.io/gateway-controller",
+					DefaultBackend: &config.GatewayDefaultBackend{
+						Namespace: "gateway-namespace",
+						Name:      "default-backend",
+						Port:      80,
+					},
+				}
+				return ctx
+			},
+			getContourConfiguration: func(cfg contour_api_v1alpha1.ContourConfigurationSpec) contour_api_v1alpha1.ContourConfigurationSpec {
+				cfg.Gateway = &contour_api_v1alpha1.GatewayConfig{
+					ControllerName: "projectcontour.io/gateway-controller",
+					DefaultBackend: &contour_api_v1alpha1.GatewayDefaultBackend{
+						Namespace: "gateway-namespace",
+						Name:      "default-backend",
+						Port:      80,
+					},
+				}
+				return cfg
+			},
+		},
 		"gatewayapi - specific gateway": {
 			getServeContext: func(ctx *serveContext) *serveContext {
 				ctx.Config.GatewayConfig = &config.GatewayParameters{
@@ -691,6 +871,51 @@ func TestConvertServeContext(t *testing.T) {
 				return cfg
 			},
 		},
+		"set request id in response": {
+			getServeContext: func(ctx *serveContext) *serveContext {
+				ctx.Config.SetRequestIDInResponse = true
+				return ctx
+			},
+			getContourConfiguration: func(cfg contour_api_v1alpha1.ContourConfigurationSpec) contour_api_v1alpha1.ContourConfigurationSpec {
+				cfg.Envoy.Listener.SetRequestIDInResponse = ref.To(true)
+				return cfg
+			},
+		},
+		"default load balancer policy": {
+			getServeContext: func(ctx *serveContext) *serveContext {
+				ctx.Config.Cluster.DefaultLoadBalancerPolicy = "WeightedLeastRequest"
+				return ctx
+			},
+			getContourConfiguration: func(cfg contour_api_v1alpha1.ContourConfigurationSpec) contour_api_v1alpha1.ContourConfigurationSpec {
+				cfg.Envoy.Cluster.DefaultLoadBalancerPolicy = "WeightedLeastRequest"
+				return cfg
+			},
+		},
+		"endpoint weight label": {
+			getServeContext: func(ctx *serveContext) *serveContext {
+				ctx.Config.Cluster.EndpointWeightLabel = "projectcontour.io/weight"
+				return ctx
+			},
+			getContourConfiguration: func(cfg contour_api_v1alpha1.ContourConfigurationSpec) contour_api_v1alpha1.ContourConfigurationSpec {
+				cfg.Envoy.Cluster.EndpointWeightLabel = "projectcontour.io/weight"
+				return cfg
+			},
+		},
+		"topology aware routing": {
+			getServeContext: func(ctx *serveContext) *serveContext {
+				ctx.Config.Cluster.TopologyAwareRouting = config.TopologyAwareRoutingParameters{
+					Enabled:        true,
+					MinClusterSize: 2,
+				}
+				return ctx
+			},
+			getContourConfiguration: func(cfg contour_api_v1alpha1.ContourConfigurationSpec) contour_api_v1alpha1.ContourConfigurationSpec {
+				cfg.Envoy.Cluster.TopologyAwareRouting = &contour_api_v1alpha1.TopologyAwareRoutingConfig{
+					MinClusterSize: 2,
+				}
+				return cfg
+			},
+		},
 		"server header transformation": {
 			getServeContext: func(ctx *serveContext) *serveContext {
 				ctx.Config.ServerHeaderTransformation = config.AppendIfAbsentServerHeader
@@ -34,6 +34,7 @@ import (
 const (
 	prometheusURL      = "http://unix/stats/prometheus"
 	healthcheckFailURL = "http://unix/healthcheck/fail"
+	drainListenersURL  = "http://unix/drain_listeners?graceful"
 	prometheusStat     = "envoy_http_downstream_cx_active"
 )
 
@@ -81,6 +82,10 @@ type shutdownContext struct {
 	// shutdownReadyFile defines the name of the file that is used to signal that shutdown is completed.
 	shutdownReadyFile string
 
+	// drainStrategy defines how Envoy is told to drain connections, either
+	// "immediate" (fail its health checks) or "gradual" (gracefully drain its listeners).
+	drainStrategy string
+
 	logrus.FieldLogger
 }
 
@@ -99,6 +104,7 @@ func newShutdownContext() *shutdownContext {
 		checkDelay:         0,
 		drainDelay:         0,
 		minOpenConnections: 0,
+		drainStrategy:      "immediate",
 	}
 }
 
@@ -149,7 +155,11 @@ func (s *shutdownContext) shutdownHandler() {
 	time.Sleep(s.drainDelay)
 
 	// Send shutdown signal to Envoy to start draining connections
-	s.Infof("failing envoy healthchecks")
+	if s.drainStrategy == "gradual" {
+		s.Infof("gracefully draining envoy listeners")
+	} else {
+		s.Infof("failing envoy healthchecks")
+	}
 
 	// Retry any failures to shutdownEnvoy(s.adminAddress) in a Backoff time window
 	// doing 4 total attempts, multiplying the Duration by the Factor
@@ -164,12 +174,12 @@ func (s *shutdownContext) shutdownHandler() {
 		return true
 	}, func() error {
 		s.Infof("attempting to shutdown")
-		return shutdownEnvoy(s.adminAddress)
+		return shutdownEnvoy(s.adminAddress, s.drainStrategy)
 	})
 	if err != nil {
 		// May be conflict if max retries were hit, or may be something unrelated
 		// like permissions or a network error
-		s.WithField("context", "shutdownHandler").Errorf("error sending envoy healthcheck fail after 4 attempts: %v", err)
+		s.WithField("context", "shutdownHandler").Errorf("error sending envoy shutdown signal after 4 attempts: %v", err)
 	}
 
 	s.WithField("context", "shutdownHandler").Infof("waiting %s before polling for draining connections", s.checkDelay)
@@ -201,8 +211,15 @@ func (s *shutdownContext) shutdownHandler() {
 	}
 }
 
-// shutdownEnvoy sends a POST request to /healthcheck/fail to tell Envoy to start draining connections
-func shutdownEnvoy(adminAddress string) error {
+// shutdownEnvoy tells Envoy to start draining connections. With the "gradual" drain
+// strategy it sends a POST request to /drain_listeners?graceful, so that Envoy spreads
+// closing connections over its drain time. Otherwise it sends a POST request to
+// /healthcheck/fail, which makes Envoy close every connection as soon as it can.
+func shutdownEnvoy(adminAddress, drainStrategy string) error {
+	url := healthcheckFailURL
+	if drainStrategy == "gradual" {
+		url = drainListenersURL
+	}
 
 	httpClient := http.Client{
 		Transport: &http.Transport{
@@ -212,14 +229,14 @@ func shutdownEnvoy(adminAddress string) error {
 		},
 	}
 	/* #nosec */
-	resp, err := httpClient.Post(healthcheckFailURL, "", nil)
+	resp, err := httpClient.Post(url, "", nil)
 	if err != nil {
-		return fmt.Errorf("creating healthcheck fail POST request failed: %s", err)
+		return fmt.Errorf("creating %q POST request failed: %s", url, err)
 	}
 
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("POST for %q returned HTTP status %s", healthcheckFailURL, resp.Status)
+		return fmt.Errorf("POST for %q returned HTTP status %s", url, resp.Status)
 	}
 	return nil
 }
@@ -321,6 +338,7 @@ func registerShutdown(cmd *kingpin.CmdClause, log logrus.FieldLogger) (*kingpin.
 	shutdown.Flag("check-delay", "Time to wait before polling Envoy for open connections.").Default("0s").DurationVar(&ctx.checkDelay)
 	shutdown.Flag("check-interval", "Time to poll Envoy for open connections.").DurationVar(&ctx.checkInterval)
 	shutdown.Flag("drain-delay", "Time to wait before draining Envoy connections.").Default("0s").DurationVar(&ctx.drainDelay)
+	shutdown.Flag("drain-strategy", "How Envoy drains connections, must match Envoy's --drain-strategy (immediate, gradual).").Default("immediate").EnumVar(&ctx.drainStrategy, "immediate", "gradual")
 	shutdown.Flag("min-open-connections", "Min number of open connections when polling Envoy.").IntVar(&ctx.minOpenConnections)
 	shutdown.Flag("ready-file", "File to write when shutdown is completed.").Default(shutdownReadyFile).StringVar(&ctx.shutdownReadyFile)
 
@@ -17,6 +17,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -134,6 +135,53 @@ func TestShutdownManager_ShutdownReadyHandler_ClientCancel(t *testing.T) {
 	handler.ServeHTTP(rr, req)
 }
 
+func TestShutdownEnvoy(t *testing.T) {
+	tests := map[string]struct {
+		drainStrategy string
+		wantURI       string
+	}{
+		"immediate drain fails health checks": {
+			drainStrategy: "immediate",
+			wantURI:       "/healthcheck/fail",
+		},
+		"gradual drain gracefully drains listeners": {
+			drainStrategy: "gradual",
+			wantURI:       "/drain_listeners?graceful",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			tmpdir, err := os.MkdirTemp("", "shutdownmanager_test-*")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(tmpdir)
+
+			adminAddress := path.Join(tmpdir, "admin.sock")
+			l, err := net.Listen("unix", adminAddress)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var gotURI string
+			srv := &http.Server{
+				Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.Method == http.MethodPost {
+						gotURI = r.RequestURI
+					}
+				}),
+				ReadHeaderTimeout: time.Second,
+			}
+			go func() { _ = srv.Serve(l) }()
+			defer srv.Close()
+
+			assert.NoError(t, shutdownEnvoy(adminAddress, tc.drainStrategy))
+			assert.Equal(t, tc.wantURI, gotURI)
+		})
+	}
+}
+
 func TestParseOpenConnections(t *testing.T) {
 	type testcase struct {
 		stats           io.Reader
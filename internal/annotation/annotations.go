@@ -15,8 +15,10 @@ package annotation
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/projectcontour/contour/internal/timeout"
 	networking_v1 "k8s.io/api/networking/v1"
@@ -60,13 +62,14 @@ var annotationsByKind = map[string]map[string]struct{}{
 		"projectcontour.io/websocket-routes":             {},
 	},
 	"Service": {
-		"projectcontour.io/max-connections":       {},
-		"projectcontour.io/max-pending-requests":  {},
-		"projectcontour.io/max-requests":          {},
-		"projectcontour.io/max-retries":           {},
-		"projectcontour.io/upstream-protocol.h2":  {},
-		"projectcontour.io/upstream-protocol.h2c": {},
-		"projectcontour.io/upstream-protocol.tls": {},
+		"projectcontour.io/max-connections":             {},
+		"projectcontour.io/max-pending-requests":        {},
+		"projectcontour.io/max-requests":                {},
+		"projectcontour.io/max-requests-per-connection": {},
+		"projectcontour.io/max-retries":                 {},
+		"projectcontour.io/upstream-protocol.h2":        {},
+		"projectcontour.io/upstream-protocol.h2c":       {},
+		"projectcontour.io/upstream-protocol.tls":       {},
 	},
 	"HTTPProxy": {
 		"kubernetes.io/ingress.class":     {},
@@ -75,6 +78,13 @@ var annotationsByKind = map[string]map[string]struct{}{
 	"Secret": {
 		"projectcontour.io/generated-by-version": {},
 	},
+	"HTTPRoute": {
+		"projectcontour.io/hedge-per-try-timeout":              {},
+		"projectcontour.io/max-request-body-bytes":             {},
+		"projectcontour.io/request-mirror-percentage":          {},
+		"projectcontour.io/retry-budget-percent":               {},
+		"projectcontour.io/retry-budget-min-retry-concurrency": {},
+	},
 }
 
 // ValidForKind checks if a particular annotation is valid for a given Kind.
@@ -247,6 +257,16 @@ func MaxRequests(o metav1.Object) uint32 {
 	return parseUInt32(ContourAnnotation(o, "max-requests"))
 }
 
+// MaxRequestsPerConnection returns the value of the first matching
+// max-requests-per-connection annotation for the following annotations:
+// 1. projectcontour.io/max-requests-per-connection
+//
+// '0' is returned if the annotation is absent or unparsable, which
+// leaves the number of requests per connection unlimited.
+func MaxRequestsPerConnection(o metav1.Object) uint32 {
+	return parseUInt32(ContourAnnotation(o, "max-requests-per-connection"))
+}
+
 // MaxRetries returns the value of the first matching max-retries
 // annotation for the following annotations:
 // 1. projectcontour.io/max-retries
@@ -255,3 +275,100 @@ func MaxRequests(o metav1.Object) uint32 {
 func MaxRetries(o metav1.Object) uint32 {
 	return parseUInt32(ContourAnnotation(o, "max-retries"))
 }
+
+// MaxRequestBodyBytes returns the value of the
+// "projectcontour.io/max-request-body-bytes" annotation.
+//
+// '0' is returned if the annotation is absent. An error is returned
+// if the annotation is present but not a positive integer that fits
+// in a uint32.
+func MaxRequestBodyBytes(o metav1.Object) (uint32, error) {
+	val := ContourAnnotation(o, "max-request-body-bytes")
+	if len(val) == 0 {
+		return 0, nil
+	}
+
+	v, err := strconv.ParseUint(val, 10, 32)
+	if err != nil || v == 0 {
+		return 0, fmt.Errorf("invalid value %q: must be a positive integer no greater than %d", val, uint32(math.MaxUint32))
+	}
+
+	return uint32(v), nil
+}
+
+// RequestMirrorPercentage returns the value of the
+// "projectcontour.io/request-mirror-percentage" annotation.
+//
+// '100' is returned if the annotation is absent. An error is returned
+// if the annotation is present but not an integer between 0 and 100.
+func RequestMirrorPercentage(o metav1.Object) (uint32, error) {
+	val := ContourAnnotation(o, "request-mirror-percentage")
+	if len(val) == 0 {
+		return 100, nil
+	}
+
+	v, err := strconv.ParseUint(val, 10, 32)
+	if err != nil || v > 100 {
+		return 0, fmt.Errorf("invalid value %q: must be an integer between 0 and 100", val)
+	}
+
+	return uint32(v), nil
+}
+
+// HedgePerTryTimeout returns the value of the
+// "projectcontour.io/hedge-per-try-timeout" annotation.
+//
+// '0' is returned if the annotation is absent. An error is returned
+// if the annotation is present but not a positive duration.
+func HedgePerTryTimeout(o metav1.Object) (time.Duration, error) {
+	val := ContourAnnotation(o, "hedge-per-try-timeout")
+	if len(val) == 0 {
+		return 0, nil
+	}
+
+	d, err := time.ParseDuration(val)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid value %q: must be a positive duration", val)
+	}
+
+	return d, nil
+}
+
+// RetryBudgetPercent returns the value of the
+// "projectcontour.io/retry-budget-percent" annotation.
+//
+// '0' is returned if the annotation is absent. An error is returned
+// if the annotation is present but not an integer between 1 and 100.
+func RetryBudgetPercent(o metav1.Object) (uint32, error) {
+	val := ContourAnnotation(o, "retry-budget-percent")
+	if len(val) == 0 {
+		return 0, nil
+	}
+
+	v, err := strconv.ParseUint(val, 10, 32)
+	if err != nil || v < 1 || v > 100 {
+		return 0, fmt.Errorf("invalid value %q: must be an integer between 1 and 100", val)
+	}
+
+	return uint32(v), nil
+}
+
+// RetryBudgetMinRetryConcurrency returns the value of the
+// "projectcontour.io/retry-budget-min-retry-concurrency" annotation.
+//
+// '0' is returned if the annotation is absent. An error is returned
+// if the annotation is present but not a positive integer that fits
+// in a uint32.
+func RetryBudgetMinRetryConcurrency(o metav1.Object) (uint32, error) {
+	val := ContourAnnotation(o, "retry-budget-min-retry-concurrency")
+	if len(val) == 0 {
+		return 0, nil
+	}
+
+	v, err := strconv.ParseUint(val, 10, 32)
+	if err != nil || v == 0 {
+		return 0, fmt.Errorf("invalid value %q: must be a positive integer no greater than %d", val, uint32(math.MaxUint32))
+	}
+
+	return uint32(v), nil
+}
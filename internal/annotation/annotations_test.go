@@ -16,6 +16,7 @@ package annotation
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	contour_api_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
 	"github.com/stretchr/testify/assert"
@@ -336,6 +337,261 @@ func TestTLSCertNamespace(t *testing.T) {
 	}
 }
 
+func TestMaxRequestBodyBytes(t *testing.T) {
+	tests := map[string]struct {
+		value   string
+		want    uint32
+		wantErr bool
+	}{
+		"absent": {
+			want: 0,
+		},
+		"valid": {
+			value: "1024",
+			want:  1024,
+		},
+		"max uint32": {
+			value: "4294967295",
+			want:  4294967295,
+		},
+		"zero": {
+			value:   "0",
+			wantErr: true,
+		},
+		"negative": {
+			value:   "-1",
+			wantErr: true,
+		},
+		"too large": {
+			value:   "4294967296",
+			wantErr: true,
+		},
+		"not a number": {
+			value:   "10MB",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			obj := &metav1.ObjectMeta{
+				Annotations: map[string]string{},
+			}
+			if len(tc.value) > 0 {
+				obj.Annotations["projectcontour.io/max-request-body-bytes"] = tc.value
+			}
+
+			got, err := MaxRequestBodyBytes(obj)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestHedgePerTryTimeout(t *testing.T) {
+	tests := map[string]struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		"absent": {
+			want: 0,
+		},
+		"valid": {
+			value: "250ms",
+			want:  250 * time.Millisecond,
+		},
+		"zero": {
+			value:   "0s",
+			wantErr: true,
+		},
+		"negative": {
+			value:   "-1s",
+			wantErr: true,
+		},
+		"not a duration": {
+			value:   "infinity",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			obj := &metav1.ObjectMeta{
+				Annotations: map[string]string{},
+			}
+			if len(tc.value) > 0 {
+				obj.Annotations["projectcontour.io/hedge-per-try-timeout"] = tc.value
+			}
+
+			got, err := HedgePerTryTimeout(obj)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestRequestMirrorPercentage(t *testing.T) {
+	tests := map[string]struct {
+		value   string
+		want    uint32
+		wantErr bool
+	}{
+		"absent": {
+			want: 100,
+		},
+		"valid": {
+			value: "10",
+			want:  10,
+		},
+		"zero": {
+			value: "0",
+			want:  0,
+		},
+		"one hundred": {
+			value: "100",
+			want:  100,
+		},
+		"too large": {
+			value:   "101",
+			wantErr: true,
+		},
+		"negative": {
+			value:   "-1",
+			wantErr: true,
+		},
+		"not a number": {
+			value:   "10%",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			obj := &metav1.ObjectMeta{
+				Annotations: map[string]string{},
+			}
+			if len(tc.value) > 0 {
+				obj.Annotations["projectcontour.io/request-mirror-percentage"] = tc.value
+			}
+
+			got, err := RequestMirrorPercentage(obj)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestRetryBudgetPercent(t *testing.T) {
+	tests := map[string]struct {
+		value   string
+		want    uint32
+		wantErr bool
+	}{
+		"absent": {
+			want: 0,
+		},
+		"valid": {
+			value: "20",
+			want:  20,
+		},
+		"one hundred": {
+			value: "100",
+			want:  100,
+		},
+		"zero": {
+			value:   "0",
+			wantErr: true,
+		},
+		"too large": {
+			value:   "101",
+			wantErr: true,
+		},
+		"not a number": {
+			value:   "20%",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			obj := &metav1.ObjectMeta{
+				Annotations: map[string]string{},
+			}
+			if len(tc.value) > 0 {
+				obj.Annotations["projectcontour.io/retry-budget-percent"] = tc.value
+			}
+
+			got, err := RetryBudgetPercent(obj)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestRetryBudgetMinRetryConcurrency(t *testing.T) {
+	tests := map[string]struct {
+		value   string
+		want    uint32
+		wantErr bool
+	}{
+		"absent": {
+			want: 0,
+		},
+		"valid": {
+			value: "5",
+			want:  5,
+		},
+		"zero": {
+			value:   "0",
+			wantErr: true,
+		},
+		"negative": {
+			value:   "-1",
+			wantErr: true,
+		},
+		"too large": {
+			value:   "4294967296",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			obj := &metav1.ObjectMeta{
+				Annotations: map[string]string{},
+			}
+			if len(tc.value) > 0 {
+				obj.Annotations["projectcontour.io/retry-budget-min-retry-concurrency"] = tc.value
+			}
+
+			got, err := RetryBudgetMinRetryConcurrency(obj)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
 func TestHttpAllowed(t *testing.T) {
 	tests := map[string]struct {
 		i     *networking_v1.Ingress
@@ -77,6 +77,7 @@ func Defaults() contour_api_v1alpha1.ContourConfigurationSpec {
 				UseProxyProto:              ref.To(false),
 				DisableAllowChunkedLength:  ref.To(false),
 				DisableMergeSlashes:        ref.To(false),
+				SetRequestIDInResponse:     ref.To(false),
 				ServerHeaderTransformation: contour_api_v1alpha1.OverwriteServerHeader,
 				ConnectionBalancer:         "",
 				TLS: &contour_api_v1alpha1.EnvoyTLS{
@@ -142,11 +143,13 @@ func Defaults() contour_api_v1alpha1.ContourConfigurationSpec {
 			FallbackCertificate:   nil,
 		},
 		EnableExternalNameService: ref.To(false),
+		UseEndpointSlices:         ref.To(false),
 		RateLimitService:          nil,
 		Policy: &contour_api_v1alpha1.PolicyConfig{
 			RequestHeadersPolicy:  &contour_api_v1alpha1.HeadersPolicy{},
 			ResponseHeadersPolicy: &contour_api_v1alpha1.HeadersPolicy{},
 			ApplyToIngress:        ref.To(false),
+			ApplyToGatewayAPI:     ref.To(false),
 		},
 		Metrics: &contour_api_v1alpha1.MetricsConfig{
 			Address: "0.0.0.0",
@@ -164,6 +167,7 @@ type Timeouts struct {
 	DelayedClose                  timeout.Setting
 	ConnectionShutdownGracePeriod timeout.Setting
 	ConnectTimeout                time.Duration // Since "infinite" is not valid ConnectTimeout value, use time.Duration instead of timeout.Setting.
+	TimeoutResponse               *contour_api_v1alpha1.TimeoutResponse
 }
 
 func ParseTimeoutPolicy(timeoutParameters *contour_api_v1alpha1.TimeoutParameters) (Timeouts, error) {
@@ -218,6 +222,12 @@ func ParseTimeoutPolicy(timeoutParameters *contour_api_v1alpha1.TimeoutParameter
 			return Timeouts{}, fmt.Errorf("failed to parse connect timeout: %s", err)
 		}
 	}
+	if timeoutParameters.TimeoutResponse != nil {
+		if err := timeoutParameters.TimeoutResponse.Validate(); err != nil {
+			return Timeouts{}, fmt.Errorf("failed to parse timeout response: %s", err)
+		}
+		timeouts.TimeoutResponse = timeoutParameters.TimeoutResponse
+	}
 
 	return timeouts, nil
 }
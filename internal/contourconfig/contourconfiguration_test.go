@@ -55,6 +55,7 @@ func TestOverlayOnDefaults(t *testing.T) {
 				UseProxyProto:              ref.To(true),
 				DisableAllowChunkedLength:  ref.To(true),
 				DisableMergeSlashes:        ref.To(true),
+				SetRequestIDInResponse:     ref.To(true),
 				ServerHeaderTransformation: contour_api_v1alpha1.PassThroughServerHeader,
 				ConnectionBalancer:         "yesplease",
 				TLS: &contour_api_v1alpha1.EnvoyTLS{
@@ -139,6 +140,7 @@ func TestOverlayOnDefaults(t *testing.T) {
 			},
 		},
 		EnableExternalNameService: ref.To(true),
+		UseEndpointSlices:         ref.To(true),
 		RateLimitService: &contour_api_v1alpha1.RateLimitServiceConfig{
 			ExtensionService: contour_api_v1alpha1.NamespacedName{
 				Namespace: "ratelimitservicenamespace",
@@ -155,9 +157,11 @@ func TestOverlayOnDefaults(t *testing.T) {
 			},
 			ResponseHeadersPolicy: &contour_api_v1alpha1.HeadersPolicy{
 				Set:    map[string]string{"set": "val"},
+				Add:    map[string]string{"add": "val"},
 				Remove: []string{"remove"},
 			},
-			ApplyToIngress: ref.To(true),
+			ApplyToIngress:    ref.To(true),
+			ApplyToGatewayAPI: ref.To(true),
 		},
 		Metrics: &contour_api_v1alpha1.MetricsConfig{
 			Address: "9.8.7.6",
@@ -299,6 +303,35 @@ func TestParseTimeoutPolicy(t *testing.T) {
 			},
 			errorMsg: "failed to parse connect timeout",
 		},
+		"timeout response": {
+			config: &contour_api_v1alpha1.TimeoutParameters{
+				TimeoutResponse: &contour_api_v1alpha1.TimeoutResponse{
+					StatusCode: ref.To(503),
+					Body:       "upstream timed out",
+				},
+			},
+			expected: contourconfig.Timeouts{
+				Request:                       timeout.DefaultSetting(),
+				ConnectionIdle:                timeout.DefaultSetting(),
+				StreamIdle:                    timeout.DefaultSetting(),
+				MaxConnectionDuration:         timeout.DefaultSetting(),
+				DelayedClose:                  timeout.DefaultSetting(),
+				ConnectionShutdownGracePeriod: timeout.DefaultSetting(),
+				ConnectTimeout:                0,
+				TimeoutResponse: &contour_api_v1alpha1.TimeoutResponse{
+					StatusCode: ref.To(503),
+					Body:       "upstream timed out",
+				},
+			},
+		},
+		"timeout response invalid": {
+			config: &contour_api_v1alpha1.TimeoutParameters{
+				TimeoutResponse: &contour_api_v1alpha1.TimeoutResponse{
+					StatusCode: ref.To(200),
+				},
+			},
+			errorMsg: "failed to parse timeout response",
+		},
 	}
 
 	for name, tc := range testCases {
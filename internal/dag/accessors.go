@@ -18,7 +18,6 @@ import (
 	"strconv"
 
 	"github.com/projectcontour/contour/internal/annotation"
-	"github.com/projectcontour/contour/internal/xds"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -61,12 +60,13 @@ func (d *DAG) EnsureService(meta types.NamespacedName, port int, healthPort int,
 			HealthPort:       healthSvcPort,
 			Weight:           1,
 		},
-		Protocol:           upstreamProtocol(svc, svcPort),
-		MaxConnections:     annotation.MaxConnections(svc),
-		MaxPendingRequests: annotation.MaxPendingRequests(svc),
-		MaxRequests:        annotation.MaxRequests(svc),
-		MaxRetries:         annotation.MaxRetries(svc),
-		ExternalName:       externalName(svc),
+		Protocol:                 upstreamProtocol(svc, svcPort),
+		MaxConnections:           annotation.MaxConnections(svc),
+		MaxPendingRequests:       annotation.MaxPendingRequests(svc),
+		MaxRequests:              annotation.MaxRequests(svc),
+		MaxRetries:               annotation.MaxRetries(svc),
+		MaxRequestsPerConnection: annotation.MaxRequestsPerConnection(svc),
+		ExternalName:             externalName(svc),
 	}, nil
 }
 
@@ -102,15 +102,31 @@ func validateExternalName(svc *v1.Service, enableExternalNameSvc bool) error {
 	return nil
 }
 
+// upstreamProtocol returns the protocol to use for port of svc. The
+// upstream-protocol annotations take precedence over the port's
+// appProtocol, so that existing Services keep their protocol.
 func upstreamProtocol(svc *v1.Service, port v1.ServicePort) string {
 	up := annotation.ParseUpstreamProtocols(svc.Annotations)
 	protocol := up[port.Name]
 	if protocol == "" {
 		protocol = up[strconv.Itoa(int(port.Port))]
 	}
+	if protocol == "" && port.AppProtocol != nil {
+		protocol = appProtocols[*port.AppProtocol]
+	}
 	return protocol
 }
 
+// appProtocols maps the Service port appProtocol values that Contour
+// understands to upstream protocols. Unknown values are plain HTTP.
+var appProtocols = map[string]string{
+	"kubernetes.io/h2c": "h2c",
+	"kubernetes.io/wss": "tls",
+	"http2":             "h2c",
+	"https":             "tls",
+	"tls":               "tls",
+}
+
 func externalName(svc *v1.Service) string {
 	if svc.Spec.Type != v1.ServiceTypeExternalName {
 		return ""
@@ -218,17 +234,13 @@ func (d *DAG) GetServiceClusters() []*ServiceCluster {
 	for _, cluster := range d.GetClusters() {
 		// A Service has only one WeightedService entry. Fake up a
 		// ServiceCluster so that the visitor can pretend to not
-		// know this.
+		// know this. Any failover services follow it at their
+		// own priority levels.
 		c := &ServiceCluster{
-			ClusterName: xds.ClusterLoadAssignmentName(
-				types.NamespacedName{
-					Name:      cluster.Upstream.Weighted.ServiceName,
-					Namespace: cluster.Upstream.Weighted.ServiceNamespace,
-				},
-				cluster.Upstream.Weighted.ServicePort.Name),
-			Services: []WeightedService{
+			ClusterName: cluster.LoadAssignmentName(),
+			Services: append([]WeightedService{
 				cluster.Upstream.Weighted,
-			},
+			}, cluster.Failover...),
 		}
 
 		res = append(res, c)
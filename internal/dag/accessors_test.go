@@ -18,6 +18,7 @@ import (
 	"testing"
 
 	"github.com/projectcontour/contour/internal/fixture"
+	"github.com/projectcontour/contour/internal/ref"
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -196,3 +197,55 @@ func TestBuilderLookupService(t *testing.T) {
 		})
 	}
 }
+
+func TestUpstreamProtocol(t *testing.T) {
+	tests := map[string]struct {
+		annotations map[string]string
+		appProtocol *string
+		want        string
+	}{
+		"no annotation or appProtocol": {
+			want: "",
+		},
+		"annotation": {
+			annotations: map[string]string{"projectcontour.io/upstream-protocol.h2c": "http"},
+			want:        "h2c",
+		},
+		"h2c appProtocol": {
+			appProtocol: ref.To("kubernetes.io/h2c"),
+			want:        "h2c",
+		},
+		"tls appProtocol": {
+			appProtocol: ref.To("https"),
+			want:        "tls",
+		},
+		"unknown appProtocol": {
+			appProtocol: ref.To("example.com/custom"),
+			want:        "",
+		},
+		"annotation takes precedence over appProtocol": {
+			annotations: map[string]string{"projectcontour.io/upstream-protocol.tls": "8080"},
+			appProtocol: ref.To("kubernetes.io/h2c"),
+			want:        "tls",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			svc := &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "kuard",
+					Namespace:   "default",
+					Annotations: tc.annotations,
+				},
+			}
+			port := v1.ServicePort{
+				Name:        "http",
+				Port:        8080,
+				AppProtocol: tc.appProtocol,
+			}
+
+			assert.Equal(t, tc.want, upstreamProtocol(svc, port))
+		})
+	}
+}
@@ -518,6 +518,32 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 				},
 			),
 		},
+		"insert basic single route with max-request-body-bytes annotation": {
+			gatewayclass: validClass,
+			gateway:      gatewayHTTPAllNamespaces,
+			objs: []interface{}{
+				kuardService,
+				&gatewayapi_v1beta1.HTTPRoute{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "basic",
+						Namespace: "projectcontour",
+						Annotations: map[string]string{
+							"projectcontour.io/max-request-body-bytes": "1024",
+						},
+					},
+					Spec: basicHTTPRoute.Spec,
+				},
+			},
+			want: listeners(
+				&Listener{
+					Name: HTTP_LISTENER_NAME,
+					Port: 8080,
+					VirtualHosts: virtualhosts(
+						virtualhost("test.projectcontour.io", withMaxRequestBodyBytes(prefixrouteHTTPRoute("/", service(kuardService)), 1024)),
+					),
+				},
+			),
+		},
 		"gateway with addresses is unsupported": {
 			gatewayclass: validClass,
 			gateway:      gatewayHTTPWithAddresses,
@@ -927,7 +953,7 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 			},
 			want: listeners(),
 		},
-		"TLS Listener with TLS.Mode=Terminate is invalid": {
+		"TLSRoute attached to TLS Listener with TLS.Mode=Terminate terminates TLS and proxies TCP": {
 			gatewayclass: validClass,
 			gateway: &gatewayapi_v1beta1.Gateway{
 				ObjectMeta: metav1.ObjectMeta{
@@ -937,7 +963,7 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 				Spec: gatewayapi_v1beta1.GatewaySpec{
 					GatewayClassName: gatewayapi_v1beta1.ObjectName(validClass.Name),
 					Listeners: []gatewayapi_v1beta1.Listener{{
-						Port:     80,
+						Port:     443,
 						Protocol: gatewayapi_v1beta1.TLSProtocolType,
 						TLS: &gatewayapi_v1beta1.GatewayTLSConfig{
 							Mode: ref.To(gatewayapi_v1beta1.TLSModeTerminate),
@@ -958,6 +984,52 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 				kuardService,
 				basicTLSRoute,
 			},
+			want: listeners(
+				&Listener{
+					Name: HTTPS_LISTENER_NAME,
+					Port: 8443,
+					SecureVirtualHosts: securevirtualhosts(
+						&SecureVirtualHost{
+							VirtualHost: VirtualHost{
+								Name: "test.projectcontour.io",
+							},
+							Secret: secret(sec1),
+							TCPProxy: &TCPProxy{
+								Clusters: clustersWeight(service(kuardService)),
+							},
+						},
+					),
+				},
+			),
+		},
+		"TLS Listener with TLS.Mode=Terminate is invalid if certificateRef is not specified": {
+			gatewayclass: validClass,
+			gateway: &gatewayapi_v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "contour",
+					Namespace: "projectcontour",
+				},
+				Spec: gatewayapi_v1beta1.GatewaySpec{
+					GatewayClassName: gatewayapi_v1beta1.ObjectName(validClass.Name),
+					Listeners: []gatewayapi_v1beta1.Listener{{
+						Port:     443,
+						Protocol: gatewayapi_v1beta1.TLSProtocolType,
+						TLS: &gatewayapi_v1beta1.GatewayTLSConfig{
+							Mode: ref.To(gatewayapi_v1beta1.TLSModeTerminate),
+						},
+						AllowedRoutes: &gatewayapi_v1beta1.AllowedRoutes{
+							Namespaces: &gatewayapi_v1beta1.RouteNamespaces{
+								From: ref.To(gatewayapi_v1beta1.NamespacesFromAll),
+							},
+						},
+					}},
+				},
+			},
+			objs: []interface{}{
+				sec1,
+				kuardService,
+				basicTLSRoute,
+			},
 			want: listeners(),
 		},
 		"TLS Listener with TLS not defined is invalid": {
@@ -1984,7 +2056,8 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 								Name:   "test.projectcontour.io",
 								Routes: routes(prefixrouteHTTPRoute("/", service(kuardService))),
 							},
-							Secret: secret(sec1),
+							Secret:              secret(sec1),
+							FallbackCertificate: secret(sec1),
 						},
 					),
 				},
@@ -2034,7 +2107,8 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 								Name:   "test.projectcontour.io",
 								Routes: routes(prefixrouteHTTPRoute("/", service(kuardService))),
 							},
-							Secret: secret(sec1),
+							Secret:              secret(sec1),
+							FallbackCertificate: secret(sec1),
 						},
 					),
 				},
@@ -2145,7 +2219,8 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 								Name:   "test.projectcontour.io",
 								Routes: routes(prefixrouteHTTPRoute("/", service(kuardService))),
 							},
-							Secret: secret(sec2),
+							Secret:              secret(sec2),
+							FallbackCertificate: secret(sec2),
 						},
 					),
 				},
@@ -2196,7 +2271,8 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 								Name:   "test.projectcontour.io",
 								Routes: routes(prefixrouteHTTPRoute("/", service(kuardService))),
 							},
-							Secret: secret(sec2),
+							Secret:              secret(sec2),
+							FallbackCertificate: secret(sec2),
 						},
 					),
 				},
@@ -2486,7 +2562,8 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 								Name:   "test.projectcontour.io",
 								Routes: routes(prefixrouteHTTPRoute("/", service(blogService))),
 							},
-							Secret: secret(sec1),
+							Secret:              secret(sec1),
+							FallbackCertificate: secret(sec1),
 						},
 					),
 				},
@@ -2739,6 +2816,138 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 				},
 			),
 		},
+		"route with hedge-per-try-timeout annotation hedges idempotent method matches": {
+			gatewayclass: validClass,
+			gateway:      gatewayHTTPAllNamespaces,
+			objs: []interface{}{
+				kuardService,
+				&gatewayapi_v1beta1.HTTPRoute{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "basic",
+						Namespace: "projectcontour",
+						Annotations: map[string]string{
+							"projectcontour.io/hedge-per-try-timeout": "100ms",
+						},
+					},
+					Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+						CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+							ParentRefs: []gatewayapi_v1beta1.ParentReference{gatewayapi.GatewayParentRef("projectcontour", "contour")},
+						},
+						Hostnames: []gatewayapi_v1beta1.Hostname{
+							"test.projectcontour.io",
+						},
+						Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+							Matches: []gatewayapi_v1beta1.HTTPRouteMatch{{
+								Path: &gatewayapi_v1beta1.HTTPPathMatch{
+									Type:  ref.To(gatewayapi_v1beta1.PathMatchPathPrefix),
+									Value: ref.To("/"),
+								},
+								Method: ref.To(gatewayapi_v1beta1.HTTPMethodGet),
+							}, {
+								Path: &gatewayapi_v1beta1.HTTPPathMatch{
+									Type:  ref.To(gatewayapi_v1beta1.PathMatchPathPrefix),
+									Value: ref.To("/"),
+								},
+								Method: ref.To(gatewayapi_v1beta1.HTTPMethodPost),
+							}},
+							BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
+						}},
+					},
+				},
+			},
+			want: listeners(
+				&Listener{
+					Name: HTTP_LISTENER_NAME,
+					Port: 8080,
+					VirtualHosts: virtualhosts(virtualhost("test.projectcontour.io",
+						&Route{
+							PathMatchCondition: prefixString("/"),
+							HeaderMatchConditions: []HeaderMatchCondition{
+								{Name: ":method", Value: "GET", MatchType: "exact"},
+							},
+							Clusters: clustersWeight(service(kuardService)),
+							RetryPolicy: &RetryPolicy{
+								RetryOn:              "reset",
+								NumRetries:           1,
+								PerTryTimeout:        timeout.DurationSetting(100 * time.Millisecond),
+								HedgeOnPerTryTimeout: true,
+							},
+						},
+						&Route{
+							PathMatchCondition: prefixString("/"),
+							HeaderMatchConditions: []HeaderMatchCondition{
+								{Name: ":method", Value: "POST", MatchType: "exact"},
+							},
+							Clusters: clustersWeight(service(kuardService)),
+						}),
+					),
+				},
+			),
+		},
+		"route with retry-budget annotations sets the retry budget of its clusters": {
+			gatewayclass: validClass,
+			gateway:      gatewayHTTPAllNamespaces,
+			objs: []interface{}{
+				kuardService,
+				&gatewayapi_v1beta1.HTTPRoute{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "basic",
+						Namespace: "projectcontour",
+						Annotations: map[string]string{
+							"projectcontour.io/hedge-per-try-timeout":              "100ms",
+							"projectcontour.io/retry-budget-percent":               "20",
+							"projectcontour.io/retry-budget-min-retry-concurrency": "5",
+						},
+					},
+					Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+						CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+							ParentRefs: []gatewayapi_v1beta1.ParentReference{gatewayapi.GatewayParentRef("projectcontour", "contour")},
+						},
+						Hostnames: []gatewayapi_v1beta1.Hostname{
+							"test.projectcontour.io",
+						},
+						Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+							Matches: []gatewayapi_v1beta1.HTTPRouteMatch{{
+								Path: &gatewayapi_v1beta1.HTTPPathMatch{
+									Type:  ref.To(gatewayapi_v1beta1.PathMatchPathPrefix),
+									Value: ref.To("/"),
+								},
+								Method: ref.To(gatewayapi_v1beta1.HTTPMethodGet),
+							}},
+							BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
+						}},
+					},
+				},
+			},
+			want: listeners(
+				&Listener{
+					Name: HTTP_LISTENER_NAME,
+					Port: 8080,
+					VirtualHosts: virtualhosts(virtualhost("test.projectcontour.io",
+						&Route{
+							PathMatchCondition: prefixString("/"),
+							HeaderMatchConditions: []HeaderMatchCondition{
+								{Name: ":method", Value: "GET", MatchType: "exact"},
+							},
+							Clusters: []*Cluster{{
+								Upstream: service(kuardService),
+								Weight:   1,
+								RetryBudget: &RetryBudget{
+									Percent:             20,
+									MinRetryConcurrency: 5,
+								},
+							}},
+							RetryPolicy: &RetryPolicy{
+								RetryOn:              "reset",
+								NumRetries:           1,
+								PerTryTimeout:        timeout.DurationSetting(100 * time.Millisecond),
+								HedgeOnPerTryTimeout: true,
+							},
+						}),
+					),
+				},
+			),
+		},
 		"insert single route with single query param match without type specified and path match": {
 			gatewayclass: validClass,
 			gateway:      gatewayHTTPAllNamespaces,
@@ -3518,7 +3727,6 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 									},
 								},
 							}},
-							BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
 						}},
 					},
 				},
@@ -3568,7 +3776,6 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 									},
 								},
 							}},
-							BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
 						}},
 					},
 				},
@@ -3618,7 +3825,6 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 									},
 								},
 							}},
-							BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
 						}},
 					},
 				},
@@ -3676,11 +3882,11 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 					Name: HTTP_LISTENER_NAME,
 					Port: 8080,
 					VirtualHosts: virtualhosts(virtualhost("test.projectcontour.io",
-						withMirror(prefixrouteHTTPRoute("/", service(kuardService)), service(kuardService2)))),
+						withMirror(prefixrouteHTTPRoute("/", service(kuardService)), service(kuardService2), 100))),
 				},
 			),
 		},
-		"HTTPRoute rule with request mirror filter with multiple matches": {
+		"HTTPRoute rule with request mirror filter and mirror percentage": {
 			gatewayclass: validClass,
 			gateway:      gatewayHTTPAllNamespaces,
 			objs: []interface{}{
@@ -3690,6 +3896,9 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      "basic",
 						Namespace: "projectcontour",
+						Annotations: map[string]string{
+							"projectcontour.io/request-mirror-percentage": "10",
+						},
 					},
 					Spec: gatewayapi_v1beta1.HTTPRouteSpec{
 						CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
@@ -3699,10 +3908,7 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 							"test.projectcontour.io",
 						},
 						Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
-							Matches: append(
-								gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
-								gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/another-match")...,
-							),
+							Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
 							BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
 							Filters: []gatewayapi_v1beta1.HTTPRouteFilter{{
 								Type: gatewayapi_v1beta1.HTTPRouteFilterRequestMirror,
@@ -3719,21 +3925,23 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 					Name: HTTP_LISTENER_NAME,
 					Port: 8080,
 					VirtualHosts: virtualhosts(virtualhost("test.projectcontour.io",
-						withMirror(prefixrouteHTTPRoute("/", service(kuardService)), service(kuardService2)),
-						withMirror(segmentPrefixHTTPRoute("/another-match", service(kuardService)), service(kuardService2)),
-					)),
+						withMirror(prefixrouteHTTPRoute("/", service(kuardService)), service(kuardService2), 10))),
 				},
 			),
 		},
-		"HTTPRoute rule with URLRewrite filter with ReplacePrefixMatch to another value": {
+		"HTTPRoute rule with request mirror filter and zero mirror percentage": {
 			gatewayclass: validClass,
 			gateway:      gatewayHTTPAllNamespaces,
 			objs: []interface{}{
 				kuardService,
+				kuardService2,
 				&gatewayapi_v1beta1.HTTPRoute{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      "basic",
 						Namespace: "projectcontour",
+						Annotations: map[string]string{
+							"projectcontour.io/request-mirror-percentage": "0",
+						},
 					},
 					Spec: gatewayapi_v1beta1.HTTPRouteSpec{
 						CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
@@ -3743,17 +3951,14 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 							"test.projectcontour.io",
 						},
 						Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
-							Matches: gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/prefix"),
+							Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
+							BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
 							Filters: []gatewayapi_v1beta1.HTTPRouteFilter{{
-								Type: gatewayapi_v1beta1.HTTPRouteFilterURLRewrite,
-								URLRewrite: &gatewayapi_v1beta1.HTTPURLRewriteFilter{
-									Path: &gatewayapi_v1beta1.HTTPPathModifier{
-										Type:               gatewayapi_v1beta1.PrefixMatchHTTPPathModifier,
-										ReplacePrefixMatch: ref.To("/replacement"),
-									},
+								Type: gatewayapi_v1beta1.HTTPRouteFilterRequestMirror,
+								RequestMirror: &gatewayapi_v1beta1.HTTPRequestMirrorFilter{
+									BackendRef: gatewayapi.ServiceBackendObjectRef("kuard2", 8080),
 								},
 							}},
-							BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
 						}},
 					},
 				},
@@ -3762,23 +3967,18 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 				&Listener{
 					Name: HTTP_LISTENER_NAME,
 					Port: 8080,
-					VirtualHosts: virtualhosts(virtualhost("test.projectcontour.io",
-						&Route{
-							PathMatchCondition: prefixSegment("/prefix"),
-							PathRewritePolicy: &PathRewritePolicy{
-								PrefixRewrite: "/replacement",
-							},
-							Clusters: clustersWeight(service(kuardService)),
-						},
-					)),
+					VirtualHosts: virtualhosts(
+						virtualhost("test.projectcontour.io", prefixrouteHTTPRoute("/", service(kuardService))),
+					),
 				},
 			),
 		},
-		"HTTPRoute rule with URLRewrite filter with ReplacePrefixMatch to \"/\"": {
+		"HTTPRoute rule with request mirror filter with multiple matches": {
 			gatewayclass: validClass,
 			gateway:      gatewayHTTPAllNamespaces,
 			objs: []interface{}{
 				kuardService,
+				kuardService2,
 				&gatewayapi_v1beta1.HTTPRoute{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      "basic",
@@ -3792,17 +3992,17 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 							"test.projectcontour.io",
 						},
 						Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
-							Matches: gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/prefix"),
+							Matches: append(
+								gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
+								gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/another-match")...,
+							),
+							BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
 							Filters: []gatewayapi_v1beta1.HTTPRouteFilter{{
-								Type: gatewayapi_v1beta1.HTTPRouteFilterURLRewrite,
-								URLRewrite: &gatewayapi_v1beta1.HTTPURLRewriteFilter{
-									Path: &gatewayapi_v1beta1.HTTPPathModifier{
-										Type:               gatewayapi_v1beta1.PrefixMatchHTTPPathModifier,
-										ReplacePrefixMatch: ref.To("/"),
-									},
+								Type: gatewayapi_v1beta1.HTTPRouteFilterRequestMirror,
+								RequestMirror: &gatewayapi_v1beta1.HTTPRequestMirrorFilter{
+									BackendRef: gatewayapi.ServiceBackendObjectRef("kuard2", 8080),
 								},
 							}},
-							BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
 						}},
 					},
 				},
@@ -3812,18 +4012,386 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 					Name: HTTP_LISTENER_NAME,
 					Port: 8080,
 					VirtualHosts: virtualhosts(virtualhost("test.projectcontour.io",
-						&Route{
-							PathMatchCondition: prefixSegment("/prefix"),
-							PathRewritePolicy: &PathRewritePolicy{
-								PrefixRegexRemove: "^/prefix/*",
+						withMirror(prefixrouteHTTPRoute("/", service(kuardService)), service(kuardService2), 100),
+						withMirror(segmentPrefixHTTPRoute("/another-match", service(kuardService)), service(kuardService2), 100),
+					)),
+				},
+			),
+		},
+		"HTTPRoute rule with URLRewrite filter with ReplacePrefixMatch to another value": {
+			gatewayclass: validClass,
+			gateway:      gatewayHTTPAllNamespaces,
+			objs: []interface{}{
+				kuardService,
+				&gatewayapi_v1beta1.HTTPRoute{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "basic",
+						Namespace: "projectcontour",
+					},
+					Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+						CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+							ParentRefs: []gatewayapi_v1beta1.ParentReference{gatewayapi.GatewayParentRef("projectcontour", "contour")},
+						},
+						Hostnames: []gatewayapi_v1beta1.Hostname{
+							"test.projectcontour.io",
+						},
+						Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+							Matches: gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/prefix"),
+							Filters: []gatewayapi_v1beta1.HTTPRouteFilter{{
+								Type: gatewayapi_v1beta1.HTTPRouteFilterURLRewrite,
+								URLRewrite: &gatewayapi_v1beta1.HTTPURLRewriteFilter{
+									Path: &gatewayapi_v1beta1.HTTPPathModifier{
+										Type:               gatewayapi_v1beta1.PrefixMatchHTTPPathModifier,
+										ReplacePrefixMatch: ref.To("/replacement"),
+									},
+								},
+							}},
+							BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
+						}},
+					},
+				},
+			},
+			want: listeners(
+				&Listener{
+					Name: HTTP_LISTENER_NAME,
+					Port: 8080,
+					VirtualHosts: virtualhosts(virtualhost("test.projectcontour.io",
+						&Route{
+							PathMatchCondition: prefixSegment("/prefix"),
+							PathRewritePolicy: &PathRewritePolicy{
+								PrefixRewrite: "/replacement",
+							},
+							Clusters: clustersWeight(service(kuardService)),
+						},
+					)),
+				},
+			),
+		},
+		"HTTPRoute rule with URLRewrite filter with ReplacePrefixMatch to \"/\"": {
+			gatewayclass: validClass,
+			gateway:      gatewayHTTPAllNamespaces,
+			objs: []interface{}{
+				kuardService,
+				&gatewayapi_v1beta1.HTTPRoute{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "basic",
+						Namespace: "projectcontour",
+					},
+					Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+						CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+							ParentRefs: []gatewayapi_v1beta1.ParentReference{gatewayapi.GatewayParentRef("projectcontour", "contour")},
+						},
+						Hostnames: []gatewayapi_v1beta1.Hostname{
+							"test.projectcontour.io",
+						},
+						Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+							Matches: gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/prefix"),
+							Filters: []gatewayapi_v1beta1.HTTPRouteFilter{{
+								Type: gatewayapi_v1beta1.HTTPRouteFilterURLRewrite,
+								URLRewrite: &gatewayapi_v1beta1.HTTPURLRewriteFilter{
+									Path: &gatewayapi_v1beta1.HTTPPathModifier{
+										Type:               gatewayapi_v1beta1.PrefixMatchHTTPPathModifier,
+										ReplacePrefixMatch: ref.To("/"),
+									},
+								},
+							}},
+							BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
+						}},
+					},
+				},
+			},
+			want: listeners(
+				&Listener{
+					Name: HTTP_LISTENER_NAME,
+					Port: 8080,
+					VirtualHosts: virtualhosts(virtualhost("test.projectcontour.io",
+						&Route{
+							PathMatchCondition: prefixSegment("/prefix"),
+							PathRewritePolicy: &PathRewritePolicy{
+								PrefixRegexRemove: "^/prefix/*",
+							},
+							Clusters: clustersWeight(service(kuardService)),
+						},
+					)),
+				},
+			),
+		},
+		"HTTPRoute rule with URLRewrite filter with ReplaceFullPath": {
+			gatewayclass: validClass,
+			gateway:      gatewayHTTPAllNamespaces,
+			objs: []interface{}{
+				kuardService,
+				&gatewayapi_v1beta1.HTTPRoute{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "basic",
+						Namespace: "projectcontour",
+					},
+					Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+						CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+							ParentRefs: []gatewayapi_v1beta1.ParentReference{gatewayapi.GatewayParentRef("projectcontour", "contour")},
+						},
+						Hostnames: []gatewayapi_v1beta1.Hostname{
+							"test.projectcontour.io",
+						},
+						Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+							Matches: gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/prefix"),
+							Filters: []gatewayapi_v1beta1.HTTPRouteFilter{{
+								Type: gatewayapi_v1beta1.HTTPRouteFilterURLRewrite,
+								URLRewrite: &gatewayapi_v1beta1.HTTPURLRewriteFilter{
+									Path: &gatewayapi_v1beta1.HTTPPathModifier{
+										Type:            gatewayapi_v1beta1.FullPathHTTPPathModifier,
+										ReplaceFullPath: ref.To("/replacement"),
+									},
+								},
+							}},
+							BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
+						}},
+					},
+				},
+			},
+			want: listeners(
+				&Listener{
+					Name: HTTP_LISTENER_NAME,
+					Port: 8080,
+					VirtualHosts: virtualhosts(virtualhost("test.projectcontour.io",
+						&Route{
+							PathMatchCondition: prefixSegment("/prefix"),
+							PathRewritePolicy: &PathRewritePolicy{
+								FullPathRewrite: "/replacement",
+							},
+							Clusters: clustersWeight(service(kuardService)),
+						},
+					)),
+				},
+			),
+		},
+		"HTTPRoute rule with URLRewrite filter with Hostname rewrite": {
+			gatewayclass: validClass,
+			gateway:      gatewayHTTPAllNamespaces,
+			objs: []interface{}{
+				kuardService,
+				&gatewayapi_v1beta1.HTTPRoute{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "basic",
+						Namespace: "projectcontour",
+					},
+					Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+						CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+							ParentRefs: []gatewayapi_v1beta1.ParentReference{gatewayapi.GatewayParentRef("projectcontour", "contour")},
+						},
+						Hostnames: []gatewayapi_v1beta1.Hostname{
+							"test.projectcontour.io",
+						},
+						Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+							Matches: gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/prefix"),
+							Filters: []gatewayapi_v1beta1.HTTPRouteFilter{{
+								Type: gatewayapi_v1beta1.HTTPRouteFilterURLRewrite,
+								URLRewrite: &gatewayapi_v1beta1.HTTPURLRewriteFilter{
+									Hostname: ref.To(gatewayapi_v1beta1.PreciseHostname("rewritten.com")),
+								},
+							}},
+							BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
+						}},
+					},
+				},
+			},
+			want: listeners(
+				&Listener{
+					Name: HTTP_LISTENER_NAME,
+					Port: 8080,
+					VirtualHosts: virtualhosts(virtualhost("test.projectcontour.io",
+						&Route{
+							PathMatchCondition:   prefixSegment("/prefix"),
+							RequestHeadersPolicy: &HeadersPolicy{HostRewrite: "rewritten.com"},
+							Clusters:             clustersWeight(service(kuardService)),
+						},
+					)),
+				},
+			),
+		},
+		"HTTPRoute rule with RequestHeadersModifier and URLRewrite filter with Hostname rewrite": {
+			gatewayclass: validClass,
+			gateway:      gatewayHTTPAllNamespaces,
+			objs: []interface{}{
+				kuardService,
+				&gatewayapi_v1beta1.HTTPRoute{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "basic",
+						Namespace: "projectcontour",
+					},
+					Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+						CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+							ParentRefs: []gatewayapi_v1beta1.ParentReference{gatewayapi.GatewayParentRef("projectcontour", "contour")},
+						},
+						Hostnames: []gatewayapi_v1beta1.Hostname{
+							"test.projectcontour.io",
+						},
+						Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+							Matches: gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/prefix"),
+							Filters: []gatewayapi_v1beta1.HTTPRouteFilter{
+								{
+									Type: gatewayapi_v1beta1.HTTPRouteFilterRequestHeaderModifier,
+									RequestHeaderModifier: &gatewayapi_v1beta1.HTTPHeaderFilter{
+										Set: []gatewayapi_v1beta1.HTTPHeader{
+											{
+												Name:  "Host",
+												Value: "requestheader.rewritten.com",
+											},
+										},
+									},
+								},
+								{
+									Type: gatewayapi_v1beta1.HTTPRouteFilterURLRewrite,
+									URLRewrite: &gatewayapi_v1beta1.HTTPURLRewriteFilter{
+										Hostname: ref.To(gatewayapi_v1beta1.PreciseHostname("url.rewritten.com")),
+									},
+								},
+							},
+							BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
+						}},
+					},
+				},
+			},
+			want: listeners(
+				&Listener{
+					Name: HTTP_LISTENER_NAME,
+					Port: 8080,
+					VirtualHosts: virtualhosts(virtualhost("test.projectcontour.io",
+						&Route{
+							PathMatchCondition: prefixSegment("/prefix"),
+							RequestHeadersPolicy: &HeadersPolicy{
+								Add:         map[string]string{},
+								HostRewrite: "url.rewritten.com",
+							},
+							Clusters: clustersWeight(service(kuardService)),
+						},
+					)),
+				},
+			),
+		},
+		"HTTPRoute rule with URLRewrite filter with Hostname rewrite and a later RequestHeadersModifier": {
+			gatewayclass: validClass,
+			gateway:      gatewayHTTPAllNamespaces,
+			objs: []interface{}{
+				kuardService,
+				&gatewayapi_v1beta1.HTTPRoute{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "basic",
+						Namespace: "projectcontour",
+					},
+					Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+						CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+							ParentRefs: []gatewayapi_v1beta1.ParentReference{gatewayapi.GatewayParentRef("projectcontour", "contour")},
+						},
+						Hostnames: []gatewayapi_v1beta1.Hostname{
+							"test.projectcontour.io",
+						},
+						Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+							Matches: gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/prefix"),
+							Filters: []gatewayapi_v1beta1.HTTPRouteFilter{
+								{
+									Type: gatewayapi_v1beta1.HTTPRouteFilterURLRewrite,
+									URLRewrite: &gatewayapi_v1beta1.HTTPURLRewriteFilter{
+										Hostname: ref.To(gatewayapi_v1beta1.PreciseHostname("url.rewritten.com")),
+									},
+								},
+								{
+									Type: gatewayapi_v1beta1.HTTPRouteFilterRequestHeaderModifier,
+									RequestHeaderModifier: &gatewayapi_v1beta1.HTTPHeaderFilter{
+										Set: []gatewayapi_v1beta1.HTTPHeader{
+											{
+												Name:  "Host",
+												Value: "requestheader.rewritten.com",
+											},
+										},
+									},
+								},
+							},
+							BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
+						}},
+					},
+				},
+			},
+			want: listeners(
+				&Listener{
+					Name: HTTP_LISTENER_NAME,
+					Port: 8080,
+					VirtualHosts: virtualhosts(virtualhost("test.projectcontour.io",
+						&Route{
+							PathMatchCondition: prefixSegment("/prefix"),
+							RequestHeadersPolicy: &HeadersPolicy{
+								Add:         map[string]string{},
+								HostRewrite: "requestheader.rewritten.com",
+							},
+							Clusters: clustersWeight(service(kuardService)),
+						},
+					)),
+				},
+			),
+		},
+		"HTTPRoute rule with RequestHeadersModifier setting Host before a RequestRedirect filter": {
+			gatewayclass: validClass,
+			gateway:      gatewayHTTPAllNamespaces,
+			objs: []interface{}{
+				kuardService,
+				&gatewayapi_v1beta1.HTTPRoute{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "basic",
+						Namespace: "projectcontour",
+					},
+					Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+						CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+							ParentRefs: []gatewayapi_v1beta1.ParentReference{gatewayapi.GatewayParentRef("projectcontour", "contour")},
+						},
+						Hostnames: []gatewayapi_v1beta1.Hostname{
+							"test.projectcontour.io",
+						},
+						Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+							Matches: gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/prefix"),
+							Filters: []gatewayapi_v1beta1.HTTPRouteFilter{
+								{
+									Type: gatewayapi_v1beta1.HTTPRouteFilterRequestHeaderModifier,
+									RequestHeaderModifier: &gatewayapi_v1beta1.HTTPHeaderFilter{
+										Set: []gatewayapi_v1beta1.HTTPHeader{
+											{
+												Name:  "Host",
+												Value: "requestheader.rewritten.com",
+											},
+										},
+									},
+								},
+								{
+									Type: gatewayapi_v1beta1.HTTPRouteFilterRequestRedirect,
+									RequestRedirect: &gatewayapi_v1beta1.HTTPRequestRedirectFilter{
+										StatusCode: ref.To(302),
+									},
+								},
+							},
+						}},
+					},
+				},
+			},
+			want: listeners(
+				&Listener{
+					Name: HTTP_LISTENER_NAME,
+					Port: 8080,
+					VirtualHosts: virtualhosts(virtualhost("test.projectcontour.io",
+						&Route{
+							PathMatchCondition: prefixSegment("/prefix"),
+							RequestHeadersPolicy: &HeadersPolicy{
+								Add:         map[string]string{},
+								HostRewrite: "requestheader.rewritten.com",
+							},
+							Redirect: &Redirect{
+								Hostname:   "requestheader.rewritten.com",
+								StatusCode: 302,
 							},
-							Clusters: clustersWeight(service(kuardService)),
 						},
 					)),
 				},
 			),
 		},
-		"HTTPRoute rule with URLRewrite filter with ReplaceFullPath": {
+		"HTTPRoute rule with RequestRedirect filter before a RequestHeadersModifier setting Host": {
 			gatewayclass: validClass,
 			gateway:      gatewayHTTPAllNamespaces,
 			objs: []interface{}{
@@ -3842,16 +4410,25 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 						},
 						Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
 							Matches: gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/prefix"),
-							Filters: []gatewayapi_v1beta1.HTTPRouteFilter{{
-								Type: gatewayapi_v1beta1.HTTPRouteFilterURLRewrite,
-								URLRewrite: &gatewayapi_v1beta1.HTTPURLRewriteFilter{
-									Path: &gatewayapi_v1beta1.HTTPPathModifier{
-										Type:            gatewayapi_v1beta1.FullPathHTTPPathModifier,
-										ReplaceFullPath: ref.To("/replacement"),
+							Filters: []gatewayapi_v1beta1.HTTPRouteFilter{
+								{
+									Type: gatewayapi_v1beta1.HTTPRouteFilterRequestRedirect,
+									RequestRedirect: &gatewayapi_v1beta1.HTTPRequestRedirectFilter{
+										StatusCode: ref.To(302),
 									},
 								},
-							}},
-							BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
+								{
+									Type: gatewayapi_v1beta1.HTTPRouteFilterRequestHeaderModifier,
+									RequestHeaderModifier: &gatewayapi_v1beta1.HTTPHeaderFilter{
+										Set: []gatewayapi_v1beta1.HTTPHeader{
+											{
+												Name:  "Host",
+												Value: "requestheader.rewritten.com",
+											},
+										},
+									},
+								},
+							},
 						}},
 					},
 				},
@@ -3863,16 +4440,19 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 					VirtualHosts: virtualhosts(virtualhost("test.projectcontour.io",
 						&Route{
 							PathMatchCondition: prefixSegment("/prefix"),
-							PathRewritePolicy: &PathRewritePolicy{
-								FullPathRewrite: "/replacement",
+							RequestHeadersPolicy: &HeadersPolicy{
+								Add:         map[string]string{},
+								HostRewrite: "requestheader.rewritten.com",
+							},
+							Redirect: &Redirect{
+								StatusCode: 302,
 							},
-							Clusters: clustersWeight(service(kuardService)),
 						},
 					)),
 				},
 			),
 		},
-		"HTTPRoute rule with URLRewrite filter with Hostname rewrite": {
+		"HTTPRoute rule with RequestRedirect filter and backendRefs": {
 			gatewayclass: validClass,
 			gateway:      gatewayHTTPAllNamespaces,
 			objs: []interface{}{
@@ -3892,9 +4472,9 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 						Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
 							Matches: gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/prefix"),
 							Filters: []gatewayapi_v1beta1.HTTPRouteFilter{{
-								Type: gatewayapi_v1beta1.HTTPRouteFilterURLRewrite,
-								URLRewrite: &gatewayapi_v1beta1.HTTPURLRewriteFilter{
-									Hostname: ref.To(gatewayapi_v1beta1.PreciseHostname("rewritten.com")),
+								Type: gatewayapi_v1beta1.HTTPRouteFilterRequestRedirect,
+								RequestRedirect: &gatewayapi_v1beta1.HTTPRequestRedirectFilter{
+									StatusCode: ref.To(302),
 								},
 							}},
 							BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
@@ -3902,21 +4482,9 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 					},
 				},
 			},
-			want: listeners(
-				&Listener{
-					Name: HTTP_LISTENER_NAME,
-					Port: 8080,
-					VirtualHosts: virtualhosts(virtualhost("test.projectcontour.io",
-						&Route{
-							PathMatchCondition:   prefixSegment("/prefix"),
-							RequestHeadersPolicy: &HeadersPolicy{HostRewrite: "rewritten.com"},
-							Clusters:             clustersWeight(service(kuardService)),
-						},
-					)),
-				},
-			),
+			want: listeners(),
 		},
-		"HTTPRoute rule with RequestHeadersModifier and URLRewrite filter with Hostname rewrite": {
+		"HTTPRoute rule with RequestRedirect and URLRewrite filters": {
 			gatewayclass: validClass,
 			gateway:      gatewayHTTPAllNamespaces,
 			objs: []interface{}{
@@ -3936,45 +4504,24 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 						Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
 							Matches: gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/prefix"),
 							Filters: []gatewayapi_v1beta1.HTTPRouteFilter{
-								{
-									Type: gatewayapi_v1beta1.HTTPRouteFilterRequestHeaderModifier,
-									RequestHeaderModifier: &gatewayapi_v1beta1.HTTPHeaderFilter{
-										Set: []gatewayapi_v1beta1.HTTPHeader{
-											{
-												Name:  "Host",
-												Value: "requestheader.rewritten.com",
-											},
-										},
-									},
-								},
 								{
 									Type: gatewayapi_v1beta1.HTTPRouteFilterURLRewrite,
 									URLRewrite: &gatewayapi_v1beta1.HTTPURLRewriteFilter{
 										Hostname: ref.To(gatewayapi_v1beta1.PreciseHostname("url.rewritten.com")),
 									},
 								},
+								{
+									Type: gatewayapi_v1beta1.HTTPRouteFilterRequestRedirect,
+									RequestRedirect: &gatewayapi_v1beta1.HTTPRequestRedirectFilter{
+										StatusCode: ref.To(302),
+									},
+								},
 							},
-							BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
 						}},
 					},
 				},
 			},
-			want: listeners(
-				&Listener{
-					Name: HTTP_LISTENER_NAME,
-					Port: 8080,
-					VirtualHosts: virtualhosts(virtualhost("test.projectcontour.io",
-						&Route{
-							PathMatchCondition: prefixSegment("/prefix"),
-							RequestHeadersPolicy: &HeadersPolicy{
-								Add:         map[string]string{},
-								HostRewrite: "url.rewritten.com",
-							},
-							Clusters: clustersWeight(service(kuardService)),
-						},
-					)),
-				},
-			),
+			want: listeners(),
 		},
 		// END
 
@@ -5081,7 +5628,8 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 								Name:   "test.projectcontour.io",
 								Routes: routes(exactrouteGRPCRoute("/io.projectcontour/Login", grpcService(blogService, "h2"))),
 							},
-							Secret: secret(sec1),
+							Secret:              secret(sec1),
+							FallbackCertificate: secret(sec1),
 						},
 					),
 				},
@@ -5527,7 +6075,7 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 					Name: HTTP_LISTENER_NAME,
 					Port: 8080,
 					VirtualHosts: virtualhosts(virtualhost("test.projectcontour.io",
-						withMirror(exactrouteGRPCRoute("/io.projectcontour/Login", grpcService(kuardService, "h2c")), grpcService(kuardService2, "h2c")))),
+						withMirror(exactrouteGRPCRoute("/io.projectcontour/Login", grpcService(kuardService, "h2c")), grpcService(kuardService2, "h2c"), 0))),
 				},
 			),
 		},
@@ -5625,6 +6173,240 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 	}
 }
 
+func TestDAGInsertGatewayAPIDefaultBackend(t *testing.T) {
+	kuardService := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "projectcontour",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Name:       "http",
+				Protocol:   "TCP",
+				Port:       8080,
+				TargetPort: intstr.FromInt(8080),
+			}},
+		},
+	}
+
+	defaultBackendService := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "default-backend",
+			Namespace: "projectcontour",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Name:       "http",
+				Protocol:   "TCP",
+				Port:       80,
+				TargetPort: intstr.FromInt(8080),
+			}},
+		},
+	}
+
+	gatewayclass := &gatewayapi_v1beta1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-validClass",
+		},
+		Spec: gatewayapi_v1beta1.GatewayClassSpec{
+			ControllerName: "projectcontour.io/contour",
+		},
+		Status: gatewayapi_v1beta1.GatewayClassStatus{
+			Conditions: []metav1.Condition{{
+				Type:   string(gatewayapi_v1beta1.GatewayClassConditionStatusAccepted),
+				Status: metav1.ConditionTrue,
+			}},
+		},
+	}
+
+	gateway := &gatewayapi_v1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "contour",
+			Namespace: "projectcontour",
+		},
+		Spec: gatewayapi_v1beta1.GatewaySpec{
+			GatewayClassName: gatewayapi_v1beta1.ObjectName(gatewayclass.Name),
+			Listeners: []gatewayapi_v1beta1.Listener{{
+				Name:     "http",
+				Port:     80,
+				Protocol: gatewayapi_v1beta1.HTTPProtocolType,
+				AllowedRoutes: &gatewayapi_v1beta1.AllowedRoutes{
+					Namespaces: &gatewayapi_v1beta1.RouteNamespaces{
+						From: ref.To(gatewayapi_v1beta1.NamespacesFromAll),
+					},
+				},
+			}},
+		},
+	}
+
+	httpRoute := func(hostnames ...gatewayapi_v1beta1.Hostname) *gatewayapi_v1beta1.HTTPRoute {
+		return &gatewayapi_v1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "basic",
+				Namespace: "projectcontour",
+			},
+			Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+					ParentRefs: []gatewayapi_v1beta1.ParentReference{gatewayapi.GatewayParentRef("projectcontour", "contour")},
+				},
+				Hostnames: hostnames,
+				Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+					Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
+					BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
+				}},
+			},
+		}
+	}
+
+	redirectRoute := httpRoute()
+	redirectRoute.Spec.Rules = []gatewayapi_v1beta1.HTTPRouteRule{{
+		Matches: gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
+		Filters: []gatewayapi_v1beta1.HTTPRouteFilter{{
+			Type: gatewayapi_v1beta1.HTTPRouteFilterRequestRedirect,
+			RequestRedirect: &gatewayapi_v1beta1.HTTPRequestRedirectFilter{
+				Scheme:     ref.To("https"),
+				StatusCode: ref.To(301),
+			},
+		}},
+	}}
+
+	defaultBackend := &GatewayDefaultBackend{
+		Service: types.NamespacedName{Namespace: "projectcontour", Name: "default-backend"},
+		Port:    80,
+	}
+
+	tests := map[string]struct {
+		gateway        *gatewayapi_v1beta1.Gateway
+		defaultBackend *GatewayDefaultBackend
+		objs           []interface{}
+		want           []*Listener
+	}{
+		"default backend serves all hostnames without routes": {
+			gateway:        gateway,
+			defaultBackend: defaultBackend,
+			objs: []interface{}{
+				defaultBackendService,
+			},
+			want: listeners(
+				&Listener{
+					Name: HTTP_LISTENER_NAME,
+					Port: 8080,
+					VirtualHosts: virtualhosts(
+						virtualhost("*", prefixrouteHTTPRoute("/", service(defaultBackendService))),
+					),
+				},
+			),
+		},
+		"routes of hostnames take precedence over the default backend": {
+			gateway:        gateway,
+			defaultBackend: defaultBackend,
+			objs: []interface{}{
+				kuardService,
+				defaultBackendService,
+				httpRoute("test.projectcontour.io"),
+			},
+			want: listeners(
+				&Listener{
+					Name: HTTP_LISTENER_NAME,
+					Port: 8080,
+					VirtualHosts: virtualhosts(
+						virtualhost("*", prefixrouteHTTPRoute("/", service(defaultBackendService))),
+						virtualhost("test.projectcontour.io", prefixrouteHTTPRoute("/", service(kuardService))),
+					),
+				},
+			),
+		},
+		"route matching all paths of all hostnames is not replaced": {
+			gateway:        gateway,
+			defaultBackend: defaultBackend,
+			objs: []interface{}{
+				kuardService,
+				defaultBackendService,
+				httpRoute(),
+			},
+			want: listeners(
+				&Listener{
+					Name: HTTP_LISTENER_NAME,
+					Port: 8080,
+					VirtualHosts: virtualhosts(
+						virtualhost("*", prefixrouteHTTPRoute("/", service(kuardService))),
+					),
+				},
+			),
+		},
+		"HTTP to HTTPS redirect of all hostnames is not replaced": {
+			gateway:        gateway,
+			defaultBackend: defaultBackend,
+			objs: []interface{}{
+				defaultBackendService,
+				redirectRoute,
+			},
+			want: listeners(
+				&Listener{
+					Name: HTTP_LISTENER_NAME,
+					Port: 8080,
+					VirtualHosts: virtualhosts(
+						virtualhost("*", &Route{
+							PathMatchCondition: prefixString("/"),
+							Redirect: &Redirect{
+								Scheme:     "https",
+								StatusCode: 301,
+							},
+						}),
+					),
+				},
+			),
+		},
+		"missing default backend Service": {
+			gateway:        gateway,
+			defaultBackend: defaultBackend,
+			want:           listeners(),
+		},
+		"no default backend": {
+			gateway: gateway,
+			objs: []interface{}{
+				defaultBackendService,
+			},
+			want: listeners(),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := Builder{
+				Source: KubernetesCache{
+					gatewayclass: gatewayclass,
+					gateway:      tc.gateway,
+					FieldLogger:  fixture.NewTestLogger(t),
+				},
+				Processors: []Processor{
+					&ListenerProcessor{},
+					&GatewayAPIProcessor{
+						FieldLogger:    fixture.NewTestLogger(t),
+						DefaultBackend: tc.defaultBackend,
+					},
+				},
+			}
+
+			for _, o := range tc.objs {
+				builder.Source.Insert(o)
+			}
+			dag := builder.Build()
+
+			got := make(map[int]*Listener)
+			for _, l := range dag.Listeners {
+				got[l.Port] = l
+			}
+
+			want := make(map[int]*Listener)
+			for _, v := range tc.want {
+				want[v.Port] = v
+			}
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
 func TestDAGInsert(t *testing.T) {
 	// The DAG is insensitive to ordering, adding an ingress, then a service,
 	// should have the same result as adding a service, then an ingress.
@@ -10785,7 +11567,7 @@ func TestDAGInsert(t *testing.T) {
 					Port: 8080,
 					VirtualHosts: virtualhosts(
 						virtualhost("example.com",
-							withMirror(prefixroute("/", service(s1)), service(s2)),
+							withMirror(prefixroute("/", service(s1)), service(s2), 0),
 						),
 					),
 				},
@@ -14831,11 +15613,18 @@ func prefixSegment(prefix string) MatchCondition {
 func exact(path string) MatchCondition  { return &ExactMatchCondition{Path: path} }
 func regex(regex string) MatchCondition { return &RegexMatchCondition{Regex: regex} }
 
-func withMirror(r *Route, mirror *Service) *Route {
+func withMaxRequestBodyBytes(r *Route, max uint32) *Route {
+	r.MaxRequestBodyBytes = max
+	return r
+}
+
+func withMirror(r *Route, mirror *Service, percentage uint32) *Route {
 	r.MirrorPolicy = &MirrorPolicy{
 		Cluster: &Cluster{
 			Upstream: mirror,
+			Protocol: mirror.Protocol,
 		},
+		Percentage: percentage,
 	}
 	return r
 }
@@ -16,6 +16,7 @@ package dag
 import (
 	"errors"
 	"fmt"
+	"net"
 	"regexp"
 	"strings"
 
@@ -91,6 +92,26 @@ func mergeQueryParamMatchConditions(conds []contour_api_v1.MatchCondition) []Que
 	return queryParameterMatchConditions(queryParameterConditions)
 }
 
+func mergeSourceFilters(conds []contour_api_v1.MatchCondition) []SourceFilter {
+	var sc []SourceFilter
+	for _, cond := range conds {
+		if cond.Source == nil {
+			continue
+		}
+
+		// sourceFiltersValid guarantees that every
+		// CIDR parses.
+		var cidrs []*net.IPNet
+		for _, cidr := range cond.Source.CIDRs {
+			_, ipNet, _ := net.ParseCIDR(cidr)
+			cidrs = append(cidrs, ipNet)
+		}
+		sc = append(sc, SourceFilter{CIDRs: cidrs})
+	}
+
+	return sc
+}
+
 func headerMatchConditions(conditions []contour_api_v1.HeaderMatchCondition) []HeaderMatchCondition {
 	var hc []HeaderMatchCondition
 
@@ -272,6 +293,29 @@ func headerMatchConditionsValid(conditions []contour_api_v1.MatchCondition) erro
 	return nil
 }
 
+// sourceFiltersValid validates that the source filters within a
+// slice of MatchConditions list at least one address range and that every
+// range is in valid CIDR notation.
+func sourceFiltersValid(conditions []contour_api_v1.MatchCondition) error {
+	for _, v := range conditions {
+		if v.Source == nil {
+			continue
+		}
+
+		if len(v.Source.CIDRs) == 0 {
+			return errors.New("must specify at least one CIDR in a source condition")
+		}
+
+		for _, cidr := range v.Source.CIDRs {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				return fmt.Errorf("invalid source CIDR %q", cidr)
+			}
+		}
+	}
+
+	return nil
+}
+
 // queryParameterMatchConditionsValid validates that the query parameter conditions within a
 // slice of MatchConditions are valid. Specifically, it returns an error for
 // any of the following scenarios:
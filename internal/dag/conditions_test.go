@@ -679,3 +679,69 @@ func TestValidateQueryParameterMatchConditions(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateSourceFilters(t *testing.T) {
+	tests := map[string]struct {
+		matchconditions []contour_api_v1.MatchCondition
+		wantErr         bool
+	}{
+		"empty condition list": {
+			matchconditions: nil,
+			wantErr:         false,
+		},
+		"ipv4 and ipv6 ranges": {
+			matchconditions: []contour_api_v1.MatchCondition{
+				{
+					Prefix: "/internal",
+				}, {
+					Source: &contour_api_v1.SourceFilter{
+						CIDRs: []string{"10.0.0.0/8", "2001:db8::/32"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		"no ranges is invalid": {
+			matchconditions: []contour_api_v1.MatchCondition{
+				{
+					Source: &contour_api_v1.SourceFilter{},
+				},
+			},
+			wantErr: true,
+		},
+		"bare address is invalid": {
+			matchconditions: []contour_api_v1.MatchCondition{
+				{
+					Source: &contour_api_v1.SourceFilter{
+						CIDRs: []string{"10.0.0.1"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		"prefix length out of range is invalid": {
+			matchconditions: []contour_api_v1.MatchCondition{
+				{
+					Source: &contour_api_v1.SourceFilter{
+						CIDRs: []string{"10.0.0.0/8", "192.168.0.0/33"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			gotErr := sourceFiltersValid(tc.matchconditions)
+
+			if !tc.wantErr {
+				assert.NoError(t, gotErr)
+			}
+
+			if tc.wantErr {
+				assert.Error(t, gotErr)
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ package dag
 import (
 	"errors"
 	"fmt"
+	"net"
 	"regexp"
 	"strconv"
 	"strings"
@@ -25,6 +26,7 @@ import (
 
 	"github.com/projectcontour/contour/internal/status"
 	"github.com/projectcontour/contour/internal/timeout"
+	"github.com/projectcontour/contour/internal/xds"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -199,6 +201,23 @@ func (qc *QueryParamMatchCondition) String() string {
 	return "queryparam: " + details
 }
 
+// SourceFilter allows the requests of a route from client
+// addresses within any of a set of address ranges. Unlike the
+// match conditions, it does not select the route, so requests
+// the route matches from other addresses are rejected.
+type SourceFilter struct {
+	CIDRs []*net.IPNet
+}
+
+func (sc *SourceFilter) String() string {
+	cidrs := make([]string, 0, len(sc.CIDRs))
+	for _, cidr := range sc.CIDRs {
+		cidrs = append(cidrs, cidr.String())
+	}
+
+	return "source: " + strings.Join(cidrs, "&")
+}
+
 // DirectResponse allows for a specific HTTP status code and body
 // to be the response to a route request vs routing to
 // an envoy cluster.
@@ -283,6 +302,11 @@ type Route struct {
 	// match on the querystring parameters.
 	QueryParamMatchConditions []QueryParamMatchCondition
 
+	// SourceFilters specifies a set of filters on the client
+	// address. All of the filters must allow a request that the
+	// route matches, or it is rejected.
+	SourceFilters []SourceFilter
+
 	// Priority specifies the relative priority of the Route when compared to other
 	// Routes that may have equivalent match conditions. A lower value here means the
 	// Route has a higher priority.
@@ -324,6 +348,10 @@ type Route struct {
 	// ResponseHeadersPolicy defines how headers are managed during forwarding
 	ResponseHeadersPolicy *HeadersPolicy
 
+	// ContentSecurityPolicy is the value of the Content-Security-Policy
+	// header to return on responses from this route, if not empty.
+	ContentSecurityPolicy string
+
 	// CookieRewritePolicies is a list of policies that define how HTTP Set-Cookie
 	// headers should be rewritten for responses on this route.
 	CookieRewritePolicies []CookieRewritePolicy
@@ -351,6 +379,11 @@ type Route struct {
 	// InternalRedirectPolicy defines if envoy should handle redirect
 	// response internally instead of sending it downstream.
 	InternalRedirectPolicy *InternalRedirectPolicy
+
+	// MaxRequestBodyBytes is the maximum size of a request body
+	// accepted by this route. Requests with larger bodies are
+	// rejected with a 413. Zero means no limit.
+	MaxRequestBodyBytes uint32
 }
 
 // HasPathPrefix returns whether this route has a PrefixPathCondition.
@@ -401,6 +434,23 @@ type RetryPolicy struct {
 	// PerTryTimeout specifies the timeout per retry attempt.
 	// Ignored if RetryOn is blank.
 	PerTryTimeout timeout.Setting
+
+	// HedgeOnPerTryTimeout sends a retry when an attempt reaches
+	// PerTryTimeout, without cancelling the attempt. The first
+	// response of either is used.
+	HedgeOnPerTryTimeout bool
+}
+
+// RetryBudget limits the concurrent retries to a cluster
+// to a percentage of its active requests.
+type RetryBudget struct {
+	// Percent is the percentage of active requests
+	// that may be retries.
+	Percent uint32
+
+	// MinRetryConcurrency is the number of concurrent retries
+	// allowed regardless of the budget. Zero uses the Envoy default.
+	MinRetryConcurrency uint32
 }
 
 // PathRewritePolicy defines a policy for rewriting the path of
@@ -421,6 +471,10 @@ type PathRewritePolicy struct {
 // MirrorPolicy defines the mirroring policy for a route.
 type MirrorPolicy struct {
 	Cluster *Cluster
+
+	// Percentage is the percentage of requests that are
+	// mirrored. If zero, every request is mirrored.
+	Percentage uint32
 }
 
 // HeadersPolicy defines how headers are managed during forwarding
@@ -592,6 +646,17 @@ type CORSPolicy struct {
 	AllowPrivateNetwork bool
 }
 
+// HSTSPolicy defines the Strict-Transport-Security header returned
+// on responses from a secure virtual host.
+type HSTSPolicy struct {
+	// MaxAge is how long clients should remember to only use HTTPS.
+	MaxAge time.Duration
+	// IncludeSubDomains applies the policy to all subdomains.
+	IncludeSubDomains bool
+	// Preload signals consent to browser HSTS preload lists.
+	Preload bool
+}
+
 type HeaderValue struct {
 	// Name represents a key of a header
 	Key string
@@ -678,6 +743,18 @@ type VirtualHost struct {
 	// are rate limited.
 	RateLimitPolicy *RateLimitPolicy
 
+	// RequireTLS indicates that plaintext requests for the virtual
+	// host must be rejected rather than routed or redirected.
+	RequireTLS bool
+
+	// HSTSPolicy is the HTTP Strict Transport Security policy to
+	// advertise. It is only honored for secure virtual hosts.
+	HSTSPolicy *HSTSPolicy
+
+	// UpstreamClusterHeader is the name of the response header set
+	// to the name of the upstream cluster that served the request.
+	UpstreamClusterHeader string
+
 	Routes map[string]*Route
 }
 
@@ -696,6 +773,9 @@ func conditionsToString(r *Route) string {
 	for _, cond := range r.QueryParamMatchConditions {
 		s = append(s, cond.String())
 	}
+	for _, cond := range r.SourceFilters {
+		s = append(s, cond.String())
+	}
 	return strings.Join(s, ",")
 }
 
@@ -723,6 +803,13 @@ type SecureVirtualHost struct {
 	// DownstreamValidation defines how to verify the client's certificate.
 	DownstreamValidation *PeerValidationContext
 
+	// OCSPStaplePolicy controls how the OCSP response stapled to the
+	// certificate is used. Defaults to lenient stapling.
+	OCSPStaplePolicy string
+
+	// DisableSessionTickets disables stateless TLS session resumption.
+	DisableSessionTickets bool
+
 	// ExternalAuthorization contains the configuration for enabling
 	// the ExtAuthz filter.
 	ExternalAuthorization *ExternalAuthorization
@@ -880,6 +967,11 @@ type Service struct {
 	// Envoy will allow to the upstream cluster.
 	MaxRetries uint32
 
+	// MaxRequestsPerConnection is the maximum number of requests
+	// Envoy sends over a single upstream connection before closing
+	// it. Zero means unlimited.
+	MaxRequestsPerConnection uint32
+
 	// ExternalName is an optional field referencing a dns entry for Service type "ExternalName"
 	ExternalName string
 }
@@ -948,6 +1040,49 @@ type Cluster struct {
 	TimeoutPolicy ClusterTimeoutPolicy
 
 	SlowStartConfig *SlowStartConfig
+
+	// IgnoreNewHostsUntilFirstHC excludes new hosts from load balancing
+	// until they have passed their first health check.
+	IgnoreNewHostsUntilFirstHC bool
+
+	// ConnectionPoolKey, if set, separates this cluster from others
+	// for the same upstream so that it gets its own connection pool.
+	// It identifies the route the cluster was isolated for.
+	ConnectionPoolKey string
+
+	// Failover are the services whose endpoints are added to this
+	// cluster at lower priority levels, to take over the traffic
+	// when the upstream's endpoints are unhealthy.
+	Failover []WeightedService
+
+	// AltStatName, if set, overrides the name the statistics
+	// of this cluster are reported under.
+	AltStatName string
+
+	// RetryBudget, if set, limits the concurrent retries to this
+	// cluster in place of the upstream's max-retries threshold.
+	RetryBudget *RetryBudget
+}
+
+// LoadAssignmentName returns the name of the ClusterLoadAssignment
+// that supplies the endpoints of this Cluster.
+func (c *Cluster) LoadAssignmentName() string {
+	name := xds.ClusterLoadAssignmentName(
+		types.NamespacedName{
+			Name:      c.Upstream.Weighted.ServiceName,
+			Namespace: c.Upstream.Weighted.ServiceNamespace,
+		},
+		c.Upstream.Weighted.ServicePort.Name)
+
+	// A cluster with failover services needs its own assignment
+	// since its endpoints are not those of the upstream alone.
+	for _, f := range c.Failover {
+		name += fmt.Sprintf(",%s/%d", xds.ClusterLoadAssignmentName(
+			types.NamespacedName{Name: f.ServiceName, Namespace: f.ServiceNamespace},
+			f.ServicePort.Name), f.Priority)
+	}
+
+	return name
 }
 
 // WeightedService represents the load balancing weight of a
@@ -963,6 +1098,9 @@ type WeightedService struct {
 	ServicePort v1.ServicePort
 	// HealthPort is the port for healthcheck.
 	HealthPort v1.ServicePort
+	// Priority is the failover priority level of the service's
+	// endpoints, where 0 is the highest priority.
+	Priority uint32
 }
 
 // ServiceCluster capture the set of Kubernetes Services that will
@@ -1078,6 +1216,11 @@ func (s *Secret) PrivateKey() []byte {
 	return s.Object.Data[v1.TLSPrivateKeyKey]
 }
 
+// OCSPStaple returns the secret's DER encoded OCSP response, if any.
+func (s *Secret) OCSPStaple() []byte {
+	return s.Object.Data[OCSPStapleKey]
+}
+
 type SecretValidationStatus struct {
 	Error error
 }
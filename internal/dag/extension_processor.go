@@ -39,6 +39,10 @@ type ExtensionServiceProcessor struct {
 
 	// ConnectTimeout defines how long the proxy should wait when establishing connection to upstream service.
 	ConnectTimeout time.Duration
+
+	// DefaultLoadBalancerPolicy is the load balancer strategy of
+	// clusters that do not set their own. Defaults to RoundRobin.
+	DefaultLoadBalancerPolicy string
 }
 
 var _ Processor = &ExtensionServiceProcessor{}
@@ -124,7 +128,7 @@ func (p *ExtensionServiceProcessor) buildExtensionService(
 		// Reset load balancer policy to ensure the default.
 		lbPolicy = ""
 	}
-	extension.LoadBalancerPolicy = lbPolicy
+	extension.LoadBalancerPolicy = defaultLoadBalancerPolicy(ext.Spec.LoadBalancerPolicy, lbPolicy, p.DefaultLoadBalancerPolicy)
 
 	// Timeouts are specified above the cluster (e.g.
 	// in the ext_authz filter). The ext_authz filter
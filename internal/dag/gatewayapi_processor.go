@@ -14,16 +14,20 @@
 package dag
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/projectcontour/contour/internal/annotation"
 	"github.com/projectcontour/contour/internal/gatewayapi"
 	"github.com/projectcontour/contour/internal/k8s"
 	"github.com/projectcontour/contour/internal/ref"
 	"github.com/projectcontour/contour/internal/status"
+	"github.com/projectcontour/contour/internal/timeout"
 
 	"github.com/sirupsen/logrus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -57,6 +61,39 @@ type GatewayAPIProcessor struct {
 
 	// ConnectTimeout defines how long the proxy should wait when establishing connection to upstream service.
 	ConnectTimeout time.Duration
+
+	// DefaultLoadBalancerPolicy is the load balancer strategy of
+	// clusters that do not set their own. Defaults to RoundRobin.
+	DefaultLoadBalancerPolicy string
+
+	// RequestHeadersPolicy defines the request headers set/added/removed on
+	// all routes, unless the route's filters modify them.
+	RequestHeadersPolicy *HeadersPolicy
+
+	// ResponseHeadersPolicy defines the response headers set/added/removed on
+	// all routes, unless the route's filters modify them.
+	ResponseHeadersPolicy *HeadersPolicy
+
+	// AllowedHostnameSuffixes restricts the hostnames that routes may
+	// match to those under one of the suffixes. If empty, routes may
+	// match any hostname.
+	AllowedHostnameSuffixes []string
+
+	// DisabledRouteKinds are the route kinds that are not supported.
+	// Routes of these kinds are not accepted, and listeners do not
+	// allow them.
+	DisabledRouteKinds []string
+
+	// DefaultBackend is the Service port that requests to HTTP
+	// listeners are routed to when no route matches them. If nil,
+	// Envoy responds with a 404.
+	DefaultBackend *GatewayDefaultBackend
+}
+
+// GatewayDefaultBackend is a reference to a port of a Service.
+type GatewayDefaultBackend struct {
+	Service types.NamespacedName
+	Port    int
 }
 
 // matchConditions holds match rules.
@@ -129,6 +166,8 @@ func (p *GatewayAPIProcessor) Run(dag *DAG, source *KubernetesCache) {
 		}
 	}
 
+	p.setDefaultCertificateListener(readyListeners)
+
 	// Keep track of the number of routes attached
 	// to each Listener so we can set status properly.
 	listenerAttachedRoutes := map[string]int{}
@@ -153,6 +192,10 @@ func (p *GatewayAPIProcessor) Run(dag *DAG, source *KubernetesCache) {
 		gwAccessor.SetListenerAttachedRoutes(listenerName, attachedRoutes)
 	}
 
+	if gatewayNotProgrammedCondition == nil {
+		p.addDefaultBackendRoute(readyListeners, gwAccessor)
+	}
+
 	p.computeGatewayConditions(gwAccessor, gatewayNotProgrammedCondition)
 }
 
@@ -172,7 +215,31 @@ func (p *GatewayAPIProcessor) processRoute(
 	)
 	defer commit()
 
+	attachedToGateway := false
+	for _, routeParentRef := range parentRefs {
+		if gatewayapi.IsRefToGateway(routeParentRef, k8s.NamespacedNameOf(p.source.gateway)) {
+			attachedToGateway = true
+			break
+		}
+	}
+
 	for _, routeParentRef := range parentRefs {
+		// Contour does not do mesh routing, so reject parent refs to a
+		// Service rather than silently ignoring them. This is only done
+		// for routes that are also attached to this Gateway, other routes
+		// may be for a mesh implementation in the cluster.
+		if gatewayapi.IsRefToService(routeParentRef) {
+			if attachedToGateway {
+				routeStatus.StatusUpdateFor(routeParentRef).AddCondition(
+					gatewayapi_v1beta1.RouteConditionAccepted,
+					metav1.ConditionFalse,
+					gatewayapi_v1beta1.RouteReasonUnsupportedValue,
+					"Service parent refs are not supported, Contour only routes traffic for Gateways",
+				)
+			}
+			continue
+		}
+
 		// If this parent ref is to a different Gateway, ignore it.
 		if !gatewayapi.IsRefToGateway(routeParentRef, k8s.NamespacedNameOf(p.source.gateway)) {
 			continue
@@ -187,8 +254,33 @@ func (p *GatewayAPIProcessor) processRoute(
 		}
 
 		// Get the list of listeners that are (a) included by this parent ref, and
-		// (b) allow the route (based on kind, namespace).
-		allowedListeners := p.getListenersForRouteParentRef(routeParentRef, route.GetNamespace(), routeKind, readyListeners, routeParentStatus)
+		// (b) allow the route (based on kind, namespace). Routes of a disabled
+		// kind are not attached to any listener.
+		var allowedListeners []*listenerInfo
+		if p.routeKindDisabled(routeKind) {
+			routeParentStatus.AddCondition(
+				gatewayapi_v1beta1.RouteConditionAccepted,
+				metav1.ConditionFalse,
+				status.ReasonRouteKindDisabled,
+				fmt.Sprintf("%ss are disabled", routeKind),
+			)
+		} else {
+			allowedListeners = p.getListenersForRouteParentRef(routeParentRef, route.GetNamespace(), routeKind, readyListeners, routeParentStatus)
+		}
+
+		// If the route would match hostnames outside of the allowed
+		// suffixes on any listener, it is not attached to any of them.
+		if disallowed := p.disallowedHostnames(route, allowedListeners); len(disallowed) > 0 {
+			routeParentStatus.AddCondition(
+				gatewayapi_v1beta1.RouteConditionAccepted,
+				metav1.ConditionFalse,
+				status.ReasonHostnameNotAllowed,
+				fmt.Sprintf("Hostnames %s are not under any of the allowed hostname suffixes %s",
+					strings.Join(disallowed, ", "), strings.Join(p.AllowedHostnameSuffixes, ", ")),
+			)
+			allowedListeners = nil
+		}
+
 		if len(allowedListeners) == 0 {
 			p.resolveRouteRefs(route, routeParentStatus)
 		}
@@ -200,18 +292,7 @@ func (p *GatewayAPIProcessor) processRoute(
 		hostCount := 0
 
 		for _, listener := range allowedListeners {
-			var routeHostnames []gatewayapi_v1beta1.Hostname
-
-			switch route := route.(type) {
-			case *gatewayapi_v1beta1.HTTPRoute:
-				routeHostnames = route.Spec.Hostnames
-			case *gatewayapi_v1alpha2.TLSRoute:
-				routeHostnames = route.Spec.Hostnames
-			case *gatewayapi_v1alpha2.GRPCRoute:
-				routeHostnames = route.Spec.Hostnames
-			}
-
-			hosts, errs := p.computeHosts(routeHostnames, gatewayapi.HostnameDeref(listener.listener.Hostname))
+			hosts, errs := p.computeHosts(routeHostnames(route), gatewayapi.HostnameDeref(listener.listener.Hostname))
 			for _, err := range errs {
 				// The Gateway API spec does not indicate what to do if syntactically
 				// invalid hostnames make it through, we're using our best judgment here.
@@ -276,6 +357,47 @@ func (p *GatewayAPIProcessor) processRoute(
 	}
 }
 
+// routeHostnames returns the hostnames of an HTTPRoute, TLSRoute or GRPCRoute.
+func routeHostnames(route client.Object) []gatewayapi_v1beta1.Hostname {
+	switch route := route.(type) {
+	case *gatewayapi_v1beta1.HTTPRoute:
+		return route.Spec.Hostnames
+	case *gatewayapi_v1alpha2.TLSRoute:
+		return route.Spec.Hostnames
+	case *gatewayapi_v1alpha2.GRPCRoute:
+		return route.Spec.Hostnames
+	}
+	return nil
+}
+
+// disallowedHostnames returns the sorted hostnames that route matches
+// on listeners that are not under any of the allowed hostname suffixes.
+func (p *GatewayAPIProcessor) disallowedHostnames(route client.Object, listeners []*listenerInfo) []string {
+	if len(p.AllowedHostnameSuffixes) == 0 {
+		return nil
+	}
+
+	disallowed := sets.New[string]()
+	for _, listener := range listeners {
+		// Invalid hostnames are reported when the route is attached.
+		hosts, _ := p.computeHosts(routeHostnames(route), gatewayapi.HostnameDeref(listener.listener.Hostname))
+		for host := range hosts {
+			allowed := false
+			for _, suffix := range p.AllowedHostnameSuffixes {
+				if gatewayapi.HostnameHasSuffix(host, suffix) {
+					allowed = true
+					break
+				}
+			}
+			if !allowed {
+				disallowed.Insert(host)
+			}
+		}
+	}
+
+	return sets.List(disallowed)
+}
+
 func (p *GatewayAPIProcessor) getListenersForRouteParentRef(
 	routeParentRef gatewayapi_v1beta1.ParentReference,
 	routeNamespace string,
@@ -344,6 +466,57 @@ type listenerInfo struct {
 	allowedKinds      []gatewayapi_v1beta1.Kind
 	namespaceSelector labels.Selector
 	tlsSecret         *Secret
+	tlsOptions        gatewayapi.TLSOptions
+
+	// defaultCertificate is true for the HTTPS listener whose secret
+	// is served to clients that do not send SNI.
+	defaultCertificate bool
+}
+
+// setSecureVirtualHostTLS configures svhost to terminate TLS with
+// the listener's secret and TLS options.
+func (l *listenerInfo) setSecureVirtualHostTLS(svhost *SecureVirtualHost) {
+	svhost.Secret = l.tlsSecret
+	svhost.OCSPStaplePolicy = l.tlsOptions.OCSPStaplePolicy
+	svhost.DisableSessionTickets = l.tlsOptions.DisableSessionTickets
+
+	// The vhosts of the default certificate listener are also routed
+	// by the fallback filter chain, which serves the listener's secret
+	// to clients that do not send SNI.
+	if l.defaultCertificate {
+		svhost.FallbackCertificate = l.tlsSecret
+	}
+}
+
+// setDefaultCertificateListener chooses the HTTPS listener whose
+// certificate is served to clients that do not send SNI. That is the
+// first listener with the default certificate TLS option set to "true",
+// or the first HTTPS listener if none has it.
+func (p *GatewayAPIProcessor) setDefaultCertificateListener(listeners []*listenerInfo) {
+	var candidates []*listenerInfo
+	for _, l := range listeners {
+		if l.listener.Protocol == gatewayapi_v1beta1.HTTPSProtocolType && l.tlsSecret != nil {
+			candidates = append(candidates, l)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return
+	}
+
+	for _, l := range candidates {
+		if l.tlsOptions.DefaultCertificate {
+			l.defaultCertificate = true
+			return
+		}
+	}
+
+	// This is the documented default, and runs on every DAG rebuild,
+	// so it is only logged at debug level.
+	candidates[0].defaultCertificate = true
+	p.WithField("listener", candidates[0].listener.Name).
+		Debugf("no HTTPS listener sets the %q TLS option, using the first HTTPS listener's certificate for clients that do not send SNI",
+			gatewayapi.TLSOptionDefaultCertificate)
 }
 
 func (l *listenerInfo) AllowsKind(kind gatewayapi_v1beta1.Kind) bool {
@@ -402,6 +575,8 @@ func (p *GatewayAPIProcessor) computeListener(
 			msg,
 		)
 	}
+	var ignoredTLSOptions []string
+
 	// set the listener's "Programmed" condition based on whether we've
 	// added any other conditions for the listener. The assumption
 	// here is that if another condition is set, the listener is
@@ -455,6 +630,18 @@ func (p *GatewayAPIProcessor) computeListener(
 				)
 			}
 		}
+
+		// Ignored TLS options don't make the listener invalid, so
+		// they are only reported once its other conditions are set.
+		if len(ignoredTLSOptions) > 0 {
+			gwAccessor.AddListenerCondition(
+				string(listener.Name),
+				status.ListenerConditionTLSOptionsValid,
+				metav1.ConditionFalse,
+				status.ListenerReasonTLSOptionsIgnored,
+				strings.Join(ignoredTLSOptions, ", "),
+			)
+		}
 	}()
 
 	// If the listener had an invalid protocol/port/hostname, we don't need to go
@@ -490,7 +677,10 @@ func (p *GatewayAPIProcessor) computeListener(
 		}
 	}
 
-	var listenerSecret *Secret
+	var (
+		listenerSecret *Secret
+		tlsOptions     gatewayapi.TLSOptions
+	)
 
 	// Validate TLS details for HTTPS/TLS protocol listeners.
 	switch listener.Protocol {
@@ -515,28 +705,48 @@ func (p *GatewayAPIProcessor) computeListener(
 			// routes to be bound to this listener since it can't serve TLS traffic.
 			return false, nil
 		}
+
+		tlsOptions, ignoredTLSOptions = gatewayapi.ParseTLSOptions(listener.TLS.Options)
 	case gatewayapi_v1beta1.TLSProtocolType:
 		// The TLS protocol is used for TCP traffic encrypted with TLS.
 		// Gateway API allows TLS to be either terminated at the proxy
-		// or passed through to the backend, but the former requires using
-		// TCPRoute to route traffic since the underlying protocol is TCP
-		// not HTTP, which Contour doesn't support. Therefore, we only
-		// support "Passthrough" with the TLS protocol, which requires
-		// the use of TLSRoute to route to backends since the traffic is
-		// still encrypted.
+		// or passed through to the backend. Both modes use TLSRoute to
+		// route to backends: with "Passthrough" the traffic is forwarded
+		// still encrypted, and with "Terminate" the proxy terminates TLS
+		// and forwards the decrypted TCP stream.
 
 		if listener.TLS == nil {
 			addInvalidListenerCondition(fmt.Sprintf("Listener.TLS is required when protocol is %q.", listener.Protocol))
 			return false, nil
 		}
 
-		if listener.TLS.Mode == nil || *listener.TLS.Mode != gatewayapi_v1beta1.TLSModePassthrough {
-			addInvalidListenerCondition(fmt.Sprintf("Listener.TLS.Mode must be %q when protocol is %q.", gatewayapi_v1beta1.TLSModePassthrough, listener.Protocol))
-			return false, nil
-		}
+		switch ref.Val(listener.TLS.Mode, gatewayapi_v1beta1.TLSModeTerminate) {
+		case gatewayapi_v1beta1.TLSModePassthrough:
+			if len(listener.TLS.CertificateRefs) != 0 {
+				addInvalidListenerCondition(fmt.Sprintf("Listener.TLS.CertificateRefs cannot be defined when Listener.TLS.Mode is %q.", gatewayapi_v1beta1.TLSModePassthrough))
+				return false, nil
+			}
+		case gatewayapi_v1beta1.TLSModeTerminate:
+			if len(listener.TLS.CertificateRefs) == 0 {
+				addInvalidListenerCondition(fmt.Sprintf("Listener.TLS.CertificateRefs must be defined when Listener.TLS.Mode is %q.", gatewayapi_v1beta1.TLSModeTerminate))
+				return false, nil
+			}
+
+			// Resolve the TLS secret. TLSRoutes attached to this listener
+			// will have TLS terminated at the proxy using it.
+			if listenerSecret = p.resolveListenerSecret(listener.TLS.CertificateRefs, string(listener.Name), gwAccessor); listenerSecret == nil {
+				return false, nil
+			}
+
+			tlsOptions, ignoredTLSOptions = gatewayapi.ParseTLSOptions(listener.TLS.Options)
 
-		if len(listener.TLS.CertificateRefs) != 0 {
-			addInvalidListenerCondition(fmt.Sprintf("Listener.TLS.CertificateRefs cannot be defined when Listener.TLS.Mode is %q.", gatewayapi_v1beta1.TLSModePassthrough))
+			// Clients without SNI can only be routed to HTTPRoutes.
+			if tlsOptions.DefaultCertificate {
+				tlsOptions.DefaultCertificate = false
+				ignoredTLSOptions = append(ignoredTLSOptions, fmt.Sprintf("TLS option %q is only supported when protocol is %q", gatewayapi.TLSOptionDefaultCertificate, gatewayapi_v1beta1.HTTPSProtocolType))
+			}
+		default:
+			addInvalidListenerCondition(fmt.Sprintf("Listener.TLS.Mode must be %q or %q when protocol is %q.", gatewayapi_v1beta1.TLSModePassthrough, gatewayapi_v1beta1.TLSModeTerminate, listener.Protocol))
 			return false, nil
 		}
 	}
@@ -545,6 +755,7 @@ func (p *GatewayAPIProcessor) computeListener(
 		listener:          listener,
 		allowedKinds:      listenerRouteKinds,
 		tlsSecret:         listenerSecret,
+		tlsOptions:        tlsOptions,
 		namespaceSelector: selector,
 	}
 }
@@ -555,14 +766,24 @@ func (p *GatewayAPIProcessor) getListenerRouteKinds(listener gatewayapi_v1beta1.
 	// None specified on the listener: return the default based on
 	// the listener's protocol.
 	if len(listener.AllowedRoutes.Kinds) == 0 {
+		var defaultKinds []gatewayapi_v1beta1.Kind
 		switch listener.Protocol {
 		case gatewayapi_v1beta1.HTTPProtocolType:
-			return []gatewayapi_v1beta1.Kind{KindHTTPRoute, KindGRPCRoute}
+			defaultKinds = []gatewayapi_v1beta1.Kind{KindHTTPRoute, KindGRPCRoute}
 		case gatewayapi_v1beta1.HTTPSProtocolType:
-			return []gatewayapi_v1beta1.Kind{KindHTTPRoute, KindGRPCRoute}
+			defaultKinds = []gatewayapi_v1beta1.Kind{KindHTTPRoute, KindGRPCRoute}
 		case gatewayapi_v1beta1.TLSProtocolType:
-			return []gatewayapi_v1beta1.Kind{KindTLSRoute}
+			defaultKinds = []gatewayapi_v1beta1.Kind{KindTLSRoute}
 		}
+
+		// Disabled kinds are left out of the defaults.
+		var routeKinds []gatewayapi_v1beta1.Kind
+		for _, kind := range defaultKinds {
+			if !p.routeKindDisabled(kind) {
+				routeKinds = append(routeKinds, kind)
+			}
+		}
+		return routeKinds
 	}
 
 	var routeKinds []gatewayapi_v1beta1.Kind
@@ -598,6 +819,16 @@ func (p *GatewayAPIProcessor) getListenerRouteKinds(listener gatewayapi_v1beta1.
 			)
 			continue
 		}
+		if p.routeKindDisabled(routeKind.Kind) {
+			gwAccessor.AddListenerCondition(
+				string(listener.Name),
+				gatewayapi_v1beta1.ListenerConditionResolvedRefs,
+				metav1.ConditionFalse,
+				gatewayapi_v1beta1.ListenerReasonInvalidRouteKinds,
+				fmt.Sprintf("Kind %q is disabled", routeKind.Kind),
+			)
+			continue
+		}
 
 		routeKinds = append(routeKinds, routeKind.Kind)
 	}
@@ -605,6 +836,74 @@ func (p *GatewayAPIProcessor) getListenerRouteKinds(listener gatewayapi_v1beta1.
 	return routeKinds
 }
 
+// addDefaultBackendRoute adds a route for all paths of the "*" virtual
+// host of the HTTP listeners to the default backend, so that it serves
+// the requests of the hostnames that no route matches. Routes of more
+// specific hostnames take precedence, and a route that already matches
+// all paths of the "*" virtual host, such as an HTTP to HTTPS redirect,
+// is not replaced. A default backend that can not be resolved is
+// reported as a condition of the Gateway.
+func (p *GatewayAPIProcessor) addDefaultBackendRoute(listeners []*listenerInfo, gwAccessor *status.GatewayStatusUpdate) {
+	if p.DefaultBackend == nil {
+		return
+	}
+
+	hasHTTPListener := false
+	for _, l := range listeners {
+		if l.listener.Protocol == gatewayapi_v1beta1.HTTPProtocolType {
+			hasHTTPListener = true
+			break
+		}
+	}
+	if !hasHTTPListener {
+		return
+	}
+
+	route := &Route{
+		PathMatchCondition: &PrefixMatchCondition{Prefix: "/"},
+	}
+	if vhost := p.dag.GetVirtualHost(HTTP_LISTENER_NAME, "*"); vhost != nil {
+		if _, ok := vhost.Routes[conditionsToString(route)]; ok {
+			return
+		}
+	}
+
+	service, err := p.dag.EnsureService(p.DefaultBackend.Service, p.DefaultBackend.Port, p.DefaultBackend.Port, p.source, p.EnableExternalNameService)
+	if err != nil {
+		p.WithError(err).WithField("service", p.DefaultBackend.Service).Debug("default backend is invalid, requests that no route matches are not routed to it")
+		gwAccessor.AddCondition(status.GatewayConditionDefaultBackendResolved, metav1.ConditionFalse, status.GatewayReasonDefaultBackendInvalid,
+			fmt.Sprintf("Default backend Service %s is invalid: %s", p.DefaultBackend.Service, err))
+		return
+	}
+
+	route.Clusters = []*Cluster{{
+		Upstream:           service,
+		Weight:             1,
+		Protocol:           service.Protocol,
+		TimeoutPolicy:      ClusterTimeoutPolicy{ConnectTimeout: p.ConnectTimeout},
+		LoadBalancerPolicy: defaultLoadBalancerPolicy(nil, "", p.DefaultLoadBalancerPolicy),
+	}}
+
+	if route.RequestHeadersPolicy, err = headersPolicyWithDefaults(p.RequestHeadersPolicy, nil, true /* allow Host */); err != nil {
+		p.WithError(err).Error("default request headers are not applied to the default backend")
+	}
+	if route.ResponseHeadersPolicy, err = headersPolicyWithDefaults(p.ResponseHeadersPolicy, nil, false /* disallow Host */); err != nil {
+		p.WithError(err).Error("default response headers are not applied to the default backend")
+	}
+
+	p.dag.EnsureVirtualHost(HTTP_LISTENER_NAME, "*").AddRoute(route)
+}
+
+// routeKindDisabled returns whether routes of the kind are disabled.
+func (p *GatewayAPIProcessor) routeKindDisabled(kind gatewayapi_v1beta1.Kind) bool {
+	for _, disabled := range p.DisabledRouteKinds {
+		if string(kind) == disabled {
+			return true
+		}
+	}
+	return false
+}
+
 // resolveListenerSecret validates and resolves a Listener TLS secret
 // from a given list of certificateRefs. There must be exactly one
 // certificate ref, to a v1.Secret, that exists, is allowed to be referenced
@@ -935,10 +1234,11 @@ func (p *GatewayAPIProcessor) computeTLSRouteForListener(route *gatewayapi_v1alp
 			// https://github.com/projectcontour/contour/issues/3593
 			service.Weighted.Weight = routeWeight
 			proxy.Clusters = append(proxy.Clusters, &Cluster{
-				Upstream:      service,
-				SNI:           service.ExternalName,
-				Weight:        routeWeight,
-				TimeoutPolicy: ClusterTimeoutPolicy{ConnectTimeout: p.ConnectTimeout},
+				Upstream:           service,
+				SNI:                service.ExternalName,
+				Weight:             routeWeight,
+				TimeoutPolicy:      ClusterTimeoutPolicy{ConnectTimeout: p.ConnectTimeout},
+				LoadBalancerPolicy: defaultLoadBalancerPolicy(nil, "", p.DefaultLoadBalancerPolicy),
 			})
 		}
 
@@ -959,7 +1259,7 @@ func (p *GatewayAPIProcessor) computeTLSRouteForListener(route *gatewayapi_v1alp
 			secure := p.dag.EnsureSecureVirtualHost(HTTPS_LISTENER_NAME, host)
 
 			if listener.tlsSecret != nil {
-				secure.Secret = listener.tlsSecret
+				listener.setSecureVirtualHostTLS(secure)
 			}
 
 			secure.TCPProxy = &proxy
@@ -1036,6 +1336,54 @@ func (p *GatewayAPIProcessor) resolveRouteRefs(route interface{}, routeAccessor
 
 func (p *GatewayAPIProcessor) computeHTTPRouteForListener(route *gatewayapi_v1beta1.HTTPRoute, routeAccessor *status.RouteParentStatusUpdate, listener *listenerInfo, hosts sets.Set[string]) bool {
 	var programmed bool
+
+	maxRequestBodyBytes, err := annotation.MaxRequestBodyBytes(route)
+	if err != nil {
+		routeAccessor.AddCondition(
+			gatewayapi_v1beta1.RouteConditionAccepted,
+			metav1.ConditionFalse,
+			gatewayapi_v1beta1.RouteReasonUnsupportedValue,
+			fmt.Sprintf("projectcontour.io/max-request-body-bytes annotation is invalid: %s", err),
+		)
+		return false
+	}
+
+	mirrorPercentage, err := annotation.RequestMirrorPercentage(route)
+	if err != nil {
+		routeAccessor.AddCondition(
+			gatewayapi_v1beta1.RouteConditionAccepted,
+			metav1.ConditionFalse,
+			gatewayapi_v1beta1.RouteReasonUnsupportedValue,
+			fmt.Sprintf("projectcontour.io/request-mirror-percentage annotation is invalid: %s", err),
+		)
+		return false
+	}
+
+	hedgePerTryTimeout, err := annotation.HedgePerTryTimeout(route)
+	if err != nil {
+		routeAccessor.AddCondition(
+			gatewayapi_v1beta1.RouteConditionAccepted,
+			metav1.ConditionFalse,
+			gatewayapi_v1beta1.RouteReasonUnsupportedValue,
+			fmt.Sprintf("projectcontour.io/hedge-per-try-timeout annotation is invalid: %s", err),
+		)
+		return false
+	}
+
+	retryBudget, err := gatewayRetryBudget(route)
+	if err != nil {
+		routeAccessor.AddCondition(
+			gatewayapi_v1beta1.RouteConditionAccepted,
+			metav1.ConditionFalse,
+			gatewayapi_v1beta1.RouteReasonUnsupportedValue,
+			err.Error(),
+		)
+		return false
+	}
+
+	// The rules with matches whose requests are not hedged.
+	var unhedgedRules []string
+
 	for ruleIndex, rule := range route.Spec.Rules {
 		// Get match conditions for the rule.
 		var matchconditions []*matchConditions
@@ -1082,21 +1430,28 @@ func (p *GatewayAPIProcessor) computeHTTPRouteForListener(route *gatewayapi_v1be
 			mirrorPolicy         *MirrorPolicy
 			pathRewritePolicy    *PathRewritePolicy
 			urlRewriteHostname   string
+
+			// The positions of the filters in the list, or -1
+			// if the rule has no filter of the type.
+			requestHeaderModifierIndex = -1
+			redirectIndex              = -1
+			urlRewriteIndex            = -1
 		)
 
 		// Per Gateway API docs: "Specifying a core filter multiple times
 		// has unspecified or implementation-specific conformance." Contour
 		// chooses to use the first instance of each filter type and ignore
 		// subsequent instances.
-		for _, filter := range rule.Filters {
+		for filterIndex, filter := range rule.Filters {
 			switch filter.Type {
 			case gatewayapi_v1beta1.HTTPRouteFilterRequestHeaderModifier:
 				if filter.RequestHeaderModifier == nil || requestHeaderPolicy != nil {
 					continue
 				}
+				requestHeaderModifierIndex = filterIndex
 
 				var err error
-				requestHeaderPolicy, err = headersPolicyGatewayAPI(filter.RequestHeaderModifier, string(filter.Type))
+				requestHeaderPolicy, err = headersPolicyGatewayAPI(filter.RequestHeaderModifier, string(filter.Type), routeDynamicHeaders(route.Namespace))
 				if err != nil {
 					routeAccessor.AddCondition(gatewayapi_v1beta1.RouteConditionResolvedRefs, metav1.ConditionFalse, status.ReasonDegraded, fmt.Sprintf("%s on request headers", err))
 				}
@@ -1106,7 +1461,7 @@ func (p *GatewayAPIProcessor) computeHTTPRouteForListener(route *gatewayapi_v1be
 				}
 
 				var err error
-				responseHeaderPolicy, err = headersPolicyGatewayAPI(filter.ResponseHeaderModifier, string(filter.Type))
+				responseHeaderPolicy, err = headersPolicyGatewayAPI(filter.ResponseHeaderModifier, string(filter.Type), routeDynamicHeaders(route.Namespace))
 				if err != nil {
 					routeAccessor.AddCondition(gatewayapi_v1beta1.RouteConditionResolvedRefs, metav1.ConditionFalse, status.ReasonDegraded, fmt.Sprintf("%s on response headers", err))
 				}
@@ -1114,6 +1469,7 @@ func (p *GatewayAPIProcessor) computeHTTPRouteForListener(route *gatewayapi_v1be
 				if filter.RequestRedirect == nil || redirect != nil {
 					continue
 				}
+				redirectIndex = filterIndex
 
 				var hostname string
 				if filter.RequestRedirect.Hostname != nil {
@@ -1186,15 +1542,23 @@ func (p *GatewayAPIProcessor) computeHTTPRouteForListener(route *gatewayapi_v1be
 					routeAccessor.AddCondition(gatewayapi_v1beta1.RouteConditionType(cond.Type), cond.Status, gatewayapi_v1beta1.RouteConditionReason(cond.Reason), cond.Message)
 					continue
 				}
+
+				// A mirror percentage of zero disables mirroring.
+				if mirrorPercentage == 0 {
+					continue
+				}
+
 				mirrorPolicy = &MirrorPolicy{
 					Cluster: &Cluster{
 						Upstream: mirrorService,
 					},
+					Percentage: mirrorPercentage,
 				}
 			case gatewayapi_v1beta1.HTTPRouteFilterURLRewrite:
-				if filter.URLRewrite == nil || pathRewritePolicy != nil {
+				if filter.URLRewrite == nil || urlRewriteIndex >= 0 {
 					continue
 				}
+				urlRewriteIndex = filterIndex
 
 				if filter.URLRewrite.Hostname != nil {
 					urlRewriteHostname = string(*filter.URLRewrite.Hostname)
@@ -1243,19 +1607,51 @@ func (p *GatewayAPIProcessor) computeHTTPRouteForListener(route *gatewayapi_v1be
 			}
 		}
 
-		// If a URLRewrite filter specified a hostname rewrite,
-		// add it to the request headers policy. The API spec does
-		// not indicate how to resolve conflicts in rewriting the
-		// Host header between a URLRewrite filter and a RequestHeaderModifier
-		// filter so here we are choosing to prioritize the URLRewrite
-		// filter.
+		// A redirect is answered by Envoy, so it can't be combined
+		// with a URLRewrite filter or with backends to forward to.
+		if redirect != nil {
+			var incompatible string
+			switch {
+			case urlRewriteIndex >= 0:
+				incompatible = "a URLRewrite filter"
+			case len(rule.BackendRefs) > 0:
+				incompatible = "backendRefs"
+			}
+
+			if incompatible != "" {
+				routeAccessor.AddCondition(
+					gatewayapi_v1beta1.RouteConditionAccepted,
+					metav1.ConditionFalse,
+					status.ReasonIncompatibleFilters,
+					fmt.Sprintf("HTTPRoute.Spec.Rules.Filters: a RequestRedirect filter cannot be combined with %s.", incompatible),
+				)
+				continue
+			}
+		}
+
+		// Filters are applied in the order they are listed, so when
+		// both a URLRewrite filter and a RequestHeaderModifier filter
+		// rewrite the Host header, the filter listed last wins.
 		if len(urlRewriteHostname) > 0 {
 			if requestHeaderPolicy == nil {
 				requestHeaderPolicy = &HeadersPolicy{}
 			}
-			requestHeaderPolicy.HostRewrite = urlRewriteHostname
+			if requestHeaderPolicy.HostRewrite == "" || requestHeaderModifierIndex < urlRewriteIndex {
+				requestHeaderPolicy.HostRewrite = urlRewriteHostname
+			}
+		}
+
+		// A Host header set by a RequestHeaderModifier filter listed
+		// before the RequestRedirect filter is the host redirected to,
+		// unless the redirect specifies its own hostname.
+		if redirect != nil && redirect.Hostname == "" &&
+			requestHeaderPolicy != nil && requestHeaderPolicy.HostRewrite != "" &&
+			requestHeaderModifierIndex < redirectIndex {
+			redirect.Hostname = requestHeaderPolicy.HostRewrite
 		}
 
+		requestHeaderPolicy, responseHeaderPolicy = p.withDefaultHeadersPolicies(requestHeaderPolicy, responseHeaderPolicy, routeAccessor)
+
 		// Priority is used to ensure if there are multiple matching route rules
 		// within an HTTPRoute, the one that comes first in the list has
 		// precedence. We treat lower values as higher priority so we use the
@@ -1278,16 +1674,44 @@ func (p *GatewayAPIProcessor) computeHTTPRouteForListener(route *gatewayapi_v1be
 			if !ok {
 				continue
 			}
+			for _, cluster := range clusters {
+				cluster.RetryBudget = retryBudget
+			}
 			routes = p.clusterRoutes(matchconditions, requestHeaderPolicy, responseHeaderPolicy, mirrorPolicy, clusters, totalWeight, priority, pathRewritePolicy)
 		}
 
+		for _, route := range routes {
+			route.MaxRequestBodyBytes = maxRequestBodyBytes
+		}
+
+		// A hedged request may be sent to the backends twice, so
+		// only requests of idempotent methods are hedged.
+		if hedgePerTryTimeout > 0 && redirect == nil {
+			hedged := true
+			for _, route := range routes {
+				if !hasIdempotentMethodMatch(route) {
+					hedged = false
+					continue
+				}
+				route.RetryPolicy = &RetryPolicy{
+					RetryOn:              "reset",
+					NumRetries:           1,
+					PerTryTimeout:        timeout.DurationSetting(hedgePerTryTimeout),
+					HedgeOnPerTryTimeout: true,
+				}
+			}
+			if !hedged {
+				unhedgedRules = append(unhedgedRules, fmt.Sprintf("Spec.Rules[%d]", ruleIndex))
+			}
+		}
+
 		// Add each route to the relevant vhost(s)/svhosts(s).
 		for host := range hosts {
 			for _, route := range routes {
 				switch {
 				case listener.tlsSecret != nil:
 					svhost := p.dag.EnsureSecureVirtualHost(HTTPS_LISTENER_NAME, host)
-					svhost.Secret = listener.tlsSecret
+					listener.setSecureVirtualHostTLS(svhost)
 					svhost.AddRoute(route)
 				default:
 					vhost := p.dag.EnsureVirtualHost(HTTP_LISTENER_NAME, host)
@@ -1299,9 +1723,65 @@ func (p *GatewayAPIProcessor) computeHTTPRouteForListener(route *gatewayapi_v1be
 		}
 	}
 
+	// The route is computed for each of the listeners it attaches
+	// to, only report the unhedged rules once.
+	if len(unhedgedRules) > 0 && !routeAccessor.ConditionExists(status.ConditionRequestHedging) {
+		routeAccessor.AddCondition(
+			status.ConditionRequestHedging,
+			metav1.ConditionFalse,
+			status.ReasonNonIdempotentMethod,
+			fmt.Sprintf("Requests to %s are not hedged, hedging requires a match on an idempotent method (GET, HEAD, OPTIONS, TRACE, PUT or DELETE).",
+				strings.Join(unhedgedRules, ", ")),
+		)
+	}
+
 	return programmed
 }
 
+// gatewayRetryBudget returns the retry budget set by the
+// "projectcontour.io/retry-budget-*" annotations of the route, or
+// nil if the route has none.
+func gatewayRetryBudget(route *gatewayapi_v1beta1.HTTPRoute) (*RetryBudget, error) {
+	percent, err := annotation.RetryBudgetPercent(route)
+	if err != nil {
+		return nil, fmt.Errorf("projectcontour.io/retry-budget-percent annotation is invalid: %s", err)
+	}
+
+	minRetryConcurrency, err := annotation.RetryBudgetMinRetryConcurrency(route)
+	if err != nil {
+		return nil, fmt.Errorf("projectcontour.io/retry-budget-min-retry-concurrency annotation is invalid: %s", err)
+	}
+
+	if percent == 0 {
+		if minRetryConcurrency > 0 {
+			return nil, errors.New("projectcontour.io/retry-budget-min-retry-concurrency annotation requires the projectcontour.io/retry-budget-percent annotation")
+		}
+		return nil, nil
+	}
+
+	return &RetryBudget{
+		Percent:             percent,
+		MinRetryConcurrency: minRetryConcurrency,
+	}, nil
+}
+
+// hasIdempotentMethodMatch returns whether the route only matches
+// requests of an idempotent HTTP method.
+func hasIdempotentMethodMatch(route *Route) bool {
+	for _, cond := range route.HeaderMatchConditions {
+		if cond.Name != ":method" || cond.MatchType != HeaderMatchTypeExact || cond.Invert {
+			continue
+		}
+
+		switch cond.Value {
+		case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+			return true
+		}
+	}
+
+	return false
+}
+
 func (p *GatewayAPIProcessor) computeGRPCRouteForListener(route *gatewayapi_v1alpha2.GRPCRoute, routeAccessor *status.RouteParentStatusUpdate, listener *listenerInfo, hosts sets.Set[string]) bool {
 	var programmed bool
 	for ruleIndex, rule := range route.Spec.Rules {
@@ -1351,7 +1831,7 @@ func (p *GatewayAPIProcessor) computeGRPCRouteForListener(route *gatewayapi_v1al
 				}
 
 				var err error
-				requestHeaderPolicy, err = headersPolicyGatewayAPI(filter.RequestHeaderModifier, string(filter.Type))
+				requestHeaderPolicy, err = headersPolicyGatewayAPI(filter.RequestHeaderModifier, string(filter.Type), routeDynamicHeaders(route.Namespace))
 				if err != nil {
 					routeAccessor.AddCondition(gatewayapi_v1beta1.RouteConditionResolvedRefs, metav1.ConditionFalse, status.ReasonDegraded, fmt.Sprintf("%s on request headers", err))
 				}
@@ -1361,7 +1841,7 @@ func (p *GatewayAPIProcessor) computeGRPCRouteForListener(route *gatewayapi_v1al
 				}
 
 				var err error
-				responseHeaderPolicy, err = headersPolicyGatewayAPI(filter.ResponseHeaderModifier, string(filter.Type))
+				responseHeaderPolicy, err = headersPolicyGatewayAPI(filter.ResponseHeaderModifier, string(filter.Type), routeDynamicHeaders(route.Namespace))
 				if err != nil {
 					routeAccessor.AddCondition(gatewayapi_v1beta1.RouteConditionResolvedRefs, metav1.ConditionFalse, status.ReasonDegraded, fmt.Sprintf("%s on response headers", err))
 				}
@@ -1380,6 +1860,7 @@ func (p *GatewayAPIProcessor) computeGRPCRouteForListener(route *gatewayapi_v1al
 				mirrorPolicy = &MirrorPolicy{
 					Cluster: &Cluster{
 						Upstream: mirrorService,
+						Protocol: mirrorService.Protocol,
 					},
 				}
 			default:
@@ -1392,6 +1873,8 @@ func (p *GatewayAPIProcessor) computeGRPCRouteForListener(route *gatewayapi_v1al
 			}
 		}
 
+		requestHeaderPolicy, responseHeaderPolicy = p.withDefaultHeadersPolicies(requestHeaderPolicy, responseHeaderPolicy, routeAccessor)
+
 		// Priority is used to ensure if there are multiple matching route rules
 		// within an GRPCRoute, the one that comes first in the list has
 		// precedence. We treat lower values as higher priority so we use the
@@ -1417,7 +1900,7 @@ func (p *GatewayAPIProcessor) computeGRPCRouteForListener(route *gatewayapi_v1al
 				switch {
 				case listener.tlsSecret != nil:
 					svhost := p.dag.EnsureSecureVirtualHost(HTTPS_LISTENER_NAME, host)
-					svhost.Secret = listener.tlsSecret
+					listener.setSecureVirtualHostTLS(svhost)
 					svhost.AddRoute(route)
 				default:
 					vhost := p.dag.EnsureVirtualHost(HTTP_LISTENER_NAME, host)
@@ -1666,6 +2149,23 @@ func gatewayQueryParamMatchConditions(matches []gatewayapi_v1beta1.HTTPQueryPara
 	return dagMatchConditions, nil
 }
 
+// routeDynamicHeaders returns the Contour dynamic header values
+// available to the header filters of a route's rules.
+func routeDynamicHeaders(routeNamespace string) map[string]string {
+	return map[string]string{
+		"CONTOUR_NAMESPACE": routeNamespace,
+	}
+}
+
+// backendDynamicHeaders returns the Contour dynamic header values
+// available to the header filters of a route's backendRef.
+func backendDynamicHeaders(routeNamespace string, service *Service) map[string]string {
+	dynamicHeaders := routeDynamicHeaders(routeNamespace)
+	dynamicHeaders["CONTOUR_SERVICE_NAME"] = service.Weighted.ServiceName
+	dynamicHeaders["CONTOUR_SERVICE_PORT"] = strconv.Itoa(int(service.Weighted.ServicePort.Port))
+	return dynamicHeaders
+}
+
 // httpClusters builds clusters from backendRef.
 func (p *GatewayAPIProcessor) httpClusters(routeNamespace string, backendRefs []gatewayapi_v1beta1.HTTPBackendRef, routeAccessor *status.RouteParentStatusUpdate) ([]*Cluster, uint32, bool) {
 	totalWeight := uint32(0)
@@ -1700,7 +2200,7 @@ func (p *GatewayAPIProcessor) httpClusters(routeNamespace string, backendRefs []
 				}
 
 				var err error
-				clusterRequestHeaderPolicy, err = headersPolicyGatewayAPI(filter.RequestHeaderModifier, string(filter.Type))
+				clusterRequestHeaderPolicy, err = headersPolicyGatewayAPI(filter.RequestHeaderModifier, string(filter.Type), backendDynamicHeaders(routeNamespace, service))
 				if err != nil {
 					routeAccessor.AddCondition(gatewayapi_v1beta1.RouteConditionResolvedRefs, metav1.ConditionFalse, status.ReasonDegraded, fmt.Sprintf("%s on request headers", err))
 				}
@@ -1710,7 +2210,7 @@ func (p *GatewayAPIProcessor) httpClusters(routeNamespace string, backendRefs []
 				}
 
 				var err error
-				clusterResponseHeaderPolicy, err = headersPolicyGatewayAPI(filter.ResponseHeaderModifier, string(filter.Type))
+				clusterResponseHeaderPolicy, err = headersPolicyGatewayAPI(filter.ResponseHeaderModifier, string(filter.Type), backendDynamicHeaders(routeNamespace, service))
 				if err != nil {
 					routeAccessor.AddCondition(gatewayapi_v1beta1.RouteConditionResolvedRefs, metav1.ConditionFalse, status.ReasonDegraded, fmt.Sprintf("%s on response headers", err))
 				}
@@ -1743,6 +2243,7 @@ func (p *GatewayAPIProcessor) httpClusters(routeNamespace string, backendRefs []
 			RequestHeadersPolicy:  clusterRequestHeaderPolicy,
 			ResponseHeadersPolicy: clusterResponseHeaderPolicy,
 			TimeoutPolicy:         ClusterTimeoutPolicy{ConnectTimeout: p.ConnectTimeout},
+			LoadBalancerPolicy:    defaultLoadBalancerPolicy(nil, "", p.DefaultLoadBalancerPolicy),
 		})
 	}
 	return clusters, totalWeight, true
@@ -1781,7 +2282,7 @@ func (p *GatewayAPIProcessor) grpcClusters(routeNamespace string, backendRefs []
 				}
 
 				var err error
-				clusterRequestHeaderPolicy, err = headersPolicyGatewayAPI(filter.RequestHeaderModifier, string(filter.Type))
+				clusterRequestHeaderPolicy, err = headersPolicyGatewayAPI(filter.RequestHeaderModifier, string(filter.Type), backendDynamicHeaders(routeNamespace, service))
 				if err != nil {
 					routeAccessor.AddCondition(gatewayapi_v1beta1.RouteConditionResolvedRefs, metav1.ConditionFalse, status.ReasonDegraded, fmt.Sprintf("%s on request headers", err))
 				}
@@ -1791,7 +2292,7 @@ func (p *GatewayAPIProcessor) grpcClusters(routeNamespace string, backendRefs []
 				}
 
 				var err error
-				clusterResponseHeaderPolicy, err = headersPolicyGatewayAPI(filter.ResponseHeaderModifier, string(filter.Type))
+				clusterResponseHeaderPolicy, err = headersPolicyGatewayAPI(filter.ResponseHeaderModifier, string(filter.Type), backendDynamicHeaders(routeNamespace, service))
 				if err != nil {
 					routeAccessor.AddCondition(gatewayapi_v1beta1.RouteConditionResolvedRefs, metav1.ConditionFalse, status.ReasonDegraded, fmt.Sprintf("%s on response headers", err))
 				}
@@ -1827,11 +2328,31 @@ func (p *GatewayAPIProcessor) grpcClusters(routeNamespace string, backendRefs []
 			RequestHeadersPolicy:  clusterRequestHeaderPolicy,
 			ResponseHeadersPolicy: clusterResponseHeaderPolicy,
 			TimeoutPolicy:         ClusterTimeoutPolicy{ConnectTimeout: p.ConnectTimeout},
+			LoadBalancerPolicy:    defaultLoadBalancerPolicy(nil, "", p.DefaultLoadBalancerPolicy),
 		})
 	}
 	return clusters, totalWeight, true
 }
 
+// withDefaultHeadersPolicies merges the processor's default headers
+// policies into the headers policies built from a rule's filters.
+// Invalid defaults are reported on the route and not applied.
+func (p *GatewayAPIProcessor) withDefaultHeadersPolicies(requestHeaderPolicy, responseHeaderPolicy *HeadersPolicy, routeAccessor *status.RouteParentStatusUpdate) (*HeadersPolicy, *HeadersPolicy) {
+	request, err := headersPolicyWithDefaults(p.RequestHeadersPolicy, requestHeaderPolicy, true /* allow Host */)
+	if err != nil {
+		routeAccessor.AddCondition(gatewayapi_v1beta1.RouteConditionResolvedRefs, metav1.ConditionFalse, status.ReasonDegraded, fmt.Sprintf("%s on default request headers", err))
+		request = requestHeaderPolicy
+	}
+
+	response, err := headersPolicyWithDefaults(p.ResponseHeadersPolicy, responseHeaderPolicy, false /* disallow Host */)
+	if err != nil {
+		routeAccessor.AddCondition(gatewayapi_v1beta1.RouteConditionResolvedRefs, metav1.ConditionFalse, status.ReasonDegraded, fmt.Sprintf("%s on default response headers", err))
+		response = responseHeaderPolicy
+	}
+
+	return request, response
+}
+
 // clusterRoutes builds a []*dag.Route for the supplied set of matchConditions, headerPolicies and backendRefs.
 func (p *GatewayAPIProcessor) clusterRoutes(matchConditions []*matchConditions, requestHeaderPolicy *HeadersPolicy, responseHeaderPolicy *HeadersPolicy,
 	mirrorPolicy *MirrorPolicy, clusters []*Cluster, totalWeight uint32, priority uint8, pathRewritePolicy *PathRewritePolicy) []*Route {
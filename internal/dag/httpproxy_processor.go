@@ -32,11 +32,25 @@ import (
 	"github.com/projectcontour/contour/internal/timeout"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 // defaultMaxRequestBytes specifies default value maxRequestBytes for AuthorizationServer
 const defaultMaxRequestBytes uint32 = 1024
 
+// altStatNameRegex matches the names that can be used as a cluster's
+// alt_stat_name without being split into several stat name segments.
+var altStatNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,60}$`)
+
+// validateAltStatName returns an error if name is set and is not
+// usable as a cluster stat name.
+func validateAltStatName(name string) error {
+	if name != "" && !altStatNameRegex.MatchString(name) {
+		return fmt.Errorf("altStatName %q must be at most 60 characters and only contain alphanumerics, '-' and '_'", name)
+	}
+	return nil
+}
+
 // defaultExtensionRef populates the unset fields in ref with default values.
 func defaultExtensionRef(ref contour_api_v1.ExtensionServiceReference) contour_api_v1.ExtensionServiceReference {
 	if ref.APIVersion == "" {
@@ -94,8 +108,26 @@ type HTTPProxyProcessor struct {
 	// GlobalExternalAuthorization defines how requests will be authorized.
 	GlobalExternalAuthorization *contour_api_v1.AuthorizationServer
 
+	// HSTSPolicy is the default HTTP Strict Transport Security policy
+	// for secure virtual hosts that do not define their own (optional).
+	HSTSPolicy *contour_api_v1.HSTSPolicy
+
+	// CSPPolicy is the default Content Security Policy for routes that
+	// do not define their own, either directly or on their virtual
+	// host (optional).
+	CSPPolicy *contour_api_v1.CSPPolicy
+
+	// UpstreamClusterHeader is the default name of the response header
+	// set to the upstream cluster that served the request, for virtual
+	// hosts that do not set their own (optional).
+	UpstreamClusterHeader string
+
 	// ConnectTimeout defines how long the proxy should wait when establishing connection to upstream service.
 	ConnectTimeout time.Duration
+
+	// DefaultLoadBalancerPolicy is the load balancer strategy of
+	// clusters that do not set their own. Defaults to RoundRobin.
+	DefaultLoadBalancerPolicy string
 }
 
 // Run translates HTTPProxies into DAG objects and
@@ -456,6 +488,49 @@ func (p *HTTPProxyProcessor) computeHTTPProxy(proxy *contour_api_v1.HTTPProxy) {
 		}
 	}
 
+	if proxy.Spec.VirtualHost.RequireTLS && !tlsEnabled {
+		validCond.AddError(contour_api_v1.ConditionTypeTLSError, "TLSMustBeConfigured",
+			"Spec.VirtualHost.RequireTLS requires that either Spec.TLS.Passthrough or Spec.TLS.SecretName be set")
+		return
+	}
+
+	if proxy.Spec.VirtualHost.HSTSPolicy != nil && !tlsEnabled {
+		validCond.AddError(contour_api_v1.ConditionTypeTLSError, "TLSMustBeConfigured",
+			"Spec.VirtualHost.HSTSPolicy requires that either Spec.TLS.Passthrough or Spec.TLS.SecretName be set")
+		return
+	}
+
+	if csp := proxy.Spec.VirtualHost.CSPPolicy; csp != nil {
+		if err := csp.Validate(); err != nil {
+			validCond.AddErrorf(contour_api_v1.ConditionTypeVirtualHostError, "CSPPolicyNotValid",
+				"Spec.VirtualHost.CSPPolicy is invalid: %s", err)
+			return
+		}
+	}
+
+	upstreamClusterHeader := proxy.Spec.VirtualHost.UpstreamClusterHeader
+	if upstreamClusterHeader == "" {
+		upstreamClusterHeader = p.UpstreamClusterHeader
+	}
+	if upstreamClusterHeader != "" {
+		if msgs := validation.IsHTTPHeaderName(upstreamClusterHeader); len(msgs) != 0 {
+			validCond.AddErrorf(contour_api_v1.ConditionTypeVirtualHostError, "UpstreamClusterHeaderNotValid",
+				"Spec.VirtualHost.UpstreamClusterHeader %q is invalid: %s", upstreamClusterHeader, strings.Join(msgs, ", "))
+			return
+		}
+	}
+
+	hstsPolicy := proxy.Spec.VirtualHost.HSTSPolicy
+	if hstsPolicy == nil {
+		hstsPolicy = p.HSTSPolicy
+	}
+	hp, err := toHSTSPolicy(hstsPolicy)
+	if err != nil {
+		validCond.AddErrorf(contour_api_v1.ConditionTypeTLSError, "HSTSPolicyNotValid",
+			"Spec.VirtualHost.HSTSPolicy is invalid: %s", err)
+		return
+	}
+
 	if proxy.Spec.TCPProxy != nil {
 		if !tlsEnabled {
 			validCond.AddError(contour_api_v1.ConditionTypeTCPProxyError, "TLSMustBeConfigured",
@@ -484,6 +559,8 @@ func (p *HTTPProxyProcessor) computeHTTPProxy(proxy *contour_api_v1.HTTPProxy) {
 		return
 	}
 	insecure.RateLimitPolicy = rlp
+	insecure.RequireTLS = proxy.Spec.VirtualHost.RequireTLS
+	insecure.UpstreamClusterHeader = upstreamClusterHeader
 
 	if p.GlobalExternalAuthorization != nil && !proxy.Spec.VirtualHost.DisableAuthorization() {
 		p.computeVirtualHostAuthorization(p.GlobalExternalAuthorization, validCond, proxy)
@@ -496,6 +573,8 @@ func (p *HTTPProxyProcessor) computeHTTPProxy(proxy *contour_api_v1.HTTPProxy) {
 	if tlsEnabled && proxy.Spec.TCPProxy == nil {
 		secure := p.dag.EnsureSecureVirtualHost(HTTPS_LISTENER_NAME, host)
 		secure.CORSPolicy = cp
+		secure.HSTSPolicy = hp
+		secure.UpstreamClusterHeader = upstreamClusterHeader
 
 		rlp, err := rateLimitPolicy(proxy.Spec.VirtualHost.RateLimitPolicy)
 		if err != nil {
@@ -605,6 +684,12 @@ func (p *HTTPProxyProcessor) computeRoutes(
 			continue
 		}
 
+		if err := sourceFiltersValid(include.Conditions); err != nil {
+			validCond.AddError(contour_api_v1.ConditionTypeRouteError, "SourceFiltersNotValid",
+				err.Error())
+			continue
+		}
+
 		// Check to see if we have any duplicate include conditions.
 		if includeMatchConditionsIdentical(include.Conditions, seenConds) {
 			validCond.AddError(contour_api_v1.ConditionTypeIncludeError, "DuplicateMatchConditions",
@@ -623,6 +708,7 @@ func (p *HTTPProxyProcessor) computeRoutes(
 					PathMatchCondition:        mergePathMatchConditions(include.Conditions),
 					HeaderMatchConditions:     mergeHeaderMatchConditions(include.Conditions),
 					QueryParamMatchConditions: mergeQueryParamMatchConditions(include.Conditions),
+					SourceFilters:             mergeSourceFilters(include.Conditions),
 					DirectResponse:            directResponse(http.StatusBadGateway, ""),
 				})
 			}
@@ -649,6 +735,12 @@ func (p *HTTPProxyProcessor) computeRoutes(
 		"CONTOUR_NAMESPACE": proxy.Namespace,
 	}
 
+	// Envoy can't select routes by the client address, so routes
+	// that only differ by their source filters would shadow each
+	// other. This tracks whether the routes seen with each set of
+	// other conditions have source filters.
+	sourceFiltered := map[string]bool{}
+
 	for _, route := range proxy.Spec.Routes {
 		if err := routeActionCountValid(route); err != nil {
 			validCond.AddError(contour_api_v1.ConditionTypeRouteError, "RouteActionCountNotValid", err.Error())
@@ -678,6 +770,26 @@ func (p *HTTPProxyProcessor) computeRoutes(
 			return nil
 		}
 
+		// Look for invalid source filters on this route
+		if err := sourceFiltersValid(routeConditions); err != nil {
+			validCond.AddError(contour_api_v1.ConditionTypeRouteError, "SourceFiltersNotValid",
+				err.Error())
+			return nil
+		}
+
+		key := conditionsToString(&Route{
+			PathMatchCondition:        mergePathMatchConditions(routeConditions),
+			HeaderMatchConditions:     mergeHeaderMatchConditions(routeConditions),
+			QueryParamMatchConditions: mergeQueryParamMatchConditions(routeConditions),
+		})
+		filtered := len(mergeSourceFilters(routeConditions)) > 0
+		if seenFiltered, ok := sourceFiltered[key]; ok && (seenFiltered || filtered) {
+			validCond.AddError(contour_api_v1.ConditionTypeRouteError, "SourceFiltersConflict",
+				"routes with the same match conditions must not differ only by their source filters")
+			return nil
+		}
+		sourceFiltered[key] = filtered
+
 		reqHP, err := headersPolicyRoute(route.RequestHeadersPolicy, true /* allow Host */, dynamicHeaders)
 		if err != nil {
 			validCond.AddErrorf(contour_api_v1.ConditionTypeRouteError, "RequestHeadersPolicyInvalid",
@@ -692,6 +804,14 @@ func (p *HTTPProxyProcessor) computeRoutes(
 			return nil
 		}
 
+		if route.CSPPolicy != nil {
+			if err := route.CSPPolicy.Validate(); err != nil {
+				validCond.AddErrorf(contour_api_v1.ConditionTypeRouteError, "CSPPolicyNotValid",
+					"route.cspPolicy is invalid: %s", err)
+				return nil
+			}
+		}
+
 		cookieRP, err := cookieRewritePolicies(route.CookieRewritePolicies)
 		if err != nil {
 			validCond.AddErrorf(contour_api_v1.ConditionTypeRouteError, "CookieRewritePoliciesInvalid",
@@ -714,6 +834,7 @@ func (p *HTTPProxyProcessor) computeRoutes(
 		}
 
 		requestHashPolicies, lbPolicy := loadBalancerRequestHashPolicies(route.LoadBalancerPolicy, validCond)
+		lbPolicy = defaultLoadBalancerPolicy(route.LoadBalancerPolicy, lbPolicy, p.DefaultLoadBalancerPolicy)
 
 		redirectPolicy, err := redirectRoutePolicy(route.RequestRedirectPolicy)
 		if err != nil {
@@ -726,16 +847,25 @@ func (p *HTTPProxyProcessor) computeRoutes(
 
 		directPolicy := directResponsePolicy(route.DirectResponsePolicy)
 
+		budget, err := retryBudget(route.RetryPolicy)
+		if err != nil {
+			validCond.AddErrorf(contour_api_v1.ConditionTypeRouteError, "RetryBudgetInvalid",
+				"route.retryPolicy.budget is invalid: %s", err)
+			return nil
+		}
+
 		r := &Route{
 			PathMatchCondition:        mergePathMatchConditions(routeConditions),
 			HeaderMatchConditions:     mergeHeaderMatchConditions(routeConditions),
 			QueryParamMatchConditions: mergeQueryParamMatchConditions(routeConditions),
+			SourceFilters:             mergeSourceFilters(routeConditions),
 			Websocket:                 route.EnableWebsockets,
 			HTTPSUpgrade:              routeEnforceTLS(enforceTLS, route.PermitInsecure && !p.DisablePermitInsecure),
 			TimeoutPolicy:             rtp,
 			RetryPolicy:               retryPolicy(route.RetryPolicy),
 			RequestHeadersPolicy:      reqHP,
 			ResponseHeadersPolicy:     respHP,
+			ContentSecurityPolicy:     p.contentSecurityPolicy(rootProxy, route),
 			CookieRewritePolicies:     cookieRP,
 			RateLimitPolicy:           rlp,
 			RequestHashPolicies:       requestHashPolicies,
@@ -813,6 +943,23 @@ func (p *HTTPProxyProcessor) computeRoutes(
 
 		}
 
+		if route.IsolateConnectionPool && len(route.Services) == 0 {
+			validCond.AddError(contour_api_v1.ConditionTypeRouteError, "ConnectionPoolIsolationNotValid",
+				"route.isolateConnectionPool requires at least one service")
+			return nil
+		}
+
+		var connectionPoolKey string
+		if route.IsolateConnectionPool {
+			connectionPoolKey = rootProxy.Spec.VirtualHost.Fqdn + conditionsToString(r)
+		}
+
+		if err := validateServicePriorities(route.Services); err != nil {
+			validCond.AddError(contour_api_v1.ConditionTypeServiceError, "ServicePriorityInvalid", err.Error())
+			return nil
+		}
+
+		var failover []WeightedService
 		for _, service := range route.Services {
 			if service.Port < 1 || service.Port > 65535 {
 				validCond.AddErrorf(contour_api_v1.ConditionTypeServiceError, "ServicePortInvalid",
@@ -822,12 +969,23 @@ func (p *HTTPProxyProcessor) computeRoutes(
 
 			var healthPort int
 			healthPolicy := httpHealthCheckPolicy(route.HealthCheckPolicy)
+			if healthPolicy == nil && service.IgnoreNewHostsUntilFirstHC {
+				validCond.AddErrorf(contour_api_v1.ConditionTypeServiceError, "IgnoreNewHostsUntilFirstHCInvalid",
+					"service %q: ignoreNewHostsUntilFirstHC requires a health check policy", service.Name)
+				return nil
+			}
 			if healthPolicy != nil && service.HealthPort > 0 {
 				healthPort = service.HealthPort
 			} else {
 				healthPort = service.Port
 			}
 
+			if err := validateAltStatName(service.AltStatName); err != nil {
+				validCond.AddErrorf(contour_api_v1.ConditionTypeServiceError, "AltStatNameInvalid",
+					"service %q: %s", service.Name, err)
+				return nil
+			}
+
 			m := types.NamespacedName{Name: service.Name, Namespace: proxy.Namespace}
 			s, err := p.dag.EnsureService(m, service.Port, healthPort, p.source, p.EnableExternalNameService)
 			if err != nil {
@@ -836,6 +994,20 @@ func (p *HTTPProxyProcessor) computeRoutes(
 				continue
 			}
 
+			// Endpoints of failover services are served by the clusters
+			// of the priority 0 services, so they need no cluster of their own.
+			if service.Priority > 0 {
+				if s.ExternalName != "" {
+					validCond.AddErrorf(contour_api_v1.ConditionTypeServiceError, "ServicePriorityInvalid",
+						"service %q: priority is not supported for ExternalName services", service.Name)
+					return nil
+				}
+				w := s.Weighted
+				w.Priority = service.Priority
+				failover = append(failover, w)
+				continue
+			}
+
 			// Determine the protocol to use to speak to this Cluster.
 			protocol, err := getProtocol(service, s)
 			if err != nil {
@@ -843,6 +1015,15 @@ func (p *HTTPProxyProcessor) computeRoutes(
 				return nil
 			}
 
+			// HTTP/2 to a TLS upstream must use "h2" so that ALPN
+			// negotiates HTTP/2; "h2c" is never sent over TLS, so
+			// upstream validation would be silently ignored.
+			if protocol == "h2c" && service.UpstreamValidation != nil {
+				validCond.AddErrorf(contour_api_v1.ConditionTypeServiceError, "InconsistentProtocol",
+					"Service [%s:%d] upstream TLS validation not supported for %q protocol, use \"h2\" for HTTP/2 over TLS", service.Name, service.Port, protocol)
+				return nil
+			}
+
 			var uv *PeerValidationContext
 			if (protocol == "tls" || protocol == "h2") && service.UpstreamValidation != nil {
 				// If the CACertificate name in the UpstreamValidation is namespaced and the namespace
@@ -915,20 +1096,24 @@ func (p *HTTPProxyProcessor) computeRoutes(
 			}
 
 			c := &Cluster{
-				Upstream:              s,
-				LoadBalancerPolicy:    lbPolicy,
-				Weight:                uint32(service.Weight),
-				HTTPHealthCheckPolicy: healthPolicy,
-				UpstreamValidation:    uv,
-				RequestHeadersPolicy:  reqHP,
-				ResponseHeadersPolicy: respHP,
-				CookieRewritePolicies: cookieRP,
-				Protocol:              protocol,
-				SNI:                   determineSNI(r.RequestHeadersPolicy, reqHP, s),
-				DNSLookupFamily:       string(p.DNSLookupFamily),
-				ClientCertificate:     clientCertSecret,
-				TimeoutPolicy:         ctp,
-				SlowStartConfig:       slowStart,
+				Upstream:                   s,
+				LoadBalancerPolicy:         lbPolicy,
+				Weight:                     uint32(service.Weight),
+				HTTPHealthCheckPolicy:      healthPolicy,
+				UpstreamValidation:         uv,
+				RequestHeadersPolicy:       reqHP,
+				ResponseHeadersPolicy:      respHP,
+				CookieRewritePolicies:      cookieRP,
+				Protocol:                   protocol,
+				SNI:                        determineSNI(r.RequestHeadersPolicy, reqHP, s),
+				DNSLookupFamily:            string(p.DNSLookupFamily),
+				ClientCertificate:          clientCertSecret,
+				TimeoutPolicy:              ctp,
+				SlowStartConfig:            slowStart,
+				IgnoreNewHostsUntilFirstHC: service.IgnoreNewHostsUntilFirstHC,
+				ConnectionPoolKey:          connectionPoolKey,
+				AltStatName:                service.AltStatName,
+				RetryBudget:                budget,
 			}
 			if service.Mirror && r.MirrorPolicy != nil {
 				validCond.AddError(contour_api_v1.ConditionTypeServiceError, "OnlyOneMirror",
@@ -943,6 +1128,16 @@ func (p *HTTPProxyProcessor) computeRoutes(
 				r.Clusters = append(r.Clusters, c)
 			}
 		}
+		if len(failover) > 0 {
+			for _, c := range r.Clusters {
+				if c.Upstream.ExternalName != "" {
+					validCond.AddErrorf(contour_api_v1.ConditionTypeServiceError, "ServicePriorityInvalid",
+						"service %q: ExternalName services cannot fail over to other services", c.Upstream.Weighted.ServiceName)
+					return nil
+				}
+				c.Failover = failover
+			}
+		}
 		if len(r.Clusters) == 0 && route.RequestRedirectPolicy == nil && route.DirectResponsePolicy == nil {
 			r.DirectResponse = directResponse(http.StatusServiceUnavailable, "")
 		}
@@ -1012,18 +1207,30 @@ func (p *HTTPProxyProcessor) processHTTPProxyTCPProxy(validCond *contour_api_v1.
 		// Reset load balancer policy to ensure the default.
 		lbPolicy = ""
 	}
+	lbPolicy = defaultLoadBalancerPolicy(tcpproxy.LoadBalancerPolicy, lbPolicy, p.DefaultLoadBalancerPolicy)
 
 	if len(tcpproxy.Services) > 0 {
 		var proxy TCPProxy
 		for _, service := range httpproxy.Spec.TCPProxy.Services {
 			var healthPort int
 			healthPolicy := tcpHealthCheckPolicy(tcpproxy.HealthCheckPolicy)
+			if healthPolicy == nil && service.IgnoreNewHostsUntilFirstHC {
+				validCond.AddErrorf(contour_api_v1.ConditionTypeServiceError, "IgnoreNewHostsUntilFirstHCInvalid",
+					"service %q: ignoreNewHostsUntilFirstHC requires a health check policy", service.Name)
+				return false
+			}
 			if healthPolicy != nil && service.HealthPort > 0 {
 				healthPort = service.HealthPort
 			} else {
 				healthPort = service.Port
 			}
 
+			if err := validateAltStatName(service.AltStatName); err != nil {
+				validCond.AddErrorf(contour_api_v1.ConditionTypeServiceError, "AltStatNameInvalid",
+					"service %q: %s", service.Name, err)
+				return false
+			}
+
 			m := types.NamespacedName{Name: service.Name, Namespace: httpproxy.Namespace}
 			s, err := p.dag.EnsureService(m, service.Port, healthPort, p.source, p.EnableExternalNameService)
 			if err != nil {
@@ -1040,13 +1247,15 @@ func (p *HTTPProxyProcessor) processHTTPProxyTCPProxy(validCond *contour_api_v1.
 			}
 
 			proxy.Clusters = append(proxy.Clusters, &Cluster{
-				Upstream:             s,
-				Weight:               uint32(service.Weight),
-				Protocol:             protocol,
-				LoadBalancerPolicy:   lbPolicy,
-				TCPHealthCheckPolicy: healthPolicy,
-				SNI:                  s.ExternalName,
-				TimeoutPolicy:        ClusterTimeoutPolicy{ConnectTimeout: p.ConnectTimeout},
+				Upstream:                   s,
+				Weight:                     uint32(service.Weight),
+				Protocol:                   protocol,
+				LoadBalancerPolicy:         lbPolicy,
+				TCPHealthCheckPolicy:       healthPolicy,
+				SNI:                        s.ExternalName,
+				TimeoutPolicy:              ClusterTimeoutPolicy{ConnectTimeout: p.ConnectTimeout},
+				IgnoreNewHostsUntilFirstHC: service.IgnoreNewHostsUntilFirstHC,
+				AltStatName:                service.AltStatName,
 			})
 		}
 		secure := p.dag.EnsureSecureVirtualHost(HTTPS_LISTENER_NAME, host)
@@ -1378,6 +1587,34 @@ func getProtocol(service contour_api_v1.Service, s *Service) (string, error) {
 	return protocol, nil
 }
 
+// validateServicePriorities checks that the failover priorities of a
+// route's services start at 0 and do not skip any levels. Mirror
+// services do not take part in failover.
+func validateServicePriorities(services []contour_api_v1.Service) error {
+	var max uint32
+	levels := map[uint32]bool{}
+	for _, service := range services {
+		if service.Mirror {
+			if service.Priority > 0 {
+				return fmt.Errorf("service %q: priority cannot be set on a mirror service", service.Name)
+			}
+			continue
+		}
+		levels[service.Priority] = true
+		if service.Priority > max {
+			max = service.Priority
+		}
+	}
+
+	for i := uint32(0); i < max; i++ {
+		if !levels[i] {
+			return fmt.Errorf("service priorities must be contiguous starting from 0, missing priority %d", i)
+		}
+	}
+
+	return nil
+}
+
 // determineSNI decides what the SNI should be on the request. It is configured via RequestHeadersPolicy.Host key.
 // Policies set on service are used before policies set on a route. Otherwise the value of the externalService
 // is used if the route is configured to proxy to an externalService type.
@@ -1473,6 +1710,42 @@ func toCORSPolicy(policy *contour_api_v1.CORSPolicy) (*CORSPolicy, error) {
 	}, nil
 }
 
+// contentSecurityPolicy returns the Content-Security-Policy header
+// value for the route, preferring the route's own policy, then the root
+// proxy's virtual host policy and finally the global default.
+func (p *HTTPProxyProcessor) contentSecurityPolicy(rootProxy *contour_api_v1.HTTPProxy, route contour_api_v1.Route) string {
+	policy := route.CSPPolicy
+	if policy == nil && rootProxy.Spec.VirtualHost != nil {
+		policy = rootProxy.Spec.VirtualHost.CSPPolicy
+	}
+	if policy == nil {
+		policy = p.CSPPolicy
+	}
+	if policy == nil {
+		return ""
+	}
+	return escapeHeaderValue(policy.Directives, nil)
+}
+
+func toHSTSPolicy(policy *contour_api_v1.HSTSPolicy) (*HSTSPolicy, error) {
+	if policy == nil {
+		return nil, nil
+	}
+
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Validate has already checked that the max age parses.
+	maxAge, _ := time.ParseDuration(policy.MaxAge)
+
+	return &HSTSPolicy{
+		MaxAge:            maxAge,
+		IncludeSubDomains: policy.IncludeSubDomains,
+		Preload:           policy.Preload,
+	}, nil
+}
+
 func toStringSlice(hvs []contour_api_v1.CORSHeaderValue) []string {
 	s := make([]string, len(hvs))
 	for i, v := range hvs {
@@ -1485,12 +1758,18 @@ func toStringSlice(hvs []contour_api_v1.CORSHeaderValue) []string {
 type matchConditionAggregate struct {
 	headerConds     []HeaderMatchCondition
 	queryParamConds []QueryParamMatchCondition
+	sourceFiltered  bool
 }
 
+// includeMatchConditionsIdentical returns whether the conditions of an
+// include are identical to those of an include seen before. Source
+// filters don't select routes, so includes that only differ by them
+// are identical.
 func includeMatchConditionsIdentical(includeConds []contour_api_v1.MatchCondition, seenConds map[string][]matchConditionAggregate) bool {
 	pathPrefix := mergePathMatchConditions(includeConds).Prefix
 	includeHeaderConds := mergeHeaderMatchConditions(includeConds)
 	includeQueryParamConds := mergeQueryParamMatchConditions(includeConds)
+	includeSourceFiltered := len(mergeSourceFilters(includeConds)) > 0
 
 	// Note: this is stop-gap that we intend to change.
 	// This means that an empty set of conditions or a lone path prefix match on "/"
@@ -1499,7 +1778,17 @@ func includeMatchConditionsIdentical(includeConds []contour_api_v1.MatchConditio
 	// behavior to set up their include tree.
 	// It is unlikely that there is much usage of duplicate non-default include
 	// conditions, so we think this special case is safe.
+	// Includes with source filters are newer, so they are not exempt,
+	// and are compared to all the includes without other conditions.
 	if pathPrefix == "/" && len(includeHeaderConds) == 0 && len(includeQueryParamConds) == 0 {
+		for _, ag := range seenConds[pathPrefix] {
+			if len(ag.headerConds) == 0 && len(ag.queryParamConds) == 0 && (ag.sourceFiltered || includeSourceFiltered) {
+				return true
+			}
+		}
+		seenConds[pathPrefix] = append(seenConds[pathPrefix], matchConditionAggregate{
+			sourceFiltered: includeSourceFiltered,
+		})
 		return false
 	}
 
@@ -1537,19 +1826,9 @@ func includeMatchConditionsIdentical(includeConds []contour_api_v1.MatchConditio
 		return false
 	})
 
-	// Check if we have seen this path before.
-	// If so, get all the collections of header and query params we
-	// have seen with it.
-	condAggregates, pathSeen := seenConds[pathPrefix]
-	if !pathSeen {
-		seenConds[pathPrefix] = []matchConditionAggregate{{
-			headerConds:     includeHeaderConds,
-			queryParamConds: includeQueryParamConds,
-		}}
-		return false
-	}
-
-	for _, ag := range condAggregates {
+	// Compare to all the collections of header and query params
+	// we have seen with this path before.
+	for _, ag := range seenConds[pathPrefix] {
 		// Quick check to see if lengths of either header or query param
 		// condition lists are mismatched.
 		// If so, we can skip the rest of the checks.
@@ -1571,17 +1850,27 @@ func includeMatchConditionsIdentical(includeConds []contour_api_v1.MatchConditio
 		}
 
 		// Now compare (sorted) query param conditions element-by-element.
-		// If any mismatch, we can return early.
+		// If any mismatch, we can skip the rest of the checks.
+		queryParamCondsIdentical := true
 		for i := range ag.queryParamConds {
 			if ag.queryParamConds[i] != includeQueryParamConds[i] {
-				return false
+				queryParamCondsIdentical = false
 			}
 		}
+		if !queryParamCondsIdentical {
+			continue
+		}
+
 		// If we get here, all header and query param conditions
 		// must be equal.
 		return true
 	}
 
+	seenConds[pathPrefix] = append(seenConds[pathPrefix], matchConditionAggregate{
+		headerConds:     includeHeaderConds,
+		queryParamConds: includeQueryParamConds,
+		sourceFiltered:  includeSourceFiltered,
+	})
 	return false
 }
 
@@ -54,6 +54,10 @@ type IngressProcessor struct {
 
 	// ConnectTimeout defines how long the proxy should wait when establishing connection to upstream service.
 	ConnectTimeout time.Duration
+
+	// DefaultLoadBalancerPolicy is the load balancer strategy of
+	// clusters that do not set their own. Defaults to RoundRobin.
+	DefaultLoadBalancerPolicy string
 }
 
 // Run translates Ingresses into DAG objects and
@@ -242,6 +246,7 @@ func (p *IngressProcessor) route(ingress *networking_v1.Ingress, host string, pa
 			RequestHeadersPolicy:  reqHP,
 			ResponseHeadersPolicy: respHP,
 			TimeoutPolicy:         ClusterTimeoutPolicy{ConnectTimeout: p.ConnectTimeout},
+			LoadBalancerPolicy:    defaultLoadBalancerPolicy(nil, "", p.DefaultLoadBalancerPolicy),
 		}},
 	}
 
@@ -105,6 +105,26 @@ func retryPolicy(rp *contour_api_v1.RetryPolicy) *RetryPolicy {
 	}
 }
 
+// retryBudget returns the retry budget of rp, or an error if rp
+// disables retries.
+func retryBudget(rp *contour_api_v1.RetryPolicy) (*RetryBudget, error) {
+	if rp == nil || rp.Budget == nil {
+		return nil, nil
+	}
+
+	if rp.NumRetries == -1 {
+		return nil, errors.New("a retry budget requires retries to be enabled")
+	}
+	if rp.Budget.Percent < 1 || rp.Budget.Percent > 100 {
+		return nil, fmt.Errorf("percent must be between 1 and 100, got %d", rp.Budget.Percent)
+	}
+
+	return &RetryBudget{
+		Percent:             rp.Budget.Percent,
+		MinRetryConcurrency: rp.Budget.MinRetryConcurrency,
+	}, nil
+}
+
 func headersPolicyService(defaultPolicy *HeadersPolicy, policy *contour_api_v1.HeadersPolicy, allowHostRewrite bool, dynamicHeaders map[string]string) (*HeadersPolicy, error) {
 	if defaultPolicy == nil {
 		return headersPolicyRoute(policy, allowHostRewrite, dynamicHeaders)
@@ -139,6 +159,22 @@ func headersPolicyService(defaultPolicy *HeadersPolicy, policy *contour_api_v1.H
 			userPolicy.Set[key] = escapeHeaderValue(v, dynamicHeaders)
 		}
 	}
+	for k, v := range defaultPolicy.Add {
+		key := http.CanonicalHeaderKey(k)
+		if msgs := validation.IsHTTPHeaderName(key); len(msgs) != 0 {
+			return nil, fmt.Errorf("invalid add header %q: %v", key, msgs)
+		}
+		// a header set by the user policy replaces any default value
+		if _, exists := userPolicy.Set[key]; exists {
+			continue
+		}
+		if userPolicy.Add == nil {
+			userPolicy.Add = make(map[string]string, len(defaultPolicy.Add))
+		}
+		if _, exists := userPolicy.Add[key]; !exists {
+			userPolicy.Add[key] = escapeHeaderValue(v, dynamicHeaders)
+		}
+	}
 	// add any default remove header policy if not already set
 	remove := sets.NewString()
 	for _, entry := range userPolicy.Remove {
@@ -157,6 +193,80 @@ func headersPolicyService(defaultPolicy *HeadersPolicy, policy *contour_api_v1.H
 	return userPolicy, nil
 }
 
+// headersPolicyWithDefaults returns policy with the headers of
+// defaultPolicy merged in. Headers that policy already sets, adds or
+// removes are left as they are. A nil policy is returned unchanged if
+// defaultPolicy has nothing to contribute.
+func headersPolicyWithDefaults(defaultPolicy, policy *HeadersPolicy, allowHostRewrite bool) (*HeadersPolicy, error) {
+	if defaultPolicy == nil {
+		return policy, nil
+	}
+
+	merged := &HeadersPolicy{}
+	if policy != nil {
+		merged = policy
+	}
+
+	modified := sets.NewString(merged.Remove...)
+	for key := range merged.Set {
+		modified.Insert(key)
+	}
+	for key := range merged.Add {
+		modified.Insert(key)
+	}
+
+	merge := func(defaults map[string]string, headers map[string]string, op string) (map[string]string, error) {
+		for k, v := range defaults {
+			key := http.CanonicalHeaderKey(k)
+			if key == "Host" {
+				if !allowHostRewrite {
+					return nil, fmt.Errorf("rewriting %q header is not supported", key)
+				}
+				if len(merged.HostRewrite) == 0 {
+					merged.HostRewrite = v
+				}
+				continue
+			}
+			if msgs := validation.IsHTTPHeaderName(key); len(msgs) != 0 {
+				return nil, fmt.Errorf("invalid %s header %q: %v", op, key, msgs)
+			}
+			if modified.Has(key) {
+				continue
+			}
+			if headers == nil {
+				headers = make(map[string]string, len(defaults))
+			}
+			headers[key] = escapeHeaderValue(v, nil)
+		}
+		return headers, nil
+	}
+
+	var err error
+	if merged.Set, err = merge(defaultPolicy.Set, merged.Set, "set"); err != nil {
+		return nil, err
+	}
+	if merged.Add, err = merge(defaultPolicy.Add, merged.Add, "add"); err != nil {
+		return nil, err
+	}
+
+	for _, entry := range defaultPolicy.Remove {
+		key := http.CanonicalHeaderKey(entry)
+		if msgs := validation.IsHTTPHeaderName(key); len(msgs) != 0 {
+			return nil, fmt.Errorf("invalid remove header %q: %v", key, msgs)
+		}
+		if !modified.Has(key) {
+			merged.Remove = append(merged.Remove, key)
+			modified.Insert(key)
+		}
+	}
+
+	if policy == nil && len(merged.Set) == 0 && len(merged.Add) == 0 && len(merged.Remove) == 0 && len(merged.HostRewrite) == 0 {
+		return nil, nil
+	}
+
+	return merged, nil
+}
+
 func headersPolicyRoute(policy *contour_api_v1.HeadersPolicy, allowHostRewrite bool, dynamicHeaders map[string]string) (*HeadersPolicy, error) {
 	if policy == nil {
 		return nil, nil
@@ -210,8 +320,10 @@ func headersPolicyRoute(policy *contour_api_v1.HeadersPolicy, allowHostRewrite b
 }
 
 // headersPolicyGatewayAPI builds a *HeaderPolicy for the supplied HTTPHeaderFilter.
+// Header values are escaped for Envoy, except for known Envoy command operators
+// and the Contour dynamic values in dynamicHeaders, which are substituted.
 // TODO: Take care about the order of operators once https://github.com/kubernetes-sigs/gateway-api/issues/480 was solved.
-func headersPolicyGatewayAPI(hf *gatewayapi_v1beta1.HTTPHeaderFilter, headerPolicyType string) (*HeadersPolicy, error) {
+func headersPolicyGatewayAPI(hf *gatewayapi_v1beta1.HTTPHeaderFilter, headerPolicyType string, dynamicHeaders map[string]string) (*HeadersPolicy, error) {
 	var (
 		remove      = sets.NewString()
 		hostRewrite = ""
@@ -236,7 +348,7 @@ func headersPolicyGatewayAPI(hf *gatewayapi_v1beta1.HTTPHeaderFilter, headerPoli
 				errlist = append(errlist, fmt.Errorf("invalid %s header %q: %v", op, key, msgs))
 				continue
 			}
-			m[key] = escapeHeaderValue(header.Value, nil)
+			m[key] = escapeHeaderValue(header.Value, dynamicHeaders)
 		}
 		return m
 	}
@@ -497,6 +609,21 @@ func loadBalancerPolicy(lbp *contour_api_v1.LoadBalancerPolicy) string {
 	}
 }
 
+// defaultLoadBalancerPolicy returns strategy, the strategy resolved
+// from lbp, unless lbp sets no strategy, in which case defaultPolicy
+// is returned. An explicit RoundRobin strategy is kept, so that it
+// overrides a different default.
+func defaultLoadBalancerPolicy(lbp *contour_api_v1.LoadBalancerPolicy, strategy, defaultPolicy string) string {
+	switch {
+	case strategy != "", defaultPolicy == "", defaultPolicy == LoadBalancerPolicyRoundRobin:
+		return strategy
+	case lbp != nil && lbp.Strategy == LoadBalancerPolicyRoundRobin:
+		return LoadBalancerPolicyRoundRobin
+	default:
+		return defaultPolicy
+	}
+}
+
 func prefixReplacementsAreValid(replacements []contour_api_v1.ReplacePrefix) (string, error) {
 	prefixes := map[string]bool{}
 
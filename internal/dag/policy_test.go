@@ -25,6 +25,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	networking_v1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
 func TestRetryPolicyIngress(t *testing.T) {
@@ -191,6 +192,67 @@ func TestRetryPolicy(t *testing.T) {
 	}
 }
 
+func TestRetryBudget(t *testing.T) {
+	tests := map[string]struct {
+		rp      *contour_api_v1.RetryPolicy
+		want    *RetryBudget
+		wantErr bool
+	}{
+		"nil retry policy": {
+			rp:   nil,
+			want: nil,
+		},
+		"no budget": {
+			rp:   &contour_api_v1.RetryPolicy{NumRetries: 3},
+			want: nil,
+		},
+		"budget": {
+			rp: &contour_api_v1.RetryPolicy{
+				NumRetries: 3,
+				Budget: &contour_api_v1.RetryBudget{
+					Percent:             20,
+					MinRetryConcurrency: 5,
+				},
+			},
+			want: &RetryBudget{
+				Percent:             20,
+				MinRetryConcurrency: 5,
+			},
+		},
+		"budget with default retry count": {
+			rp: &contour_api_v1.RetryPolicy{
+				Budget: &contour_api_v1.RetryBudget{Percent: 100},
+			},
+			want: &RetryBudget{Percent: 100},
+		},
+		"budget with retries disabled": {
+			rp: &contour_api_v1.RetryPolicy{
+				NumRetries: -1,
+				Budget:     &contour_api_v1.RetryBudget{Percent: 20},
+			},
+			wantErr: true,
+		},
+		"budget percent out of range": {
+			rp: &contour_api_v1.RetryPolicy{
+				Budget: &contour_api_v1.RetryBudget{Percent: 101},
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := retryBudget(tc.rp)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
 func TestTimeoutPolicy(t *testing.T) {
 	tests := map[string]struct {
 		tp                       *contour_api_v1.TimeoutPolicy
@@ -1249,6 +1311,36 @@ func TestValidateHeaderAlteration(t *testing.T) {
 				"K-Foo": "100%%",
 			},
 		},
+	}, {
+		name: "default added headers are skipped when the header is set",
+		in: &contour_api_v1.HeadersPolicy{
+			Set: []contour_api_v1.HeaderValue{{
+				Name:  "K-Foo",
+				Value: "bar",
+			}},
+		},
+		dhp: &HeadersPolicy{
+			Add: map[string]string{
+				"k-foo": "baz",
+				"k-bar": "100%",
+			},
+		},
+		want: &HeadersPolicy{
+			Set: map[string]string{
+				"K-Foo": "bar",
+			},
+			Add: map[string]string{
+				"K-Bar": "100%%",
+			},
+		},
+	}, {
+		name: "invalid default added header",
+		dhp: &HeadersPolicy{
+			Add: map[string]string{
+				"K-Foo!": "bar",
+			},
+		},
+		wantErr: errors.New(`invalid add header "K-Foo!": [a valid HTTP header must consist of alphanumeric characters or '-' (e.g. 'X-Header-Name', regex used for validation is '[-A-Za-z0-9]+')]`),
 	}}
 
 	for _, test := range tests {
@@ -1259,3 +1351,133 @@ func TestValidateHeaderAlteration(t *testing.T) {
 		})
 	}
 }
+
+func TestHeadersPolicyWithDefaults(t *testing.T) {
+	tests := map[string]struct {
+		defaults         *HeadersPolicy
+		policy           *HeadersPolicy
+		allowHostRewrite bool
+		want             *HeadersPolicy
+		wantErr          error
+	}{
+		"no defaults": {
+			policy: &HeadersPolicy{Set: map[string]string{"K-Foo": "bar"}},
+			want:   &HeadersPolicy{Set: map[string]string{"K-Foo": "bar"}},
+		},
+		"empty defaults keep a nil policy": {
+			defaults: &HeadersPolicy{},
+			want:     nil,
+		},
+		"defaults apply to a nil policy": {
+			defaults: &HeadersPolicy{
+				Set:    map[string]string{"strict-transport-security": "max-age=31536000"},
+				Add:    map[string]string{"x-content-type-options": "nosniff"},
+				Remove: []string{"server"},
+			},
+			want: &HeadersPolicy{
+				Set:    map[string]string{"Strict-Transport-Security": "max-age=31536000"},
+				Add:    map[string]string{"X-Content-Type-Options": "nosniff"},
+				Remove: []string{"Server"},
+			},
+		},
+		"headers modified by the policy are not overridden": {
+			defaults: &HeadersPolicy{
+				Set:    map[string]string{"K-Foo": "default", "K-Bar": "default"},
+				Add:    map[string]string{"K-Baz": "default"},
+				Remove: []string{"K-Qux", "K-Foo"},
+			},
+			policy: &HeadersPolicy{
+				Set:    map[string]string{"K-Foo": "route"},
+				Remove: []string{"K-Baz"},
+				Add:    map[string]string{"K-Qux": "route"},
+			},
+			want: &HeadersPolicy{
+				Set:    map[string]string{"K-Foo": "route", "K-Bar": "default"},
+				Add:    map[string]string{"K-Qux": "route"},
+				Remove: []string{"K-Baz"},
+			},
+		},
+		"default host rewrite": {
+			defaults:         &HeadersPolicy{Set: map[string]string{"host": "example.com"}},
+			allowHostRewrite: true,
+			want:             &HeadersPolicy{HostRewrite: "example.com"},
+		},
+		"default host rewrite does not replace the policy": {
+			defaults:         &HeadersPolicy{Set: map[string]string{"Host": "example.com"}},
+			policy:           &HeadersPolicy{HostRewrite: "route.example.com"},
+			allowHostRewrite: true,
+			want:             &HeadersPolicy{HostRewrite: "route.example.com"},
+		},
+		"default host rewrite not allowed": {
+			defaults: &HeadersPolicy{Set: map[string]string{"Host": "example.com"}},
+			wantErr:  errors.New(`rewriting "Host" header is not supported`),
+		},
+		"invalid default header": {
+			defaults: &HeadersPolicy{Remove: []string{"K-Foo!"}},
+			wantErr:  errors.New(`invalid remove header "K-Foo!": [a valid HTTP header must consist of alphanumeric characters or '-' (e.g. 'X-Header-Name', regex used for validation is '[-A-Za-z0-9]+')]`),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, gotErr := headersPolicyWithDefaults(tc.defaults, tc.policy, tc.allowHostRewrite)
+			assert.Equal(t, tc.wantErr, gotErr)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestHeadersPolicyGatewayAPI(t *testing.T) {
+	dynamicHeaders := map[string]string{
+		"CONTOUR_NAMESPACE":    "default",
+		"CONTOUR_SERVICE_NAME": "backend",
+	}
+
+	tests := map[string]struct {
+		value string
+		want  string
+	}{
+		"literal value": {
+			value: "static",
+			want:  "static",
+		},
+		"literal percent is escaped": {
+			value: "100%",
+			want:  "100%%",
+		},
+		"unknown variable is escaped": {
+			value: "%NOT_A_VARIABLE%",
+			want:  "%%NOT_A_VARIABLE%%",
+		},
+		"envoy command operator passes through": {
+			value: "%DOWNSTREAM_REMOTE_ADDRESS%",
+			want:  "%DOWNSTREAM_REMOTE_ADDRESS%",
+		},
+		"envoy request header operator passes through": {
+			value: "%REQ(x-request-id)%",
+			want:  "%REQ(x-request-id)%",
+		},
+		"contour dynamic values are substituted": {
+			value: "%CONTOUR_SERVICE_NAME%.%CONTOUR_NAMESPACE%",
+			want:  "backend.default",
+		},
+		"mixed literal and dynamic values": {
+			value: "client=%DOWNSTREAM_REMOTE_ADDRESS_WITHOUT_PORT%; share=5%",
+			want:  "client=%DOWNSTREAM_REMOTE_ADDRESS_WITHOUT_PORT%; share=5%%",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := headersPolicyGatewayAPI(&gatewayapi_v1beta1.HTTPHeaderFilter{
+				Set: []gatewayapi_v1beta1.HTTPHeader{{Name: "X-Set", Value: tc.value}},
+				Add: []gatewayapi_v1beta1.HTTPHeader{{Name: "X-Add", Value: tc.value}},
+			}, string(gatewayapi_v1beta1.HTTPRouteFilterRequestHeaderModifier), dynamicHeaders)
+			assert.NoError(t, err)
+			assert.Equal(t, &HeadersPolicy{
+				Set: map[string]string{"X-Set": tc.want},
+				Add: map[string]string{"X-Add": tc.want},
+			}, got)
+		})
+	}
+}
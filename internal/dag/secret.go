@@ -30,6 +30,10 @@ const (
 
 	// CRLKey is the key name for accessing CRL bundles in Kubernetes Secrets.
 	CRLKey = "crl.pem"
+
+	// OCSPStapleKey is the key name for accessing the DER encoded OCSP
+	// response to staple to a TLS certificate in Kubernetes Secrets.
+	OCSPStapleKey = "tls.ocsp-staple"
 )
 
 // validTLSSecret returns an error if the Secret is not of type TLS or Opaque or
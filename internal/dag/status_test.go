@@ -1161,6 +1161,80 @@ func TestDAGStatus(t *testing.T) {
 		},
 	})
 
+	proxyInvalidSourceFilter := &contour_api_v1.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "example",
+		},
+		Spec: contour_api_v1.HTTPProxySpec{
+			VirtualHost: &contour_api_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []contour_api_v1.Route{{
+				Conditions: []contour_api_v1.MatchCondition{{
+					Source: &contour_api_v1.SourceFilter{
+						CIDRs: []string{"10.0.0.0/40"},
+					},
+				}},
+				Services: []contour_api_v1.Service{{
+					Name: "home",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	run(t, "invalid route source condition", testcase{
+		objs: []interface{}{proxyInvalidSourceFilter, fixture.ServiceRootsHome},
+		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
+			{Name: proxyInvalidSourceFilter.Name, Namespace: proxyInvalidSourceFilter.Namespace}: fixture.NewValidCondition().
+				WithGeneration(proxyInvalidSourceFilter.Generation).
+				WithError(contour_api_v1.ConditionTypeRouteError, "SourceFiltersNotValid", `invalid source CIDR "10.0.0.0/40"`),
+		},
+	})
+
+	proxyInvalidConflictingSourceFilters := &contour_api_v1.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "example",
+		},
+		Spec: contour_api_v1.HTTPProxySpec{
+			VirtualHost: &contour_api_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []contour_api_v1.Route{{
+				Conditions: []contour_api_v1.MatchCondition{{
+					Prefix: "/admin",
+				}, {
+					Source: &contour_api_v1.SourceFilter{
+						CIDRs: []string{"10.0.0.0/8"},
+					},
+				}},
+				Services: []contour_api_v1.Service{{
+					Name: "home",
+					Port: 8080,
+				}},
+			}, {
+				Conditions: []contour_api_v1.MatchCondition{{
+					Prefix: "/admin",
+				}},
+				Services: []contour_api_v1.Service{{
+					Name: "home",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	run(t, "routes that only differ by source filters", testcase{
+		objs: []interface{}{proxyInvalidConflictingSourceFilters, fixture.ServiceRootsHome},
+		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
+			{Name: proxyInvalidConflictingSourceFilters.Name, Namespace: proxyInvalidConflictingSourceFilters.Namespace}: fixture.NewValidCondition().
+				WithGeneration(proxyInvalidConflictingSourceFilters.Generation).
+				WithError(contour_api_v1.ConditionTypeRouteError, "SourceFiltersConflict", "routes with the same match conditions must not differ only by their source filters"),
+		},
+	})
+
 	proxyValidDelegatedRoots := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: "roots",
@@ -1643,6 +1717,57 @@ func TestDAGStatus(t *testing.T) {
 		},
 	})
 
+	proxyInvalidConflictingIncludeSourceFilters := &contour_api_v1.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "example",
+		},
+		Spec: contour_api_v1.HTTPProxySpec{
+			VirtualHost: &contour_api_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Includes: []contour_api_v1.Include{{
+				Name:      "blogteama",
+				Namespace: "teama",
+				Conditions: []contour_api_v1.MatchCondition{{
+					Prefix: "/blog",
+				}, {
+					Source: &contour_api_v1.SourceFilter{
+						CIDRs: []string{"10.0.0.0/8"},
+					},
+				}},
+			}, {
+				Name:      "blogteamb",
+				Namespace: "teamb",
+				Conditions: []contour_api_v1.MatchCondition{{
+					Prefix: "/blog",
+				}},
+			}},
+			Routes: []contour_api_v1.Route{{
+				Conditions: []contour_api_v1.MatchCondition{{
+					Prefix: "/",
+				}},
+				Services: []contour_api_v1.Service{{
+					Name: "home",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	run(t, "includes that only differ by source filters", testcase{
+		objs: []interface{}{proxyInvalidConflictingIncludeSourceFilters, proxyValidBlogTeamA, proxyValidBlogTeamB, fixture.ServiceRootsHome, fixture.ServiceTeamAKuard, fixture.ServiceTeamBKuard},
+		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
+			{Name: proxyValidBlogTeamA.Name, Namespace: proxyValidBlogTeamA.Namespace}: fixture.NewValidCondition().
+				Valid(),
+			{Name: proxyValidBlogTeamB.Name, Namespace: proxyValidBlogTeamB.Namespace}: fixture.NewValidCondition().
+				Orphaned(),
+			{Name: proxyInvalidConflictingIncludeSourceFilters.Name,
+				Namespace: proxyInvalidConflictingIncludeSourceFilters.Namespace}: fixture.NewValidCondition().
+				WithError(contour_api_v1.ConditionTypeIncludeError, "DuplicateMatchConditions", "duplicate conditions defined on an include"),
+		},
+	})
+
 	proxyIncludeConditionsEmpty := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: "roots",
@@ -2290,745 +2415,609 @@ func TestDAGStatus(t *testing.T) {
 		},
 	})
 
-	proxyInvalidMissingServiceWithTCPProxy := &contour_api_v1.HTTPProxy{
+	proxyRequireTLSMissingTLS := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "missing-route-service",
+			Name:      "require-tls-missing-tls",
 			Namespace: fixture.ServiceRootsKuard.Namespace,
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
-				Fqdn: "tcpproxy.example.com",
-				TLS: &contour_api_v1.TLS{
-					SecretName: fixture.SecretRootsCert.Name,
-				},
+				Fqdn:       "example.com",
+				RequireTLS: true,
 			},
 			Routes: []contour_api_v1.Route{{
-				Services: []contour_api_v1.Service{
-					{Name: "missing", Port: 9000},
-				},
-			}},
-			TCPProxy: &contour_api_v1.TCPProxy{
 				Services: []contour_api_v1.Service{{
 					Name: fixture.ServiceRootsKuard.Name,
 					Port: 8080,
 				}},
-			},
+			}},
 		},
 	}
 
-	run(t, "httpproxy w/ tcpproxy missing service", testcase{
-		objs: []interface{}{fixture.SecretRootsCert, fixture.ServiceRootsKuard, proxyInvalidMissingServiceWithTCPProxy},
+	run(t, "httpproxy w/ requireTLS missing tls", testcase{
+		objs: []interface{}{proxyRequireTLSMissingTLS, fixture.ServiceRootsKuard},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			{Name: proxyInvalidMissingServiceWithTCPProxy.Name, Namespace: proxyInvalidMissingServiceWithTCPProxy.Namespace}: fixture.NewValidCondition().
-				WithError(contour_api_v1.ConditionTypeServiceError, "ServiceUnresolvedReference", `Spec.Routes unresolved service reference: service "roots/missing" not found`),
+			{Name: proxyRequireTLSMissingTLS.Name, Namespace: proxyRequireTLSMissingTLS.Namespace}: fixture.NewValidCondition().
+				WithError(contour_api_v1.ConditionTypeTLSError, "TLSMustBeConfigured", "Spec.VirtualHost.RequireTLS requires that either Spec.TLS.Passthrough or Spec.TLS.SecretName be set"),
 		},
 	})
 
-	proxyRoutePortNotMatchedWithTCP := &contour_api_v1.HTTPProxy{
+	proxyHSTSMissingTLS := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "missing-route-service-port",
+			Name:      "hsts-missing-tls",
 			Namespace: fixture.ServiceRootsKuard.Namespace,
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
-				Fqdn: "tcpproxy.example.com",
-				TLS: &contour_api_v1.TLS{
-					SecretName: fixture.SecretRootsCert.Name,
+				Fqdn: "example.com",
+				HSTSPolicy: &contour_api_v1.HSTSPolicy{
+					MaxAge: "24h",
 				},
 			},
 			Routes: []contour_api_v1.Route{{
-				Services: []contour_api_v1.Service{
-					{Name: fixture.ServiceRootsKuard.Name, Port: 9999},
-				},
-			}},
-			TCPProxy: &contour_api_v1.TCPProxy{
 				Services: []contour_api_v1.Service{{
 					Name: fixture.ServiceRootsKuard.Name,
 					Port: 8080,
 				}},
-			},
+			}},
 		},
 	}
 
-	run(t, "tcpproxy route unmatched service port", testcase{
-		objs: []interface{}{fixture.SecretRootsCert, fixture.ServiceRootsKuard, proxyRoutePortNotMatchedWithTCP},
+	run(t, "httpproxy w/ hstsPolicy missing tls", testcase{
+		objs: []interface{}{proxyHSTSMissingTLS, fixture.ServiceRootsKuard},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			{Name: proxyRoutePortNotMatchedWithTCP.Name, Namespace: proxyRoutePortNotMatchedWithTCP.Namespace}: fixture.NewValidCondition().
-				WithError(contour_api_v1.ConditionTypeServiceError, "ServiceUnresolvedReference", `Spec.Routes unresolved service reference: port "9999" on service "roots/kuard" not matched`),
+			{Name: proxyHSTSMissingTLS.Name, Namespace: proxyHSTSMissingTLS.Namespace}: fixture.NewValidCondition().
+				WithError(contour_api_v1.ConditionTypeTLSError, "TLSMustBeConfigured", "Spec.VirtualHost.HSTSPolicy requires that either Spec.TLS.Passthrough or Spec.TLS.SecretName be set"),
 		},
 	})
 
-	proxyTCPValidIncludeChild := &contour_api_v1.HTTPProxy{
+	proxyHSTSInvalidPreload := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "validtcpproxy",
+			Name:      "hsts-invalid-preload",
 			Namespace: fixture.ServiceRootsKuard.Namespace,
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
-				Fqdn: "tcpproxy.example.com",
+				Fqdn: "example.com",
 				TLS: &contour_api_v1.TLS{
 					SecretName: fixture.SecretRootsCert.Name,
 				},
-			},
-			TCPProxy: &contour_api_v1.TCPProxy{
-				Include: &contour_api_v1.TCPProxyInclude{
-					Name:      "child",
-					Namespace: fixture.ServiceRootsKuard.Namespace,
+				HSTSPolicy: &contour_api_v1.HSTSPolicy{
+					MaxAge:  "24h",
+					Preload: true,
 				},
 			},
+			Routes: []contour_api_v1.Route{{
+				Services: []contour_api_v1.Service{{
+					Name: fixture.ServiceRootsKuard.Name,
+					Port: 8080,
+				}},
+			}},
 		},
 	}
 
-	proxyTCPValidIncludesChild := &contour_api_v1.HTTPProxy{
+	run(t, "httpproxy w/ invalid hstsPolicy", testcase{
+		objs: []interface{}{proxyHSTSInvalidPreload, fixture.SecretRootsCert, fixture.ServiceRootsKuard},
+		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
+			{Name: proxyHSTSInvalidPreload.Name, Namespace: proxyHSTSInvalidPreload.Namespace}: fixture.NewValidCondition().
+				WithError(contour_api_v1.ConditionTypeTLSError, "HSTSPolicyNotValid", "Spec.VirtualHost.HSTSPolicy is invalid: preload requires includeSubDomains to be set"),
+		},
+	})
+
+	proxyInvalidVirtualHostCSP := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "validtcpproxy",
+			Name:      "invalid-vhost-csp",
 			Namespace: fixture.ServiceRootsKuard.Namespace,
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
-				Fqdn: "tcpproxy.example.com",
-				TLS: &contour_api_v1.TLS{
-					SecretName: fixture.SecretRootsCert.Name,
-				},
-			},
-			TCPProxy: &contour_api_v1.TCPProxy{
-				IncludesDeprecated: &contour_api_v1.TCPProxyInclude{
-					Name:      "child",
-					Namespace: fixture.ServiceRootsKuard.Namespace,
+				Fqdn: "example.com",
+				CSPPolicy: &contour_api_v1.CSPPolicy{
+					Directives: " ",
 				},
 			},
+			Routes: []contour_api_v1.Route{{
+				Services: []contour_api_v1.Service{{
+					Name: fixture.ServiceRootsKuard.Name,
+					Port: 8080,
+				}},
+			}},
 		},
 	}
 
-	proxyTCPValidChild := &contour_api_v1.HTTPProxy{
+	run(t, "httpproxy w/ invalid virtualhost cspPolicy", testcase{
+		objs: []interface{}{proxyInvalidVirtualHostCSP, fixture.ServiceRootsKuard},
+		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
+			{Name: proxyInvalidVirtualHostCSP.Name, Namespace: proxyInvalidVirtualHostCSP.Namespace}: fixture.NewValidCondition().
+				WithError(contour_api_v1.ConditionTypeVirtualHostError, "CSPPolicyNotValid", "Spec.VirtualHost.CSPPolicy is invalid: directives must not be empty"),
+		},
+	})
+
+	proxyInvalidRouteCSP := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "child",
+			Name:      "invalid-route-csp",
 			Namespace: fixture.ServiceRootsKuard.Namespace,
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
-			TCPProxy: &contour_api_v1.TCPProxy{
+			VirtualHost: &contour_api_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []contour_api_v1.Route{{
+				CSPPolicy: &contour_api_v1.CSPPolicy{
+					Directives: "\t",
+				},
 				Services: []contour_api_v1.Service{{
 					Name: fixture.ServiceRootsKuard.Name,
 					Port: 8080,
 				}},
-			},
+			}},
 		},
 	}
 
-	run(t, "valid HTTPProxy.TCPProxy - plural", testcase{
-		objs: []interface{}{proxyTCPValidIncludesChild, proxyTCPValidChild, fixture.ServiceRootsKuard, fixture.SecretRootsCert},
-		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			{Name: proxyTCPValidIncludesChild.Name,
-				Namespace: proxyTCPValidIncludesChild.Namespace}: fixture.NewValidCondition().Valid(),
-			{Name: proxyTCPValidChild.Name,
-				Namespace: proxyTCPValidChild.Namespace}: fixture.NewValidCondition().Valid(),
-		},
-	})
-
-	run(t, "valid HTTPProxy.TCPProxy", testcase{
-		objs: []interface{}{proxyTCPValidIncludeChild, proxyTCPValidChild, fixture.ServiceRootsKuard, fixture.SecretRootsCert},
+	run(t, "httpproxy w/ invalid route cspPolicy", testcase{
+		objs: []interface{}{proxyInvalidRouteCSP, fixture.ServiceRootsKuard},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			{Name: proxyTCPValidIncludeChild.Name,
-				Namespace: proxyTCPValidIncludeChild.Namespace}: fixture.NewValidCondition().Valid(),
-			{Name: proxyTCPValidChild.Name,
-				Namespace: proxyTCPValidChild.Namespace}: fixture.NewValidCondition().Valid(),
+			{Name: proxyInvalidRouteCSP.Name, Namespace: proxyInvalidRouteCSP.Namespace}: fixture.NewValidCondition().
+				WithError(contour_api_v1.ConditionTypeRouteError, "CSPPolicyNotValid", "route.cspPolicy is invalid: directives must not be empty"),
 		},
 	})
 
-	// issue 2309
-	proxyInvalidNoServices := &contour_api_v1.HTTPProxy{
+	proxyIsolatedConnectionPoolNoServices := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "missing-service",
+			Name:      "isolated-connection-pool-no-services",
 			Namespace: fixture.ServiceRootsKuard.Namespace,
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
-				Fqdn: "missing-service.example.com",
+				Fqdn: "example.com",
 			},
 			Routes: []contour_api_v1.Route{{
-				Conditions: []contour_api_v1.MatchCondition{{
-					Prefix: "/",
-				}},
-				Services: nil, // missing
+				IsolateConnectionPool: true,
+				DirectResponsePolicy: &contour_api_v1.HTTPDirectResponsePolicy{
+					StatusCode: 200,
+				},
 			}},
 		},
 	}
 
-	run(t, "No routeAction specified is invalid", testcase{
-		objs: []interface{}{proxyInvalidNoServices, fixture.ServiceRootsKuard},
+	run(t, "httpproxy w/ isolated connection pool and no services", testcase{
+		objs: []interface{}{proxyIsolatedConnectionPoolNoServices},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			{Name: proxyInvalidNoServices.Name, Namespace: proxyInvalidNoServices.Namespace}: fixture.NewValidCondition().
-				WithError(contour_api_v1.ConditionTypeRouteError, "RouteActionCountNotValid", "must set exactly one of route.services or route.requestRedirectPolicy or route.directResponsePolicy"),
+			{Name: proxyIsolatedConnectionPoolNoServices.Name, Namespace: proxyIsolatedConnectionPoolNoServices.Namespace}: fixture.NewValidCondition().
+				WithError(contour_api_v1.ConditionTypeRouteError, "ConnectionPoolIsolationNotValid", "route.isolateConnectionPool requires at least one service"),
 		},
 	})
 
-	fallbackCertificate := &contour_api_v1.HTTPProxy{
+	proxyIgnoreNewHostsWithoutHealthCheck := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: "roots",
-			Name:      "example",
+			Name:      "ignore-new-hosts-without-healthcheck",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
 				Fqdn: "example.com",
-				TLS: &contour_api_v1.TLS{
-					SecretName:                "ssl-cert",
-					EnableFallbackCertificate: true,
-				},
 			},
 			Routes: []contour_api_v1.Route{{
-				Conditions: []contour_api_v1.MatchCondition{{
-					Prefix: "/foo",
-				}},
 				Services: []contour_api_v1.Service{{
-					Name: "home",
-					Port: 8080,
+					Name:                       fixture.ServiceRootsKuard.Name,
+					Port:                       8080,
+					IgnoreNewHostsUntilFirstHC: true,
 				}},
 			}},
 		},
 	}
 
-	run(t, "invalid fallback certificate passed to contour", testcase{
-		fallbackCertificate: &types.NamespacedName{
-			Name:      "invalid",
-			Namespace: "invalid",
-		},
-		objs: []interface{}{fallbackCertificate, fixture.SecretRootsFallback, fixture.SecretRootsCert, fixture.ServiceRootsHome},
-		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			{Name: fallbackCertificate.Name,
-				Namespace: fallbackCertificate.Namespace}: fixture.NewValidCondition().
-				WithError(contour_api_v1.ConditionTypeTLSError, "FallbackNotValid", `Spec.Virtualhost.TLS Secret "invalid/invalid" fallback certificate is invalid: Secret not found`),
-		},
-	})
-
-	run(t, "fallback certificate requested but cert not configured in contour", testcase{
-		objs: []interface{}{fallbackCertificate, fixture.SecretRootsFallback, fixture.SecretRootsCert, fixture.ServiceRootsHome},
+	run(t, "httpproxy w/ ignoreNewHostsUntilFirstHC and no health check policy", testcase{
+		objs: []interface{}{proxyIgnoreNewHostsWithoutHealthCheck, fixture.ServiceRootsKuard},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			{Name: fallbackCertificate.Name,
-				Namespace: fallbackCertificate.Namespace}: fixture.NewValidCondition().
-				WithError(contour_api_v1.ConditionTypeTLSError, "FallbackNotPresent", "Spec.Virtualhost.TLS enabled fallback but the fallback Certificate Secret is not configured in Contour configuration file"),
+			{Name: proxyIgnoreNewHostsWithoutHealthCheck.Name, Namespace: proxyIgnoreNewHostsWithoutHealthCheck.Namespace}: fixture.NewValidCondition().
+				WithError(contour_api_v1.ConditionTypeServiceError, "IgnoreNewHostsUntilFirstHCInvalid", `service "kuard": ignoreNewHostsUntilFirstHC requires a health check policy`),
 		},
 	})
 
-	fallbackCertificateWithClientValidationNoCA := &contour_api_v1.HTTPProxy{
+	proxyTCPIgnoreNewHostsWithoutHealthCheck := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: "roots",
-			Name:      "example",
+			Name:      "tcp-ignore-new-hosts-without-healthcheck",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
-				Fqdn: "example.com",
+				Fqdn: "tcpproxy.example.com",
 				TLS: &contour_api_v1.TLS{
-					SecretName:       "ssl-cert",
-					ClientValidation: &contour_api_v1.DownstreamValidation{},
+					Passthrough: true,
 				},
 			},
-			Routes: []contour_api_v1.Route{{
-				Conditions: []contour_api_v1.MatchCondition{{
-					Prefix: "/foo",
-				}},
+			TCPProxy: &contour_api_v1.TCPProxy{
 				Services: []contour_api_v1.Service{{
-					Name: "home",
-					Port: 8080,
+					Name:                       fixture.ServiceRootsKuard.Name,
+					Port:                       8080,
+					IgnoreNewHostsUntilFirstHC: true,
 				}},
-			}},
+			},
 		},
 	}
 
-	run(t, "clientValidation missing CA", testcase{
-		objs: []interface{}{fallbackCertificateWithClientValidationNoCA, fixture.SecretRootsFallback, fixture.SecretRootsCert, fixture.ServiceRootsHome},
+	run(t, "tcpproxy w/ ignoreNewHostsUntilFirstHC and no health check policy", testcase{
+		objs: []interface{}{proxyTCPIgnoreNewHostsWithoutHealthCheck, fixture.ServiceRootsKuard},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			{Name: fallbackCertificateWithClientValidationNoCA.Name,
-				Namespace: fallbackCertificateWithClientValidationNoCA.Namespace}: fixture.NewValidCondition().
-				WithError(contour_api_v1.ConditionTypeTLSError, "ClientValidationInvalid", "Spec.VirtualHost.TLS client validation is invalid: CA Secret must be specified"),
+			{Name: proxyTCPIgnoreNewHostsWithoutHealthCheck.Name, Namespace: proxyTCPIgnoreNewHostsWithoutHealthCheck.Namespace}: fixture.NewValidCondition().
+				WithError(contour_api_v1.ConditionTypeServiceError, "IgnoreNewHostsUntilFirstHCInvalid", `service "kuard": ignoreNewHostsUntilFirstHC requires a health check policy`),
 		},
 	})
 
-	fallbackCertificateWithClientValidation := &contour_api_v1.HTTPProxy{
+	proxyInvalidAltStatName := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: "roots",
-			Name:      "example",
+			Name:      "invalid-alt-stat-name",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
 				Fqdn: "example.com",
-				TLS: &contour_api_v1.TLS{
-					SecretName:                "ssl-cert",
-					EnableFallbackCertificate: true,
-					ClientValidation: &contour_api_v1.DownstreamValidation{
-						CACertificate: "something",
-					},
-				},
 			},
 			Routes: []contour_api_v1.Route{{
-				Conditions: []contour_api_v1.MatchCondition{{
-					Prefix: "/foo",
-				}},
 				Services: []contour_api_v1.Service{{
-					Name: "home",
-					Port: 8080,
+					Name:        fixture.ServiceRootsKuard.Name,
+					Port:        8080,
+					AltStatName: "kuard.stable",
 				}},
 			}},
 		},
 	}
 
-	run(t, "fallback certificate requested and clientValidation also configured", testcase{
-		objs: []interface{}{fallbackCertificateWithClientValidation, fixture.SecretRootsFallback, fixture.SecretRootsCert, fixture.ServiceRootsHome},
+	run(t, "httpproxy w/ invalid altStatName", testcase{
+		objs: []interface{}{proxyInvalidAltStatName, fixture.ServiceRootsKuard},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			{Name: fallbackCertificateWithClientValidation.Name,
-				Namespace: fallbackCertificateWithClientValidation.Namespace}: fixture.NewValidCondition().
-				WithError(contour_api_v1.ConditionTypeTLSError, "TLSIncompatibleFeatures", "Spec.Virtualhost.TLS fallback & client validation are incompatible"),
+			{Name: proxyInvalidAltStatName.Name, Namespace: proxyInvalidAltStatName.Namespace}: fixture.NewValidCondition().
+				WithError(contour_api_v1.ConditionTypeServiceError, "AltStatNameInvalid", `service "kuard": altStatName "kuard.stable" must be at most 60 characters and only contain alphanumerics, '-' and '_'`),
 		},
 	})
 
-	tlsPassthroughAndValidation := &contour_api_v1.HTTPProxy{
+	proxyServicePrioritySkipsLevel := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "invalid",
+			Name:      "service-priority-skips-level",
 			Namespace: fixture.ServiceRootsKuard.Namespace,
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
-				Fqdn: "tcpproxy.example.com",
-				TLS: &contour_api_v1.TLS{
-					Passthrough: true,
-					ClientValidation: &contour_api_v1.DownstreamValidation{
-						CACertificate: "aCAcert",
-					},
-				},
+				Fqdn: "example.com",
 			},
-			TCPProxy: &contour_api_v1.TCPProxy{},
+			Routes: []contour_api_v1.Route{{
+				Services: []contour_api_v1.Service{{
+					Name: fixture.ServiceRootsKuard.Name,
+					Port: 8080,
+				}, {
+					Name:     fixture.ServiceRootsKuard.Name,
+					Port:     8080,
+					Priority: 2,
+				}},
+			}},
 		},
 	}
 
-	run(t, "passthrough and client auth are incompatible tlsPassthroughAndValidation", testcase{
-		objs: []interface{}{fixture.SecretRootsCert, tlsPassthroughAndValidation},
+	run(t, "httpproxy w/ service priorities that skip a level", testcase{
+		objs: []interface{}{proxyServicePrioritySkipsLevel, fixture.ServiceRootsKuard},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			{Name: tlsPassthroughAndValidation.Name, Namespace: tlsPassthroughAndValidation.Namespace}: fixture.NewValidCondition().
-				WithError(contour_api_v1.ConditionTypeTLSError, "TLSIncompatibleFeatures", "Spec.VirtualHost.TLS passthrough cannot be combined with tls.clientValidation"),
+			{Name: proxyServicePrioritySkipsLevel.Name, Namespace: proxyServicePrioritySkipsLevel.Namespace}: fixture.NewValidCondition().
+				WithError(contour_api_v1.ConditionTypeServiceError, "ServicePriorityInvalid", "service priorities must be contiguous starting from 0, missing priority 1"),
 		},
 	})
 
-	tlsPassthroughAndSecretName := &contour_api_v1.HTTPProxy{
+	proxyServicePriorityNoPrimary := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "invalid",
+			Name:      "service-priority-no-primary",
 			Namespace: fixture.ServiceRootsKuard.Namespace,
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
-				Fqdn: "tcpproxy.example.com",
-				TLS: &contour_api_v1.TLS{
-					Passthrough: true,
-					SecretName:  fixture.SecretRootsCert.Name,
-				},
+				Fqdn: "example.com",
 			},
-			TCPProxy: &contour_api_v1.TCPProxy{},
+			Routes: []contour_api_v1.Route{{
+				Services: []contour_api_v1.Service{{
+					Name:     fixture.ServiceRootsKuard.Name,
+					Port:     8080,
+					Priority: 1,
+				}},
+			}},
 		},
 	}
 
-	run(t, "tcpproxy with TLS passthrough and secret name both specified", testcase{
-		objs: []interface{}{
-			fixture.SecretRootsCert,
-			tlsPassthroughAndSecretName,
-		},
+	run(t, "httpproxy w/ no priority 0 service", testcase{
+		objs: []interface{}{proxyServicePriorityNoPrimary, fixture.ServiceRootsKuard},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			{Name: "invalid", Namespace: fixture.ServiceRootsKuard.Namespace}: fixture.NewValidCondition().
-				WithError(contour_api_v1.ConditionTypeTLSError, "TLSConfigNotValid", "Spec.VirtualHost.TLS: both Passthrough and SecretName were specified"),
+			{Name: proxyServicePriorityNoPrimary.Name, Namespace: proxyServicePriorityNoPrimary.Namespace}: fixture.NewValidCondition().
+				WithError(contour_api_v1.ConditionTypeServiceError, "ServicePriorityInvalid", "service priorities must be contiguous starting from 0, missing priority 0"),
 		},
 	})
 
-	tlsNoPassthroughOrSecretName := &contour_api_v1.HTTPProxy{
+	proxyServicePriorityMirror := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "invalid",
+			Name:      "service-priority-mirror",
 			Namespace: fixture.ServiceRootsKuard.Namespace,
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
-				Fqdn: "tcpproxy.example.com",
-				TLS: &contour_api_v1.TLS{
-					Passthrough: false,
-					SecretName:  "",
-				},
+				Fqdn: "example.com",
 			},
-			TCPProxy: &contour_api_v1.TCPProxy{},
+			Routes: []contour_api_v1.Route{{
+				Services: []contour_api_v1.Service{{
+					Name: fixture.ServiceRootsKuard.Name,
+					Port: 8080,
+				}, {
+					Name:     fixture.ServiceRootsKuard.Name,
+					Port:     8080,
+					Mirror:   true,
+					Priority: 1,
+				}},
+			}},
 		},
 	}
 
-	run(t, "httpproxy w/ tcpproxy with neither TLS passthrough nor secret name specified", testcase{
-		objs: []interface{}{
-			fixture.SecretRootsCert,
-			tlsNoPassthroughOrSecretName,
-		},
+	run(t, "httpproxy w/ priority on a mirror service", testcase{
+		objs: []interface{}{proxyServicePriorityMirror, fixture.ServiceRootsKuard},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			{Name: "invalid", Namespace: fixture.ServiceRootsKuard.Namespace}: fixture.NewValidCondition().
-				WithError(contour_api_v1.ConditionTypeTLSError, "TLSConfigNotValid", "Spec.VirtualHost.TLS: neither Passthrough nor SecretName were specified"),
+			{Name: proxyServicePriorityMirror.Name, Namespace: proxyServicePriorityMirror.Namespace}: fixture.NewValidCondition().
+				WithError(contour_api_v1.ConditionTypeServiceError, "ServicePriorityInvalid", `service "kuard": priority cannot be set on a mirror service`),
 		},
 	})
 
-	emptyProxy := &contour_api_v1.HTTPProxy{
+	proxyInvalidUpstreamClusterHeader := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "empty",
-			Namespace: "roots",
+			Name:      "invalid-upstream-cluster-header",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
-				Fqdn: "example.com",
+				Fqdn:                  "example.com",
+				UpstreamClusterHeader: "x upstream cluster",
 			},
+			Routes: []contour_api_v1.Route{{
+				Services: []contour_api_v1.Service{{
+					Name: fixture.ServiceRootsKuard.Name,
+					Port: 8080,
+				}},
+			}},
 		},
 	}
 
-	run(t, "proxy with no routes, includes, or tcpproxy is invalid", testcase{
-		objs: []interface{}{emptyProxy},
+	run(t, "httpproxy w/ invalid upstreamClusterHeader", testcase{
+		objs: []interface{}{proxyInvalidUpstreamClusterHeader, fixture.ServiceRootsKuard},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			{Name: emptyProxy.Name, Namespace: emptyProxy.Namespace}: fixture.NewValidCondition().
-				WithError(contour_api_v1.ConditionTypeSpecError, "NothingDefined", "HTTPProxy.Spec must have at least one Route, Include, or a TCPProxy"),
+			{Name: proxyInvalidUpstreamClusterHeader.Name, Namespace: proxyInvalidUpstreamClusterHeader.Namespace}: fixture.NewValidCondition().
+				WithError(contour_api_v1.ConditionTypeVirtualHostError, "UpstreamClusterHeaderNotValid", `Spec.VirtualHost.UpstreamClusterHeader "x upstream cluster" is invalid: a valid HTTP header must consist of alphanumeric characters or '-' (e.g. 'X-Header-Name', regex used for validation is '[-A-Za-z0-9]+')`),
 		},
 	})
 
-	invalidResponseHeadersPolicyService := &contour_api_v1.HTTPProxy{
+	proxyInvalidMissingServiceWithTCPProxy := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "invalidRHPService",
+			Name:      "missing-route-service",
 			Namespace: fixture.ServiceRootsKuard.Namespace,
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
-				Fqdn: "example.com",
+				Fqdn: "tcpproxy.example.com",
+				TLS: &contour_api_v1.TLS{
+					SecretName: fixture.SecretRootsCert.Name,
+				},
 			},
 			Routes: []contour_api_v1.Route{{
 				Services: []contour_api_v1.Service{
-					{
-						Name: fixture.ServiceRootsKuard.Name,
-						Port: 8080,
-						ResponseHeadersPolicy: &contour_api_v1.HeadersPolicy{
-							Set: []contour_api_v1.HeaderValue{{
-								Name:  "Host",
-								Value: "external.com",
-							}},
-						},
-					},
+					{Name: "missing", Port: 9000},
 				},
 			}},
+			TCPProxy: &contour_api_v1.TCPProxy{
+				Services: []contour_api_v1.Service{{
+					Name: fixture.ServiceRootsKuard.Name,
+					Port: 8080,
+				}},
+			},
 		},
 	}
 
-	run(t, "responseHeadersPolicy, Host header invalid on Service", testcase{
-		objs: []interface{}{invalidResponseHeadersPolicyService, fixture.ServiceRootsKuard},
+	run(t, "httpproxy w/ tcpproxy missing service", testcase{
+		objs: []interface{}{fixture.SecretRootsCert, fixture.ServiceRootsKuard, proxyInvalidMissingServiceWithTCPProxy},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			{Name: invalidResponseHeadersPolicyService.Name, Namespace: invalidResponseHeadersPolicyService.Namespace}: fixture.NewValidCondition().
-				WithError(contour_api_v1.ConditionTypeServiceError, "ResponseHeadersPolicyInvalid", `rewriting "Host" header is not supported on response headers`),
+			{Name: proxyInvalidMissingServiceWithTCPProxy.Name, Namespace: proxyInvalidMissingServiceWithTCPProxy.Namespace}: fixture.NewValidCondition().
+				WithError(contour_api_v1.ConditionTypeServiceError, "ServiceUnresolvedReference", `Spec.Routes unresolved service reference: service "roots/missing" not found`),
 		},
 	})
 
-	invalidResponseHeadersPolicyRoute := &contour_api_v1.HTTPProxy{
+	proxyRoutePortNotMatchedWithTCP := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "invalidRHPRoute",
+			Name:      "missing-route-service-port",
 			Namespace: fixture.ServiceRootsKuard.Namespace,
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
-				Fqdn: "example.com",
+				Fqdn: "tcpproxy.example.com",
+				TLS: &contour_api_v1.TLS{
+					SecretName: fixture.SecretRootsCert.Name,
+				},
 			},
 			Routes: []contour_api_v1.Route{{
 				Services: []contour_api_v1.Service{
-					{
-						Name: fixture.ServiceRootsKuard.Name,
-						Port: 8080,
-					},
-				},
-				ResponseHeadersPolicy: &contour_api_v1.HeadersPolicy{
-					Set: []contour_api_v1.HeaderValue{{
-						Name:  "Host",
-						Value: "external.com",
-					}},
+					{Name: fixture.ServiceRootsKuard.Name, Port: 9999},
 				},
 			}},
+			TCPProxy: &contour_api_v1.TCPProxy{
+				Services: []contour_api_v1.Service{{
+					Name: fixture.ServiceRootsKuard.Name,
+					Port: 8080,
+				}},
+			},
 		},
 	}
 
-	run(t, "responseHeadersPolicy, Host header invalid on Route", testcase{
-		objs: []interface{}{invalidResponseHeadersPolicyRoute, fixture.ServiceRootsKuard},
+	run(t, "tcpproxy route unmatched service port", testcase{
+		objs: []interface{}{fixture.SecretRootsCert, fixture.ServiceRootsKuard, proxyRoutePortNotMatchedWithTCP},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			{Name: invalidResponseHeadersPolicyRoute.Name, Namespace: invalidResponseHeadersPolicyRoute.Namespace}: fixture.NewValidCondition().
-				WithError(contour_api_v1.ConditionTypeRouteError, "ResponseHeaderPolicyInvalid", `rewriting "Host" header is not supported on response headers`),
+			{Name: proxyRoutePortNotMatchedWithTCP.Name, Namespace: proxyRoutePortNotMatchedWithTCP.Namespace}: fixture.NewValidCondition().
+				WithError(contour_api_v1.ConditionTypeServiceError, "ServiceUnresolvedReference", `Spec.Routes unresolved service reference: port "9999" on service "roots/kuard" not matched`),
 		},
 	})
 
-	duplicateCookieRewritePolicyRoute := &contour_api_v1.HTTPProxy{
+	proxyTCPValidIncludeChild := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "invalidCRPRoute",
+			Name:      "validtcpproxy",
 			Namespace: fixture.ServiceRootsKuard.Namespace,
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
-				Fqdn: "example.com",
-			},
-			Routes: []contour_api_v1.Route{{
-				Services: []contour_api_v1.Service{
-					{
-						Name: fixture.ServiceRootsKuard.Name,
-						Port: 8080,
-					},
+				Fqdn: "tcpproxy.example.com",
+				TLS: &contour_api_v1.TLS{
+					SecretName: fixture.SecretRootsCert.Name,
 				},
-				CookieRewritePolicies: []contour_api_v1.CookieRewritePolicy{
-					{
-						Name:   "a-cookie",
-						Secure: ref.To(true),
-					},
-					{
-						Name:     "a-cookie",
-						SameSite: ref.To("Lax"),
-					},
+			},
+			TCPProxy: &contour_api_v1.TCPProxy{
+				Include: &contour_api_v1.TCPProxyInclude{
+					Name:      "child",
+					Namespace: fixture.ServiceRootsKuard.Namespace,
 				},
-			}},
+			},
 		},
 	}
 
-	run(t, "cookieRewritePolicies, duplicate cookie names on route", testcase{
-		objs: []interface{}{duplicateCookieRewritePolicyRoute, fixture.ServiceRootsKuard},
-		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			{Name: duplicateCookieRewritePolicyRoute.Name, Namespace: duplicateCookieRewritePolicyRoute.Namespace}: fixture.NewValidCondition().
-				WithError(contour_api_v1.ConditionTypeRouteError, "CookieRewritePoliciesInvalid", `duplicate cookie rewrite rule for cookie "a-cookie" on route cookie rewrite rules`),
-		},
-	})
-
-	duplicateCookieRewritePolicyService := &contour_api_v1.HTTPProxy{
+	proxyTCPValidIncludesChild := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "invalidCRPService",
+			Name:      "validtcpproxy",
 			Namespace: fixture.ServiceRootsKuard.Namespace,
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
-				Fqdn: "example.com",
+				Fqdn: "tcpproxy.example.com",
+				TLS: &contour_api_v1.TLS{
+					SecretName: fixture.SecretRootsCert.Name,
+				},
 			},
-			Routes: []contour_api_v1.Route{{
-				Services: []contour_api_v1.Service{
-					{
-						Name: fixture.ServiceRootsKuard.Name,
-						Port: 8080,
-						CookieRewritePolicies: []contour_api_v1.CookieRewritePolicy{
-							{
-								Name:   "a-cookie",
-								Secure: ref.To(true),
-							},
-							{
-								Name:     "a-cookie",
-								SameSite: ref.To("Lax"),
-							},
-						},
-					},
+			TCPProxy: &contour_api_v1.TCPProxy{
+				IncludesDeprecated: &contour_api_v1.TCPProxyInclude{
+					Name:      "child",
+					Namespace: fixture.ServiceRootsKuard.Namespace,
 				},
-			}},
+			},
 		},
 	}
 
-	run(t, "cookieRewritePolicies, duplicate cookie names on service", testcase{
-		objs: []interface{}{duplicateCookieRewritePolicyService, fixture.ServiceRootsKuard},
-		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			{Name: duplicateCookieRewritePolicyService.Name, Namespace: duplicateCookieRewritePolicyService.Namespace}: fixture.NewValidCondition().
-				WithError(contour_api_v1.ConditionTypeRouteError, "CookieRewritePoliciesInvalid", `duplicate cookie rewrite rule for cookie "a-cookie" on service cookie rewrite rules`),
-		},
-	})
-
-	emptyCookieRewritePolicyRoute := &contour_api_v1.HTTPProxy{
+	proxyTCPValidChild := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "invalidCRPRoute",
+			Name:      "child",
 			Namespace: fixture.ServiceRootsKuard.Namespace,
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
-			VirtualHost: &contour_api_v1.VirtualHost{
-				Fqdn: "example.com",
+			TCPProxy: &contour_api_v1.TCPProxy{
+				Services: []contour_api_v1.Service{{
+					Name: fixture.ServiceRootsKuard.Name,
+					Port: 8080,
+				}},
 			},
-			Routes: []contour_api_v1.Route{{
-				CookieRewritePolicies: []contour_api_v1.CookieRewritePolicy{
-					{
-						Name: "a-cookie",
-					},
-				},
-				Services: []contour_api_v1.Service{
-					{
-						Name: fixture.ServiceRootsKuard.Name,
-						Port: 8080,
-					},
-				},
-			}},
 		},
 	}
 
-	run(t, "cookieRewritePolicies, empty cookie rewrite on route", testcase{
-		objs: []interface{}{emptyCookieRewritePolicyRoute, fixture.ServiceRootsKuard},
+	run(t, "valid HTTPProxy.TCPProxy - plural", testcase{
+		objs: []interface{}{proxyTCPValidIncludesChild, proxyTCPValidChild, fixture.ServiceRootsKuard, fixture.SecretRootsCert},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			{Name: emptyCookieRewritePolicyRoute.Name, Namespace: emptyCookieRewritePolicyRoute.Namespace}: fixture.NewValidCondition().
-				WithError(contour_api_v1.ConditionTypeRouteError, "CookieRewritePoliciesInvalid", `no attributes rewritten for cookie "a-cookie" on route cookie rewrite rules`),
+			{Name: proxyTCPValidIncludesChild.Name,
+				Namespace: proxyTCPValidIncludesChild.Namespace}: fixture.NewValidCondition().Valid(),
+			{Name: proxyTCPValidChild.Name,
+				Namespace: proxyTCPValidChild.Namespace}: fixture.NewValidCondition().Valid(),
 		},
 	})
 
-	emptyCookieRewritePolicyService := &contour_api_v1.HTTPProxy{
+	run(t, "valid HTTPProxy.TCPProxy", testcase{
+		objs: []interface{}{proxyTCPValidIncludeChild, proxyTCPValidChild, fixture.ServiceRootsKuard, fixture.SecretRootsCert},
+		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
+			{Name: proxyTCPValidIncludeChild.Name,
+				Namespace: proxyTCPValidIncludeChild.Namespace}: fixture.NewValidCondition().Valid(),
+			{Name: proxyTCPValidChild.Name,
+				Namespace: proxyTCPValidChild.Namespace}: fixture.NewValidCondition().Valid(),
+		},
+	})
+
+	// issue 2309
+	proxyInvalidNoServices := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "invalidCRPService",
+			Name:      "missing-service",
 			Namespace: fixture.ServiceRootsKuard.Namespace,
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
-				Fqdn: "example.com",
+				Fqdn: "missing-service.example.com",
 			},
 			Routes: []contour_api_v1.Route{{
-				Services: []contour_api_v1.Service{
-					{
-						Name: fixture.ServiceRootsKuard.Name,
-						Port: 8080,
-						CookieRewritePolicies: []contour_api_v1.CookieRewritePolicy{
-							{
-								Name: "a-cookie",
-							},
-						},
-					},
-				},
+				Conditions: []contour_api_v1.MatchCondition{{
+					Prefix: "/",
+				}},
+				Services: nil, // missing
 			}},
 		},
 	}
 
-	run(t, "cookieRewritePolicies, empty cookie rewrite on service", testcase{
-		objs: []interface{}{emptyCookieRewritePolicyService, fixture.ServiceRootsKuard},
+	run(t, "No routeAction specified is invalid", testcase{
+		objs: []interface{}{proxyInvalidNoServices, fixture.ServiceRootsKuard},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			{Name: emptyCookieRewritePolicyService.Name, Namespace: emptyCookieRewritePolicyService.Namespace}: fixture.NewValidCondition().
-				WithError(contour_api_v1.ConditionTypeRouteError, "CookieRewritePoliciesInvalid", `no attributes rewritten for cookie "a-cookie" on service cookie rewrite rules`),
+			{Name: proxyInvalidNoServices.Name, Namespace: proxyInvalidNoServices.Namespace}: fixture.NewValidCondition().
+				WithError(contour_api_v1.ConditionTypeRouteError, "RouteActionCountNotValid", "must set exactly one of route.services or route.requestRedirectPolicy or route.directResponsePolicy"),
 		},
 	})
 
-	proxyAuthFallback := fixture.NewProxy("roots/fallback-incompat").
-		WithSpec(contour_api_v1.HTTPProxySpec{
+	fallbackCertificate := &contour_api_v1.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "example",
+		},
+		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
-				Fqdn: "invalid.com",
+				Fqdn: "example.com",
 				TLS: &contour_api_v1.TLS{
 					SecretName:                "ssl-cert",
 					EnableFallbackCertificate: true,
 				},
-				Authorization: &contour_api_v1.AuthorizationServer{
-					ExtensionServiceRef: contour_api_v1.ExtensionServiceReference{
-						Namespace: "auth",
-						Name:      "extension",
-					},
-				},
 			},
 			Routes: []contour_api_v1.Route{{
-				Services: []contour_api_v1.Service{{Name: "app-server", Port: 80}},
-			}},
-		})
-
-	run(t, "fallback and client auth is invalid", testcase{
-		objs: []interface{}{fixture.SecretRootsCert, proxyAuthFallback},
-		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			{Name: proxyAuthFallback.Name, Namespace: proxyAuthFallback.Namespace}: fixture.NewValidCondition().WithGeneration(proxyAuthFallback.Generation).
-				WithError(contour_api_v1.ConditionTypeTLSError, "TLSIncompatibleFeatures", "Spec.Virtualhost.TLS fallback & client authorization are incompatible"),
-		},
-	})
-
-	proxyAuthHTTP := fixture.NewProxy("roots/http").
-		WithSpec(contour_api_v1.HTTPProxySpec{
-			VirtualHost: &contour_api_v1.VirtualHost{
-				Fqdn: "invalid.com",
-				Authorization: &contour_api_v1.AuthorizationServer{
-					ExtensionServiceRef: contour_api_v1.ExtensionServiceReference{
-						Namespace: "auth",
-						Name:      "extension",
-					},
-				},
-			},
-			Routes: []contour_api_v1.Route{{
-				Services: []contour_api_v1.Service{{Name: "app-server", Port: 80}},
+				Conditions: []contour_api_v1.MatchCondition{{
+					Prefix: "/foo",
+				}},
+				Services: []contour_api_v1.Service{{
+					Name: "home",
+					Port: 8080,
+				}},
 			}},
-		})
-
-	run(t, "plain HTTP vhost and client auth is invalid", testcase{
-		objs: []interface{}{fixture.SecretRootsCert, proxyAuthHTTP},
-		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			k8s.NamespacedNameOf(proxyAuthHTTP): fixture.NewValidCondition().WithGeneration(proxyAuthHTTP.Generation).
-				WithError(contour_api_v1.ConditionTypeAuthError, "AuthNotPermitted", "Spec.VirtualHost.Authorization.ExtensionServiceRef can only be defined for root HTTPProxies that terminate TLS"),
-		},
-	})
-
-	invalidResponseTimeout := &contour_api_v1.HTTPProxy{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: fixture.ServiceRootsKuard.Namespace,
-			Name:      "invalid-timeouts",
-		},
-		Spec: contour_api_v1.HTTPProxySpec{
-			VirtualHost: &contour_api_v1.VirtualHost{
-				Fqdn: "example.com",
-			},
-			Routes: []contour_api_v1.Route{
-				{
-					Services: []contour_api_v1.Service{
-						{
-							Name: fixture.ServiceRootsKuard.Name,
-						},
-					},
-					TimeoutPolicy: &contour_api_v1.TimeoutPolicy{
-						Response: "invalid-val",
-					},
-				},
-			},
 		},
 	}
 
-	run(t, "proxy with invalid response timeout value is invalid", testcase{
-		objs: []interface{}{invalidResponseTimeout, fixture.ServiceRootsKuard},
+	run(t, "invalid fallback certificate passed to contour", testcase{
+		fallbackCertificate: &types.NamespacedName{
+			Name:      "invalid",
+			Namespace: "invalid",
+		},
+		objs: []interface{}{fallbackCertificate, fixture.SecretRootsFallback, fixture.SecretRootsCert, fixture.ServiceRootsHome},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			{
-				Name:      invalidResponseTimeout.Name,
-				Namespace: invalidResponseTimeout.Namespace,
-			}: fixture.NewValidCondition().WithError(contour_api_v1.ConditionTypeRouteError, "TimeoutPolicyNotValid",
-				`route.timeoutPolicy failed to parse: error parsing response timeout: unable to parse timeout string "invalid-val": time: invalid duration "invalid-val"`),
+			{Name: fallbackCertificate.Name,
+				Namespace: fallbackCertificate.Namespace}: fixture.NewValidCondition().
+				WithError(contour_api_v1.ConditionTypeTLSError, "FallbackNotValid", `Spec.Virtualhost.TLS Secret "invalid/invalid" fallback certificate is invalid: Secret not found`),
 		},
 	})
 
-	invalidIdleTimeout := &contour_api_v1.HTTPProxy{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: fixture.ServiceRootsKuard.Namespace,
-			Name:      "invalid-timeouts",
-		},
-		Spec: contour_api_v1.HTTPProxySpec{
-			VirtualHost: &contour_api_v1.VirtualHost{
-				Fqdn: "example.com",
-			},
-			Routes: []contour_api_v1.Route{
-				{
-					Services: []contour_api_v1.Service{
-						{
-							Name: fixture.ServiceRootsKuard.Name,
-						},
-					},
-					TimeoutPolicy: &contour_api_v1.TimeoutPolicy{
-						Idle: "invalid-val",
-					},
-				},
-			},
-		},
-	}
-
-	run(t, "proxy with invalid idle timeout value is invalid", testcase{
-		objs: []interface{}{invalidIdleTimeout, fixture.ServiceRootsKuard},
+	run(t, "fallback certificate requested but cert not configured in contour", testcase{
+		objs: []interface{}{fallbackCertificate, fixture.SecretRootsFallback, fixture.SecretRootsCert, fixture.ServiceRootsHome},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			{
-				Name:      invalidIdleTimeout.Name,
-				Namespace: invalidIdleTimeout.Namespace,
-			}: fixture.NewValidCondition().WithError(contour_api_v1.ConditionTypeRouteError, "TimeoutPolicyNotValid",
-				`route.timeoutPolicy failed to parse: error parsing idle timeout: unable to parse timeout string "invalid-val": time: invalid duration "invalid-val"`),
+			{Name: fallbackCertificate.Name,
+				Namespace: fallbackCertificate.Namespace}: fixture.NewValidCondition().
+				WithError(contour_api_v1.ConditionTypeTLSError, "FallbackNotPresent", "Spec.Virtualhost.TLS enabled fallback but the fallback Certificate Secret is not configured in Contour configuration file"),
 		},
 	})
 
-	// issue 3197: Fallback and passthrough HTTPProxy directive should emit a config error
-	tlsPassthroughAndFallback := &contour_api_v1.HTTPProxy{
+	fallbackCertificateWithClientValidationNoCA := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace:  "roots",
-			Name:       "example",
-			Generation: 24,
+			Namespace: "roots",
+			Name:      "example",
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
+				Fqdn: "example.com",
 				TLS: &contour_api_v1.TLS{
-					Passthrough:               true,
-					EnableFallbackCertificate: true,
+					SecretName:       "ssl-cert",
+					ClientValidation: &contour_api_v1.DownstreamValidation{},
 				},
-				Fqdn: "example.com",
 			},
 			Routes: []contour_api_v1.Route{{
 				Conditions: []contour_api_v1.MatchCondition{{
@@ -3042,29 +3031,30 @@ func TestDAGStatus(t *testing.T) {
 		},
 	}
 
-	run(t, "TLS with passthrough and fallback cert enabled is invalid", testcase{
-		objs: []interface{}{tlsPassthroughAndFallback, fixture.ServiceRootsHome},
+	run(t, "clientValidation missing CA", testcase{
+		objs: []interface{}{fallbackCertificateWithClientValidationNoCA, fixture.SecretRootsFallback, fixture.SecretRootsCert, fixture.ServiceRootsHome},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			{Name: tlsPassthroughAndFallback.Name, Namespace: tlsPassthroughAndFallback.Namespace}: fixture.NewValidCondition().
-				WithGeneration(tlsPassthroughAndFallback.Generation).WithError(
-				contour_api_v1.ConditionTypeTLSError, "TLSIncompatibleFeatures",
-				`Spec.VirtualHost.TLS: both Passthrough and enableFallbackCertificate were specified`,
-			),
+			{Name: fallbackCertificateWithClientValidationNoCA.Name,
+				Namespace: fallbackCertificateWithClientValidationNoCA.Namespace}: fixture.NewValidCondition().
+				WithError(contour_api_v1.ConditionTypeTLSError, "ClientValidationInvalid", "Spec.VirtualHost.TLS client validation is invalid: CA Secret must be specified"),
 		},
 	})
-	tlsPassthrough := &contour_api_v1.HTTPProxy{
+
+	fallbackCertificateWithClientValidation := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace:  "roots",
-			Name:       "example",
-			Generation: 24,
+			Namespace: "roots",
+			Name:      "example",
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
+				Fqdn: "example.com",
 				TLS: &contour_api_v1.TLS{
-					Passthrough:               true,
-					EnableFallbackCertificate: false,
+					SecretName:                "ssl-cert",
+					EnableFallbackCertificate: true,
+					ClientValidation: &contour_api_v1.DownstreamValidation{
+						CACertificate: "something",
+					},
 				},
-				Fqdn: "example.com",
 			},
 			Routes: []contour_api_v1.Route{{
 				Conditions: []contour_api_v1.MatchCondition{{
@@ -3078,747 +3068,596 @@ func TestDAGStatus(t *testing.T) {
 		},
 	}
 
-	run(t, "valid TLS passthrough", testcase{
-		objs: []interface{}{tlsPassthrough, fixture.ServiceRootsHome},
+	run(t, "fallback certificate requested and clientValidation also configured", testcase{
+		objs: []interface{}{fallbackCertificateWithClientValidation, fixture.SecretRootsFallback, fixture.SecretRootsCert, fixture.ServiceRootsHome},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			{Name: tlsPassthrough.Name, Namespace: tlsPassthrough.Namespace}: fixture.NewValidCondition().
-				WithGeneration(tlsPassthrough.Generation).
-				Valid(),
+			{Name: fallbackCertificateWithClientValidation.Name,
+				Namespace: fallbackCertificateWithClientValidation.Namespace}: fixture.NewValidCondition().
+				WithError(contour_api_v1.ConditionTypeTLSError, "TLSIncompatibleFeatures", "Spec.Virtualhost.TLS fallback & client validation are incompatible"),
 		},
 	})
 
-	multipleRouteAction := &contour_api_v1.HTTPProxy{
+	tlsPassthroughAndValidation := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: "roots",
-			Name:      "multipleRouteAction",
+			Name:      "invalid",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
-				Fqdn: "example.com",
-			},
-			Routes: []contour_api_v1.Route{{
-				Conditions: []contour_api_v1.MatchCondition{{
-					Prefix: "/foo",
-				}},
-				Services: []contour_api_v1.Service{{
-					Name: "home",
-					Port: 8080,
-				}},
-				DirectResponsePolicy: &contour_api_v1.HTTPDirectResponsePolicy{
-					StatusCode: 200,
-					Body:       "success",
+				Fqdn: "tcpproxy.example.com",
+				TLS: &contour_api_v1.TLS{
+					Passthrough: true,
+					ClientValidation: &contour_api_v1.DownstreamValidation{
+						CACertificate: "aCAcert",
+					},
 				},
-			}},
+			},
+			TCPProxy: &contour_api_v1.TCPProxy{},
 		},
 	}
-	run(t, "Selecting more than one routeAction is invalid", testcase{
-		objs: []interface{}{multipleRouteAction},
+
+	run(t, "passthrough and client auth are incompatible tlsPassthroughAndValidation", testcase{
+		objs: []interface{}{fixture.SecretRootsCert, tlsPassthroughAndValidation},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			{Name: multipleRouteAction.Name, Namespace: multipleRouteAction.Namespace}: fixture.NewValidCondition().
-				WithError(contour_api_v1.ConditionTypeRouteError, "RouteActionCountNotValid",
-					"must set exactly one of route.services or route.requestRedirectPolicy or route.directResponsePolicy"),
+			{Name: tlsPassthroughAndValidation.Name, Namespace: tlsPassthroughAndValidation.Namespace}: fixture.NewValidCondition().
+				WithError(contour_api_v1.ConditionTypeTLSError, "TLSIncompatibleFeatures", "Spec.VirtualHost.TLS passthrough cannot be combined with tls.clientValidation"),
 		},
 	})
 
-	invalidAllowOrigin := &contour_api_v1.HTTPProxy{
+	tlsPassthroughAndSecretName := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
+			Name:      "invalid",
 			Namespace: fixture.ServiceRootsKuard.Namespace,
-			Name:      "invalid-alloworigin",
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
-				Fqdn: "example.com",
-				CORSPolicy: &contour_api_v1.CORSPolicy{
-					AllowOrigin:  []string{"example-2.com", "**"},
-					AllowMethods: []contour_api_v1.CORSHeaderValue{"GET"},
-				},
-			},
-			Routes: []contour_api_v1.Route{
-				{
-					Services: []contour_api_v1.Service{
-						{
-							Name: fixture.ServiceRootsKuard.Name,
-							Port: 8080,
-						},
-					},
+				Fqdn: "tcpproxy.example.com",
+				TLS: &contour_api_v1.TLS{
+					Passthrough: true,
+					SecretName:  fixture.SecretRootsCert.Name,
 				},
 			},
+			TCPProxy: &contour_api_v1.TCPProxy{},
 		},
 	}
 
-	run(t, "proxy with invalid allow origin is invalid", testcase{
-		objs: []interface{}{invalidAllowOrigin, fixture.ServiceRootsKuard},
+	run(t, "tcpproxy with TLS passthrough and secret name both specified", testcase{
+		objs: []interface{}{
+			fixture.SecretRootsCert,
+			tlsPassthroughAndSecretName,
+		},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			{
-				Name:      invalidAllowOrigin.Name,
-				Namespace: invalidAllowOrigin.Namespace,
-			}: fixture.NewValidCondition().WithError(contour_api_v1.ConditionTypeCORSError, "PolicyDidNotParse",
-				`Spec.VirtualHost.CORSPolicy: invalid allowed origin "**": allowed origin is invalid exact match and invalid regex match`),
+			{Name: "invalid", Namespace: fixture.ServiceRootsKuard.Namespace}: fixture.NewValidCondition().
+				WithError(contour_api_v1.ConditionTypeTLSError, "TLSConfigNotValid", "Spec.VirtualHost.TLS: both Passthrough and SecretName were specified"),
 		},
 	})
 
-	jwtVerificationValidProxy := &contour_api_v1.HTTPProxy{
+	tlsNoPassthroughOrSecretName := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: "roots",
-			Name:      "jwt-verification-valid-proxy",
+			Name:      "invalid",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
-				Fqdn: "example.com",
+				Fqdn: "tcpproxy.example.com",
 				TLS: &contour_api_v1.TLS{
-					SecretName: fixture.SecretRootsCert.Name,
-				},
-				JWTProviders: []contour_api_v1.JWTProvider{
-					{
-						Name:      "provider-1",
-						Issuer:    "jwt.example.com",
-						Audiences: []string{"foo", "bar"},
-						RemoteJWKS: contour_api_v1.RemoteJWKS{
-							URI:           "https://jwt.example.com/jwks.json",
-							Timeout:       "10s",
-							CacheDuration: "1h",
-						},
-					},
-				},
-			},
-			Routes: []contour_api_v1.Route{
-				{
-					JWTVerificationPolicy: &contour_api_v1.JWTVerificationPolicy{Require: "provider-1"},
-					Conditions: []contour_api_v1.MatchCondition{{
-						Prefix: "/foo",
-					}},
-					Services: []contour_api_v1.Service{{
-						Name: "home",
-						Port: 8080,
-					}},
+					Passthrough: false,
+					SecretName:  "",
 				},
 			},
+			TCPProxy: &contour_api_v1.TCPProxy{},
 		},
 	}
 
-	run(t, "JWT verification valid proxy", testcase{
+	run(t, "httpproxy w/ tcpproxy with neither TLS passthrough nor secret name specified", testcase{
 		objs: []interface{}{
-			jwtVerificationValidProxy,
 			fixture.SecretRootsCert,
-			fixture.ServiceRootsHome,
+			tlsNoPassthroughOrSecretName,
 		},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			k8s.NamespacedNameOf(jwtVerificationValidProxy): fixture.NewValidCondition().Valid(),
+			{Name: "invalid", Namespace: fixture.ServiceRootsKuard.Namespace}: fixture.NewValidCondition().
+				WithError(contour_api_v1.ConditionTypeTLSError, "TLSConfigNotValid", "Spec.VirtualHost.TLS: neither Passthrough nor SecretName were specified"),
 		},
 	})
 
-	jwtVerificationDuplicateProviders := &contour_api_v1.HTTPProxy{
+	emptyProxy := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
+			Name:      "empty",
 			Namespace: "roots",
-			Name:      "jwt-verification-duplicate-provider-names",
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
 				Fqdn: "example.com",
-				TLS: &contour_api_v1.TLS{
-					SecretName: fixture.SecretRootsCert.Name,
-				},
-				JWTProviders: []contour_api_v1.JWTProvider{
-					{
-						Name: "provider-1",
-						RemoteJWKS: contour_api_v1.RemoteJWKS{
-							URI: "https://jwt.example.com/jwks.json",
-						},
-					},
-					{
-						Name: "provider-1",
-						RemoteJWKS: contour_api_v1.RemoteJWKS{
-							URI: "https://jwt.example.com/jwks.json",
-						},
-					},
-				},
-			},
-			Routes: []contour_api_v1.Route{
-				{
-					Conditions: []contour_api_v1.MatchCondition{{
-						Prefix: "/foo",
-					}},
-					Services: []contour_api_v1.Service{{
-						Name: "home",
-						Port: 8080,
-					}},
-				},
 			},
 		},
 	}
 
-	run(t, "JWT verification duplicate provider names", testcase{
-		objs: []interface{}{
-			jwtVerificationDuplicateProviders,
-			fixture.SecretRootsCert,
-			fixture.ServiceRootsHome,
-		},
+	run(t, "proxy with no routes, includes, or tcpproxy is invalid", testcase{
+		objs: []interface{}{emptyProxy},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			k8s.NamespacedNameOf(jwtVerificationDuplicateProviders): fixture.NewValidCondition().
-				WithError(
-					contour_api_v1.ConditionTypeJWTVerificationError,
-					"DuplicateProviderName",
-					"Spec.VirtualHost.JWTProviders is invalid: duplicate name provider-1",
-				),
+			{Name: emptyProxy.Name, Namespace: emptyProxy.Namespace}: fixture.NewValidCondition().
+				WithError(contour_api_v1.ConditionTypeSpecError, "NothingDefined", "HTTPProxy.Spec must have at least one Route, Include, or a TCPProxy"),
 		},
 	})
 
-	jwtVerificationMultipleDefaults := &contour_api_v1.HTTPProxy{
+	invalidResponseHeadersPolicyService := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: "roots",
-			Name:      "jwt-verification-multiple-defaults",
+			Name:      "invalidRHPService",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
 				Fqdn: "example.com",
-				TLS: &contour_api_v1.TLS{
-					SecretName: fixture.SecretRootsCert.Name,
-				},
-				JWTProviders: []contour_api_v1.JWTProvider{
-					{
-						Name:    "provider-1",
-						Default: true,
-						RemoteJWKS: contour_api_v1.RemoteJWKS{
-							URI: "https://jwt.example.com/jwks.json",
-						},
-					},
+			},
+			Routes: []contour_api_v1.Route{{
+				Services: []contour_api_v1.Service{
 					{
-						Name:    "provider-2",
-						Default: true,
-						RemoteJWKS: contour_api_v1.RemoteJWKS{
-							URI: "https://jwt.example.com/jwks.json",
+						Name: fixture.ServiceRootsKuard.Name,
+						Port: 8080,
+						ResponseHeadersPolicy: &contour_api_v1.HeadersPolicy{
+							Set: []contour_api_v1.HeaderValue{{
+								Name:  "Host",
+								Value: "external.com",
+							}},
 						},
 					},
 				},
-			},
-			Routes: []contour_api_v1.Route{
-				{
-					Conditions: []contour_api_v1.MatchCondition{{
-						Prefix: "/foo",
-					}},
-					Services: []contour_api_v1.Service{{
-						Name: "home",
-						Port: 8080,
-					}},
-				},
-			},
+			}},
 		},
 	}
 
-	run(t, "JWT verification multiple default providers", testcase{
-		objs: []interface{}{
-			jwtVerificationMultipleDefaults,
-			fixture.SecretRootsCert,
-			fixture.ServiceRootsHome,
-		},
+	run(t, "responseHeadersPolicy, Host header invalid on Service", testcase{
+		objs: []interface{}{invalidResponseHeadersPolicyService, fixture.ServiceRootsKuard},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			k8s.NamespacedNameOf(jwtVerificationMultipleDefaults): fixture.NewValidCondition().
-				WithError(
-					contour_api_v1.ConditionTypeJWTVerificationError,
-					"MultipleDefaultProvidersSpecified",
-					"Spec.VirtualHost.JWTProviders is invalid: at most one provider can be set as the default",
-				),
+			{Name: invalidResponseHeadersPolicyService.Name, Namespace: invalidResponseHeadersPolicyService.Namespace}: fixture.NewValidCondition().
+				WithError(contour_api_v1.ConditionTypeServiceError, "ResponseHeadersPolicyInvalid", `rewriting "Host" header is not supported on response headers`),
 		},
 	})
 
-	jwtVerificationInvalidRemoteJWKSURI := &contour_api_v1.HTTPProxy{
+	invalidResponseHeadersPolicyRoute := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: "roots",
-			Name:      "jwt-verification-invalid-remote-jwks-uri",
+			Name:      "invalidRHPRoute",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
 				Fqdn: "example.com",
-				TLS: &contour_api_v1.TLS{
-					SecretName: fixture.SecretRootsCert.Name,
-				},
-				JWTProviders: []contour_api_v1.JWTProvider{
+			},
+			Routes: []contour_api_v1.Route{{
+				Services: []contour_api_v1.Service{
 					{
-						Name: "provider-1",
-						RemoteJWKS: contour_api_v1.RemoteJWKS{
-							URI: ":/invalid-uri",
-						},
+						Name: fixture.ServiceRootsKuard.Name,
+						Port: 8080,
 					},
 				},
-			},
-			Routes: []contour_api_v1.Route{
-				{
-					Conditions: []contour_api_v1.MatchCondition{{
-						Prefix: "/foo",
-					}},
-					Services: []contour_api_v1.Service{{
-						Name: "home",
-						Port: 8080,
+				ResponseHeadersPolicy: &contour_api_v1.HeadersPolicy{
+					Set: []contour_api_v1.HeaderValue{{
+						Name:  "Host",
+						Value: "external.com",
 					}},
 				},
-			},
+			}},
 		},
 	}
 
-	run(t, "JWT verification invalid remote JWKS URI", testcase{
-		objs: []interface{}{
-			jwtVerificationInvalidRemoteJWKSURI,
-			fixture.SecretRootsCert,
-			fixture.ServiceRootsHome,
-		},
+	run(t, "responseHeadersPolicy, Host header invalid on Route", testcase{
+		objs: []interface{}{invalidResponseHeadersPolicyRoute, fixture.ServiceRootsKuard},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			k8s.NamespacedNameOf(jwtVerificationInvalidRemoteJWKSURI): fixture.NewValidCondition().
-				WithError(
-					contour_api_v1.ConditionTypeJWTVerificationError,
-					"RemoteJWKSURIInvalid",
-					"Spec.VirtualHost.JWTProviders.RemoteJWKS.URI is invalid: parse \":/invalid-uri\": missing protocol scheme",
-				),
+			{Name: invalidResponseHeadersPolicyRoute.Name, Namespace: invalidResponseHeadersPolicyRoute.Namespace}: fixture.NewValidCondition().
+				WithError(contour_api_v1.ConditionTypeRouteError, "ResponseHeaderPolicyInvalid", `rewriting "Host" header is not supported on response headers`),
 		},
 	})
 
-	jwtVerificationInvalidRemoteJWKSScheme := &contour_api_v1.HTTPProxy{
+	duplicateCookieRewritePolicyRoute := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: "roots",
-			Name:      "jwt-verification-invalid-remote-jwks-scheme",
+			Name:      "invalidCRPRoute",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
 				Fqdn: "example.com",
-				TLS: &contour_api_v1.TLS{
-					SecretName: fixture.SecretRootsCert.Name,
-				},
-				JWTProviders: []contour_api_v1.JWTProvider{
+			},
+			Routes: []contour_api_v1.Route{{
+				Services: []contour_api_v1.Service{
 					{
-						Name: "provider-1",
-						RemoteJWKS: contour_api_v1.RemoteJWKS{
-							URI: "ftp://jwt.example.com/jwks.json",
-						},
+						Name: fixture.ServiceRootsKuard.Name,
+						Port: 8080,
 					},
 				},
-			},
-			Routes: []contour_api_v1.Route{
-				{
-					Conditions: []contour_api_v1.MatchCondition{{
-						Prefix: "/foo",
-					}},
-					Services: []contour_api_v1.Service{{
-						Name: "home",
-						Port: 8080,
-					}},
+				CookieRewritePolicies: []contour_api_v1.CookieRewritePolicy{
+					{
+						Name:   "a-cookie",
+						Secure: ref.To(true),
+					},
+					{
+						Name:     "a-cookie",
+						SameSite: ref.To("Lax"),
+					},
 				},
-			},
+			}},
 		},
 	}
 
-	run(t, "JWT verification invalid remote JWKS scheme", testcase{
-		objs: []interface{}{
-			jwtVerificationInvalidRemoteJWKSScheme,
-			fixture.SecretRootsCert,
-			fixture.ServiceRootsHome,
-		},
+	run(t, "cookieRewritePolicies, duplicate cookie names on route", testcase{
+		objs: []interface{}{duplicateCookieRewritePolicyRoute, fixture.ServiceRootsKuard},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			k8s.NamespacedNameOf(jwtVerificationInvalidRemoteJWKSScheme): fixture.NewValidCondition().
-				WithError(
-					contour_api_v1.ConditionTypeJWTVerificationError,
-					"RemoteJWKSSchemeInvalid",
-					"Spec.VirtualHost.JWTProviders.RemoteJWKS.URI has invalid scheme \"ftp\", must be http or https",
-				),
+			{Name: duplicateCookieRewritePolicyRoute.Name, Namespace: duplicateCookieRewritePolicyRoute.Namespace}: fixture.NewValidCondition().
+				WithError(contour_api_v1.ConditionTypeRouteError, "CookieRewritePoliciesInvalid", `duplicate cookie rewrite rule for cookie "a-cookie" on route cookie rewrite rules`),
 		},
 	})
 
-	jwtVerificationInvalidRemoteJWKSTimeout := &contour_api_v1.HTTPProxy{
+	duplicateCookieRewritePolicyService := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: "roots",
-			Name:      "jwt-verification-invalid-remote-jwks-timeout",
+			Name:      "invalidCRPService",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
 				Fqdn: "example.com",
-				TLS: &contour_api_v1.TLS{
-					SecretName: fixture.SecretRootsCert.Name,
-				},
-				JWTProviders: []contour_api_v1.JWTProvider{
+			},
+			Routes: []contour_api_v1.Route{{
+				Services: []contour_api_v1.Service{
 					{
-						Name: "provider-1",
-						RemoteJWKS: contour_api_v1.RemoteJWKS{
-							URI:     "http://jwt.example.com/jwks.json",
-							Timeout: "invalid-timeout-string",
+						Name: fixture.ServiceRootsKuard.Name,
+						Port: 8080,
+						CookieRewritePolicies: []contour_api_v1.CookieRewritePolicy{
+							{
+								Name:   "a-cookie",
+								Secure: ref.To(true),
+							},
+							{
+								Name:     "a-cookie",
+								SameSite: ref.To("Lax"),
+							},
 						},
 					},
 				},
-			},
-			Routes: []contour_api_v1.Route{
-				{
-					Conditions: []contour_api_v1.MatchCondition{{
-						Prefix: "/foo",
-					}},
-					Services: []contour_api_v1.Service{{
-						Name: "home",
-						Port: 8080,
-					}},
-				},
-			},
+			}},
 		},
 	}
 
-	run(t, "JWT verification invalid remote JWKS timeout", testcase{
-		objs: []interface{}{
-			jwtVerificationInvalidRemoteJWKSTimeout,
-			fixture.SecretRootsCert,
-			fixture.ServiceRootsHome,
-		},
+	run(t, "cookieRewritePolicies, duplicate cookie names on service", testcase{
+		objs: []interface{}{duplicateCookieRewritePolicyService, fixture.ServiceRootsKuard},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			k8s.NamespacedNameOf(jwtVerificationInvalidRemoteJWKSTimeout): fixture.NewValidCondition().
-				WithError(
-					contour_api_v1.ConditionTypeJWTVerificationError,
-					"RemoteJWKSTimeoutInvalid",
-					"Spec.VirtualHost.JWTProviders.RemoteJWKS.Timeout is invalid: time: invalid duration \"invalid-timeout-string\"",
-				),
+			{Name: duplicateCookieRewritePolicyService.Name, Namespace: duplicateCookieRewritePolicyService.Namespace}: fixture.NewValidCondition().
+				WithError(contour_api_v1.ConditionTypeRouteError, "CookieRewritePoliciesInvalid", `duplicate cookie rewrite rule for cookie "a-cookie" on service cookie rewrite rules`),
 		},
 	})
 
-	jwtVerificationInvalidRemoteJWKSCacheDuration := &contour_api_v1.HTTPProxy{
+	emptyCookieRewritePolicyRoute := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: "roots",
-			Name:      "jwt-verification-invalid-remote-jwks-cache-duration",
+			Name:      "invalidCRPRoute",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
 				Fqdn: "example.com",
-				TLS: &contour_api_v1.TLS{
-					SecretName: fixture.SecretRootsCert.Name,
-				},
-				JWTProviders: []contour_api_v1.JWTProvider{
+			},
+			Routes: []contour_api_v1.Route{{
+				CookieRewritePolicies: []contour_api_v1.CookieRewritePolicy{
 					{
-						Name: "provider-1",
-						RemoteJWKS: contour_api_v1.RemoteJWKS{
-							URI:           "http://jwt.example.com/jwks.json",
-							CacheDuration: "invalid-duration-string",
-						},
+						Name: "a-cookie",
 					},
 				},
-			},
-			Routes: []contour_api_v1.Route{
-				{
-					Conditions: []contour_api_v1.MatchCondition{{
-						Prefix: "/foo",
-					}},
-					Services: []contour_api_v1.Service{{
-						Name: "home",
+				Services: []contour_api_v1.Service{
+					{
+						Name: fixture.ServiceRootsKuard.Name,
 						Port: 8080,
-					}},
+					},
 				},
-			},
+			}},
 		},
 	}
 
-	run(t, "JWT verification invalid remote JWKS cache duration", testcase{
-		objs: []interface{}{
-			jwtVerificationInvalidRemoteJWKSCacheDuration,
-			fixture.SecretRootsCert,
-			fixture.ServiceRootsHome,
-		},
+	run(t, "cookieRewritePolicies, empty cookie rewrite on route", testcase{
+		objs: []interface{}{emptyCookieRewritePolicyRoute, fixture.ServiceRootsKuard},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			k8s.NamespacedNameOf(jwtVerificationInvalidRemoteJWKSCacheDuration): fixture.NewValidCondition().
-				WithError(
-					contour_api_v1.ConditionTypeJWTVerificationError,
-					"RemoteJWKSCacheDurationInvalid",
-					"Spec.VirtualHost.JWTProviders.RemoteJWKS.CacheDuration is invalid: time: invalid duration \"invalid-duration-string\"",
-				),
+			{Name: emptyCookieRewritePolicyRoute.Name, Namespace: emptyCookieRewritePolicyRoute.Namespace}: fixture.NewValidCondition().
+				WithError(contour_api_v1.ConditionTypeRouteError, "CookieRewritePoliciesInvalid", `no attributes rewritten for cookie "a-cookie" on route cookie rewrite rules`),
 		},
 	})
 
-	jwtVerificationInvalidRemoteJWKSDNSLookupFamily := &contour_api_v1.HTTPProxy{
+	emptyCookieRewritePolicyService := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: "roots",
-			Name:      "jwt-verification-invalid-remote-jwks-dns-lookup-family",
+			Name:      "invalidCRPService",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
 				Fqdn: "example.com",
-				TLS: &contour_api_v1.TLS{
-					SecretName: fixture.SecretRootsCert.Name,
-				},
-				JWTProviders: []contour_api_v1.JWTProvider{
+			},
+			Routes: []contour_api_v1.Route{{
+				Services: []contour_api_v1.Service{
 					{
-						Name: "provider-1",
-						RemoteJWKS: contour_api_v1.RemoteJWKS{
-							URI:             "http://jwt.example.com/jwks.json",
-							DNSLookupFamily: "v7",
+						Name: fixture.ServiceRootsKuard.Name,
+						Port: 8080,
+						CookieRewritePolicies: []contour_api_v1.CookieRewritePolicy{
+							{
+								Name: "a-cookie",
+							},
 						},
 					},
 				},
-			},
-			Routes: []contour_api_v1.Route{
-				{
-					Conditions: []contour_api_v1.MatchCondition{{
-						Prefix: "/foo",
-					}},
-					Services: []contour_api_v1.Service{{
-						Name: "home",
-						Port: 8080,
-					}},
-				},
-			},
+			}},
 		},
 	}
 
-	run(t, "JWT verification invalid remote JWKS DNS lookup family", testcase{
-		objs: []interface{}{
-			jwtVerificationInvalidRemoteJWKSDNSLookupFamily,
-			fixture.SecretRootsCert,
-			fixture.ServiceRootsHome,
-		},
+	run(t, "cookieRewritePolicies, empty cookie rewrite on service", testcase{
+		objs: []interface{}{emptyCookieRewritePolicyService, fixture.ServiceRootsKuard},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			k8s.NamespacedNameOf(jwtVerificationInvalidRemoteJWKSDNSLookupFamily): fixture.NewValidCondition().
-				WithError(
-					contour_api_v1.ConditionTypeJWTVerificationError,
-					"RemoteJWKSDNSLookupFamilyInvalid",
-					"Spec.VirtualHost.JWTProviders.RemoteJWKS.DNSLookupFamily has an invalid value \"v7\", must be auto, all, v4 or v6",
-				),
+			{Name: emptyCookieRewritePolicyService.Name, Namespace: emptyCookieRewritePolicyService.Namespace}: fixture.NewValidCondition().
+				WithError(contour_api_v1.ConditionTypeRouteError, "CookieRewritePoliciesInvalid", `no attributes rewritten for cookie "a-cookie" on service cookie rewrite rules`),
 		},
 	})
 
-	jwtVerificationNoProvidersRouteHasRef := &contour_api_v1.HTTPProxy{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: "roots",
-			Name:      "jwt-verification-no-providers-route-has-ref",
-		},
-		Spec: contour_api_v1.HTTPProxySpec{
+	proxyAuthFallback := fixture.NewProxy("roots/fallback-incompat").
+		WithSpec(contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
-				Fqdn: "example.com",
+				Fqdn: "invalid.com",
 				TLS: &contour_api_v1.TLS{
-					SecretName: fixture.SecretRootsCert.Name,
-				},
-			},
-			Routes: []contour_api_v1.Route{
-				{
-					Conditions: []contour_api_v1.MatchCondition{{
-						Prefix: "/foo",
-					}},
-					Services: []contour_api_v1.Service{{
-						Name: "home",
-						Port: 8080,
-					}},
-					JWTVerificationPolicy: &contour_api_v1.JWTVerificationPolicy{Require: "provider-1"},
+					SecretName:                "ssl-cert",
+					EnableFallbackCertificate: true,
+				},
+				Authorization: &contour_api_v1.AuthorizationServer{
+					ExtensionServiceRef: contour_api_v1.ExtensionServiceReference{
+						Namespace: "auth",
+						Name:      "extension",
+					},
 				},
 			},
-		},
-	}
+			Routes: []contour_api_v1.Route{{
+				Services: []contour_api_v1.Service{{Name: "app-server", Port: 80}},
+			}},
+		})
 
-	run(t, "JWT verification no providers defined, route has provider ref", testcase{
-		objs: []interface{}{
-			jwtVerificationNoProvidersRouteHasRef,
-			fixture.SecretRootsCert,
-			fixture.ServiceRootsHome,
-		},
+	run(t, "fallback and client auth is invalid", testcase{
+		objs: []interface{}{fixture.SecretRootsCert, proxyAuthFallback},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			k8s.NamespacedNameOf(jwtVerificationNoProvidersRouteHasRef): fixture.NewValidCondition().
-				WithError(
-					contour_api_v1.ConditionTypeJWTVerificationError,
-					"JWTProviderNotDefined",
-					"Route references an undefined JWT provider \"provider-1\"",
-				),
+			{Name: proxyAuthFallback.Name, Namespace: proxyAuthFallback.Namespace}: fixture.NewValidCondition().WithGeneration(proxyAuthFallback.Generation).
+				WithError(contour_api_v1.ConditionTypeTLSError, "TLSIncompatibleFeatures", "Spec.Virtualhost.TLS fallback & client authorization are incompatible"),
 		},
 	})
 
-	jwtVerificationRouteReferencesNonexistentProvider := &contour_api_v1.HTTPProxy{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: "roots",
-			Name:      "jwt-verification-route-references-nonexistent-provider",
-		},
-		Spec: contour_api_v1.HTTPProxySpec{
+	proxyAuthHTTP := fixture.NewProxy("roots/http").
+		WithSpec(contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
-				Fqdn: "example.com",
-				TLS: &contour_api_v1.TLS{
-					SecretName: fixture.SecretRootsCert.Name,
-				},
-				JWTProviders: []contour_api_v1.JWTProvider{
-					{
-						Name: "provider-1",
-						RemoteJWKS: contour_api_v1.RemoteJWKS{
-							URI: "http://jwt.example.com/jwks.json",
-						},
+				Fqdn: "invalid.com",
+				Authorization: &contour_api_v1.AuthorizationServer{
+					ExtensionServiceRef: contour_api_v1.ExtensionServiceReference{
+						Namespace: "auth",
+						Name:      "extension",
 					},
 				},
 			},
-			Routes: []contour_api_v1.Route{
-				{
-					JWTVerificationPolicy: &contour_api_v1.JWTVerificationPolicy{Require: "nonexistent-provider"},
-					Conditions: []contour_api_v1.MatchCondition{{
-						Prefix: "/foo",
-					}},
-					Services: []contour_api_v1.Service{{
-						Name: "home",
-						Port: 8080,
-					}},
-				},
-			},
-		},
-	}
+			Routes: []contour_api_v1.Route{{
+				Services: []contour_api_v1.Service{{Name: "app-server", Port: 80}},
+			}},
+		})
 
-	run(t, "JWT verification route references nonexistent provider", testcase{
-		objs: []interface{}{
-			jwtVerificationRouteReferencesNonexistentProvider,
-			fixture.SecretRootsCert,
-			fixture.ServiceRootsHome,
-		},
+	run(t, "plain HTTP vhost and client auth is invalid", testcase{
+		objs: []interface{}{fixture.SecretRootsCert, proxyAuthHTTP},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			k8s.NamespacedNameOf(jwtVerificationRouteReferencesNonexistentProvider): fixture.NewValidCondition().
-				WithError(
-					contour_api_v1.ConditionTypeJWTVerificationError,
-					"JWTProviderNotDefined",
-					"Route references an undefined JWT provider \"nonexistent-provider\"",
-				),
+			k8s.NamespacedNameOf(proxyAuthHTTP): fixture.NewValidCondition().WithGeneration(proxyAuthHTTP.Generation).
+				WithError(contour_api_v1.ConditionTypeAuthError, "AuthNotPermitted", "Spec.VirtualHost.Authorization.ExtensionServiceRef can only be defined for root HTTPProxies that terminate TLS"),
 		},
 	})
 
-	jwtVerificationInvalidTLSNotConfigured := &contour_api_v1.HTTPProxy{
+	invalidResponseTimeout := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: "roots",
-			Name:      "jwt-verification-invalid-tls-not-configured",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+			Name:      "invalid-timeouts",
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
 				Fqdn: "example.com",
-				JWTProviders: []contour_api_v1.JWTProvider{
-					{
-						Name: "provider-1",
-						RemoteJWKS: contour_api_v1.RemoteJWKS{
-							URI: "https://jwt.example.com/jwks.json",
-						},
-					},
-				},
 			},
 			Routes: []contour_api_v1.Route{
 				{
-					JWTVerificationPolicy: &contour_api_v1.JWTVerificationPolicy{Require: "provider-1"},
-					Conditions: []contour_api_v1.MatchCondition{{
-						Prefix: "/foo",
-					}},
-					Services: []contour_api_v1.Service{{
-						Name: "home",
-						Port: 8080,
-					}},
+					Services: []contour_api_v1.Service{
+						{
+							Name: fixture.ServiceRootsKuard.Name,
+						},
+					},
+					TimeoutPolicy: &contour_api_v1.TimeoutPolicy{
+						Response: "invalid-val",
+					},
 				},
 			},
 		},
 	}
 
-	run(t, "JWT verification invalid TLS not configured", testcase{
-		objs: []interface{}{
-			jwtVerificationInvalidTLSNotConfigured,
-			fixture.ServiceRootsHome,
-		},
+	run(t, "proxy with invalid response timeout value is invalid", testcase{
+		objs: []interface{}{invalidResponseTimeout, fixture.ServiceRootsKuard},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			k8s.NamespacedNameOf(jwtVerificationInvalidTLSNotConfigured): fixture.NewValidCondition().
-				WithError(
-					contour_api_v1.ConditionTypeJWTVerificationError,
-					"JWTVerificationNotPermitted",
-					"Spec.VirtualHost.JWTProviders can only be defined for root HTTPProxies that terminate TLS",
-				),
+			{
+				Name:      invalidResponseTimeout.Name,
+				Namespace: invalidResponseTimeout.Namespace,
+			}: fixture.NewValidCondition().WithError(contour_api_v1.ConditionTypeRouteError, "TimeoutPolicyNotValid",
+				`route.timeoutPolicy failed to parse: error parsing response timeout: unable to parse timeout string "invalid-val": time: invalid duration "invalid-val"`),
 		},
 	})
 
-	jwtVerificationInvalidTLSPassthroughConfigured := &contour_api_v1.HTTPProxy{
+	invalidIdleTimeout := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: "roots",
-			Name:      "jwt-verification-invalid-tls-passthrough-configured",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+			Name:      "invalid-timeouts",
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
 				Fqdn: "example.com",
-				TLS: &contour_api_v1.TLS{
-					Passthrough: true,
-				},
-				JWTProviders: []contour_api_v1.JWTProvider{
-					{
-						Name: "provider-1",
-						RemoteJWKS: contour_api_v1.RemoteJWKS{
-							URI: "https://jwt.example.com/jwks.json",
-						},
-					},
-				},
 			},
 			Routes: []contour_api_v1.Route{
 				{
-					JWTVerificationPolicy: &contour_api_v1.JWTVerificationPolicy{Require: "provider-1"},
-					Conditions: []contour_api_v1.MatchCondition{{
-						Prefix: "/foo",
-					}},
-					Services: []contour_api_v1.Service{{
-						Name: "home",
-						Port: 8080,
-					}},
+					Services: []contour_api_v1.Service{
+						{
+							Name: fixture.ServiceRootsKuard.Name,
+						},
+					},
+					TimeoutPolicy: &contour_api_v1.TimeoutPolicy{
+						Idle: "invalid-val",
+					},
 				},
 			},
 		},
 	}
 
-	run(t, "JWT verification invalid TLS passthrough configured", testcase{
-		objs: []interface{}{
-			jwtVerificationInvalidTLSPassthroughConfigured,
-			fixture.ServiceRootsHome,
-		},
+	run(t, "proxy with invalid idle timeout value is invalid", testcase{
+		objs: []interface{}{invalidIdleTimeout, fixture.ServiceRootsKuard},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			k8s.NamespacedNameOf(jwtVerificationInvalidTLSPassthroughConfigured): fixture.NewValidCondition().
-				WithError(
-					contour_api_v1.ConditionTypeJWTVerificationError,
-					"JWTVerificationNotPermitted",
-					"Spec.VirtualHost.JWTProviders can only be defined for root HTTPProxies that terminate TLS",
-				),
+			{
+				Name:      invalidIdleTimeout.Name,
+				Namespace: invalidIdleTimeout.Namespace,
+			}: fixture.NewValidCondition().WithError(contour_api_v1.ConditionTypeRouteError, "TimeoutPolicyNotValid",
+				`route.timeoutPolicy failed to parse: error parsing idle timeout: unable to parse timeout string "invalid-val": time: invalid duration "invalid-val"`),
 		},
 	})
 
-	jwtVerificationInvalidTLSFallbackConfigured := &contour_api_v1.HTTPProxy{
+	// issue 3197: Fallback and passthrough HTTPProxy directive should emit a config error
+	tlsPassthroughAndFallback := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: "roots",
-			Name:      "jwt-verification-invalid-tls-fallback-configured",
+			Namespace:  "roots",
+			Name:       "example",
+			Generation: 24,
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
-				Fqdn: "example.com",
 				TLS: &contour_api_v1.TLS{
+					Passthrough:               true,
 					EnableFallbackCertificate: true,
 				},
-				JWTProviders: []contour_api_v1.JWTProvider{
-					{
-						Name: "provider-1",
-						RemoteJWKS: contour_api_v1.RemoteJWKS{
-							URI: "https://jwt.example.com/jwks.json",
-						},
-					},
-				},
-			},
-			Routes: []contour_api_v1.Route{
-				{
-					JWTVerificationPolicy: &contour_api_v1.JWTVerificationPolicy{Require: "provider-1"},
-					Conditions: []contour_api_v1.MatchCondition{{
-						Prefix: "/foo",
-					}},
-					Services: []contour_api_v1.Service{{
-						Name: "home",
-						Port: 8080,
-					}},
-				},
+				Fqdn: "example.com",
 			},
+			Routes: []contour_api_v1.Route{{
+				Conditions: []contour_api_v1.MatchCondition{{
+					Prefix: "/foo",
+				}},
+				Services: []contour_api_v1.Service{{
+					Name: "home",
+					Port: 8080,
+				}},
+			}},
 		},
 	}
 
-	run(t, "JWT verification invalid TLS fallback configured", testcase{
-		objs: []interface{}{
-			jwtVerificationInvalidTLSFallbackConfigured,
-			fixture.ServiceRootsHome,
-		},
+	run(t, "TLS with passthrough and fallback cert enabled is invalid", testcase{
+		objs: []interface{}{tlsPassthroughAndFallback, fixture.ServiceRootsHome},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			k8s.NamespacedNameOf(jwtVerificationInvalidTLSFallbackConfigured): fixture.NewValidCondition().
-				WithError(
-					contour_api_v1.ConditionTypeJWTVerificationError,
-					"JWTVerificationNotPermitted",
-					"Spec.VirtualHost.JWTProviders can only be defined for root HTTPProxies that terminate TLS",
-				),
+			{Name: tlsPassthroughAndFallback.Name, Namespace: tlsPassthroughAndFallback.Namespace}: fixture.NewValidCondition().
+				WithGeneration(tlsPassthroughAndFallback.Generation).WithError(
+				contour_api_v1.ConditionTypeTLSError, "TLSIncompatibleFeatures",
+				`Spec.VirtualHost.TLS: both Passthrough and enableFallbackCertificate were specified`,
+			),
 		},
 	})
-
-	jwtVerificationInvalidRequireAndDisabledSpecified := &contour_api_v1.HTTPProxy{
+	tlsPassthrough := &contour_api_v1.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  "roots",
+			Name:       "example",
+			Generation: 24,
+		},
+		Spec: contour_api_v1.HTTPProxySpec{
+			VirtualHost: &contour_api_v1.VirtualHost{
+				TLS: &contour_api_v1.TLS{
+					Passthrough:               true,
+					EnableFallbackCertificate: false,
+				},
+				Fqdn: "example.com",
+			},
+			Routes: []contour_api_v1.Route{{
+				Conditions: []contour_api_v1.MatchCondition{{
+					Prefix: "/foo",
+				}},
+				Services: []contour_api_v1.Service{{
+					Name: "home",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	run(t, "valid TLS passthrough", testcase{
+		objs: []interface{}{tlsPassthrough, fixture.ServiceRootsHome},
+		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
+			{Name: tlsPassthrough.Name, Namespace: tlsPassthrough.Namespace}: fixture.NewValidCondition().
+				WithGeneration(tlsPassthrough.Generation).
+				Valid(),
+		},
+	})
+
+	multipleRouteAction := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: "roots",
-			Name:      "jwt-verification-invalid-require-and-disabled-specified",
+			Name:      "multipleRouteAction",
+		},
+		Spec: contour_api_v1.HTTPProxySpec{
+			VirtualHost: &contour_api_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []contour_api_v1.Route{{
+				Conditions: []contour_api_v1.MatchCondition{{
+					Prefix: "/foo",
+				}},
+				Services: []contour_api_v1.Service{{
+					Name: "home",
+					Port: 8080,
+				}},
+				DirectResponsePolicy: &contour_api_v1.HTTPDirectResponsePolicy{
+					StatusCode: 200,
+					Body:       "success",
+				},
+			}},
+		},
+	}
+	run(t, "Selecting more than one routeAction is invalid", testcase{
+		objs: []interface{}{multipleRouteAction},
+		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
+			{Name: multipleRouteAction.Name, Namespace: multipleRouteAction.Namespace}: fixture.NewValidCondition().
+				WithError(contour_api_v1.ConditionTypeRouteError, "RouteActionCountNotValid",
+					"must set exactly one of route.services or route.requestRedirectPolicy or route.directResponsePolicy"),
+		},
+	})
+
+	invalidAllowOrigin := &contour_api_v1.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+			Name:      "invalid-alloworigin",
+		},
+		Spec: contour_api_v1.HTTPProxySpec{
+			VirtualHost: &contour_api_v1.VirtualHost{
+				Fqdn: "example.com",
+				CORSPolicy: &contour_api_v1.CORSPolicy{
+					AllowOrigin:  []string{"example-2.com", "**"},
+					AllowMethods: []contour_api_v1.CORSHeaderValue{"GET"},
+				},
+			},
+			Routes: []contour_api_v1.Route{
+				{
+					Services: []contour_api_v1.Service{
+						{
+							Name: fixture.ServiceRootsKuard.Name,
+							Port: 8080,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	run(t, "proxy with invalid allow origin is invalid", testcase{
+		objs: []interface{}{invalidAllowOrigin, fixture.ServiceRootsKuard},
+		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
+			{
+				Name:      invalidAllowOrigin.Name,
+				Namespace: invalidAllowOrigin.Namespace,
+			}: fixture.NewValidCondition().WithError(contour_api_v1.ConditionTypeCORSError, "PolicyDidNotParse",
+				`Spec.VirtualHost.CORSPolicy: invalid allowed origin "**": allowed origin is invalid exact match and invalid regex match`),
+		},
+	})
+
+	jwtVerificationValidProxy := &contour_api_v1.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "jwt-verification-valid-proxy",
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
@@ -3828,19 +3667,20 @@ func TestDAGStatus(t *testing.T) {
 				},
 				JWTProviders: []contour_api_v1.JWTProvider{
 					{
-						Name: "provider-1",
+						Name:      "provider-1",
+						Issuer:    "jwt.example.com",
+						Audiences: []string{"foo", "bar"},
 						RemoteJWKS: contour_api_v1.RemoteJWKS{
-							URI: "https://jwt.example.com/jwks.json",
+							URI:           "https://jwt.example.com/jwks.json",
+							Timeout:       "10s",
+							CacheDuration: "1h",
 						},
 					},
 				},
 			},
 			Routes: []contour_api_v1.Route{
 				{
-					JWTVerificationPolicy: &contour_api_v1.JWTVerificationPolicy{
-						Require:  "provider-1",
-						Disabled: true,
-					},
+					JWTVerificationPolicy: &contour_api_v1.JWTVerificationPolicy{Require: "provider-1"},
 					Conditions: []contour_api_v1.MatchCondition{{
 						Prefix: "/foo",
 					}},
@@ -3853,26 +3693,21 @@ func TestDAGStatus(t *testing.T) {
 		},
 	}
 
-	run(t, "JWT verification invalid route specifies both requires and disabled", testcase{
+	run(t, "JWT verification valid proxy", testcase{
 		objs: []interface{}{
-			jwtVerificationInvalidRequireAndDisabledSpecified,
+			jwtVerificationValidProxy,
 			fixture.SecretRootsCert,
 			fixture.ServiceRootsHome,
 		},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			k8s.NamespacedNameOf(jwtVerificationInvalidRequireAndDisabledSpecified): fixture.NewValidCondition().
-				WithError(
-					contour_api_v1.ConditionTypeJWTVerificationError,
-					"InvalidJWTVerificationPolicy",
-					"route's JWT verification policy cannot specify both require and disabled",
-				),
+			k8s.NamespacedNameOf(jwtVerificationValidProxy): fixture.NewValidCondition().Valid(),
 		},
 	})
 
-	jwtVerificationUpstreamValidationForHTTPJWKS := &contour_api_v1.HTTPProxy{
+	jwtVerificationDuplicateProviders := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: "roots",
-			Name:      "jwt-verification-upstream-validation-for-http-jwks",
+			Name:      "jwt-verification-duplicate-provider-names",
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
@@ -3884,22 +3719,21 @@ func TestDAGStatus(t *testing.T) {
 					{
 						Name: "provider-1",
 						RemoteJWKS: contour_api_v1.RemoteJWKS{
-							URI: "http://jwt.example.com/jwks.json",
-							UpstreamValidation: &contour_api_v1.UpstreamValidation{
-								CACertificate: "foo",
-								SubjectName:   "jwt.example.com",
-							},
+							URI: "https://jwt.example.com/jwks.json",
+						},
+					},
+					{
+						Name: "provider-1",
+						RemoteJWKS: contour_api_v1.RemoteJWKS{
+							URI: "https://jwt.example.com/jwks.json",
 						},
 					},
 				},
 			},
 			Routes: []contour_api_v1.Route{
 				{
-					JWTVerificationPolicy: &contour_api_v1.JWTVerificationPolicy{
-						Require: "provider-1",
-					},
 					Conditions: []contour_api_v1.MatchCondition{{
-						Prefix: "/",
+						Prefix: "/foo",
 					}},
 					Services: []contour_api_v1.Service{{
 						Name: "home",
@@ -3910,26 +3744,26 @@ func TestDAGStatus(t *testing.T) {
 		},
 	}
 
-	run(t, "JWT verification invalid upstream validation specified for HTTP JWKS", testcase{
+	run(t, "JWT verification duplicate provider names", testcase{
 		objs: []interface{}{
-			jwtVerificationUpstreamValidationForHTTPJWKS,
+			jwtVerificationDuplicateProviders,
 			fixture.SecretRootsCert,
 			fixture.ServiceRootsHome,
 		},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			k8s.NamespacedNameOf(jwtVerificationUpstreamValidationForHTTPJWKS): fixture.NewValidCondition().
+			k8s.NamespacedNameOf(jwtVerificationDuplicateProviders): fixture.NewValidCondition().
 				WithError(
 					contour_api_v1.ConditionTypeJWTVerificationError,
-					"RemoteJWKSUpstreamValidationInvalid",
-					"Spec.VirtualHost.JWTProviders.RemoteJWKS.UpstreamValidation must not be specified when URI scheme is http.",
+					"DuplicateProviderName",
+					"Spec.VirtualHost.JWTProviders is invalid: duplicate name provider-1",
 				),
 		},
 	})
 
-	jwtVerificationUpstreamValidationCACertDoesNotExist := &contour_api_v1.HTTPProxy{
+	jwtVerificationMultipleDefaults := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: "roots",
-			Name:      "jwt-verification-upstream-validation-cacert-does-not-exist",
+			Name:      "jwt-verification-multiple-defaults",
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
@@ -3939,24 +3773,25 @@ func TestDAGStatus(t *testing.T) {
 				},
 				JWTProviders: []contour_api_v1.JWTProvider{
 					{
-						Name: "provider-1",
+						Name:    "provider-1",
+						Default: true,
+						RemoteJWKS: contour_api_v1.RemoteJWKS{
+							URI: "https://jwt.example.com/jwks.json",
+						},
+					},
+					{
+						Name:    "provider-2",
+						Default: true,
 						RemoteJWKS: contour_api_v1.RemoteJWKS{
 							URI: "https://jwt.example.com/jwks.json",
-							UpstreamValidation: &contour_api_v1.UpstreamValidation{
-								CACertificate: "nonexistent",
-								SubjectName:   "jwt.example.com",
-							},
 						},
 					},
 				},
 			},
 			Routes: []contour_api_v1.Route{
 				{
-					JWTVerificationPolicy: &contour_api_v1.JWTVerificationPolicy{
-						Require: "provider-1",
-					},
 					Conditions: []contour_api_v1.MatchCondition{{
-						Prefix: "/",
+						Prefix: "/foo",
 					}},
 					Services: []contour_api_v1.Service{{
 						Name: "home",
@@ -3967,34 +3802,26 @@ func TestDAGStatus(t *testing.T) {
 		},
 	}
 
-	run(t, "JWT verification invalid upstream validation CA cert does not exist", testcase{
+	run(t, "JWT verification multiple default providers", testcase{
 		objs: []interface{}{
-			jwtVerificationUpstreamValidationCACertDoesNotExist,
+			jwtVerificationMultipleDefaults,
 			fixture.SecretRootsCert,
 			fixture.ServiceRootsHome,
 		},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			k8s.NamespacedNameOf(jwtVerificationUpstreamValidationCACertDoesNotExist): fixture.NewValidCondition().
+			k8s.NamespacedNameOf(jwtVerificationMultipleDefaults): fixture.NewValidCondition().
 				WithError(
 					contour_api_v1.ConditionTypeJWTVerificationError,
-					"RemoteJWKSUpstreamValidationInvalid",
-					"Spec.VirtualHost.JWTProviders.RemoteJWKS.UpstreamValidation is invalid: invalid CA Secret \"roots/nonexistent\": Secret not found",
+					"MultipleDefaultProvidersSpecified",
+					"Spec.VirtualHost.JWTProviders is invalid: at most one provider can be set as the default",
 				),
 		},
 	})
 
-	jwksInvalidCACert := &v1.Secret{
-		ObjectMeta: fixture.ObjectMeta("roots/cacert"),
-		Type:       v1.SecretTypeOpaque,
-		Data: map[string][]byte{
-			"wrong-key": []byte(fixture.CERTIFICATE),
-		},
-	}
-
-	jwtVerificationUpstreamValidationCACertInvalid := &contour_api_v1.HTTPProxy{
+	jwtVerificationInvalidRemoteJWKSURI := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: "roots",
-			Name:      "jwt-verification-upstream-validation-cacert-invalid",
+			Name:      "jwt-verification-invalid-remote-jwks-uri",
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
@@ -4006,22 +3833,15 @@ func TestDAGStatus(t *testing.T) {
 					{
 						Name: "provider-1",
 						RemoteJWKS: contour_api_v1.RemoteJWKS{
-							URI: "https://jwt.example.com/jwks.json",
-							UpstreamValidation: &contour_api_v1.UpstreamValidation{
-								CACertificate: "cacert",
-								SubjectName:   "jwt.example.com",
-							},
+							URI: ":/invalid-uri",
 						},
 					},
 				},
 			},
 			Routes: []contour_api_v1.Route{
 				{
-					JWTVerificationPolicy: &contour_api_v1.JWTVerificationPolicy{
-						Require: "provider-1",
-					},
 					Conditions: []contour_api_v1.MatchCondition{{
-						Prefix: "/",
+						Prefix: "/foo",
 					}},
 					Services: []contour_api_v1.Service{{
 						Name: "home",
@@ -4032,34 +3852,26 @@ func TestDAGStatus(t *testing.T) {
 		},
 	}
 
-	run(t, "JWT verification invalid upstream validation CA cert invalid", testcase{
+	run(t, "JWT verification invalid remote JWKS URI", testcase{
 		objs: []interface{}{
-			jwtVerificationUpstreamValidationCACertInvalid,
+			jwtVerificationInvalidRemoteJWKSURI,
 			fixture.SecretRootsCert,
 			fixture.ServiceRootsHome,
-			jwksInvalidCACert,
 		},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			k8s.NamespacedNameOf(jwtVerificationUpstreamValidationCACertInvalid): fixture.NewValidCondition().
+			k8s.NamespacedNameOf(jwtVerificationInvalidRemoteJWKSURI): fixture.NewValidCondition().
 				WithError(
 					contour_api_v1.ConditionTypeJWTVerificationError,
-					"RemoteJWKSUpstreamValidationInvalid",
-					"Spec.VirtualHost.JWTProviders.RemoteJWKS.UpstreamValidation is invalid: invalid CA Secret \"roots/cacert\": empty \"ca.crt\" key",
+					"RemoteJWKSURIInvalid",
+					"Spec.VirtualHost.JWTProviders.RemoteJWKS.URI is invalid: parse \":/invalid-uri\": missing protocol scheme",
 				),
 		},
 	})
 
-	jwksCACertDifferentNamespace := &v1.Secret{
-		ObjectMeta: fixture.ObjectMeta("default/cacert"),
-		Data: map[string][]byte{
-			"ca.crt": []byte(fixture.CERTIFICATE),
-		},
-	}
-
-	jwtVerificationUpstreamValidationCACertNotDelegated := &contour_api_v1.HTTPProxy{
+	jwtVerificationInvalidRemoteJWKSScheme := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: "roots",
-			Name:      "jwt-verification-upstream-validation-cacert-not-delegated",
+			Name:      "jwt-verification-invalid-remote-jwks-scheme",
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
@@ -4071,22 +3883,15 @@ func TestDAGStatus(t *testing.T) {
 					{
 						Name: "provider-1",
 						RemoteJWKS: contour_api_v1.RemoteJWKS{
-							URI: "https://jwt.example.com/jwks.json",
-							UpstreamValidation: &contour_api_v1.UpstreamValidation{
-								CACertificate: "default/cacert",
-								SubjectName:   "jwt.example.com",
-							},
+							URI: "ftp://jwt.example.com/jwks.json",
 						},
 					},
 				},
 			},
 			Routes: []contour_api_v1.Route{
 				{
-					JWTVerificationPolicy: &contour_api_v1.JWTVerificationPolicy{
-						Require: "provider-1",
-					},
 					Conditions: []contour_api_v1.MatchCondition{{
-						Prefix: "/",
+						Prefix: "/foo",
 					}},
 					Services: []contour_api_v1.Service{{
 						Name: "home",
@@ -4097,363 +3902,1711 @@ func TestDAGStatus(t *testing.T) {
 		},
 	}
 
-	run(t, "JWT verification invalid upstream validation CA cert not delegated", testcase{
+	run(t, "JWT verification invalid remote JWKS scheme", testcase{
 		objs: []interface{}{
-			jwtVerificationUpstreamValidationCACertNotDelegated,
+			jwtVerificationInvalidRemoteJWKSScheme,
 			fixture.SecretRootsCert,
 			fixture.ServiceRootsHome,
-			jwksCACertDifferentNamespace,
 		},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			k8s.NamespacedNameOf(jwtVerificationUpstreamValidationCACertNotDelegated): fixture.NewValidCondition().
+			k8s.NamespacedNameOf(jwtVerificationInvalidRemoteJWKSScheme): fixture.NewValidCondition().
 				WithError(
 					contour_api_v1.ConditionTypeJWTVerificationError,
-					"RemoteJWKSCACertificateNotDelegated",
-					"Spec.VirtualHost.JWTProviders.RemoteJWKS.UpstreamValidation.CACertificate Secret \"default/cacert\" is not configured for certificate delegation",
+					"RemoteJWKSSchemeInvalid",
+					"Spec.VirtualHost.JWTProviders.RemoteJWKS.URI has invalid scheme \"ftp\", must be http or https",
 				),
 		},
 	})
 
-	// proxyWithInvalidSlowStartWindow is invalid because it has invalid window size syntax.
-	proxyWithInvalidSlowStartWindow := &contour_api_v1.HTTPProxy{
+	jwtVerificationInvalidRemoteJWKSTimeout := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: "roots",
-			Name:      "slow-start-invalid-window",
+			Name:      "jwt-verification-invalid-remote-jwks-timeout",
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
-				Fqdn: "www.example.com",
-			},
-			Routes: []contour_api_v1.Route{{
-				Services: []contour_api_v1.Service{{
-					Name: "home",
-					Port: 8080,
-					SlowStartPolicy: &contour_api_v1.SlowStartPolicy{
-						Window: "invalid",
+				Fqdn: "example.com",
+				TLS: &contour_api_v1.TLS{
+					SecretName: fixture.SecretRootsCert.Name,
+				},
+				JWTProviders: []contour_api_v1.JWTProvider{
+					{
+						Name: "provider-1",
+						RemoteJWKS: contour_api_v1.RemoteJWKS{
+							URI:     "http://jwt.example.com/jwks.json",
+							Timeout: "invalid-timeout-string",
+						},
 					},
-				}},
-			}},
+				},
+			},
+			Routes: []contour_api_v1.Route{
+				{
+					Conditions: []contour_api_v1.MatchCondition{{
+						Prefix: "/foo",
+					}},
+					Services: []contour_api_v1.Service{{
+						Name: "home",
+						Port: 8080,
+					}},
+				},
+			},
 		},
 	}
 
-	// proxyWithInvalidSlowStartAggression is invalid because it has invalid aggression syntax.
-	proxyWithInvalidSlowStartAggression := &contour_api_v1.HTTPProxy{
+	run(t, "JWT verification invalid remote JWKS timeout", testcase{
+		objs: []interface{}{
+			jwtVerificationInvalidRemoteJWKSTimeout,
+			fixture.SecretRootsCert,
+			fixture.ServiceRootsHome,
+		},
+		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
+			k8s.NamespacedNameOf(jwtVerificationInvalidRemoteJWKSTimeout): fixture.NewValidCondition().
+				WithError(
+					contour_api_v1.ConditionTypeJWTVerificationError,
+					"RemoteJWKSTimeoutInvalid",
+					"Spec.VirtualHost.JWTProviders.RemoteJWKS.Timeout is invalid: time: invalid duration \"invalid-timeout-string\"",
+				),
+		},
+	})
+
+	jwtVerificationInvalidRemoteJWKSCacheDuration := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: "roots",
-			Name:      "slow-start-invalid-aggression",
+			Name:      "jwt-verification-invalid-remote-jwks-cache-duration",
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
-				Fqdn: "www.example.com",
-			},
-			Routes: []contour_api_v1.Route{{
-				Services: []contour_api_v1.Service{{
-					Name: "home",
-					Port: 8080,
-					SlowStartPolicy: &contour_api_v1.SlowStartPolicy{
-						Window:     "5s",
-						Aggression: "invalid",
+				Fqdn: "example.com",
+				TLS: &contour_api_v1.TLS{
+					SecretName: fixture.SecretRootsCert.Name,
+				},
+				JWTProviders: []contour_api_v1.JWTProvider{
+					{
+						Name: "provider-1",
+						RemoteJWKS: contour_api_v1.RemoteJWKS{
+							URI:           "http://jwt.example.com/jwks.json",
+							CacheDuration: "invalid-duration-string",
+						},
 					},
-				}},
-			}},
+				},
+			},
+			Routes: []contour_api_v1.Route{
+				{
+					Conditions: []contour_api_v1.MatchCondition{{
+						Prefix: "/foo",
+					}},
+					Services: []contour_api_v1.Service{{
+						Name: "home",
+						Port: 8080,
+					}},
+				},
+			},
 		},
 	}
 
-	// proxyWithInvalidSlowStartLBStrategy is invalid because route has LB strategy that does not support slow start.
-	proxyWithInvalidSlowStartLBStrategy := &contour_api_v1.HTTPProxy{
+	run(t, "JWT verification invalid remote JWKS cache duration", testcase{
+		objs: []interface{}{
+			jwtVerificationInvalidRemoteJWKSCacheDuration,
+			fixture.SecretRootsCert,
+			fixture.ServiceRootsHome,
+		},
+		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
+			k8s.NamespacedNameOf(jwtVerificationInvalidRemoteJWKSCacheDuration): fixture.NewValidCondition().
+				WithError(
+					contour_api_v1.ConditionTypeJWTVerificationError,
+					"RemoteJWKSCacheDurationInvalid",
+					"Spec.VirtualHost.JWTProviders.RemoteJWKS.CacheDuration is invalid: time: invalid duration \"invalid-duration-string\"",
+				),
+		},
+	})
+
+	jwtVerificationInvalidRemoteJWKSDNSLookupFamily := &contour_api_v1.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: "roots",
-			Name:      "slow-start-invalid-lb-strategy",
+			Name:      "jwt-verification-invalid-remote-jwks-dns-lookup-family",
 		},
 		Spec: contour_api_v1.HTTPProxySpec{
 			VirtualHost: &contour_api_v1.VirtualHost{
-				Fqdn: "www.example.com",
-			},
-			Routes: []contour_api_v1.Route{{
-				LoadBalancerPolicy: &contour_api_v1.LoadBalancerPolicy{
-					Strategy: LoadBalancerPolicyCookie,
+				Fqdn: "example.com",
+				TLS: &contour_api_v1.TLS{
+					SecretName: fixture.SecretRootsCert.Name,
 				},
-				Services: []contour_api_v1.Service{{
-					Name: "home",
-					Port: 8080,
-					SlowStartPolicy: &contour_api_v1.SlowStartPolicy{
-						Window: "5s",
+				JWTProviders: []contour_api_v1.JWTProvider{
+					{
+						Name: "provider-1",
+						RemoteJWKS: contour_api_v1.RemoteJWKS{
+							URI:             "http://jwt.example.com/jwks.json",
+							DNSLookupFamily: "v7",
+						},
 					},
-				}},
-			}},
+				},
+			},
+			Routes: []contour_api_v1.Route{
+				{
+					Conditions: []contour_api_v1.MatchCondition{{
+						Prefix: "/foo",
+					}},
+					Services: []contour_api_v1.Service{{
+						Name: "home",
+						Port: 8080,
+					}},
+				},
+			},
 		},
 	}
 
-	run(t, "Slow start with invalid window syntax", testcase{
+	run(t, "JWT verification invalid remote JWKS DNS lookup family", testcase{
 		objs: []interface{}{
-			proxyWithInvalidSlowStartWindow,
+			jwtVerificationInvalidRemoteJWKSDNSLookupFamily,
+			fixture.SecretRootsCert,
 			fixture.ServiceRootsHome,
 		},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			k8s.NamespacedNameOf(proxyWithInvalidSlowStartWindow): fixture.NewValidCondition().
+			k8s.NamespacedNameOf(jwtVerificationInvalidRemoteJWKSDNSLookupFamily): fixture.NewValidCondition().
 				WithError(
-					contour_api_v1.ConditionTypeServiceError,
-					"SlowStartInvalid",
-					"error parsing window: time: invalid duration \"invalid\" on slow start",
+					contour_api_v1.ConditionTypeJWTVerificationError,
+					"RemoteJWKSDNSLookupFamilyInvalid",
+					"Spec.VirtualHost.JWTProviders.RemoteJWKS.DNSLookupFamily has an invalid value \"v7\", must be auto, all, v4 or v6",
 				),
 		},
 	})
 
-	run(t, "Slow start with invalid aggression syntax", testcase{
+	jwtVerificationNoProvidersRouteHasRef := &contour_api_v1.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "jwt-verification-no-providers-route-has-ref",
+		},
+		Spec: contour_api_v1.HTTPProxySpec{
+			VirtualHost: &contour_api_v1.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &contour_api_v1.TLS{
+					SecretName: fixture.SecretRootsCert.Name,
+				},
+			},
+			Routes: []contour_api_v1.Route{
+				{
+					Conditions: []contour_api_v1.MatchCondition{{
+						Prefix: "/foo",
+					}},
+					Services: []contour_api_v1.Service{{
+						Name: "home",
+						Port: 8080,
+					}},
+					JWTVerificationPolicy: &contour_api_v1.JWTVerificationPolicy{Require: "provider-1"},
+				},
+			},
+		},
+	}
+
+	run(t, "JWT verification no providers defined, route has provider ref", testcase{
 		objs: []interface{}{
-			proxyWithInvalidSlowStartAggression,
+			jwtVerificationNoProvidersRouteHasRef,
+			fixture.SecretRootsCert,
 			fixture.ServiceRootsHome,
 		},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			k8s.NamespacedNameOf(proxyWithInvalidSlowStartAggression): fixture.NewValidCondition().
+			k8s.NamespacedNameOf(jwtVerificationNoProvidersRouteHasRef): fixture.NewValidCondition().
 				WithError(
-					contour_api_v1.ConditionTypeServiceError,
-					"SlowStartInvalid",
-					"error parsing aggression: \"invalid\" is not a decimal number on slow start",
+					contour_api_v1.ConditionTypeJWTVerificationError,
+					"JWTProviderNotDefined",
+					"Route references an undefined JWT provider \"provider-1\"",
 				),
 		},
 	})
 
-	run(t, "Slow start with load balancer strategy that does not support slow start", testcase{
+	jwtVerificationRouteReferencesNonexistentProvider := &contour_api_v1.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "jwt-verification-route-references-nonexistent-provider",
+		},
+		Spec: contour_api_v1.HTTPProxySpec{
+			VirtualHost: &contour_api_v1.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &contour_api_v1.TLS{
+					SecretName: fixture.SecretRootsCert.Name,
+				},
+				JWTProviders: []contour_api_v1.JWTProvider{
+					{
+						Name: "provider-1",
+						RemoteJWKS: contour_api_v1.RemoteJWKS{
+							URI: "http://jwt.example.com/jwks.json",
+						},
+					},
+				},
+			},
+			Routes: []contour_api_v1.Route{
+				{
+					JWTVerificationPolicy: &contour_api_v1.JWTVerificationPolicy{Require: "nonexistent-provider"},
+					Conditions: []contour_api_v1.MatchCondition{{
+						Prefix: "/foo",
+					}},
+					Services: []contour_api_v1.Service{{
+						Name: "home",
+						Port: 8080,
+					}},
+				},
+			},
+		},
+	}
+
+	run(t, "JWT verification route references nonexistent provider", testcase{
 		objs: []interface{}{
-			proxyWithInvalidSlowStartLBStrategy,
+			jwtVerificationRouteReferencesNonexistentProvider,
+			fixture.SecretRootsCert,
 			fixture.ServiceRootsHome,
 		},
 		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
-			k8s.NamespacedNameOf(proxyWithInvalidSlowStartLBStrategy): fixture.NewValidCondition().
+			k8s.NamespacedNameOf(jwtVerificationRouteReferencesNonexistentProvider): fixture.NewValidCondition().
 				WithError(
-					contour_api_v1.ConditionTypeServiceError,
-					"SlowStartInvalid",
-					"slow start is only supported with RoundRobin or WeightedLeastRequest load balancer strategy",
+					contour_api_v1.ConditionTypeJWTVerificationError,
+					"JWTProviderNotDefined",
+					"Route references an undefined JWT provider \"nonexistent-provider\"",
 				),
 		},
 	})
-}
 
-func validGatewayStatusUpdate(listenerName string, kind gatewayapi_v1beta1.Kind, attachedRoutes int) []*status.GatewayStatusUpdate {
-	// This applies to tests that the listener doesn't have allowed kind configured
-	// hence the wanted allowed kind is determined by the listener protocol only.
-	var supportedKinds []gatewayapi_v1beta1.RouteGroupKind
+	jwtVerificationInvalidTLSNotConfigured := &contour_api_v1.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "jwt-verification-invalid-tls-not-configured",
+		},
+		Spec: contour_api_v1.HTTPProxySpec{
+			VirtualHost: &contour_api_v1.VirtualHost{
+				Fqdn: "example.com",
+				JWTProviders: []contour_api_v1.JWTProvider{
+					{
+						Name: "provider-1",
+						RemoteJWKS: contour_api_v1.RemoteJWKS{
+							URI: "https://jwt.example.com/jwks.json",
+						},
+					},
+				},
+			},
+			Routes: []contour_api_v1.Route{
+				{
+					JWTVerificationPolicy: &contour_api_v1.JWTVerificationPolicy{Require: "provider-1"},
+					Conditions: []contour_api_v1.MatchCondition{{
+						Prefix: "/foo",
+					}},
+					Services: []contour_api_v1.Service{{
+						Name: "home",
+						Port: 8080,
+					}},
+				},
+			},
+		},
+	}
 
-	if kind == "HTTPRoute" || kind == "GRPCRoute" {
-		supportedKinds = append(supportedKinds, gatewayapi_v1beta1.RouteGroupKind{
-			Group: ref.To(gatewayapi_v1beta1.Group(gatewayapi_v1beta1.GroupName)),
-			Kind:  "HTTPRoute",
-		})
-		supportedKinds = append(supportedKinds, gatewayapi_v1beta1.RouteGroupKind{
-			Group: ref.To(gatewayapi_v1beta1.Group(gatewayapi_v1beta1.GroupName)),
-			Kind:  "GRPCRoute",
-		})
-	} else {
-		supportedKinds = append(supportedKinds, gatewayapi_v1beta1.RouteGroupKind{
-			Group: ref.To(gatewayapi_v1beta1.Group(gatewayapi_v1beta1.GroupName)),
-			Kind:  kind,
-		})
+	run(t, "JWT verification invalid TLS not configured", testcase{
+		objs: []interface{}{
+			jwtVerificationInvalidTLSNotConfigured,
+			fixture.ServiceRootsHome,
+		},
+		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
+			k8s.NamespacedNameOf(jwtVerificationInvalidTLSNotConfigured): fixture.NewValidCondition().
+				WithError(
+					contour_api_v1.ConditionTypeJWTVerificationError,
+					"JWTVerificationNotPermitted",
+					"Spec.VirtualHost.JWTProviders can only be defined for root HTTPProxies that terminate TLS",
+				),
+		},
+	})
+
+	jwtVerificationInvalidTLSPassthroughConfigured := &contour_api_v1.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "jwt-verification-invalid-tls-passthrough-configured",
+		},
+		Spec: contour_api_v1.HTTPProxySpec{
+			VirtualHost: &contour_api_v1.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &contour_api_v1.TLS{
+					Passthrough: true,
+				},
+				JWTProviders: []contour_api_v1.JWTProvider{
+					{
+						Name: "provider-1",
+						RemoteJWKS: contour_api_v1.RemoteJWKS{
+							URI: "https://jwt.example.com/jwks.json",
+						},
+					},
+				},
+			},
+			Routes: []contour_api_v1.Route{
+				{
+					JWTVerificationPolicy: &contour_api_v1.JWTVerificationPolicy{Require: "provider-1"},
+					Conditions: []contour_api_v1.MatchCondition{{
+						Prefix: "/foo",
+					}},
+					Services: []contour_api_v1.Service{{
+						Name: "home",
+						Port: 8080,
+					}},
+				},
+			},
+		},
 	}
 
-	return []*status.GatewayStatusUpdate{
-		{
-			FullName: types.NamespacedName{Namespace: "projectcontour", Name: "contour"},
-			Conditions: map[gatewayapi_v1beta1.GatewayConditionType]metav1.Condition{
-				gatewayapi_v1beta1.GatewayConditionAccepted: gatewayAcceptedCondition(),
-				gatewayapi_v1beta1.GatewayConditionProgrammed: {
-					Type:    string(gatewayapi_v1beta1.GatewayConditionProgrammed),
-					Status:  contour_api_v1.ConditionTrue,
-					Reason:  string(gatewayapi_v1beta1.GatewayReasonProgrammed),
-					Message: status.MessageValidGateway,
+	run(t, "JWT verification invalid TLS passthrough configured", testcase{
+		objs: []interface{}{
+			jwtVerificationInvalidTLSPassthroughConfigured,
+			fixture.ServiceRootsHome,
+		},
+		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
+			k8s.NamespacedNameOf(jwtVerificationInvalidTLSPassthroughConfigured): fixture.NewValidCondition().
+				WithError(
+					contour_api_v1.ConditionTypeJWTVerificationError,
+					"JWTVerificationNotPermitted",
+					"Spec.VirtualHost.JWTProviders can only be defined for root HTTPProxies that terminate TLS",
+				),
+		},
+	})
+
+	jwtVerificationInvalidTLSFallbackConfigured := &contour_api_v1.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "jwt-verification-invalid-tls-fallback-configured",
+		},
+		Spec: contour_api_v1.HTTPProxySpec{
+			VirtualHost: &contour_api_v1.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &contour_api_v1.TLS{
+					EnableFallbackCertificate: true,
+				},
+				JWTProviders: []contour_api_v1.JWTProvider{
+					{
+						Name: "provider-1",
+						RemoteJWKS: contour_api_v1.RemoteJWKS{
+							URI: "https://jwt.example.com/jwks.json",
+						},
+					},
+				},
+			},
+			Routes: []contour_api_v1.Route{
+				{
+					JWTVerificationPolicy: &contour_api_v1.JWTVerificationPolicy{Require: "provider-1"},
+					Conditions: []contour_api_v1.MatchCondition{{
+						Prefix: "/foo",
+					}},
+					Services: []contour_api_v1.Service{{
+						Name: "home",
+						Port: 8080,
+					}},
+				},
+			},
+		},
+	}
+
+	run(t, "JWT verification invalid TLS fallback configured", testcase{
+		objs: []interface{}{
+			jwtVerificationInvalidTLSFallbackConfigured,
+			fixture.ServiceRootsHome,
+		},
+		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
+			k8s.NamespacedNameOf(jwtVerificationInvalidTLSFallbackConfigured): fixture.NewValidCondition().
+				WithError(
+					contour_api_v1.ConditionTypeJWTVerificationError,
+					"JWTVerificationNotPermitted",
+					"Spec.VirtualHost.JWTProviders can only be defined for root HTTPProxies that terminate TLS",
+				),
+		},
+	})
+
+	jwtVerificationInvalidRequireAndDisabledSpecified := &contour_api_v1.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "jwt-verification-invalid-require-and-disabled-specified",
+		},
+		Spec: contour_api_v1.HTTPProxySpec{
+			VirtualHost: &contour_api_v1.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &contour_api_v1.TLS{
+					SecretName: fixture.SecretRootsCert.Name,
+				},
+				JWTProviders: []contour_api_v1.JWTProvider{
+					{
+						Name: "provider-1",
+						RemoteJWKS: contour_api_v1.RemoteJWKS{
+							URI: "https://jwt.example.com/jwks.json",
+						},
+					},
+				},
+			},
+			Routes: []contour_api_v1.Route{
+				{
+					JWTVerificationPolicy: &contour_api_v1.JWTVerificationPolicy{
+						Require:  "provider-1",
+						Disabled: true,
+					},
+					Conditions: []contour_api_v1.MatchCondition{{
+						Prefix: "/foo",
+					}},
+					Services: []contour_api_v1.Service{{
+						Name: "home",
+						Port: 8080,
+					}},
+				},
+			},
+		},
+	}
+
+	run(t, "JWT verification invalid route specifies both requires and disabled", testcase{
+		objs: []interface{}{
+			jwtVerificationInvalidRequireAndDisabledSpecified,
+			fixture.SecretRootsCert,
+			fixture.ServiceRootsHome,
+		},
+		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
+			k8s.NamespacedNameOf(jwtVerificationInvalidRequireAndDisabledSpecified): fixture.NewValidCondition().
+				WithError(
+					contour_api_v1.ConditionTypeJWTVerificationError,
+					"InvalidJWTVerificationPolicy",
+					"route's JWT verification policy cannot specify both require and disabled",
+				),
+		},
+	})
+
+	jwtVerificationUpstreamValidationForHTTPJWKS := &contour_api_v1.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "jwt-verification-upstream-validation-for-http-jwks",
+		},
+		Spec: contour_api_v1.HTTPProxySpec{
+			VirtualHost: &contour_api_v1.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &contour_api_v1.TLS{
+					SecretName: fixture.SecretRootsCert.Name,
+				},
+				JWTProviders: []contour_api_v1.JWTProvider{
+					{
+						Name: "provider-1",
+						RemoteJWKS: contour_api_v1.RemoteJWKS{
+							URI: "http://jwt.example.com/jwks.json",
+							UpstreamValidation: &contour_api_v1.UpstreamValidation{
+								CACertificate: "foo",
+								SubjectName:   "jwt.example.com",
+							},
+						},
+					},
+				},
+			},
+			Routes: []contour_api_v1.Route{
+				{
+					JWTVerificationPolicy: &contour_api_v1.JWTVerificationPolicy{
+						Require: "provider-1",
+					},
+					Conditions: []contour_api_v1.MatchCondition{{
+						Prefix: "/",
+					}},
+					Services: []contour_api_v1.Service{{
+						Name: "home",
+						Port: 8080,
+					}},
+				},
+			},
+		},
+	}
+
+	run(t, "JWT verification invalid upstream validation specified for HTTP JWKS", testcase{
+		objs: []interface{}{
+			jwtVerificationUpstreamValidationForHTTPJWKS,
+			fixture.SecretRootsCert,
+			fixture.ServiceRootsHome,
+		},
+		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
+			k8s.NamespacedNameOf(jwtVerificationUpstreamValidationForHTTPJWKS): fixture.NewValidCondition().
+				WithError(
+					contour_api_v1.ConditionTypeJWTVerificationError,
+					"RemoteJWKSUpstreamValidationInvalid",
+					"Spec.VirtualHost.JWTProviders.RemoteJWKS.UpstreamValidation must not be specified when URI scheme is http.",
+				),
+		},
+	})
+
+	jwtVerificationUpstreamValidationCACertDoesNotExist := &contour_api_v1.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "jwt-verification-upstream-validation-cacert-does-not-exist",
+		},
+		Spec: contour_api_v1.HTTPProxySpec{
+			VirtualHost: &contour_api_v1.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &contour_api_v1.TLS{
+					SecretName: fixture.SecretRootsCert.Name,
+				},
+				JWTProviders: []contour_api_v1.JWTProvider{
+					{
+						Name: "provider-1",
+						RemoteJWKS: contour_api_v1.RemoteJWKS{
+							URI: "https://jwt.example.com/jwks.json",
+							UpstreamValidation: &contour_api_v1.UpstreamValidation{
+								CACertificate: "nonexistent",
+								SubjectName:   "jwt.example.com",
+							},
+						},
+					},
+				},
+			},
+			Routes: []contour_api_v1.Route{
+				{
+					JWTVerificationPolicy: &contour_api_v1.JWTVerificationPolicy{
+						Require: "provider-1",
+					},
+					Conditions: []contour_api_v1.MatchCondition{{
+						Prefix: "/",
+					}},
+					Services: []contour_api_v1.Service{{
+						Name: "home",
+						Port: 8080,
+					}},
+				},
+			},
+		},
+	}
+
+	run(t, "JWT verification invalid upstream validation CA cert does not exist", testcase{
+		objs: []interface{}{
+			jwtVerificationUpstreamValidationCACertDoesNotExist,
+			fixture.SecretRootsCert,
+			fixture.ServiceRootsHome,
+		},
+		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
+			k8s.NamespacedNameOf(jwtVerificationUpstreamValidationCACertDoesNotExist): fixture.NewValidCondition().
+				WithError(
+					contour_api_v1.ConditionTypeJWTVerificationError,
+					"RemoteJWKSUpstreamValidationInvalid",
+					"Spec.VirtualHost.JWTProviders.RemoteJWKS.UpstreamValidation is invalid: invalid CA Secret \"roots/nonexistent\": Secret not found",
+				),
+		},
+	})
+
+	jwksInvalidCACert := &v1.Secret{
+		ObjectMeta: fixture.ObjectMeta("roots/cacert"),
+		Type:       v1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			"wrong-key": []byte(fixture.CERTIFICATE),
+		},
+	}
+
+	jwtVerificationUpstreamValidationCACertInvalid := &contour_api_v1.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "jwt-verification-upstream-validation-cacert-invalid",
+		},
+		Spec: contour_api_v1.HTTPProxySpec{
+			VirtualHost: &contour_api_v1.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &contour_api_v1.TLS{
+					SecretName: fixture.SecretRootsCert.Name,
+				},
+				JWTProviders: []contour_api_v1.JWTProvider{
+					{
+						Name: "provider-1",
+						RemoteJWKS: contour_api_v1.RemoteJWKS{
+							URI: "https://jwt.example.com/jwks.json",
+							UpstreamValidation: &contour_api_v1.UpstreamValidation{
+								CACertificate: "cacert",
+								SubjectName:   "jwt.example.com",
+							},
+						},
+					},
+				},
+			},
+			Routes: []contour_api_v1.Route{
+				{
+					JWTVerificationPolicy: &contour_api_v1.JWTVerificationPolicy{
+						Require: "provider-1",
+					},
+					Conditions: []contour_api_v1.MatchCondition{{
+						Prefix: "/",
+					}},
+					Services: []contour_api_v1.Service{{
+						Name: "home",
+						Port: 8080,
+					}},
+				},
+			},
+		},
+	}
+
+	run(t, "JWT verification invalid upstream validation CA cert invalid", testcase{
+		objs: []interface{}{
+			jwtVerificationUpstreamValidationCACertInvalid,
+			fixture.SecretRootsCert,
+			fixture.ServiceRootsHome,
+			jwksInvalidCACert,
+		},
+		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
+			k8s.NamespacedNameOf(jwtVerificationUpstreamValidationCACertInvalid): fixture.NewValidCondition().
+				WithError(
+					contour_api_v1.ConditionTypeJWTVerificationError,
+					"RemoteJWKSUpstreamValidationInvalid",
+					"Spec.VirtualHost.JWTProviders.RemoteJWKS.UpstreamValidation is invalid: invalid CA Secret \"roots/cacert\": empty \"ca.crt\" key",
+				),
+		},
+	})
+
+	jwksCACertDifferentNamespace := &v1.Secret{
+		ObjectMeta: fixture.ObjectMeta("default/cacert"),
+		Data: map[string][]byte{
+			"ca.crt": []byte(fixture.CERTIFICATE),
+		},
+	}
+
+	jwtVerificationUpstreamValidationCACertNotDelegated := &contour_api_v1.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "jwt-verification-upstream-validation-cacert-not-delegated",
+		},
+		Spec: contour_api_v1.HTTPProxySpec{
+			VirtualHost: &contour_api_v1.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &contour_api_v1.TLS{
+					SecretName: fixture.SecretRootsCert.Name,
+				},
+				JWTProviders: []contour_api_v1.JWTProvider{
+					{
+						Name: "provider-1",
+						RemoteJWKS: contour_api_v1.RemoteJWKS{
+							URI: "https://jwt.example.com/jwks.json",
+							UpstreamValidation: &contour_api_v1.UpstreamValidation{
+								CACertificate: "default/cacert",
+								SubjectName:   "jwt.example.com",
+							},
+						},
+					},
+				},
+			},
+			Routes: []contour_api_v1.Route{
+				{
+					JWTVerificationPolicy: &contour_api_v1.JWTVerificationPolicy{
+						Require: "provider-1",
+					},
+					Conditions: []contour_api_v1.MatchCondition{{
+						Prefix: "/",
+					}},
+					Services: []contour_api_v1.Service{{
+						Name: "home",
+						Port: 8080,
+					}},
+				},
+			},
+		},
+	}
+
+	run(t, "JWT verification invalid upstream validation CA cert not delegated", testcase{
+		objs: []interface{}{
+			jwtVerificationUpstreamValidationCACertNotDelegated,
+			fixture.SecretRootsCert,
+			fixture.ServiceRootsHome,
+			jwksCACertDifferentNamespace,
+		},
+		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
+			k8s.NamespacedNameOf(jwtVerificationUpstreamValidationCACertNotDelegated): fixture.NewValidCondition().
+				WithError(
+					contour_api_v1.ConditionTypeJWTVerificationError,
+					"RemoteJWKSCACertificateNotDelegated",
+					"Spec.VirtualHost.JWTProviders.RemoteJWKS.UpstreamValidation.CACertificate Secret \"default/cacert\" is not configured for certificate delegation",
+				),
+		},
+	})
+
+	// proxyWithInvalidSlowStartWindow is invalid because it has invalid window size syntax.
+	proxyWithInvalidSlowStartWindow := &contour_api_v1.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "slow-start-invalid-window",
+		},
+		Spec: contour_api_v1.HTTPProxySpec{
+			VirtualHost: &contour_api_v1.VirtualHost{
+				Fqdn: "www.example.com",
+			},
+			Routes: []contour_api_v1.Route{{
+				Services: []contour_api_v1.Service{{
+					Name: "home",
+					Port: 8080,
+					SlowStartPolicy: &contour_api_v1.SlowStartPolicy{
+						Window: "invalid",
+					},
+				}},
+			}},
+		},
+	}
+
+	// proxyWithInvalidSlowStartAggression is invalid because it has invalid aggression syntax.
+	proxyWithInvalidSlowStartAggression := &contour_api_v1.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "slow-start-invalid-aggression",
+		},
+		Spec: contour_api_v1.HTTPProxySpec{
+			VirtualHost: &contour_api_v1.VirtualHost{
+				Fqdn: "www.example.com",
+			},
+			Routes: []contour_api_v1.Route{{
+				Services: []contour_api_v1.Service{{
+					Name: "home",
+					Port: 8080,
+					SlowStartPolicy: &contour_api_v1.SlowStartPolicy{
+						Window:     "5s",
+						Aggression: "invalid",
+					},
+				}},
+			}},
+		},
+	}
+
+	// proxyWithInvalidSlowStartLBStrategy is invalid because route has LB strategy that does not support slow start.
+	proxyWithInvalidSlowStartLBStrategy := &contour_api_v1.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "slow-start-invalid-lb-strategy",
+		},
+		Spec: contour_api_v1.HTTPProxySpec{
+			VirtualHost: &contour_api_v1.VirtualHost{
+				Fqdn: "www.example.com",
+			},
+			Routes: []contour_api_v1.Route{{
+				LoadBalancerPolicy: &contour_api_v1.LoadBalancerPolicy{
+					Strategy: LoadBalancerPolicyCookie,
+				},
+				Services: []contour_api_v1.Service{{
+					Name: "home",
+					Port: 8080,
+					SlowStartPolicy: &contour_api_v1.SlowStartPolicy{
+						Window: "5s",
+					},
+				}},
+			}},
+		},
+	}
+
+	run(t, "Slow start with invalid window syntax", testcase{
+		objs: []interface{}{
+			proxyWithInvalidSlowStartWindow,
+			fixture.ServiceRootsHome,
+		},
+		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
+			k8s.NamespacedNameOf(proxyWithInvalidSlowStartWindow): fixture.NewValidCondition().
+				WithError(
+					contour_api_v1.ConditionTypeServiceError,
+					"SlowStartInvalid",
+					"error parsing window: time: invalid duration \"invalid\" on slow start",
+				),
+		},
+	})
+
+	run(t, "Slow start with invalid aggression syntax", testcase{
+		objs: []interface{}{
+			proxyWithInvalidSlowStartAggression,
+			fixture.ServiceRootsHome,
+		},
+		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
+			k8s.NamespacedNameOf(proxyWithInvalidSlowStartAggression): fixture.NewValidCondition().
+				WithError(
+					contour_api_v1.ConditionTypeServiceError,
+					"SlowStartInvalid",
+					"error parsing aggression: \"invalid\" is not a decimal number on slow start",
+				),
+		},
+	})
+
+	run(t, "Slow start with load balancer strategy that does not support slow start", testcase{
+		objs: []interface{}{
+			proxyWithInvalidSlowStartLBStrategy,
+			fixture.ServiceRootsHome,
+		},
+		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
+			k8s.NamespacedNameOf(proxyWithInvalidSlowStartLBStrategy): fixture.NewValidCondition().
+				WithError(
+					contour_api_v1.ConditionTypeServiceError,
+					"SlowStartInvalid",
+					"slow start is only supported with RoundRobin or WeightedLeastRequest load balancer strategy",
+				),
+		},
+	})
+
+	h2cUpstreamValidation := &contour_api_v1.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "h2c-upstream-validation",
+		},
+		Spec: contour_api_v1.HTTPProxySpec{
+			VirtualHost: &contour_api_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []contour_api_v1.Route{{
+				Services: []contour_api_v1.Service{{
+					Name:     "home",
+					Port:     8080,
+					Protocol: ref.To("h2c"),
+					UpstreamValidation: &contour_api_v1.UpstreamValidation{
+						CACertificate: fixture.SecretRootsCert.Name,
+						SubjectName:   "home.roots",
+					},
+				}},
+			}},
+		},
+	}
+
+	run(t, "h2c protocol with upstream validation", testcase{
+		objs: []interface{}{
+			h2cUpstreamValidation,
+			fixture.SecretRootsCert,
+			fixture.ServiceRootsHome,
+		},
+		want: map[types.NamespacedName]contour_api_v1.DetailedCondition{
+			k8s.NamespacedNameOf(h2cUpstreamValidation): fixture.NewValidCondition().
+				WithError(
+					contour_api_v1.ConditionTypeServiceError,
+					"InconsistentProtocol",
+					`Service [home:8080] upstream TLS validation not supported for "h2c" protocol, use "h2" for HTTP/2 over TLS`,
+				),
+		},
+	})
+}
+
+func validGatewayStatusUpdate(listenerName string, kind gatewayapi_v1beta1.Kind, attachedRoutes int) []*status.GatewayStatusUpdate {
+	// This applies to tests that the listener doesn't have allowed kind configured
+	// hence the wanted allowed kind is determined by the listener protocol only.
+	var supportedKinds []gatewayapi_v1beta1.RouteGroupKind
+
+	if kind == "HTTPRoute" || kind == "GRPCRoute" {
+		supportedKinds = append(supportedKinds, gatewayapi_v1beta1.RouteGroupKind{
+			Group: ref.To(gatewayapi_v1beta1.Group(gatewayapi_v1beta1.GroupName)),
+			Kind:  "HTTPRoute",
+		})
+		supportedKinds = append(supportedKinds, gatewayapi_v1beta1.RouteGroupKind{
+			Group: ref.To(gatewayapi_v1beta1.Group(gatewayapi_v1beta1.GroupName)),
+			Kind:  "GRPCRoute",
+		})
+	} else {
+		supportedKinds = append(supportedKinds, gatewayapi_v1beta1.RouteGroupKind{
+			Group: ref.To(gatewayapi_v1beta1.Group(gatewayapi_v1beta1.GroupName)),
+			Kind:  kind,
+		})
+	}
+
+	return []*status.GatewayStatusUpdate{
+		{
+			FullName: types.NamespacedName{Namespace: "projectcontour", Name: "contour"},
+			Conditions: map[gatewayapi_v1beta1.GatewayConditionType]metav1.Condition{
+				gatewayapi_v1beta1.GatewayConditionAccepted: gatewayAcceptedCondition(),
+				gatewayapi_v1beta1.GatewayConditionProgrammed: {
+					Type:    string(gatewayapi_v1beta1.GatewayConditionProgrammed),
+					Status:  contour_api_v1.ConditionTrue,
+					Reason:  string(gatewayapi_v1beta1.GatewayReasonProgrammed),
+					Message: status.MessageValidGateway,
+				},
+			},
+			ListenerStatus: map[string]*gatewayapi_v1beta1.ListenerStatus{
+				listenerName: {
+					Name:           gatewayapi_v1beta1.SectionName(listenerName),
+					AttachedRoutes: int32(attachedRoutes),
+					SupportedKinds: supportedKinds,
+					Conditions: []metav1.Condition{
+						{
+							Type:    string(gatewayapi_v1beta1.ListenerConditionProgrammed),
+							Status:  metav1.ConditionTrue,
+							Reason:  string(gatewayapi_v1beta1.ListenerReasonProgrammed),
+							Message: "Valid listener",
+						},
+						{
+							Type:    string(gatewayapi_v1beta1.ListenerConditionAccepted),
+							Status:  metav1.ConditionTrue,
+							Reason:  string(gatewayapi_v1beta1.ListenerReasonAccepted),
+							Message: "Listener accepted",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestGatewayAPIHTTPRouteDAGStatus(t *testing.T) {
+	type testcase struct {
+		objs                      []interface{}
+		gateway                   *gatewayapi_v1beta1.Gateway
+		allowedHostnameSuffixes   []string
+		enableExternalNameService bool
+		disabledRouteKinds        []string
+		defaultBackend            *GatewayDefaultBackend
+		wantRouteConditions       []*status.RouteStatusUpdate
+		wantGatewayStatusUpdate   []*status.GatewayStatusUpdate
+	}
+
+	run := func(t *testing.T, desc string, tc testcase) {
+		t.Helper()
+		t.Run(desc, func(t *testing.T) {
+			t.Helper()
+			builder := Builder{
+				Source: KubernetesCache{
+					RootNamespaces: []string{"roots", "marketing"},
+					FieldLogger:    fixture.NewTestLogger(t),
+					gatewayclass: &gatewayapi_v1beta1.GatewayClass{
+						TypeMeta: metav1.TypeMeta{},
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "test-gc",
+						},
+						Spec: gatewayapi_v1beta1.GatewayClassSpec{
+							ControllerName: "projectcontour.io/contour",
+						},
+						Status: gatewayapi_v1beta1.GatewayClassStatus{
+							Conditions: []metav1.Condition{
+								{
+									Type:   string(gatewayapi_v1beta1.GatewayClassConditionStatusAccepted),
+									Status: metav1.ConditionTrue,
+								},
+							},
+						},
+					},
+					gateway: tc.gateway,
+				},
+				Processors: []Processor{
+					&ListenerProcessor{},
+					&IngressProcessor{
+						FieldLogger: fixture.NewTestLogger(t),
+					},
+					&HTTPProxyProcessor{},
+					&GatewayAPIProcessor{
+						FieldLogger:               fixture.NewTestLogger(t),
+						AllowedHostnameSuffixes:   tc.allowedHostnameSuffixes,
+						EnableExternalNameService: tc.enableExternalNameService,
+						DisabledRouteKinds:        tc.disabledRouteKinds,
+						DefaultBackend:            tc.defaultBackend,
+					},
+				},
+			}
+
+			// Set a default gateway if not defined by a test
+			if tc.gateway == nil {
+				builder.Source.gateway = &gatewayapi_v1beta1.Gateway{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "contour",
+						Namespace: "projectcontour",
+					},
+					Spec: gatewayapi_v1beta1.GatewaySpec{
+						Listeners: []gatewayapi_v1beta1.Listener{{
+							Name:     "http",
+							Port:     80,
+							Protocol: gatewayapi_v1beta1.HTTPProtocolType,
+							AllowedRoutes: &gatewayapi_v1beta1.AllowedRoutes{
+								Namespaces: &gatewayapi_v1beta1.RouteNamespaces{
+									From: ref.To(gatewayapi_v1beta1.NamespacesFromAll),
+								},
+							},
+						}},
+					},
+				}
+			}
+
+			for _, o := range tc.objs {
+				builder.Source.Insert(o)
+			}
+			dag := builder.Build()
+			gotRouteUpdates := dag.StatusCache.GetRouteUpdates()
+			gotGatewayUpdates := dag.StatusCache.GetGatewayUpdates()
+
+			ops := []cmp.Option{
+				cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime"),
+				cmpopts.IgnoreFields(status.RouteStatusUpdate{}, "GatewayRef"),
+				cmpopts.IgnoreFields(status.RouteStatusUpdate{}, "Generation"),
+				cmpopts.IgnoreFields(status.RouteStatusUpdate{}, "TransitionTime"),
+				cmpopts.IgnoreFields(status.RouteStatusUpdate{}, "Resource"),
+				cmpopts.IgnoreFields(status.GatewayStatusUpdate{}, "ExistingConditions"),
+				cmpopts.IgnoreFields(status.GatewayStatusUpdate{}, "Generation"),
+				cmpopts.IgnoreFields(status.GatewayStatusUpdate{}, "TransitionTime"),
+				cmpopts.SortSlices(func(i, j metav1.Condition) bool {
+					return i.Message < j.Message
+				}),
+				cmpopts.SortSlices(func(i, j *status.RouteStatusUpdate) bool {
+					return i.FullName.String() < j.FullName.String()
+				}),
+			}
+
+			// Since we're using a single static GatewayClass,
+			// set the expected controller string here for all
+			// test cases.
+			for _, u := range tc.wantRouteConditions {
+				u.GatewayController = builder.Source.gatewayclass.Spec.ControllerName
+
+				for _, rps := range u.RouteParentStatuses {
+					rps.ControllerName = builder.Source.gatewayclass.Spec.ControllerName
+				}
+			}
+
+			if diff := cmp.Diff(tc.wantRouteConditions, gotRouteUpdates, ops...); diff != "" {
+				t.Fatalf("expected route status: %v, got %v", tc.wantRouteConditions, diff)
+			}
+
+			if diff := cmp.Diff(tc.wantGatewayStatusUpdate, gotGatewayUpdates, ops...); diff != "" {
+				t.Fatalf("expected gateway status: %v, got %v", tc.wantGatewayStatusUpdate, diff)
+			}
+		})
+	}
+
+	kuardService := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "default",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Name:       "http",
+				Protocol:   "TCP",
+				Port:       8080,
+				TargetPort: intstr.FromInt(8080),
+			}},
+		},
+	}
+
+	kuardService2 := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard2",
+			Namespace: "default",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Name:       "http",
+				Protocol:   "TCP",
+				Port:       8080,
+				TargetPort: intstr.FromInt(8080),
+			}},
+		},
+	}
+
+	kuardService3 := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard3",
+			Namespace: "default",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Name:       "http",
+				Protocol:   "TCP",
+				Port:       8080,
+				TargetPort: intstr.FromInt(8080),
+			}},
+		},
+	}
+
+	run(t, "simple httproute", testcase{
+		objs: []interface{}{
+			kuardService,
+			&gatewayapi_v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "basic",
+					Namespace: "default",
+				},
+				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1beta1.ParentReference{gatewayapi.GatewayParentRef("projectcontour", "contour")},
+					},
+					Hostnames: []gatewayapi_v1beta1.Hostname{
+						"test.projectcontour.io",
+					},
+					Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+						Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
+						BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
+					}},
+				},
+			}},
+		wantRouteConditions: []*status.RouteStatusUpdate{{
+			FullName: types.NamespacedName{Namespace: "default", Name: "basic"},
+			RouteParentStatuses: []*gatewayapi_v1beta1.RouteParentStatus{
+				{
+					ParentRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
+					Conditions: []metav1.Condition{
+						routeResolvedRefsCondition(),
+						routeAcceptedHTTPRouteCondition(),
+					},
+				},
+			},
+		}},
+		wantGatewayStatusUpdate: validGatewayStatusUpdate("http", "HTTPRoute", 1),
+	})
+
+	run(t, "missing default backend Service", testcase{
+		defaultBackend: &GatewayDefaultBackend{
+			Service: types.NamespacedName{Namespace: "projectcontour", Name: "default-backend"},
+			Port:    8080,
+		},
+		wantGatewayStatusUpdate: func() []*status.GatewayStatusUpdate {
+			updates := validGatewayStatusUpdate("http", "HTTPRoute", 0)
+			updates[0].Conditions[status.GatewayConditionDefaultBackendResolved] = metav1.Condition{
+				Type:    string(status.GatewayConditionDefaultBackendResolved),
+				Status:  contour_api_v1.ConditionFalse,
+				Reason:  string(status.GatewayReasonDefaultBackendInvalid),
+				Message: `Default backend Service projectcontour/default-backend is invalid: service "projectcontour/default-backend" not found`,
+			}
+			return updates
+		}(),
+	})
+
+	run(t, "simple httproute with backendref namespace matching route's explicitly specified", testcase{
+		objs: []interface{}{
+			kuardService,
+			&gatewayapi_v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "basic",
+					Namespace: "default",
+				},
+				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1beta1.ParentReference{gatewayapi.GatewayParentRef("projectcontour", "contour")},
+					},
+					Hostnames: []gatewayapi_v1beta1.Hostname{
+						"test.projectcontour.io",
+					},
+					Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+						Matches: gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
+						BackendRefs: []gatewayapi_v1beta1.HTTPBackendRef{
+							{
+								BackendRef: gatewayapi_v1beta1.BackendRef{
+									BackendObjectReference: gatewayapi_v1beta1.BackendObjectReference{
+										Kind:      ref.To(gatewayapi_v1beta1.Kind("Service")),
+										Namespace: ref.To(gatewayapi_v1beta1.Namespace(kuardService.Namespace)),
+										Name:      gatewayapi_v1beta1.ObjectName(kuardService.Name),
+										Port:      ref.To(gatewayapi_v1beta1.PortNumber(8080)),
+									},
+									Weight: ref.To(int32(1)),
+								},
+							},
+						},
+					}},
+				},
+			}},
+		wantRouteConditions: []*status.RouteStatusUpdate{{
+			FullName: types.NamespacedName{Namespace: "default", Name: "basic"},
+			RouteParentStatuses: []*gatewayapi_v1beta1.RouteParentStatus{{
+				ParentRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
+				Conditions: []metav1.Condition{
+					routeResolvedRefsCondition(),
+					routeAcceptedHTTPRouteCondition(),
+				},
+			}},
+		}},
+		wantGatewayStatusUpdate: validGatewayStatusUpdate("http", "HTTPRoute", 1),
+	})
+
+	serviceParentRef := gatewayapi_v1beta1.ParentReference{
+		Group: ref.To(gatewayapi_v1beta1.Group("")),
+		Kind:  ref.To(gatewayapi_v1beta1.Kind("Service")),
+		Name:  "kuard",
+	}
+
+	run(t, "httproute with a service parent ref", testcase{
+		objs: []interface{}{
+			kuardService,
+			&gatewayapi_v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "basic",
+					Namespace: "default",
+				},
+				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1beta1.ParentReference{
+							gatewayapi.GatewayParentRef("projectcontour", "contour"),
+							serviceParentRef,
+						},
+					},
+					Hostnames: []gatewayapi_v1beta1.Hostname{
+						"test.projectcontour.io",
+					},
+					Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+						Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
+						BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
+					}},
+				},
+			},
+			// Not attached to the Gateway, so may be for a mesh
+			// implementation and has no status set.
+			&gatewayapi_v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "mesh",
+					Namespace: "default",
+				},
+				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1beta1.ParentReference{serviceParentRef},
+					},
+					Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+						Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
+						BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
+					}},
+				},
+			},
+		},
+		wantRouteConditions: []*status.RouteStatusUpdate{{
+			FullName: types.NamespacedName{Namespace: "default", Name: "basic"},
+			RouteParentStatuses: []*gatewayapi_v1beta1.RouteParentStatus{
+				{
+					ParentRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
+					Conditions: []metav1.Condition{
+						routeResolvedRefsCondition(),
+						routeAcceptedHTTPRouteCondition(),
+					},
+				},
+				{
+					ParentRef: serviceParentRef,
+					Conditions: []metav1.Condition{
+						{
+							Type:    string(gatewayapi_v1beta1.RouteConditionAccepted),
+							Status:  contour_api_v1.ConditionFalse,
+							Reason:  string(gatewayapi_v1beta1.RouteReasonUnsupportedValue),
+							Message: "Service parent refs are not supported, Contour only routes traffic for Gateways",
+						},
+					},
+				},
+			},
+		}},
+		wantGatewayStatusUpdate: validGatewayStatusUpdate("http", "HTTPRoute", 1),
+	})
+
+	run(t, "multiple httproutes", testcase{
+		objs: []interface{}{
+			kuardService,
+			&gatewayapi_v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "basic",
+					Namespace: "default",
+				},
+				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1beta1.ParentReference{gatewayapi.GatewayParentRef("projectcontour", "contour")},
+					},
+					Hostnames: []gatewayapi_v1beta1.Hostname{
+						"test.projectcontour.io",
+					},
+					Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+						Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
+						BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
+					}},
+				},
+			},
+			&gatewayapi_v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "basic-2",
+					Namespace: "default",
+				},
+				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1beta1.ParentReference{gatewayapi.GatewayParentRef("projectcontour", "contour")},
+					},
+					Hostnames: []gatewayapi_v1beta1.Hostname{
+						"test.projectcontour.io",
+					},
+					Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+						Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
+						BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
+					}},
+				},
+			}},
+		wantRouteConditions: []*status.RouteStatusUpdate{
+			{
+				FullName: types.NamespacedName{Namespace: "default", Name: "basic"},
+				RouteParentStatuses: []*gatewayapi_v1beta1.RouteParentStatus{
+					{
+						ParentRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
+						Conditions: []metav1.Condition{
+							routeResolvedRefsCondition(),
+							routeAcceptedHTTPRouteCondition(),
+						},
+					},
+				},
+			},
+			{
+				FullName: types.NamespacedName{Namespace: "default", Name: "basic-2"},
+				RouteParentStatuses: []*gatewayapi_v1beta1.RouteParentStatus{
+					{
+						ParentRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
+						Conditions: []metav1.Condition{
+							routeResolvedRefsCondition(),
+							routeAcceptedHTTPRouteCondition(),
+						},
+					},
+				},
+			},
+		},
+		wantGatewayStatusUpdate: validGatewayStatusUpdate("http", "HTTPRoute", 2),
+	})
+
+	run(t, "prefix path match not starting with '/' for httproute", testcase{
+		objs: []interface{}{
+			kuardService,
+			&gatewayapi_v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "basic",
+					Namespace: "default",
+				},
+				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1beta1.ParentReference{gatewayapi.GatewayParentRef("projectcontour", "contour")},
+					},
+					Hostnames: []gatewayapi_v1beta1.Hostname{
+						"test.projectcontour.io",
+					},
+					Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+						Matches: []gatewayapi_v1beta1.HTTPRouteMatch{{
+							Path: &gatewayapi_v1beta1.HTTPPathMatch{
+								Type:  ref.To(gatewayapi_v1beta1.PathMatchPathPrefix),
+								Value: ref.To("doesnt-start-with-slash"),
+							},
+						}},
+						BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
+					}},
+				},
+			}},
+		wantRouteConditions: []*status.RouteStatusUpdate{{
+			FullName: types.NamespacedName{Namespace: "default", Name: "basic"},
+			RouteParentStatuses: []*gatewayapi_v1beta1.RouteParentStatus{
+				{
+					ParentRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
+					Conditions: []metav1.Condition{
+						routeAcceptedHTTPRouteCondition(),
+						routeResolvedRefsCondition(),
+						{
+							Type:    string(status.ConditionValidMatches),
+							Status:  contour_api_v1.ConditionFalse,
+							Reason:  string(status.ReasonInvalidPathMatch),
+							Message: "Match.Path.Value must start with '/'.",
+						},
+					},
+				},
+			},
+		}},
+		wantGatewayStatusUpdate: validGatewayStatusUpdate("http", "HTTPRoute", 0),
+	})
+	run(t, "exact path match not starting with '/' for httproute", testcase{
+		objs: []interface{}{
+			kuardService,
+			&gatewayapi_v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "basic",
+					Namespace: "default",
+				},
+				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1beta1.ParentReference{gatewayapi.GatewayParentRef("projectcontour", "contour")},
+					},
+					Hostnames: []gatewayapi_v1beta1.Hostname{
+						"test.projectcontour.io",
+					},
+					Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+						Matches: []gatewayapi_v1beta1.HTTPRouteMatch{{
+							Path: &gatewayapi_v1beta1.HTTPPathMatch{
+								Type:  ref.To(gatewayapi_v1beta1.PathMatchExact),
+								Value: ref.To("doesnt-start-with-slash"),
+							},
+						}},
+						BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
+					}},
+				},
+			}},
+		wantRouteConditions: []*status.RouteStatusUpdate{{
+			FullName: types.NamespacedName{Namespace: "default", Name: "basic"},
+			RouteParentStatuses: []*gatewayapi_v1beta1.RouteParentStatus{
+				{
+					ParentRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
+					Conditions: []metav1.Condition{
+						routeResolvedRefsCondition(),
+						routeAcceptedHTTPRouteCondition(),
+						{
+							Type:    string(status.ConditionValidMatches),
+							Status:  contour_api_v1.ConditionFalse,
+							Reason:  string(status.ReasonInvalidPathMatch),
+							Message: "Match.Path.Value must start with '/'.",
+						},
+					},
+				},
+			},
+		}},
+		wantGatewayStatusUpdate: validGatewayStatusUpdate("http", "HTTPRoute", 0),
+	})
+
+	run(t, "prefix path match with consecutive '/' characters for httproute", testcase{
+		objs: []interface{}{
+			kuardService,
+			&gatewayapi_v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "basic",
+					Namespace: "default",
+				},
+				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1beta1.ParentReference{gatewayapi.GatewayParentRef("projectcontour", "contour")},
+					},
+					Hostnames: []gatewayapi_v1beta1.Hostname{
+						"test.projectcontour.io",
+					},
+					Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+						Matches: []gatewayapi_v1beta1.HTTPRouteMatch{{
+							Path: &gatewayapi_v1beta1.HTTPPathMatch{
+								Type:  ref.To(gatewayapi_v1beta1.PathMatchPathPrefix),
+								Value: ref.To("/foo///bar"),
+							},
+						}},
+						BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
+					}},
+				},
+			}},
+		wantRouteConditions: []*status.RouteStatusUpdate{{
+			FullName: types.NamespacedName{Namespace: "default", Name: "basic"},
+			RouteParentStatuses: []*gatewayapi_v1beta1.RouteParentStatus{
+				{
+					ParentRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
+					Conditions: []metav1.Condition{
+						routeResolvedRefsCondition(),
+						routeAcceptedHTTPRouteCondition(),
+						{
+							Type:    string(status.ConditionValidMatches),
+							Status:  contour_api_v1.ConditionFalse,
+							Reason:  string(status.ReasonInvalidPathMatch),
+							Message: "Match.Path.Value must not contain consecutive '/' characters.",
+						},
+					},
+				},
+			},
+		}},
+		wantGatewayStatusUpdate: validGatewayStatusUpdate("http", "HTTPRoute", 0),
+	})
+
+	run(t, "exact path match with consecutive '/' characters for httproute", testcase{
+		objs: []interface{}{
+			kuardService,
+			&gatewayapi_v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "basic",
+					Namespace: "default",
+				},
+				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1beta1.ParentReference{gatewayapi.GatewayParentRef("projectcontour", "contour")},
+					},
+					Hostnames: []gatewayapi_v1beta1.Hostname{
+						"test.projectcontour.io",
+					},
+					Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+						Matches: []gatewayapi_v1beta1.HTTPRouteMatch{{
+							Path: &gatewayapi_v1beta1.HTTPPathMatch{
+								Type:  ref.To(gatewayapi_v1beta1.PathMatchExact),
+								Value: ref.To("//foo/bar"),
+							},
+						}},
+						BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
+					}},
+				},
+			}},
+		wantRouteConditions: []*status.RouteStatusUpdate{{
+			FullName: types.NamespacedName{Namespace: "default", Name: "basic"},
+			RouteParentStatuses: []*gatewayapi_v1beta1.RouteParentStatus{
+				{
+					ParentRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
+					Conditions: []metav1.Condition{
+						routeResolvedRefsCondition(),
+						routeAcceptedHTTPRouteCondition(),
+						{
+							Type:    string(status.ConditionValidMatches),
+							Status:  contour_api_v1.ConditionFalse,
+							Reason:  string(status.ReasonInvalidPathMatch),
+							Message: "Match.Path.Value must not contain consecutive '/' characters.",
+						},
+					},
+				},
+			},
+		}},
+		wantGatewayStatusUpdate: validGatewayStatusUpdate("http", "HTTPRoute", 0),
+	})
+
+	run(t, "invalid path match type for httproute", testcase{
+		objs: []interface{}{
+			kuardService,
+			&gatewayapi_v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "basic",
+					Namespace: "default",
+				},
+				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1beta1.ParentReference{gatewayapi.GatewayParentRef("projectcontour", "contour")},
+					},
+					Hostnames: []gatewayapi_v1beta1.Hostname{
+						"test.projectcontour.io",
+					},
+					Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+						Matches: []gatewayapi_v1beta1.HTTPRouteMatch{{
+							Path: &gatewayapi_v1beta1.HTTPPathMatch{
+								Type:  ref.To(gatewayapi_v1beta1.PathMatchType("UNKNOWN")), // <---- unknown type to break the test
+								Value: ref.To("/"),
+							},
+						}},
+						BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
+					}},
+				},
+			}},
+		wantRouteConditions: []*status.RouteStatusUpdate{{
+			FullName: types.NamespacedName{Namespace: "default", Name: "basic"},
+			RouteParentStatuses: []*gatewayapi_v1beta1.RouteParentStatus{
+				{
+					ParentRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
+					Conditions: []metav1.Condition{
+						routeResolvedRefsCondition(),
+						{
+							Type:    string(gatewayapi_v1beta1.RouteConditionAccepted),
+							Status:  contour_api_v1.ConditionFalse,
+							Reason:  string(gatewayapi_v1beta1.RouteReasonUnsupportedValue),
+							Message: "HTTPRoute.Spec.Rules.PathMatch: Only Prefix match type and Exact match type are supported.",
+						},
+					},
+				},
+			},
+		}},
+		wantGatewayStatusUpdate: validGatewayStatusUpdate("http", "HTTPRoute", 0),
+	})
+
+	run(t, "invalid max-request-body-bytes annotation for httproute", testcase{
+		objs: []interface{}{
+			kuardService,
+			&gatewayapi_v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "basic",
+					Namespace: "default",
+					Annotations: map[string]string{
+						"projectcontour.io/max-request-body-bytes": "10MB",
+					},
+				},
+				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1beta1.ParentReference{gatewayapi.GatewayParentRef("projectcontour", "contour")},
+					},
+					Hostnames: []gatewayapi_v1beta1.Hostname{
+						"test.projectcontour.io",
+					},
+					Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+						Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
+						BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
+					}},
+				},
+			}},
+		wantRouteConditions: []*status.RouteStatusUpdate{{
+			FullName: types.NamespacedName{Namespace: "default", Name: "basic"},
+			RouteParentStatuses: []*gatewayapi_v1beta1.RouteParentStatus{
+				{
+					ParentRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
+					Conditions: []metav1.Condition{
+						routeResolvedRefsCondition(),
+						{
+							Type:    string(gatewayapi_v1beta1.RouteConditionAccepted),
+							Status:  contour_api_v1.ConditionFalse,
+							Reason:  string(gatewayapi_v1beta1.RouteReasonUnsupportedValue),
+							Message: "projectcontour.io/max-request-body-bytes annotation is invalid: invalid value \"10MB\": must be a positive integer no greater than 4294967295",
+						},
+					},
+				},
+			},
+		}},
+		wantGatewayStatusUpdate: validGatewayStatusUpdate("http", "HTTPRoute", 0),
+	})
+
+	run(t, "invalid hedge-per-try-timeout annotation for httproute", testcase{
+		objs: []interface{}{
+			kuardService,
+			&gatewayapi_v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "basic",
+					Namespace: "default",
+					Annotations: map[string]string{
+						"projectcontour.io/hedge-per-try-timeout": "100",
+					},
+				},
+				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1beta1.ParentReference{gatewayapi.GatewayParentRef("projectcontour", "contour")},
+					},
+					Hostnames: []gatewayapi_v1beta1.Hostname{
+						"test.projectcontour.io",
+					},
+					Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+						Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
+						BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
+					}},
 				},
-			},
-			ListenerStatus: map[string]*gatewayapi_v1beta1.ListenerStatus{
-				listenerName: {
-					Name:           gatewayapi_v1beta1.SectionName(listenerName),
-					AttachedRoutes: int32(attachedRoutes),
-					SupportedKinds: supportedKinds,
+			}},
+		wantRouteConditions: []*status.RouteStatusUpdate{{
+			FullName: types.NamespacedName{Namespace: "default", Name: "basic"},
+			RouteParentStatuses: []*gatewayapi_v1beta1.RouteParentStatus{
+				{
+					ParentRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
 					Conditions: []metav1.Condition{
+						routeResolvedRefsCondition(),
 						{
-							Type:    string(gatewayapi_v1beta1.ListenerConditionProgrammed),
-							Status:  metav1.ConditionTrue,
-							Reason:  string(gatewayapi_v1beta1.ListenerReasonProgrammed),
-							Message: "Valid listener",
-						},
-						{
-							Type:    string(gatewayapi_v1beta1.ListenerConditionAccepted),
-							Status:  metav1.ConditionTrue,
-							Reason:  string(gatewayapi_v1beta1.ListenerReasonAccepted),
-							Message: "Listener accepted",
+							Type:    string(gatewayapi_v1beta1.RouteConditionAccepted),
+							Status:  contour_api_v1.ConditionFalse,
+							Reason:  string(gatewayapi_v1beta1.RouteReasonUnsupportedValue),
+							Message: "projectcontour.io/hedge-per-try-timeout annotation is invalid: invalid value \"100\": must be a positive duration",
 						},
 					},
 				},
 			},
-		},
-	}
-}
-
-func TestGatewayAPIHTTPRouteDAGStatus(t *testing.T) {
-	type testcase struct {
-		objs                    []interface{}
-		gateway                 *gatewayapi_v1beta1.Gateway
-		wantRouteConditions     []*status.RouteStatusUpdate
-		wantGatewayStatusUpdate []*status.GatewayStatusUpdate
-	}
+		}},
+		wantGatewayStatusUpdate: validGatewayStatusUpdate("http", "HTTPRoute", 0),
+	})
 
-	run := func(t *testing.T, desc string, tc testcase) {
-		t.Helper()
-		t.Run(desc, func(t *testing.T) {
-			t.Helper()
-			builder := Builder{
-				Source: KubernetesCache{
-					RootNamespaces: []string{"roots", "marketing"},
-					FieldLogger:    fixture.NewTestLogger(t),
-					gatewayclass: &gatewayapi_v1beta1.GatewayClass{
-						TypeMeta: metav1.TypeMeta{},
-						ObjectMeta: metav1.ObjectMeta{
-							Name: "test-gc",
-						},
-						Spec: gatewayapi_v1beta1.GatewayClassSpec{
-							ControllerName: "projectcontour.io/contour",
-						},
-						Status: gatewayapi_v1beta1.GatewayClassStatus{
-							Conditions: []metav1.Condition{
-								{
-									Type:   string(gatewayapi_v1beta1.GatewayClassConditionStatusAccepted),
-									Status: metav1.ConditionTrue,
-								},
-							},
-						},
+	run(t, "invalid retry-budget-percent annotation for httproute", testcase{
+		objs: []interface{}{
+			kuardService,
+			&gatewayapi_v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "basic",
+					Namespace: "default",
+					Annotations: map[string]string{
+						"projectcontour.io/retry-budget-percent": "0",
 					},
-					gateway: tc.gateway,
 				},
-				Processors: []Processor{
-					&ListenerProcessor{},
-					&IngressProcessor{
-						FieldLogger: fixture.NewTestLogger(t),
+				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1beta1.ParentReference{gatewayapi.GatewayParentRef("projectcontour", "contour")},
 					},
-					&HTTPProxyProcessor{},
-					&GatewayAPIProcessor{
-						FieldLogger: fixture.NewTestLogger(t),
+					Hostnames: []gatewayapi_v1beta1.Hostname{
+						"test.projectcontour.io",
 					},
+					Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+						Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
+						BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
+					}},
 				},
-			}
-
-			// Set a default gateway if not defined by a test
-			if tc.gateway == nil {
-				builder.Source.gateway = &gatewayapi_v1beta1.Gateway{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      "contour",
-						Namespace: "projectcontour",
-					},
-					Spec: gatewayapi_v1beta1.GatewaySpec{
-						Listeners: []gatewayapi_v1beta1.Listener{{
-							Name:     "http",
-							Port:     80,
-							Protocol: gatewayapi_v1beta1.HTTPProtocolType,
-							AllowedRoutes: &gatewayapi_v1beta1.AllowedRoutes{
-								Namespaces: &gatewayapi_v1beta1.RouteNamespaces{
-									From: ref.To(gatewayapi_v1beta1.NamespacesFromAll),
-								},
-							},
-						}},
-					},
-				}
-			}
-
-			for _, o := range tc.objs {
-				builder.Source.Insert(o)
-			}
-			dag := builder.Build()
-			gotRouteUpdates := dag.StatusCache.GetRouteUpdates()
-			gotGatewayUpdates := dag.StatusCache.GetGatewayUpdates()
-
-			ops := []cmp.Option{
-				cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime"),
-				cmpopts.IgnoreFields(status.RouteStatusUpdate{}, "GatewayRef"),
-				cmpopts.IgnoreFields(status.RouteStatusUpdate{}, "Generation"),
-				cmpopts.IgnoreFields(status.RouteStatusUpdate{}, "TransitionTime"),
-				cmpopts.IgnoreFields(status.RouteStatusUpdate{}, "Resource"),
-				cmpopts.IgnoreFields(status.GatewayStatusUpdate{}, "ExistingConditions"),
-				cmpopts.IgnoreFields(status.GatewayStatusUpdate{}, "Generation"),
-				cmpopts.IgnoreFields(status.GatewayStatusUpdate{}, "TransitionTime"),
-				cmpopts.SortSlices(func(i, j metav1.Condition) bool {
-					return i.Message < j.Message
-				}),
-				cmpopts.SortSlices(func(i, j *status.RouteStatusUpdate) bool {
-					return i.FullName.String() < j.FullName.String()
-				}),
-			}
-
-			// Since we're using a single static GatewayClass,
-			// set the expected controller string here for all
-			// test cases.
-			for _, u := range tc.wantRouteConditions {
-				u.GatewayController = builder.Source.gatewayclass.Spec.ControllerName
-
-				for _, rps := range u.RouteParentStatuses {
-					rps.ControllerName = builder.Source.gatewayclass.Spec.ControllerName
-				}
-			}
-
-			if diff := cmp.Diff(tc.wantRouteConditions, gotRouteUpdates, ops...); diff != "" {
-				t.Fatalf("expected route status: %v, got %v", tc.wantRouteConditions, diff)
-			}
-
-			if diff := cmp.Diff(tc.wantGatewayStatusUpdate, gotGatewayUpdates, ops...); diff != "" {
-				t.Fatalf("expected gateway status: %v, got %v", tc.wantGatewayStatusUpdate, diff)
-			}
-		})
-	}
-
-	kuardService := &v1.Service{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "kuard",
-			Namespace: "default",
-		},
-		Spec: v1.ServiceSpec{
-			Ports: []v1.ServicePort{{
-				Name:       "http",
-				Protocol:   "TCP",
-				Port:       8080,
-				TargetPort: intstr.FromInt(8080),
-			}},
-		},
-	}
-
-	kuardService2 := &v1.Service{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "kuard2",
-			Namespace: "default",
-		},
-		Spec: v1.ServiceSpec{
-			Ports: []v1.ServicePort{{
-				Name:       "http",
-				Protocol:   "TCP",
-				Port:       8080,
-				TargetPort: intstr.FromInt(8080),
-			}},
-		},
-	}
-
-	kuardService3 := &v1.Service{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "kuard3",
-			Namespace: "default",
-		},
-		Spec: v1.ServiceSpec{
-			Ports: []v1.ServicePort{{
-				Name:       "http",
-				Protocol:   "TCP",
-				Port:       8080,
-				TargetPort: intstr.FromInt(8080),
 			}},
-		},
-	}
+		wantRouteConditions: []*status.RouteStatusUpdate{{
+			FullName: types.NamespacedName{Namespace: "default", Name: "basic"},
+			RouteParentStatuses: []*gatewayapi_v1beta1.RouteParentStatus{
+				{
+					ParentRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
+					Conditions: []metav1.Condition{
+						routeResolvedRefsCondition(),
+						{
+							Type:    string(gatewayapi_v1beta1.RouteConditionAccepted),
+							Status:  contour_api_v1.ConditionFalse,
+							Reason:  string(gatewayapi_v1beta1.RouteReasonUnsupportedValue),
+							Message: "projectcontour.io/retry-budget-percent annotation is invalid: invalid value \"0\": must be an integer between 1 and 100",
+						},
+					},
+				},
+			},
+		}},
+		wantGatewayStatusUpdate: validGatewayStatusUpdate("http", "HTTPRoute", 0),
+	})
 
-	run(t, "simple httproute", testcase{
+	run(t, "retry-budget-min-retry-concurrency annotation without retry-budget-percent for httproute", testcase{
 		objs: []interface{}{
 			kuardService,
 			&gatewayapi_v1beta1.HTTPRoute{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "basic",
 					Namespace: "default",
+					Annotations: map[string]string{
+						"projectcontour.io/retry-budget-min-retry-concurrency": "5",
+					},
 				},
 				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
 					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
@@ -4475,21 +5628,29 @@ func TestGatewayAPIHTTPRouteDAGStatus(t *testing.T) {
 					ParentRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
 					Conditions: []metav1.Condition{
 						routeResolvedRefsCondition(),
-						routeAcceptedHTTPRouteCondition(),
+						{
+							Type:    string(gatewayapi_v1beta1.RouteConditionAccepted),
+							Status:  contour_api_v1.ConditionFalse,
+							Reason:  string(gatewayapi_v1beta1.RouteReasonUnsupportedValue),
+							Message: "projectcontour.io/retry-budget-min-retry-concurrency annotation requires the projectcontour.io/retry-budget-percent annotation",
+						},
 					},
 				},
 			},
 		}},
-		wantGatewayStatusUpdate: validGatewayStatusUpdate("http", "HTTPRoute", 1),
+		wantGatewayStatusUpdate: validGatewayStatusUpdate("http", "HTTPRoute", 0),
 	})
 
-	run(t, "simple httproute with backendref namespace matching route's explicitly specified", testcase{
+	run(t, "hedge-per-try-timeout annotation for httproute without idempotent method matches", testcase{
 		objs: []interface{}{
 			kuardService,
 			&gatewayapi_v1beta1.HTTPRoute{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "basic",
 					Namespace: "default",
+					Annotations: map[string]string{
+						"projectcontour.io/hedge-per-try-timeout": "100ms",
+					},
 				},
 				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
 					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
@@ -4499,61 +5660,60 @@ func TestGatewayAPIHTTPRouteDAGStatus(t *testing.T) {
 						"test.projectcontour.io",
 					},
 					Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
-						Matches: gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
-						BackendRefs: []gatewayapi_v1beta1.HTTPBackendRef{
-							{
-								BackendRef: gatewayapi_v1beta1.BackendRef{
-									BackendObjectReference: gatewayapi_v1beta1.BackendObjectReference{
-										Kind:      ref.To(gatewayapi_v1beta1.Kind("Service")),
-										Namespace: ref.To(gatewayapi_v1beta1.Namespace(kuardService.Namespace)),
-										Name:      gatewayapi_v1beta1.ObjectName(kuardService.Name),
-										Port:      ref.To(gatewayapi_v1beta1.PortNumber(8080)),
-									},
-									Weight: ref.To(int32(1)),
-								},
+						Matches: []gatewayapi_v1beta1.HTTPRouteMatch{{
+							Path: &gatewayapi_v1beta1.HTTPPathMatch{
+								Type:  ref.To(gatewayapi_v1beta1.PathMatchPathPrefix),
+								Value: ref.To("/"),
 							},
-						},
+							Method: ref.To(gatewayapi_v1beta1.HTTPMethodGet),
+						}},
+						BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
+					}, {
+						Matches: []gatewayapi_v1beta1.HTTPRouteMatch{{
+							Path: &gatewayapi_v1beta1.HTTPPathMatch{
+								Type:  ref.To(gatewayapi_v1beta1.PathMatchPathPrefix),
+								Value: ref.To("/"),
+							},
+							Method: ref.To(gatewayapi_v1beta1.HTTPMethodPost),
+						}},
+						BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
+					}, {
+						Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/other"),
+						BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
 					}},
 				},
 			}},
 		wantRouteConditions: []*status.RouteStatusUpdate{{
 			FullName: types.NamespacedName{Namespace: "default", Name: "basic"},
-			RouteParentStatuses: []*gatewayapi_v1beta1.RouteParentStatus{{
-				ParentRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
-				Conditions: []metav1.Condition{
-					routeResolvedRefsCondition(),
-					routeAcceptedHTTPRouteCondition(),
+			RouteParentStatuses: []*gatewayapi_v1beta1.RouteParentStatus{
+				{
+					ParentRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
+					Conditions: []metav1.Condition{
+						{
+							Type:    string(status.ConditionRequestHedging),
+							Status:  contour_api_v1.ConditionFalse,
+							Reason:  string(status.ReasonNonIdempotentMethod),
+							Message: "Requests to Spec.Rules[1], Spec.Rules[2] are not hedged, hedging requires a match on an idempotent method (GET, HEAD, OPTIONS, TRACE, PUT or DELETE).",
+						},
+						routeResolvedRefsCondition(),
+						routeAcceptedHTTPRouteCondition(),
+					},
 				},
-			}},
+			},
 		}},
 		wantGatewayStatusUpdate: validGatewayStatusUpdate("http", "HTTPRoute", 1),
 	})
 
-	run(t, "multiple httproutes", testcase{
+	run(t, "invalid request-mirror-percentage annotation for httproute", testcase{
 		objs: []interface{}{
 			kuardService,
 			&gatewayapi_v1beta1.HTTPRoute{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "basic",
 					Namespace: "default",
-				},
-				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
-					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
-						ParentRefs: []gatewayapi_v1beta1.ParentReference{gatewayapi.GatewayParentRef("projectcontour", "contour")},
-					},
-					Hostnames: []gatewayapi_v1beta1.Hostname{
-						"test.projectcontour.io",
+					Annotations: map[string]string{
+						"projectcontour.io/request-mirror-percentage": "150",
 					},
-					Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
-						Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
-						BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
-					}},
-				},
-			},
-			&gatewayapi_v1beta1.HTTPRoute{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      "basic-2",
-					Namespace: "default",
 				},
 				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
 					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
@@ -4568,36 +5728,28 @@ func TestGatewayAPIHTTPRouteDAGStatus(t *testing.T) {
 					}},
 				},
 			}},
-		wantRouteConditions: []*status.RouteStatusUpdate{
-			{
-				FullName: types.NamespacedName{Namespace: "default", Name: "basic"},
-				RouteParentStatuses: []*gatewayapi_v1beta1.RouteParentStatus{
-					{
-						ParentRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
-						Conditions: []metav1.Condition{
-							routeResolvedRefsCondition(),
-							routeAcceptedHTTPRouteCondition(),
-						},
-					},
-				},
-			},
-			{
-				FullName: types.NamespacedName{Namespace: "default", Name: "basic-2"},
-				RouteParentStatuses: []*gatewayapi_v1beta1.RouteParentStatus{
-					{
-						ParentRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
-						Conditions: []metav1.Condition{
-							routeResolvedRefsCondition(),
-							routeAcceptedHTTPRouteCondition(),
+		wantRouteConditions: []*status.RouteStatusUpdate{{
+			FullName: types.NamespacedName{Namespace: "default", Name: "basic"},
+			RouteParentStatuses: []*gatewayapi_v1beta1.RouteParentStatus{
+				{
+					ParentRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
+					Conditions: []metav1.Condition{
+						routeResolvedRefsCondition(),
+						{
+							Type:    string(gatewayapi_v1beta1.RouteConditionAccepted),
+							Status:  contour_api_v1.ConditionFalse,
+							Reason:  string(gatewayapi_v1beta1.RouteReasonUnsupportedValue),
+							Message: "projectcontour.io/request-mirror-percentage annotation is invalid: invalid value \"150\": must be an integer between 0 and 100",
 						},
 					},
 				},
 			},
-		},
-		wantGatewayStatusUpdate: validGatewayStatusUpdate("http", "HTTPRoute", 2),
+		}},
+		wantGatewayStatusUpdate: validGatewayStatusUpdate("http", "HTTPRoute", 0),
 	})
 
-	run(t, "prefix path match not starting with '/' for httproute", testcase{
+	run(t, "disabled route kinds are left out of the listener's supported kinds", testcase{
+		disabledRouteKinds: []string{"GRPCRoute"},
 		objs: []interface{}{
 			kuardService,
 			&gatewayapi_v1beta1.HTTPRoute{
@@ -4609,16 +5761,9 @@ func TestGatewayAPIHTTPRouteDAGStatus(t *testing.T) {
 					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
 						ParentRefs: []gatewayapi_v1beta1.ParentReference{gatewayapi.GatewayParentRef("projectcontour", "contour")},
 					},
-					Hostnames: []gatewayapi_v1beta1.Hostname{
-						"test.projectcontour.io",
-					},
+					Hostnames: []gatewayapi_v1beta1.Hostname{"test.projectcontour.io"},
 					Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
-						Matches: []gatewayapi_v1beta1.HTTPRouteMatch{{
-							Path: &gatewayapi_v1beta1.HTTPPathMatch{
-								Type:  ref.To(gatewayapi_v1beta1.PathMatchPathPrefix),
-								Value: ref.To("doesnt-start-with-slash"),
-							},
-						}},
+						Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
 						BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
 					}},
 				},
@@ -4629,21 +5774,24 @@ func TestGatewayAPIHTTPRouteDAGStatus(t *testing.T) {
 				{
 					ParentRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
 					Conditions: []metav1.Condition{
-						routeAcceptedHTTPRouteCondition(),
 						routeResolvedRefsCondition(),
-						{
-							Type:    string(status.ConditionValidMatches),
-							Status:  contour_api_v1.ConditionFalse,
-							Reason:  string(status.ReasonInvalidPathMatch),
-							Message: "Match.Path.Value must start with '/'.",
-						},
+						routeAcceptedHTTPRouteCondition(),
 					},
 				},
 			},
 		}},
-		wantGatewayStatusUpdate: validGatewayStatusUpdate("http", "HTTPRoute", 0),
-	})
-	run(t, "exact path match not starting with '/' for httproute", testcase{
+		wantGatewayStatusUpdate: func() []*status.GatewayStatusUpdate {
+			updates := validGatewayStatusUpdate("http", "HTTPRoute", 1)
+			updates[0].ListenerStatus["http"].SupportedKinds = []gatewayapi_v1beta1.RouteGroupKind{{
+				Group: ref.To(gatewayapi_v1beta1.Group(gatewayapi_v1beta1.GroupName)),
+				Kind:  "HTTPRoute",
+			}}
+			return updates
+		}(),
+	})
+
+	run(t, "httproute of a disabled kind is not accepted", testcase{
+		disabledRouteKinds: []string{"HTTPRoute"},
 		objs: []interface{}{
 			kuardService,
 			&gatewayapi_v1beta1.HTTPRoute{
@@ -4655,16 +5803,9 @@ func TestGatewayAPIHTTPRouteDAGStatus(t *testing.T) {
 					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
 						ParentRefs: []gatewayapi_v1beta1.ParentReference{gatewayapi.GatewayParentRef("projectcontour", "contour")},
 					},
-					Hostnames: []gatewayapi_v1beta1.Hostname{
-						"test.projectcontour.io",
-					},
+					Hostnames: []gatewayapi_v1beta1.Hostname{"test.projectcontour.io"},
 					Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
-						Matches: []gatewayapi_v1beta1.HTTPRouteMatch{{
-							Path: &gatewayapi_v1beta1.HTTPPathMatch{
-								Type:  ref.To(gatewayapi_v1beta1.PathMatchExact),
-								Value: ref.To("doesnt-start-with-slash"),
-							},
-						}},
+						Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
 						BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
 					}},
 				},
@@ -4676,21 +5817,28 @@ func TestGatewayAPIHTTPRouteDAGStatus(t *testing.T) {
 					ParentRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
 					Conditions: []metav1.Condition{
 						routeResolvedRefsCondition(),
-						routeAcceptedHTTPRouteCondition(),
 						{
-							Type:    string(status.ConditionValidMatches),
+							Type:    string(gatewayapi_v1beta1.RouteConditionAccepted),
 							Status:  contour_api_v1.ConditionFalse,
-							Reason:  string(status.ReasonInvalidPathMatch),
-							Message: "Match.Path.Value must start with '/'.",
+							Reason:  string(status.ReasonRouteKindDisabled),
+							Message: "HTTPRoutes are disabled",
 						},
 					},
 				},
 			},
 		}},
-		wantGatewayStatusUpdate: validGatewayStatusUpdate("http", "HTTPRoute", 0),
-	})
-
-	run(t, "prefix path match with consecutive '/' characters for httproute", testcase{
+		wantGatewayStatusUpdate: func() []*status.GatewayStatusUpdate {
+			updates := validGatewayStatusUpdate("http", "GRPCRoute", 0)
+			updates[0].ListenerStatus["http"].SupportedKinds = []gatewayapi_v1beta1.RouteGroupKind{{
+				Group: ref.To(gatewayapi_v1beta1.Group(gatewayapi_v1beta1.GroupName)),
+				Kind:  "GRPCRoute",
+			}}
+			return updates
+		}(),
+	})
+
+	run(t, "httproute hostnames under an allowed hostname suffix", testcase{
+		allowedHostnameSuffixes: []string{"projectcontour.io"},
 		objs: []interface{}{
 			kuardService,
 			&gatewayapi_v1beta1.HTTPRoute{
@@ -4704,14 +5852,10 @@ func TestGatewayAPIHTTPRouteDAGStatus(t *testing.T) {
 					},
 					Hostnames: []gatewayapi_v1beta1.Hostname{
 						"test.projectcontour.io",
+						"*.projectcontour.io",
 					},
 					Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
-						Matches: []gatewayapi_v1beta1.HTTPRouteMatch{{
-							Path: &gatewayapi_v1beta1.HTTPPathMatch{
-								Type:  ref.To(gatewayapi_v1beta1.PathMatchPathPrefix),
-								Value: ref.To("/foo///bar"),
-							},
-						}},
+						Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
 						BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
 					}},
 				},
@@ -4724,20 +5868,15 @@ func TestGatewayAPIHTTPRouteDAGStatus(t *testing.T) {
 					Conditions: []metav1.Condition{
 						routeResolvedRefsCondition(),
 						routeAcceptedHTTPRouteCondition(),
-						{
-							Type:    string(status.ConditionValidMatches),
-							Status:  contour_api_v1.ConditionFalse,
-							Reason:  string(status.ReasonInvalidPathMatch),
-							Message: "Match.Path.Value must not contain consecutive '/' characters.",
-						},
 					},
 				},
 			},
 		}},
-		wantGatewayStatusUpdate: validGatewayStatusUpdate("http", "HTTPRoute", 0),
+		wantGatewayStatusUpdate: validGatewayStatusUpdate("http", "HTTPRoute", 1),
 	})
 
-	run(t, "exact path match with consecutive '/' characters for httproute", testcase{
+	run(t, "httproute hostname not under an allowed hostname suffix", testcase{
+		allowedHostnameSuffixes: []string{"projectcontour.io"},
 		objs: []interface{}{
 			kuardService,
 			&gatewayapi_v1beta1.HTTPRoute{
@@ -4751,14 +5890,10 @@ func TestGatewayAPIHTTPRouteDAGStatus(t *testing.T) {
 					},
 					Hostnames: []gatewayapi_v1beta1.Hostname{
 						"test.projectcontour.io",
+						"test.example.com",
 					},
 					Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
-						Matches: []gatewayapi_v1beta1.HTTPRouteMatch{{
-							Path: &gatewayapi_v1beta1.HTTPPathMatch{
-								Type:  ref.To(gatewayapi_v1beta1.PathMatchExact),
-								Value: ref.To("//foo/bar"),
-							},
-						}},
+						Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
 						BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
 					}},
 				},
@@ -4770,12 +5905,11 @@ func TestGatewayAPIHTTPRouteDAGStatus(t *testing.T) {
 					ParentRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
 					Conditions: []metav1.Condition{
 						routeResolvedRefsCondition(),
-						routeAcceptedHTTPRouteCondition(),
 						{
-							Type:    string(status.ConditionValidMatches),
+							Type:    string(gatewayapi_v1beta1.RouteConditionAccepted),
 							Status:  contour_api_v1.ConditionFalse,
-							Reason:  string(status.ReasonInvalidPathMatch),
-							Message: "Match.Path.Value must not contain consecutive '/' characters.",
+							Reason:  string(status.ReasonHostnameNotAllowed),
+							Message: "Hostnames test.example.com are not under any of the allowed hostname suffixes projectcontour.io",
 						},
 					},
 				},
@@ -4784,7 +5918,8 @@ func TestGatewayAPIHTTPRouteDAGStatus(t *testing.T) {
 		wantGatewayStatusUpdate: validGatewayStatusUpdate("http", "HTTPRoute", 0),
 	})
 
-	run(t, "invalid path match type for httproute", testcase{
+	run(t, "httproute without hostnames matches all hostnames when suffixes are enforced", testcase{
+		allowedHostnameSuffixes: []string{"projectcontour.io"},
 		objs: []interface{}{
 			kuardService,
 			&gatewayapi_v1beta1.HTTPRoute{
@@ -4796,16 +5931,8 @@ func TestGatewayAPIHTTPRouteDAGStatus(t *testing.T) {
 					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
 						ParentRefs: []gatewayapi_v1beta1.ParentReference{gatewayapi.GatewayParentRef("projectcontour", "contour")},
 					},
-					Hostnames: []gatewayapi_v1beta1.Hostname{
-						"test.projectcontour.io",
-					},
 					Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
-						Matches: []gatewayapi_v1beta1.HTTPRouteMatch{{
-							Path: &gatewayapi_v1beta1.HTTPPathMatch{
-								Type:  ref.To(gatewayapi_v1beta1.PathMatchType("UNKNOWN")), // <---- unknown type to break the test
-								Value: ref.To("/"),
-							},
-						}},
+						Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
 						BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
 					}},
 				},
@@ -4820,8 +5947,8 @@ func TestGatewayAPIHTTPRouteDAGStatus(t *testing.T) {
 						{
 							Type:    string(gatewayapi_v1beta1.RouteConditionAccepted),
 							Status:  contour_api_v1.ConditionFalse,
-							Reason:  string(gatewayapi_v1beta1.RouteReasonUnsupportedValue),
-							Message: "HTTPRoute.Spec.Rules.PathMatch: Only Prefix match type and Exact match type are supported.",
+							Reason:  string(status.ReasonHostnameNotAllowed),
+							Message: "Hostnames * are not under any of the allowed hostname suffixes projectcontour.io",
 						},
 					},
 				},
@@ -5082,6 +6209,88 @@ func TestGatewayAPIHTTPRouteDAGStatus(t *testing.T) {
 		wantGatewayStatusUpdate: validGatewayStatusUpdate("http", "HTTPRoute", 1),
 	})
 
+	externalNameService := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "external",
+			Namespace: "default",
+		},
+		Spec: v1.ServiceSpec{
+			Type:         v1.ServiceTypeExternalName,
+			ExternalName: "external.example.com",
+			Ports: []v1.ServicePort{{
+				Name:     "http",
+				Protocol: "TCP",
+				Port:     80,
+			}},
+		},
+	}
+
+	externalNameRoute := &gatewayapi_v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "basic",
+			Namespace: "default",
+		},
+		Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+				ParentRefs: []gatewayapi_v1beta1.ParentReference{gatewayapi.GatewayParentRef("projectcontour", "contour")},
+			},
+			Hostnames: []gatewayapi_v1beta1.Hostname{
+				"test.projectcontour.io",
+			},
+			Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+				Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
+				BackendRefs: gatewayapi.HTTPBackendRef("external", 80, 1),
+			}},
+		},
+	}
+
+	run(t, "spec.rules.backendRef is an ExternalName service, ExternalName services disabled", testcase{
+		objs: []interface{}{
+			externalNameService,
+			externalNameRoute,
+		},
+		wantRouteConditions: []*status.RouteStatusUpdate{{
+			FullName: types.NamespacedName{Namespace: "default", Name: "basic"},
+			RouteParentStatuses: []*gatewayapi_v1beta1.RouteParentStatus{
+				{
+					ParentRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
+					Conditions: []metav1.Condition{
+						{
+							Type:    string(gatewayapi_v1beta1.RouteConditionResolvedRefs),
+							Status:  contour_api_v1.ConditionFalse,
+							Reason:  string(gatewayapi_v1beta1.RouteReasonBackendNotFound),
+							Message: "service \"external\" is invalid: default/external is an ExternalName service, these are not currently enabled. See the config.enableExternalNameService config file setting",
+						},
+						routeAcceptedHTTPRouteCondition(),
+					},
+				},
+			},
+		}},
+		// This still results in an attached route because it returns a 404.
+		wantGatewayStatusUpdate: validGatewayStatusUpdate("http", "HTTPRoute", 1),
+	})
+
+	run(t, "spec.rules.backendRef is an ExternalName service, ExternalName services enabled", testcase{
+		objs: []interface{}{
+			externalNameService,
+			externalNameRoute,
+		},
+		enableExternalNameService: true,
+		wantRouteConditions: []*status.RouteStatusUpdate{{
+			FullName: types.NamespacedName{Namespace: "default", Name: "basic"},
+			RouteParentStatuses: []*gatewayapi_v1beta1.RouteParentStatus{
+				{
+					ParentRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
+					Conditions: []metav1.Condition{
+						routeResolvedRefsCondition(),
+						routeAcceptedHTTPRouteCondition(),
+					},
+				},
+			},
+		}},
+		wantGatewayStatusUpdate: validGatewayStatusUpdate("http", "HTTPRoute", 1),
+	})
+
 	run(t, "spec.rules.backendRef.port not specified", testcase{
 		objs: []interface{}{
 			kuardService,
@@ -5221,13 +6430,68 @@ func TestGatewayAPIHTTPRouteDAGStatus(t *testing.T) {
 					},
 				},
 			},
-		}},
-		// This still results in an attached route because it returns a 404.
-		wantGatewayStatusUpdate: validGatewayStatusUpdate("http", "HTTPRoute", 1),
+		}},
+		// This still results in an attached route because it returns a 404.
+		wantGatewayStatusUpdate: validGatewayStatusUpdate("http", "HTTPRoute", 1),
+	})
+
+	// BEGIN TLS CertificateRef + ReferenceGrant tests
+	run(t, "Gateway references TLS cert in different namespace, with valid ReferenceGrant", testcase{
+		gateway: &gatewayapi_v1beta1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "contour",
+				Namespace: "projectcontour",
+			},
+			Spec: gatewayapi_v1beta1.GatewaySpec{
+				GatewayClassName: gatewayapi_v1beta1.ObjectName("projectcontour.io/contour"),
+				Listeners: []gatewayapi_v1beta1.Listener{{
+					Name:     "https",
+					Port:     443,
+					Protocol: gatewayapi_v1beta1.HTTPSProtocolType,
+					TLS: &gatewayapi_v1beta1.GatewayTLSConfig{
+						Mode: ref.To(gatewayapi_v1beta1.TLSModeTerminate),
+						CertificateRefs: []gatewayapi_v1beta1.SecretObjectReference{
+							gatewayapi.CertificateRef("secret", "tls-cert-namespace"),
+						},
+					},
+					AllowedRoutes: &gatewayapi_v1beta1.AllowedRoutes{
+						Namespaces: &gatewayapi_v1beta1.RouteNamespaces{
+							From: ref.To(gatewayapi_v1beta1.NamespacesFromAll),
+						},
+					},
+				}},
+			},
+		},
+		objs: []interface{}{
+			&v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret",
+					Namespace: "tls-cert-namespace",
+				},
+				Type: v1.SecretTypeTLS,
+				Data: secretdata(fixture.CERTIFICATE, fixture.RSA_PRIVATE_KEY),
+			},
+			&gatewayapi_v1beta1.ReferenceGrant{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "tls-cert-reference-policy",
+					Namespace: "tls-cert-namespace",
+				},
+				Spec: gatewayapi_v1beta1.ReferenceGrantSpec{
+					From: []gatewayapi_v1beta1.ReferenceGrantFrom{{
+						Group:     gatewayapi_v1beta1.GroupName,
+						Kind:      "Gateway",
+						Namespace: gatewayapi_v1beta1.Namespace("projectcontour"),
+					}},
+					To: []gatewayapi_v1beta1.ReferenceGrantTo{{
+						Kind: "Secret",
+					}},
+				},
+			},
+		},
+		wantGatewayStatusUpdate: validGatewayStatusUpdate("https", "HTTPRoute", 0),
 	})
 
-	// BEGIN TLS CertificateRef + ReferenceGrant tests
-	run(t, "Gateway references TLS cert in different namespace, with valid ReferenceGrant", testcase{
+	run(t, "Gateway listener with unsupported TLS options is still programmed", testcase{
 		gateway: &gatewayapi_v1beta1.Gateway{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      "contour",
@@ -5242,7 +6506,11 @@ func TestGatewayAPIHTTPRouteDAGStatus(t *testing.T) {
 					TLS: &gatewayapi_v1beta1.GatewayTLSConfig{
 						Mode: ref.To(gatewayapi_v1beta1.TLSModeTerminate),
 						CertificateRefs: []gatewayapi_v1beta1.SecretObjectReference{
-							gatewayapi.CertificateRef("secret", "tls-cert-namespace"),
+							gatewayapi.CertificateRef("secret", ""),
+						},
+						Options: map[gatewayapi_v1beta1.AnnotationKey]gatewayapi_v1beta1.AnnotationValue{
+							gatewayapi.TLSOptionSessionTickets: "disabled",
+							"example.com/early-data":           "true",
 						},
 					},
 					AllowedRoutes: &gatewayapi_v1beta1.AllowedRoutes{
@@ -5257,29 +6525,75 @@ func TestGatewayAPIHTTPRouteDAGStatus(t *testing.T) {
 			&v1.Secret{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "secret",
-					Namespace: "tls-cert-namespace",
+					Namespace: "projectcontour",
 				},
 				Type: v1.SecretTypeTLS,
 				Data: secretdata(fixture.CERTIFICATE, fixture.RSA_PRIVATE_KEY),
 			},
-			&gatewayapi_v1beta1.ReferenceGrant{
+		},
+		wantGatewayStatusUpdate: func() []*status.GatewayStatusUpdate {
+			updates := validGatewayStatusUpdate("https", "HTTPRoute", 0)
+			listener := updates[0].ListenerStatus["https"]
+			listener.Conditions = append(listener.Conditions, metav1.Condition{
+				Type:    string(status.ListenerConditionTLSOptionsValid),
+				Status:  metav1.ConditionFalse,
+				Reason:  string(status.ListenerReasonTLSOptionsIgnored),
+				Message: `TLS option "example.com/early-data" is not supported`,
+			})
+			return updates
+		}(),
+	})
+
+	run(t, "TLS listener with the default certificate TLS option is still programmed", testcase{
+		gateway: &gatewayapi_v1beta1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "contour",
+				Namespace: "projectcontour",
+			},
+			Spec: gatewayapi_v1beta1.GatewaySpec{
+				GatewayClassName: gatewayapi_v1beta1.ObjectName("projectcontour.io/contour"),
+				Listeners: []gatewayapi_v1beta1.Listener{{
+					Name:     "tls",
+					Port:     443,
+					Protocol: gatewayapi_v1beta1.TLSProtocolType,
+					TLS: &gatewayapi_v1beta1.GatewayTLSConfig{
+						Mode: ref.To(gatewayapi_v1beta1.TLSModeTerminate),
+						CertificateRefs: []gatewayapi_v1beta1.SecretObjectReference{
+							gatewayapi.CertificateRef("secret", ""),
+						},
+						Options: map[gatewayapi_v1beta1.AnnotationKey]gatewayapi_v1beta1.AnnotationValue{
+							gatewayapi.TLSOptionDefaultCertificate: "true",
+						},
+					},
+					AllowedRoutes: &gatewayapi_v1beta1.AllowedRoutes{
+						Namespaces: &gatewayapi_v1beta1.RouteNamespaces{
+							From: ref.To(gatewayapi_v1beta1.NamespacesFromAll),
+						},
+					},
+				}},
+			},
+		},
+		objs: []interface{}{
+			&v1.Secret{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      "tls-cert-reference-policy",
-					Namespace: "tls-cert-namespace",
-				},
-				Spec: gatewayapi_v1beta1.ReferenceGrantSpec{
-					From: []gatewayapi_v1beta1.ReferenceGrantFrom{{
-						Group:     gatewayapi_v1beta1.GroupName,
-						Kind:      "Gateway",
-						Namespace: gatewayapi_v1beta1.Namespace("projectcontour"),
-					}},
-					To: []gatewayapi_v1beta1.ReferenceGrantTo{{
-						Kind: "Secret",
-					}},
+					Name:      "secret",
+					Namespace: "projectcontour",
 				},
+				Type: v1.SecretTypeTLS,
+				Data: secretdata(fixture.CERTIFICATE, fixture.RSA_PRIVATE_KEY),
 			},
 		},
-		wantGatewayStatusUpdate: validGatewayStatusUpdate("https", "HTTPRoute", 0),
+		wantGatewayStatusUpdate: func() []*status.GatewayStatusUpdate {
+			updates := validGatewayStatusUpdate("tls", "TLSRoute", 0)
+			listener := updates[0].ListenerStatus["tls"]
+			listener.Conditions = append(listener.Conditions, metav1.Condition{
+				Type:    string(status.ListenerConditionTLSOptionsValid),
+				Status:  metav1.ConditionFalse,
+				Reason:  string(status.ListenerReasonTLSOptionsIgnored),
+				Message: `TLS option "projectcontour.io/default-certificate" is only supported when protocol is "HTTPS"`,
+			})
+			return updates
+		}(),
 	})
 
 	run(t, "Gateway references TLS cert in different namespace, with no ReferenceGrant", testcase{
@@ -7167,6 +8481,109 @@ func TestGatewayAPIHTTPRouteDAGStatus(t *testing.T) {
 		wantGatewayStatusUpdate: validGatewayStatusUpdate("http", "HTTPRoute", 1),
 	})
 
+	run(t, "HTTPRoute rule with a RequestRedirect filter and backendRefs is not accepted", testcase{
+		objs: []interface{}{
+			kuardService,
+			&gatewayapi_v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "basic",
+					Namespace: "default",
+				},
+				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1beta1.ParentReference{gatewayapi.GatewayParentRef("projectcontour", "contour")},
+					},
+					Hostnames: []gatewayapi_v1beta1.Hostname{
+						"test.projectcontour.io",
+					},
+					Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+						Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
+						BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
+						Filters: []gatewayapi_v1beta1.HTTPRouteFilter{{
+							Type: gatewayapi_v1beta1.HTTPRouteFilterRequestRedirect,
+							RequestRedirect: &gatewayapi_v1beta1.HTTPRequestRedirectFilter{
+								Hostname: ref.To(gatewayapi_v1beta1.PreciseHostname("envoyproxy.io")),
+							},
+						}},
+					}},
+				},
+			}},
+		wantRouteConditions: []*status.RouteStatusUpdate{{
+			FullName: types.NamespacedName{Namespace: "default", Name: "basic"},
+			RouteParentStatuses: []*gatewayapi_v1beta1.RouteParentStatus{
+				{
+					ParentRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
+					Conditions: []metav1.Condition{
+						routeResolvedRefsCondition(),
+						{
+							Type:    string(gatewayapi_v1beta1.RouteConditionAccepted),
+							Status:  metav1.ConditionFalse,
+							Reason:  string(status.ReasonIncompatibleFilters),
+							Message: "HTTPRoute.Spec.Rules.Filters: a RequestRedirect filter cannot be combined with backendRefs.",
+						},
+					},
+				},
+			},
+		}},
+		// No routes are programmed for the rule, so none are attached.
+		wantGatewayStatusUpdate: validGatewayStatusUpdate("http", "HTTPRoute", 0),
+	})
+
+	run(t, "HTTPRoute rule with RequestRedirect and URLRewrite filters is not accepted", testcase{
+		objs: []interface{}{
+			kuardService,
+			&gatewayapi_v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "basic",
+					Namespace: "default",
+				},
+				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1beta1.ParentReference{gatewayapi.GatewayParentRef("projectcontour", "contour")},
+					},
+					Hostnames: []gatewayapi_v1beta1.Hostname{
+						"test.projectcontour.io",
+					},
+					Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+						Matches: gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
+						Filters: []gatewayapi_v1beta1.HTTPRouteFilter{
+							{
+								Type: gatewayapi_v1beta1.HTTPRouteFilterRequestRedirect,
+								RequestRedirect: &gatewayapi_v1beta1.HTTPRequestRedirectFilter{
+									Hostname: ref.To(gatewayapi_v1beta1.PreciseHostname("envoyproxy.io")),
+								},
+							},
+							{
+								Type: gatewayapi_v1beta1.HTTPRouteFilterURLRewrite,
+								URLRewrite: &gatewayapi_v1beta1.HTTPURLRewriteFilter{
+									Hostname: ref.To(gatewayapi_v1beta1.PreciseHostname("url.rewritten.com")),
+								},
+							},
+						},
+					}},
+				},
+			}},
+		wantRouteConditions: []*status.RouteStatusUpdate{{
+			FullName: types.NamespacedName{Namespace: "default", Name: "basic"},
+			RouteParentStatuses: []*gatewayapi_v1beta1.RouteParentStatus{
+				{
+					ParentRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
+					Conditions: []metav1.Condition{
+						routeResolvedRefsCondition(),
+						{
+							Type:    string(gatewayapi_v1beta1.RouteConditionAccepted),
+							Status:  metav1.ConditionFalse,
+							Reason:  string(status.ReasonIncompatibleFilters),
+							Message: "HTTPRoute.Spec.Rules.Filters: a RequestRedirect filter cannot be combined with a URLRewrite filter.",
+						},
+					},
+				},
+			},
+		}},
+		// No routes are programmed for the rule, so none are attached.
+		wantGatewayStatusUpdate: validGatewayStatusUpdate("http", "HTTPRoute", 0),
+	})
+
 	run(t, "Invalid RequestHeaderModifier due to duplicated headers", testcase{
 		objs: []interface{}{
 			kuardService,
@@ -8077,7 +9494,7 @@ func TestGatewayAPIHTTPRouteDAGStatus(t *testing.T) {
 		}},
 	})
 
-	run(t, "TLS listener with TLS.Mode=Terminate results in a listener condition", testcase{
+	run(t, "TLS listener with TLS.Mode=Terminate and no certificateRefs results in a listener condition", testcase{
 		objs: []interface{}{},
 		gateway: &gatewayapi_v1beta1.Gateway{
 			ObjectMeta: metav1.ObjectMeta{
@@ -8096,9 +9513,6 @@ func TestGatewayAPIHTTPRouteDAGStatus(t *testing.T) {
 					},
 					TLS: &gatewayapi_v1beta1.GatewayTLSConfig{
 						Mode: ref.To(gatewayapi_v1beta1.TLSModeTerminate),
-						CertificateRefs: []gatewayapi_v1beta1.SecretObjectReference{
-							gatewayapi.CertificateRef("tlscert", "projectcontour"),
-						},
 					},
 				}},
 			},
@@ -8128,7 +9542,7 @@ func TestGatewayAPIHTTPRouteDAGStatus(t *testing.T) {
 							Type:    string(gatewayapi_v1beta1.ListenerConditionProgrammed),
 							Status:  metav1.ConditionFalse,
 							Reason:  "Invalid",
-							Message: "Listener.TLS.Mode must be \"Passthrough\" when protocol is \"TLS\".",
+							Message: "Listener.TLS.CertificateRefs must be defined when Listener.TLS.Mode is \"Terminate\".",
 						},
 						{
 							Type:    string(gatewayapi_v1beta1.ListenerConditionAccepted),
@@ -8416,6 +9830,7 @@ func TestGatewayAPITLSRouteDAGStatus(t *testing.T) {
 	type testcase struct {
 		objs                    []interface{}
 		gateway                 *gatewayapi_v1beta1.Gateway
+		disabledRouteKinds      []string
 		wantRouteConditions     []*status.RouteStatusUpdate
 		wantGatewayStatusUpdate []*status.GatewayStatusUpdate
 	}
@@ -8454,7 +9869,8 @@ func TestGatewayAPITLSRouteDAGStatus(t *testing.T) {
 					},
 					&HTTPProxyProcessor{},
 					&GatewayAPIProcessor{
-						FieldLogger: fixture.NewTestLogger(t),
+						FieldLogger:        fixture.NewTestLogger(t),
+						DisabledRouteKinds: tc.disabledRouteKinds,
 					},
 				},
 			}
@@ -8542,6 +9958,118 @@ func TestGatewayAPITLSRouteDAGStatus(t *testing.T) {
 		},
 	}
 
+	run(t, "TLSRoute: route kind disabled", testcase{
+		gateway:            gw,
+		disabledRouteKinds: []string{"TLSRoute"},
+		objs: []interface{}{
+			kuardService,
+			&gatewayapi_v1alpha2.TLSRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "basic",
+					Namespace: "default",
+				},
+				Spec: gatewayapi_v1alpha2.TLSRouteSpec{
+					CommonRouteSpec: gatewayapi_v1alpha2.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1alpha2.ParentReference{
+							gatewayapi.GatewayParentRef("projectcontour", "contour"),
+						},
+					},
+					Hostnames: []gatewayapi_v1alpha2.Hostname{"test.projectcontour.io"},
+					Rules: []gatewayapi_v1alpha2.TLSRouteRule{{
+						BackendRefs: gatewayapi.TLSRouteBackendRef("kuard", 8080, nil),
+					}},
+				},
+			}},
+		wantRouteConditions: []*status.RouteStatusUpdate{{
+			FullName: types.NamespacedName{Namespace: "default", Name: "basic"},
+			RouteParentStatuses: []*gatewayapi_v1beta1.RouteParentStatus{
+				{
+					ParentRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
+					Conditions: []metav1.Condition{
+						routeResolvedRefsCondition(),
+						{
+							Type:    string(gatewayapi_v1beta1.RouteConditionAccepted),
+							Status:  contour_api_v1.ConditionFalse,
+							Reason:  string(status.ReasonRouteKindDisabled),
+							Message: "TLSRoutes are disabled",
+						},
+					},
+				},
+			},
+		}},
+		wantGatewayStatusUpdate: func() []*status.GatewayStatusUpdate {
+			updates := validGatewayStatusUpdate(string(gw.Spec.Listeners[0].Name), "TLSRoute", 0)
+			updates[0].ListenerStatus[string(gw.Spec.Listeners[0].Name)].SupportedKinds = nil
+			return updates
+		}(),
+	})
+
+	run(t, "TLSRoute: disabled route kind in the listener's allowed kinds", testcase{
+		gateway: &gatewayapi_v1beta1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "contour",
+				Namespace: "projectcontour",
+			},
+			Spec: gatewayapi_v1beta1.GatewaySpec{
+				Listeners: []gatewayapi_v1beta1.Listener{{
+					Name:     "tls-passthrough",
+					Port:     443,
+					Protocol: gatewayapi_v1beta1.TLSProtocolType,
+					TLS: &gatewayapi_v1beta1.GatewayTLSConfig{
+						Mode: ref.To(gatewayapi_v1beta1.TLSModePassthrough),
+					},
+					AllowedRoutes: &gatewayapi_v1beta1.AllowedRoutes{
+						Kinds: []gatewayapi_v1beta1.RouteGroupKind{
+							{Kind: "TLSRoute"},
+						},
+						Namespaces: &gatewayapi_v1beta1.RouteNamespaces{
+							From: ref.To(gatewayapi_v1beta1.NamespacesFromAll),
+						},
+					},
+				}},
+			},
+		},
+		disabledRouteKinds: []string{"TLSRoute"},
+		wantGatewayStatusUpdate: []*status.GatewayStatusUpdate{{
+			FullName: types.NamespacedName{Namespace: "projectcontour", Name: "contour"},
+			Conditions: map[gatewayapi_v1beta1.GatewayConditionType]metav1.Condition{
+				gatewayapi_v1beta1.GatewayConditionAccepted: gatewayAcceptedCondition(),
+				gatewayapi_v1beta1.GatewayConditionProgrammed: {
+					Type:    string(gatewayapi_v1beta1.GatewayConditionProgrammed),
+					Status:  contour_api_v1.ConditionFalse,
+					Reason:  string(gatewayapi_v1beta1.GatewayReasonListenersNotValid),
+					Message: "Listeners are not valid",
+				},
+			},
+			ListenerStatus: map[string]*gatewayapi_v1beta1.ListenerStatus{
+				"tls-passthrough": {
+					Name:           "tls-passthrough",
+					SupportedKinds: nil,
+					Conditions: []metav1.Condition{
+						{
+							Type:    string(gatewayapi_v1beta1.ListenerConditionProgrammed),
+							Status:  metav1.ConditionFalse,
+							Reason:  "Invalid",
+							Message: "Invalid listener, see other listener conditions for details",
+						},
+						{
+							Type:    string(gatewayapi_v1beta1.ListenerConditionAccepted),
+							Status:  metav1.ConditionTrue,
+							Reason:  string(gatewayapi_v1beta1.ListenerReasonAccepted),
+							Message: "Listener accepted",
+						},
+						{
+							Type:    string(gatewayapi_v1beta1.ListenerConditionResolvedRefs),
+							Status:  metav1.ConditionFalse,
+							Reason:  string(gatewayapi_v1beta1.ListenerReasonInvalidRouteKinds),
+							Message: "Kind \"TLSRoute\" is disabled",
+						},
+					},
+				},
+			},
+		}},
+	})
+
 	run(t, "TLSRoute: spec.rules.backendRef.name not specified", testcase{
 		gateway: gw,
 		objs: []interface{}{
@@ -17,14 +17,22 @@ package envoy
 
 import (
 	"fmt"
+	"math"
 	"net"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/projectcontour/contour/pkg/config"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 )
 
+// LocalClusterName is the name of the cluster of the Envoy pods
+// themselves, which Envoy needs to route requests zone aware.
+const LocalClusterName = "envoy-local"
+
 // SDSResourcesSubdirectory stores the subdirectory name where SDS path resources are stored to.
 const SDSResourcesSubdirectory = "sds"
 
@@ -94,6 +102,58 @@ type BootstrapConfig struct {
 	// MaximumHeapSizeBytes specifies the number of bytes that overload manager allows heap to grow to.
 	// When reaching the set threshold, new connections are denied.
 	MaximumHeapSizeBytes uint64
+
+	// TopologyAwareRouting adds the local cluster to the bootstrap
+	// configuration so that Envoy can route requests zone aware.
+	// Envoy must also know its zone, see Zone.
+	TopologyAwareRouting bool
+
+	// NodeName is the name of the node Envoy runs on. If set, Zone
+	// is looked up from the node's zone label.
+	NodeName string
+
+	// Zone is the zone Envoy runs in, which Envoy's --service-zone
+	// flag overrides.
+	Zone string
+
+	// StatsFlushInterval is how often Envoy flushes its stats.
+	// Envoy's default of 5s is used if zero.
+	StatsFlushInterval time.Duration
+
+	// HistogramBuckets overrides the buckets of the histograms
+	// whose names match. The first match is used.
+	HistogramBuckets []HistogramBuckets
+}
+
+// HistogramBuckets holds the bucket boundaries of the
+// histograms whose stat names match Regex.
+type HistogramBuckets struct {
+	Regex   string
+	Buckets []float64
+}
+
+// ParseHistogramBuckets parses histogram buckets in the form
+// "<regex>=<bucket>,<bucket>,...". The regex may itself contain
+// '=', so the buckets follow the last one.
+func ParseHistogramBuckets(s string) (HistogramBuckets, error) {
+	i := strings.LastIndex(s, "=")
+	if i < 1 {
+		return HistogramBuckets{}, fmt.Errorf("invalid value %q, must be in the form <regex>=<bucket>,<bucket>,...", s)
+	}
+
+	hb := HistogramBuckets{Regex: s[:i]}
+	for _, b := range strings.Split(s[i+1:], ",") {
+		v, err := strconv.ParseFloat(b, 64)
+		if err != nil || v <= 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+			return HistogramBuckets{}, fmt.Errorf("invalid bucket %q, must be a positive number", b)
+		}
+		if n := len(hb.Buckets); n > 0 && v <= hb.Buckets[n-1] {
+			return HistogramBuckets{}, fmt.Errorf("invalid value %q, buckets must be in ascending order", s)
+		}
+		hb.Buckets = append(hb.Buckets, v)
+	}
+
+	return hb, nil
 }
 
 // GetXdsAddress returns the address configured or defaults to "127.0.0.1"
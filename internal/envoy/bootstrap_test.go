@@ -40,3 +40,56 @@ func TestValidAdminAddress(t *testing.T) {
 		})
 	}
 }
+
+func TestParseHistogramBuckets(t *testing.T) {
+	tests := map[string]struct {
+		value   string
+		want    HistogramBuckets
+		wantErr bool
+	}{
+		"single bucket": {
+			value: `cluster\..*\.upstream_rq_time=0.5`,
+			want:  HistogramBuckets{Regex: `cluster\..*\.upstream_rq_time`, Buckets: []float64{0.5}},
+		},
+		"regex with '='": {
+			value: "a=b=1,10,100",
+			want:  HistogramBuckets{Regex: "a=b", Buckets: []float64{1, 10, 100}},
+		},
+		"no buckets": {
+			value:   "http",
+			wantErr: true,
+		},
+		"no regex": {
+			value:   "=1,2",
+			wantErr: true,
+		},
+		"invalid bucket": {
+			value:   "http=1,foo",
+			wantErr: true,
+		},
+		"zero bucket": {
+			value:   "http=0,1",
+			wantErr: true,
+		},
+		"buckets out of order": {
+			value:   "http=10,1",
+			wantErr: true,
+		},
+		"duplicate buckets": {
+			value:   "http=1,1",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseHistogramBuckets(tc.value)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
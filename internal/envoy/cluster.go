@@ -57,6 +57,21 @@ func Clustername(cluster *dag.Cluster) string {
 	if cluster.SlowStartConfig != nil {
 		buf += cluster.SlowStartConfig.String()
 	}
+	if cluster.IgnoreNewHostsUntilFirstHC {
+		buf += "ignoreNewHostsUntilFirstHC"
+	}
+	if cluster.ConnectionPoolKey != "" || cluster.AltStatName != "" {
+		// The connection pool key is built from route conditions and may
+		// contain anything, but the alt stat name can not contain '|', so
+		// the pair is unambiguous.
+		buf += cluster.ConnectionPoolKey + "|" + cluster.AltStatName
+	}
+	if rb := cluster.RetryBudget; rb != nil {
+		buf += fmt.Sprintf("retryBudget%d/%d", rb.Percent, rb.MinRetryConcurrency)
+	}
+	for _, f := range cluster.Failover {
+		buf += f.ServiceNamespace + f.ServiceName + strconv.Itoa(int(f.ServicePort.Port)) + strconv.Itoa(int(f.Priority))
+	}
 
 	// This isn't a crypto hash, we just want a unique name.
 	hash := sha1.Sum([]byte(buf)) // nolint:gosec
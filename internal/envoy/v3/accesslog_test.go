@@ -135,6 +135,30 @@ func TestJSONFileAccessLog(t *testing.T) {
 			},
 			},
 		},
+		"request id": {
+			path:    "/dev/stdout",
+			headers: contour_api_v1alpha1.AccessLogJSONFields([]string{"request_id"}),
+			want: []*envoy_accesslog_v3.AccessLog{{
+				Name: wellknown.FileAccessLog,
+				ConfigType: &envoy_accesslog_v3.AccessLog_TypedConfig{
+					TypedConfig: protobuf.MustMarshalAny(&envoy_file_v3.FileAccessLog{
+						Path: "/dev/stdout",
+						AccessLogFormat: &envoy_file_v3.FileAccessLog_LogFormat{
+							LogFormat: &envoy_config_core_v3.SubstitutionFormatString{
+								Format: &envoy_config_core_v3.SubstitutionFormatString_JsonFormat{
+									JsonFormat: &structpb.Struct{
+										Fields: map[string]*structpb.Value{
+											"request_id": sv("%REQ(X-REQUEST-ID)%"),
+										},
+									},
+								},
+							},
+						},
+					}),
+				},
+			},
+			},
+		},
 		"custom fields should appear": {
 			path: "/dev/stdout",
 			headers: contour_api_v1alpha1.AccessLogJSONFields([]string{
@@ -264,3 +288,10 @@ func TestAccessLogLevel(t *testing.T) {
 	// Log level disabled should return nil.
 	assert.Nil(t, FileAccessLogJSON("/dev/stdout", nil, nil, contour_api_v1alpha1.LogLevelDisabled))
 }
+
+func TestDefaultJSONFieldsIncludeRequestID(t *testing.T) {
+	// The request ID is the correlation ID that is returned to clients
+	// when setRequestIDInResponse is enabled, so it must be logged by default.
+	assert.Contains(t, contour_api_v1alpha1.DefaultAccessLogJSONFields, "request_id")
+	assert.Equal(t, "%REQ(X-REQUEST-ID)%", contour_api_v1alpha1.DefaultAccessLogJSONFields.AsFieldMap()["request_id"])
+}
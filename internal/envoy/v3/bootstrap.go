@@ -29,6 +29,7 @@ import (
 	envoy_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
 	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoy_endpoint_v3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	envoy_metrics_v3 "github.com/envoyproxy/go-control-plane/envoy/config/metrics/v3"
 	envoy_config_overload_v3 "github.com/envoyproxy/go-control-plane/envoy/config/overload/v3"
 	envoy_file_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/access_loggers/file/v3"
 	envoy_regex_engines_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/regex_engines/v3"
@@ -216,7 +217,7 @@ func bootstrapConfig(c *envoy.BootstrapConfig) *envoy_bootstrap_v3.Bootstrap {
 						KeepaliveInterval: wrapperspb.UInt32(5),
 					},
 				},
-				TypedExtensionProtocolOptions: protocolOptions(HTTPVersion2, timeout.DefaultSetting()),
+				TypedExtensionProtocolOptions: protocolOptions(HTTPVersion2, timeout.DefaultSetting(), 0),
 				CircuitBreakers: &envoy_cluster_v3.CircuitBreakers{
 					Thresholds: []*envoy_cluster_v3.CircuitBreakers_Thresholds{{
 						Priority:           envoy_core_v3.RoutingPriority_HIGH,
@@ -299,6 +300,44 @@ func bootstrapConfig(c *envoy.BootstrapConfig) *envoy_bootstrap_v3.Bootstrap {
 			},
 		}
 	}
+	if c.TopologyAwareRouting {
+		// Contour serves the endpoints of the Envoy service as the
+		// local cluster, which tells Envoy how its own pods are spread
+		// across zones.
+		bootstrap.StaticResources.Clusters = append(bootstrap.StaticResources.Clusters, &envoy_cluster_v3.Cluster{
+			Name:                 envoy.LocalClusterName,
+			ConnectTimeout:       durationpb.New(5 * time.Second),
+			ClusterDiscoveryType: ClusterDiscoveryType(envoy_cluster_v3.Cluster_EDS),
+			EdsClusterConfig: &envoy_cluster_v3.Cluster_EdsClusterConfig{
+				EdsConfig:   ConfigSource("contour"),
+				ServiceName: envoy.LocalClusterName,
+			},
+		})
+		bootstrap.ClusterManager = &envoy_bootstrap_v3.ClusterManager{
+			LocalClusterName: envoy.LocalClusterName,
+		}
+	}
+	if c.Zone != "" {
+		bootstrap.Node = &envoy_core_v3.Node{
+			Locality: &envoy_core_v3.Locality{Zone: c.Zone},
+		}
+	}
+	if c.StatsFlushInterval > 0 {
+		bootstrap.StatsFlushInterval = durationpb.New(c.StatsFlushInterval)
+	}
+	if len(c.HistogramBuckets) > 0 {
+		bootstrap.StatsConfig = &envoy_metrics_v3.StatsConfig{}
+		for _, hb := range c.HistogramBuckets {
+			bootstrap.StatsConfig.HistogramBucketSettings = append(bootstrap.StatsConfig.HistogramBucketSettings, &envoy_metrics_v3.HistogramBucketSettings{
+				Match: &matcher.StringMatcher{
+					MatchPattern: &matcher.StringMatcher_SafeRegex{
+						SafeRegex: SafeRegexMatch(hb.Regex),
+					},
+				},
+				Buckets: hb.Buckets,
+			})
+		}
+	}
 	return bootstrap
 }
 
@@ -16,14 +16,18 @@ package v3
 import (
 	"path"
 	"testing"
+	"time"
 
 	envoy_bootstrap_v3 "github.com/envoyproxy/go-control-plane/envoy/config/bootstrap/v3"
+	envoy_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	envoy_metrics_v3 "github.com/envoyproxy/go-control-plane/envoy/config/metrics/v3"
 	envoy_service_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 	"github.com/projectcontour/contour/internal/envoy"
 	"github.com/projectcontour/contour/internal/protobuf"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 func TestBootstrap(t *testing.T) {
@@ -2096,6 +2100,102 @@ func TestBootstrap(t *testing.T) {
 	}
 }
 
+func TestBootstrapTopologyAwareRouting(t *testing.T) {
+	c := &envoy.BootstrapConfig{
+		Path:                 "envoy.json",
+		Namespace:            "projectcontour",
+		TopologyAwareRouting: true,
+	}
+	b := bootstrapConfig(c)
+
+	want := new(envoy_cluster_v3.Cluster)
+	unmarshal(t, `{
+	  "name": "envoy-local",
+	  "type": "EDS",
+	  "connect_timeout": "5s",
+	  "eds_cluster_config": {
+	    "eds_config": {
+	      "api_config_source": {
+	        "api_type": "GRPC",
+	        "transport_api_version": "V3",
+	        "grpc_services": [
+	          {
+	            "envoy_grpc": {
+	              "cluster_name": "contour",
+	              "authority": "contour"
+	            }
+	          }
+	        ]
+	      },
+	      "resource_api_version": "V3"
+	    },
+	    "service_name": "envoy-local"
+	  }
+	}`, want)
+
+	clusters := b.GetStaticResources().GetClusters()
+	protobuf.ExpectEqual(t, want, clusters[len(clusters)-1])
+	assert.Equal(t, "envoy-local", b.GetClusterManager().GetLocalClusterName())
+	assert.Nil(t, b.GetNode())
+
+	// The zone is Envoy's locality.
+	c.Zone = "zone-a"
+	b = bootstrapConfig(c)
+	assert.Equal(t, "zone-a", b.GetNode().GetLocality().GetZone())
+
+	// Without topology aware routing, there is no local cluster.
+	c.TopologyAwareRouting = false
+	c.Zone = ""
+	b = bootstrapConfig(c)
+	assert.Len(t, b.GetStaticResources().GetClusters(), 2)
+	assert.Nil(t, b.GetClusterManager())
+}
+
+func TestBootstrapStats(t *testing.T) {
+	c := &envoy.BootstrapConfig{
+		Path:               "envoy.json",
+		Namespace:          "projectcontour",
+		StatsFlushInterval: 10 * time.Second,
+		HistogramBuckets: []envoy.HistogramBuckets{
+			{Regex: `cluster\..*\.upstream_rq_time`, Buckets: []float64{0.5, 1, 5}},
+			{Regex: "http\\..*", Buckets: []float64{10}},
+		},
+	}
+	b := bootstrapConfig(c)
+
+	protobuf.ExpectEqual(t, durationpb.New(10*time.Second), b.GetStatsFlushInterval())
+
+	want := new(envoy_metrics_v3.StatsConfig)
+	unmarshal(t, `{
+	  "histogram_bucket_settings": [
+	    {
+	      "match": {
+	        "safe_regex": {
+	          "regex": "cluster\\..*\\.upstream_rq_time"
+	        }
+	      },
+	      "buckets": [0.5, 1, 5]
+	    },
+	    {
+	      "match": {
+	        "safe_regex": {
+	          "regex": "http\\..*"
+	        }
+	      },
+	      "buckets": [10]
+	    }
+	  ]
+	}`, want)
+	protobuf.ExpectEqual(t, want, b.GetStatsConfig())
+
+	// Without stats settings, Envoy's defaults are used.
+	c.StatsFlushInterval = 0
+	c.HistogramBuckets = nil
+	b = bootstrapConfig(c)
+	assert.Nil(t, b.GetStatsFlushInterval())
+	assert.Nil(t, b.GetStatsConfig())
+}
+
 func unmarshal(t *testing.T, data string, pb proto.Message) {
 	err := protojson.Unmarshal([]byte(data), pb)
 	checkErr(t, err)
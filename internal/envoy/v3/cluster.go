@@ -26,10 +26,9 @@ import (
 	"github.com/projectcontour/contour/internal/envoy"
 	"github.com/projectcontour/contour/internal/protobuf"
 	"github.com/projectcontour/contour/internal/timeout"
-	"github.com/projectcontour/contour/internal/xds"
 	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/durationpb"
-	"k8s.io/apimachinery/pkg/types"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 func clusterDefaults() *envoy_cluster_v3.Cluster {
@@ -47,6 +46,9 @@ func Cluster(c *dag.Cluster) *envoy_cluster_v3.Cluster {
 
 	cluster.Name = envoy.Clustername(c)
 	cluster.AltStatName = envoy.AltStatName(service)
+	if c.AltStatName != "" {
+		cluster.AltStatName = c.AltStatName
+	}
 	cluster.LbPolicy = lbPolicy(c.LoadBalancerPolicy)
 	cluster.HealthChecks = edshealthcheck(c)
 	cluster.DnsLookupFamily = parseDNSLookupFamily(c.DNSLookupFamily)
@@ -55,7 +57,7 @@ func Cluster(c *dag.Cluster) *envoy_cluster_v3.Cluster {
 	case 0:
 		// external name not set, cluster will be discovered via EDS
 		cluster.ClusterDiscoveryType = ClusterDiscoveryType(envoy_cluster_v3.Cluster_EDS)
-		cluster.EdsClusterConfig = edsconfig("contour", service)
+		cluster.EdsClusterConfig = edsconfig("contour", c)
 	default:
 		// external name set, use hard coded DNS name
 		// external name set to LOGICAL_DNS when user selects the ALL loookup family
@@ -71,16 +73,32 @@ func Cluster(c *dag.Cluster) *envoy_cluster_v3.Cluster {
 	// Drain connections immediately if using healthchecks and the endpoint is known to be removed
 	if c.HTTPHealthCheckPolicy != nil || c.TCPHealthCheckPolicy != nil {
 		cluster.IgnoreHealthOnHostRemoval = true
+
+		// Only send traffic to new hosts once they have been health checked.
+		if c.IgnoreNewHostsUntilFirstHC {
+			cluster.CommonLbConfig.IgnoreNewHostsUntilFirstHc = true
+		}
 	}
 
-	if envoy.AnyPositive(service.MaxConnections, service.MaxPendingRequests, service.MaxRequests, service.MaxRetries) {
+	if envoy.AnyPositive(service.MaxConnections, service.MaxPendingRequests, service.MaxRequests, service.MaxRetries) || c.RetryBudget != nil {
+		thresholds := &envoy_cluster_v3.CircuitBreakers_Thresholds{
+			MaxConnections:     protobuf.UInt32OrNil(service.MaxConnections),
+			MaxPendingRequests: protobuf.UInt32OrNil(service.MaxPendingRequests),
+			MaxRequests:        protobuf.UInt32OrNil(service.MaxRequests),
+			MaxRetries:         protobuf.UInt32OrNil(service.MaxRetries),
+		}
+
+		// Envoy ignores max_retries once a retry budget is set.
+		if rb := c.RetryBudget; rb != nil {
+			thresholds.MaxRetries = nil
+			thresholds.RetryBudget = &envoy_cluster_v3.CircuitBreakers_Thresholds_RetryBudget{
+				BudgetPercent:       &envoy_type.Percent{Value: float64(rb.Percent)},
+				MinRetryConcurrency: protobuf.UInt32OrNil(rb.MinRetryConcurrency),
+			}
+		}
+
 		cluster.CircuitBreakers = &envoy_cluster_v3.CircuitBreakers{
-			Thresholds: []*envoy_cluster_v3.CircuitBreakers_Thresholds{{
-				MaxConnections:     protobuf.UInt32OrNil(service.MaxConnections),
-				MaxPendingRequests: protobuf.UInt32OrNil(service.MaxPendingRequests),
-				MaxRequests:        protobuf.UInt32OrNil(service.MaxRequests),
-				MaxRetries:         protobuf.UInt32OrNil(service.MaxRetries),
-			}},
+			Thresholds: []*envoy_cluster_v3.CircuitBreakers_Thresholds{thresholds},
 		}
 	}
 
@@ -92,6 +110,7 @@ func Cluster(c *dag.Cluster) *envoy_cluster_v3.Cluster {
 				c.UpstreamValidation,
 				c.SNI,
 				c.ClientCertificate,
+				upstreamALPNProtocols(c.Protocol)...,
 			),
 		)
 	case "h2":
@@ -101,7 +120,7 @@ func Cluster(c *dag.Cluster) *envoy_cluster_v3.Cluster {
 				c.UpstreamValidation,
 				c.SNI,
 				c.ClientCertificate,
-				"h2",
+				upstreamALPNProtocols(c.Protocol)...,
 			),
 		)
 	case "h2c":
@@ -112,7 +131,7 @@ func Cluster(c *dag.Cluster) *envoy_cluster_v3.Cluster {
 		cluster.ConnectTimeout = durationpb.New(c.TimeoutPolicy.ConnectTimeout)
 	}
 
-	cluster.TypedExtensionProtocolOptions = protocolOptions(httpVersion, c.TimeoutPolicy.IdleConnectionTimeout)
+	cluster.TypedExtensionProtocolOptions = protocolOptions(httpVersion, c.TimeoutPolicy.IdleConnectionTimeout, service.MaxRequestsPerConnection)
 
 	if c.SlowStartConfig != nil {
 		switch cluster.LbPolicy {
@@ -172,7 +191,7 @@ func ExtensionCluster(ext *dag.ExtensionCluster) *envoy_cluster_v3.Cluster {
 				ext.UpstreamValidation,
 				ext.SNI,
 				ext.ClientCertificate,
-				"h2",
+				upstreamALPNProtocols(ext.Protocol)...,
 			),
 		)
 	case "h2c":
@@ -182,11 +201,25 @@ func ExtensionCluster(ext *dag.ExtensionCluster) *envoy_cluster_v3.Cluster {
 	if ext.ClusterTimeoutPolicy.ConnectTimeout > time.Duration(0) {
 		cluster.ConnectTimeout = durationpb.New(ext.ClusterTimeoutPolicy.ConnectTimeout)
 	}
-	cluster.TypedExtensionProtocolOptions = protocolOptions(http2Version, ext.ClusterTimeoutPolicy.IdleConnectionTimeout)
+	cluster.TypedExtensionProtocolOptions = protocolOptions(http2Version, ext.ClusterTimeoutPolicy.IdleConnectionTimeout, 0)
 
 	return cluster
 }
 
+// upstreamALPNProtocols returns the ALPN protocols to advertise when
+// connecting to a TLS upstream using the given protocol. HTTP/2 over TLS
+// must negotiate "h2", otherwise the upstream may select HTTP/1.1 and the
+// connection fails on the HTTP/2 codec. Plain "tls" upstreams may also be
+// used for TCP proxying, so no application protocol is advertised for them.
+func upstreamALPNProtocols(protocol string) []string {
+	switch protocol {
+	case "h2":
+		return []string{"h2"}
+	default:
+		return nil
+	}
+}
+
 // DNSNameCluster builds a envoy_cluster_v3.Cluster for the given *dag.DNSNameCluster.
 func DNSNameCluster(c *dag.DNSNameCluster) *envoy_cluster_v3.Cluster {
 	cluster := clusterDefaults()
@@ -211,13 +244,10 @@ func DNSNameCluster(c *dag.DNSNameCluster) *envoy_cluster_v3.Cluster {
 	return cluster
 }
 
-func edsconfig(cluster string, service *dag.Service) *envoy_cluster_v3.Cluster_EdsClusterConfig {
+func edsconfig(cluster string, c *dag.Cluster) *envoy_cluster_v3.Cluster_EdsClusterConfig {
 	return &envoy_cluster_v3.Cluster_EdsClusterConfig{
-		EdsConfig: ConfigSource(cluster),
-		ServiceName: xds.ClusterLoadAssignmentName(
-			types.NamespacedName{Name: service.Weighted.ServiceName, Namespace: service.Weighted.ServiceNamespace},
-			service.Weighted.ServicePort.Name,
-		),
+		EdsConfig:   ConfigSource(cluster),
+		ServiceName: c.LoadAssignmentName(),
 	}
 }
 
@@ -250,6 +280,45 @@ func edshealthcheck(c *dag.Cluster) []*envoy_core_v3.HealthCheck {
 	}
 }
 
+// LocalityWeightedLB enables locality weighted load balancing on an EDS
+// cluster so that Envoy honours the locality weights of its endpoints.
+// Envoy does not support locality weighting for the hash based load
+// balancers, so those clusters are left unchanged.
+func LocalityWeightedLB(c *envoy_cluster_v3.Cluster) {
+	if c.GetType() != envoy_cluster_v3.Cluster_EDS || c.LbPolicy == envoy_cluster_v3.Cluster_RING_HASH {
+		return
+	}
+
+	if c.CommonLbConfig == nil {
+		c.CommonLbConfig = ClusterCommonLBConfig()
+	}
+	c.CommonLbConfig.LocalityConfigSpecifier = &envoy_cluster_v3.Cluster_CommonLbConfig_LocalityWeightedLbConfig_{
+		LocalityWeightedLbConfig: &envoy_cluster_v3.Cluster_CommonLbConfig_LocalityWeightedLbConfig{},
+	}
+}
+
+// ZoneAwareLB enables zone aware routing on an EDS cluster so that
+// Envoy prefers the endpoints in its own zone. minClusterSize is the
+// number of endpoints the cluster needs for zone aware routing to be
+// used; 0 keeps Envoy's default. As with locality weighting, the hash
+// based load balancers are left unchanged.
+func ZoneAwareLB(c *envoy_cluster_v3.Cluster, minClusterSize uint32) {
+	if c.GetType() != envoy_cluster_v3.Cluster_EDS || c.LbPolicy == envoy_cluster_v3.Cluster_RING_HASH {
+		return
+	}
+
+	if c.CommonLbConfig == nil {
+		c.CommonLbConfig = ClusterCommonLBConfig()
+	}
+	zoneAware := &envoy_cluster_v3.Cluster_CommonLbConfig_ZoneAwareLbConfig{}
+	if minClusterSize > 0 {
+		zoneAware.MinClusterSize = wrapperspb.UInt64(uint64(minClusterSize))
+	}
+	c.CommonLbConfig.LocalityConfigSpecifier = &envoy_cluster_v3.Cluster_CommonLbConfig_ZoneAwareLbConfig_{
+		ZoneAwareLbConfig: zoneAware,
+	}
+}
+
 // ClusterCommonLBConfig creates a *envoy_cluster_v3.Cluster_CommonLbConfig with HealthyPanicThreshold disabled.
 func ClusterCommonLBConfig() *envoy_cluster_v3.Cluster_CommonLbConfig {
 	return &envoy_cluster_v3.Cluster_CommonLbConfig{
@@ -305,9 +374,9 @@ func parseDNSLookupFamily(value string) envoy_cluster_v3.Cluster_DnsLookupFamily
 	return envoy_cluster_v3.Cluster_AUTO
 }
 
-func protocolOptions(explicitHTTPVersion HTTPVersionType, idleConnectionTimeout timeout.Setting) map[string]*anypb.Any {
+func protocolOptions(explicitHTTPVersion HTTPVersionType, idleConnectionTimeout timeout.Setting, maxRequestsPerConnection uint32) map[string]*anypb.Any {
 	// Keep Envoy defaults by not setting protocol options at all if not necessary.
-	if explicitHTTPVersion == HTTPVersionAuto && idleConnectionTimeout.UseDefault() {
+	if explicitHTTPVersion == HTTPVersionAuto && idleConnectionTimeout.UseDefault() && maxRequestsPerConnection == 0 {
 		return nil
 	}
 
@@ -335,8 +404,13 @@ func protocolOptions(explicitHTTPVersion HTTPVersionType, idleConnectionTimeout
 		}
 	}
 
-	if !idleConnectionTimeout.UseDefault() {
-		options.CommonHttpProtocolOptions = &envoy_core_v3.HttpProtocolOptions{IdleTimeout: durationpb.New(idleConnectionTimeout.Duration())}
+	if !idleConnectionTimeout.UseDefault() || maxRequestsPerConnection > 0 {
+		options.CommonHttpProtocolOptions = &envoy_core_v3.HttpProtocolOptions{
+			MaxRequestsPerConnection: protobuf.UInt32OrNil(maxRequestsPerConnection),
+		}
+		if !idleConnectionTimeout.UseDefault() {
+			options.CommonHttpProtocolOptions.IdleTimeout = durationpb.New(idleConnectionTimeout.Duration())
+		}
 	}
 
 	return map[string]*anypb.Any{
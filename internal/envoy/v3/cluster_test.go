@@ -433,6 +433,119 @@ func TestCluster(t *testing.T) {
 				},
 			},
 		},
+		"projectcontour.io/max-requests-per-connection": {
+			cluster: &dag.Cluster{
+				Upstream: &dag.Service{
+					MaxRequestsPerConnection: 100,
+					Weighted: dag.WeightedService{
+						Weight:           1,
+						ServiceName:      s1.Name,
+						ServiceNamespace: s1.Namespace,
+						ServicePort:      s1.Spec.Ports[0],
+						HealthPort:       s1.Spec.Ports[0],
+					},
+				},
+			},
+			want: &envoy_cluster_v3.Cluster{
+				Name:                 "default/kuard/443/da39a3ee5e",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_cluster_v3.Cluster_EDS),
+				EdsClusterConfig: &envoy_cluster_v3.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				TypedExtensionProtocolOptions: map[string]*anypb.Any{
+					"envoy.extensions.upstreams.http.v3.HttpProtocolOptions": protobuf.MustMarshalAny(
+						&envoy_extensions_upstream_http_v3.HttpProtocolOptions{
+							CommonHttpProtocolOptions: &envoy_core_v3.HttpProtocolOptions{
+								MaxRequestsPerConnection: wrapperspb.UInt32(100),
+							},
+							UpstreamProtocolOptions: &envoy_extensions_upstream_http_v3.HttpProtocolOptions_ExplicitHttpConfig_{
+								ExplicitHttpConfig: &envoy_extensions_upstream_http_v3.HttpProtocolOptions_ExplicitHttpConfig{
+									ProtocolConfig: &envoy_extensions_upstream_http_v3.HttpProtocolOptions_ExplicitHttpConfig_HttpProtocolOptions{},
+								},
+							},
+						},
+					),
+				},
+			},
+		},
+		"projectcontour.io/max-requests-per-connection with idle connection timeout set": {
+			cluster: &dag.Cluster{
+				Upstream: &dag.Service{
+					MaxRequestsPerConnection: 100,
+					Weighted: dag.WeightedService{
+						Weight:           1,
+						ServiceName:      s1.Name,
+						ServiceNamespace: s1.Namespace,
+						ServicePort:      s1.Spec.Ports[0],
+						HealthPort:       s1.Spec.Ports[0],
+					},
+				},
+				TimeoutPolicy: dag.ClusterTimeoutPolicy{IdleConnectionTimeout: timeout.DurationSetting(10 * time.Second)},
+			},
+			want: &envoy_cluster_v3.Cluster{
+				Name:                 "default/kuard/443/357c84df09",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_cluster_v3.Cluster_EDS),
+				EdsClusterConfig: &envoy_cluster_v3.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				TypedExtensionProtocolOptions: map[string]*anypb.Any{
+					"envoy.extensions.upstreams.http.v3.HttpProtocolOptions": protobuf.MustMarshalAny(
+						&envoy_extensions_upstream_http_v3.HttpProtocolOptions{
+							CommonHttpProtocolOptions: &envoy_core_v3.HttpProtocolOptions{
+								IdleTimeout:              durationpb.New(10 * time.Second),
+								MaxRequestsPerConnection: wrapperspb.UInt32(100),
+							},
+							UpstreamProtocolOptions: &envoy_extensions_upstream_http_v3.HttpProtocolOptions_ExplicitHttpConfig_{
+								ExplicitHttpConfig: &envoy_extensions_upstream_http_v3.HttpProtocolOptions_ExplicitHttpConfig{
+									ProtocolConfig: &envoy_extensions_upstream_http_v3.HttpProtocolOptions_ExplicitHttpConfig_HttpProtocolOptions{},
+								},
+							},
+						},
+					),
+				},
+			},
+		},
+		"retry budget replaces projectcontour.io/max-retries": {
+			cluster: &dag.Cluster{
+				Upstream: &dag.Service{
+					MaxRetries:     7,
+					MaxConnections: 9,
+					Weighted: dag.WeightedService{
+						Weight:           1,
+						ServiceName:      s1.Name,
+						ServiceNamespace: s1.Namespace,
+						ServicePort:      s1.Spec.Ports[0],
+						HealthPort:       s1.Spec.Ports[0],
+					},
+				},
+				RetryBudget: &dag.RetryBudget{
+					Percent:             20,
+					MinRetryConcurrency: 5,
+				},
+			},
+			want: &envoy_cluster_v3.Cluster{
+				Name:                 "default/kuard/443/3b3949c444",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_cluster_v3.Cluster_EDS),
+				EdsClusterConfig: &envoy_cluster_v3.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				CircuitBreakers: &envoy_cluster_v3.CircuitBreakers{
+					Thresholds: []*envoy_cluster_v3.CircuitBreakers_Thresholds{{
+						MaxConnections: wrapperspb.UInt32(9),
+						RetryBudget: &envoy_cluster_v3.CircuitBreakers_Thresholds_RetryBudget{
+							BudgetPercent:       &envoy_type.Percent{Value: 20},
+							MinRetryConcurrency: wrapperspb.UInt32(5),
+						},
+					}},
+				},
+			},
+		},
 		"cluster with random load balancer policy": {
 			cluster: &dag.Cluster{
 				Upstream:           service(s1),
@@ -510,6 +623,55 @@ func TestCluster(t *testing.T) {
 				}},
 			},
 		},
+		"tcp service with healthcheck, ignoring new hosts until first healthcheck": {
+			cluster: &dag.Cluster{
+				Upstream: service(s1),
+				TCPHealthCheckPolicy: &dag.TCPHealthCheckPolicy{
+					Timeout:            2,
+					Interval:           10,
+					UnhealthyThreshold: 3,
+					HealthyThreshold:   2,
+				},
+				IgnoreNewHostsUntilFirstHC: true,
+			},
+			want: &envoy_cluster_v3.Cluster{
+				Name:                 "default/kuard/443/120f5a444c",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_cluster_v3.Cluster_EDS),
+				EdsClusterConfig: &envoy_cluster_v3.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				CommonLbConfig: &envoy_cluster_v3.Cluster_CommonLbConfig{
+					IgnoreNewHostsUntilFirstHc: true,
+				},
+				IgnoreHealthOnHostRemoval: true,
+				HealthChecks: []*envoy_core_v3.HealthCheck{{
+					Timeout:            durationOrDefault(2, envoy.HCTimeout),
+					Interval:           durationOrDefault(10, envoy.HCInterval),
+					UnhealthyThreshold: protobuf.UInt32OrDefault(3, envoy.HCUnhealthyThreshold),
+					HealthyThreshold:   protobuf.UInt32OrDefault(2, envoy.HCHealthyThreshold),
+					HealthChecker: &envoy_core_v3.HealthCheck_TcpHealthCheck_{
+						TcpHealthCheck: &envoy_core_v3.HealthCheck_TcpHealthCheck{},
+					},
+				}},
+			},
+		},
+		"alt stat name": {
+			cluster: &dag.Cluster{
+				Upstream:    service(s1),
+				AltStatName: "kuard-stable",
+			},
+			want: &envoy_cluster_v3.Cluster{
+				Name:                 "default/kuard/443/33aeba2ef1",
+				AltStatName:          "kuard-stable",
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_cluster_v3.Cluster_EDS),
+				EdsClusterConfig: &envoy_cluster_v3.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+			},
+		},
 		"use client certificate to authentication towards backend": {
 			cluster: &dag.Cluster{
 				Upstream:          service(s1, "tls"),
@@ -529,6 +691,46 @@ func TestCluster(t *testing.T) {
 				),
 			},
 		},
+		"h2 upstream with upstream validation and client certificate": {
+			cluster: &dag.Cluster{
+				Upstream: service(s1, "h2"),
+				Protocol: "h2",
+				UpstreamValidation: &dag.PeerValidationContext{
+					CACertificate: secret,
+					SubjectName:   "foo.bar.io",
+				},
+				ClientCertificate: clientSecret,
+			},
+			want: &envoy_cluster_v3.Cluster{
+				Name:                 "default/kuard/443/e74247664f",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_cluster_v3.Cluster_EDS),
+				EdsClusterConfig: &envoy_cluster_v3.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				TransportSocket: UpstreamTLSTransportSocket(
+					UpstreamTLSContext(
+						&dag.PeerValidationContext{
+							CACertificate: secret,
+							SubjectName:   "foo.bar.io",
+						},
+						"",
+						clientSecret,
+						"h2"),
+				),
+				TypedExtensionProtocolOptions: map[string]*anypb.Any{
+					"envoy.extensions.upstreams.http.v3.HttpProtocolOptions": protobuf.MustMarshalAny(
+						&envoy_extensions_upstream_http_v3.HttpProtocolOptions{
+							UpstreamProtocolOptions: &envoy_extensions_upstream_http_v3.HttpProtocolOptions_ExplicitHttpConfig_{
+								ExplicitHttpConfig: &envoy_extensions_upstream_http_v3.HttpProtocolOptions_ExplicitHttpConfig{
+									ProtocolConfig: &envoy_extensions_upstream_http_v3.HttpProtocolOptions_ExplicitHttpConfig_Http2ProtocolOptions{},
+								},
+							},
+						}),
+				},
+			},
+		},
 		"cluster with connect timeout set": {
 			cluster: &dag.Cluster{
 				Upstream:      service(s1),
@@ -607,6 +809,21 @@ func TestCluster(t *testing.T) {
 				},
 			},
 		},
+		"isolated connection pool": {
+			cluster: &dag.Cluster{
+				Upstream:          service(s1),
+				ConnectionPoolKey: "www.example.com/slow",
+			},
+			want: &envoy_cluster_v3.Cluster{
+				Name:                 "default/kuard/443/9f2ae9eb8b",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_cluster_v3.Cluster_EDS),
+				EdsClusterConfig: &envoy_cluster_v3.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+			},
+		},
 	}
 
 	for name, tc := range tests {
@@ -940,6 +1157,158 @@ func TestClustername(t *testing.T) {
 		want:    "default/backend/80/50abc1400c",
 	})
 
+	run(t, "isolated connection pool", testcase{
+		cluster: &dag.Cluster{
+			Upstream: &dag.Service{
+				Weighted: dag.WeightedService{
+					Weight:           1,
+					ServiceName:      "backend",
+					ServiceNamespace: "default",
+					ServicePort: v1.ServicePort{
+						Name:       "http",
+						Protocol:   "TCP",
+						Port:       80,
+						TargetPort: intstr.FromInt(6502),
+					},
+				},
+			},
+			ConnectionPoolKey: "www.example.com/slow",
+		},
+		want: "default/backend/80/9f2ae9eb8b",
+	})
+
+	run(t, "isolated connection pool with alt stat name", testcase{
+		cluster: &dag.Cluster{
+			Upstream: &dag.Service{
+				Weighted: dag.WeightedService{
+					Weight:           1,
+					ServiceName:      "backend",
+					ServiceNamespace: "default",
+					ServicePort: v1.ServicePort{
+						Name:       "http",
+						Protocol:   "TCP",
+						Port:       80,
+						TargetPort: intstr.FromInt(6502),
+					},
+				},
+			},
+			ConnectionPoolKey: "www.example.com/slow",
+			AltStatName:       "stats",
+		},
+		want: "default/backend/80/e38601ab60",
+	})
+
+	// The concatenation of the connection pool key and the alt stat
+	// name above must not collide with this connection pool key.
+	run(t, "isolated connection pool with a key ending in the alt stat name", testcase{
+		cluster: &dag.Cluster{
+			Upstream: &dag.Service{
+				Weighted: dag.WeightedService{
+					Weight:           1,
+					ServiceName:      "backend",
+					ServiceNamespace: "default",
+					ServicePort: v1.ServicePort{
+						Name:       "http",
+						Protocol:   "TCP",
+						Port:       80,
+						TargetPort: intstr.FromInt(6502),
+					},
+				},
+			},
+			ConnectionPoolKey: "www.example.com/slowstats",
+		},
+		want: "default/backend/80/344475a87c",
+	})
+}
+
+func TestExtensionCluster(t *testing.T) {
+	tests := map[string]struct {
+		cluster *dag.ExtensionCluster
+		want    *envoy_cluster_v3.Cluster
+	}{
+		"h2 extension": {
+			cluster: &dag.ExtensionCluster{
+				Name:     "extension/projectcontour/ratelimit",
+				Upstream: dag.ServiceCluster{ClusterName: "extension/projectcontour/ratelimit"},
+				Protocol: "h2",
+				SNI:      "ratelimit.projectcontour.io",
+			},
+			want: &envoy_cluster_v3.Cluster{
+				Name:                 "extension/projectcontour/ratelimit",
+				AltStatName:          "extension_projectcontour_ratelimit",
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_cluster_v3.Cluster_EDS),
+				EdsClusterConfig: &envoy_cluster_v3.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "extension/projectcontour/ratelimit",
+				},
+				TransportSocket: UpstreamTLSTransportSocket(
+					UpstreamTLSContext(nil, "ratelimit.projectcontour.io", nil, "h2"),
+				),
+				TypedExtensionProtocolOptions: map[string]*anypb.Any{
+					"envoy.extensions.upstreams.http.v3.HttpProtocolOptions": protobuf.MustMarshalAny(
+						&envoy_extensions_upstream_http_v3.HttpProtocolOptions{
+							UpstreamProtocolOptions: &envoy_extensions_upstream_http_v3.HttpProtocolOptions_ExplicitHttpConfig_{
+								ExplicitHttpConfig: &envoy_extensions_upstream_http_v3.HttpProtocolOptions_ExplicitHttpConfig{
+									ProtocolConfig: &envoy_extensions_upstream_http_v3.HttpProtocolOptions_ExplicitHttpConfig_Http2ProtocolOptions{},
+								},
+							},
+						}),
+				},
+			},
+		},
+		"h2c extension": {
+			cluster: &dag.ExtensionCluster{
+				Name:     "extension/projectcontour/ratelimit",
+				Upstream: dag.ServiceCluster{ClusterName: "extension/projectcontour/ratelimit"},
+				Protocol: "h2c",
+			},
+			want: &envoy_cluster_v3.Cluster{
+				Name:                 "extension/projectcontour/ratelimit",
+				AltStatName:          "extension_projectcontour_ratelimit",
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_cluster_v3.Cluster_EDS),
+				EdsClusterConfig: &envoy_cluster_v3.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "extension/projectcontour/ratelimit",
+				},
+				TypedExtensionProtocolOptions: map[string]*anypb.Any{
+					"envoy.extensions.upstreams.http.v3.HttpProtocolOptions": protobuf.MustMarshalAny(
+						&envoy_extensions_upstream_http_v3.HttpProtocolOptions{
+							UpstreamProtocolOptions: &envoy_extensions_upstream_http_v3.HttpProtocolOptions_ExplicitHttpConfig_{
+								ExplicitHttpConfig: &envoy_extensions_upstream_http_v3.HttpProtocolOptions_ExplicitHttpConfig{
+									ProtocolConfig: &envoy_extensions_upstream_http_v3.HttpProtocolOptions_ExplicitHttpConfig_Http2ProtocolOptions{},
+								},
+							},
+						}),
+				},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := ExtensionCluster(tc.cluster)
+			want := clusterDefaults()
+
+			proto.Merge(want, tc.want)
+
+			protobuf.ExpectEqual(t, want, got)
+		})
+	}
+}
+
+func TestUpstreamALPNProtocols(t *testing.T) {
+	tests := map[string][]string{
+		"h2":  {"h2"},
+		"h2c": nil,
+		"tls": nil,
+		"":    nil,
+	}
+
+	for protocol, want := range tests {
+		t.Run(protocol, func(t *testing.T) {
+			assert.Equal(t, want, upstreamALPNProtocols(protocol))
+		})
+	}
 }
 
 func TestLBPolicy(t *testing.T) {
@@ -976,6 +1345,136 @@ func TestClusterCommonLBConfig(t *testing.T) {
 	assert.Equal(t, want, got)
 }
 
+func TestLocalityWeightedLB(t *testing.T) {
+	tests := map[string]struct {
+		cluster *envoy_cluster_v3.Cluster
+		want    *envoy_cluster_v3.Cluster
+	}{
+		"eds cluster": {
+			cluster: &envoy_cluster_v3.Cluster{
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_cluster_v3.Cluster_EDS),
+				LbPolicy:             envoy_cluster_v3.Cluster_ROUND_ROBIN,
+				CommonLbConfig:       ClusterCommonLBConfig(),
+			},
+			want: &envoy_cluster_v3.Cluster{
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_cluster_v3.Cluster_EDS),
+				LbPolicy:             envoy_cluster_v3.Cluster_ROUND_ROBIN,
+				CommonLbConfig: &envoy_cluster_v3.Cluster_CommonLbConfig{
+					HealthyPanicThreshold: &envoy_type.Percent{
+						Value: 0,
+					},
+					LocalityConfigSpecifier: &envoy_cluster_v3.Cluster_CommonLbConfig_LocalityWeightedLbConfig_{
+						LocalityWeightedLbConfig: &envoy_cluster_v3.Cluster_CommonLbConfig_LocalityWeightedLbConfig{},
+					},
+				},
+			},
+		},
+		"ring hash cluster": {
+			cluster: &envoy_cluster_v3.Cluster{
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_cluster_v3.Cluster_EDS),
+				LbPolicy:             envoy_cluster_v3.Cluster_RING_HASH,
+				CommonLbConfig:       ClusterCommonLBConfig(),
+			},
+			want: &envoy_cluster_v3.Cluster{
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_cluster_v3.Cluster_EDS),
+				LbPolicy:             envoy_cluster_v3.Cluster_RING_HASH,
+				CommonLbConfig:       ClusterCommonLBConfig(),
+			},
+		},
+		"strict dns cluster": {
+			cluster: &envoy_cluster_v3.Cluster{
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_cluster_v3.Cluster_STRICT_DNS),
+			},
+			want: &envoy_cluster_v3.Cluster{
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_cluster_v3.Cluster_STRICT_DNS),
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			LocalityWeightedLB(tc.cluster)
+			protobuf.ExpectEqual(t, tc.want, tc.cluster)
+		})
+	}
+}
+
+func TestZoneAwareLB(t *testing.T) {
+	tests := map[string]struct {
+		cluster        *envoy_cluster_v3.Cluster
+		minClusterSize uint32
+		want           *envoy_cluster_v3.Cluster
+	}{
+		"eds cluster": {
+			cluster: &envoy_cluster_v3.Cluster{
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_cluster_v3.Cluster_EDS),
+				LbPolicy:             envoy_cluster_v3.Cluster_ROUND_ROBIN,
+				CommonLbConfig:       ClusterCommonLBConfig(),
+			},
+			want: &envoy_cluster_v3.Cluster{
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_cluster_v3.Cluster_EDS),
+				LbPolicy:             envoy_cluster_v3.Cluster_ROUND_ROBIN,
+				CommonLbConfig: &envoy_cluster_v3.Cluster_CommonLbConfig{
+					HealthyPanicThreshold: &envoy_type.Percent{
+						Value: 0,
+					},
+					LocalityConfigSpecifier: &envoy_cluster_v3.Cluster_CommonLbConfig_ZoneAwareLbConfig_{
+						ZoneAwareLbConfig: &envoy_cluster_v3.Cluster_CommonLbConfig_ZoneAwareLbConfig{},
+					},
+				},
+			},
+		},
+		"eds cluster with a min cluster size": {
+			cluster: &envoy_cluster_v3.Cluster{
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_cluster_v3.Cluster_EDS),
+				LbPolicy:             envoy_cluster_v3.Cluster_LEAST_REQUEST,
+			},
+			minClusterSize: 2,
+			want: &envoy_cluster_v3.Cluster{
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_cluster_v3.Cluster_EDS),
+				LbPolicy:             envoy_cluster_v3.Cluster_LEAST_REQUEST,
+				CommonLbConfig: &envoy_cluster_v3.Cluster_CommonLbConfig{
+					HealthyPanicThreshold: &envoy_type.Percent{
+						Value: 0,
+					},
+					LocalityConfigSpecifier: &envoy_cluster_v3.Cluster_CommonLbConfig_ZoneAwareLbConfig_{
+						ZoneAwareLbConfig: &envoy_cluster_v3.Cluster_CommonLbConfig_ZoneAwareLbConfig{
+							MinClusterSize: wrapperspb.UInt64(2),
+						},
+					},
+				},
+			},
+		},
+		"ring hash cluster": {
+			cluster: &envoy_cluster_v3.Cluster{
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_cluster_v3.Cluster_EDS),
+				LbPolicy:             envoy_cluster_v3.Cluster_RING_HASH,
+				CommonLbConfig:       ClusterCommonLBConfig(),
+			},
+			want: &envoy_cluster_v3.Cluster{
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_cluster_v3.Cluster_EDS),
+				LbPolicy:             envoy_cluster_v3.Cluster_RING_HASH,
+				CommonLbConfig:       ClusterCommonLBConfig(),
+			},
+		},
+		"strict dns cluster": {
+			cluster: &envoy_cluster_v3.Cluster{
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_cluster_v3.Cluster_STRICT_DNS),
+			},
+			want: &envoy_cluster_v3.Cluster{
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_cluster_v3.Cluster_STRICT_DNS),
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ZoneAwareLB(tc.cluster, tc.minClusterSize)
+			protobuf.ExpectEqual(t, tc.want, tc.cluster)
+		})
+	}
+}
+
 func service(s *v1.Service, protocols ...string) *dag.Service {
 	protocol := ""
 	if len(protocols) > 0 {
@@ -16,6 +16,7 @@ package v3
 import (
 	"errors"
 	"fmt"
+	"math"
 	"sort"
 	"strings"
 	"time"
@@ -24,6 +25,7 @@ import (
 	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	envoy_gzip_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/compression/gzip/compressor/v3"
+	envoy_buffer_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/buffer/v3"
 	envoy_compressor_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/compressor/v3"
 	envoy_cors_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/cors/v3"
 	envoy_config_filter_http_ext_authz_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ext_authz/v3"
@@ -32,6 +34,7 @@ import (
 	envoy_jwt_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/jwt_authn/v3"
 	envoy_config_filter_http_local_ratelimit_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/local_ratelimit/v3"
 	lua "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/lua/v3"
+	envoy_rbac_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/rbac/v3"
 	envoy_extensions_filters_http_router_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/router/v3"
 	envoy_router_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/router/v3"
 	envoy_proxy_protocol_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/listener/proxy_protocol/v3"
@@ -162,9 +165,14 @@ type httpConnectionManagerBuilder struct {
 	codec                         HTTPVersionType // Note the zero value is AUTO, which is the default we want.
 	allowChunkedLength            bool
 	mergeSlashes                  bool
+	setRequestIDInResponse        bool
 	serverHeaderTransformation    http.HttpConnectionManager_ServerHeaderTransformation
 	forwardClientCertificate      *dag.ClientCertificateDetails
 	numTrustedHops                uint32
+	timeoutResponse               *contour_api_v1alpha1.TimeoutResponse
+	http2KeepaliveInterval        time.Duration
+	http2KeepaliveTimeout         time.Duration
+	http2MaxConcurrentStreams     uint32
 }
 
 // RouteConfigName sets the name of the RDS element that contains
@@ -243,6 +251,13 @@ func (b *httpConnectionManagerBuilder) MergeSlashes(enabled bool) *httpConnectio
 	return b
 }
 
+// SetRequestIDInResponse toggles returning the request ID to clients in
+// the X-Request-Id response header.
+func (b *httpConnectionManagerBuilder) SetRequestIDInResponse(enabled bool) *httpConnectionManagerBuilder {
+	b.setRequestIDInResponse = enabled
+	return b
+}
+
 func (b *httpConnectionManagerBuilder) ServerHeaderTransformation(value contour_api_v1alpha1.ServerHeaderTransformationType) *httpConnectionManagerBuilder {
 	switch value {
 	case contour_api_v1alpha1.OverwriteServerHeader:
@@ -265,6 +280,31 @@ func (b *httpConnectionManagerBuilder) NumTrustedHops(num uint32) *httpConnectio
 	return b
 }
 
+// TimeoutResponse sets the local reply returned to the client when
+// an upstream request times out. A nil response keeps Envoy's default.
+func (b *httpConnectionManagerBuilder) TimeoutResponse(response *contour_api_v1alpha1.TimeoutResponse) *httpConnectionManagerBuilder {
+	b.timeoutResponse = response
+	return b
+}
+
+// HTTP2Keepalive configures the manager to send an HTTP/2 PING frame
+// every interval on HTTP/2 connections, closing the connections whose
+// PINGs are not acknowledged within timeout. A zero interval disables
+// HTTP/2 keepalive.
+func (b *httpConnectionManagerBuilder) HTTP2Keepalive(interval, timeout time.Duration) *httpConnectionManagerBuilder {
+	b.http2KeepaliveInterval = interval
+	b.http2KeepaliveTimeout = timeout
+	return b
+}
+
+// HTTP2MaxConcurrentStreams configures the maximum number of concurrent
+// streams on each HTTP/2 connection to the manager. Zero means Envoy's
+// default.
+func (b *httpConnectionManagerBuilder) HTTP2MaxConcurrentStreams(streams uint32) *httpConnectionManagerBuilder {
+	b.http2MaxConcurrentStreams = streams
+	return b
+}
+
 func (b *httpConnectionManagerBuilder) DefaultFilters() *httpConnectionManagerBuilder {
 
 	// Add a default set of ordered http filters.
@@ -467,9 +507,10 @@ func (b *httpConnectionManagerBuilder) Get() *envoy_listener_v3.Filter {
 		},
 
 		// issue #1487 pass through X-Request-Id if provided.
-		PreserveExternalRequestId:  true,
-		MergeSlashes:               b.mergeSlashes,
-		ServerHeaderTransformation: b.serverHeaderTransformation,
+		PreserveExternalRequestId:    true,
+		AlwaysSetRequestIdInResponse: b.setRequestIDInResponse,
+		MergeSlashes:                 b.mergeSlashes,
+		ServerHeaderTransformation:   b.serverHeaderTransformation,
 
 		RequestTimeout:      envoy.Timeout(b.requestTimeout),
 		StreamIdleTimeout:   envoy.Timeout(b.streamIdleTimeout),
@@ -485,10 +526,29 @@ func (b *httpConnectionManagerBuilder) Get() *envoy_listener_v3.Filter {
 		cm.CommonHttpProtocolOptions.MaxConnectionDuration = durationpb.New(b.maxConnectionDuration.Duration())
 	}
 
+	if b.http2KeepaliveInterval > 0 || b.http2MaxConcurrentStreams > 0 {
+		cm.Http2ProtocolOptions = &envoy_core_v3.Http2ProtocolOptions{}
+	}
+
+	if b.http2KeepaliveInterval > 0 {
+		cm.Http2ProtocolOptions.ConnectionKeepalive = &envoy_core_v3.KeepaliveSettings{
+			Interval: durationpb.New(b.http2KeepaliveInterval),
+			Timeout:  durationpb.New(b.http2KeepaliveTimeout),
+		}
+	}
+
+	if b.http2MaxConcurrentStreams > 0 {
+		cm.Http2ProtocolOptions.MaxConcurrentStreams = wrapperspb.UInt32(b.http2MaxConcurrentStreams)
+	}
+
 	if len(b.accessLoggers) > 0 {
 		cm.AccessLog = b.accessLoggers
 	}
 
+	if b.timeoutResponse != nil {
+		cm.LocalReplyConfig = timeoutLocalReplyConfig(b.timeoutResponse)
+	}
+
 	// If there's no explicit metrics prefix, default it to the
 	// route config name.
 	if b.metricsPrefix != "" {
@@ -515,6 +575,52 @@ func (b *httpConnectionManagerBuilder) Get() *envoy_listener_v3.Filter {
 	}
 }
 
+// timeoutLocalReplyConfig returns a local reply configuration that
+// rewrites the response Envoy generates when the upstream request
+// times out, identified by the "UT" response flag.
+func timeoutLocalReplyConfig(response *contour_api_v1alpha1.TimeoutResponse) *http.LocalReplyConfig {
+	mapper := &http.ResponseMapper{
+		Filter: &accesslog.AccessLogFilter{
+			FilterSpecifier: &accesslog.AccessLogFilter_ResponseFlagFilter{
+				ResponseFlagFilter: &accesslog.ResponseFlagFilter{
+					Flags: []string{"UT"},
+				},
+			},
+		},
+	}
+
+	if response.StatusCode != nil {
+		mapper.StatusCode = wrapperspb.UInt32(uint32(*response.StatusCode))
+	}
+
+	if response.Body != "" {
+		mapper.Body = &envoy_core_v3.DataSource{
+			Specifier: &envoy_core_v3.DataSource_InlineString{
+				InlineString: response.Body,
+			},
+		}
+
+		// The content type can only be changed by overriding the
+		// body format, so pass the mapped body through unchanged.
+		if response.ContentType != "" {
+			mapper.BodyFormatOverride = &envoy_core_v3.SubstitutionFormatString{
+				Format: &envoy_core_v3.SubstitutionFormatString_TextFormatSource{
+					TextFormatSource: &envoy_core_v3.DataSource{
+						Specifier: &envoy_core_v3.DataSource_InlineString{
+							InlineString: "%LOCAL_REPLY_BODY%",
+						},
+					},
+				},
+				ContentType: response.ContentType,
+			}
+		}
+	}
+
+	return &http.LocalReplyConfig{
+		Mappers: []*http.ResponseMapper{mapper},
+	}
+}
+
 // HTTPConnectionManager creates a new HTTP Connection Manager filter
 // for the supplied route, access log, and client request timeout.
 func HTTPConnectionManager(routename string, accesslogger []*accesslog.AccessLog, requestTimeout time.Duration) *envoy_listener_v3.Filter {
@@ -757,6 +863,48 @@ func FilterExternalAuthz(externalAuthorization *dag.ExternalAuthorization) *http
 	}
 }
 
+// FilterBuffer returns a `buffer` filter used to enforce per-route
+// request body size limits, or nil if enabled is false.
+//
+// The limit configured here is never applied: route configurations
+// that contain limited routes disable the filter by default (see
+// DisableBufferFilter), and each limited route re-enables it with its
+// own limit. This keeps requests to other routes, such as streaming
+// gRPC calls, from being buffered.
+func FilterBuffer(enabled bool) *http.HttpFilter {
+	if !enabled {
+		return nil
+	}
+
+	return &http.HttpFilter{
+		Name: "envoy.filters.http.buffer",
+		ConfigType: &http.HttpFilter_TypedConfig{
+			TypedConfig: protobuf.MustMarshalAny(&envoy_buffer_v3.Buffer{
+				MaxRequestBytes: wrapperspb.UInt32(math.MaxUint32),
+			}),
+		},
+	}
+}
+
+// FilterRBAC returns an `rbac` filter used to enforce per-route
+// source address conditions, or nil if enabled is false.
+//
+// The filter has no policy of its own and so allows every request;
+// routes with source conditions attach their policy as per-route
+// config.
+func FilterRBAC(enabled bool) *http.HttpFilter {
+	if !enabled {
+		return nil
+	}
+
+	return &http.HttpFilter{
+		Name: "envoy.filters.http.rbac",
+		ConfigType: &http.HttpFilter_TypedConfig{
+			TypedConfig: protobuf.MustMarshalAny(&envoy_rbac_v3.RBAC{}),
+		},
+	}
+}
+
 // FilterJWTAuth returns a `jwt_authn` filter configured with the
 // requested parameters.
 func FilterJWTAuth(jwtProviders []dag.JWTProvider) *http.HttpFilter {
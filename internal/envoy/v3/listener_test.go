@@ -38,6 +38,7 @@ import (
 	"github.com/projectcontour/contour/internal/dag"
 	"github.com/projectcontour/contour/internal/envoy"
 	"github.com/projectcontour/contour/internal/protobuf"
+	"github.com/projectcontour/contour/internal/ref"
 	"github.com/projectcontour/contour/internal/timeout"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -604,9 +605,14 @@ func TestHTTPConnectionManager(t *testing.T) {
 		connectionShutdownGracePeriod timeout.Setting
 		allowChunkedLength            bool
 		mergeSlashes                  bool
+		setRequestIDInResponse        bool
 		serverHeaderTranformation     v1alpha1.ServerHeaderTransformationType
 		forwardClientCertificate      *dag.ClientCertificateDetails
 		xffNumTrustedHops             uint32
+		timeoutResponse               *v1alpha1.TimeoutResponse
+		http2KeepaliveInterval        time.Duration
+		http2KeepaliveTimeout         time.Duration
+		http2MaxConcurrentStreams     uint32
 		want                          *envoy_listener_v3.Filter
 	}{
 		"default": {
@@ -1119,6 +1125,56 @@ func TestHTTPConnectionManager(t *testing.T) {
 				},
 			},
 		},
+		"set request id in response": {
+			routename:              "default/kuard",
+			accesslogger:           FileAccessLogEnvoy("/dev/stdout", "", nil, v1alpha1.LogLevelInfo),
+			setRequestIDInResponse: true,
+			want: &envoy_listener_v3.Filter{
+				Name: wellknown.HTTPConnectionManager,
+				ConfigType: &envoy_listener_v3.Filter_TypedConfig{
+					TypedConfig: protobuf.MustMarshalAny(&http.HttpConnectionManager{
+						StatPrefix: "default/kuard",
+						RouteSpecifier: &http.HttpConnectionManager_Rds{
+							Rds: &http.Rds{
+								RouteConfigName: "default/kuard",
+								ConfigSource: &envoy_core_v3.ConfigSource{
+									ResourceApiVersion: envoy_core_v3.ApiVersion_V3,
+									ConfigSourceSpecifier: &envoy_core_v3.ConfigSource_ApiConfigSource{
+										ApiConfigSource: &envoy_core_v3.ApiConfigSource{
+											ApiType:             envoy_core_v3.ApiConfigSource_GRPC,
+											TransportApiVersion: envoy_core_v3.ApiVersion_V3,
+											GrpcServices: []*envoy_core_v3.GrpcService{{
+												TargetSpecifier: &envoy_core_v3.GrpcService_EnvoyGrpc_{
+													EnvoyGrpc: &envoy_core_v3.GrpcService_EnvoyGrpc{
+														ClusterName: "contour",
+														Authority:   "contour",
+													},
+												},
+											}},
+										},
+									},
+								},
+							},
+						},
+						HttpFilters: defaultHTTPFilters,
+						HttpProtocolOptions: &envoy_core_v3.Http1ProtocolOptions{
+							// Enable support for HTTP/1.0 requests that carry
+							// a Host: header. See #537.
+							AcceptHttp_10: true,
+						},
+						CommonHttpProtocolOptions: &envoy_core_v3.HttpProtocolOptions{},
+						AccessLog:                 FileAccessLogEnvoy("/dev/stdout", "", nil, v1alpha1.LogLevelInfo),
+						UseRemoteAddress:          wrapperspb.Bool(true),
+						NormalizePath:             wrapperspb.Bool(true),
+						StripPortMode: &http.HttpConnectionManager_StripAnyHostPort{
+							StripAnyHostPort: true,
+						},
+						PreserveExternalRequestId:    true,
+						AlwaysSetRequestIdInResponse: true,
+					}),
+				},
+			},
+		},
 		"server header transform set to pass through": {
 			routename:                 "default/kuard",
 			accesslogger:              FileAccessLogEnvoy("/dev/stdout", "", nil, v1alpha1.LogLevelInfo),
@@ -1344,6 +1400,197 @@ func TestHTTPConnectionManager(t *testing.T) {
 				},
 			},
 		},
+		"timeout response": {
+			routename:    "default/kuard",
+			accesslogger: FileAccessLogEnvoy("/dev/stdout", "", nil, v1alpha1.LogLevelInfo),
+			timeoutResponse: &v1alpha1.TimeoutResponse{
+				StatusCode:  ref.To(503),
+				Body:        "<h1>Timed out</h1>",
+				ContentType: "text/html",
+			},
+			want: &envoy_listener_v3.Filter{
+				Name: wellknown.HTTPConnectionManager,
+				ConfigType: &envoy_listener_v3.Filter_TypedConfig{
+					TypedConfig: protobuf.MustMarshalAny(&http.HttpConnectionManager{
+						StatPrefix: "default/kuard",
+						RouteSpecifier: &http.HttpConnectionManager_Rds{
+							Rds: &http.Rds{
+								RouteConfigName: "default/kuard",
+								ConfigSource: &envoy_core_v3.ConfigSource{
+									ResourceApiVersion: envoy_core_v3.ApiVersion_V3,
+									ConfigSourceSpecifier: &envoy_core_v3.ConfigSource_ApiConfigSource{
+										ApiConfigSource: &envoy_core_v3.ApiConfigSource{
+											ApiType:             envoy_core_v3.ApiConfigSource_GRPC,
+											TransportApiVersion: envoy_core_v3.ApiVersion_V3,
+											GrpcServices: []*envoy_core_v3.GrpcService{{
+												TargetSpecifier: &envoy_core_v3.GrpcService_EnvoyGrpc_{
+													EnvoyGrpc: &envoy_core_v3.GrpcService_EnvoyGrpc{
+														ClusterName: "contour",
+														Authority:   "contour",
+													},
+												},
+											}},
+										},
+									},
+								},
+							},
+						},
+						HttpFilters: defaultHTTPFilters,
+						HttpProtocolOptions: &envoy_core_v3.Http1ProtocolOptions{
+							// Enable support for HTTP/1.0 requests that carry
+							// a Host: header. See #537.
+							AcceptHttp_10: true,
+						},
+						CommonHttpProtocolOptions: &envoy_core_v3.HttpProtocolOptions{},
+						AccessLog:                 FileAccessLogEnvoy("/dev/stdout", "", nil, v1alpha1.LogLevelInfo),
+						UseRemoteAddress:          wrapperspb.Bool(true),
+						NormalizePath:             wrapperspb.Bool(true),
+						StripPortMode: &http.HttpConnectionManager_StripAnyHostPort{
+							StripAnyHostPort: true,
+						},
+						PreserveExternalRequestId: true,
+						MergeSlashes:              false,
+						LocalReplyConfig: &http.LocalReplyConfig{
+							Mappers: []*http.ResponseMapper{{
+								Filter: &envoy_accesslog_v3.AccessLogFilter{
+									FilterSpecifier: &envoy_accesslog_v3.AccessLogFilter_ResponseFlagFilter{
+										ResponseFlagFilter: &envoy_accesslog_v3.ResponseFlagFilter{
+											Flags: []string{"UT"},
+										},
+									},
+								},
+								StatusCode: wrapperspb.UInt32(503),
+								Body: &envoy_core_v3.DataSource{
+									Specifier: &envoy_core_v3.DataSource_InlineString{
+										InlineString: "<h1>Timed out</h1>",
+									},
+								},
+								BodyFormatOverride: &envoy_core_v3.SubstitutionFormatString{
+									Format: &envoy_core_v3.SubstitutionFormatString_TextFormatSource{
+										TextFormatSource: &envoy_core_v3.DataSource{
+											Specifier: &envoy_core_v3.DataSource_InlineString{
+												InlineString: "%LOCAL_REPLY_BODY%",
+											},
+										},
+									},
+									ContentType: "text/html",
+								},
+							}},
+						},
+					}),
+				},
+			},
+		},
+		"http2 keepalive": {
+			routename:              "default/kuard",
+			accesslogger:           FileAccessLogEnvoy("/dev/stdout", "", nil, v1alpha1.LogLevelInfo),
+			http2KeepaliveInterval: 30 * time.Second,
+			http2KeepaliveTimeout:  5 * time.Second,
+			want: &envoy_listener_v3.Filter{
+				Name: wellknown.HTTPConnectionManager,
+				ConfigType: &envoy_listener_v3.Filter_TypedConfig{
+					TypedConfig: protobuf.MustMarshalAny(&http.HttpConnectionManager{
+						StatPrefix: "default/kuard",
+						RouteSpecifier: &http.HttpConnectionManager_Rds{
+							Rds: &http.Rds{
+								RouteConfigName: "default/kuard",
+								ConfigSource: &envoy_core_v3.ConfigSource{
+									ResourceApiVersion: envoy_core_v3.ApiVersion_V3,
+									ConfigSourceSpecifier: &envoy_core_v3.ConfigSource_ApiConfigSource{
+										ApiConfigSource: &envoy_core_v3.ApiConfigSource{
+											ApiType:             envoy_core_v3.ApiConfigSource_GRPC,
+											TransportApiVersion: envoy_core_v3.ApiVersion_V3,
+											GrpcServices: []*envoy_core_v3.GrpcService{{
+												TargetSpecifier: &envoy_core_v3.GrpcService_EnvoyGrpc_{
+													EnvoyGrpc: &envoy_core_v3.GrpcService_EnvoyGrpc{
+														ClusterName: "contour",
+														Authority:   "contour",
+													},
+												},
+											}},
+										},
+									},
+								},
+							},
+						},
+						HttpFilters: defaultHTTPFilters,
+						HttpProtocolOptions: &envoy_core_v3.Http1ProtocolOptions{
+							// Enable support for HTTP/1.0 requests that carry
+							// a Host: header. See #537.
+							AcceptHttp_10: true,
+						},
+						Http2ProtocolOptions: &envoy_core_v3.Http2ProtocolOptions{
+							ConnectionKeepalive: &envoy_core_v3.KeepaliveSettings{
+								Interval: durationpb.New(30 * time.Second),
+								Timeout:  durationpb.New(5 * time.Second),
+							},
+						},
+						CommonHttpProtocolOptions: &envoy_core_v3.HttpProtocolOptions{},
+						AccessLog:                 FileAccessLogEnvoy("/dev/stdout", "", nil, v1alpha1.LogLevelInfo),
+						UseRemoteAddress:          wrapperspb.Bool(true),
+						NormalizePath:             wrapperspb.Bool(true),
+						StripPortMode: &http.HttpConnectionManager_StripAnyHostPort{
+							StripAnyHostPort: true,
+						},
+						PreserveExternalRequestId: true,
+						MergeSlashes:              false,
+					}),
+				},
+			},
+		},
+		"http2 max concurrent streams": {
+			routename:                 "default/kuard",
+			accesslogger:              FileAccessLogEnvoy("/dev/stdout", "", nil, v1alpha1.LogLevelInfo),
+			http2MaxConcurrentStreams: 100,
+			want: &envoy_listener_v3.Filter{
+				Name: wellknown.HTTPConnectionManager,
+				ConfigType: &envoy_listener_v3.Filter_TypedConfig{
+					TypedConfig: protobuf.MustMarshalAny(&http.HttpConnectionManager{
+						StatPrefix: "default/kuard",
+						RouteSpecifier: &http.HttpConnectionManager_Rds{
+							Rds: &http.Rds{
+								RouteConfigName: "default/kuard",
+								ConfigSource: &envoy_core_v3.ConfigSource{
+									ResourceApiVersion: envoy_core_v3.ApiVersion_V3,
+									ConfigSourceSpecifier: &envoy_core_v3.ConfigSource_ApiConfigSource{
+										ApiConfigSource: &envoy_core_v3.ApiConfigSource{
+											ApiType:             envoy_core_v3.ApiConfigSource_GRPC,
+											TransportApiVersion: envoy_core_v3.ApiVersion_V3,
+											GrpcServices: []*envoy_core_v3.GrpcService{{
+												TargetSpecifier: &envoy_core_v3.GrpcService_EnvoyGrpc_{
+													EnvoyGrpc: &envoy_core_v3.GrpcService_EnvoyGrpc{
+														ClusterName: "contour",
+														Authority:   "contour",
+													},
+												},
+											}},
+										},
+									},
+								},
+							},
+						},
+						HttpFilters: defaultHTTPFilters,
+						HttpProtocolOptions: &envoy_core_v3.Http1ProtocolOptions{
+							// Enable support for HTTP/1.0 requests that carry
+							// a Host: header. See #537.
+							AcceptHttp_10: true,
+						},
+						Http2ProtocolOptions: &envoy_core_v3.Http2ProtocolOptions{
+							MaxConcurrentStreams: wrapperspb.UInt32(100),
+						},
+						CommonHttpProtocolOptions: &envoy_core_v3.HttpProtocolOptions{},
+						AccessLog:                 FileAccessLogEnvoy("/dev/stdout", "", nil, v1alpha1.LogLevelInfo),
+						UseRemoteAddress:          wrapperspb.Bool(true),
+						NormalizePath:             wrapperspb.Bool(true),
+						StripPortMode: &http.HttpConnectionManager_StripAnyHostPort{
+							StripAnyHostPort: true,
+						},
+						PreserveExternalRequestId: true,
+						MergeSlashes:              false,
+					}),
+				},
+			},
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -1359,9 +1606,13 @@ func TestHTTPConnectionManager(t *testing.T) {
 				ConnectionShutdownGracePeriod(tc.connectionShutdownGracePeriod).
 				AllowChunkedLength(tc.allowChunkedLength).
 				MergeSlashes(tc.mergeSlashes).
+				SetRequestIDInResponse(tc.setRequestIDInResponse).
 				ServerHeaderTransformation(tc.serverHeaderTranformation).
 				NumTrustedHops(tc.xffNumTrustedHops).
 				ForwardClientCertificate(tc.forwardClientCertificate).
+				TimeoutResponse(tc.timeoutResponse).
+				HTTP2Keepalive(tc.http2KeepaliveInterval, tc.http2KeepaliveTimeout).
+				HTTP2MaxConcurrentStreams(tc.http2MaxConcurrentStreams).
 				DefaultFilters().
 				Get()
 
@@ -1370,6 +1621,54 @@ func TestHTTPConnectionManager(t *testing.T) {
 	}
 }
 
+func TestTimeoutLocalReplyConfig(t *testing.T) {
+	upstreamTimeout := &envoy_accesslog_v3.AccessLogFilter{
+		FilterSpecifier: &envoy_accesslog_v3.AccessLogFilter_ResponseFlagFilter{
+			ResponseFlagFilter: &envoy_accesslog_v3.ResponseFlagFilter{
+				Flags: []string{"UT"},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		response *v1alpha1.TimeoutResponse
+		want     *http.LocalReplyConfig
+	}{
+		"status code only": {
+			response: &v1alpha1.TimeoutResponse{
+				StatusCode: ref.To(408),
+			},
+			want: &http.LocalReplyConfig{
+				Mappers: []*http.ResponseMapper{{
+					Filter:     upstreamTimeout,
+					StatusCode: wrapperspb.UInt32(408),
+				}},
+			},
+		},
+		"body keeps the default status code and content type": {
+			response: &v1alpha1.TimeoutResponse{
+				Body: "upstream timed out",
+			},
+			want: &http.LocalReplyConfig{
+				Mappers: []*http.ResponseMapper{{
+					Filter: upstreamTimeout,
+					Body: &envoy_core_v3.DataSource{
+						Specifier: &envoy_core_v3.DataSource_InlineString{
+							InlineString: "upstream timed out",
+						},
+					},
+				}},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			protobuf.ExpectEqual(t, tc.want, timeoutLocalReplyConfig(tc.response))
+		})
+	}
+}
+
 func TestTCPProxy(t *testing.T) {
 	const (
 		statPrefix    = "ingress_https"
@@ -23,14 +23,18 @@ import (
 	"text/template"
 
 	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_config_rbac_v3 "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
 	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_buffer_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/buffer/v3"
 	envoy_cors_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/cors/v3"
 	envoy_config_filter_http_ext_authz_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ext_authz/v3"
 	envoy_jwt_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/jwt_authn/v3"
 	lua "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/lua/v3"
+	envoy_rbac_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/rbac/v3"
 	envoy_internal_redirect_previous_routes_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/internal_redirect/previous_routes/v3"
 	envoy_internal_redirect_safe_cross_scheme_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/internal_redirect/safe_cross_scheme/v3"
 	matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	envoy_type_v3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	"github.com/projectcontour/contour/internal/dag"
 	"github.com/projectcontour/contour/internal/envoy"
 	"github.com/projectcontour/contour/internal/protobuf"
@@ -43,8 +47,17 @@ import (
 // VirtualHostAndRoutes converts a DAG virtual host and routes to an Envoy virtual host.
 func VirtualHostAndRoutes(vh *dag.VirtualHost, dagRoutes []*dag.Route, secure bool) *envoy_route_v3.VirtualHost {
 	var envoyRoutes []*envoy_route_v3.Route
-	for _, route := range dagRoutes {
-		envoyRoutes = append(envoyRoutes, buildRoute(route, vh.Name, secure))
+	if vh.RequireTLS && !secure {
+		// Plaintext requests to a virtual host that requires TLS
+		// are rejected outright rather than routed or redirected.
+		envoyRoutes = append(envoyRoutes, &envoy_route_v3.Route{
+			Match:  RouteMatch(&dag.Route{PathMatchCondition: &dag.PrefixMatchCondition{Prefix: "/"}}),
+			Action: routeDirectResponse(&dag.DirectResponse{StatusCode: http.StatusUpgradeRequired}),
+		})
+	} else {
+		for _, route := range dagRoutes {
+			envoyRoutes = append(envoyRoutes, buildRoute(route, vh.Name, secure))
+		}
 	}
 
 	evh := VirtualHost(vh.Name, envoyRoutes...)
@@ -66,9 +79,54 @@ func VirtualHostAndRoutes(vh *dag.VirtualHost, dagRoutes []*dag.Route, secure bo
 		evh.RateLimits = GlobalRateLimits(vh.RateLimitPolicy.Global.Descriptors)
 	}
 
+	// Browsers ignore Strict-Transport-Security when it is received
+	// over plaintext, so it is only added to secure virtual hosts.
+	// Envoy applies virtual host headers after route headers, so the
+	// header is only added if absent to let a Strict-Transport-Security
+	// header set explicitly by a route, or by the upstream, win.
+	if secure && vh.HSTSPolicy != nil {
+		evh.ResponseHeadersToAdd = []*envoy_core_v3.HeaderValueOption{{
+			Header: &envoy_core_v3.HeaderValue{
+				Key:   "Strict-Transport-Security",
+				Value: hstsHeaderValue(vh.HSTSPolicy),
+			},
+			AppendAction: envoy_core_v3.HeaderValueOption_ADD_IF_ABSENT,
+		}}
+	}
+
+	if vh.UpstreamClusterHeader != "" {
+		evh.ResponseHeadersToAdd = append(evh.ResponseHeadersToAdd, headerValueList(map[string]string{
+			vh.UpstreamClusterHeader: "%UPSTREAM_CLUSTER%",
+		}, false)...)
+	}
+
 	return evh
 }
 
+// setsResponseHeader returns true if the headers policy explicitly
+// sets or adds the named header. Names are compared in canonical form.
+func setsResponseHeader(policy *dag.HeadersPolicy, name string) bool {
+	if policy == nil {
+		return false
+	}
+	_, set := policy.Set[name]
+	_, add := policy.Add[name]
+	return set || add
+}
+
+// hstsHeaderValue formats the Strict-Transport-Security header value
+// for the given policy.
+func hstsHeaderValue(policy *dag.HSTSPolicy) string {
+	value := fmt.Sprintf("max-age=%d", int64(policy.MaxAge.Seconds()))
+	if policy.IncludeSubDomains {
+		value += "; includeSubDomains"
+	}
+	if policy.Preload {
+		value += "; preload"
+	}
+	return value
+}
+
 // buildRoute converts a DAG route to an Envoy route.
 func buildRoute(dagRoute *dag.Route, vhostName string, secure bool) *envoy_route_v3.Route {
 	switch {
@@ -106,6 +164,11 @@ func buildRoute(dagRoute *dag.Route, vhostName string, secure bool) *envoy_route
 			rt.ResponseHeadersToAdd = append(headerValueList(dagRoute.ResponseHeadersPolicy.Set, false), headerValueList(dagRoute.ResponseHeadersPolicy.Add, true)...)
 			rt.ResponseHeadersToRemove = dagRoute.ResponseHeadersPolicy.Remove
 		}
+		if dagRoute.ContentSecurityPolicy != "" && !setsResponseHeader(dagRoute.ResponseHeadersPolicy, "Content-Security-Policy") {
+			rt.ResponseHeadersToAdd = append(rt.ResponseHeadersToAdd, headerValueList(map[string]string{
+				"Content-Security-Policy": dagRoute.ContentSecurityPolicy,
+			}, false)...)
+		}
 		if dagRoute.RateLimitPolicy != nil && dagRoute.RateLimitPolicy.Local != nil {
 			if rt.TypedPerFilterConfig == nil {
 				rt.TypedPerFilterConfig = map[string]*anypb.Any{}
@@ -126,6 +189,20 @@ func buildRoute(dagRoute *dag.Route, vhostName string, secure bool) *envoy_route
 			rt.TypedPerFilterConfig["envoy.filters.http.ext_authz"] = routeAuthzContext(dagRoute.AuthContext)
 		}
 
+		if dagRoute.MaxRequestBodyBytes > 0 {
+			if rt.TypedPerFilterConfig == nil {
+				rt.TypedPerFilterConfig = map[string]*anypb.Any{}
+			}
+			rt.TypedPerFilterConfig["envoy.filters.http.buffer"] = routeBuffer(dagRoute.MaxRequestBodyBytes)
+		}
+
+		if len(dagRoute.SourceFilters) > 0 {
+			if rt.TypedPerFilterConfig == nil {
+				rt.TypedPerFilterConfig = map[string]*anypb.Any{}
+			}
+			rt.TypedPerFilterConfig["envoy.filters.http.rbac"] = routeSourceRBAC(dagRoute.SourceFilters)
+		}
+
 		// If JWT verification is enabled, add per-route filter
 		// config referencing a requirement in the main filter
 		// config.
@@ -167,6 +244,91 @@ func routeAuthzContext(settings map[string]string) *anypb.Any {
 	)
 }
 
+// routeBuffer returns a per-route config that enables the buffer
+// filter with the given request body limit. Envoy responds with a 413
+// as soon as the buffered body exceeds the limit, so streamed uploads
+// are rejected without waiting for the whole body.
+func routeBuffer(maxRequestBytes uint32) *anypb.Any {
+	return protobuf.MustMarshalAny(
+		&envoy_buffer_v3.BufferPerRoute{
+			Override: &envoy_buffer_v3.BufferPerRoute_Buffer{
+				Buffer: &envoy_buffer_v3.Buffer{
+					MaxRequestBytes: wrapperspb.UInt32(maxRequestBytes),
+				},
+			},
+		},
+	)
+}
+
+// routeSourceRBAC returns a per-route config that only allows requests
+// whose client address every one of the supplied source filters allows.
+// The client address is matched with `remote_ip`, which takes the
+// connection manager's trusted X-Forwarded-For hops into account.
+func routeSourceRBAC(filters []dag.SourceFilter) *anypb.Any {
+	var principals []*envoy_config_rbac_v3.Principal
+	for _, filter := range filters {
+		var ids []*envoy_config_rbac_v3.Principal
+		for _, cidr := range filter.CIDRs {
+			prefixLen, _ := cidr.Mask.Size()
+			ids = append(ids, &envoy_config_rbac_v3.Principal{
+				Identifier: &envoy_config_rbac_v3.Principal_RemoteIp{
+					RemoteIp: &envoy_core_v3.CidrRange{
+						AddressPrefix: cidr.IP.String(),
+						PrefixLen:     wrapperspb.UInt32(uint32(prefixLen)),
+					},
+				},
+			})
+		}
+		principals = append(principals, &envoy_config_rbac_v3.Principal{
+			Identifier: &envoy_config_rbac_v3.Principal_OrIds{
+				OrIds: &envoy_config_rbac_v3.Principal_Set{Ids: ids},
+			},
+		})
+	}
+
+	principal := principals[0]
+	if len(principals) > 1 {
+		principal = &envoy_config_rbac_v3.Principal{
+			Identifier: &envoy_config_rbac_v3.Principal_AndIds{
+				AndIds: &envoy_config_rbac_v3.Principal_Set{Ids: principals},
+			},
+		}
+	}
+
+	return protobuf.MustMarshalAny(
+		&envoy_rbac_v3.RBACPerRoute{
+			Rbac: &envoy_rbac_v3.RBAC{
+				Rules: &envoy_config_rbac_v3.RBAC{
+					Action: envoy_config_rbac_v3.RBAC_ALLOW,
+					Policies: map[string]*envoy_config_rbac_v3.Policy{
+						"source": {
+							Permissions: []*envoy_config_rbac_v3.Permission{{
+								Rule: &envoy_config_rbac_v3.Permission_Any{Any: true},
+							}},
+							Principals: []*envoy_config_rbac_v3.Principal{principal},
+						},
+					},
+				},
+			},
+		},
+	)
+}
+
+// DisableBufferFilter disables the buffer filter for every route in
+// the supplied route configuration that does not enable it itself.
+func DisableBufferFilter(rc *envoy_route_v3.RouteConfiguration) {
+	if rc.TypedPerFilterConfig == nil {
+		rc.TypedPerFilterConfig = map[string]*anypb.Any{}
+	}
+	rc.TypedPerFilterConfig["envoy.filters.http.buffer"] = protobuf.MustMarshalAny(
+		&envoy_buffer_v3.BufferPerRoute{
+			Override: &envoy_buffer_v3.BufferPerRoute_Disabled{
+				Disabled: true,
+			},
+		},
+	)
+}
+
 // RouteMatch creates a *envoy_route_v3.RouteMatch for the supplied *dag.Route.
 func RouteMatch(route *dag.Route) *envoy_route_v3.RouteMatch {
 	routeMatch := PathRouteMatch(route.PathMatchCondition)
@@ -297,6 +459,7 @@ func routeRedirect(redirect *dag.Redirect) *envoy_route_v3.Route_Redirect {
 func routeRoute(r *dag.Route) *envoy_route_v3.Route_Route {
 	ra := envoy_route_v3.RouteAction{
 		RetryPolicy:            retryPolicy(r),
+		HedgePolicy:            hedgePolicy(r),
 		Timeout:                envoy.Timeout(r.TimeoutPolicy.ResponseTimeout),
 		IdleTimeout:            envoy.Timeout(r.TimeoutPolicy.IdleStreamTimeout),
 		HashPolicy:             hashPolicy(r.RequestHashPolicies),
@@ -406,9 +569,20 @@ func mirrorPolicy(r *dag.Route) []*envoy_route_v3.RouteAction_RequestMirrorPolic
 		return nil
 	}
 
-	return []*envoy_route_v3.RouteAction_RequestMirrorPolicy{{
+	policy := &envoy_route_v3.RouteAction_RequestMirrorPolicy{
 		Cluster: envoy.Clustername(r.MirrorPolicy.Cluster),
-	}}
+	}
+
+	if r.MirrorPolicy.Percentage > 0 && r.MirrorPolicy.Percentage < 100 {
+		policy.RuntimeFraction = &envoy_core_v3.RuntimeFractionalPercent{
+			DefaultValue: &envoy_type_v3.FractionalPercent{
+				Numerator:   r.MirrorPolicy.Percentage,
+				Denominator: envoy_type_v3.FractionalPercent_HUNDRED,
+			},
+		}
+	}
+
+	return []*envoy_route_v3.RouteAction_RequestMirrorPolicy{policy}
 }
 
 func retryPolicy(r *dag.Route) *envoy_route_v3.RetryPolicy {
@@ -431,6 +605,18 @@ func retryPolicy(r *dag.Route) *envoy_route_v3.RetryPolicy {
 	return rp
 }
 
+// hedgePolicy returns the hedge policy of the route, or nil if the
+// route's retry policy does not hedge requests.
+func hedgePolicy(r *dag.Route) *envoy_route_v3.HedgePolicy {
+	if retryPolicy(r) == nil || !r.RetryPolicy.HedgeOnPerTryTimeout {
+		return nil
+	}
+
+	return &envoy_route_v3.HedgePolicy{
+		HedgeOnPerTryTimeout: true,
+	}
+}
+
 func internalRedirectPolicy(p *dag.InternalRedirectPolicy) *envoy_route_v3.InternalRedirectPolicy {
 	if p == nil {
 		return nil
@@ -23,11 +23,13 @@ import (
 	envoy_internal_redirect_previous_routes_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/internal_redirect/previous_routes/v3"
 	envoy_internal_redirect_safe_cross_scheme_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/internal_redirect/safe_cross_scheme/v3"
 	matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	envoy_type_v3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	"github.com/projectcontour/contour/internal/dag"
 	"github.com/projectcontour/contour/internal/fixture"
 	"github.com/projectcontour/contour/internal/protobuf"
 	"github.com/projectcontour/contour/internal/timeout"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
@@ -292,6 +294,48 @@ func TestRouteRoute(t *testing.T) {
 				},
 			},
 		},
+		"hedge on per try timeout": {
+			route: &dag.Route{
+				RetryPolicy: &dag.RetryPolicy{
+					RetryOn:              "reset",
+					NumRetries:           1,
+					PerTryTimeout:        timeout.DurationSetting(100 * time.Millisecond),
+					HedgeOnPerTryTimeout: true,
+				},
+				Clusters: []*dag.Cluster{c1},
+			},
+			want: &envoy_route_v3.Route_Route{
+				Route: &envoy_route_v3.RouteAction{
+					ClusterSpecifier: &envoy_route_v3.RouteAction_Cluster{
+						Cluster: "default/kuard/8080/da39a3ee5e",
+					},
+					RetryPolicy: &envoy_route_v3.RetryPolicy{
+						RetryOn:       "reset",
+						NumRetries:    wrapperspb.UInt32(1),
+						PerTryTimeout: durationpb.New(100 * time.Millisecond),
+					},
+					HedgePolicy: &envoy_route_v3.HedgePolicy{
+						HedgeOnPerTryTimeout: true,
+					},
+				},
+			},
+		},
+		"hedging without retry-on": {
+			route: &dag.Route{
+				RetryPolicy: &dag.RetryPolicy{
+					PerTryTimeout:        timeout.DurationSetting(100 * time.Millisecond),
+					HedgeOnPerTryTimeout: true, // ignored
+				},
+				Clusters: []*dag.Cluster{c1},
+			},
+			want: &envoy_route_v3.Route_Route{
+				Route: &envoy_route_v3.RouteAction{
+					ClusterSpecifier: &envoy_route_v3.RouteAction_Cluster{
+						Cluster: "default/kuard/8080/da39a3ee5e",
+					},
+				},
+			},
+		},
 		"retriable status codes: 502, 503, 504": {
 			route: &dag.Route{
 				RetryPolicy: &dag.RetryPolicy{
@@ -709,6 +753,50 @@ func TestRouteRoute(t *testing.T) {
 				},
 			},
 		},
+		"mirror with percentage": {
+			route: &dag.Route{
+				Clusters: []*dag.Cluster{{
+					Upstream: &dag.Service{
+						Weighted: dag.WeightedService{
+							Weight:           1,
+							ServiceName:      s1.Name,
+							ServiceNamespace: s1.Namespace,
+							ServicePort:      s1.Spec.Ports[0],
+						},
+					},
+					Weight: 90,
+				}},
+				MirrorPolicy: &dag.MirrorPolicy{
+					Cluster: &dag.Cluster{
+						Upstream: &dag.Service{
+							Weighted: dag.WeightedService{
+								Weight:           1,
+								ServiceName:      s1.Name,
+								ServiceNamespace: s1.Namespace,
+								ServicePort:      s1.Spec.Ports[0],
+							},
+						},
+					},
+					Percentage: 10,
+				},
+			},
+			want: &envoy_route_v3.Route_Route{
+				Route: &envoy_route_v3.RouteAction{
+					ClusterSpecifier: &envoy_route_v3.RouteAction_Cluster{
+						Cluster: "default/kuard/8080/da39a3ee5e",
+					},
+					RequestMirrorPolicies: []*envoy_route_v3.RouteAction_RequestMirrorPolicy{{
+						Cluster: "default/kuard/8080/da39a3ee5e",
+						RuntimeFraction: &envoy_core_v3.RuntimeFractionalPercent{
+							DefaultValue: &envoy_type_v3.FractionalPercent{
+								Numerator:   10,
+								Denominator: envoy_type_v3.FractionalPercent_HUNDRED,
+							},
+						},
+					}},
+				},
+			},
+		},
 		"prefix rewrite": {
 			route: &dag.Route{
 				Clusters:          []*dag.Cluster{c1},
@@ -1189,6 +1277,217 @@ func TestCORSVirtualHost(t *testing.T) {
 	}
 }
 
+func TestVirtualHostAndRoutesHSTS(t *testing.T) {
+	tests := map[string]struct {
+		policy *dag.HSTSPolicy
+		secure bool
+		want   []*envoy_core_v3.HeaderValueOption
+	}{
+		"no policy": {
+			secure: true,
+		},
+		"insecure virtual host": {
+			policy: &dag.HSTSPolicy{MaxAge: time.Hour},
+			secure: false,
+		},
+		"max age only": {
+			policy: &dag.HSTSPolicy{MaxAge: time.Hour},
+			secure: true,
+			want: []*envoy_core_v3.HeaderValueOption{{
+				Header: &envoy_core_v3.HeaderValue{
+					Key:   "Strict-Transport-Security",
+					Value: "max-age=3600",
+				},
+				AppendAction: envoy_core_v3.HeaderValueOption_ADD_IF_ABSENT,
+			}},
+		},
+		"include subdomains and preload": {
+			policy: &dag.HSTSPolicy{
+				MaxAge:            365 * 24 * time.Hour,
+				IncludeSubDomains: true,
+				Preload:           true,
+			},
+			secure: true,
+			want: []*envoy_core_v3.HeaderValueOption{{
+				Header: &envoy_core_v3.HeaderValue{
+					Key:   "Strict-Transport-Security",
+					Value: "max-age=31536000; includeSubDomains; preload",
+				},
+				AppendAction: envoy_core_v3.HeaderValueOption_ADD_IF_ABSENT,
+			}},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			vh := &dag.VirtualHost{
+				Name:       "www.example.com",
+				HSTSPolicy: tc.policy,
+			}
+			got := VirtualHostAndRoutes(vh, nil, tc.secure)
+			protobuf.ExpectEqual(t, &envoy_route_v3.VirtualHost{
+				Name:                 "www.example.com",
+				Domains:              []string{"www.example.com"},
+				ResponseHeadersToAdd: tc.want,
+			}, got)
+		})
+	}
+}
+
+func TestVirtualHostAndRoutesHSTSRouteHeaderPrecedence(t *testing.T) {
+	// A Strict-Transport-Security header set by a route's response
+	// headers policy overwrites any existing header, and the virtual
+	// host's policy is then only added if the header is absent, so the
+	// route's header wins.
+	route := &dag.Route{
+		PathMatchCondition: &dag.PrefixMatchCondition{Prefix: "/"},
+		ResponseHeadersPolicy: &dag.HeadersPolicy{
+			Set: map[string]string{"Strict-Transport-Security": "max-age=0"},
+		},
+	}
+	vh := &dag.VirtualHost{
+		Name:       "www.example.com",
+		HSTSPolicy: &dag.HSTSPolicy{MaxAge: time.Hour},
+	}
+
+	got := VirtualHostAndRoutes(vh, []*dag.Route{route}, true)
+	require.Len(t, got.Routes, 1)
+	protobuf.ExpectEqual(t, []*envoy_core_v3.HeaderValueOption{{
+		Header: &envoy_core_v3.HeaderValue{
+			Key:   "Strict-Transport-Security",
+			Value: "max-age=0",
+		},
+		AppendAction: envoy_core_v3.HeaderValueOption_OVERWRITE_IF_EXISTS_OR_ADD,
+	}}, got.Routes[0].ResponseHeadersToAdd)
+	protobuf.ExpectEqual(t, []*envoy_core_v3.HeaderValueOption{{
+		Header: &envoy_core_v3.HeaderValue{
+			Key:   "Strict-Transport-Security",
+			Value: "max-age=3600",
+		},
+		AppendAction: envoy_core_v3.HeaderValueOption_ADD_IF_ABSENT,
+	}}, got.ResponseHeadersToAdd)
+}
+
+func TestVirtualHostAndRoutesCSP(t *testing.T) {
+	cspHeader := func(value string) *envoy_core_v3.HeaderValueOption {
+		return &envoy_core_v3.HeaderValueOption{
+			Header: &envoy_core_v3.HeaderValue{
+				Key:   "Content-Security-Policy",
+				Value: value,
+			},
+			AppendAction: envoy_core_v3.HeaderValueOption_OVERWRITE_IF_EXISTS_OR_ADD,
+		}
+	}
+
+	tests := map[string]struct {
+		route *dag.Route
+		want  []*envoy_core_v3.HeaderValueOption
+	}{
+		"no policy": {
+			route: &dag.Route{},
+		},
+		"policy": {
+			route: &dag.Route{
+				ContentSecurityPolicy: "default-src 'self'",
+			},
+			want: []*envoy_core_v3.HeaderValueOption{
+				cspHeader("default-src 'self'"),
+			},
+		},
+		"policy with other response headers": {
+			route: &dag.Route{
+				ContentSecurityPolicy: "default-src 'self'",
+				ResponseHeadersPolicy: &dag.HeadersPolicy{
+					Set: map[string]string{"X-Frame-Options": "DENY"},
+				},
+			},
+			want: []*envoy_core_v3.HeaderValueOption{{
+				Header: &envoy_core_v3.HeaderValue{
+					Key:   "X-Frame-Options",
+					Value: "DENY",
+				},
+				AppendAction: envoy_core_v3.HeaderValueOption_OVERWRITE_IF_EXISTS_OR_ADD,
+			},
+				cspHeader("default-src 'self'"),
+			},
+		},
+		"response headers policy takes precedence": {
+			route: &dag.Route{
+				ContentSecurityPolicy: "default-src 'self'",
+				ResponseHeadersPolicy: &dag.HeadersPolicy{
+					Set: map[string]string{"Content-Security-Policy": "default-src 'none'"},
+				},
+			},
+			want: []*envoy_core_v3.HeaderValueOption{
+				cspHeader("default-src 'none'"),
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			tc.route.PathMatchCondition = &dag.PrefixMatchCondition{Prefix: "/"}
+			vh := &dag.VirtualHost{Name: "www.example.com"}
+
+			for _, secure := range []bool{false, true} {
+				got := VirtualHostAndRoutes(vh, []*dag.Route{tc.route}, secure)
+				require.Len(t, got.Routes, 1)
+				protobuf.ExpectEqual(t, tc.want, got.Routes[0].ResponseHeadersToAdd)
+			}
+		})
+	}
+}
+
+func TestVirtualHostAndRoutesUpstreamClusterHeader(t *testing.T) {
+	upstreamClusterHeader := &envoy_core_v3.HeaderValueOption{
+		Header: &envoy_core_v3.HeaderValue{
+			Key:   "x-upstream-cluster",
+			Value: "%UPSTREAM_CLUSTER%",
+		},
+		AppendAction: envoy_core_v3.HeaderValueOption_OVERWRITE_IF_EXISTS_OR_ADD,
+	}
+
+	tests := map[string]struct {
+		vh     *dag.VirtualHost
+		secure bool
+		want   []*envoy_core_v3.HeaderValueOption
+	}{
+		"no header": {
+			vh: &dag.VirtualHost{Name: "www.example.com"},
+		},
+		"insecure virtual host": {
+			vh: &dag.VirtualHost{
+				Name:                  "www.example.com",
+				UpstreamClusterHeader: "x-upstream-cluster",
+			},
+			want: []*envoy_core_v3.HeaderValueOption{upstreamClusterHeader},
+		},
+		"secure virtual host with hsts policy": {
+			vh: &dag.VirtualHost{
+				Name:                  "www.example.com",
+				HSTSPolicy:            &dag.HSTSPolicy{MaxAge: time.Hour},
+				UpstreamClusterHeader: "x-upstream-cluster",
+			},
+			secure: true,
+			want: []*envoy_core_v3.HeaderValueOption{{
+				Header: &envoy_core_v3.HeaderValue{
+					Key:   "Strict-Transport-Security",
+					Value: "max-age=3600",
+				},
+				AppendAction: envoy_core_v3.HeaderValueOption_ADD_IF_ABSENT,
+			}, upstreamClusterHeader},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := VirtualHostAndRoutes(tc.vh, nil, tc.secure)
+			protobuf.ExpectEqual(t, &envoy_route_v3.VirtualHost{
+				Name:                 "www.example.com",
+				Domains:              []string{"www.example.com"},
+				ResponseHeadersToAdd: tc.want,
+			}, got)
+		})
+	}
+}
+
 func TestCORSPolicy(t *testing.T) {
 	tests := map[string]struct {
 		cp   *dag.CORSPolicy
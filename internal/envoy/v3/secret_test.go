@@ -62,6 +62,43 @@ func TestSecret(t *testing.T) {
 				},
 			},
 		},
+		"secret with ocsp staple": {
+			secret: &dag.Secret{
+				Object: &v1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Data: map[string][]byte{
+						v1.TLSCertKey:       []byte("cert"),
+						v1.TLSPrivateKeyKey: []byte("key"),
+						dag.OCSPStapleKey:   []byte("staple"),
+					},
+				},
+			},
+			want: &envoy_tls_v3.Secret{
+				Name: "default/simple/cd1b506996",
+				Type: &envoy_tls_v3.Secret_TlsCertificate{
+					TlsCertificate: &envoy_tls_v3.TlsCertificate{
+						PrivateKey: &envoy_core_v3.DataSource{
+							Specifier: &envoy_core_v3.DataSource_InlineBytes{
+								InlineBytes: []byte("key"),
+							},
+						},
+						CertificateChain: &envoy_core_v3.DataSource{
+							Specifier: &envoy_core_v3.DataSource_InlineBytes{
+								InlineBytes: []byte("cert"),
+							},
+						},
+						OcspStaple: &envoy_core_v3.DataSource{
+							Specifier: &envoy_core_v3.DataSource_InlineBytes{
+								InlineBytes: []byte("staple"),
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for name, tc := range tests {
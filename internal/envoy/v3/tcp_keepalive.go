@@ -18,11 +18,52 @@ import (
 	"github.com/projectcontour/contour/internal/envoy"
 )
 
+// Default TCP keepalive settings.
+//
+// Note: TCP_KEEPIDLE + (TCP_KEEPINTVL * TCP_KEEPCNT) must be greater than
+// the grpc.KeepaliveParams time + timeout (currently 60 + 20 = 80 seconds)
+// otherwise TestGRPC/StreamClusters fails.
+const (
+	defaultTCPKeepaliveIdleTime      = 45
+	defaultTCPKeepaliveProbeInterval = 5
+	defaultTCPKeepaliveProbeCount    = 9
+)
+
+// TCPKeepalive holds the TCP keepalive settings of a listener.
+// Zero values are replaced by the defaults.
+type TCPKeepalive struct {
+	// IdleTime is the time (in seconds) a connection needs to
+	// remain idle before TCP starts sending keepalive probes.
+	IdleTime int64
+
+	// ProbeInterval is the time (in seconds) between individual
+	// keepalive probes.
+	ProbeInterval int64
+
+	// ProbeCount is the maximum number of keepalive probes to
+	// send before the connection is killed.
+	ProbeCount int64
+}
+
+// TCPKeepaliveSocketOptions returns the socket options enabling TCP
+// keepalive with the default settings.
 func TCPKeepaliveSocketOptions() []*envoy_core_v3.SocketOption {
+	return TCPKeepaliveSocketOptionsFor(TCPKeepalive{})
+}
+
+// TCPKeepaliveSocketOptionsFor returns the socket options enabling TCP
+// keepalive with the supplied settings.
+func TCPKeepaliveSocketOptionsFor(k TCPKeepalive) []*envoy_core_v3.SocketOption {
+	if k.IdleTime == 0 {
+		k.IdleTime = defaultTCPKeepaliveIdleTime
+	}
+	if k.ProbeInterval == 0 {
+		k.ProbeInterval = defaultTCPKeepaliveProbeInterval
+	}
+	if k.ProbeCount == 0 {
+		k.ProbeCount = defaultTCPKeepaliveProbeCount
+	}
 
-	// Note: TCP_KEEPIDLE + (TCP_KEEPINTVL * TCP_KEEPCNT) must be greater than
-	// the grpc.KeepaliveParams time + timeout (currently 60 + 20 = 80 seconds)
-	// otherwise TestGRPC/StreamClusters fails.
 	return []*envoy_core_v3.SocketOption{
 		// Enable TCP keep-alive.
 		{
@@ -38,7 +79,7 @@ func TCPKeepaliveSocketOptions() []*envoy_core_v3.SocketOption {
 			Description: "TCP keep-alive initial idle time",
 			Level:       envoy.IPPROTO_TCP,
 			Name:        envoy.TCP_KEEPIDLE,
-			Value:       &envoy_core_v3.SocketOption_IntValue{IntValue: 45},
+			Value:       &envoy_core_v3.SocketOption_IntValue{IntValue: k.IdleTime},
 			State:       envoy_core_v3.SocketOption_STATE_LISTENING,
 		},
 		// The time (in seconds) between individual keepalive probes.
@@ -46,7 +87,7 @@ func TCPKeepaliveSocketOptions() []*envoy_core_v3.SocketOption {
 			Description: "TCP keep-alive time between probes",
 			Level:       envoy.IPPROTO_TCP,
 			Name:        envoy.TCP_KEEPINTVL,
-			Value:       &envoy_core_v3.SocketOption_IntValue{IntValue: 5},
+			Value:       &envoy_core_v3.SocketOption_IntValue{IntValue: k.ProbeInterval},
 			State:       envoy_core_v3.SocketOption_STATE_LISTENING,
 		},
 		// The maximum number of TCP keep-alive probes to send before
@@ -56,7 +97,7 @@ func TCPKeepaliveSocketOptions() []*envoy_core_v3.SocketOption {
 			Description: "TCP keep-alive probe count",
 			Level:       envoy.IPPROTO_TCP,
 			Name:        envoy.TCP_KEEPCNT,
-			Value:       &envoy_core_v3.SocketOption_IntValue{IntValue: 9},
+			Value:       &envoy_core_v3.SocketOption_IntValue{IntValue: k.ProbeCount},
 			State:       envoy_core_v3.SocketOption_STATE_LISTENING,
 		},
 	}
@@ -0,0 +1,37 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3
+
+import (
+	"testing"
+
+	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTCPKeepaliveSocketOptionsFor(t *testing.T) {
+	values := func(opts []*envoy_core_v3.SocketOption) []int64 {
+		var got []int64
+		for _, opt := range opts {
+			got = append(got, opt.GetIntValue())
+		}
+		return got
+	}
+
+	// SO_KEEPALIVE, TCP_KEEPIDLE, TCP_KEEPINTVL, TCP_KEEPCNT.
+	assert.Equal(t, []int64{1, 45, 5, 9}, values(TCPKeepaliveSocketOptions()))
+	assert.Equal(t, []int64{1, 45, 5, 9}, values(TCPKeepaliveSocketOptionsFor(TCPKeepalive{})))
+	assert.Equal(t, []int64{1, 60, 5, 3}, values(TCPKeepaliveSocketOptionsFor(TCPKeepalive{IdleTime: 60, ProbeCount: 3})))
+	assert.Equal(t, []int64{1, 300, 30, 4}, values(TCPKeepaliveSocketOptionsFor(TCPKeepalive{IdleTime: 300, ProbeInterval: 30, ProbeCount: 4})))
+}
@@ -15,6 +15,7 @@ package v3
 
 import (
 	envoy_tls_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/projectcontour/contour/internal/gatewayapi"
 )
 
 func ParseTLSVersion(version string) envoy_tls_v3.TlsParameters_TlsProtocol {
@@ -27,3 +28,16 @@ func ParseTLSVersion(version string) envoy_tls_v3.TlsParameters_TlsProtocol {
 		return envoy_tls_v3.TlsParameters_TLS_AUTO
 	}
 }
+
+// ParseOCSPStaplePolicy returns the Envoy OCSP staple policy for the
+// given policy name, defaulting to lenient stapling.
+func ParseOCSPStaplePolicy(policy string) envoy_tls_v3.DownstreamTlsContext_OcspStaplePolicy {
+	switch policy {
+	case gatewayapi.OCSPStaplePolicyStrict:
+		return envoy_tls_v3.DownstreamTlsContext_STRICT_STAPLING
+	case gatewayapi.OCSPStaplePolicyMustStaple:
+		return envoy_tls_v3.DownstreamTlsContext_MUST_STAPLE
+	default:
+		return envoy_tls_v3.DownstreamTlsContext_LENIENT_STAPLING
+	}
+}
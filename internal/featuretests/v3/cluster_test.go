@@ -17,16 +17,24 @@ import (
 	"testing"
 
 	envoy_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_extensions_upstream_http_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/upstreams/http/v3"
 	envoy_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 	contour_api_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"github.com/projectcontour/contour/internal/dag"
 	envoy_v3 "github.com/projectcontour/contour/internal/envoy/v3"
 	"github.com/projectcontour/contour/internal/featuretests"
 	"github.com/projectcontour/contour/internal/fixture"
+	"github.com/projectcontour/contour/internal/gatewayapi"
+	"github.com/projectcontour/contour/internal/protobuf"
+	"github.com/projectcontour/contour/internal/ref"
+	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 	v1 "k8s.io/api/core/v1"
 	networking_v1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
 // projectcontour/contour#186
@@ -461,6 +469,92 @@ func TestClusterCircuitbreakerAnnotations(t *testing.T) {
 	})
 }
 
+// Test that the max-requests-per-connection annotation of a Service
+// limits the requests of the connections to the clusters of Gateway
+// routes, and that zero leaves them unlimited.
+func TestClusterMaxRequestsPerConnectionAnnotation(t *testing.T) {
+	rh, c, done := setup(t)
+	defer done()
+
+	s1 := fixture.NewService("kuard").
+		Annotate("projectcontour.io/max-requests-per-connection", "10").
+		WithPorts(v1.ServicePort{Port: 8080, TargetPort: intstr.FromString("8080")})
+	rh.OnAdd(s1)
+
+	rh.OnAdd(gc)
+	rh.OnAdd(gateway)
+
+	rh.OnAdd(&gatewayapi_v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "basic",
+			Namespace: "default",
+		},
+		Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+				ParentRefs: []gatewayapi_v1beta1.ParentReference{
+					gatewayapi.GatewayListenerParentRef("projectcontour", "contour", "http", 0),
+				},
+			},
+			Hostnames: []gatewayapi_v1beta1.Hostname{
+				"test.projectcontour.io",
+			},
+			Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+				Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
+				BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
+			}},
+		},
+	})
+
+	c.Request(clusterType).Equals(&envoy_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			DefaultCluster(&envoy_cluster_v3.Cluster{
+				Name:                 "default/kuard/8080/da39a3ee5e",
+				AltStatName:          "default_kuard_8080",
+				ClusterDiscoveryType: envoy_v3.ClusterDiscoveryType(envoy_cluster_v3.Cluster_EDS),
+				EdsClusterConfig: &envoy_cluster_v3.Cluster_EdsClusterConfig{
+					EdsConfig:   envoy_v3.ConfigSource("contour"),
+					ServiceName: "default/kuard",
+				},
+				TypedExtensionProtocolOptions: map[string]*anypb.Any{
+					"envoy.extensions.upstreams.http.v3.HttpProtocolOptions": protobuf.MustMarshalAny(
+						&envoy_extensions_upstream_http_v3.HttpProtocolOptions{
+							CommonHttpProtocolOptions: &envoy_core_v3.HttpProtocolOptions{
+								MaxRequestsPerConnection: wrapperspb.UInt32(10),
+							},
+							UpstreamProtocolOptions: &envoy_extensions_upstream_http_v3.HttpProtocolOptions_ExplicitHttpConfig_{
+								ExplicitHttpConfig: &envoy_extensions_upstream_http_v3.HttpProtocolOptions_ExplicitHttpConfig{
+									ProtocolConfig: &envoy_extensions_upstream_http_v3.HttpProtocolOptions_ExplicitHttpConfig_HttpProtocolOptions{},
+								},
+							},
+						},
+					),
+				},
+			}),
+		),
+		TypeUrl: clusterType,
+	})
+
+	s2 := fixture.NewService("kuard").
+		Annotate("projectcontour.io/max-requests-per-connection", "0").
+		WithPorts(v1.ServicePort{Port: 8080, TargetPort: intstr.FromString("8080")})
+	rh.OnUpdate(s1, s2)
+
+	c.Request(clusterType).Equals(&envoy_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			DefaultCluster(&envoy_cluster_v3.Cluster{
+				Name:                 "default/kuard/8080/da39a3ee5e",
+				AltStatName:          "default_kuard_8080",
+				ClusterDiscoveryType: envoy_v3.ClusterDiscoveryType(envoy_cluster_v3.Cluster_EDS),
+				EdsClusterConfig: &envoy_cluster_v3.Cluster_EdsClusterConfig{
+					EdsConfig:   envoy_v3.ConfigSource("contour"),
+					ServiceName: "default/kuard",
+				},
+			}),
+		),
+		TypeUrl: clusterType,
+	})
+}
+
 // issue 581, different service parameters should generate
 // a single CDS entry if they differ only in weight.
 func TestClusterPerServiceParameters(t *testing.T) {
@@ -579,6 +673,152 @@ func TestClusterLoadBalancerStrategyPerRoute(t *testing.T) {
 	})
 }
 
+// Routes that do not set a load balancer strategy use the
+// configured default, while an explicit strategy overrides it.
+func TestClusterDefaultLoadBalancerPolicy(t *testing.T) {
+	rh, c, done := setup(t, func(b *dag.Builder) {
+		for _, processor := range b.Processors {
+			if httpProxyProcessor, ok := processor.(*dag.HTTPProxyProcessor); ok {
+				httpProxyProcessor.DefaultLoadBalancerPolicy = dag.LoadBalancerPolicyWeightedLeastRequest
+			}
+		}
+	})
+	defer done()
+
+	rh.OnAdd(fixture.NewService("kuard").
+		WithPorts(v1.ServicePort{Port: 80, TargetPort: intstr.FromString("8080")}),
+	)
+
+	rh.OnAdd(&contour_api_v1.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "simple",
+			Namespace: "default",
+		},
+		Spec: contour_api_v1.HTTPProxySpec{
+			VirtualHost: &contour_api_v1.VirtualHost{Fqdn: "www.example.com"},
+			Routes: []contour_api_v1.Route{{
+				Conditions: []contour_api_v1.MatchCondition{{
+					Prefix: "/a",
+				}},
+				Services: []contour_api_v1.Service{{
+					Name: "kuard",
+					Port: 80,
+				}},
+			}, {
+				Conditions: []contour_api_v1.MatchCondition{{
+					Prefix: "/b",
+				}},
+				LoadBalancerPolicy: &contour_api_v1.LoadBalancerPolicy{
+					Strategy: "RoundRobin",
+				},
+				Services: []contour_api_v1.Service{{
+					Name: "kuard",
+					Port: 80,
+				}},
+			}},
+		},
+	})
+
+	c.Request(clusterType).Equals(&envoy_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			DefaultCluster(&envoy_cluster_v3.Cluster{
+				Name:                 "default/kuard/80/8bf87fefba",
+				AltStatName:          "default_kuard_80",
+				ClusterDiscoveryType: envoy_v3.ClusterDiscoveryType(envoy_cluster_v3.Cluster_EDS),
+				EdsClusterConfig: &envoy_cluster_v3.Cluster_EdsClusterConfig{
+					EdsConfig:   envoy_v3.ConfigSource("contour"),
+					ServiceName: "default/kuard",
+				},
+				LbPolicy: envoy_cluster_v3.Cluster_LEAST_REQUEST,
+			}),
+			DefaultCluster(&envoy_cluster_v3.Cluster{
+				Name:                 "default/kuard/80/f3b72af6a9",
+				AltStatName:          "default_kuard_80",
+				ClusterDiscoveryType: envoy_v3.ClusterDiscoveryType(envoy_cluster_v3.Cluster_EDS),
+				EdsClusterConfig: &envoy_cluster_v3.Cluster_EdsClusterConfig{
+					EdsConfig:   envoy_v3.ConfigSource("contour"),
+					ServiceName: "default/kuard",
+				},
+			}),
+		),
+		TypeUrl: clusterType,
+	})
+}
+
+func TestHTTPRouteClusterDefaultLoadBalancerPolicy(t *testing.T) {
+	rh, c, done := setup(t, func(b *dag.Builder) {
+		for _, processor := range b.Processors {
+			if gatewayAPIProcessor, ok := processor.(*dag.GatewayAPIProcessor); ok {
+				gatewayAPIProcessor.DefaultLoadBalancerPolicy = dag.LoadBalancerPolicyRandom
+			}
+		}
+	})
+	defer done()
+
+	rh.OnAdd(fixture.NewService("kuard").
+		WithPorts(v1.ServicePort{Port: 80, TargetPort: intstr.FromString("8080")}),
+	)
+
+	rh.OnAdd(gc)
+
+	rh.OnAdd(&gatewayapi_v1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "contour",
+			Namespace: "projectcontour",
+		},
+		Spec: gatewayapi_v1beta1.GatewaySpec{
+			GatewayClassName: gatewayapi_v1beta1.ObjectName(gc.Name),
+			Listeners: []gatewayapi_v1beta1.Listener{{
+				Name:     "http",
+				Port:     80,
+				Protocol: gatewayapi_v1beta1.HTTPProtocolType,
+				AllowedRoutes: &gatewayapi_v1beta1.AllowedRoutes{
+					Namespaces: &gatewayapi_v1beta1.RouteNamespaces{
+						From: ref.To(gatewayapi_v1beta1.NamespacesFromAll),
+					},
+				},
+			}},
+		},
+	})
+
+	rh.OnAdd(&gatewayapi_v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "basic",
+			Namespace: "default",
+		},
+		Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+				ParentRefs: []gatewayapi_v1beta1.ParentReference{
+					gatewayapi.GatewayParentRef("projectcontour", "contour"),
+				},
+			},
+			Hostnames: []gatewayapi_v1beta1.Hostname{
+				"test.projectcontour.io",
+			},
+			Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+				Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
+				BackendRefs: gatewayapi.HTTPBackendRef("kuard", 80, 1),
+			}},
+		},
+	})
+
+	c.Request(clusterType).Equals(&envoy_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			DefaultCluster(&envoy_cluster_v3.Cluster{
+				Name:                 "default/kuard/80/58d888c08a",
+				AltStatName:          "default_kuard_80",
+				ClusterDiscoveryType: envoy_v3.ClusterDiscoveryType(envoy_cluster_v3.Cluster_EDS),
+				EdsClusterConfig: &envoy_cluster_v3.Cluster_EdsClusterConfig{
+					EdsConfig:   envoy_v3.ConfigSource("contour"),
+					ServiceName: "default/kuard",
+				},
+				LbPolicy: envoy_cluster_v3.Cluster_RANDOM,
+			}),
+		),
+		TypeUrl: clusterType,
+	})
+}
+
 func TestClusterWithHealthChecks(t *testing.T) {
 	rh, c, done := setup(t)
 	defer done()
@@ -618,6 +858,43 @@ func TestClusterWithHealthChecks(t *testing.T) {
 	})
 }
 
+func TestClusterIgnoreNewHostsUntilFirstHC(t *testing.T) {
+	rh, c, done := setup(t)
+	defer done()
+
+	rh.OnAdd(fixture.NewService("kuard").
+		WithPorts(v1.ServicePort{Port: 80, TargetPort: intstr.FromString("8080")}),
+	)
+
+	rh.OnAdd(&contour_api_v1.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "simple",
+			Namespace: "default",
+		},
+		Spec: contour_api_v1.HTTPProxySpec{
+			VirtualHost: &contour_api_v1.VirtualHost{Fqdn: "www.example.com"},
+			Routes: []contour_api_v1.Route{{
+				HealthCheckPolicy: &contour_api_v1.HTTPHealthCheckPolicy{
+					Path: "/healthz",
+				},
+				Services: []contour_api_v1.Service{{
+					Name:                       "kuard",
+					Port:                       80,
+					IgnoreNewHostsUntilFirstHC: true,
+				}},
+			}},
+		},
+	})
+
+	want := clusterWithHealthCheck("default/kuard/80/314337de74", "default/kuard", "default_kuard_80", "/healthz", true)
+	want.CommonLbConfig.IgnoreNewHostsUntilFirstHc = true
+
+	c.Request(clusterType).Equals(&envoy_discovery_v3.DiscoveryResponse{
+		Resources: resources(t, want),
+		TypeUrl:   clusterType,
+	})
+}
+
 // Test processing a service that exists but is not referenced
 func TestUnreferencedService(t *testing.T) {
 	rh, c, done := setup(t)
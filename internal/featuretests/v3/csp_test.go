@@ -0,0 +1,168 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3
+
+import (
+	"testing"
+
+	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	contour_api_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"github.com/projectcontour/contour/internal/dag"
+	envoy_v3 "github.com/projectcontour/contour/internal/envoy/v3"
+	"github.com/projectcontour/contour/internal/fixture"
+	v1 "k8s.io/api/core/v1"
+)
+
+func cspHeader(value string) []*envoy_core_v3.HeaderValueOption {
+	return []*envoy_core_v3.HeaderValueOption{{
+		Header: &envoy_core_v3.HeaderValue{
+			Key:   "Content-Security-Policy",
+			Value: value,
+		},
+		AppendAction: envoy_core_v3.HeaderValueOption_OVERWRITE_IF_EXISTS_OR_ADD,
+	}}
+}
+
+func TestHTTPProxyCSPPolicy(t *testing.T) {
+	rh, c, done := setup(t, func(b *dag.Builder) {
+		for _, processor := range b.Processors {
+			if httpProxyProcessor, ok := processor.(*dag.HTTPProxyProcessor); ok {
+				httpProxyProcessor.CSPPolicy = &contour_api_v1.CSPPolicy{
+					Directives: "default-src 'self'",
+				}
+			}
+		}
+	})
+	defer done()
+
+	rh.OnAdd(fixture.NewService("backend").
+		WithPorts(v1.ServicePort{Name: "http", Port: 80}))
+
+	// The global policy applies to routes that set nothing.
+	p1 := fixture.NewProxy("simple").WithSpec(
+		contour_api_v1.HTTPProxySpec{
+			VirtualHost: &contour_api_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []contour_api_v1.Route{{
+				Services: []contour_api_v1.Service{{
+					Name: "backend",
+					Port: 80,
+				}},
+			}},
+		})
+	rh.OnAdd(p1)
+
+	c.Request(routeType).Equals(&envoy_discovery_v3.DiscoveryResponse{
+		Resources: routeResources(t,
+			envoy_v3.RouteConfiguration("ingress_http",
+				envoy_v3.VirtualHost("example.com",
+					&envoy_route_v3.Route{
+						Match:                routePrefix("/"),
+						Action:               routecluster("default/backend/80/da39a3ee5e"),
+						ResponseHeadersToAdd: cspHeader("default-src 'self'"),
+					},
+				),
+			),
+		),
+		TypeUrl: routeType,
+	})
+
+	// The virtual host policy overrides the global one, and a
+	// route policy overrides both.
+	p2 := fixture.NewProxy("simple").WithSpec(
+		contour_api_v1.HTTPProxySpec{
+			VirtualHost: &contour_api_v1.VirtualHost{
+				Fqdn: "example.com",
+				CSPPolicy: &contour_api_v1.CSPPolicy{
+					Directives: "default-src 'none'",
+				},
+			},
+			Routes: []contour_api_v1.Route{{
+				Conditions: matchconditions(prefixMatchCondition("/images")),
+				CSPPolicy: &contour_api_v1.CSPPolicy{
+					Directives: "img-src *",
+				},
+				Services: []contour_api_v1.Service{{
+					Name: "backend",
+					Port: 80,
+				}},
+			}, {
+				Services: []contour_api_v1.Service{{
+					Name: "backend",
+					Port: 80,
+				}},
+			}},
+		})
+	rh.OnUpdate(p1, p2)
+
+	c.Request(routeType).Equals(&envoy_discovery_v3.DiscoveryResponse{
+		Resources: routeResources(t,
+			envoy_v3.RouteConfiguration("ingress_http",
+				envoy_v3.VirtualHost("example.com",
+					&envoy_route_v3.Route{
+						Match:                routePrefix("/images"),
+						Action:               routecluster("default/backend/80/da39a3ee5e"),
+						ResponseHeadersToAdd: cspHeader("img-src *"),
+					},
+					&envoy_route_v3.Route{
+						Match:                routePrefix("/"),
+						Action:               routecluster("default/backend/80/da39a3ee5e"),
+						ResponseHeadersToAdd: cspHeader("default-src 'none'"),
+					},
+				),
+			),
+		),
+		TypeUrl: routeType,
+	})
+
+	// A Content-Security-Policy header set by the route's response
+	// headers policy wins over the CSP policies.
+	p3 := fixture.NewProxy("simple").WithSpec(
+		contour_api_v1.HTTPProxySpec{
+			VirtualHost: &contour_api_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []contour_api_v1.Route{{
+				ResponseHeadersPolicy: &contour_api_v1.HeadersPolicy{
+					Set: []contour_api_v1.HeaderValue{{
+						Name:  "content-security-policy",
+						Value: "script-src 'self'",
+					}},
+				},
+				Services: []contour_api_v1.Service{{
+					Name: "backend",
+					Port: 80,
+				}},
+			}},
+		})
+	rh.OnUpdate(p2, p3)
+
+	c.Request(routeType).Equals(&envoy_discovery_v3.DiscoveryResponse{
+		Resources: routeResources(t,
+			envoy_v3.RouteConfiguration("ingress_http",
+				envoy_v3.VirtualHost("example.com",
+					&envoy_route_v3.Route{
+						Match:                routePrefix("/"),
+						Action:               routecluster("default/backend/80/da39a3ee5e"),
+						ResponseHeadersToAdd: cspHeader("script-src 'self'"),
+					},
+				),
+			),
+		),
+		TypeUrl: routeType,
+	})
+}
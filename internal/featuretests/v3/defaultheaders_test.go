@@ -0,0 +1,172 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3
+
+import (
+	"testing"
+
+	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/projectcontour/contour/internal/dag"
+	envoy_v3 "github.com/projectcontour/contour/internal/envoy/v3"
+	"github.com/projectcontour/contour/internal/fixture"
+	"github.com/projectcontour/contour/internal/gatewayapi"
+	"github.com/projectcontour/contour/internal/ref"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func TestGatewayAPIDefaultResponseHeaders(t *testing.T) {
+	rh, c, done := setup(t, func(b *dag.Builder) {
+		for _, processor := range b.Processors {
+			if gatewayAPIProcessor, ok := processor.(*dag.GatewayAPIProcessor); ok {
+				gatewayAPIProcessor.ResponseHeadersPolicy = &dag.HeadersPolicy{
+					Set: map[string]string{
+						"Strict-Transport-Security": "max-age=31536000",
+					},
+					Add: map[string]string{
+						"X-Content-Type-Options": "nosniff",
+					},
+					Remove: []string{"Server"},
+				}
+			}
+		}
+	})
+	defer done()
+
+	rh.OnAdd(fixture.NewService("svc1").
+		WithPorts(v1.ServicePort{Port: 80, TargetPort: intstr.FromInt(8080)}),
+	)
+
+	rh.OnAdd(gc)
+
+	rh.OnAdd(&gatewayapi_v1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "contour",
+			Namespace: "projectcontour",
+		},
+		Spec: gatewayapi_v1beta1.GatewaySpec{
+			GatewayClassName: gatewayapi_v1beta1.ObjectName(gc.Name),
+			Listeners: []gatewayapi_v1beta1.Listener{{
+				Name:     "http",
+				Port:     80,
+				Protocol: gatewayapi_v1beta1.HTTPProtocolType,
+				AllowedRoutes: &gatewayapi_v1beta1.AllowedRoutes{
+					Namespaces: &gatewayapi_v1beta1.RouteNamespaces{
+						From: ref.To(gatewayapi_v1beta1.NamespacesFromAll),
+					},
+				},
+			}},
+		},
+	})
+
+	route := &gatewayapi_v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "basic",
+			Namespace:       "default",
+			ResourceVersion: "1",
+			Generation:      1,
+		},
+		Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+				ParentRefs: []gatewayapi_v1beta1.ParentReference{
+					gatewayapi.GatewayParentRef("projectcontour", "contour"),
+				},
+			},
+			Hostnames: []gatewayapi_v1beta1.Hostname{
+				"test.projectcontour.io",
+			},
+			Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+				Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
+				BackendRefs: gatewayapi.HTTPBackendRef("svc1", 80, 1),
+			}},
+		},
+	}
+	rh.OnAdd(route)
+
+	c.Request(routeType).Equals(&envoy_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_http",
+				envoy_v3.VirtualHost("test.projectcontour.io",
+					&envoy_route_v3.Route{
+						Match:  routePrefix("/"),
+						Action: routeCluster("default/svc1/80/da39a3ee5e"),
+						ResponseHeadersToAdd: []*envoy_core_v3.HeaderValueOption{{
+							Header: &envoy_core_v3.HeaderValue{
+								Key:   "Strict-Transport-Security",
+								Value: "max-age=31536000",
+							},
+							AppendAction: envoy_core_v3.HeaderValueOption_OVERWRITE_IF_EXISTS_OR_ADD,
+						}, {
+							Header: &envoy_core_v3.HeaderValue{
+								Key:   "X-Content-Type-Options",
+								Value: "nosniff",
+							},
+							AppendAction: envoy_core_v3.HeaderValueOption_APPEND_IF_EXISTS_OR_ADD,
+						}},
+						ResponseHeadersToRemove: []string{"Server"},
+					},
+				),
+			),
+		),
+		TypeUrl: routeType,
+	})
+
+	// A ResponseHeaderModifier filter on the route takes
+	// precedence over the default for the same header.
+	routeWithFilter := route.DeepCopy()
+	routeWithFilter.ResourceVersion = "2"
+	routeWithFilter.Generation = 2
+	routeWithFilter.Spec.Rules[0].Filters = []gatewayapi_v1beta1.HTTPRouteFilter{{
+		Type: gatewayapi_v1beta1.HTTPRouteFilterResponseHeaderModifier,
+		ResponseHeaderModifier: &gatewayapi_v1beta1.HTTPHeaderFilter{
+			Set: []gatewayapi_v1beta1.HTTPHeader{{
+				Name:  "Strict-Transport-Security",
+				Value: "max-age=60",
+			}},
+		},
+	}}
+	rh.OnUpdate(route, routeWithFilter)
+
+	c.Request(routeType).Equals(&envoy_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_http",
+				envoy_v3.VirtualHost("test.projectcontour.io",
+					&envoy_route_v3.Route{
+						Match:  routePrefix("/"),
+						Action: routeCluster("default/svc1/80/da39a3ee5e"),
+						ResponseHeadersToAdd: []*envoy_core_v3.HeaderValueOption{{
+							Header: &envoy_core_v3.HeaderValue{
+								Key:   "Strict-Transport-Security",
+								Value: "max-age=60",
+							},
+							AppendAction: envoy_core_v3.HeaderValueOption_OVERWRITE_IF_EXISTS_OR_ADD,
+						}, {
+							Header: &envoy_core_v3.HeaderValue{
+								Key:   "X-Content-Type-Options",
+								Value: "nosniff",
+							},
+							AppendAction: envoy_core_v3.HeaderValueOption_APPEND_IF_EXISTS_OR_ADD,
+						}},
+						ResponseHeadersToRemove: []string{"Server"},
+					},
+				),
+			),
+		),
+		TypeUrl: routeType,
+	})
+}
@@ -421,13 +421,20 @@ func filterchaintls(domain string, secret *v1.Secret, filter *envoy_listener_v3.
 
 // filterchaintlsfallback returns a FilterChain for the given TLS fallback certificate.
 func filterchaintlsfallback(fallbackSecret *v1.Secret, peerValidationContext *dag.PeerValidationContext, alpn ...string) *envoy_listener_v3.FilterChain {
-	return envoy_v3.FilterChainTLSFallback(
+	return filterchaintlsfallbackcontext(
 		envoy_v3.DownstreamTLSContext(
 			&dag.Secret{Object: fallbackSecret},
 			envoy_tls_v3.TlsParameters_TLSv1_2,
 			nil,
 			peerValidationContext,
 			alpn...),
+	)
+}
+
+// filterchaintlsfallbackcontext returns a TLS fallback FilterChain for the given TLS context.
+func filterchaintlsfallbackcontext(tlsContext *envoy_tls_v3.DownstreamTlsContext) *envoy_listener_v3.FilterChain {
+	return envoy_v3.FilterChainTLSFallback(
+		tlsContext,
 		envoy_v3.Filters(
 			envoy_v3.HTTPConnectionManagerBuilder().
 				DefaultFilters().
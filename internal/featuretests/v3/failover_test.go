@@ -0,0 +1,101 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3
+
+import (
+	"testing"
+
+	envoy_endpoint_v3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	contour_api_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+	envoy_v3 "github.com/projectcontour/contour/internal/envoy/v3"
+	"github.com/projectcontour/contour/internal/featuretests"
+	"github.com/projectcontour/contour/internal/fixture"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestHTTPProxyServicePriorityFailover(t *testing.T) {
+	rh, c, done := setup(t)
+	defer done()
+
+	rh.OnAdd(fixture.NewService("primary").
+		WithPorts(v1.ServicePort{Port: 80, TargetPort: intstr.FromInt(8080)}))
+	rh.OnAdd(fixture.NewService("secondary").
+		WithPorts(v1.ServicePort{Port: 80, TargetPort: intstr.FromInt(8080)}))
+
+	rh.OnAdd(fixture.NewProxy("simple").
+		WithFQDN("www.example.com").
+		WithSpec(contour_api_v1.HTTPProxySpec{
+			Routes: []contour_api_v1.Route{{
+				Services: []contour_api_v1.Service{{
+					Name: "primary",
+					Port: 80,
+				}, {
+					Name:     "secondary",
+					Port:     80,
+					Priority: 1,
+				}},
+			}},
+		}),
+	)
+
+	// Only the primary service gets a cluster, and its endpoints
+	// come from an assignment that includes the secondary service.
+	c.Request(clusterType).Equals(&envoy_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			cluster("default/primary/80/721f9c0f44", "default/primary,default/secondary/1", "default_primary_80"),
+		),
+		TypeUrl: clusterType,
+	})
+
+	c.Request(routeType).Equals(&envoy_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_http",
+				envoy_v3.VirtualHost("www.example.com",
+					&envoy_route_v3.Route{
+						Match:  routePrefix("/"),
+						Action: routeCluster("default/primary/80/721f9c0f44"),
+					},
+				),
+			),
+		),
+		TypeUrl: routeType,
+	})
+
+	rh.OnAdd(featuretests.Endpoints("default", "primary", v1.EndpointSubset{
+		Addresses: featuretests.Addresses("172.16.0.1"),
+		Ports:     featuretests.Ports(featuretests.Port("", 8080)),
+	}))
+	rh.OnAdd(featuretests.Endpoints("default", "secondary", v1.EndpointSubset{
+		Addresses: featuretests.Addresses("172.16.0.2"),
+		Ports:     featuretests.Ports(featuretests.Port("", 8080)),
+	}))
+
+	primary := envoy_v3.WeightedEndpoints(1, envoy_v3.SocketAddress("172.16.0.1", 8080))
+	secondary := envoy_v3.WeightedEndpoints(1, envoy_v3.SocketAddress("172.16.0.2", 8080))
+	secondary[0].Priority = 1
+
+	// The secondary endpoints form the failover priority level.
+	c.Request(endpointType).Equals(&envoy_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			&envoy_endpoint_v3.ClusterLoadAssignment{
+				ClusterName: "default/primary,default/secondary/1",
+				Endpoints:   []*envoy_endpoint_v3.LocalityLbEndpoints{primary[0], secondary[0]},
+			},
+		),
+		TypeUrl: endpointType,
+	})
+}
@@ -55,6 +55,7 @@ import (
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 	v1 "k8s.io/api/core/v1"
+	discovery_v1 "k8s.io/api/discovery/v1"
 	"k8s.io/client-go/tools/cache"
 )
 
@@ -220,7 +221,7 @@ func (r *resourceEventHandler) OnAdd(obj interface{}) {
 	}
 
 	switch obj.(type) {
-	case *v1.Endpoints:
+	case *v1.Endpoints, *discovery_v1.EndpointSlice:
 		r.EndpointsHandler.OnAdd(obj)
 	default:
 		r.EventHandler.OnAdd(obj)
@@ -242,7 +243,7 @@ func (r *resourceEventHandler) OnUpdate(oldObj, newObj interface{}) {
 	}
 
 	switch newObj.(type) {
-	case *v1.Endpoints:
+	case *v1.Endpoints, *discovery_v1.EndpointSlice:
 		r.EndpointsHandler.OnUpdate(oldObj, newObj)
 	default:
 		r.EventHandler.OnUpdate(oldObj, newObj)
@@ -261,7 +262,7 @@ func (r *resourceEventHandler) OnDelete(obj interface{}) {
 	}
 
 	switch obj.(type) {
-	case *v1.Endpoints:
+	case *v1.Endpoints, *discovery_v1.EndpointSlice:
 		r.EndpointsHandler.OnDelete(obj)
 	default:
 		r.EventHandler.OnDelete(obj)
@@ -0,0 +1,125 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3
+
+import (
+	"testing"
+
+	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	envoy_v3 "github.com/projectcontour/contour/internal/envoy/v3"
+	"github.com/projectcontour/contour/internal/fixture"
+	"github.com/projectcontour/contour/internal/gatewayapi"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	gatewayapi_v1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func TestGRPCRoute_Filters(t *testing.T) {
+	rh, c, done := setup(t)
+	defer done()
+
+	rh.OnAdd(fixture.NewService("grpc").
+		WithPorts(v1.ServicePort{Port: 9000, TargetPort: intstr.FromInt(9000)}),
+	)
+
+	rh.OnAdd(fixture.NewService("grpc-shadow").
+		WithPorts(v1.ServicePort{Port: 9000, TargetPort: intstr.FromInt(9000)}),
+	)
+
+	rh.OnAdd(gc)
+
+	rh.OnAdd(gateway)
+
+	rh.OnAdd(&gatewayapi_v1alpha2.GRPCRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "filters",
+			Namespace: "default",
+		},
+		Spec: gatewayapi_v1alpha2.GRPCRouteSpec{
+			CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+				ParentRefs: []gatewayapi_v1beta1.ParentReference{
+					gatewayapi.GatewayListenerParentRef("projectcontour", "contour", "http", 80),
+				},
+			},
+			Hostnames: []gatewayapi_v1beta1.Hostname{
+				"test.projectcontour.io",
+			},
+			Rules: []gatewayapi_v1alpha2.GRPCRouteRule{{
+				Filters: []gatewayapi_v1alpha2.GRPCRouteFilter{{
+					Type: gatewayapi_v1alpha2.GRPCRouteFilterRequestHeaderModifier,
+					RequestHeaderModifier: &gatewayapi_v1beta1.HTTPHeaderFilter{
+						Set:    []gatewayapi_v1beta1.HTTPHeader{{Name: "x-request-metadata", Value: "set"}},
+						Remove: []string{"x-internal-metadata"},
+					},
+				}, {
+					Type: gatewayapi_v1alpha2.GRPCRouteFilterResponseHeaderModifier,
+					ResponseHeaderModifier: &gatewayapi_v1beta1.HTTPHeaderFilter{
+						Add: []gatewayapi_v1beta1.HTTPHeader{{Name: "x-response-metadata", Value: "added"}},
+					},
+				}, {
+					Type: gatewayapi_v1alpha2.GRPCRouteFilterRequestMirror,
+					RequestMirror: &gatewayapi_v1beta1.HTTPRequestMirrorFilter{
+						BackendRef: gatewayapi.ServiceBackendObjectRef("grpc-shadow", 9000),
+					},
+				}},
+				BackendRefs: gatewayapi.GRPCRouteBackendRef("grpc", 9000, 1),
+			}},
+		},
+	})
+
+	// The header modifiers only apply to the headers, so that the
+	// gRPC status in the response trailers is proxied unchanged.
+	c.Request(routeType, "ingress_http").Equals(&envoy_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_http",
+				envoy_v3.VirtualHost("test.projectcontour.io",
+					&envoy_route_v3.Route{
+						Match:  routePrefix("/"),
+						Action: withMirrorPolicy(routeCluster("default/grpc/9000/f4f94965ec"), "default/grpc-shadow/9000/f4f94965ec"),
+						RequestHeadersToAdd: []*envoy_core_v3.HeaderValueOption{{
+							Header: &envoy_core_v3.HeaderValue{
+								Key:   "X-Request-Metadata",
+								Value: "set",
+							},
+							AppendAction: envoy_core_v3.HeaderValueOption_OVERWRITE_IF_EXISTS_OR_ADD,
+						}},
+						RequestHeadersToRemove: []string{"X-Internal-Metadata"},
+						ResponseHeadersToAdd: []*envoy_core_v3.HeaderValueOption{{
+							Header: &envoy_core_v3.HeaderValue{
+								Key:   "X-Response-Metadata",
+								Value: "added",
+							},
+							AppendAction: envoy_core_v3.HeaderValueOption_APPEND_IF_EXISTS_OR_ADD,
+						}},
+					},
+				),
+			),
+		),
+		TypeUrl: routeType,
+	})
+
+	// Both the backend and the mirror are proxied to over h2c, the
+	// default for GRPCRoutes attached to an HTTP listener.
+	c.Request(clusterType).Equals(&envoy_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			h2cCluster(cluster("default/grpc-shadow/9000/f4f94965ec", "default/grpc-shadow", "default_grpc-shadow_9000")),
+			h2cCluster(cluster("default/grpc/9000/f4f94965ec", "default/grpc", "default_grpc_9000")),
+		),
+		TypeUrl: clusterType,
+	})
+}
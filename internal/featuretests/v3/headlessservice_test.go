@@ -0,0 +1,118 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3
+
+import (
+	"testing"
+
+	envoy_endpoint_v3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	envoy_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	envoy_v3 "github.com/projectcontour/contour/internal/envoy/v3"
+	"github.com/projectcontour/contour/internal/fixture"
+	"github.com/projectcontour/contour/internal/gatewayapi"
+	"github.com/projectcontour/contour/internal/ref"
+	v1 "k8s.io/api/core/v1"
+	discovery_v1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// test that an HTTPRoute to a headless Service load balances
+// across the pod IPs in the Service's EndpointSlices.
+func TestHTTPRouteHeadlessService(t *testing.T) {
+	rh, c, done := setup(t)
+	defer done()
+
+	rh.OnAdd(fixture.NewService("web").WithSpec(v1.ServiceSpec{
+		ClusterIP: v1.ClusterIPNone,
+		Ports: []v1.ServicePort{{
+			Name:       "http",
+			Port:       80,
+			TargetPort: intstr.FromString("http"),
+		}},
+	}))
+
+	rh.OnAdd(gc)
+	rh.OnAdd(gateway)
+	rh.OnAdd(&gatewayapi_v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web",
+			Namespace: "default",
+		},
+		Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+				ParentRefs: []gatewayapi_v1beta1.ParentReference{
+					gatewayapi.GatewayParentRef("projectcontour", "contour"),
+				},
+			},
+			Hostnames: []gatewayapi_v1beta1.Hostname{
+				"web.projectcontour.io",
+			},
+			Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+				Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
+				BackendRefs: gatewayapi.HTTPBackendRef("web", 80, 1),
+			}},
+		},
+	})
+
+	ports := []discovery_v1.EndpointPort{{Name: ref.To("http"), Port: ref.To(int32(8080))}}
+	slice := &discovery_v1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "web-abc",
+			Labels:    map[string]string{discovery_v1.LabelServiceName: "web"},
+		},
+		AddressType: discovery_v1.AddressTypeIPv4,
+		Endpoints: []discovery_v1.Endpoint{
+			{Addresses: []string{"10.244.0.10"}},
+			{Addresses: []string{"10.244.0.11"}},
+		},
+		Ports: ports,
+	}
+	rh.OnAdd(slice)
+
+	c.Request(endpointType).Equals(&envoy_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			&envoy_endpoint_v3.ClusterLoadAssignment{
+				ClusterName: "default/web/http",
+				Endpoints: envoy_v3.WeightedEndpoints(1,
+					envoy_v3.SocketAddress("10.244.0.10", 8080),
+					envoy_v3.SocketAddress("10.244.0.11", 8080),
+				),
+			},
+		),
+		TypeUrl: endpointType,
+	})
+
+	// Scaling up the StatefulSet behind the Service adds its
+	// new pod to the EndpointSlice.
+	scaled := slice.DeepCopy()
+	scaled.Endpoints = append(scaled.Endpoints, discovery_v1.Endpoint{Addresses: []string{"10.244.0.12"}})
+	rh.OnUpdate(slice, scaled)
+
+	c.Request(endpointType).Equals(&envoy_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			&envoy_endpoint_v3.ClusterLoadAssignment{
+				ClusterName: "default/web/http",
+				Endpoints: envoy_v3.WeightedEndpoints(1,
+					envoy_v3.SocketAddress("10.244.0.10", 8080),
+					envoy_v3.SocketAddress("10.244.0.11", 8080),
+					envoy_v3.SocketAddress("10.244.0.12", 8080),
+				),
+			},
+		),
+		TypeUrl: endpointType,
+	})
+}
@@ -0,0 +1,175 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3
+
+import (
+	"testing"
+
+	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	contour_api_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"github.com/projectcontour/contour/internal/dag"
+	envoy_v3 "github.com/projectcontour/contour/internal/envoy/v3"
+	"github.com/projectcontour/contour/internal/featuretests"
+	"github.com/projectcontour/contour/internal/fixture"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func hstsHeader(value string) []*envoy_core_v3.HeaderValueOption {
+	return []*envoy_core_v3.HeaderValueOption{{
+		Header: &envoy_core_v3.HeaderValue{
+			Key:   "Strict-Transport-Security",
+			Value: value,
+		},
+		AppendAction: envoy_core_v3.HeaderValueOption_ADD_IF_ABSENT,
+	}}
+}
+
+func TestHTTPProxyHSTSPolicy(t *testing.T) {
+	rh, c, done := setup(t, func(b *dag.Builder) {
+		for _, processor := range b.Processors {
+			if httpProxyProcessor, ok := processor.(*dag.HTTPProxyProcessor); ok {
+				httpProxyProcessor.HSTSPolicy = &contour_api_v1.HSTSPolicy{
+					MaxAge: "1h",
+				}
+			}
+		}
+	})
+	defer done()
+
+	sec1 := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "secret",
+			Namespace: "default",
+		},
+		Type: "kubernetes.io/tls",
+		Data: featuretests.Secretdata(featuretests.CERTIFICATE, featuretests.RSA_PRIVATE_KEY),
+	}
+	rh.OnAdd(sec1)
+
+	rh.OnAdd(fixture.NewService("backend").
+		WithPorts(v1.ServicePort{Name: "http", Port: 80}))
+
+	// The global policy applies to every secure virtual host
+	// that does not define its own.
+	p1 := fixture.NewProxy("simple").WithSpec(
+		contour_api_v1.HTTPProxySpec{
+			VirtualHost: &contour_api_v1.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &contour_api_v1.TLS{
+					SecretName: sec1.Name,
+				},
+			},
+			Routes: []contour_api_v1.Route{{
+				PermitInsecure: true,
+				Services: []contour_api_v1.Service{{
+					Name: "backend",
+					Port: 80,
+				}},
+			}},
+		})
+	rh.OnAdd(p1)
+
+	secureVirtualHost := func(value string) *envoy_route_v3.VirtualHost {
+		vh := envoy_v3.VirtualHost("example.com",
+			&envoy_route_v3.Route{
+				Match:  routePrefix("/"),
+				Action: routecluster("default/backend/80/da39a3ee5e"),
+			},
+		)
+		vh.ResponseHeadersToAdd = hstsHeader(value)
+		return vh
+	}
+
+	// Plaintext responses never carry the header.
+	insecureRoutes := envoy_v3.RouteConfiguration("ingress_http",
+		envoy_v3.VirtualHost("example.com",
+			&envoy_route_v3.Route{
+				Match:  routePrefix("/"),
+				Action: routecluster("default/backend/80/da39a3ee5e"),
+			},
+		),
+	)
+
+	c.Request(routeType).Equals(&envoy_discovery_v3.DiscoveryResponse{
+		Resources: routeResources(t,
+			insecureRoutes,
+			envoy_v3.RouteConfiguration("https/example.com", secureVirtualHost("max-age=3600")),
+		),
+		TypeUrl: routeType,
+	})
+
+	// A virtual host policy overrides the global one.
+	p2 := fixture.NewProxy("simple").WithSpec(
+		contour_api_v1.HTTPProxySpec{
+			VirtualHost: &contour_api_v1.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &contour_api_v1.TLS{
+					SecretName: sec1.Name,
+				},
+				HSTSPolicy: &contour_api_v1.HSTSPolicy{
+					MaxAge:            "8760h",
+					IncludeSubDomains: true,
+					Preload:           true,
+				},
+			},
+			Routes: []contour_api_v1.Route{{
+				PermitInsecure: true,
+				Services: []contour_api_v1.Service{{
+					Name: "backend",
+					Port: 80,
+				}},
+			}},
+		})
+	rh.OnUpdate(p1, p2)
+
+	c.Request(routeType).Equals(&envoy_discovery_v3.DiscoveryResponse{
+		Resources: routeResources(t,
+			insecureRoutes,
+			envoy_v3.RouteConfiguration("https/example.com", secureVirtualHost("max-age=31536000; includeSubDomains; preload")),
+		),
+		TypeUrl: routeType,
+	})
+
+	// An invalid virtual host policy rejects the HTTPProxy.
+	p3 := fixture.NewProxy("simple").WithSpec(
+		contour_api_v1.HTTPProxySpec{
+			VirtualHost: &contour_api_v1.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &contour_api_v1.TLS{
+					SecretName: sec1.Name,
+				},
+				HSTSPolicy: &contour_api_v1.HSTSPolicy{
+					MaxAge:  "1h",
+					Preload: true,
+				},
+			},
+			Routes: []contour_api_v1.Route{{
+				Services: []contour_api_v1.Service{{
+					Name: "backend",
+					Port: 80,
+				}},
+			}},
+		})
+	rh.OnUpdate(p2, p3)
+
+	c.Request(routeType).Equals(&envoy_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_http"),
+		),
+		TypeUrl: routeType,
+	})
+}
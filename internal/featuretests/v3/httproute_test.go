@@ -19,6 +19,7 @@ import (
 	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
 	envoy_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/projectcontour/contour/internal/dag"
 	envoy_v3 "github.com/projectcontour/contour/internal/envoy/v3"
 	"github.com/projectcontour/contour/internal/featuretests"
 	"github.com/projectcontour/contour/internal/fixture"
@@ -154,6 +155,19 @@ func TestGateway_TLS(t *testing.T) {
 					},
 				),
 			),
+			// The only HTTPS listener's certificate is served to
+			// clients that do not send SNI.
+			envoy_v3.RouteConfiguration("ingress_fallbackcert",
+				envoy_v3.VirtualHost("test.projectcontour.io",
+					&envoy_route_v3.Route{
+						Match:  routeSegmentPrefix("/blog"),
+						Action: routeCluster("default/svc2/80/da39a3ee5e"),
+					}, &envoy_route_v3.Route{
+						Match:  routePrefix("/"),
+						Action: routeCluster("default/svc1/80/da39a3ee5e"),
+					},
+				),
+			),
 			envoy_v3.RouteConfiguration("ingress_http",
 				envoy_v3.VirtualHost("test.projectcontour.io",
 					&envoy_route_v3.Route{
@@ -182,9 +196,100 @@ func TestGateway_TLS(t *testing.T) {
 					filterchaintls("test.projectcontour.io", sec1,
 						httpsFilterFor("test.projectcontour.io"),
 						nil, "h2", "http/1.1"),
+					filterchaintlsfallback(sec1, nil, "h2", "http/1.1"),
 				),
 				SocketOptions: envoy_v3.TCPKeepaliveSocketOptions(),
 			},
 		),
 	})
 }
+
+func TestHTTPRoute_HeaderCanary(t *testing.T) {
+	rh, c, done := setup(t)
+	defer done()
+
+	rh.OnAdd(fixture.NewService("stable").
+		WithPorts(v1.ServicePort{Port: 80, TargetPort: intstr.FromInt(8080)}),
+	)
+
+	rh.OnAdd(fixture.NewService("canary").
+		WithPorts(v1.ServicePort{Port: 80, TargetPort: intstr.FromInt(8080)}),
+	)
+
+	rh.OnAdd(gc)
+
+	rh.OnAdd(gateway)
+
+	// The stable and canary routes are separate HTTPRoutes for the same
+	// hostname, and only the canary one matches on the header.
+	rh.OnAdd(&gatewayapi_v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "stable",
+			Namespace: "default",
+		},
+		Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+				ParentRefs: []gatewayapi_v1beta1.ParentReference{
+					gatewayapi.GatewayListenerParentRef("projectcontour", "contour", "http", 80),
+				},
+			},
+			Hostnames: []gatewayapi_v1beta1.Hostname{
+				"test.projectcontour.io",
+			},
+			Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+				Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
+				BackendRefs: gatewayapi.HTTPBackendRef("stable", 80, 1),
+			}},
+		},
+	})
+
+	rh.OnAdd(&gatewayapi_v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "canary",
+			Namespace: "default",
+		},
+		Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+				ParentRefs: []gatewayapi_v1beta1.ParentReference{
+					gatewayapi.GatewayListenerParentRef("projectcontour", "contour", "http", 80),
+				},
+			},
+			Hostnames: []gatewayapi_v1beta1.Hostname{
+				"test.projectcontour.io",
+			},
+			Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+				Matches: []gatewayapi_v1beta1.HTTPRouteMatch{{
+					Path: &gatewayapi_v1beta1.HTTPPathMatch{
+						Type:  ref.To(gatewayapi_v1beta1.PathMatchPathPrefix),
+						Value: ref.To("/"),
+					},
+					Headers: gatewayapi.HTTPHeaderMatch(gatewayapi_v1beta1.HeaderMatchExact, "canary", "true"),
+				}},
+				BackendRefs: gatewayapi.HTTPBackendRef("canary", 80, 1),
+			}},
+		},
+	})
+
+	// The canary route is ordered first, so that only the requests with
+	// the header are routed to the canary service.
+	c.Request(routeType, "ingress_http").Equals(&envoy_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_http",
+				envoy_v3.VirtualHost("test.projectcontour.io",
+					&envoy_route_v3.Route{
+						Match: routePrefixWithHeaderConditions("/", dag.HeaderMatchCondition{
+							Name:      "canary",
+							Value:     "true",
+							MatchType: dag.HeaderMatchTypeExact,
+						}),
+						Action: routeCluster("default/canary/80/da39a3ee5e"),
+					}, &envoy_route_v3.Route{
+						Match:  routePrefix("/"),
+						Action: routeCluster("default/stable/80/da39a3ee5e"),
+					},
+				),
+			),
+		),
+		TypeUrl: routeType,
+	})
+}
@@ -0,0 +1,314 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3
+
+import (
+	"testing"
+
+	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	envoy_config_rbac_v3 "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_rbac_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/rbac/v3"
+	envoy_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	contour_api_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+	contour_api_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
+	envoy_v3 "github.com/projectcontour/contour/internal/envoy/v3"
+	"github.com/projectcontour/contour/internal/fixture"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func remoteIPPrincipal(prefix string, prefixLen uint32) *envoy_config_rbac_v3.Principal {
+	return &envoy_config_rbac_v3.Principal{
+		Identifier: &envoy_config_rbac_v3.Principal_RemoteIp{
+			RemoteIp: &envoy_core_v3.CidrRange{
+				AddressPrefix: prefix,
+				PrefixLen:     wrapperspb.UInt32(prefixLen),
+			},
+		},
+	}
+}
+
+func sourceRBAC(principal *envoy_config_rbac_v3.Principal) *envoy_rbac_v3.RBACPerRoute {
+	return &envoy_rbac_v3.RBACPerRoute{
+		Rbac: &envoy_rbac_v3.RBAC{
+			Rules: &envoy_config_rbac_v3.RBAC{
+				Action: envoy_config_rbac_v3.RBAC_ALLOW,
+				Policies: map[string]*envoy_config_rbac_v3.Policy{
+					"source": {
+						Permissions: []*envoy_config_rbac_v3.Permission{{
+							Rule: &envoy_config_rbac_v3.Permission_Any{Any: true},
+						}},
+						Principals: []*envoy_config_rbac_v3.Principal{principal},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestConditions_Source_HTTPProxy(t *testing.T) {
+	rh, c, done := setup(t)
+	defer done()
+
+	rh.OnAdd(fixture.NewService("internal").
+		WithPorts(v1.ServicePort{Port: 80, TargetPort: intstr.FromInt(8080)}),
+	)
+
+	rh.OnAdd(fixture.NewService("external").
+		WithPorts(v1.ServicePort{Port: 80, TargetPort: intstr.FromInt(8080)}),
+	)
+
+	// The /admin route is only served to clients in either of the
+	// internal ranges, the rest is served to everyone.
+	p1 := fixture.NewProxy("simple").WithSpec(
+		contour_api_v1.HTTPProxySpec{
+			VirtualHost: &contour_api_v1.VirtualHost{Fqdn: "hello.world"},
+			Routes: []contour_api_v1.Route{{
+				Conditions: []contour_api_v1.MatchCondition{{
+					Prefix: "/admin",
+				}, {
+					Source: &contour_api_v1.SourceFilter{
+						CIDRs: []string{"10.0.0.0/8", "fd00::/8"},
+					},
+				}},
+				Services: []contour_api_v1.Service{{
+					Name: "internal",
+					Port: 80,
+				}},
+			}, {
+				Services: []contour_api_v1.Service{{
+					Name: "external",
+					Port: 80,
+				}},
+			}},
+		})
+	rh.OnAdd(p1)
+
+	c.Request(routeType).Equals(&envoy_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_http",
+				envoy_v3.VirtualHost("hello.world",
+					&envoy_route_v3.Route{
+						Match:  routePrefix("/admin"),
+						Action: routeCluster("default/internal/80/da39a3ee5e"),
+						TypedPerFilterConfig: withFilterConfig("envoy.filters.http.rbac", sourceRBAC(
+							&envoy_config_rbac_v3.Principal{
+								Identifier: &envoy_config_rbac_v3.Principal_OrIds{
+									OrIds: &envoy_config_rbac_v3.Principal_Set{
+										Ids: []*envoy_config_rbac_v3.Principal{
+											remoteIPPrincipal("10.0.0.0", 8),
+											remoteIPPrincipal("fd00::", 8),
+										},
+									},
+								},
+							},
+						)),
+					},
+					&envoy_route_v3.Route{
+						Match:  routePrefix("/"),
+						Action: routeCluster("default/external/80/da39a3ee5e"),
+					},
+				),
+			),
+		),
+		TypeUrl: routeType,
+	})
+
+	c.Request(listenerType, "ingress_http").Equals(&envoy_discovery_v3.DiscoveryResponse{
+		TypeUrl: listenerType,
+		Resources: resources(t,
+			&envoy_listener_v3.Listener{
+				Name:    "ingress_http",
+				Address: envoy_v3.SocketAddress("0.0.0.0", 8080),
+				FilterChains: envoy_v3.FilterChains(
+					envoy_v3.HTTPConnectionManagerBuilder().
+						DefaultFilters().
+						AddFilter(envoy_v3.FilterRBAC(true)).
+						RouteConfigName("ingress_http").
+						MetricsPrefix("ingress_http").
+						AccessLoggers(envoy_v3.FileAccessLogEnvoy("/dev/stdout", "", nil, contour_api_v1alpha1.LogLevelInfo)).
+						Get(),
+				),
+				SocketOptions: envoy_v3.TCPKeepaliveSocketOptions(),
+			},
+		),
+	})
+
+	// Source conditions inherited from an include must all hold,
+	// and are combined with the route's own.
+	p2 := fixture.NewProxy("simple").WithSpec(
+		contour_api_v1.HTTPProxySpec{
+			VirtualHost: &contour_api_v1.VirtualHost{Fqdn: "hello.world"},
+			Includes: []contour_api_v1.Include{{
+				Name: "admin",
+				Conditions: []contour_api_v1.MatchCondition{{
+					Prefix: "/admin",
+				}, {
+					Source: &contour_api_v1.SourceFilter{
+						CIDRs: []string{"10.0.0.0/8"},
+					},
+				}},
+			}},
+		})
+	admin := fixture.NewProxy("admin").WithSpec(
+		contour_api_v1.HTTPProxySpec{
+			Routes: []contour_api_v1.Route{{
+				Conditions: []contour_api_v1.MatchCondition{{
+					Source: &contour_api_v1.SourceFilter{
+						CIDRs: []string{"10.1.0.0/16"},
+					},
+				}},
+				Services: []contour_api_v1.Service{{
+					Name: "internal",
+					Port: 80,
+				}},
+			}},
+		})
+	rh.OnAdd(admin)
+	rh.OnUpdate(p1, p2)
+
+	orIDs := func(principal *envoy_config_rbac_v3.Principal) *envoy_config_rbac_v3.Principal {
+		return &envoy_config_rbac_v3.Principal{
+			Identifier: &envoy_config_rbac_v3.Principal_OrIds{
+				OrIds: &envoy_config_rbac_v3.Principal_Set{
+					Ids: []*envoy_config_rbac_v3.Principal{principal},
+				},
+			},
+		}
+	}
+
+	c.Request(routeType).Equals(&envoy_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_http",
+				envoy_v3.VirtualHost("hello.world",
+					&envoy_route_v3.Route{
+						Match:  routePrefix("/admin"),
+						Action: routeCluster("default/internal/80/da39a3ee5e"),
+						TypedPerFilterConfig: withFilterConfig("envoy.filters.http.rbac", sourceRBAC(
+							&envoy_config_rbac_v3.Principal{
+								Identifier: &envoy_config_rbac_v3.Principal_AndIds{
+									AndIds: &envoy_config_rbac_v3.Principal_Set{
+										Ids: []*envoy_config_rbac_v3.Principal{
+											orIDs(remoteIPPrincipal("10.0.0.0", 8)),
+											orIDs(remoteIPPrincipal("10.1.0.0", 16)),
+										},
+									},
+								},
+							},
+						)),
+					},
+				),
+			),
+		),
+		TypeUrl: routeType,
+	})
+
+	// An invalid range rejects the HTTPProxy.
+	p3 := fixture.NewProxy("simple").WithSpec(
+		contour_api_v1.HTTPProxySpec{
+			VirtualHost: &contour_api_v1.VirtualHost{Fqdn: "hello.world"},
+			Routes: []contour_api_v1.Route{{
+				Conditions: []contour_api_v1.MatchCondition{{
+					Source: &contour_api_v1.SourceFilter{
+						CIDRs: []string{"10.0.0.1"},
+					},
+				}},
+				Services: []contour_api_v1.Service{{
+					Name: "internal",
+					Port: 80,
+				}},
+			}},
+		})
+	rh.OnUpdate(p2, p3)
+
+	c.Request(routeType).Equals(&envoy_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_http"),
+		),
+		TypeUrl: routeType,
+	})
+
+	// A route restricted to a source range and an unrestricted route
+	// on the same path would shadow each other, so the proxy is invalid.
+	p4 := fixture.NewProxy("simple").WithSpec(
+		contour_api_v1.HTTPProxySpec{
+			VirtualHost: &contour_api_v1.VirtualHost{Fqdn: "hello.world"},
+			Routes: []contour_api_v1.Route{{
+				Services: []contour_api_v1.Service{{
+					Name: "external",
+					Port: 80,
+				}},
+			}, {
+				Conditions: []contour_api_v1.MatchCondition{{
+					Source: &contour_api_v1.SourceFilter{
+						CIDRs: []string{"10.0.0.0/8"},
+					},
+				}},
+				Services: []contour_api_v1.Service{{
+					Name: "internal",
+					Port: 80,
+				}},
+			}},
+		})
+	rh.OnUpdate(p3, p4)
+
+	c.Request(routeType).Equals(&envoy_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_http"),
+		),
+		TypeUrl: routeType,
+	})
+
+	// Routes that only differ by their source ranges are invalid, too.
+	p5 := fixture.NewProxy("simple").WithSpec(
+		contour_api_v1.HTTPProxySpec{
+			VirtualHost: &contour_api_v1.VirtualHost{Fqdn: "hello.world"},
+			Routes: []contour_api_v1.Route{{
+				Conditions: []contour_api_v1.MatchCondition{{
+					Prefix: "/admin",
+				}, {
+					Source: &contour_api_v1.SourceFilter{
+						CIDRs: []string{"10.0.0.0/8"},
+					},
+				}},
+				Services: []contour_api_v1.Service{{
+					Name: "internal",
+					Port: 80,
+				}},
+			}, {
+				Conditions: []contour_api_v1.MatchCondition{{
+					Prefix: "/admin",
+				}, {
+					Source: &contour_api_v1.SourceFilter{
+						CIDRs: []string{"192.168.0.0/16"},
+					},
+				}},
+				Services: []contour_api_v1.Service{{
+					Name: "external",
+					Port: 80,
+				}},
+			}},
+		})
+	rh.OnUpdate(p4, p5)
+
+	c.Request(routeType).Equals(&envoy_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_http"),
+		),
+		TypeUrl: routeType,
+	})
+}
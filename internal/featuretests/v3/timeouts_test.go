@@ -23,6 +23,7 @@ import (
 	"github.com/projectcontour/contour/internal/contourconfig"
 	envoy_v3 "github.com/projectcontour/contour/internal/envoy/v3"
 	"github.com/projectcontour/contour/internal/fixture"
+	"github.com/projectcontour/contour/internal/ref"
 	"github.com/projectcontour/contour/internal/timeout"
 	xdscache_v3 "github.com/projectcontour/contour/internal/xdscache/v3"
 	v1 "k8s.io/api/core/v1"
@@ -131,3 +132,60 @@ func TestNonZeroTimeoutsSpecified(t *testing.T) {
 		Resources: resources(t, httpListener),
 	})
 }
+
+func TestTimeoutResponseSpecified(t *testing.T) {
+	timeoutResponse := &v1alpha1.TimeoutResponse{
+		StatusCode:  ref.To(504),
+		Body:        "<html><body>Please try again later.</body></html>",
+		ContentType: "text/html",
+	}
+
+	rh, c, done := setup(t, func(conf *xdscache_v3.ListenerConfig) {
+		conf.Timeouts = contourconfig.Timeouts{
+			TimeoutResponse: timeoutResponse,
+		}
+	})
+	defer done()
+
+	s1 := fixture.NewService("backend").
+		WithPorts(v1.ServicePort{Name: "http", Port: 80})
+	rh.OnAdd(s1)
+
+	hp1 := &contour_api_v1.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "simple",
+			Namespace: s1.Namespace,
+		},
+		Spec: contour_api_v1.HTTPProxySpec{
+			VirtualHost: &contour_api_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []contour_api_v1.Route{{
+				Conditions: matchconditions(prefixMatchCondition("/")),
+				TimeoutPolicy: &contour_api_v1.TimeoutPolicy{
+					Response: "1s",
+				},
+				Services: []contour_api_v1.Service{{
+					Name: s1.Name,
+					Port: 80,
+				}},
+			}},
+		},
+	}
+	rh.OnAdd(hp1)
+
+	httpListener := defaultHTTPListener()
+	httpListener.FilterChains = envoy_v3.FilterChains(envoy_v3.HTTPConnectionManagerBuilder().
+		RouteConfigName(xdscache_v3.ENVOY_HTTP_LISTENER).
+		MetricsPrefix(xdscache_v3.ENVOY_HTTP_LISTENER).
+		AccessLoggers(envoy_v3.FileAccessLogEnvoy(xdscache_v3.DEFAULT_HTTP_ACCESS_LOG, "", nil, v1alpha1.LogLevelInfo)).
+		DefaultFilters().
+		TimeoutResponse(timeoutResponse).
+		Get(),
+	)
+
+	c.Request(listenerType, xdscache_v3.ENVOY_HTTP_LISTENER).Equals(&envoy_discovery_v3.DiscoveryResponse{
+		TypeUrl:   listenerType,
+		Resources: resources(t, httpListener),
+	})
+}
@@ -0,0 +1,299 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3
+
+import (
+	"testing"
+
+	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_tls_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	envoy_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/projectcontour/contour/internal/dag"
+	envoy_v3 "github.com/projectcontour/contour/internal/envoy/v3"
+	"github.com/projectcontour/contour/internal/featuretests"
+	"github.com/projectcontour/contour/internal/fixture"
+	"github.com/projectcontour/contour/internal/gatewayapi"
+	"github.com/projectcontour/contour/internal/ref"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func TestGatewayListenerTLSOptions(t *testing.T) {
+	rh, c, done := setup(t)
+	defer done()
+
+	rh.OnAdd(fixture.NewService("svc1").
+		WithPorts(v1.ServicePort{Port: 80, TargetPort: intstr.FromInt(8080)}),
+	)
+
+	sec1 := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "tlscert",
+			Namespace: "projectcontour",
+		},
+		Type: v1.SecretTypeTLS,
+		Data: featuretests.Secretdata(featuretests.CERTIFICATE, featuretests.RSA_PRIVATE_KEY),
+	}
+	sec1.Data[dag.OCSPStapleKey] = []byte("staple")
+	rh.OnAdd(sec1)
+
+	rh.OnAdd(gc)
+
+	rh.OnAdd(&gatewayapi_v1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "contour",
+			Namespace: "projectcontour",
+		},
+		Spec: gatewayapi_v1beta1.GatewaySpec{
+			GatewayClassName: gatewayapi_v1beta1.ObjectName(gc.Name),
+			Listeners: []gatewayapi_v1beta1.Listener{{
+				Name:     "https",
+				Port:     443,
+				Protocol: gatewayapi_v1beta1.HTTPSProtocolType,
+				TLS: &gatewayapi_v1beta1.GatewayTLSConfig{
+					CertificateRefs: []gatewayapi_v1beta1.SecretObjectReference{
+						gatewayapi.CertificateRef("tlscert", ""),
+					},
+					Options: map[gatewayapi_v1beta1.AnnotationKey]gatewayapi_v1beta1.AnnotationValue{
+						gatewayapi.TLSOptionOCSPStaplePolicy: gatewayapi.OCSPStaplePolicyMustStaple,
+						gatewayapi.TLSOptionSessionTickets:   "disabled",
+						// Unknown options are ignored.
+						"example.com/early-data": "true",
+					},
+				},
+				AllowedRoutes: &gatewayapi_v1beta1.AllowedRoutes{
+					Namespaces: &gatewayapi_v1beta1.RouteNamespaces{
+						From: ref.To(gatewayapi_v1beta1.NamespacesFromAll),
+					},
+				},
+			}},
+		},
+	})
+
+	rh.OnAdd(&gatewayapi_v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "basic",
+			Namespace: "default",
+		},
+		Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+				ParentRefs: []gatewayapi_v1beta1.ParentReference{
+					gatewayapi.GatewayParentRef("projectcontour", "contour"),
+				},
+			},
+			Hostnames: []gatewayapi_v1beta1.Hostname{
+				"test.projectcontour.io",
+			},
+			Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+				Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
+				BackendRefs: gatewayapi.HTTPBackendRef("svc1", 80, 1),
+			}},
+		},
+	})
+
+	tlsContext := envoy_v3.DownstreamTLSContext(
+		&dag.Secret{Object: sec1},
+		envoy_tls_v3.TlsParameters_TLSv1_2,
+		nil,
+		nil,
+		"h2", "http/1.1")
+	tlsContext.OcspStaplePolicy = envoy_tls_v3.DownstreamTlsContext_MUST_STAPLE
+	tlsContext.SessionTicketKeysType = &envoy_tls_v3.DownstreamTlsContext_DisableStatelessSessionResumption{
+		DisableStatelessSessionResumption: true,
+	}
+
+	c.Request(listenerType, "ingress_https").Equals(&envoy_discovery_v3.DiscoveryResponse{
+		TypeUrl: listenerType,
+		Resources: resources(t,
+			&envoy_listener_v3.Listener{
+				Name:    "ingress_https",
+				Address: envoy_v3.SocketAddress("0.0.0.0", 8443),
+				ListenerFilters: envoy_v3.ListenerFilters(
+					envoy_v3.TLSInspector(),
+				),
+				FilterChains: appendFilterChains(
+					envoy_v3.FilterChainTLS(
+						"test.projectcontour.io",
+						tlsContext,
+						envoy_v3.Filters(httpsFilterFor("test.projectcontour.io")),
+					),
+					// The default certificate is served with the same options.
+					filterchaintlsfallbackcontext(tlsContext),
+				),
+				SocketOptions: envoy_v3.TCPKeepaliveSocketOptions(),
+			},
+		),
+	})
+
+	// The OCSP response in the secret is sent with the certificate.
+	c.Request(secretType).Equals(&envoy_discovery_v3.DiscoveryResponse{
+		TypeUrl: secretType,
+		Resources: resources(t,
+			envoy_v3.Secret(&dag.Secret{Object: sec1}),
+		),
+	})
+}
+
+func TestGatewayListenerDefaultCertificate(t *testing.T) {
+	rh, c, done := setup(t)
+	defer done()
+
+	rh.OnAdd(fixture.NewService("svc1").
+		WithPorts(v1.ServicePort{Port: 80, TargetPort: intstr.FromInt(8080)}),
+	)
+
+	tlsSecret := func(name string) *v1.Secret {
+		return &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "projectcontour",
+			},
+			Type: v1.SecretTypeTLS,
+			Data: featuretests.Secretdata(featuretests.CERTIFICATE, featuretests.RSA_PRIVATE_KEY),
+		}
+	}
+
+	sec1 := tlsSecret("tlscert1")
+	sec2 := tlsSecret("tlscert2")
+	rh.OnAdd(sec1)
+	rh.OnAdd(sec2)
+
+	rh.OnAdd(gc)
+
+	httpsListener := func(name, hostname, secret string, options map[gatewayapi_v1beta1.AnnotationKey]gatewayapi_v1beta1.AnnotationValue) gatewayapi_v1beta1.Listener {
+		return gatewayapi_v1beta1.Listener{
+			Name:     gatewayapi_v1beta1.SectionName(name),
+			Port:     443,
+			Protocol: gatewayapi_v1beta1.HTTPSProtocolType,
+			Hostname: ref.To(gatewayapi_v1beta1.Hostname(hostname)),
+			TLS: &gatewayapi_v1beta1.GatewayTLSConfig{
+				CertificateRefs: []gatewayapi_v1beta1.SecretObjectReference{
+					gatewayapi.CertificateRef(secret, ""),
+				},
+				Options: options,
+			},
+			AllowedRoutes: &gatewayapi_v1beta1.AllowedRoutes{
+				Namespaces: &gatewayapi_v1beta1.RouteNamespaces{
+					From: ref.To(gatewayapi_v1beta1.NamespacesFromAll),
+				},
+			},
+		}
+	}
+
+	gw := &gatewayapi_v1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "contour",
+			Namespace:       "projectcontour",
+			Generation:      1,
+			ResourceVersion: "1",
+		},
+		Spec: gatewayapi_v1beta1.GatewaySpec{
+			GatewayClassName: gatewayapi_v1beta1.ObjectName(gc.Name),
+			Listeners: []gatewayapi_v1beta1.Listener{
+				httpsListener("https-1", "one.projectcontour.io", "tlscert1", nil),
+				httpsListener("https-2", "two.projectcontour.io", "tlscert2", map[gatewayapi_v1beta1.AnnotationKey]gatewayapi_v1beta1.AnnotationValue{
+					gatewayapi.TLSOptionDefaultCertificate: "true",
+				}),
+			},
+		},
+	}
+	rh.OnAdd(gw)
+
+	for _, hostname := range []string{"one.projectcontour.io", "two.projectcontour.io"} {
+		rh.OnAdd(&gatewayapi_v1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      hostname,
+				Namespace: "default",
+			},
+			Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+					ParentRefs: []gatewayapi_v1beta1.ParentReference{
+						gatewayapi.GatewayParentRef("projectcontour", "contour"),
+					},
+				},
+				Hostnames: []gatewayapi_v1beta1.Hostname{
+					gatewayapi_v1beta1.Hostname(hostname),
+				},
+				Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+					Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
+					BackendRefs: gatewayapi.HTTPBackendRef("svc1", 80, 1),
+				}},
+			},
+		})
+	}
+
+	// The designated listener's certificate is served to clients that
+	// do not send SNI, and only its hostnames can be routed to.
+	c.Request(listenerType, "ingress_https").Equals(&envoy_discovery_v3.DiscoveryResponse{
+		TypeUrl: listenerType,
+		Resources: resources(t,
+			&envoy_listener_v3.Listener{
+				Name:    "ingress_https",
+				Address: envoy_v3.SocketAddress("0.0.0.0", 8443),
+				ListenerFilters: envoy_v3.ListenerFilters(
+					envoy_v3.TLSInspector(),
+				),
+				FilterChains: appendFilterChains(
+					filterchaintls("one.projectcontour.io", sec1,
+						httpsFilterFor("one.projectcontour.io"),
+						nil, "h2", "http/1.1"),
+					filterchaintls("two.projectcontour.io", sec2,
+						httpsFilterFor("two.projectcontour.io"),
+						nil, "h2", "http/1.1"),
+					filterchaintlsfallback(sec2, nil, "h2", "http/1.1"),
+				),
+				SocketOptions: envoy_v3.TCPKeepaliveSocketOptions(),
+			},
+		),
+	})
+
+	c.Request(routeType, "ingress_fallbackcert").Equals(&envoy_discovery_v3.DiscoveryResponse{
+		TypeUrl: routeType,
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_fallbackcert",
+				envoy_v3.VirtualHost("two.projectcontour.io",
+					&envoy_route_v3.Route{
+						Match:  routePrefix("/"),
+						Action: routeCluster("default/svc1/80/da39a3ee5e"),
+					},
+				),
+			),
+		),
+	})
+
+	// Without a designated listener, the first HTTPS listener's
+	// certificate is the default.
+	gw2 := gw.DeepCopy()
+	gw2.Generation++
+	gw2.ResourceVersion = "2"
+	gw2.Spec.Listeners[1].TLS.Options = nil
+	rh.OnUpdate(gw, gw2)
+
+	c.Request(routeType, "ingress_fallbackcert").Equals(&envoy_discovery_v3.DiscoveryResponse{
+		TypeUrl: routeType,
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_fallbackcert",
+				envoy_v3.VirtualHost("one.projectcontour.io",
+					&envoy_route_v3.Route{
+						Match:  routePrefix("/"),
+						Action: routeCluster("default/svc1/80/da39a3ee5e"),
+					},
+				),
+			),
+		),
+	})
+}
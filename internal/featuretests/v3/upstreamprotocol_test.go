@@ -16,13 +16,18 @@ package v3
 import (
 	"testing"
 
+	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
 	envoy_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	envoy_v3 "github.com/projectcontour/contour/internal/envoy/v3"
 	"github.com/projectcontour/contour/internal/featuretests"
 	"github.com/projectcontour/contour/internal/fixture"
+	"github.com/projectcontour/contour/internal/gatewayapi"
+	"github.com/projectcontour/contour/internal/ref"
 	v1 "k8s.io/api/core/v1"
 	networking_v1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
 // Test that contour correctly recognizes the upstream-protocol.tls
@@ -150,3 +155,69 @@ func TestUpstreamProtocolH2(t *testing.T) {
 		TypeUrl: clusterType,
 	})
 }
+
+// Test that the backends of a split HTTPRoute rule each get a cluster
+// with the protocol of their own Service port.
+func TestUpstreamProtocolPerHTTPRouteBackend(t *testing.T) {
+	rh, c, done := setup(t)
+	defer done()
+
+	rh.OnAdd(fixture.NewService("grpc").
+		WithPorts(v1.ServicePort{Name: "http", Port: 80, TargetPort: intstr.FromInt(8080), AppProtocol: ref.To("kubernetes.io/h2c")}),
+	)
+	rh.OnAdd(fixture.NewService("web").
+		WithPorts(v1.ServicePort{Name: "http", Port: 80, TargetPort: intstr.FromInt(8080)}),
+	)
+
+	rh.OnAdd(gc)
+	rh.OnAdd(gateway)
+
+	rh.OnAdd(&gatewayapi_v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "split",
+			Namespace: "default",
+		},
+		Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+				ParentRefs: []gatewayapi_v1beta1.ParentReference{
+					gatewayapi.GatewayListenerParentRef("projectcontour", "contour", "http", 0),
+				},
+			},
+			Hostnames: []gatewayapi_v1beta1.Hostname{
+				"test.projectcontour.io",
+			},
+			Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+				Matches: gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
+				BackendRefs: gatewayapi.HTTPBackendRefs(
+					gatewayapi.HTTPBackendRef("grpc", 80, 1),
+					gatewayapi.HTTPBackendRef("web", 80, 3),
+				),
+			}},
+		},
+	})
+
+	c.Request(routeType, "ingress_http").Equals(&envoy_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_http",
+				envoy_v3.VirtualHost("test.projectcontour.io",
+					&envoy_route_v3.Route{
+						Match: routePrefix("/"),
+						Action: routeWeightedCluster(
+							weightedCluster{"default/grpc/80/f4f94965ec", 1},
+							weightedCluster{"default/web/80/da39a3ee5e", 3},
+						),
+					},
+				),
+			),
+		),
+		TypeUrl: routeType,
+	})
+
+	c.Request(clusterType).Equals(&envoy_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			h2cCluster(cluster("default/grpc/80/f4f94965ec", "default/grpc/http", "default_grpc_80")),
+			cluster("default/web/80/da39a3ee5e", "default/web/http", "default_web_80"),
+		),
+		TypeUrl: clusterType,
+	})
+}
@@ -0,0 +1,38 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gatewayapi
+
+// SupportedFeatures are the Gateway API conformance features that
+// Contour implements, using the feature names of the Gateway API
+// conformance suite.
+//
+// The list is not published on the GatewayClass: its
+// status.supportedFeatures field does not exist in Gateway API v0.6.2,
+// so publishing it is deferred until Contour moves to a later Gateway
+// API version, and the list only selects the features the conformance
+// suite runs until then.
+var SupportedFeatures = []string{
+	"ReferenceGrant",
+	"TLSRoute",
+	"HTTPRouteQueryParamMatching",
+	"HTTPRouteMethodMatching",
+	"HTTPResponseHeaderModification",
+	"RouteDestinationPortMatching",
+	"GatewayClassObservedGenerationBump",
+	"HTTPRoutePortRedirect",
+	"HTTPRouteSchemeRedirect",
+	"HTTPRoutePathRedirect",
+	"HTTPRouteHostRewrite",
+	"HTTPRoutePathRewrite",
+}
@@ -0,0 +1,36 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gatewayapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/gateway-api/conformance/utils/suite"
+)
+
+func TestSupportedFeatures(t *testing.T) {
+	features := sets.New[suite.SupportedFeature]()
+	for _, f := range SupportedFeatures {
+		assert.Falsef(t, features.Has(suite.SupportedFeature(f)), "feature %q is listed more than once", f)
+		features.Insert(suite.SupportedFeature(f))
+	}
+
+	// Every core feature must be supported, and Contour implements
+	// all the extended and experimental features of the conformance
+	// suite.
+	assert.True(t, features.IsSuperset(suite.StandardCoreFeatures))
+	assert.Equal(t, sets.List(suite.AllFeatures), sets.List(features))
+}
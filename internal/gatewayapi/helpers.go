@@ -85,7 +85,7 @@ func HTTPRouteMatch(pathType gatewayapi_v1beta1.PathMatchType, value string) []g
 func HTTPHeaderMatch(matchType gatewayapi_v1beta1.HeaderMatchType, name, value string) []gatewayapi_v1beta1.HTTPHeaderMatch {
 	return []gatewayapi_v1beta1.HTTPHeaderMatch{
 		{
-			Type:  ref.To(gatewayapi_v1beta1.HeaderMatchExact),
+			Type:  ref.To(matchType),
 			Name:  gatewayapi_v1beta1.HTTPHeaderName(name),
 			Value: value,
 		},
@@ -183,6 +183,17 @@ func GRPCHeaderMatch(name, value string) []gatewayapi_v1alpha2.GRPCHeaderMatch {
 	}
 }
 
+// IsRefToService returns whether the provided parent ref is a reference
+// to a core Service, as used to attach routes to a Service for mesh
+// (east/west) routing.
+func IsRefToService(parentRef gatewayapi_v1beta1.ParentReference) bool {
+	if parentRef.Group == nil || (*parentRef.Group != "" && *parentRef.Group != "core") {
+		return false
+	}
+
+	return parentRef.Kind != nil && *parentRef.Kind == "Service"
+}
+
 // IsRefToGateway returns whether the provided parent ref is a reference
 // to a Gateway with the given namespace/name, irrespective of whether a
 // section/listener name has been specified (i.e. a parent ref to a listener
@@ -33,6 +33,7 @@ type ValidateListenersResult struct {
 // ValidateListeners validates protocols, ports and hostnames on a set of listeners.
 // It ensures that:
 //   - all protocols are supported
+//   - all ports are between 1 and 65535
 //   - each listener group (grouped by protocol, with HTTPS & TLS going together) uses a single port
 //   - listener hostnames are syntactically valid
 //   - hostnames within each listener group are unique
@@ -56,6 +57,12 @@ func ValidateListeners(listeners []gatewayapi_v1beta1.Listener) ValidateListener
 	)
 
 	for _, listener := range listeners {
+		// Listeners with an invalid port can't claim the port of
+		// their group.
+		if !isValidPort(listener.Port) {
+			continue
+		}
+
 		hostname := HostnameDeref(listener.Hostname)
 
 		switch listener.Protocol {
@@ -87,6 +94,16 @@ func ValidateListeners(listeners []gatewayapi_v1beta1.Listener) ValidateListener
 	}
 
 	for _, listener := range listeners {
+		if !isValidPort(listener.Port) {
+			result.InvalidListenerConditions[listener.Name] = metav1.Condition{
+				Type:    string(gatewayapi_v1beta1.ListenerConditionProgrammed),
+				Status:  metav1.ConditionFalse,
+				Reason:  string(gatewayapi_v1beta1.ListenerReasonInvalid),
+				Message: fmt.Sprintf("Listener port %d is invalid, must be between 1 and 65535", listener.Port),
+			}
+			continue
+		}
+
 		hostname := HostnameDeref(listener.Hostname)
 
 		if len(hostname) > 0 {
@@ -148,6 +165,12 @@ func ValidateListeners(listeners []gatewayapi_v1beta1.Listener) ValidateListener
 	return result
 }
 
+// isValidPort returns whether port is a valid TCP port
+// number for a listener.
+func isValidPort(port gatewayapi_v1beta1.PortNumber) bool {
+	return port >= 1 && port <= 65535
+}
+
 // HostnameDeref returns the hostname as a string if it's not nil,
 // or an empty string otherwise.
 func HostnameDeref(hostname *gatewayapi_v1beta1.Hostname) string {
@@ -177,3 +200,12 @@ func IsValidHostname(hostname string) error {
 
 	return nil
 }
+
+// HostnameHasSuffix returns true if every hostname that hostname
+// matches is suffix itself or a subdomain of it. For example, both
+// "foo.example.com" and "*.example.com" have the suffix "example.com",
+// while "*" and "badexample.com" do not.
+func HostnameHasSuffix(hostname, suffix string) bool {
+	hostname = strings.TrimPrefix(hostname, "*.")
+	return hostname == suffix || strings.HasSuffix(hostname, "."+suffix)
+}
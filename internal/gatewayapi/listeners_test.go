@@ -358,4 +358,114 @@ func TestValidateListeners(t *testing.T) {
 			Message: "invalid hostname \".invalid.$.\": [a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')]",
 		},
 	}, res.InvalidListenerConditions)
+
+	// Listeners with ports outside 1-65535 are invalid, and don't
+	// take the port of their group from the valid listeners that
+	// follow them.
+	listeners = []gatewayapi_v1beta1.Listener{
+		{
+			Name:     "listener-1",
+			Protocol: gatewayapi_v1beta1.HTTPProtocolType,
+			Port:     0,
+		},
+		{
+			Name:     "listener-2",
+			Protocol: gatewayapi_v1beta1.HTTPProtocolType,
+			Port:     1,
+		},
+		{
+			Name:     "listener-3",
+			Protocol: gatewayapi_v1beta1.HTTPSProtocolType,
+			Port:     65536,
+		},
+		{
+			Name:     "listener-4",
+			Protocol: gatewayapi_v1beta1.HTTPSProtocolType,
+			Port:     65535,
+		},
+		{
+			Name:     "listener-5",
+			Protocol: gatewayapi_v1beta1.TLSProtocolType,
+			Port:     -1,
+		},
+	}
+
+	res = ValidateListeners(listeners)
+	assert.Equal(t, 1, res.InsecurePort)
+	assert.Equal(t, 65535, res.SecurePort)
+	assert.Equal(t, map[gatewayapi_v1beta1.SectionName]metav1.Condition{
+		"listener-1": {
+			Type:    string(gatewayapi_v1beta1.ListenerConditionProgrammed),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(gatewayapi_v1beta1.ListenerReasonInvalid),
+			Message: "Listener port 0 is invalid, must be between 1 and 65535",
+		},
+		"listener-3": {
+			Type:    string(gatewayapi_v1beta1.ListenerConditionProgrammed),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(gatewayapi_v1beta1.ListenerReasonInvalid),
+			Message: "Listener port 65536 is invalid, must be between 1 and 65535",
+		},
+		"listener-5": {
+			Type:    string(gatewayapi_v1beta1.ListenerConditionProgrammed),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(gatewayapi_v1beta1.ListenerReasonInvalid),
+			Message: "Listener port -1 is invalid, must be between 1 and 65535",
+		},
+	}, res.InvalidListenerConditions)
+}
+
+func TestHostnameHasSuffix(t *testing.T) {
+	tests := map[string]struct {
+		hostname string
+		suffix   string
+		want     bool
+	}{
+		"suffix itself": {
+			hostname: "example.com",
+			suffix:   "example.com",
+			want:     true,
+		},
+		"subdomain": {
+			hostname: "foo.example.com",
+			suffix:   "example.com",
+			want:     true,
+		},
+		"nested subdomain": {
+			hostname: "bar.foo.example.com",
+			suffix:   "example.com",
+			want:     true,
+		},
+		"wildcard subdomain": {
+			hostname: "*.example.com",
+			suffix:   "example.com",
+			want:     true,
+		},
+		"wildcard of a parent domain": {
+			hostname: "*.com",
+			suffix:   "example.com",
+			want:     false,
+		},
+		"match all": {
+			hostname: "*",
+			suffix:   "example.com",
+			want:     false,
+		},
+		"partial label": {
+			hostname: "badexample.com",
+			suffix:   "example.com",
+			want:     false,
+		},
+		"different domain": {
+			hostname: "example.org",
+			suffix:   "example.com",
+			want:     false,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, HostnameHasSuffix(tc.hostname, tc.suffix))
+		})
+	}
 }
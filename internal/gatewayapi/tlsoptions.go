@@ -0,0 +1,116 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gatewayapi
+
+import (
+	"fmt"
+	"sort"
+
+	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+const (
+	// TLSOptionOCSPStaplePolicy is the Listener TLS option that sets how
+	// Envoy uses the OCSP response stapled to the listener's certificate.
+	TLSOptionOCSPStaplePolicy = "projectcontour.io/ocsp-staple-policy"
+
+	// TLSOptionSessionTickets is the Listener TLS option that enables or
+	// disables TLS session resumption using session tickets.
+	TLSOptionSessionTickets = "projectcontour.io/session-tickets"
+
+	// TLSOptionDefaultCertificate is the Listener TLS option that makes
+	// the listener's certificate the one served to clients that do not
+	// send a TLS SNI server name.
+	TLSOptionDefaultCertificate = "projectcontour.io/default-certificate"
+)
+
+const (
+	// OCSPStaplePolicyLenient staples the OCSP response if there is
+	// one, and still uses the certificate if it is missing or expired.
+	// This is the default.
+	OCSPStaplePolicyLenient = "LenientStapling"
+
+	// OCSPStaplePolicyStrict staples the OCSP response if there is one,
+	// but stops using the certificate if the response has expired.
+	OCSPStaplePolicyStrict = "StrictStapling"
+
+	// OCSPStaplePolicyMustStaple requires a valid OCSP response for the
+	// certificate to be used.
+	OCSPStaplePolicyMustStaple = "MustStaple"
+)
+
+// TLSOptions are the Envoy downstream TLS settings that can be set
+// through a Listener's TLS options.
+type TLSOptions struct {
+	// OCSPStaplePolicy is one of the OCSPStaplePolicy constants, or
+	// empty to use Envoy's default.
+	OCSPStaplePolicy string
+
+	// DisableSessionTickets disables stateless TLS session resumption.
+	DisableSessionTickets bool
+
+	// DefaultCertificate makes the listener's certificate the default
+	// for clients that do not send SNI.
+	DefaultCertificate bool
+}
+
+// ParseTLSOptions returns the TLSOptions set by the supplied Listener
+// TLS options. Options that Contour does not recognize, or that have
+// an invalid value, are ignored and described in the returned messages.
+func ParseTLSOptions(options map[gatewayapi_v1beta1.AnnotationKey]gatewayapi_v1beta1.AnnotationValue) (TLSOptions, []string) {
+	var (
+		result  TLSOptions
+		ignored []string
+	)
+
+	for key, value := range options {
+		switch key {
+		case TLSOptionOCSPStaplePolicy:
+			switch value {
+			case OCSPStaplePolicyLenient, OCSPStaplePolicyStrict, OCSPStaplePolicyMustStaple:
+				result.OCSPStaplePolicy = string(value)
+			default:
+				ignored = append(ignored, fmt.Sprintf("TLS option %q has invalid value %q, must be %q, %q or %q",
+					key, value, OCSPStaplePolicyLenient, OCSPStaplePolicyStrict, OCSPStaplePolicyMustStaple))
+			}
+		case TLSOptionSessionTickets:
+			switch value {
+			case "enabled":
+				result.DisableSessionTickets = false
+			case "disabled":
+				result.DisableSessionTickets = true
+			default:
+				ignored = append(ignored, fmt.Sprintf("TLS option %q has invalid value %q, must be %q or %q",
+					key, value, "enabled", "disabled"))
+			}
+		case TLSOptionDefaultCertificate:
+			switch value {
+			case "true":
+				result.DefaultCertificate = true
+			case "false":
+				result.DefaultCertificate = false
+			default:
+				ignored = append(ignored, fmt.Sprintf("TLS option %q has invalid value %q, must be %q or %q",
+					key, value, "true", "false"))
+			}
+		default:
+			ignored = append(ignored, fmt.Sprintf("TLS option %q is not supported", key))
+		}
+	}
+
+	// Map iteration order is random, keep status messages stable.
+	sort.Strings(ignored)
+
+	return result, ignored
+}
@@ -0,0 +1,78 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gatewayapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func TestParseTLSOptions(t *testing.T) {
+	tests := map[string]struct {
+		options     map[gatewayapi_v1beta1.AnnotationKey]gatewayapi_v1beta1.AnnotationValue
+		want        TLSOptions
+		wantIgnored []string
+	}{
+		"no options": {
+			options: nil,
+			want:    TLSOptions{},
+		},
+		"all options": {
+			options: map[gatewayapi_v1beta1.AnnotationKey]gatewayapi_v1beta1.AnnotationValue{
+				TLSOptionOCSPStaplePolicy:   "MustStaple",
+				TLSOptionSessionTickets:     "disabled",
+				TLSOptionDefaultCertificate: "true",
+			},
+			want: TLSOptions{
+				OCSPStaplePolicy:      OCSPStaplePolicyMustStaple,
+				DisableSessionTickets: true,
+				DefaultCertificate:    true,
+			},
+		},
+		"session tickets enabled": {
+			options: map[gatewayapi_v1beta1.AnnotationKey]gatewayapi_v1beta1.AnnotationValue{
+				TLSOptionSessionTickets: "enabled",
+			},
+			want: TLSOptions{},
+		},
+		"unknown keys and invalid values are ignored": {
+			options: map[gatewayapi_v1beta1.AnnotationKey]gatewayapi_v1beta1.AnnotationValue{
+				TLSOptionOCSPStaplePolicy:    "Always",
+				TLSOptionSessionTickets:      "disabled",
+				TLSOptionDefaultCertificate:  "yes",
+				"example.com/early-data":     "true",
+				"projectcontour.io/whatever": "1",
+			},
+			want: TLSOptions{
+				DisableSessionTickets: true,
+			},
+			wantIgnored: []string{
+				`TLS option "example.com/early-data" is not supported`,
+				`TLS option "projectcontour.io/default-certificate" has invalid value "yes", must be "true" or "false"`,
+				`TLS option "projectcontour.io/ocsp-staple-policy" has invalid value "Always", must be "LenientStapling", "StrictStapling" or "MustStaple"`,
+				`TLS option "projectcontour.io/whatever" is not supported`,
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, gotIgnored := ParseTLSOptions(tc.options)
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, tc.wantIgnored, gotIgnored)
+		})
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bootstrap creates the cluster-scoped prerequisites of the
+// gateway provisioner, such as CRDs, RBAC and GatewayClasses, so that
+// they can be set up by a one-shot Job ahead of the provisioner itself.
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/projectcontour/contour/internal/provisioner/objects"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// LoadManifests decodes the Kubernetes objects in the YAML file at path,
+// or in the .yaml and .yml files in the directory at path, in lexical
+// file order.
+func LoadManifests(path string) ([]*unstructured.Unstructured, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+
+		files = nil
+		for _, entry := range entries {
+			ext := filepath.Ext(entry.Name())
+			if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+				continue
+			}
+			files = append(files, filepath.Join(path, entry.Name()))
+		}
+		sort.Strings(files)
+	}
+
+	var objs []*unstructured.Unstructured
+	for _, file := range files {
+		contents, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		decoded, err := decodeManifests(contents)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", file, err)
+		}
+		objs = append(objs, decoded...)
+	}
+
+	return objs, nil
+}
+
+func decodeManifests(contents []byte) ([]*unstructured.Unstructured, error) {
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(contents), 4096)
+
+	var objs []*unstructured.Unstructured
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				return objs, nil
+			}
+			return nil, err
+		}
+
+		// Skip empty documents, e.g. a leading "---".
+		if len(obj.Object) == 0 {
+			continue
+		}
+		if obj.GetKind() == "" || obj.GetName() == "" {
+			return nil, fmt.Errorf("object is missing a kind or name")
+		}
+
+		objs = append(objs, obj)
+	}
+}
+
+// EnsureObjects creates the given objects in order, or updates them to
+// match if they already exist.
+func EnsureObjects(ctx context.Context, cli client.Client, objs []*unstructured.Unstructured) error {
+	for _, obj := range objs {
+		current := &unstructured.Unstructured{}
+		current.SetGroupVersionKind(obj.GroupVersionKind())
+
+		if err := objects.EnsureObject(ctx, cli, obj, updateObject, current); err != nil {
+			return fmt.Errorf("%s: %w", obj.GetKind(), err)
+		}
+	}
+
+	return nil
+}
+
+func updateObject(ctx context.Context, cli client.Client, current, desired *unstructured.Unstructured) error {
+	desired.SetResourceVersion(current.GetResourceVersion())
+	return cli.Update(ctx, desired)
+}
+
+// EnsureGatewayClass creates a GatewayClass with the given name for
+// controllerName, if it does not already exist. An existing GatewayClass
+// of the same name must already be for controllerName, since the field
+// is immutable.
+func EnsureGatewayClass(ctx context.Context, cli client.Client, name, controllerName string) error {
+	desired := &gatewayv1beta1.GatewayClass{}
+	desired.Name = name
+	desired.Spec.ControllerName = gatewayv1beta1.GatewayController(controllerName)
+
+	return objects.EnsureObject(ctx, cli, desired, checkGatewayClass, &gatewayv1beta1.GatewayClass{})
+}
+
+func checkGatewayClass(_ context.Context, _ client.Client, current, desired *gatewayv1beta1.GatewayClass) error {
+	if current.Spec.ControllerName != desired.Spec.ControllerName {
+		return fmt.Errorf("gatewayclass is for controller %q, not %q",
+			current.Spec.ControllerName, desired.Spec.ControllerName)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrap
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadManifests(t *testing.T) {
+	dir := t.TempDir()
+
+	write := func(name, contents string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o600))
+	}
+
+	write("01-rbac.yaml", `
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: contour-gateway-provisioner
+  namespace: projectcontour
+`)
+	write("00-common.yml", `---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: projectcontour
+---
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: projectcontour
+`)
+	write("README.md", "not a manifest")
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "nested.yaml"), 0o700))
+
+	objs, err := LoadManifests(dir)
+	require.NoError(t, err)
+
+	var got []string
+	for _, obj := range objs {
+		got = append(got, obj.GetKind()+"/"+obj.GetName())
+	}
+	assert.Equal(t, []string{
+		"Namespace/projectcontour",
+		"ConfigMap/settings",
+		"ServiceAccount/contour-gateway-provisioner",
+	}, got)
+
+	// A single file can be loaded too.
+	objs, err = LoadManifests(filepath.Join(dir, "01-rbac.yaml"))
+	require.NoError(t, err)
+	require.Len(t, objs, 1)
+	assert.Equal(t, "projectcontour", objs[0].GetNamespace())
+
+	write("02-invalid.yaml", `
+apiVersion: v1
+metadata:
+  name: no-kind
+`)
+	_, err = LoadManifests(dir)
+	assert.Error(t, err)
+
+	_, err = LoadManifests(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,92 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	contour_api_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// NewContourDeploymentWebhook registers a validating admission webhook
+// for ContourDeployments with mgr's webhook server. It rejects the
+// ContourDeployments that would otherwise only be reported as invalid
+// in the status of the GatewayClasses that reference them.
+func NewContourDeploymentWebhook(mgr manager.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&contour_api_v1alpha1.ContourDeployment{}).
+		WithValidator(&contourDeploymentValidator{}).
+		Complete()
+}
+
+// contourDeploymentValidator validates ContourDeployments on admission.
+type contourDeploymentValidator struct{}
+
+var _ admission.CustomValidator = &contourDeploymentValidator{}
+
+func (v *contourDeploymentValidator) ValidateCreate(_ context.Context, obj runtime.Object) error {
+	return v.validate(nil, obj)
+}
+
+func (v *contourDeploymentValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) error {
+	return v.validate(oldObj, newObj)
+}
+
+func (v *contourDeploymentValidator) ValidateDelete(_ context.Context, _ runtime.Object) error {
+	return nil
+}
+
+func (v *contourDeploymentValidator) validate(oldObj, obj runtime.Object) (err error) {
+	defer observeReconcile(kindContourDeployment, time.Now(), &err)
+
+	params, ok := obj.(*contour_api_v1alpha1.ContourDeployment)
+	if !ok {
+		return fmt.Errorf("expected a ContourDeployment but got %T", obj)
+	}
+
+	msgs := validateContourDeployment(params)
+
+	// The Envoy workload checks are only enforced here and not by the
+	// GatewayClass reconciler, so that ContourDeployments accepted by
+	// earlier versions keep working. An update is only rejected for
+	// workload problems the previous version of the object did not have.
+	if params.Spec.Envoy != nil {
+		existing := map[string]bool{}
+		if old, ok := oldObj.(*contour_api_v1alpha1.ContourDeployment); ok && old.Spec.Envoy != nil {
+			for _, msg := range validateEnvoyWorkload(old.Spec.Envoy) {
+				existing[msg] = true
+			}
+		}
+
+		for _, msg := range validateEnvoyWorkload(params.Spec.Envoy) {
+			if !existing[msg] {
+				msgs = append(msgs, msg)
+			}
+		}
+	}
+
+	if len(msgs) > 0 {
+		return errors.New(strings.Join(msgs, "; "))
+	}
+
+	return nil
+}
@@ -0,0 +1,433 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	contour_api_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
+	"github.com/projectcontour/contour/internal/ref"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestContourDeploymentValidator(t *testing.T) {
+	tests := map[string]struct {
+		spec    contour_api_v1alpha1.ContourDeploymentSpec
+		wantErr string
+	}{
+		"empty spec": {},
+		"valid Envoy Deployment": {
+			spec: contour_api_v1alpha1.ContourDeploymentSpec{
+				Envoy: &contour_api_v1alpha1.EnvoySettings{
+					WorkloadType: contour_api_v1alpha1.WorkloadTypeDeployment,
+					Deployment: &contour_api_v1alpha1.DeploymentSettings{
+						Replicas: 3,
+					},
+					NetworkPublishing: &contour_api_v1alpha1.NetworkPublishing{
+						Type:                  contour_api_v1alpha1.LoadBalancerServicePublishingType,
+						ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeLocal,
+					},
+				},
+			},
+		},
+		"valid NodePort DaemonSet with Local policy": {
+			spec: contour_api_v1alpha1.ContourDeploymentSpec{
+				Envoy: &contour_api_v1alpha1.EnvoySettings{
+					NetworkPublishing: &contour_api_v1alpha1.NetworkPublishing{
+						Type:                  contour_api_v1alpha1.NodePortServicePublishingType,
+						ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeLocal,
+					},
+				},
+			},
+		},
+		"valid Envoy ClientIP session affinity": {
+			spec: contour_api_v1alpha1.ContourDeploymentSpec{
+				Envoy: &contour_api_v1alpha1.EnvoySettings{
+					NetworkPublishing: &contour_api_v1alpha1.NetworkPublishing{
+						SessionAffinity:               corev1.ServiceAffinityClientIP,
+						SessionAffinityTimeoutSeconds: 600,
+					},
+				},
+			},
+		},
+		"invalid Envoy session affinity": {
+			spec: contour_api_v1alpha1.ContourDeploymentSpec{
+				Envoy: &contour_api_v1alpha1.EnvoySettings{
+					NetworkPublishing: &contour_api_v1alpha1.NetworkPublishing{
+						SessionAffinity:               "Cookie",
+						SessionAffinityTimeoutSeconds: 600,
+					},
+				},
+			},
+			wantErr: `invalid ContourDeployment spec.envoy.networkPublishing.sessionAffinity "Cookie", must be ClientIP or None; ` +
+				"invalid ContourDeployment spec.envoy.networkPublishing.sessionAffinityTimeoutSeconds 600, only valid with the ClientIP session affinity",
+		},
+		"invalid Envoy session affinity timeout": {
+			spec: contour_api_v1alpha1.ContourDeploymentSpec{
+				Envoy: &contour_api_v1alpha1.EnvoySettings{
+					NetworkPublishing: &contour_api_v1alpha1.NetworkPublishing{
+						SessionAffinity:               corev1.ServiceAffinityClientIP,
+						SessionAffinityTimeoutSeconds: 86401,
+					},
+				},
+			},
+			wantErr: "invalid ContourDeployment spec.envoy.networkPublishing.sessionAffinityTimeoutSeconds 86401, must be between 1 and 86400",
+		},
+		"Envoy replicas with a DaemonSet": {
+			spec: contour_api_v1alpha1.ContourDeploymentSpec{
+				Envoy: &contour_api_v1alpha1.EnvoySettings{
+					WorkloadType: contour_api_v1alpha1.WorkloadTypeDaemonSet,
+					Replicas:     3,
+				},
+			},
+			wantErr: "invalid ContourDeployment spec.envoy.replicas, must not be set when spec.envoy.workloadType is DaemonSet",
+		},
+		"Envoy Deployment settings with the default workload type": {
+			spec: contour_api_v1alpha1.ContourDeploymentSpec{
+				Envoy: &contour_api_v1alpha1.EnvoySettings{
+					Deployment: &contour_api_v1alpha1.DeploymentSettings{
+						Replicas: 3,
+					},
+				},
+			},
+			wantErr: "invalid ContourDeployment spec.envoy.deployment, must not be set when spec.envoy.workloadType is DaemonSet",
+		},
+		"Envoy DaemonSet settings with a Deployment": {
+			spec: contour_api_v1alpha1.ContourDeploymentSpec{
+				Envoy: &contour_api_v1alpha1.EnvoySettings{
+					WorkloadType: contour_api_v1alpha1.WorkloadTypeDeployment,
+					DaemonSet:    &contour_api_v1alpha1.DaemonSetSettings{},
+				},
+			},
+			wantErr: "invalid ContourDeployment spec.envoy.daemonSet, must not be set when spec.envoy.workloadType is Deployment",
+		},
+		"NodePort Deployment with Local policy": {
+			spec: contour_api_v1alpha1.ContourDeploymentSpec{
+				Envoy: &contour_api_v1alpha1.EnvoySettings{
+					WorkloadType: contour_api_v1alpha1.WorkloadTypeDeployment,
+					NetworkPublishing: &contour_api_v1alpha1.NetworkPublishing{
+						Type:                  contour_api_v1alpha1.NodePortServicePublishingType,
+						ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeLocal,
+					},
+				},
+			},
+			wantErr: "invalid ContourDeployment spec.envoy.networkPublishing, a NodePortService with a Local externalTrafficPolicy requires spec.envoy.workloadType DaemonSet",
+		},
+		"invalid workload type": {
+			spec: contour_api_v1alpha1.ContourDeploymentSpec{
+				Envoy: &contour_api_v1alpha1.EnvoySettings{
+					WorkloadType: "ReplicaSet",
+				},
+			},
+			wantErr: `invalid ContourDeployment spec.envoy.workloadType "ReplicaSet", must be DaemonSet or Deployment`,
+		},
+		"shutdown settings without the shutdown-manager": {
+			spec: contour_api_v1alpha1.ContourDeploymentSpec{
+				Envoy: &contour_api_v1alpha1.EnvoySettings{
+					Shutdown: &contour_api_v1alpha1.EnvoyShutdownSettings{
+						DrainDelay: "5s",
+					},
+					ShutdownManager: &contour_api_v1alpha1.ShutdownManagerSettings{
+						Enabled: ref.To(false),
+					},
+				},
+			},
+			wantErr: "invalid ContourDeployment spec.envoy.shutdown.drainDelay, must not be set when spec.envoy.shutdownManager.enabled is false",
+		},
+		"Kubernetes client burst lower than QPS": {
+			spec: contour_api_v1alpha1.ContourDeploymentSpec{
+				Contour: &contour_api_v1alpha1.ContourSettings{
+					KubernetesClientQPS:   20,
+					KubernetesClientBurst: 10,
+				},
+			},
+			wantErr: "invalid ContourDeployment spec.contour.kubernetesClientBurst 10, must not be lower than kubernetesClientQPS 20",
+		},
+		"negative Envoy minReadySeconds": {
+			spec: contour_api_v1alpha1.ContourDeploymentSpec{
+				Envoy: &contour_api_v1alpha1.EnvoySettings{
+					MinReadySeconds: -1,
+				},
+			},
+			wantErr: "invalid ContourDeployment spec.envoy.minReadySeconds -1, must not be negative",
+		},
+		"valid Envoy listener keepalive": {
+			spec: contour_api_v1alpha1.ContourDeploymentSpec{
+				Envoy: &contour_api_v1alpha1.EnvoySettings{
+					Listener: &contour_api_v1alpha1.EnvoyListenerSettings{
+						KeepAlive: &contour_api_v1alpha1.EnvoyListenerKeepAlive{
+							TCP:   &contour_api_v1alpha1.TCPKeepAlive{IdleTime: 60},
+							HTTP2: &contour_api_v1alpha1.HTTP2KeepAlive{Interval: "30s", Timeout: "5s"},
+						},
+					},
+				},
+			},
+		},
+		"invalid Envoy listener HTTP/2 keepalive interval": {
+			spec: contour_api_v1alpha1.ContourDeploymentSpec{
+				Envoy: &contour_api_v1alpha1.EnvoySettings{
+					Listener: &contour_api_v1alpha1.EnvoyListenerSettings{
+						KeepAlive: &contour_api_v1alpha1.EnvoyListenerKeepAlive{
+							HTTP2: &contour_api_v1alpha1.HTTP2KeepAlive{Interval: "-30s"},
+						},
+					},
+				},
+			},
+			wantErr: `invalid ContourDeployment spec.envoy.listener.keepAlive: invalid listener HTTP/2 keepalive interval "-30s": must be positive`,
+		},
+		"invalid Envoy listener HTTP/2 max concurrent streams": {
+			spec: contour_api_v1alpha1.ContourDeploymentSpec{
+				Envoy: &contour_api_v1alpha1.EnvoySettings{
+					Listener: &contour_api_v1alpha1.EnvoyListenerSettings{
+						HTTP2MaxConcurrentStreams: ref.To(uint32(0)),
+					},
+				},
+			},
+			wantErr: "invalid ContourDeployment spec.envoy.listener.http2MaxConcurrentStreams: invalid listener HTTP/2 max concurrent streams 0: must be between 1 and 2147483647",
+		},
+		"valid Envoy extra args": {
+			spec: contour_api_v1alpha1.ContourDeploymentSpec{
+				Envoy: &contour_api_v1alpha1.EnvoySettings{
+					ExtraArgs: []string{"--log-format %v", "--concurrency=2"},
+				},
+			},
+		},
+		"Envoy extra args managed by the provisioner": {
+			spec: contour_api_v1alpha1.ContourDeploymentSpec{
+				Envoy: &contour_api_v1alpha1.EnvoySettings{
+					ExtraArgs: []string{"--log-format %v", "--config-path /tmp/envoy.json", "--base-id=1"},
+				},
+			},
+			wantErr: `invalid ContourDeployment spec.envoy.extraArgs[1] "--config-path /tmp/envoy.json", --config-path is managed by the provisioner; ` +
+				`invalid ContourDeployment spec.envoy.extraArgs[2] "--base-id=1", --base-id is managed by the provisioner`,
+		},
+		"invalid allowed hostname suffix": {
+			spec: contour_api_v1alpha1.ContourDeploymentSpec{
+				Contour: &contour_api_v1alpha1.ContourSettings{
+					AllowedHostnameSuffixes: []string{"example.com", "*.example.com"},
+				},
+			},
+			wantErr: `invalid ContourDeployment spec.contour.allowedHostnameSuffixes "*.example.com", must be a DNS subdomain: ` +
+				`a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')`,
+		},
+		"invalid disabled route kind": {
+			spec: contour_api_v1alpha1.ContourDeploymentSpec{
+				Contour: &contour_api_v1alpha1.ContourSettings{
+					DisabledRouteKinds: []string{"TLSRoute", "TCPRoute"},
+				},
+			},
+			wantErr: `invalid ContourDeployment spec.contour.disabledRouteKinds "TCPRoute", must be HTTPRoute, TLSRoute or GRPCRoute`,
+		},
+		"valid extra init containers": {
+			spec: contour_api_v1alpha1.ContourDeploymentSpec{
+				Contour: &contour_api_v1alpha1.ContourSettings{
+					ExtraInitContainers: []corev1.Container{{Name: "fetch-secrets", Image: "vault:1.13"}},
+				},
+				Envoy: &contour_api_v1alpha1.EnvoySettings{
+					ExtraInitContainers: []corev1.Container{{Name: "fetch-secrets", Image: "vault:1.13"}},
+				},
+			},
+		},
+		"invalid Contour extra init containers": {
+			spec: contour_api_v1alpha1.ContourDeploymentSpec{
+				Contour: &contour_api_v1alpha1.ContourSettings{
+					ExtraInitContainers: []corev1.Container{
+						{Name: "contour", Image: "vault:1.13"},
+						{Name: "fetch-secrets"},
+					},
+				},
+			},
+			wantErr: `invalid ContourDeployment spec.contour.extraInitContainers[0] name "contour", already used by another container; ` +
+				`invalid ContourDeployment spec.contour.extraInitContainers[1] "fetch-secrets", image must be set`,
+		},
+		"invalid Envoy extra init containers": {
+			spec: contour_api_v1alpha1.ContourDeploymentSpec{
+				Envoy: &contour_api_v1alpha1.EnvoySettings{
+					ExtraInitContainers: []corev1.Container{
+						{Name: "envoy-initconfig", Image: "vault:1.13"},
+						{Name: "fetch-secrets", Image: "vault:1.13"},
+						{Name: "fetch-secrets", Image: "vault:1.13"},
+						{Name: "Fetch", Image: "vault:1.13"},
+					},
+				},
+			},
+			wantErr: `invalid ContourDeployment spec.envoy.extraInitContainers[0] name "envoy-initconfig", already used by another container; ` +
+				`invalid ContourDeployment spec.envoy.extraInitContainers[2] name "fetch-secrets", already used by another container; ` +
+				`invalid ContourDeployment spec.envoy.extraInitContainers[3] name "Fetch": ` +
+				`a lowercase RFC 1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')`,
+		},
+		"valid Envoy default backend": {
+			spec: contour_api_v1alpha1.ContourDeploymentSpec{
+				Envoy: &contour_api_v1alpha1.EnvoySettings{
+					DefaultBackend: &contour_api_v1alpha1.EnvoyDefaultBackend{
+						Name: "default-backend",
+						Port: 80,
+					},
+				},
+			},
+		},
+		"invalid Envoy default backend": {
+			spec: contour_api_v1alpha1.ContourDeploymentSpec{
+				Envoy: &contour_api_v1alpha1.EnvoySettings{
+					DefaultBackend: &contour_api_v1alpha1.EnvoyDefaultBackend{
+						Name: "Default-Backend",
+						Port: 0,
+					},
+				},
+			},
+			wantErr: `invalid ContourDeployment spec.envoy.defaultBackend.name "Default-Backend", must be a Service name: ` +
+				`a DNS-1035 label must consist of lower case alphanumeric characters or '-', start with an alphabetic character, and end with an alphanumeric character (e.g. 'my-name',  or 'abc-123', regex used for validation is '[a-z]([-a-z0-9]*[a-z0-9])?'); ` +
+				`invalid ContourDeployment spec.envoy.defaultBackend.port 0: must be between 1 and 65535, inclusive`,
+		},
+		"valid xDS TLS settings": {
+			spec: contour_api_v1alpha1.ContourDeploymentSpec{
+				XDSServer: &contour_api_v1alpha1.XDSServerSettings{
+					TLS: &contour_api_v1alpha1.XDSServerTLSSettings{
+						KeyType:             contour_api_v1alpha1.XDSServerKeyTypeECDSA,
+						CertificateLifetime: 30,
+					},
+				},
+			},
+		},
+		"invalid xDS TLS key type": {
+			spec: contour_api_v1alpha1.ContourDeploymentSpec{
+				XDSServer: &contour_api_v1alpha1.XDSServerSettings{
+					TLS: &contour_api_v1alpha1.XDSServerTLSSettings{
+						KeyType: "DSA",
+					},
+				},
+			},
+			wantErr: `invalid ContourDeployment spec.xdsServer.tls.keyType "DSA", must be RSA or ECDSA`,
+		},
+		"valid Envoy metrics": {
+			spec: contour_api_v1alpha1.ContourDeploymentSpec{
+				Envoy: &contour_api_v1alpha1.EnvoySettings{
+					Metrics: &contour_api_v1alpha1.EnvoyMetricsSettings{
+						FlushInterval: "10s",
+						HistogramBuckets: []contour_api_v1alpha1.EnvoyHistogramBuckets{{
+							Regex:   `cluster\..*\.upstream_rq_time`,
+							Buckets: []string{"0.5", "1", "5"},
+						}},
+					},
+				},
+			},
+		},
+		"sub-second Envoy stats flush interval": {
+			spec: contour_api_v1alpha1.ContourDeploymentSpec{
+				Envoy: &contour_api_v1alpha1.EnvoySettings{
+					Metrics: &contour_api_v1alpha1.EnvoyMetricsSettings{
+						FlushInterval: "500ms",
+					},
+				},
+			},
+			wantErr: `invalid ContourDeployment spec.envoy.metrics.flushInterval "500ms", must be at least 1s`,
+		},
+		"Envoy stats flush interval too long": {
+			spec: contour_api_v1alpha1.ContourDeploymentSpec{
+				Envoy: &contour_api_v1alpha1.EnvoySettings{
+					Metrics: &contour_api_v1alpha1.EnvoyMetricsSettings{
+						FlushInterval: "5m",
+					},
+				},
+			},
+			wantErr: `invalid ContourDeployment spec.envoy.metrics.flushInterval "5m", must be less than 5m`,
+		},
+		"invalid Envoy histogram buckets": {
+			spec: contour_api_v1alpha1.ContourDeploymentSpec{
+				Envoy: &contour_api_v1alpha1.EnvoySettings{
+					Metrics: &contour_api_v1alpha1.EnvoyMetricsSettings{
+						HistogramBuckets: []contour_api_v1alpha1.EnvoyHistogramBuckets{{
+							Regex:   "cluster(",
+							Buckets: []string{"5", "1", "-1"},
+						}},
+					},
+				},
+			},
+			wantErr: `invalid ContourDeployment spec.envoy.metrics.histogramBuckets[0].regex "cluster(": error parsing regexp: missing closing ): ` + "`cluster(`; " +
+				`invalid ContourDeployment spec.envoy.metrics.histogramBuckets[0].buckets[1] "1", must be greater than the previous bucket; ` +
+				`invalid ContourDeployment spec.envoy.metrics.histogramBuckets[0].buckets[2] "-1", must be a positive number`,
+		},
+		"several invalid values": {
+			spec: contour_api_v1alpha1.ContourDeploymentSpec{
+				Envoy: &contour_api_v1alpha1.EnvoySettings{
+					Replicas: 3,
+					LogLevel: "verbose",
+				},
+			},
+			wantErr: `invalid ContourDeployment spec.envoy.logLevel "verbose", must be trace, debug, info, warn, error, critical or off; ` +
+				"invalid ContourDeployment spec.envoy.replicas, must not be set when spec.envoy.workloadType is DaemonSet",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			params := &contour_api_v1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "projectcontour",
+					Name:      "contour-params",
+				},
+				Spec: tc.spec,
+			}
+
+			v := &contourDeploymentValidator{}
+			createErr := v.ValidateCreate(context.Background(), params)
+			updateErr := v.ValidateUpdate(context.Background(), &contour_api_v1alpha1.ContourDeployment{}, params)
+
+			if tc.wantErr == "" {
+				assert.NoError(t, createErr)
+				assert.NoError(t, updateErr)
+			} else {
+				require.EqualError(t, createErr, tc.wantErr)
+				require.EqualError(t, updateErr, tc.wantErr)
+			}
+
+			// Deleting is always allowed.
+			assert.NoError(t, v.ValidateDelete(context.Background(), params))
+		})
+	}
+}
+
+func TestContourDeploymentValidatorUpdateExistingEnvoyWorkload(t *testing.T) {
+	old := &contour_api_v1alpha1.ContourDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "projectcontour",
+			Name:      "contour-params",
+		},
+		Spec: contour_api_v1alpha1.ContourDeploymentSpec{
+			Envoy: &contour_api_v1alpha1.EnvoySettings{
+				Replicas: 3,
+			},
+		},
+	}
+
+	v := &contourDeploymentValidator{}
+
+	// An object stored before the workload checks existed can still be
+	// updated as long as the update does not add a new workload problem.
+	updated := old.DeepCopy()
+	updated.Spec.Envoy.LogLevel = contour_api_v1alpha1.DebugLog
+	assert.NoError(t, v.ValidateUpdate(context.Background(), old, updated))
+
+	updated = old.DeepCopy()
+	updated.Spec.Envoy.Deployment = &contour_api_v1alpha1.DeploymentSettings{}
+	require.EqualError(t, v.ValidateUpdate(context.Background(), old, updated),
+		"invalid ContourDeployment spec.envoy.deployment, must not be set when spec.envoy.workloadType is DaemonSet")
+
+	// Creating the same object is rejected.
+	require.Error(t, v.ValidateCreate(context.Background(), old))
+}
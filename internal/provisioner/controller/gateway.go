@@ -16,6 +16,8 @@ package controller
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
 	contour_api_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
@@ -30,10 +32,13 @@ import (
 	retryable "github.com/projectcontour/contour/internal/provisioner/retryableerror"
 
 	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -51,6 +56,7 @@ type gatewayReconciler struct {
 	contourImage      string
 	envoyImage        string
 	client            client.Client
+	recorder          record.EventRecorder
 	log               logr.Logger
 }
 
@@ -60,6 +66,7 @@ func NewGatewayController(mgr manager.Manager, gatewayController, contourImage,
 		contourImage:      contourImage,
 		envoyImage:        envoyImage,
 		client:            mgr.GetClient(),
+		recorder:          mgr.GetEventRecorderFor("contour-gateway-provisioner"),
 		log:               ctrl.Log.WithName("gateway-controller"),
 	}
 
@@ -155,7 +162,9 @@ func (r *gatewayReconciler) getGatewayClassGateways(gatewayClass client.Object)
 	return reconciles
 }
 
-func (r *gatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *gatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	defer observeReconcile(kindGateway, time.Now(), &err)
+
 	log := r.log.WithValues("gateway-namespace", req.Namespace, "gateway-name", req.Name)
 
 	gateway := &gatewayapi_v1beta1.Gateway{}
@@ -195,6 +204,14 @@ func (r *gatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 
 	contourModel := model.Default(gateway.Namespace, gateway.Name)
 
+	// The Envoy data plane, whose kind depends on the settings, is
+	// controlled by the Gateway so that the kind no longer called for
+	// can be pruned. The ContourConfiguration is controlled by the
+	// Gateway too, so that it is garbage collected with it.
+	contourModel.OwnerReferences = []metav1.OwnerReference{
+		*metav1.NewControllerRef(gateway, gatewayapi_v1beta1.SchemeGroupVersion.WithKind("Gateway")),
+	}
+
 	// Currently, only a single address of type IPAddress or Hostname
 	// is supported; anything else will be ignored.
 	if len(gateway.Spec.Addresses) > 0 {
@@ -260,16 +277,26 @@ func (r *gatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 				}
 
 				contourModel.Spec.NodePlacement.Contour = &model.ContourNodePlacement{
-					NodeSelector: nodePlacement.NodeSelector,
-					Tolerations:  nodePlacement.Tolerations,
+					NodeSelector:              nodePlacement.NodeSelector,
+					Tolerations:               nodePlacement.Tolerations,
+					TopologySpreadConstraints: nodePlacement.TopologySpreadConstraints,
 				}
 			}
 
+			contourModel.Spec.ContourPodLabels = contourParams.PodLabels
+
 			contourModel.Spec.ContourResources = contourParams.Resources
 
 			contourModel.Spec.ContourLogLevel = contourParams.LogLevel
 
 			contourModel.Spec.KubernetesLogLevel = contourParams.KubernetesLogLevel
+			contourModel.Spec.KubernetesClientQPS = contourParams.KubernetesClientQPS
+			contourModel.Spec.KubernetesClientBurst = contourParams.KubernetesClientBurst
+			contourModel.Spec.ContourUseEndpointSlices = contourParams.UseEndpointSlices
+			contourModel.Spec.EnableExternalNameService = contourParams.EnableExternalNameService
+			contourModel.Spec.ContourAllowedHostnameSuffixes = contourParams.AllowedHostnameSuffixes
+			contourModel.Spec.ContourDisabledRouteKinds = contourParams.DisabledRouteKinds
+			contourModel.Spec.ContourExtraInitContainers = contourParams.ExtraInitContainers
 
 			if contourParams.Deployment != nil &&
 				contourParams.Deployment.Strategy != nil {
@@ -323,11 +350,31 @@ func (r *gatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 					}
 				}
 
+				for _, custom := range networkPublishing.Ports {
+					for i := range contourModel.Spec.NetworkPublishing.Envoy.Ports {
+						port := &contourModel.Spec.NetworkPublishing.Envoy.Ports[i]
+						if port.Name != custom.Listener {
+							continue
+						}
+						port.CustomName = custom.Name
+						if custom.TargetPort > 0 {
+							port.ContainerPort = custom.TargetPort
+						}
+						if custom.NodePort > 0 {
+							port.NodePort = custom.NodePort
+						}
+					}
+				}
+
 				if networkPublishing.ExternalTrafficPolicy != "" {
 					contourModel.Spec.NetworkPublishing.Envoy.ExternalTrafficPolicy = networkPublishing.ExternalTrafficPolicy
 				}
 
+				contourModel.Spec.NetworkPublishing.Envoy.SessionAffinity = networkPublishing.SessionAffinity
+				contourModel.Spec.NetworkPublishing.Envoy.SessionAffinityTimeoutSeconds = networkPublishing.SessionAffinityTimeoutSeconds
 				contourModel.Spec.NetworkPublishing.Envoy.ServiceAnnotations = networkPublishing.ServiceAnnotations
+				contourModel.Spec.NetworkPublishing.Envoy.ProxyProtocol = networkPublishing.ProxyProtocol
+				contourModel.Spec.NetworkPublishing.Envoy.ExternalHostname = networkPublishing.ExternalHostname
 			}
 
 			// Node placement
@@ -337,26 +384,68 @@ func (r *gatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 				}
 
 				contourModel.Spec.NodePlacement.Envoy = &model.EnvoyNodePlacement{
-					NodeSelector: nodePlacement.NodeSelector,
-					Tolerations:  nodePlacement.Tolerations,
+					NodeSelector:              nodePlacement.NodeSelector,
+					Tolerations:               nodePlacement.Tolerations,
+					TopologySpreadConstraints: nodePlacement.TopologySpreadConstraints,
 				}
 			}
 
 			// volume mount
 			contourModel.Spec.EnvoyExtraVolumeMounts = append(contourModel.Spec.EnvoyExtraVolumeMounts, envoyParams.ExtraVolumeMounts...)
 			contourModel.Spec.EnvoyExtraVolumes = append(contourModel.Spec.EnvoyExtraVolumes, envoyParams.ExtraVolumes...)
+			contourModel.Spec.EnvoyExtraInitContainers = envoyParams.ExtraInitContainers
 
 			// Pod Annotations
 			for k, v := range envoyParams.PodAnnotations {
 				contourModel.Spec.EnvoyPodAnnotations[k] = v
 			}
 
+			contourModel.Spec.EnvoyPodLabels = envoyParams.PodLabels
+
 			contourModel.Spec.EnvoyResources = envoyParams.Resources
+			contourModel.Spec.EnvoyPodSecurityContext = envoyParams.PodSecurityContext
+			contourModel.Spec.EnvoyReadOnlyRootFilesystem = envoyParams.ReadOnlyRootFilesystem
+			contourModel.Spec.EnvoyMinReadySeconds = envoyParams.MinReadySeconds
 
 			if envoyParams.LogLevel != "" {
 				contourModel.Spec.EnvoyLogLevel = envoyParams.LogLevel
 			}
 
+			contourModel.Spec.EnvoyExtraArgs = envoyParams.ExtraArgs
+
+			contourModel.Spec.EnvoyDrainStrategy = envoyParams.DrainStrategy
+
+			// Note, the durations have already been validated by the
+			// gatewayclass controller.
+			if shutdown := envoyParams.Shutdown; shutdown != nil {
+				contourModel.Spec.EnvoyShutdown = model.EnvoyShutdownSettings{
+					DrainTimeout:       parseDuration(shutdown.DrainTimeout),
+					DrainDelay:         parseDuration(shutdown.DrainDelay),
+					CheckDelay:         parseDuration(shutdown.CheckDelay),
+					CheckInterval:      parseDuration(shutdown.CheckInterval),
+					MinOpenConnections: shutdown.MinOpenConnections,
+				}
+			}
+
+			if envoyParams.ShutdownManager != nil && envoyParams.ShutdownManager.Enabled != nil {
+				contourModel.Spec.EnvoyShutdownManagerDisabled = !*envoyParams.ShutdownManager.Enabled
+			}
+
+			contourModel.Spec.EnvoyDefaultResponseHeaders = envoyParams.DefaultResponseHeaders
+			contourModel.Spec.EnvoyDefaultLoadBalancerPolicy = envoyParams.DefaultLoadBalancerPolicy
+
+			if metrics := envoyParams.Metrics; metrics != nil {
+				contourModel.Spec.EnvoyStatsFlushInterval = parseDuration(metrics.FlushInterval)
+				contourModel.Spec.EnvoyHistogramBuckets = metrics.HistogramBuckets
+			}
+
+			contourModel.Spec.EnvoyDefaultBackend = envoyParams.DefaultBackend
+
+			if envoyParams.Listener != nil {
+				contourModel.Spec.EnvoyListenerKeepAlive = envoyParams.Listener.KeepAlive
+				contourModel.Spec.EnvoyListenerHTTP2MaxConcurrentStreams = envoyParams.Listener.HTTP2MaxConcurrentStreams
+			}
+
 			if envoyParams.WorkloadType == contour_api_v1alpha1.WorkloadTypeDeployment &&
 				envoyParams.Deployment != nil &&
 				envoyParams.Deployment.Strategy != nil {
@@ -370,17 +459,30 @@ func (r *gatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 			}
 
 		}
+
+		if xdsParams := gatewayClassParams.Spec.XDSServer; xdsParams != nil && xdsParams.TLS != nil {
+			contourModel.Spec.XDSServerKeyType = xdsParams.TLS.KeyType
+			contourModel.Spec.XDSServerCertificateLifetime = xdsParams.TLS.CertificateLifetime
+		}
 	}
 
-	if errs := r.ensureContour(ctx, contourModel, log); len(errs) > 0 {
+	xdsSecretsRotation, errs := r.ensureContour(ctx, gateway, contourModel, log)
+	if len(errs) > 0 {
 		return ctrl.Result{}, fmt.Errorf("failed to ensure resources for gateway: %w", retryable.NewMaybeRetryableAggregate(errs))
 	}
 
+	// Nothing else may trigger a reconcile before the xDS TLS secrets
+	// must be rotated, so requeue the gateway for then.
+	var result ctrl.Result
+	if !xdsSecretsRotation.IsZero() {
+		result.RequeueAfter = time.Until(xdsSecretsRotation)
+	}
+
 	var newConds []metav1.Condition
 	for _, cond := range gateway.Status.Conditions {
 		if cond.Type == string(gatewayapi_v1beta1.GatewayConditionAccepted) {
 			if cond.Status == metav1.ConditionTrue {
-				return ctrl.Result{}, nil
+				return result, nil
 			}
 
 			continue
@@ -405,10 +507,12 @@ func (r *gatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return ctrl.Result{}, fmt.Errorf("failed to set gateway %s Accepted condition: %w", req, err)
 	}
 
-	return ctrl.Result{}, nil
+	return result, nil
 }
 
-func (r *gatewayReconciler) ensureContour(ctx context.Context, contour *model.Contour, log logr.Logger) []error {
+// ensureContour ensures the resources of the gateway's Contour, and
+// returns the time at which its xDS TLS secrets must be rotated.
+func (r *gatewayReconciler) ensureContour(ctx context.Context, gateway *gatewayapi_v1beta1.Gateway, contour *model.Contour, log logr.Logger) (time.Time, []error) {
 	var errs []error
 
 	handleResult := func(resource string, err error) {
@@ -422,13 +526,18 @@ func (r *gatewayReconciler) ensureContour(ctx context.Context, contour *model.Co
 	handleResult("rbac", rbac.EnsureRBAC(ctx, r.client, contour))
 
 	if len(errs) > 0 {
-		return errs
+		return time.Time{}, errs
 	}
 
 	handleResult("contour config", contourconfig.EnsureContourConfig(ctx, r.client, contour))
-	handleResult("xDS TLS secrets", secret.EnsureXDSSecrets(ctx, r.client, contour, r.contourImage))
-	handleResult("deployment", deployment.EnsureDeployment(ctx, r.client, contour, r.contourImage))
-	handleResult("envoy data plane", dataplane.EnsureDataPlane(ctx, r.client, contour, r.contourImage, r.envoyImage))
+	xdsSecretsRotation, err := secret.EnsureXDSSecrets(ctx, r.client, contour, r.contourImage)
+	handleResult("xDS TLS secrets", err)
+	recordRollout := func(workload client.Object, changes []string) {
+		r.recordRollout(gateway, workload, changes)
+	}
+
+	handleResult("deployment", deployment.EnsureDeployment(ctx, r.client, contour, r.contourImage, recordRollout))
+	handleResult("envoy data plane", dataplane.EnsureDataPlane(ctx, r.client, contour, r.contourImage, r.envoyImage, recordRollout))
 	handleResult("contour service", service.EnsureContourService(ctx, r.client, contour))
 
 	switch contour.Spec.NetworkPublishing.Envoy.Type {
@@ -436,7 +545,26 @@ func (r *gatewayReconciler) ensureContour(ctx context.Context, contour *model.Co
 		handleResult("envoy service", service.EnsureEnvoyService(ctx, r.client, contour))
 	}
 
-	return errs
+	return xdsSecretsRotation, errs
+}
+
+// recordRollout records an event on the gateway describing the changes
+// that roll out the pods of one of its workloads.
+func (r *gatewayReconciler) recordRollout(gateway *gatewayapi_v1beta1.Gateway, workload client.Object, changes []string) {
+	if r.recorder == nil {
+		return
+	}
+
+	kind := "workload"
+	switch workload.(type) {
+	case *appsv1.Deployment:
+		kind = "deployment"
+	case *appsv1.DaemonSet:
+		kind = "daemonset"
+	}
+
+	r.recorder.Eventf(gateway, corev1.EventTypeNormal, "RollingUpdate", "Rolling update of %s %s/%s: %s",
+		kind, workload.GetNamespace(), workload.GetName(), strings.Join(changes, ", "))
 }
 
 func (r *gatewayReconciler) ensureContourDeleted(ctx context.Context, contour *model.Contour, log logr.Logger) []error {
@@ -488,3 +616,9 @@ func (r *gatewayReconciler) getGatewayClassParams(ctx context.Context, gatewayCl
 	return gcParams, nil
 
 }
+
+// parseDuration returns the duration in s, or zero if it is unset.
+func parseDuration(s string) time.Duration {
+	d, _ := time.ParseDuration(s)
+	return d
+}
@@ -15,7 +15,11 @@ package controller
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/tls"
+	"crypto/x509"
 	"testing"
+	"time"
 
 	"github.com/go-logr/logr"
 	contourv1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
@@ -26,8 +30,10 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -387,6 +393,7 @@ func TestGatewayReconcile(t *testing.T) {
 				require.NoError(t, r.client.Get(context.Background(), keyFor(contourConfig), contourConfig))
 
 				want := contourv1alpha1.ContourConfigurationSpec{
+					UseEndpointSlices:         ref.To(true),
 					EnableExternalNameService: ref.To(true),
 					Gateway: &contourv1alpha1.GatewayConfig{
 						GatewayRef: &contourv1alpha1.NamespacedName{
@@ -461,6 +468,7 @@ func TestGatewayReconcile(t *testing.T) {
 				require.NoError(t, r.client.Get(context.Background(), keyFor(contourConfig), contourConfig))
 
 				want := contourv1alpha1.ContourConfigurationSpec{
+					UseEndpointSlices: ref.To(true),
 					Gateway: &contourv1alpha1.GatewayConfig{
 						GatewayRef: &contourv1alpha1.NamespacedName{
 							Namespace: gw.Name,
@@ -524,6 +532,7 @@ func TestGatewayReconcile(t *testing.T) {
 				require.NoError(t, r.client.Get(context.Background(), keyFor(contourConfig), contourConfig))
 
 				want := contourv1alpha1.ContourConfigurationSpec{
+					UseEndpointSlices: ref.To(true),
 					Gateway: &contourv1alpha1.GatewayConfig{
 						GatewayRef: &contourv1alpha1.NamespacedName{
 							Namespace: gw.Name,
@@ -769,6 +778,54 @@ func TestGatewayReconcile(t *testing.T) {
 				assert.EqualValues(t, appsv1.RecreateDeploymentStrategyType, deploy.Spec.Strategy.Type)
 			},
 		},
+		"If ContourDeployment.Spec.Contour.Deployment.Strategy is RollingUpdate, the Contour deployment gets maxSurge and maxUnavailable": {
+			gatewayClass: reconcilableGatewayClassWithParams("gatewayclass-1", controller),
+			gatewayClassParams: &contourv1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "projectcontour",
+					Name:      "gatewayclass-1-params",
+				},
+				Spec: contourv1alpha1.ContourDeploymentSpec{
+					Contour: &contourv1alpha1.ContourSettings{
+						Deployment: &contourv1alpha1.DeploymentSettings{
+							Strategy: &appsv1.DeploymentStrategy{
+								Type: appsv1.RollingUpdateDeploymentStrategyType,
+								RollingUpdate: &appsv1.RollingUpdateDeployment{
+									MaxSurge:       ref.To(intstr.FromInt(1)),
+									MaxUnavailable: ref.To(intstr.FromInt(0)),
+								},
+							},
+						},
+					},
+				},
+			},
+			gateway: &gatewayv1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "gateway-1",
+					Name:      "gateway-1",
+				},
+				Spec: gatewayv1beta1.GatewaySpec{
+					GatewayClassName: gatewayv1beta1.ObjectName("gatewayclass-1"),
+				},
+			},
+			assertions: func(t *testing.T, r *gatewayReconciler, gw *gatewayv1beta1.Gateway, reconcileErr error) {
+				require.NoError(t, reconcileErr)
+
+				// Verify the Deployment has been created
+				deploy := &appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "gateway-1",
+						Name:      "contour-gateway-1",
+					},
+				}
+				require.NoError(t, r.client.Get(context.Background(), keyFor(deploy), deploy))
+
+				assert.EqualValues(t, appsv1.RollingUpdateDeploymentStrategyType, deploy.Spec.Strategy.Type)
+				require.NotNil(t, deploy.Spec.Strategy.RollingUpdate)
+				assert.Equal(t, intstr.FromInt(1), *deploy.Spec.Strategy.RollingUpdate.MaxSurge)
+				assert.Equal(t, intstr.FromInt(0), *deploy.Spec.Strategy.RollingUpdate.MaxUnavailable)
+			},
+		},
 		"If ContourDeployment.Spec.Contour.NodePlacement is not specified, the Contour deployment has no node selector or tolerations set": {
 			gatewayClass: reconcilableGatewayClassWithParams("gatewayclass-1", controller),
 			gatewayClassParams: &contourv1alpha1.ContourDeployment{
@@ -930,6 +987,13 @@ func TestGatewayReconcile(t *testing.T) {
 									Value:    "toleration-value-1",
 								},
 							},
+							TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+								{
+									MaxSkew:           1,
+									TopologyKey:       "topology.kubernetes.io/zone",
+									WhenUnsatisfiable: corev1.ScheduleAnyway,
+								},
+							},
 						},
 					},
 				},
@@ -969,6 +1033,14 @@ func TestGatewayReconcile(t *testing.T) {
 						Value:    "toleration-value-1",
 					},
 				}, daemonset.Spec.Template.Spec.Tolerations)
+				assert.Equal(t, []corev1.TopologySpreadConstraint{
+					{
+						MaxSkew:           1,
+						TopologyKey:       "topology.kubernetes.io/zone",
+						WhenUnsatisfiable: corev1.ScheduleAnyway,
+						LabelSelector:     daemonset.Spec.Selector,
+					},
+				}, daemonset.Spec.Template.Spec.TopologySpreadConstraints)
 			},
 		},
 		"If ContourDeployment.Spec.Envoy.NetworkPublishing is not specified, the Envoy service defaults to a LoadBalancer with no annotations": {
@@ -1021,8 +1093,10 @@ func TestGatewayReconcile(t *testing.T) {
 				Spec: contourv1alpha1.ContourDeploymentSpec{
 					Envoy: &contourv1alpha1.EnvoySettings{
 						NetworkPublishing: &contourv1alpha1.NetworkPublishing{
-							Type:                  contourv1alpha1.NodePortServicePublishingType,
-							ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeCluster,
+							Type:                          contourv1alpha1.NodePortServicePublishingType,
+							ExternalTrafficPolicy:         corev1.ServiceExternalTrafficPolicyTypeCluster,
+							SessionAffinity:               corev1.ServiceAffinityClientIP,
+							SessionAffinityTimeoutSeconds: 600,
 							ServiceAnnotations: map[string]string{
 								"key-1": "val-1",
 								"key-2": "val-2",
@@ -1084,6 +1158,9 @@ func TestGatewayReconcile(t *testing.T) {
 				require.NoError(t, r.client.Get(context.Background(), keyFor(svc), svc))
 				assert.Equal(t, corev1.ServiceExternalTrafficPolicyTypeCluster, svc.Spec.ExternalTrafficPolicy)
 				assert.Equal(t, corev1.ServiceTypeNodePort, svc.Spec.Type)
+				assert.Equal(t, corev1.ServiceAffinityClientIP, svc.Spec.SessionAffinity)
+				require.NotNil(t, svc.Spec.SessionAffinityConfig)
+				assert.Equal(t, ref.To(int32(600)), svc.Spec.SessionAffinityConfig.ClientIP.TimeoutSeconds)
 				require.Len(t, svc.Annotations, 2)
 				assert.Equal(t, "val-1", svc.Annotations["key-1"])
 				assert.Equal(t, "val-2", svc.Annotations["key-2"])
@@ -1095,7 +1172,7 @@ func TestGatewayReconcile(t *testing.T) {
 				assert.Equal(t, int32(443), svc.Spec.Ports[1].Port)
 			},
 		},
-		"If ContourDeployment.Spec.Envoy.WorkloadType is set to Deployment, an Envoy deployment is provisioned with the specified number of replicas": {
+		"If ContourDeployment.Spec.Envoy.NetworkPublishing.Ports is specified, the Envoy service ports are customized": {
 			gatewayClass: reconcilableGatewayClassWithParams("gatewayclass-1", controller),
 			gatewayClassParams: &contourv1alpha1.ContourDeployment{
 				ObjectMeta: metav1.ObjectMeta{
@@ -1104,8 +1181,21 @@ func TestGatewayReconcile(t *testing.T) {
 				},
 				Spec: contourv1alpha1.ContourDeploymentSpec{
 					Envoy: &contourv1alpha1.EnvoySettings{
-						WorkloadType: contourv1alpha1.WorkloadTypeDeployment,
-						Replicas:     7,
+						NetworkPublishing: &contourv1alpha1.NetworkPublishing{
+							Type: contourv1alpha1.NodePortServicePublishingType,
+							Ports: []contourv1alpha1.EnvoyServicePort{
+								{
+									Listener:   "http",
+									Name:       "web",
+									TargetPort: 8081,
+								},
+								{
+									Listener: "https",
+									Name:     "websecure",
+									NodePort: 30443,
+								},
+							},
+						},
 					},
 				},
 			},
@@ -1116,40 +1206,73 @@ func TestGatewayReconcile(t *testing.T) {
 				},
 				Spec: gatewayv1beta1.GatewaySpec{
 					GatewayClassName: gatewayv1beta1.ObjectName("gatewayclass-1"),
+					Listeners: []gatewayv1beta1.Listener{
+						{
+							Name:     gatewayv1beta1.SectionName("http"),
+							Port:     gatewayv1beta1.PortNumber(30080),
+							Protocol: gatewayv1beta1.HTTPProtocolType,
+						},
+						{
+							Name:     gatewayv1beta1.SectionName("https"),
+							Port:     gatewayv1beta1.PortNumber(30001),
+							Protocol: gatewayv1beta1.HTTPSProtocolType,
+							TLS: &gatewayv1beta1.GatewayTLSConfig{
+								Mode: ref.To(gatewayv1beta1.TLSModeTerminate),
+							},
+						},
+					},
 				},
 			},
 			assertions: func(t *testing.T, r *gatewayReconciler, gw *gatewayv1beta1.Gateway, reconcileErr error) {
 				require.NoError(t, reconcileErr)
 
-				// Verify the Gateway has a "Accepted: true" condition
-				require.NoError(t, r.client.Get(context.Background(), keyFor(gw), gw))
-				require.Len(t, gw.Status.Conditions, 1)
-				assert.Equal(t, string(gatewayv1beta1.GatewayConditionAccepted), gw.Status.Conditions[0].Type)
-				assert.Equal(t, metav1.ConditionTrue, gw.Status.Conditions[0].Status)
-
-				// Verify the deployment has been created
-				deploy := &appsv1.Deployment{
+				svc := &corev1.Service{
 					ObjectMeta: metav1.ObjectMeta{
 						Namespace: "gateway-1",
 						Name:      "envoy-gateway-1",
 					},
 				}
-				require.NoError(t, r.client.Get(context.Background(), keyFor(deploy), deploy))
-				assert.EqualValues(t, 7, *deploy.Spec.Replicas)
+				require.NoError(t, r.client.Get(context.Background(), keyFor(svc), svc))
+				require.Len(t, svc.Spec.Ports, 2)
+				assert.Equal(t, "web", svc.Spec.Ports[0].Name)
+				assert.Equal(t, int32(80), svc.Spec.Ports[0].Port)
+				assert.Equal(t, int32(30080), svc.Spec.Ports[0].NodePort)
+				assert.Equal(t, int32(8081), svc.Spec.Ports[0].TargetPort.IntVal)
+				assert.Equal(t, "websecure", svc.Spec.Ports[1].Name)
+				assert.Equal(t, int32(443), svc.Spec.Ports[1].Port)
+				assert.Equal(t, int32(30443), svc.Spec.Ports[1].NodePort)
+				assert.Equal(t, int32(8443), svc.Spec.Ports[1].TargetPort.IntVal)
+
+				// The Envoy listener must bind to the customized target port.
+				contourConfig := &contourv1alpha1.ContourConfiguration{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: gw.Namespace,
+						Name:      "contourconfig-" + gw.Name,
+					},
+				}
+				require.NoError(t, r.client.Get(context.Background(), keyFor(contourConfig), contourConfig))
+				require.NotNil(t, contourConfig.Spec.Envoy.HTTPListener)
+				assert.Equal(t, 8081, contourConfig.Spec.Envoy.HTTPListener.Port)
+				assert.Nil(t, contourConfig.Spec.Envoy.HTTPSListener)
 
-				// Verify that a daemonset has *not* been created
 				ds := &appsv1.DaemonSet{
 					ObjectMeta: metav1.ObjectMeta{
 						Namespace: "gateway-1",
 						Name:      "envoy-gateway-1",
 					},
 				}
-				err := r.client.Get(context.Background(), keyFor(ds), ds)
-				assert.True(t, errors.IsNotFound(err))
+				require.NoError(t, r.client.Get(context.Background(), keyFor(ds), ds))
+				var envoyPorts []corev1.ContainerPort
+				for _, c := range ds.Spec.Template.Spec.Containers {
+					if c.Name == "envoy" {
+						envoyPorts = c.Ports
+					}
+				}
+				assert.Contains(t, envoyPorts, corev1.ContainerPort{Name: "web", ContainerPort: 8081, Protocol: corev1.ProtocolTCP})
+				assert.Contains(t, envoyPorts, corev1.ContainerPort{Name: "websecure", ContainerPort: 8443, Protocol: corev1.ProtocolTCP})
 			},
 		},
-		"If ContourDeployment.Spec.Envoy.WorkloadType is set to Deployment," +
-			"an Envoy deployment is provisioned with the settings come from DeployemntSettings": {
+		"If ContourDeployment.Spec.Envoy.NetworkPublishing.ProxyProtocol is true, the Envoy listeners expect PROXY protocol": {
 			gatewayClass: reconcilableGatewayClassWithParams("gatewayclass-1", controller),
 			gatewayClassParams: &contourv1alpha1.ContourDeployment{
 				ObjectMeta: metav1.ObjectMeta{
@@ -1158,13 +1281,8 @@ func TestGatewayReconcile(t *testing.T) {
 				},
 				Spec: contourv1alpha1.ContourDeploymentSpec{
 					Envoy: &contourv1alpha1.EnvoySettings{
-						WorkloadType: contourv1alpha1.WorkloadTypeDeployment,
-						Replicas:     7,
-						Deployment: &contourv1alpha1.DeploymentSettings{
-							Replicas: 6,
-							Strategy: &appsv1.DeploymentStrategy{
-								Type: appsv1.RecreateDeploymentStrategyType,
-							},
+						NetworkPublishing: &contourv1alpha1.NetworkPublishing{
+							ProxyProtocol: true,
 						},
 					},
 				},
@@ -1181,40 +1299,57 @@ func TestGatewayReconcile(t *testing.T) {
 			assertions: func(t *testing.T, r *gatewayReconciler, gw *gatewayv1beta1.Gateway, reconcileErr error) {
 				require.NoError(t, reconcileErr)
 
-				// Verify the Gateway has an "Accepted: true" condition
-				require.NoError(t, r.client.Get(context.Background(), keyFor(gw), gw))
-				require.Len(t, gw.Status.Conditions, 1)
-				assert.Equal(t, string(gatewayv1beta1.GatewayConditionAccepted), gw.Status.Conditions[0].Type)
-				assert.Equal(t, metav1.ConditionTrue, gw.Status.Conditions[0].Status)
-
-				// Verify the deployment has been created
-				deploy := &appsv1.Deployment{
+				contourConfig := &contourv1alpha1.ContourConfiguration{
 					ObjectMeta: metav1.ObjectMeta{
-						Namespace: "gateway-1",
-						Name:      "envoy-gateway-1",
+						Namespace: gw.Namespace,
+						Name:      "contourconfig-" + gw.Name,
 					},
 				}
-				require.NoError(t, r.client.Get(context.Background(), keyFor(deploy), deploy))
-
-				assert.NotNil(t, deploy.Spec.Replicas)
-				assert.EqualValues(t, 6, *deploy.Spec.Replicas)
-
-				assert.NotNil(t, deploy.Spec.Strategy)
-				assert.EqualValues(t, appsv1.RecreateDeploymentStrategyType, deploy.Spec.Strategy.Type)
+				require.NoError(t, r.client.Get(context.Background(), keyFor(contourConfig), contourConfig))
+				require.NotNil(t, contourConfig.Spec.Envoy)
+				require.NotNil(t, contourConfig.Spec.Envoy.Listener)
+				assert.Equal(t, ref.To(true), contourConfig.Spec.Envoy.Listener.UseProxyProto)
+			},
+		},
+		"If ContourDeployment.Spec.Envoy.NetworkPublishing.ExternalHostname is specified, it is the Gateway's status address": {
+			gatewayClass: reconcilableGatewayClassWithParams("gatewayclass-1", controller),
+			gatewayClassParams: &contourv1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "projectcontour",
+					Name:      "gatewayclass-1-params",
+				},
+				Spec: contourv1alpha1.ContourDeploymentSpec{
+					Envoy: &contourv1alpha1.EnvoySettings{
+						NetworkPublishing: &contourv1alpha1.NetworkPublishing{
+							ExternalHostname: "gateway.projectcontour.io",
+						},
+					},
+				},
+			},
+			gateway: &gatewayv1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "gateway-1",
+					Name:      "gateway-1",
+				},
+				Spec: gatewayv1beta1.GatewaySpec{
+					GatewayClassName: gatewayv1beta1.ObjectName("gatewayclass-1"),
+				},
+			},
+			assertions: func(t *testing.T, r *gatewayReconciler, gw *gatewayv1beta1.Gateway, reconcileErr error) {
+				require.NoError(t, reconcileErr)
 
-				// Verify that a daemonset has *not* been created
-				ds := &appsv1.DaemonSet{
+				contourConfig := &contourv1alpha1.ContourConfiguration{
 					ObjectMeta: metav1.ObjectMeta{
-						Namespace: "gateway-1",
-						Name:      "envoy-gateway-1",
+						Namespace: gw.Namespace,
+						Name:      "contourconfig-" + gw.Name,
 					},
 				}
-				err := r.client.Get(context.Background(), keyFor(ds), ds)
-				assert.True(t, errors.IsNotFound(err))
+				require.NoError(t, r.client.Get(context.Background(), keyFor(contourConfig), contourConfig))
+				require.NotNil(t, contourConfig.Spec.Ingress)
+				assert.Equal(t, "gateway.projectcontour.io", contourConfig.Spec.Ingress.StatusAddress)
 			},
 		},
-
-		"If ContourDeployment.Spec.Envoy.PodAnnotations is specified, the Envoy pods' have annotations for prometheus & user-defined": {
+		"If ContourDeployment.Spec.Contour.UseEndpointSlices is false, Contour watches Endpoints": {
 			gatewayClass: reconcilableGatewayClassWithParams("gatewayclass-1", controller),
 			gatewayClassParams: &contourv1alpha1.ContourDeployment{
 				ObjectMeta: metav1.ObjectMeta{
@@ -1222,10 +1357,8 @@ func TestGatewayReconcile(t *testing.T) {
 					Name:      "gatewayclass-1-params",
 				},
 				Spec: contourv1alpha1.ContourDeploymentSpec{
-					Envoy: &contourv1alpha1.EnvoySettings{
-						PodAnnotations: map[string]string{
-							"key": "val",
-						},
+					Contour: &contourv1alpha1.ContourSettings{
+						UseEndpointSlices: ref.To(false),
 					},
 				},
 			},
@@ -1241,26 +1374,17 @@ func TestGatewayReconcile(t *testing.T) {
 			assertions: func(t *testing.T, r *gatewayReconciler, gw *gatewayv1beta1.Gateway, reconcileErr error) {
 				require.NoError(t, reconcileErr)
 
-				// Verify the Gateway has a "Accepted: true" condition
-				require.NoError(t, r.client.Get(context.Background(), keyFor(gw), gw))
-				require.Len(t, gw.Status.Conditions, 1)
-				assert.Equal(t, string(gatewayv1beta1.GatewayConditionAccepted), gw.Status.Conditions[0].Type)
-				assert.Equal(t, metav1.ConditionTrue, gw.Status.Conditions[0].Status)
-
-				// Verify the service has been created
-				ds := &appsv1.DaemonSet{
+				contourConfig := &contourv1alpha1.ContourConfiguration{
 					ObjectMeta: metav1.ObjectMeta{
-						Namespace: "gateway-1",
-						Name:      "envoy-gateway-1",
+						Namespace: gw.Namespace,
+						Name:      "contourconfig-" + gw.Name,
 					},
 				}
-				require.NoError(t, r.client.Get(context.Background(), keyFor(ds), ds))
-				assert.Contains(t, ds.Spec.Template.ObjectMeta.Annotations, "key")
+				require.NoError(t, r.client.Get(context.Background(), keyFor(contourConfig), contourConfig))
+				assert.Equal(t, ref.To(false), contourConfig.Spec.UseEndpointSlices)
 			},
 		},
-
-		"If ContourDeployment.Spec.Envoy.WorkloadType is set to DaemonSet," +
-			"an Envoy daemonset is provisioned with the strategy that come from DaemonsetSettings": {
+		"If ContourDeployment.Spec.Contour.EnableExternalNameService is true, ExternalName Services are enabled": {
 			gatewayClass: reconcilableGatewayClassWithParams("gatewayclass-1", controller),
 			gatewayClassParams: &contourv1alpha1.ContourDeployment{
 				ObjectMeta: metav1.ObjectMeta{
@@ -1268,13 +1392,8 @@ func TestGatewayReconcile(t *testing.T) {
 					Name:      "gatewayclass-1-params",
 				},
 				Spec: contourv1alpha1.ContourDeploymentSpec{
-					Envoy: &contourv1alpha1.EnvoySettings{
-						WorkloadType: contourv1alpha1.WorkloadTypeDaemonSet,
-						DaemonSet: &contourv1alpha1.DaemonSetSettings{
-							UpdateStrategy: &appsv1.DaemonSetUpdateStrategy{
-								Type: appsv1.OnDeleteDaemonSetStrategyType,
-							},
-						},
+					Contour: &contourv1alpha1.ContourSettings{
+						EnableExternalNameService: ref.To(true),
 					},
 				},
 			},
@@ -1290,49 +1409,713 @@ func TestGatewayReconcile(t *testing.T) {
 			assertions: func(t *testing.T, r *gatewayReconciler, gw *gatewayv1beta1.Gateway, reconcileErr error) {
 				require.NoError(t, reconcileErr)
 
-				// Verify the Gateway has an "Accepted: true" condition
-				require.NoError(t, r.client.Get(context.Background(), keyFor(gw), gw))
-				require.Len(t, gw.Status.Conditions, 1)
-				assert.Equal(t, string(gatewayv1beta1.GatewayConditionAccepted), gw.Status.Conditions[0].Type)
-				assert.Equal(t, metav1.ConditionTrue, gw.Status.Conditions[0].Status)
-
-				// Verify the daemonset has been created
-				ds := &appsv1.DaemonSet{
+				contourConfig := &contourv1alpha1.ContourConfiguration{
 					ObjectMeta: metav1.ObjectMeta{
-						Namespace: "gateway-1",
-						Name:      "envoy-gateway-1",
+						Namespace: gw.Namespace,
+						Name:      "contourconfig-" + gw.Name,
 					},
 				}
-				require.NoError(t, r.client.Get(context.Background(), keyFor(ds), ds))
-				assert.EqualValues(t, appsv1.OnDeleteDaemonSetStrategyType, ds.Spec.UpdateStrategy.Type)
+				require.NoError(t, r.client.Get(context.Background(), keyFor(contourConfig), contourConfig))
+				assert.Equal(t, ref.To(true), contourConfig.Spec.EnableExternalNameService)
+			},
+		},
+		"If ContourDeployment.Spec.Contour.EnableExternalNameService is false, ExternalName Services are disabled": {
+			gatewayClass: reconcilableGatewayClassWithParams("gatewayclass-1", controller),
+			gatewayClassParams: &contourv1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "projectcontour",
+					Name:      "gatewayclass-1-params",
+				},
+				Spec: contourv1alpha1.ContourDeploymentSpec{
+					Contour: &contourv1alpha1.ContourSettings{
+						EnableExternalNameService: ref.To(false),
+					},
+				},
+			},
+			gateway: &gatewayv1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "gateway-1",
+					Name:      "gateway-1",
+				},
+				Spec: gatewayv1beta1.GatewaySpec{
+					GatewayClassName: gatewayv1beta1.ObjectName("gatewayclass-1"),
+				},
+			},
+			assertions: func(t *testing.T, r *gatewayReconciler, gw *gatewayv1beta1.Gateway, reconcileErr error) {
+				require.NoError(t, reconcileErr)
 
-				// Verify that a deployment has *not* been created
-				deployment := &appsv1.Deployment{
+				contourConfig := &contourv1alpha1.ContourConfiguration{
 					ObjectMeta: metav1.ObjectMeta{
-						Namespace: "gateway-1",
-						Name:      "envoy-gateway-1",
+						Namespace: gw.Namespace,
+						Name:      "contourconfig-" + gw.Name,
 					},
 				}
-				err := r.client.Get(context.Background(), keyFor(deployment), deployment)
-				assert.True(t, errors.IsNotFound(err))
+				require.NoError(t, r.client.Get(context.Background(), keyFor(contourConfig), contourConfig))
+				assert.Equal(t, ref.To(false), contourConfig.Spec.EnableExternalNameService)
 			},
 		},
-	}
-
-	for name, tc := range tests {
-		t.Run(name, func(t *testing.T) {
-			scheme, err := provisioner.CreateScheme()
-			require.NoError(t, err)
-
-			client := fake.NewClientBuilder().WithScheme(scheme)
-			if tc.gatewayClass != nil {
-				client.WithObjects(tc.gatewayClass)
-			}
-			if tc.gatewayClassParams != nil {
-				client.WithObjects(tc.gatewayClassParams)
-			}
-			if tc.gateway != nil {
-				client.WithObjects(tc.gateway)
+		"If ContourDeployment.Spec.XDSServer.TLS is specified, the xDS certificates are generated with those settings": {
+			gatewayClass: reconcilableGatewayClassWithParams("gatewayclass-1", controller),
+			gatewayClassParams: &contourv1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "projectcontour",
+					Name:      "gatewayclass-1-params",
+				},
+				Spec: contourv1alpha1.ContourDeploymentSpec{
+					XDSServer: &contourv1alpha1.XDSServerSettings{
+						TLS: &contourv1alpha1.XDSServerTLSSettings{
+							KeyType:             contourv1alpha1.XDSServerKeyTypeECDSA,
+							CertificateLifetime: 30,
+						},
+					},
+				},
+			},
+			gateway: &gatewayv1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "gateway-1",
+					Name:      "gateway-1",
+				},
+				Spec: gatewayv1beta1.GatewaySpec{
+					GatewayClassName: gatewayv1beta1.ObjectName("gatewayclass-1"),
+				},
+			},
+			assertions: func(t *testing.T, r *gatewayReconciler, gw *gatewayv1beta1.Gateway, reconcileErr error) {
+				require.NoError(t, reconcileErr)
+
+				for _, name := range []string{"contourcert-" + gw.Name, "envoycert-" + gw.Name} {
+					secret := &corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: gw.Namespace,
+							Name:      name,
+						},
+					}
+					require.NoError(t, r.client.Get(context.Background(), keyFor(secret), secret))
+
+					pair, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+					require.NoError(t, err)
+					assert.IsType(t, &ecdsa.PrivateKey{}, pair.PrivateKey)
+
+					cert, err := x509.ParseCertificate(pair.Certificate[0])
+					require.NoError(t, err)
+					assert.WithinDuration(t, time.Now().Add(30*24*time.Hour), cert.NotAfter, time.Minute)
+				}
+			},
+		},
+		"If ContourDeployment.Spec.Envoy.MinReadySeconds is specified, it is set on the Envoy workload": {
+			gatewayClass: reconcilableGatewayClassWithParams("gatewayclass-1", controller),
+			gatewayClassParams: &contourv1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "projectcontour",
+					Name:      "gatewayclass-1-params",
+				},
+				Spec: contourv1alpha1.ContourDeploymentSpec{
+					Envoy: &contourv1alpha1.EnvoySettings{
+						WorkloadType:    contourv1alpha1.WorkloadTypeDeployment,
+						MinReadySeconds: 10,
+					},
+				},
+			},
+			gateway: &gatewayv1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "gateway-1",
+					Name:      "gateway-1",
+				},
+				Spec: gatewayv1beta1.GatewaySpec{
+					GatewayClassName: gatewayv1beta1.ObjectName("gatewayclass-1"),
+				},
+			},
+			assertions: func(t *testing.T, r *gatewayReconciler, gw *gatewayv1beta1.Gateway, reconcileErr error) {
+				require.NoError(t, reconcileErr)
+
+				deploy := &appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "gateway-1",
+						Name:      "envoy-gateway-1",
+					},
+				}
+				require.NoError(t, r.client.Get(context.Background(), keyFor(deploy), deploy))
+				assert.EqualValues(t, 10, deploy.Spec.MinReadySeconds)
+			},
+		},
+		"If ContourDeployment.Spec.Envoy.ReadOnlyRootFilesystem is true, the Envoy containers have a read-only root filesystem": {
+			gatewayClass: reconcilableGatewayClassWithParams("gatewayclass-1", controller),
+			gatewayClassParams: &contourv1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "projectcontour",
+					Name:      "gatewayclass-1-params",
+				},
+				Spec: contourv1alpha1.ContourDeploymentSpec{
+					Envoy: &contourv1alpha1.EnvoySettings{
+						ReadOnlyRootFilesystem: true,
+					},
+				},
+			},
+			gateway: &gatewayv1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "gateway-1",
+					Name:      "gateway-1",
+				},
+				Spec: gatewayv1beta1.GatewaySpec{
+					GatewayClassName: gatewayv1beta1.ObjectName("gatewayclass-1"),
+				},
+			},
+			assertions: func(t *testing.T, r *gatewayReconciler, gw *gatewayv1beta1.Gateway, reconcileErr error) {
+				require.NoError(t, reconcileErr)
+
+				ds := &appsv1.DaemonSet{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "gateway-1",
+						Name:      "envoy-gateway-1",
+					},
+				}
+				require.NoError(t, r.client.Get(context.Background(), keyFor(ds), ds))
+				for _, c := range ds.Spec.Template.Spec.Containers {
+					require.NotNil(t, c.SecurityContext, c.Name)
+					assert.Equal(t, ref.To(true), c.SecurityContext.ReadOnlyRootFilesystem, c.Name)
+				}
+			},
+		},
+		"If ContourDeployment.Spec.*.ExtraInitContainers are specified, they run before the managed init containers": {
+			gatewayClass: reconcilableGatewayClassWithParams("gatewayclass-1", controller),
+			gatewayClassParams: &contourv1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "projectcontour",
+					Name:      "gatewayclass-1-params",
+				},
+				Spec: contourv1alpha1.ContourDeploymentSpec{
+					Contour: &contourv1alpha1.ContourSettings{
+						ExtraInitContainers: []corev1.Container{{Name: "fetch-contour-secrets", Image: "busybox:1.36"}},
+					},
+					Envoy: &contourv1alpha1.EnvoySettings{
+						ExtraInitContainers: []corev1.Container{{Name: "fetch-envoy-secrets", Image: "busybox:1.36"}},
+					},
+				},
+			},
+			gateway: &gatewayv1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "gateway-1",
+					Name:      "gateway-1",
+				},
+				Spec: gatewayv1beta1.GatewaySpec{
+					GatewayClassName: gatewayv1beta1.ObjectName("gatewayclass-1"),
+				},
+			},
+			assertions: func(t *testing.T, r *gatewayReconciler, gw *gatewayv1beta1.Gateway, reconcileErr error) {
+				require.NoError(t, reconcileErr)
+
+				deploy := &appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "gateway-1",
+						Name:      "contour-gateway-1",
+					},
+				}
+				require.NoError(t, r.client.Get(context.Background(), keyFor(deploy), deploy))
+				require.Len(t, deploy.Spec.Template.Spec.InitContainers, 1)
+				assert.Equal(t, "fetch-contour-secrets", deploy.Spec.Template.Spec.InitContainers[0].Name)
+
+				ds := &appsv1.DaemonSet{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "gateway-1",
+						Name:      "envoy-gateway-1",
+					},
+				}
+				require.NoError(t, r.client.Get(context.Background(), keyFor(ds), ds))
+				require.Len(t, ds.Spec.Template.Spec.InitContainers, 2)
+				assert.Equal(t, "fetch-envoy-secrets", ds.Spec.Template.Spec.InitContainers[0].Name)
+				assert.Equal(t, "envoy-initconfig", ds.Spec.Template.Spec.InitContainers[1].Name)
+			},
+		},
+		"If ContourDeployment.Spec.Envoy.Metrics is specified, it is rendered into the Envoy bootstrap": {
+			gatewayClass: reconcilableGatewayClassWithParams("gatewayclass-1", controller),
+			gatewayClassParams: &contourv1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "projectcontour",
+					Name:      "gatewayclass-1-params",
+				},
+				Spec: contourv1alpha1.ContourDeploymentSpec{
+					Envoy: &contourv1alpha1.EnvoySettings{
+						Metrics: &contourv1alpha1.EnvoyMetricsSettings{
+							FlushInterval: "10s",
+							HistogramBuckets: []contourv1alpha1.EnvoyHistogramBuckets{{
+								Regex:   "http\\..*",
+								Buckets: []string{"1", "10"},
+							}},
+						},
+					},
+				},
+			},
+			gateway: &gatewayv1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "gateway-1",
+					Name:      "gateway-1",
+				},
+				Spec: gatewayv1beta1.GatewaySpec{
+					GatewayClassName: gatewayv1beta1.ObjectName("gatewayclass-1"),
+				},
+			},
+			assertions: func(t *testing.T, r *gatewayReconciler, gw *gatewayv1beta1.Gateway, reconcileErr error) {
+				require.NoError(t, reconcileErr)
+
+				ds := &appsv1.DaemonSet{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "gateway-1",
+						Name:      "envoy-gateway-1",
+					},
+				}
+				require.NoError(t, r.client.Get(context.Background(), keyFor(ds), ds))
+				args := ds.Spec.Template.Spec.InitContainers[0].Args
+				assert.Contains(t, args, "--stats-flush-interval=10s")
+				assert.Contains(t, args, `--stats-histogram-buckets=http\..*=1,10`)
+			},
+		},
+		"If ContourDeployment.Spec.Envoy.DefaultBackend is specified, it is set in the Gateway's namespace on the ContourConfiguration": {
+			gatewayClass: reconcilableGatewayClassWithParams("gatewayclass-1", controller),
+			gatewayClassParams: &contourv1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "projectcontour",
+					Name:      "gatewayclass-1-params",
+				},
+				Spec: contourv1alpha1.ContourDeploymentSpec{
+					Envoy: &contourv1alpha1.EnvoySettings{
+						DefaultBackend: &contourv1alpha1.EnvoyDefaultBackend{
+							Name: "default-backend",
+							Port: 80,
+						},
+					},
+				},
+			},
+			gateway: &gatewayv1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "gateway-1",
+					Name:      "gateway-1",
+				},
+				Spec: gatewayv1beta1.GatewaySpec{
+					GatewayClassName: gatewayv1beta1.ObjectName("gatewayclass-1"),
+				},
+			},
+			assertions: func(t *testing.T, r *gatewayReconciler, gw *gatewayv1beta1.Gateway, reconcileErr error) {
+				require.NoError(t, reconcileErr)
+
+				contourConfig := &contourv1alpha1.ContourConfiguration{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "gateway-1",
+						Name:      "contourconfig-gateway-1",
+					},
+				}
+				require.NoError(t, r.client.Get(context.Background(), keyFor(contourConfig), contourConfig))
+				require.NotNil(t, contourConfig.Spec.Gateway)
+				assert.Equal(t, &contourv1alpha1.GatewayDefaultBackend{
+					Namespace: "gateway-1",
+					Name:      "default-backend",
+					Port:      80,
+				}, contourConfig.Spec.Gateway.DefaultBackend)
+			},
+		},
+		"If ContourDeployment.Spec.Envoy.ExtraArgs is specified, they are appended to the Envoy container args": {
+			gatewayClass: reconcilableGatewayClassWithParams("gatewayclass-1", controller),
+			gatewayClassParams: &contourv1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "projectcontour",
+					Name:      "gatewayclass-1-params",
+				},
+				Spec: contourv1alpha1.ContourDeploymentSpec{
+					Envoy: &contourv1alpha1.EnvoySettings{
+						ExtraArgs: []string{"--log-format %v"},
+					},
+				},
+			},
+			gateway: &gatewayv1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "gateway-1",
+					Name:      "gateway-1",
+				},
+				Spec: gatewayv1beta1.GatewaySpec{
+					GatewayClassName: gatewayv1beta1.ObjectName("gatewayclass-1"),
+				},
+			},
+			assertions: func(t *testing.T, r *gatewayReconciler, gw *gatewayv1beta1.Gateway, reconcileErr error) {
+				require.NoError(t, reconcileErr)
+
+				ds := &appsv1.DaemonSet{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "gateway-1",
+						Name:      "envoy-gateway-1",
+					},
+				}
+				require.NoError(t, r.client.Get(context.Background(), keyFor(ds), ds))
+
+				var envoyArgs []string
+				for _, container := range ds.Spec.Template.Spec.Containers {
+					if container.Name == "envoy" {
+						envoyArgs = container.Args
+					}
+				}
+				assert.Contains(t, envoyArgs, "--log-format %v")
+			},
+		},
+		"If ContourDeployment.Spec.Envoy.Listener.KeepAlive is specified, it is set in the ContourConfiguration": {
+			gatewayClass: reconcilableGatewayClassWithParams("gatewayclass-1", controller),
+			gatewayClassParams: &contourv1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "projectcontour",
+					Name:      "gatewayclass-1-params",
+				},
+				Spec: contourv1alpha1.ContourDeploymentSpec{
+					Envoy: &contourv1alpha1.EnvoySettings{
+						Listener: &contourv1alpha1.EnvoyListenerSettings{
+							KeepAlive: &contourv1alpha1.EnvoyListenerKeepAlive{
+								TCP:   &contourv1alpha1.TCPKeepAlive{IdleTime: 60},
+								HTTP2: &contourv1alpha1.HTTP2KeepAlive{Interval: "30s", Timeout: "5s"},
+							},
+						},
+					},
+				},
+			},
+			gateway: &gatewayv1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "gateway-1",
+					Name:      "gateway-1",
+				},
+				Spec: gatewayv1beta1.GatewaySpec{
+					GatewayClassName: gatewayv1beta1.ObjectName("gatewayclass-1"),
+				},
+			},
+			assertions: func(t *testing.T, r *gatewayReconciler, gw *gatewayv1beta1.Gateway, reconcileErr error) {
+				require.NoError(t, reconcileErr)
+
+				contourConfig := &contourv1alpha1.ContourConfiguration{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: gw.Namespace,
+						Name:      "contourconfig-" + gw.Name,
+					},
+				}
+				require.NoError(t, r.client.Get(context.Background(), keyFor(contourConfig), contourConfig))
+				require.NotNil(t, contourConfig.Spec.Envoy.Listener)
+				assert.Equal(t, &contourv1alpha1.EnvoyListenerKeepAlive{
+					TCP:   &contourv1alpha1.TCPKeepAlive{IdleTime: 60},
+					HTTP2: &contourv1alpha1.HTTP2KeepAlive{Interval: "30s", Timeout: "5s"},
+				}, contourConfig.Spec.Envoy.Listener.KeepAlive)
+			},
+		},
+		"If ContourDeployment.Spec.Envoy.Listener.HTTP2MaxConcurrentStreams is specified, it is set in the ContourConfiguration": {
+			gatewayClass: reconcilableGatewayClassWithParams("gatewayclass-1", controller),
+			gatewayClassParams: &contourv1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "projectcontour",
+					Name:      "gatewayclass-1-params",
+				},
+				Spec: contourv1alpha1.ContourDeploymentSpec{
+					Envoy: &contourv1alpha1.EnvoySettings{
+						Listener: &contourv1alpha1.EnvoyListenerSettings{
+							HTTP2MaxConcurrentStreams: ref.To(uint32(100)),
+						},
+					},
+				},
+			},
+			gateway: &gatewayv1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "gateway-1",
+					Name:      "gateway-1",
+				},
+				Spec: gatewayv1beta1.GatewaySpec{
+					GatewayClassName: gatewayv1beta1.ObjectName("gatewayclass-1"),
+				},
+			},
+			assertions: func(t *testing.T, r *gatewayReconciler, gw *gatewayv1beta1.Gateway, reconcileErr error) {
+				require.NoError(t, reconcileErr)
+
+				contourConfig := &contourv1alpha1.ContourConfiguration{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: gw.Namespace,
+						Name:      "contourconfig-" + gw.Name,
+					},
+				}
+				require.NoError(t, r.client.Get(context.Background(), keyFor(contourConfig), contourConfig))
+				require.NotNil(t, contourConfig.Spec.Envoy.Listener)
+				assert.Equal(t, ref.To(uint32(100)), contourConfig.Spec.Envoy.Listener.HTTP2MaxConcurrentStreams)
+			},
+		},
+		"If ContourDeployment.Spec.Envoy.DefaultResponseHeaders is specified, the headers are applied to Gateway API routes": {
+			gatewayClass: reconcilableGatewayClassWithParams("gatewayclass-1", controller),
+			gatewayClassParams: &contourv1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "projectcontour",
+					Name:      "gatewayclass-1-params",
+				},
+				Spec: contourv1alpha1.ContourDeploymentSpec{
+					Envoy: &contourv1alpha1.EnvoySettings{
+						DefaultResponseHeaders: &contourv1alpha1.HeadersPolicy{
+							Set:    map[string]string{"Strict-Transport-Security": "max-age=31536000"},
+							Remove: []string{"Server"},
+						},
+					},
+				},
+			},
+			gateway: &gatewayv1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "gateway-1",
+					Name:      "gateway-1",
+				},
+				Spec: gatewayv1beta1.GatewaySpec{
+					GatewayClassName: gatewayv1beta1.ObjectName("gatewayclass-1"),
+				},
+			},
+			assertions: func(t *testing.T, r *gatewayReconciler, gw *gatewayv1beta1.Gateway, reconcileErr error) {
+				require.NoError(t, reconcileErr)
+
+				contourConfig := &contourv1alpha1.ContourConfiguration{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: gw.Namespace,
+						Name:      "contourconfig-" + gw.Name,
+					},
+				}
+				require.NoError(t, r.client.Get(context.Background(), keyFor(contourConfig), contourConfig))
+				require.NotNil(t, contourConfig.Spec.Policy)
+				assert.Equal(t, &contourv1alpha1.HeadersPolicy{
+					Set:    map[string]string{"Strict-Transport-Security": "max-age=31536000"},
+					Remove: []string{"Server"},
+				}, contourConfig.Spec.Policy.ResponseHeadersPolicy)
+				assert.Equal(t, ref.To(true), contourConfig.Spec.Policy.ApplyToGatewayAPI)
+			},
+		},
+		"If ContourDeployment.Spec.Envoy.WorkloadType is set to Deployment, an Envoy deployment is provisioned with the specified number of replicas": {
+			gatewayClass: reconcilableGatewayClassWithParams("gatewayclass-1", controller),
+			gatewayClassParams: &contourv1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "projectcontour",
+					Name:      "gatewayclass-1-params",
+				},
+				Spec: contourv1alpha1.ContourDeploymentSpec{
+					Envoy: &contourv1alpha1.EnvoySettings{
+						WorkloadType: contourv1alpha1.WorkloadTypeDeployment,
+						Replicas:     7,
+					},
+				},
+			},
+			gateway: &gatewayv1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "gateway-1",
+					Name:      "gateway-1",
+				},
+				Spec: gatewayv1beta1.GatewaySpec{
+					GatewayClassName: gatewayv1beta1.ObjectName("gatewayclass-1"),
+				},
+			},
+			assertions: func(t *testing.T, r *gatewayReconciler, gw *gatewayv1beta1.Gateway, reconcileErr error) {
+				require.NoError(t, reconcileErr)
+
+				// Verify the Gateway has a "Accepted: true" condition
+				require.NoError(t, r.client.Get(context.Background(), keyFor(gw), gw))
+				require.Len(t, gw.Status.Conditions, 1)
+				assert.Equal(t, string(gatewayv1beta1.GatewayConditionAccepted), gw.Status.Conditions[0].Type)
+				assert.Equal(t, metav1.ConditionTrue, gw.Status.Conditions[0].Status)
+
+				// Verify the deployment has been created
+				deploy := &appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "gateway-1",
+						Name:      "envoy-gateway-1",
+					},
+				}
+				require.NoError(t, r.client.Get(context.Background(), keyFor(deploy), deploy))
+				assert.EqualValues(t, 7, *deploy.Spec.Replicas)
+
+				// Verify that a daemonset has *not* been created
+				ds := &appsv1.DaemonSet{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "gateway-1",
+						Name:      "envoy-gateway-1",
+					},
+				}
+				err := r.client.Get(context.Background(), keyFor(ds), ds)
+				assert.True(t, errors.IsNotFound(err))
+			},
+		},
+		"If ContourDeployment.Spec.Envoy.WorkloadType is set to Deployment," +
+			"an Envoy deployment is provisioned with the settings come from DeployemntSettings": {
+			gatewayClass: reconcilableGatewayClassWithParams("gatewayclass-1", controller),
+			gatewayClassParams: &contourv1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "projectcontour",
+					Name:      "gatewayclass-1-params",
+				},
+				Spec: contourv1alpha1.ContourDeploymentSpec{
+					Envoy: &contourv1alpha1.EnvoySettings{
+						WorkloadType: contourv1alpha1.WorkloadTypeDeployment,
+						Replicas:     7,
+						Deployment: &contourv1alpha1.DeploymentSettings{
+							Replicas: 6,
+							Strategy: &appsv1.DeploymentStrategy{
+								Type: appsv1.RecreateDeploymentStrategyType,
+							},
+						},
+					},
+				},
+			},
+			gateway: &gatewayv1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "gateway-1",
+					Name:      "gateway-1",
+				},
+				Spec: gatewayv1beta1.GatewaySpec{
+					GatewayClassName: gatewayv1beta1.ObjectName("gatewayclass-1"),
+				},
+			},
+			assertions: func(t *testing.T, r *gatewayReconciler, gw *gatewayv1beta1.Gateway, reconcileErr error) {
+				require.NoError(t, reconcileErr)
+
+				// Verify the Gateway has an "Accepted: true" condition
+				require.NoError(t, r.client.Get(context.Background(), keyFor(gw), gw))
+				require.Len(t, gw.Status.Conditions, 1)
+				assert.Equal(t, string(gatewayv1beta1.GatewayConditionAccepted), gw.Status.Conditions[0].Type)
+				assert.Equal(t, metav1.ConditionTrue, gw.Status.Conditions[0].Status)
+
+				// Verify the deployment has been created
+				deploy := &appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "gateway-1",
+						Name:      "envoy-gateway-1",
+					},
+				}
+				require.NoError(t, r.client.Get(context.Background(), keyFor(deploy), deploy))
+
+				assert.NotNil(t, deploy.Spec.Replicas)
+				assert.EqualValues(t, 6, *deploy.Spec.Replicas)
+
+				assert.NotNil(t, deploy.Spec.Strategy)
+				assert.EqualValues(t, appsv1.RecreateDeploymentStrategyType, deploy.Spec.Strategy.Type)
+
+				// Verify that a daemonset has *not* been created
+				ds := &appsv1.DaemonSet{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "gateway-1",
+						Name:      "envoy-gateway-1",
+					},
+				}
+				err := r.client.Get(context.Background(), keyFor(ds), ds)
+				assert.True(t, errors.IsNotFound(err))
+			},
+		},
+
+		"If ContourDeployment.Spec.Envoy.PodAnnotations is specified, the Envoy pods' have annotations for prometheus & user-defined": {
+			gatewayClass: reconcilableGatewayClassWithParams("gatewayclass-1", controller),
+			gatewayClassParams: &contourv1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "projectcontour",
+					Name:      "gatewayclass-1-params",
+				},
+				Spec: contourv1alpha1.ContourDeploymentSpec{
+					Envoy: &contourv1alpha1.EnvoySettings{
+						PodAnnotations: map[string]string{
+							"key": "val",
+						},
+					},
+				},
+			},
+			gateway: &gatewayv1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "gateway-1",
+					Name:      "gateway-1",
+				},
+				Spec: gatewayv1beta1.GatewaySpec{
+					GatewayClassName: gatewayv1beta1.ObjectName("gatewayclass-1"),
+				},
+			},
+			assertions: func(t *testing.T, r *gatewayReconciler, gw *gatewayv1beta1.Gateway, reconcileErr error) {
+				require.NoError(t, reconcileErr)
+
+				// Verify the Gateway has a "Accepted: true" condition
+				require.NoError(t, r.client.Get(context.Background(), keyFor(gw), gw))
+				require.Len(t, gw.Status.Conditions, 1)
+				assert.Equal(t, string(gatewayv1beta1.GatewayConditionAccepted), gw.Status.Conditions[0].Type)
+				assert.Equal(t, metav1.ConditionTrue, gw.Status.Conditions[0].Status)
+
+				// Verify the service has been created
+				ds := &appsv1.DaemonSet{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "gateway-1",
+						Name:      "envoy-gateway-1",
+					},
+				}
+				require.NoError(t, r.client.Get(context.Background(), keyFor(ds), ds))
+				assert.Contains(t, ds.Spec.Template.ObjectMeta.Annotations, "key")
+			},
+		},
+
+		"If ContourDeployment.Spec.Envoy.WorkloadType is set to DaemonSet," +
+			"an Envoy daemonset is provisioned with the strategy that come from DaemonsetSettings": {
+			gatewayClass: reconcilableGatewayClassWithParams("gatewayclass-1", controller),
+			gatewayClassParams: &contourv1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "projectcontour",
+					Name:      "gatewayclass-1-params",
+				},
+				Spec: contourv1alpha1.ContourDeploymentSpec{
+					Envoy: &contourv1alpha1.EnvoySettings{
+						WorkloadType: contourv1alpha1.WorkloadTypeDaemonSet,
+						DaemonSet: &contourv1alpha1.DaemonSetSettings{
+							UpdateStrategy: &appsv1.DaemonSetUpdateStrategy{
+								Type: appsv1.OnDeleteDaemonSetStrategyType,
+							},
+						},
+					},
+				},
+			},
+			gateway: &gatewayv1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "gateway-1",
+					Name:      "gateway-1",
+				},
+				Spec: gatewayv1beta1.GatewaySpec{
+					GatewayClassName: gatewayv1beta1.ObjectName("gatewayclass-1"),
+				},
+			},
+			assertions: func(t *testing.T, r *gatewayReconciler, gw *gatewayv1beta1.Gateway, reconcileErr error) {
+				require.NoError(t, reconcileErr)
+
+				// Verify the Gateway has an "Accepted: true" condition
+				require.NoError(t, r.client.Get(context.Background(), keyFor(gw), gw))
+				require.Len(t, gw.Status.Conditions, 1)
+				assert.Equal(t, string(gatewayv1beta1.GatewayConditionAccepted), gw.Status.Conditions[0].Type)
+				assert.Equal(t, metav1.ConditionTrue, gw.Status.Conditions[0].Status)
+
+				// Verify the daemonset has been created
+				ds := &appsv1.DaemonSet{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "gateway-1",
+						Name:      "envoy-gateway-1",
+					},
+				}
+				require.NoError(t, r.client.Get(context.Background(), keyFor(ds), ds))
+				assert.EqualValues(t, appsv1.OnDeleteDaemonSetStrategyType, ds.Spec.UpdateStrategy.Type)
+
+				// Verify that a deployment has *not* been created
+				deployment := &appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "gateway-1",
+						Name:      "envoy-gateway-1",
+					},
+				}
+				err := r.client.Get(context.Background(), keyFor(deployment), deployment)
+				assert.True(t, errors.IsNotFound(err))
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			scheme, err := provisioner.CreateScheme()
+			require.NoError(t, err)
+
+			client := fake.NewClientBuilder().WithScheme(scheme)
+			if tc.gatewayClass != nil {
+				client.WithObjects(tc.gatewayClass)
+			}
+			if tc.gatewayClassParams != nil {
+				client.WithObjects(tc.gatewayClassParams)
+			}
+			if tc.gateway != nil {
+				client.WithObjects(tc.gateway)
 			}
 
 			r := &gatewayReconciler{
@@ -1357,6 +2140,370 @@ func TestGatewayReconcile(t *testing.T) {
 	}
 }
 
+func TestGatewayReconcileRecordsRollout(t *testing.T) {
+	const controller = "projectcontour.io/gateway-controller"
+
+	gatewayClass := &gatewayv1beta1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "gatewayclass-1",
+		},
+		Spec: gatewayv1beta1.GatewayClassSpec{
+			ControllerName: gatewayv1beta1.GatewayController(controller),
+			ParametersRef: &gatewayv1beta1.ParametersReference{
+				Group:     gatewayv1beta1.Group(contourv1alpha1.GroupVersion.Group),
+				Kind:      "ContourDeployment",
+				Namespace: ref.To(gatewayv1beta1.Namespace("projectcontour")),
+				Name:      "gatewayclass-1-params",
+			},
+		},
+		Status: gatewayv1beta1.GatewayClassStatus{
+			Conditions: []metav1.Condition{
+				{
+					Type:   string(gatewayv1beta1.GatewayClassConditionStatusAccepted),
+					Status: metav1.ConditionTrue,
+					Reason: string(gatewayv1beta1.GatewayClassReasonAccepted),
+				},
+			},
+		},
+	}
+	gatewayClassParams := &contourv1alpha1.ContourDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "projectcontour",
+			Name:      "gatewayclass-1-params",
+		},
+		Spec: contourv1alpha1.ContourDeploymentSpec{
+			Envoy: &contourv1alpha1.EnvoySettings{},
+		},
+	}
+	gateway := &gatewayv1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "gateway-1",
+			Name:      "gateway-1",
+		},
+		Spec: gatewayv1beta1.GatewaySpec{
+			GatewayClassName: gatewayv1beta1.ObjectName("gatewayclass-1"),
+		},
+	}
+
+	scheme, err := provisioner.CreateScheme()
+	require.NoError(t, err)
+
+	recorder := record.NewFakeRecorder(10)
+	r := &gatewayReconciler{
+		gatewayController: controller,
+		client:            fake.NewClientBuilder().WithScheme(scheme).WithObjects(gatewayClass, gatewayClassParams, gateway).Build(),
+		recorder:          recorder,
+		log:               logr.Discard(),
+	}
+	req := reconcile.Request{NamespacedName: keyFor(gateway)}
+
+	// Creating the workloads does not record a rollout.
+	_, err = r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.Empty(t, recorder.Events)
+
+	// Changing the Envoy resources rolls out the Envoy pods.
+	require.NoError(t, r.client.Get(context.Background(), keyFor(gatewayClassParams), gatewayClassParams))
+	gatewayClassParams.Spec.Envoy.Resources = corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("1Gi"),
+		},
+	}
+	require.NoError(t, r.client.Update(context.Background(), gatewayClassParams))
+
+	_, err = r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, recorder.Events, 1)
+	assert.Equal(t, "Normal RollingUpdate Rolling update of daemonset gateway-1/envoy-gateway-1: resources changed", <-recorder.Events)
+
+	// Reconciling again without changes does not record a rollout.
+	_, err = r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.Empty(t, recorder.Events)
+}
+
+func TestGatewayReconcileRequeuesXDSSecretsRotation(t *testing.T) {
+	const controller = "projectcontour.io/gateway-controller"
+
+	gatewayClass := &gatewayv1beta1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "gatewayclass-1",
+		},
+		Spec: gatewayv1beta1.GatewayClassSpec{
+			ControllerName: gatewayv1beta1.GatewayController(controller),
+		},
+		Status: gatewayv1beta1.GatewayClassStatus{
+			Conditions: []metav1.Condition{
+				{
+					Type:   string(gatewayv1beta1.GatewayClassConditionStatusAccepted),
+					Status: metav1.ConditionTrue,
+					Reason: string(gatewayv1beta1.GatewayClassReasonAccepted),
+				},
+			},
+		},
+	}
+	gateway := &gatewayv1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "gateway-1",
+			Name:      "gateway-1",
+		},
+		Spec: gatewayv1beta1.GatewaySpec{
+			GatewayClassName: gatewayv1beta1.ObjectName("gatewayclass-1"),
+		},
+	}
+
+	scheme, err := provisioner.CreateScheme()
+	require.NoError(t, err)
+
+	r := &gatewayReconciler{
+		gatewayController: controller,
+		client:            fake.NewClientBuilder().WithScheme(scheme).WithObjects(gatewayClass, gateway).Build(),
+		log:               logr.Discard(),
+	}
+	req := reconcile.Request{NamespacedName: keyFor(gateway)}
+
+	// The xDS TLS secrets are valid for a year by default, and
+	// are rotated once less than a third of that remains.
+	rotateAfter := 365 * 24 * time.Hour * 2 / 3
+
+	result, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.InDelta(t, rotateAfter, result.RequeueAfter, float64(time.Minute))
+
+	// The gateway is requeued for the rotation after
+	// it has been accepted, too.
+	result, err = r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.InDelta(t, rotateAfter, result.RequeueAfter, float64(time.Minute))
+}
+
+func TestGatewayReconcilePrunesEnvoyDataPlane(t *testing.T) {
+	const controller = "projectcontour.io/gateway-controller"
+
+	gatewayClass := &gatewayv1beta1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "gatewayclass-1",
+		},
+		Spec: gatewayv1beta1.GatewayClassSpec{
+			ControllerName: gatewayv1beta1.GatewayController(controller),
+			ParametersRef: &gatewayv1beta1.ParametersReference{
+				Group:     gatewayv1beta1.Group(contourv1alpha1.GroupVersion.Group),
+				Kind:      "ContourDeployment",
+				Namespace: ref.To(gatewayv1beta1.Namespace("projectcontour")),
+				Name:      "gatewayclass-1-params",
+			},
+		},
+		Status: gatewayv1beta1.GatewayClassStatus{
+			Conditions: []metav1.Condition{
+				{
+					Type:   string(gatewayv1beta1.GatewayClassConditionStatusAccepted),
+					Status: metav1.ConditionTrue,
+					Reason: string(gatewayv1beta1.GatewayClassReasonAccepted),
+				},
+			},
+		},
+	}
+	gatewayClassParams := &contourv1alpha1.ContourDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "projectcontour",
+			Name:      "gatewayclass-1-params",
+		},
+		Spec: contourv1alpha1.ContourDeploymentSpec{
+			Envoy: &contourv1alpha1.EnvoySettings{
+				WorkloadType: contourv1alpha1.WorkloadTypeDaemonSet,
+			},
+		},
+	}
+	gateway := &gatewayv1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "gateway-1",
+			Name:      "gateway-1",
+			UID:       "gateway-1-uid",
+		},
+		Spec: gatewayv1beta1.GatewaySpec{
+			GatewayClassName: gatewayv1beta1.ObjectName("gatewayclass-1"),
+		},
+	}
+
+	scheme, err := provisioner.CreateScheme()
+	require.NoError(t, err)
+
+	r := &gatewayReconciler{
+		gatewayController: controller,
+		client:            fake.NewClientBuilder().WithScheme(scheme).WithObjects(gatewayClass, gatewayClassParams, gateway).Build(),
+		log:               logr.Discard(),
+	}
+	req := reconcile.Request{NamespacedName: keyFor(gateway)}
+
+	// The DaemonSet is controlled by the Gateway.
+	_, err = r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	daemonSet := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "gateway-1",
+			Name:      "envoy-gateway-1",
+		},
+	}
+	require.NoError(t, r.client.Get(context.Background(), keyFor(daemonSet), daemonSet))
+	require.NotNil(t, metav1.GetControllerOf(daemonSet))
+	assert.Equal(t, gateway.UID, metav1.GetControllerOf(daemonSet).UID)
+
+	// Switching to a Deployment prunes the DaemonSet.
+	require.NoError(t, r.client.Get(context.Background(), keyFor(gatewayClassParams), gatewayClassParams))
+	gatewayClassParams.Spec.Envoy.WorkloadType = contourv1alpha1.WorkloadTypeDeployment
+	require.NoError(t, r.client.Update(context.Background(), gatewayClassParams))
+
+	_, err = r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "gateway-1",
+			Name:      "envoy-gateway-1",
+		},
+	}
+	require.NoError(t, r.client.Get(context.Background(), keyFor(deployment), deployment))
+	require.NotNil(t, metav1.GetControllerOf(deployment))
+	assert.Equal(t, gateway.UID, metav1.GetControllerOf(deployment).UID)
+
+	err = r.client.Get(context.Background(), keyFor(daemonSet), daemonSet)
+	assert.True(t, errors.IsNotFound(err))
+
+	// A DaemonSet with the data plane's name that is not controlled by
+	// the Gateway, e.g. one created before the Gateway controlled its
+	// data plane, is left alone.
+	daemonSet = &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "gateway-1",
+			Name:      "envoy-gateway-1",
+		},
+	}
+	require.NoError(t, r.client.Create(context.Background(), daemonSet))
+
+	_, err = r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.NoError(t, r.client.Get(context.Background(), keyFor(daemonSet), daemonSet))
+
+	// Switching back to a DaemonSet prunes the Deployment.
+	require.NoError(t, r.client.Delete(context.Background(), daemonSet))
+	require.NoError(t, r.client.Get(context.Background(), keyFor(gatewayClassParams), gatewayClassParams))
+	gatewayClassParams.Spec.Envoy.WorkloadType = contourv1alpha1.WorkloadTypeDaemonSet
+	require.NoError(t, r.client.Update(context.Background(), gatewayClassParams))
+
+	_, err = r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	err = r.client.Get(context.Background(), keyFor(deployment), deployment)
+	assert.True(t, errors.IsNotFound(err))
+	assert.NoError(t, r.client.Get(context.Background(), keyFor(daemonSet), &appsv1.DaemonSet{}))
+}
+
+func TestGatewayReconcileDeletesGatewayResources(t *testing.T) {
+	const controller = "projectcontour.io/gateway-controller"
+
+	gatewayClass := &gatewayv1beta1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "gatewayclass-1",
+		},
+		Spec: gatewayv1beta1.GatewayClassSpec{
+			ControllerName: gatewayv1beta1.GatewayController(controller),
+			ParametersRef: &gatewayv1beta1.ParametersReference{
+				Group:     gatewayv1beta1.Group(contourv1alpha1.GroupVersion.Group),
+				Kind:      "ContourDeployment",
+				Namespace: ref.To(gatewayv1beta1.Namespace("projectcontour")),
+				Name:      "gatewayclass-1-params",
+			},
+		},
+		Status: gatewayv1beta1.GatewayClassStatus{
+			Conditions: []metav1.Condition{
+				{
+					Type:   string(gatewayv1beta1.GatewayClassConditionStatusAccepted),
+					Status: metav1.ConditionTrue,
+					Reason: string(gatewayv1beta1.GatewayClassReasonAccepted),
+				},
+			},
+		},
+	}
+	gatewayClassParams := &contourv1alpha1.ContourDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "projectcontour",
+			Name:      "gatewayclass-1-params",
+		},
+	}
+	gateway1 := &gatewayv1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "gateway-1",
+			Name:      "gateway-1",
+			UID:       "gateway-1-uid",
+		},
+		Spec: gatewayv1beta1.GatewaySpec{
+			GatewayClassName: gatewayv1beta1.ObjectName("gatewayclass-1"),
+		},
+	}
+	gateway2 := &gatewayv1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "gateway-1",
+			Name:      "gateway-2",
+			UID:       "gateway-2-uid",
+		},
+		Spec: gatewayv1beta1.GatewaySpec{
+			GatewayClassName: gatewayv1beta1.ObjectName("gatewayclass-1"),
+		},
+	}
+
+	scheme, err := provisioner.CreateScheme()
+	require.NoError(t, err)
+
+	r := &gatewayReconciler{
+		gatewayController: controller,
+		client:            fake.NewClientBuilder().WithScheme(scheme).WithObjects(gatewayClass, gatewayClassParams, gateway1, gateway2).Build(),
+		log:               logr.Discard(),
+	}
+
+	// Both Gateways of the class get their own resources, and their
+	// ContourConfigurations are controlled by them.
+	for _, gateway := range []*gatewayv1beta1.Gateway{gateway1, gateway2} {
+		_, err = r.Reconcile(context.Background(), reconcile.Request{NamespacedName: keyFor(gateway)})
+		require.NoError(t, err)
+
+		contourConfig := &contourv1alpha1.ContourConfiguration{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: gateway.Namespace,
+				Name:      "contourconfig-" + gateway.Name,
+			},
+		}
+		require.NoError(t, r.client.Get(context.Background(), keyFor(contourConfig), contourConfig))
+		require.NotNil(t, metav1.GetControllerOf(contourConfig))
+		assert.Equal(t, gateway.UID, metav1.GetControllerOf(contourConfig).UID)
+	}
+
+	// Deleting one of the Gateways deletes its resources, and keeps the
+	// resources of the other one.
+	require.NoError(t, r.client.Delete(context.Background(), gateway1))
+	_, err = r.Reconcile(context.Background(), reconcile.Request{NamespacedName: keyFor(gateway1)})
+	require.NoError(t, err)
+
+	for _, obj := range []client.Object{
+		&contourv1alpha1.ContourConfiguration{ObjectMeta: metav1.ObjectMeta{Namespace: "gateway-1", Name: "contourconfig-gateway-1"}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "gateway-1", Name: "contour-gateway-1"}},
+		&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Namespace: "gateway-1", Name: "envoy-gateway-1"}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "gateway-1", Name: "envoy-gateway-1"}},
+	} {
+		err := r.client.Get(context.Background(), keyFor(obj), obj)
+		assert.Truef(t, errors.IsNotFound(err), "expected %T %s to be deleted", obj, obj.GetName())
+	}
+
+	for _, obj := range []client.Object{
+		&contourv1alpha1.ContourConfiguration{ObjectMeta: metav1.ObjectMeta{Namespace: "gateway-1", Name: "contourconfig-gateway-2"}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "gateway-1", Name: "contour-gateway-2"}},
+		&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Namespace: "gateway-1", Name: "envoy-gateway-2"}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "gateway-1", Name: "envoy-gateway-2"}},
+	} {
+		assert.NoErrorf(t, r.client.Get(context.Background(), keyFor(obj), obj), "expected %T %s to be kept", obj, obj.GetName())
+	}
+}
+
 func assertEnvoyServiceLoadBalancerIP(t *testing.T, gateway *gatewayv1beta1.Gateway, client client.Client, want string) {
 	// Get the expected Envoy service from the client.
 	envoyService := &corev1.Service{
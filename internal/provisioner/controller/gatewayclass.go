@@ -16,16 +16,27 @@ package controller
 import (
 	"context"
 	"fmt"
+	"math"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
 	contour_api_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
+	"github.com/projectcontour/contour/internal/provisioner/model"
+	"github.com/projectcontour/contour/internal/provisioner/objects"
+	"github.com/projectcontour/contour/internal/provisioner/objects/dataplane"
+	"github.com/projectcontour/contour/internal/provisioner/objects/deployment"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -122,7 +133,9 @@ func (r *gatewayClassReconciler) mapContourDeploymentToGatewayClasses(contourDep
 	return reconciles
 }
 
-func (r *gatewayClassReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *gatewayClassReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	defer observeReconcile(kindGatewayClass, time.Now(), &err)
+
 	gatewayClass := &gatewayapi_v1beta1.GatewayClass{}
 	if err := r.client.Get(ctx, req.NamespacedName, gatewayClass); err != nil {
 		// GatewayClass no longer exists, nothing to do.
@@ -161,63 +174,7 @@ func (r *gatewayClassReconciler) Reconcile(ctx context.Context, req ctrl.Request
 
 	// If parameters are referenced, validate the values.
 	if params != nil {
-		var invalidParamsMessages []string
-
-		if params.Spec.Envoy != nil {
-			switch params.Spec.Envoy.WorkloadType {
-			// valid values, nothing to do
-			case "", contour_api_v1alpha1.WorkloadTypeDaemonSet, contour_api_v1alpha1.WorkloadTypeDeployment:
-			// invalid value, set message
-			default:
-				msg := fmt.Sprintf("invalid ContourDeployment spec.envoy.workloadType %q, must be DaemonSet or Deployment", params.Spec.Envoy.WorkloadType)
-				invalidParamsMessages = append(invalidParamsMessages, msg)
-			}
-
-			if params.Spec.Envoy.NetworkPublishing != nil {
-				switch params.Spec.Envoy.NetworkPublishing.Type {
-				// valid values, nothing to do
-				case "", contour_api_v1alpha1.LoadBalancerServicePublishingType, contour_api_v1alpha1.NodePortServicePublishingType, contour_api_v1alpha1.ClusterIPServicePublishingType:
-				// invalid value, set message
-				default:
-					msg := fmt.Sprintf("invalid ContourDeployment spec.envoy.networkPublishing.type %q, must be LoadBalancerService, NoderPortService or ClusterIPService",
-						params.Spec.Envoy.NetworkPublishing.Type)
-					invalidParamsMessages = append(invalidParamsMessages, msg)
-				}
-
-				switch params.Spec.Envoy.NetworkPublishing.ExternalTrafficPolicy {
-				case "", corev1.ServiceExternalTrafficPolicyTypeCluster, corev1.ServiceExternalTrafficPolicyTypeLocal:
-				default:
-					msg := fmt.Sprintf("invalid ContourDeployment spec.envoy.networkPublishing.externalTrafficPolicy %q, must be Local or Cluster",
-						params.Spec.Envoy.NetworkPublishing.ExternalTrafficPolicy)
-					invalidParamsMessages = append(invalidParamsMessages, msg)
-				}
-			}
-
-			if params.Spec.Envoy.ExtraVolumeMounts != nil {
-				volumes := map[string]struct{}{}
-				for _, vol := range params.Spec.Envoy.ExtraVolumes {
-					volumes[vol.Name] = struct{}{}
-				}
-				for _, mnt := range params.Spec.Envoy.ExtraVolumeMounts {
-					if _, ok := volumes[mnt.Name]; !ok {
-						msg := fmt.Sprintf("invalid ContourDeployment spec.envoy.extraVolumeMounts, mount to unknown volume: %q", mnt.Name)
-						invalidParamsMessages = append(invalidParamsMessages, msg)
-					}
-				}
-			}
-
-			switch params.Spec.Envoy.LogLevel {
-			// valid values, nothing to do.
-			case "", v1alpha1.TraceLog, v1alpha1.DebugLog, v1alpha1.InfoLog, v1alpha1.WarnLog, v1alpha1.ErrorLog, v1alpha1.CriticalLog, v1alpha1.OffLog:
-			// invalid value, set message.
-			default:
-				msg := fmt.Sprintf("invalid ContourDeployment spec.envoy.logLevel %q, must be trace, debug, info, warn, error, critical or off",
-					params.Spec.Envoy.LogLevel)
-				invalidParamsMessages = append(invalidParamsMessages, msg)
-			}
-		}
-
-		if len(invalidParamsMessages) > 0 {
+		if invalidParamsMessages := validateContourDeployment(params); len(invalidParamsMessages) > 0 {
 			if err := r.setAcceptedCondition(
 				ctx,
 				gatewayClass,
@@ -325,3 +282,585 @@ func isContourDeploymentRef(ref *gatewayapi_v1beta1.ParametersReference) bool {
 
 	return true
 }
+
+// validateContourDeployment checks the values of params, returning
+// a message for each invalid one.
+func validateContourDeployment(params *contour_api_v1alpha1.ContourDeployment) []string {
+	var invalidParamsMessages []string
+
+	if params.Spec.Contour != nil {
+		if params.Spec.Contour.NodePlacement != nil {
+			invalidParamsMessages = append(invalidParamsMessages,
+				validateTopologySpreadConstraints("spec.contour.nodePlacement", params.Spec.Contour.NodePlacement.TopologySpreadConstraints)...)
+		}
+
+		invalidParamsMessages = append(invalidParamsMessages, validatePodLabels("spec.contour.podLabels", params.Spec.Contour.PodLabels)...)
+
+		invalidParamsMessages = append(invalidParamsMessages, validateKubernetesClientRateLimits(params.Spec.Contour)...)
+
+		for _, suffix := range params.Spec.Contour.AllowedHostnameSuffixes {
+			if errs := validation.IsDNS1123Subdomain(suffix); errs != nil {
+				msg := fmt.Sprintf("invalid ContourDeployment spec.contour.allowedHostnameSuffixes %q, must be a DNS subdomain: %s",
+					suffix, strings.Join(errs, ", "))
+				invalidParamsMessages = append(invalidParamsMessages, msg)
+			}
+		}
+
+		for _, kind := range params.Spec.Contour.DisabledRouteKinds {
+			switch kind {
+			case "HTTPRoute", "TLSRoute", "GRPCRoute":
+			default:
+				msg := fmt.Sprintf("invalid ContourDeployment spec.contour.disabledRouteKinds %q, must be HTTPRoute, TLSRoute or GRPCRoute", kind)
+				invalidParamsMessages = append(invalidParamsMessages, msg)
+			}
+		}
+
+		invalidParamsMessages = append(invalidParamsMessages,
+			validateExtraInitContainers("spec.contour.extraInitContainers", params.Spec.Contour.ExtraInitContainers,
+				deployment.ContourContainerName)...)
+	}
+
+	if params.Spec.Envoy != nil {
+		switch params.Spec.Envoy.WorkloadType {
+		// valid values, nothing to do
+		case "", contour_api_v1alpha1.WorkloadTypeDaemonSet, contour_api_v1alpha1.WorkloadTypeDeployment:
+		// invalid value, set message
+		default:
+			msg := fmt.Sprintf("invalid ContourDeployment spec.envoy.workloadType %q, must be DaemonSet or Deployment", params.Spec.Envoy.WorkloadType)
+			invalidParamsMessages = append(invalidParamsMessages, msg)
+		}
+
+		if params.Spec.Envoy.NetworkPublishing != nil {
+			switch params.Spec.Envoy.NetworkPublishing.Type {
+			// valid values, nothing to do
+			case "", contour_api_v1alpha1.LoadBalancerServicePublishingType, contour_api_v1alpha1.NodePortServicePublishingType, contour_api_v1alpha1.ClusterIPServicePublishingType:
+			// invalid value, set message
+			default:
+				msg := fmt.Sprintf("invalid ContourDeployment spec.envoy.networkPublishing.type %q, must be LoadBalancerService, NoderPortService or ClusterIPService",
+					params.Spec.Envoy.NetworkPublishing.Type)
+				invalidParamsMessages = append(invalidParamsMessages, msg)
+			}
+
+			switch params.Spec.Envoy.NetworkPublishing.ExternalTrafficPolicy {
+			case "", corev1.ServiceExternalTrafficPolicyTypeCluster, corev1.ServiceExternalTrafficPolicyTypeLocal:
+			default:
+				msg := fmt.Sprintf("invalid ContourDeployment spec.envoy.networkPublishing.externalTrafficPolicy %q, must be Local or Cluster",
+					params.Spec.Envoy.NetworkPublishing.ExternalTrafficPolicy)
+				invalidParamsMessages = append(invalidParamsMessages, msg)
+			}
+
+			invalidParamsMessages = append(invalidParamsMessages, validateEnvoySessionAffinity(params.Spec.Envoy.NetworkPublishing)...)
+			invalidParamsMessages = append(invalidParamsMessages, validateEnvoyServicePorts(params.Spec.Envoy.NetworkPublishing.Ports)...)
+			invalidParamsMessages = append(invalidParamsMessages, validateExternalHostname(params.Spec.Envoy.NetworkPublishing.ExternalHostname)...)
+		}
+
+		if params.Spec.Envoy.ExtraVolumeMounts != nil {
+			volumes := map[string]struct{}{}
+			for _, vol := range params.Spec.Envoy.ExtraVolumes {
+				volumes[vol.Name] = struct{}{}
+			}
+			for _, mnt := range params.Spec.Envoy.ExtraVolumeMounts {
+				if _, ok := volumes[mnt.Name]; !ok {
+					msg := fmt.Sprintf("invalid ContourDeployment spec.envoy.extraVolumeMounts, mount to unknown volume: %q", mnt.Name)
+					invalidParamsMessages = append(invalidParamsMessages, msg)
+				}
+			}
+		}
+
+		switch params.Spec.Envoy.DrainStrategy {
+		case "", contour_api_v1alpha1.DrainStrategyGradual, contour_api_v1alpha1.DrainStrategyImmediate:
+		default:
+			msg := fmt.Sprintf("invalid ContourDeployment spec.envoy.drainStrategy %q, must be gradual or immediate",
+				params.Spec.Envoy.DrainStrategy)
+			invalidParamsMessages = append(invalidParamsMessages, msg)
+		}
+
+		if params.Spec.Envoy.NodePlacement != nil {
+			invalidParamsMessages = append(invalidParamsMessages,
+				validateTopologySpreadConstraints("spec.envoy.nodePlacement", params.Spec.Envoy.NodePlacement.TopologySpreadConstraints)...)
+		}
+
+		invalidParamsMessages = append(invalidParamsMessages, validatePodLabels("spec.envoy.podLabels", params.Spec.Envoy.PodLabels)...)
+
+		invalidParamsMessages = append(invalidParamsMessages,
+			validateExtraInitContainers("spec.envoy.extraInitContainers", params.Spec.Envoy.ExtraInitContainers,
+				dataplane.EnvoyInitContainerName, dataplane.EnvoyContainerName, dataplane.ShutdownContainerName)...)
+
+		invalidParamsMessages = append(invalidParamsMessages, validateEnvoyShutdown(params.Spec.Envoy.Shutdown)...)
+
+		invalidParamsMessages = append(invalidParamsMessages, validateShutdownManager(params.Spec.Envoy)...)
+
+		invalidParamsMessages = append(invalidParamsMessages, validateEnvoyPodSecurityContext(params.Spec.Envoy.PodSecurityContext)...)
+
+		if params.Spec.Envoy.MinReadySeconds < 0 {
+			msg := fmt.Sprintf("invalid ContourDeployment spec.envoy.minReadySeconds %d, must not be negative",
+				params.Spec.Envoy.MinReadySeconds)
+			invalidParamsMessages = append(invalidParamsMessages, msg)
+		}
+
+		invalidParamsMessages = append(invalidParamsMessages, validateEnvoyMetrics(params.Spec.Envoy.Metrics)...)
+
+		if backend := params.Spec.Envoy.DefaultBackend; backend != nil {
+			if errs := validation.IsDNS1035Label(backend.Name); errs != nil {
+				msg := fmt.Sprintf("invalid ContourDeployment spec.envoy.defaultBackend.name %q, must be a Service name: %s",
+					backend.Name, strings.Join(errs, ", "))
+				invalidParamsMessages = append(invalidParamsMessages, msg)
+			}
+			if errs := validation.IsValidPortNum(int(backend.Port)); errs != nil {
+				msg := fmt.Sprintf("invalid ContourDeployment spec.envoy.defaultBackend.port %d: %s", backend.Port, strings.Join(errs, ", "))
+				invalidParamsMessages = append(invalidParamsMessages, msg)
+			}
+		}
+
+		if params.Spec.Envoy.Listener != nil && params.Spec.Envoy.Listener.KeepAlive != nil {
+			if err := params.Spec.Envoy.Listener.KeepAlive.Validate(); err != nil {
+				msg := fmt.Sprintf("invalid ContourDeployment spec.envoy.listener.keepAlive: %v", err)
+				invalidParamsMessages = append(invalidParamsMessages, msg)
+			}
+		}
+
+		if params.Spec.Envoy.Listener != nil && params.Spec.Envoy.Listener.HTTP2MaxConcurrentStreams != nil {
+			if err := contour_api_v1alpha1.ValidateHTTP2MaxConcurrentStreams(*params.Spec.Envoy.Listener.HTTP2MaxConcurrentStreams); err != nil {
+				msg := fmt.Sprintf("invalid ContourDeployment spec.envoy.listener.http2MaxConcurrentStreams: %v", err)
+				invalidParamsMessages = append(invalidParamsMessages, msg)
+			}
+		}
+
+		invalidParamsMessages = append(invalidParamsMessages, validateEnvoyExtraArgs(params.Spec.Envoy.ExtraArgs)...)
+
+		switch params.Spec.Envoy.LogLevel {
+		// valid values, nothing to do.
+		case "", v1alpha1.TraceLog, v1alpha1.DebugLog, v1alpha1.InfoLog, v1alpha1.WarnLog, v1alpha1.ErrorLog, v1alpha1.CriticalLog, v1alpha1.OffLog:
+		// invalid value, set message.
+		default:
+			msg := fmt.Sprintf("invalid ContourDeployment spec.envoy.logLevel %q, must be trace, debug, info, warn, error, critical or off",
+				params.Spec.Envoy.LogLevel)
+			invalidParamsMessages = append(invalidParamsMessages, msg)
+		}
+	}
+
+	if params.Spec.XDSServer != nil && params.Spec.XDSServer.TLS != nil {
+		switch params.Spec.XDSServer.TLS.KeyType {
+		case "", contour_api_v1alpha1.XDSServerKeyTypeRSA, contour_api_v1alpha1.XDSServerKeyTypeECDSA:
+		default:
+			msg := fmt.Sprintf("invalid ContourDeployment spec.xdsServer.tls.keyType %q, must be RSA or ECDSA",
+				params.Spec.XDSServer.TLS.KeyType)
+			invalidParamsMessages = append(invalidParamsMessages, msg)
+		}
+
+		if params.Spec.XDSServer.TLS.CertificateLifetime < 0 {
+			msg := fmt.Sprintf("invalid ContourDeployment spec.xdsServer.tls.certificateLifetime %d, must be at least 1",
+				params.Spec.XDSServer.TLS.CertificateLifetime)
+			invalidParamsMessages = append(invalidParamsMessages, msg)
+		}
+	}
+
+	return invalidParamsMessages
+}
+
+// validateEnvoyWorkload checks that the Envoy settings only configure
+// the workload type Envoy runs as, and that a NodePort service with a
+// Local external traffic policy is only used with a DaemonSet, so that
+// every node has an Envoy pod to serve the node port. These checks are
+// only enforced on admission, since GatewayClasses referencing existing
+// ContourDeployments that fail them were previously Accepted.
+func validateEnvoyWorkload(envoy *contour_api_v1alpha1.EnvoySettings) []string {
+	var msgs []string
+
+	switch envoy.WorkloadType {
+	case "", contour_api_v1alpha1.WorkloadTypeDaemonSet:
+		if envoy.Replicas != 0 { // nolint:staticcheck
+			msgs = append(msgs, "invalid ContourDeployment spec.envoy.replicas, must not be set when spec.envoy.workloadType is DaemonSet")
+		}
+		if envoy.Deployment != nil {
+			msgs = append(msgs, "invalid ContourDeployment spec.envoy.deployment, must not be set when spec.envoy.workloadType is DaemonSet")
+		}
+	case contour_api_v1alpha1.WorkloadTypeDeployment:
+		if envoy.DaemonSet != nil {
+			msgs = append(msgs, "invalid ContourDeployment spec.envoy.daemonSet, must not be set when spec.envoy.workloadType is Deployment")
+		}
+		if np := envoy.NetworkPublishing; np != nil &&
+			np.Type == contour_api_v1alpha1.NodePortServicePublishingType &&
+			np.ExternalTrafficPolicy == corev1.ServiceExternalTrafficPolicyTypeLocal {
+			msgs = append(msgs, "invalid ContourDeployment spec.envoy.networkPublishing, a NodePortService with a Local externalTrafficPolicy requires spec.envoy.workloadType DaemonSet")
+		}
+	}
+
+	return msgs
+}
+
+// validateEnvoyServicePorts checks that the customized Envoy service
+// ports still give each listener its own port name and target port.
+func validateEnvoyServicePorts(ports []contour_api_v1alpha1.EnvoyServicePort) []string {
+	var msgs []string
+
+	names := map[string]string{"http": "http", "https": "https"}
+	targetPorts := map[string]int32{"http": objects.EnvoyInsecureContainerPort, "https": objects.EnvoySecureContainerPort}
+	for _, port := range ports {
+		if _, ok := names[port.Listener]; !ok {
+			msgs = append(msgs, fmt.Sprintf("invalid ContourDeployment spec.envoy.networkPublishing.ports listener %q, must be http or https", port.Listener))
+			continue
+		}
+		if port.Name != "" {
+			names[port.Listener] = port.Name
+		}
+		if port.TargetPort > 0 {
+			targetPorts[port.Listener] = port.TargetPort
+		}
+	}
+
+	if names["http"] == names["https"] {
+		msgs = append(msgs, fmt.Sprintf("invalid ContourDeployment spec.envoy.networkPublishing.ports, http and https listeners both use port name %q", names["http"]))
+	}
+	if targetPorts["http"] == targetPorts["https"] {
+		msgs = append(msgs, fmt.Sprintf("invalid ContourDeployment spec.envoy.networkPublishing.ports, http and https listeners both use target port %d", targetPorts["http"]))
+	}
+
+	return msgs
+}
+
+// validateEnvoyPodSecurityContext checks that the IDs in the Envoy pod
+// security context are valid and consistent with running as non-root.
+func validateEnvoyPodSecurityContext(sc *corev1.PodSecurityContext) []string {
+	if sc == nil {
+		return nil
+	}
+
+	var msgs []string
+
+	ids := []struct {
+		field string
+		id    *int64
+	}{
+		{"runAsUser", sc.RunAsUser},
+		{"runAsGroup", sc.RunAsGroup},
+		{"fsGroup", sc.FSGroup},
+	}
+	for _, id := range ids {
+		if id.id != nil && *id.id < 0 {
+			msgs = append(msgs, fmt.Sprintf("invalid ContourDeployment spec.envoy.podSecurityContext.%s %d, must not be negative", id.field, *id.id))
+		}
+	}
+	for _, group := range sc.SupplementalGroups {
+		if group < 0 {
+			msgs = append(msgs, fmt.Sprintf("invalid ContourDeployment spec.envoy.podSecurityContext.supplementalGroups %d, must not be negative", group))
+		}
+	}
+
+	// Envoy defaults to running as non-root, so running as root
+	// must be asked for explicitly.
+	if sc.RunAsUser != nil && *sc.RunAsUser == 0 && (sc.RunAsNonRoot == nil || *sc.RunAsNonRoot) {
+		msgs = append(msgs, "invalid ContourDeployment spec.envoy.podSecurityContext.runAsUser 0, runAsNonRoot must be false to run as root")
+	}
+
+	return msgs
+}
+
+// validateEnvoyShutdown checks that the Envoy shutdown durations are valid,
+// and that the drain timeout leaves time for Envoy to drain.
+func validateEnvoyShutdown(shutdown *contour_api_v1alpha1.EnvoyShutdownSettings) []string {
+	if shutdown == nil {
+		return nil
+	}
+
+	var msgs []string
+
+	durations := []struct {
+		field string
+		value string
+		d     time.Duration
+	}{
+		{field: "drainTimeout", value: shutdown.DrainTimeout},
+		{field: "drainDelay", value: shutdown.DrainDelay},
+		{field: "checkDelay", value: shutdown.CheckDelay},
+		{field: "checkInterval", value: shutdown.CheckInterval},
+	}
+	valid := true
+	for i := range durations {
+		if durations[i].value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(durations[i].value)
+		if err != nil || d < 0 {
+			msgs = append(msgs, fmt.Sprintf("invalid ContourDeployment spec.envoy.shutdown.%s %q, must be a non-negative duration", durations[i].field, durations[i].value))
+			valid = false
+			continue
+		}
+		durations[i].d = d
+	}
+	if !valid {
+		return msgs
+	}
+
+	drainTimeout, drainDelay, checkDelay := durations[0].d, durations[1].d, durations[2].d
+	if shutdown.DrainTimeout != "" && drainTimeout == 0 {
+		msgs = append(msgs, fmt.Sprintf("invalid ContourDeployment spec.envoy.shutdown.drainTimeout %q, must be greater than zero", shutdown.DrainTimeout))
+	}
+
+	// Without a drain timeout, the pods keep the default
+	// termination grace period.
+	if drainTimeout == 0 {
+		drainTimeout = dataplane.DefaultTerminationGracePeriod
+	}
+	if drainDelay+checkDelay >= drainTimeout {
+		msgs = append(msgs, fmt.Sprintf("invalid ContourDeployment spec.envoy.shutdown, drainDelay and checkDelay (%s) must be shorter than drainTimeout (%s)",
+			drainDelay+checkDelay, drainTimeout))
+	}
+
+	return msgs
+}
+
+// validateEnvoyExtraArgs checks that the extra Envoy args don't set
+// any of the flags managed by the provisioner.
+func validateEnvoyExtraArgs(args []string) []string {
+	var msgs []string
+
+	for i, arg := range args {
+		// The flag's value can follow it in the same arg.
+		flag := arg
+		if idx := strings.IndexAny(arg, " ="); idx >= 0 {
+			flag = arg[:idx]
+		}
+		if dataplane.IsManagedEnvoyArg(flag) {
+			msgs = append(msgs, fmt.Sprintf("invalid ContourDeployment spec.envoy.extraArgs[%d] %q, %s is managed by the provisioner", i, arg, flag))
+		}
+	}
+
+	return msgs
+}
+
+// validateShutdownManager checks that the shutdown settings run by the
+// shutdown-manager's preStop hook are not set when the sidecar is disabled.
+func validateShutdownManager(envoy *contour_api_v1alpha1.EnvoySettings) []string {
+	if envoy.ShutdownManager == nil || envoy.ShutdownManager.Enabled == nil || *envoy.ShutdownManager.Enabled {
+		return nil
+	}
+	shutdown := envoy.Shutdown
+	if shutdown == nil {
+		return nil
+	}
+
+	var fields []string
+	if shutdown.DrainDelay != "" {
+		fields = append(fields, "drainDelay")
+	}
+	if shutdown.CheckDelay != "" {
+		fields = append(fields, "checkDelay")
+	}
+	if shutdown.CheckInterval != "" {
+		fields = append(fields, "checkInterval")
+	}
+	if shutdown.MinOpenConnections != nil {
+		fields = append(fields, "minOpenConnections")
+	}
+
+	var msgs []string
+	for _, field := range fields {
+		msgs = append(msgs, fmt.Sprintf("invalid ContourDeployment spec.envoy.shutdown.%s, must not be set when spec.envoy.shutdownManager.enabled is false", field))
+	}
+	return msgs
+}
+
+// validateEnvoyMetrics checks that the Envoy stats flush interval is
+// within the bounds Envoy accepts, and that the histogram buckets can
+// be rendered into Envoy's bootstrap.
+func validateEnvoyMetrics(metrics *contour_api_v1alpha1.EnvoyMetricsSettings) []string {
+	if metrics == nil {
+		return nil
+	}
+
+	var msgs []string
+	if metrics.FlushInterval != "" {
+		interval, err := time.ParseDuration(metrics.FlushInterval)
+		switch {
+		case err != nil:
+			msgs = append(msgs, fmt.Sprintf("invalid ContourDeployment spec.envoy.metrics.flushInterval %q: %v", metrics.FlushInterval, err))
+		case interval < time.Second:
+			msgs = append(msgs, fmt.Sprintf("invalid ContourDeployment spec.envoy.metrics.flushInterval %q, must be at least 1s", metrics.FlushInterval))
+		case interval >= 5*time.Minute:
+			msgs = append(msgs, fmt.Sprintf("invalid ContourDeployment spec.envoy.metrics.flushInterval %q, must be less than 5m", metrics.FlushInterval))
+		}
+	}
+
+	for i, hb := range metrics.HistogramBuckets {
+		field := fmt.Sprintf("invalid ContourDeployment spec.envoy.metrics.histogramBuckets[%d]", i)
+
+		if hb.Regex == "" {
+			msgs = append(msgs, fmt.Sprintf("%s.regex, must not be empty", field))
+		} else if _, err := regexp.Compile(hb.Regex); err != nil {
+			msgs = append(msgs, fmt.Sprintf("%s.regex %q: %v", field, hb.Regex, err))
+		}
+
+		if len(hb.Buckets) == 0 {
+			msgs = append(msgs, fmt.Sprintf("%s.buckets, must not be empty", field))
+		}
+		prev := 0.0
+		for j, b := range hb.Buckets {
+			v, err := strconv.ParseFloat(b, 64)
+			switch {
+			case err != nil || v <= 0 || math.IsNaN(v) || math.IsInf(v, 0):
+				msgs = append(msgs, fmt.Sprintf("%s.buckets[%d] %q, must be a positive number", field, j, b))
+			case v <= prev:
+				msgs = append(msgs, fmt.Sprintf("%s.buckets[%d] %q, must be greater than the previous bucket", field, j, b))
+			default:
+				prev = v
+			}
+		}
+	}
+	return msgs
+}
+
+// validateTopologySpreadConstraints checks the fields of the topology spread
+// constraints set in the nodePlacement at path.
+func validateTopologySpreadConstraints(path string, constraints []corev1.TopologySpreadConstraint) []string {
+	var msgs []string
+
+	for i, constraint := range constraints {
+		field := fmt.Sprintf("invalid ContourDeployment %s.topologySpreadConstraints[%d]", path, i)
+
+		if constraint.MaxSkew < 1 {
+			msgs = append(msgs, fmt.Sprintf("%s.maxSkew %d, must be greater than zero", field, constraint.MaxSkew))
+		}
+		if constraint.TopologyKey == "" {
+			msgs = append(msgs, fmt.Sprintf("%s.topologyKey, must not be empty", field))
+		}
+
+		switch constraint.WhenUnsatisfiable {
+		case corev1.DoNotSchedule:
+		case corev1.ScheduleAnyway:
+			if constraint.MinDomains != nil {
+				msgs = append(msgs, fmt.Sprintf("%s.minDomains, can only be set when whenUnsatisfiable is DoNotSchedule", field))
+			}
+		default:
+			msgs = append(msgs, fmt.Sprintf("%s.whenUnsatisfiable %q, must be DoNotSchedule or ScheduleAnyway", field, constraint.WhenUnsatisfiable))
+		}
+
+		if constraint.MinDomains != nil && *constraint.MinDomains < 1 {
+			msgs = append(msgs, fmt.Sprintf("%s.minDomains %d, must be greater than zero", field, *constraint.MinDomains))
+		}
+
+		if _, err := metav1.LabelSelectorAsSelector(constraint.LabelSelector); err != nil {
+			msgs = append(msgs, fmt.Sprintf("%s.labelSelector: %v", field, err))
+		}
+	}
+
+	return msgs
+}
+
+// validatePodLabels checks that the pod labels at path are valid labels,
+// and that they don't collide with the labels pods are selected by.
+func validatePodLabels(path string, labels map[string]string) []string {
+	var msgs []string
+
+	for k, v := range labels {
+		if k == model.PodSelectorLabel {
+			msgs = append(msgs, fmt.Sprintf("invalid ContourDeployment %s, label %q is used by the pod selector", path, k))
+			continue
+		}
+		for _, msg := range validation.IsQualifiedName(k) {
+			msgs = append(msgs, fmt.Sprintf("invalid ContourDeployment %s, label key %q: %s", path, k, msg))
+		}
+		for _, msg := range validation.IsValidLabelValue(v) {
+			msgs = append(msgs, fmt.Sprintf("invalid ContourDeployment %s, label %q value %q: %s", path, k, v, msg))
+		}
+	}
+
+	// Map iteration order is random, keep the condition message stable.
+	sort.Strings(msgs)
+
+	return msgs
+}
+
+// validateExtraInitContainers checks that the extra init containers
+// have an image and unique names, none of which is used by one of the
+// containers the provisioner manages in the pods.
+func validateExtraInitContainers(path string, containers []corev1.Container, managedNames ...string) []string {
+	var msgs []string
+
+	names := map[string]struct{}{}
+	for _, name := range managedNames {
+		names[name] = struct{}{}
+	}
+
+	for i, container := range containers {
+		if errs := validation.IsDNS1123Label(container.Name); errs != nil {
+			msgs = append(msgs, fmt.Sprintf("invalid ContourDeployment %s[%d] name %q: %s", path, i, container.Name, strings.Join(errs, ", ")))
+		} else if _, ok := names[container.Name]; ok {
+			msgs = append(msgs, fmt.Sprintf("invalid ContourDeployment %s[%d] name %q, already used by another container", path, i, container.Name))
+		}
+		names[container.Name] = struct{}{}
+
+		if container.Image == "" {
+			msgs = append(msgs, fmt.Sprintf("invalid ContourDeployment %s[%d] %q, image must be set", path, i, container.Name))
+		}
+	}
+
+	return msgs
+}
+
+// validateEnvoySessionAffinity checks the session affinity of the Envoy
+// service, and that its timeout is only set for the ClientIP affinity.
+func validateEnvoySessionAffinity(networkPublishing *contour_api_v1alpha1.NetworkPublishing) []string {
+	var msgs []string
+
+	switch networkPublishing.SessionAffinity {
+	case "", corev1.ServiceAffinityNone, corev1.ServiceAffinityClientIP:
+	default:
+		msgs = append(msgs, fmt.Sprintf("invalid ContourDeployment spec.envoy.networkPublishing.sessionAffinity %q, must be ClientIP or None",
+			networkPublishing.SessionAffinity))
+	}
+
+	// The maximum Kubernetes accepts, one day.
+	const maxTimeoutSeconds = 86400
+
+	timeoutSeconds := networkPublishing.SessionAffinityTimeoutSeconds
+	switch {
+	case timeoutSeconds == 0:
+	case networkPublishing.SessionAffinity != corev1.ServiceAffinityClientIP:
+		msgs = append(msgs, fmt.Sprintf("invalid ContourDeployment spec.envoy.networkPublishing.sessionAffinityTimeoutSeconds %d, only valid with the ClientIP session affinity",
+			timeoutSeconds))
+	case timeoutSeconds < 0 || timeoutSeconds > maxTimeoutSeconds:
+		msgs = append(msgs, fmt.Sprintf("invalid ContourDeployment spec.envoy.networkPublishing.sessionAffinityTimeoutSeconds %d, must be between 1 and %d",
+			timeoutSeconds, maxTimeoutSeconds))
+	}
+
+	return msgs
+}
+
+// validateExternalHostname checks that the external hostname is a DNS
+// name. An IP address would be advertised with the IPAddress type.
+func validateExternalHostname(hostname string) []string {
+	if hostname == "" {
+		return nil
+	}
+
+	if net.ParseIP(hostname) != nil {
+		return []string{fmt.Sprintf("invalid ContourDeployment spec.envoy.networkPublishing.externalHostname %q, must be a hostname, not an IP address", hostname)}
+	}
+
+	var msgs []string
+	for _, msg := range validation.IsDNS1123Subdomain(hostname) {
+		msgs = append(msgs, fmt.Sprintf("invalid ContourDeployment spec.envoy.networkPublishing.externalHostname %q: %s", hostname, msg))
+	}
+	return msgs
+}
+
+// validateKubernetesClientRateLimits checks that the rate limits of
+// Contour's Kubernetes client are not negative, and that the burst
+// allows at least one second's worth of queries.
+func validateKubernetesClientRateLimits(contour *contour_api_v1alpha1.ContourSettings) []string {
+	var msgs []string
+
+	if contour.KubernetesClientQPS < 0 {
+		msgs = append(msgs, fmt.Sprintf("invalid ContourDeployment spec.contour.kubernetesClientQPS %d, must not be negative", contour.KubernetesClientQPS))
+	}
+	if contour.KubernetesClientBurst < 0 {
+		msgs = append(msgs, fmt.Sprintf("invalid ContourDeployment spec.contour.kubernetesClientBurst %d, must not be negative", contour.KubernetesClientBurst))
+	}
+	if contour.KubernetesClientBurst > 0 && contour.KubernetesClientBurst < contour.KubernetesClientQPS {
+		msgs = append(msgs, fmt.Sprintf("invalid ContourDeployment spec.contour.kubernetesClientBurst %d, must not be lower than kubernetesClientQPS %d",
+			contour.KubernetesClientBurst, contour.KubernetesClientQPS))
+	}
+
+	return msgs
+}
@@ -0,0 +1,72 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	ReconcileTotal           = "contour_provisioner_reconcile_total"
+	ReconcileErrorsTotal     = "contour_provisioner_reconcile_errors_total"
+	ReconcileDurationSeconds = "contour_provisioner_reconcile_duration_seconds"
+
+	kindGateway           = "Gateway"
+	kindGatewayClass      = "GatewayClass"
+	kindContourDeployment = "ContourDeployment"
+)
+
+var (
+	reconcileTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: ReconcileTotal,
+			Help: "Total number of reconciles by the gateway provisioner, by kind of resource. ContourDeployments are counted when validated by the admission webhook.",
+		},
+		[]string{"kind"},
+	)
+	reconcileErrorsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: ReconcileErrorsTotal,
+			Help: "Total number of reconciles by the gateway provisioner that returned an error, by kind of resource. ContourDeployments are counted when rejected by the admission webhook.",
+		},
+		[]string{"kind"},
+	)
+	reconcileDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    ReconcileDurationSeconds,
+			Help:    "Duration of reconciles by the gateway provisioner, by kind of resource.",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"kind"},
+	)
+)
+
+func init() {
+	// Registering with controller-runtime's registry exposes the
+	// metrics on the manager's metrics endpoint.
+	metrics.Registry.MustRegister(reconcileTotal, reconcileErrorsTotal, reconcileDurationSeconds)
+}
+
+// observeReconcile records a reconcile of kind that started at start
+// and returned *err. It's meant to be deferred by reconcilers.
+func observeReconcile(kind string, start time.Time, err *error) {
+	reconcileTotal.WithLabelValues(kind).Inc()
+	if *err != nil {
+		reconcileErrorsTotal.WithLabelValues(kind).Inc()
+	}
+	reconcileDurationSeconds.WithLabelValues(kind).Observe(time.Since(start).Seconds())
+}
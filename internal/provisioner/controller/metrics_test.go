@@ -0,0 +1,144 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	contourv1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
+	"github.com/projectcontour/contour/internal/provisioner"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func TestReconcileMetrics(t *testing.T) {
+	const controller = "projectcontour.io/gateway-controller"
+
+	scheme, err := provisioner.CreateScheme()
+	require.NoError(t, err)
+
+	gatewayClass := &gatewayv1beta1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "gatewayclass-1",
+		},
+		Spec: gatewayv1beta1.GatewayClassSpec{
+			ControllerName: gatewayv1beta1.GatewayController(controller),
+		},
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gatewayClass).Build()
+
+	gatewayReconciler := &gatewayReconciler{
+		gatewayController: controller,
+		client:            cli,
+		log:               logr.Discard(),
+	}
+	gatewayClassReconciler := &gatewayClassReconciler{
+		gatewayController: controller,
+		client:            cli,
+		log:               logr.Discard(),
+	}
+	validator := &contourDeploymentValidator{}
+
+	// The metrics are global, so compare them to what they were before.
+	before := scrapeReconcileMetrics(t)
+
+	_, err = gatewayReconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "gateway-1", Name: "gateway-1"}})
+	require.NoError(t, err)
+	_, err = gatewayClassReconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "gatewayclass-1"}})
+	require.NoError(t, err)
+	require.NoError(t, validator.ValidateCreate(context.Background(), &contourv1alpha1.ContourDeployment{}))
+	require.Error(t, validator.ValidateCreate(context.Background(), &contourv1alpha1.ContourDeployment{
+		Spec: contourv1alpha1.ContourDeploymentSpec{
+			Envoy: &contourv1alpha1.EnvoySettings{
+				LogLevel: "verbose",
+			},
+		},
+	}))
+
+	after := scrapeReconcileMetrics(t)
+
+	want := map[string]reconcileMetrics{
+		kindGateway:           {total: 1, errors: 0, durations: 1},
+		kindGatewayClass:      {total: 1, errors: 0, durations: 1},
+		kindContourDeployment: {total: 2, errors: 1, durations: 2},
+	}
+	for kind, w := range want {
+		assert.Equalf(t, w.total, after[kind].total-before[kind].total, "%s reconciles", kind)
+		assert.Equalf(t, w.errors, after[kind].errors-before[kind].errors, "%s reconcile errors", kind)
+		assert.Equalf(t, w.durations, after[kind].durations-before[kind].durations, "%s reconcile durations", kind)
+	}
+}
+
+type reconcileMetrics struct {
+	total, errors float64
+	durations     uint64
+}
+
+// scrapeReconcileMetrics scrapes the metrics served on the manager's
+// metrics endpoint and returns the reconcile metrics by kind.
+func scrapeReconcileMetrics(t *testing.T) map[string]reconcileMetrics {
+	t.Helper()
+
+	srv := httptest.NewServer(promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{}))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(resp.Body)
+	require.NoError(t, err)
+
+	kindOf := func(m *dto.Metric) string {
+		for _, label := range m.GetLabel() {
+			if label.GetName() == "kind" {
+				return label.GetValue()
+			}
+		}
+		return ""
+	}
+
+	res := map[string]reconcileMetrics{}
+	for _, m := range families[ReconcileTotal].GetMetric() {
+		r := res[kindOf(m)]
+		r.total = m.GetCounter().GetValue()
+		res[kindOf(m)] = r
+	}
+	for _, m := range families[ReconcileErrorsTotal].GetMetric() {
+		r := res[kindOf(m)]
+		r.errors = m.GetCounter().GetValue()
+		res[kindOf(m)] = r
+	}
+	for _, m := range families[ReconcileDurationSeconds].GetMetric() {
+		r := res[kindOf(m)]
+		r.durations = m.GetHistogram().GetSampleCount()
+		res[kindOf(m)] = r
+	}
+	return res
+}
@@ -37,6 +37,11 @@ func DaemonsetConfigChanged(current, expected *appsv1.DaemonSet) (*appsv1.Daemon
 		updated.Spec = expected.Spec
 	}
 
+	if !apiequality.Semantic.DeepEqual(current.OwnerReferences, expected.OwnerReferences) {
+		changed = true
+		updated.OwnerReferences = expected.OwnerReferences
+	}
+
 	if !changed {
 		return nil, false
 	}
@@ -65,6 +70,11 @@ func DeploymentConfigChanged(current, expected *appsv1.Deployment) (*appsv1.Depl
 		changed = true
 	}
 
+	if !apiequality.Semantic.DeepEqual(current.OwnerReferences, expected.OwnerReferences) {
+		updated = expected
+		changed = true
+	}
+
 	if !changed {
 		return nil, false
 	}
@@ -98,8 +108,10 @@ func ClusterIPServiceChanged(current, expected *corev1.Service) (*corev1.Service
 		changed = true
 	}
 
-	if !apiequality.Semantic.DeepEqual(current.Spec.SessionAffinity, expected.Spec.SessionAffinity) {
+	if !apiequality.Semantic.DeepEqual(current.Spec.SessionAffinity, expected.Spec.SessionAffinity) ||
+		!apiequality.Semantic.DeepEqual(current.Spec.SessionAffinityConfig, expected.Spec.SessionAffinityConfig) {
 		updated.Spec.SessionAffinity = expected.Spec.SessionAffinity
+		updated.Spec.SessionAffinityConfig = expected.Spec.SessionAffinityConfig
 		changed = true
 	}
 
@@ -157,8 +169,10 @@ func LoadBalancerServiceChanged(current, expected *corev1.Service) (*corev1.Serv
 		changed = true
 	}
 
-	if !apiequality.Semantic.DeepEqual(current.Spec.SessionAffinity, expected.Spec.SessionAffinity) {
+	if !apiequality.Semantic.DeepEqual(current.Spec.SessionAffinity, expected.Spec.SessionAffinity) ||
+		!apiequality.Semantic.DeepEqual(current.Spec.SessionAffinityConfig, expected.Spec.SessionAffinityConfig) {
 		updated.Spec.SessionAffinity = expected.Spec.SessionAffinity
+		updated.Spec.SessionAffinityConfig = expected.Spec.SessionAffinityConfig
 		changed = true
 	}
 
@@ -213,8 +227,10 @@ func NodePortServiceChanged(current, expected *corev1.Service) (*corev1.Service,
 		changed = true
 	}
 
-	if !apiequality.Semantic.DeepEqual(current.Spec.SessionAffinity, expected.Spec.SessionAffinity) {
+	if !apiequality.Semantic.DeepEqual(current.Spec.SessionAffinity, expected.Spec.SessionAffinity) ||
+		!apiequality.Semantic.DeepEqual(current.Spec.SessionAffinityConfig, expected.Spec.SessionAffinityConfig) {
 		updated.Spec.SessionAffinity = expected.Spec.SessionAffinity
+		updated.Spec.SessionAffinityConfig = expected.Spec.SessionAffinityConfig
 		changed = true
 	}
 
@@ -356,3 +372,93 @@ func RoleBindingConfigChanged(current, expected *rbacv1.RoleBinding) (*rbacv1.Ro
 
 	return updated, true
 }
+
+// PodTemplateChanges describes the differences between the current and
+// expected pod templates of a workload. Updating the workload to the
+// expected pod template rolls out its pods for these reasons. Nil is
+// returned if the pod templates match.
+func PodTemplateChanges(current, expected *corev1.PodTemplateSpec) []string {
+	var changes []string
+	add := func(change string) {
+		for _, c := range changes {
+			if c == change {
+				return
+			}
+		}
+		changes = append(changes, change)
+	}
+
+	if !apiequality.Semantic.DeepEqual(current.Labels, expected.Labels) {
+		add("pod labels changed")
+	}
+	if !apiequality.Semantic.DeepEqual(current.Annotations, expected.Annotations) {
+		add("pod annotations changed")
+	}
+
+	for _, containers := range [][2][]corev1.Container{
+		{current.Spec.InitContainers, expected.Spec.InitContainers},
+		{current.Spec.Containers, expected.Spec.Containers},
+	} {
+		for _, change := range containerChanges(containers[0], containers[1]) {
+			add(change)
+		}
+	}
+
+	if !apiequality.Semantic.DeepEqual(current.Spec.Volumes, expected.Spec.Volumes) {
+		add("volumes changed")
+	}
+
+	currentSpec, expectedSpec := current.Spec.DeepCopy(), expected.Spec.DeepCopy()
+	for _, spec := range []*corev1.PodSpec{currentSpec, expectedSpec} {
+		spec.InitContainers = nil
+		spec.Containers = nil
+		spec.Volumes = nil
+	}
+	if !apiequality.Semantic.DeepEqual(currentSpec, expectedSpec) {
+		add("pod spec changed")
+	}
+
+	return changes
+}
+
+// containerChanges describes the differences between the current and
+// expected containers of a pod template.
+func containerChanges(current, expected []corev1.Container) []string {
+	if len(current) != len(expected) {
+		return []string{"containers changed"}
+	}
+
+	var changes []string
+	for i := range current {
+		c, e := current[i].DeepCopy(), expected[i].DeepCopy()
+		if c.Name != e.Name {
+			return []string{"containers changed"}
+		}
+
+		if c.Image != e.Image {
+			changes = append(changes, "image changed")
+		}
+		if !apiequality.Semantic.DeepEqual(c.Resources, e.Resources) {
+			changes = append(changes, "resources changed")
+		}
+		if !apiequality.Semantic.DeepEqual(c.Command, e.Command) || !apiequality.Semantic.DeepEqual(c.Args, e.Args) {
+			changes = append(changes, "args changed")
+		}
+		if !apiequality.Semantic.DeepEqual(c.Env, e.Env) {
+			changes = append(changes, "env changed")
+		}
+
+		for _, container := range []*corev1.Container{c, e} {
+			container.Image = ""
+			container.Resources = corev1.ResourceRequirements{}
+			container.Command = nil
+			container.Args = nil
+			container.Env = nil
+		}
+		if !apiequality.Semantic.DeepEqual(c, e) {
+			changes = append(changes, "container "+c.Name+" changed")
+		}
+	}
+
+	return changes
+}
@@ -21,9 +21,12 @@ import (
 	"github.com/projectcontour/contour/internal/provisioner/objects/dataplane"
 	"github.com/projectcontour/contour/internal/provisioner/objects/deployment"
 	"github.com/projectcontour/contour/internal/provisioner/objects/service"
+	"github.com/projectcontour/contour/internal/ref"
 
+	"github.com/stretchr/testify/assert"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
@@ -58,6 +61,13 @@ func TestDaemonSetConfigChanged(t *testing.T) {
 			},
 			expect: true,
 		},
+		{
+			description: "if owner references are changed",
+			mutate: func(ds *appsv1.DaemonSet) {
+				ds.OwnerReferences = []metav1.OwnerReference{{Kind: "Gateway", Name: "gateway", UID: "gateway-uid"}}
+			},
+			expect: true,
+		},
 		{
 			description: "if selector is changed",
 			mutate: func(ds *appsv1.DaemonSet) {
@@ -153,6 +163,13 @@ func TestDeploymentConfigChanged(t *testing.T) {
 			},
 			expect: true,
 		},
+		{
+			description: "if owner references are changed",
+			mutate: func(deploy *appsv1.Deployment) {
+				deploy.OwnerReferences = []metav1.OwnerReference{{Kind: "Gateway", Name: "gateway", UID: "gateway-uid"}}
+			},
+			expect: true,
+		},
 		{
 			description: "if selector is changed",
 			mutate: func(deploy *appsv1.Deployment) {
@@ -228,6 +245,67 @@ func TestDeploymentConfigChanged(t *testing.T) {
 	}
 }
 
+func TestPodTemplateChanges(t *testing.T) {
+	testCases := []struct {
+		description string
+		mutate      func(template *corev1.PodTemplateSpec)
+		expect      []string
+	}{
+		{
+			description: "if nothing changes",
+			mutate:      func(_ *corev1.PodTemplateSpec) {},
+		},
+		{
+			description: "if the container image is changed",
+			mutate: func(template *corev1.PodTemplateSpec) {
+				template.Spec.Containers[0].Image = "foo:latest"
+			},
+			expect: []string{"image changed"},
+		},
+		{
+			description: "if the container resources and args are changed",
+			mutate: func(template *corev1.PodTemplateSpec) {
+				template.Spec.Containers[0].Resources.Limits = corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("1"),
+				}
+				template.Spec.Containers[0].Args = []string{"foo"}
+			},
+			expect: []string{"resources changed", "args changed"},
+		},
+		{
+			description: "if the container env and probes are changed",
+			mutate: func(template *corev1.PodTemplateSpec) {
+				template.Spec.Containers[0].Env = nil
+				template.Spec.Containers[0].ReadinessProbe = nil
+			},
+			expect: []string{"env changed", "container contour changed"},
+		},
+		{
+			description: "if a container is added",
+			mutate: func(template *corev1.PodTemplateSpec) {
+				template.Spec.Containers = append(template.Spec.Containers, corev1.Container{Name: "sidecar"})
+			},
+			expect: []string{"containers changed"},
+		},
+		{
+			description: "if the pod annotations, volumes and node placement are changed",
+			mutate: func(template *corev1.PodTemplateSpec) {
+				template.Annotations = map[string]string{"foo": "bar"}
+				template.Spec.Volumes = nil
+				template.Spec.NodeSelector = map[string]string{"foo": "bar"}
+			},
+			expect: []string{"pod annotations changed", "volumes changed", "pod spec changed"},
+		},
+	}
+
+	for _, tc := range testCases {
+		original := deployment.DesiredDeployment(cntr, testImage)
+		mutated := original.DeepCopy()
+		tc.mutate(&mutated.Spec.Template)
+		assert.Equal(t, tc.expect, equality.PodTemplateChanges(&original.Spec.Template, &mutated.Spec.Template), tc.description)
+	}
+}
+
 func TestClusterIpServiceChanged(t *testing.T) {
 	testCases := []struct {
 		description string
@@ -316,6 +394,15 @@ func TestClusterIpServiceChanged(t *testing.T) {
 			},
 			expect: true,
 		},
+		{
+			description: "if session affinity config changed",
+			mutate: func(svc *corev1.Service) {
+				svc.Spec.SessionAffinityConfig = &corev1.SessionAffinityConfig{
+					ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: ref.To(int32(600))},
+				}
+			},
+			expect: true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -421,6 +508,15 @@ func TestLoadBalancerServiceChanged(t *testing.T) {
 			},
 			expect: true,
 		},
+		{
+			description: "if session affinity config changed",
+			mutate: func(svc *corev1.Service) {
+				svc.Spec.SessionAffinityConfig = &corev1.SessionAffinityConfig{
+					ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: ref.To(int32(600))},
+				}
+			},
+			expect: true,
+		},
 		{
 			description: "if external traffic policy changed",
 			mutate: func(svc *corev1.Service) {
@@ -519,6 +615,16 @@ func TestNodePortServiceChanged(t *testing.T) {
 			},
 			expect: true,
 		},
+		{
+			description: "if session affinity config changed",
+			mutate: func(svc *corev1.Service) {
+				svc.Spec.SessionAffinity = corev1.ServiceAffinityClientIP
+				svc.Spec.SessionAffinityConfig = &corev1.SessionAffinityConfig{
+					ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: ref.To(int32(600))},
+				}
+			},
+			expect: true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -14,6 +14,8 @@
 package model
 
 import (
+	"time"
+
 	contourv1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
 	opintstr "github.com/projectcontour/contour/internal/provisioner/intstr"
 
@@ -27,6 +29,10 @@ const (
 	// OwningGatewayNameLabel is the owner reference label used for a Contour
 	// created by the gateway provisioner. The value should be the name of the Gateway.
 	OwningGatewayNameLabel = "projectcontour.io/owning-gateway-name"
+
+	// PodSelectorLabel is the label that the Contour Deployment and the
+	// Envoy workload select their pods by.
+	PodSelectorLabel = "app"
 )
 
 // Default returns a default instance of a Contour
@@ -103,6 +109,18 @@ func (c *Contour) ContourTolerationsExist() bool {
 	return false
 }
 
+// ContourTopologySpreadConstraintsExist returns true if topology spread
+// constraints are set for Contour.
+func (c *Contour) ContourTopologySpreadConstraintsExist() bool {
+	if c.Spec.NodePlacement != nil &&
+		c.Spec.NodePlacement.Contour != nil &&
+		len(c.Spec.NodePlacement.Contour.TopologySpreadConstraints) > 0 {
+		return true
+	}
+
+	return false
+}
+
 // EnvoyNodeSelectorExists returns true if a nodeSelector is specified for Envoy.
 func (c *Contour) EnvoyNodeSelectorExists() bool {
 	if c.Spec.NodePlacement != nil &&
@@ -125,6 +143,18 @@ func (c *Contour) EnvoyTolerationsExist() bool {
 	return false
 }
 
+// EnvoyTopologySpreadConstraintsExist returns true if topology spread
+// constraints are set for Envoy.
+func (c *Contour) EnvoyTopologySpreadConstraintsExist() bool {
+	if c.Spec.NodePlacement != nil &&
+		c.Spec.NodePlacement.Envoy != nil &&
+		len(c.Spec.NodePlacement.Envoy.TopologySpreadConstraints) > 0 {
+		return true
+	}
+
+	return false
+}
+
 // ContourSpec defines the desired state of Contour.
 type ContourSpec struct {
 	// ContourReplicas is the desired number of Contour replicas. If unset,
@@ -183,6 +213,28 @@ type ContourSpec struct {
 	// defaults to 0.
 	KubernetesLogLevel uint8
 
+	// KubernetesClientQPS and KubernetesClientBurst rate limit Contour's
+	// Kubernetes client. If unset, the client-go defaults are used.
+	KubernetesClientQPS   int32
+	KubernetesClientBurst int32
+
+	// ContourUseEndpointSlices makes Contour watch EndpointSlices rather
+	// than Endpoints. If unset, the user-provided runtime settings are
+	// used, and EndpointSlices otherwise.
+	ContourUseEndpointSlices *bool
+
+	// ContourAllowedHostnameSuffixes restricts the hostnames of the
+	// routes attached to the Gateway to those under one of the suffixes.
+	ContourAllowedHostnameSuffixes []string
+
+	// ContourDisabledRouteKinds are the route kinds the Gateway does
+	// not support.
+	ContourDisabledRouteKinds []string
+
+	// ContourExtraInitContainers are run before the init containers of
+	// contour's pods.
+	ContourExtraInitContainers []corev1.Container
+
 	// An update strategy to replace existing Envoy DaemonSet pods with new pods.
 	// when envoy be running as a `Deployment`,it's must be nil
 	// +optional
@@ -199,24 +251,98 @@ type ContourSpec struct {
 	// ResourceLabels is a set of labels to add to the provisioned Contour resource(s).
 	ResourceLabels map[string]string
 
+	// ContourPodLabels holds the labels that will be added to contour's pods,
+	// without being part of the deployment's selector.
+	ContourPodLabels map[string]string
+
 	// EnvoyExtraVolumes holds the extra volumes to add to envoy's pod.
 	EnvoyExtraVolumes []corev1.Volume
 
 	// EnvoyExtraVolumeMounts holds the extra volume mounts to add to envoy's pod(normally used with envoyExtraVolumes).
 	EnvoyExtraVolumeMounts []corev1.VolumeMount
 
+	// EnvoyExtraInitContainers are run before the init containers of
+	// envoy's pods.
+	EnvoyExtraInitContainers []corev1.Container
+
 	// EnvoyPodAnnotations holds the annotations that will be add to the envoy‘s pod.
 	EnvoyPodAnnotations map[string]string
 
+	// EnvoyPodLabels holds the labels that will be added to envoy's pods,
+	// without being part of the workload's selector.
+	EnvoyPodLabels map[string]string
+
 	// Compute Resources required by envoy container.
 	EnvoyResources corev1.ResourceRequirements
 
+	// EnvoyDrainStrategy is how envoy drains connections on shutdown.
+	EnvoyDrainStrategy contourv1alpha1.DrainStrategy
+
+	// EnvoyShutdown is how envoy pods drain connections when they are stopped.
+	EnvoyShutdown EnvoyShutdownSettings
+
+	// EnvoyShutdownManagerDisabled omits the shutdown-manager sidecar,
+	// and the preStop hooks that drain envoy, from envoy's pods.
+	EnvoyShutdownManagerDisabled bool
+
+	// EnvoyPodSecurityContext holds the security attributes set on the
+	// envoy pods, on top of the unprivileged defaults.
+	EnvoyPodSecurityContext *corev1.PodSecurityContext
+
+	// EnvoyReadOnlyRootFilesystem runs the containers of the envoy pods
+	// with a read-only root filesystem.
+	EnvoyReadOnlyRootFilesystem bool
+
+	// EnvoyMinReadySeconds is the minimum number of seconds a new envoy
+	// pod must be ready for before it is considered available.
+	EnvoyMinReadySeconds int32
+
+	// EnvoyStatsFlushInterval is how often envoy flushes its stats.
+	// If zero, envoy's default is used.
+	EnvoyStatsFlushInterval time.Duration
+
+	// EnvoyHistogramBuckets overrides the buckets of envoy's histograms
+	// whose names match.
+	EnvoyHistogramBuckets []contourv1alpha1.EnvoyHistogramBuckets
+
+	// EnvoyDefaultBackend is the Service in the Gateway's namespace
+	// that requests no route matches are routed to. If unset, envoy
+	// responds with a 404.
+	EnvoyDefaultBackend *contourv1alpha1.EnvoyDefaultBackend
+
 	// Compute Resources required by contour container.
 	ContourResources corev1.ResourceRequirements
 
 	// EnvoyLogLevel sets the log level for Envoy
 	// Allowed values are "trace", "debug", "info", "warn", "error", "critical", "off".
 	EnvoyLogLevel contourv1alpha1.LogLevel
+
+	// EnvoyExtraArgs are appended to the args of the envoy container.
+	EnvoyExtraArgs []string
+
+	// EnvoyDefaultResponseHeaders holds the headers set, added or removed
+	// on every response from the Gateway.
+	EnvoyDefaultResponseHeaders *contourv1alpha1.HeadersPolicy
+
+	// EnvoyDefaultLoadBalancerPolicy is the load balancer strategy of
+	// clusters that do not set their own.
+	EnvoyDefaultLoadBalancerPolicy string
+
+	// EnvoyListenerKeepAlive configures the TCP and HTTP/2 keepalive of
+	// downstream connections to the Gateway's listeners.
+	EnvoyListenerKeepAlive *contourv1alpha1.EnvoyListenerKeepAlive
+
+	// EnvoyListenerHTTP2MaxConcurrentStreams caps the concurrent streams
+	// on each downstream HTTP/2 connection to the Gateway's listeners.
+	EnvoyListenerHTTP2MaxConcurrentStreams *uint32
+
+	// XDSServerKeyType is the type of the private keys of the
+	// generated xDS certificates. Defaults to RSA if unset.
+	XDSServerKeyType contourv1alpha1.XDSServerKeyType
+
+	// XDSServerCertificateLifetime is the number of days the generated
+	// xDS certificates are valid for. Defaults to 365 if unset.
+	XDSServerCertificateLifetime int32
 }
 
 // WorkloadType is the type of Kubernetes workload to use for a component.
@@ -230,6 +356,29 @@ const (
 	WorkloadTypeDeployment = contourv1alpha1.WorkloadTypeDeployment
 )
 
+// EnvoyShutdownSettings is how envoy pods shut down. Zero values leave
+// the corresponding setting at its default.
+type EnvoyShutdownSettings struct {
+	// DrainTimeout is the termination grace period of envoy pods,
+	// and envoy's drain time.
+	DrainTimeout time.Duration
+
+	// DrainDelay is how long the shutdown waits before draining envoy.
+	DrainDelay time.Duration
+
+	// CheckDelay is how long the shutdown waits after draining
+	// starts before polling envoy's open connections.
+	CheckDelay time.Duration
+
+	// CheckInterval is how often the shutdown polls envoy's
+	// open connections.
+	CheckInterval time.Duration
+
+	// MinOpenConnections is the number of open connections at
+	// or below which the shutdown completes.
+	MinOpenConnections *int32
+}
+
 // NodePlacement describes node scheduling configuration of Contour and Envoy pods.
 type NodePlacement struct {
 	// Contour describes node scheduling configuration of Contour pods.
@@ -261,6 +410,11 @@ type ContourNodePlacement struct {
 	// See https://kubernetes.io/docs/concepts/configuration/taint-and-toleration/
 	// for additional details.
 	Tolerations []corev1.Toleration
+
+	// TopologySpreadConstraints describe how the Contour pods are spread across
+	// topology domains. A constraint without a labelSelector selects the
+	// Contour pods.
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint
 }
 
 // EnvoyNodePlacement describes node scheduling configuration for Envoy pods.
@@ -284,6 +438,11 @@ type EnvoyNodePlacement struct {
 	// See https://kubernetes.io/docs/concepts/configuration/taint-and-toleration/
 	// for additional details.
 	Tolerations []corev1.Toleration
+
+	// TopologySpreadConstraints describe how the Envoy pods are spread across
+	// topology domains. A constraint without a labelSelector selects the
+	// Envoy pods.
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint
 }
 
 // NamespaceSpec defines the schema of a Contour namespace.
@@ -364,12 +523,28 @@ type EnvoyNetworkPublishing struct {
 	// ServiceAnnotations is a set of annotations to add to the provisioned Envoy service.
 	ServiceAnnotations map[string]string
 
+	// ProxyProtocol configures the Envoy listeners to expect a PROXY
+	// protocol header on every connection.
+	ProxyProtocol bool
+
+	// ExternalHostname is the hostname set in the Gateway's status
+	// addresses in place of the Envoy service's address.
+	ExternalHostname string
+
 	// ExternalTrafficPolicy describes how nodes distribute service traffic they
 	// receive on one of the Service's "externally-facing" addresses (NodePorts, ExternalIPs,
 	// and LoadBalancer IPs).
 	//
 	// If unset, defaults to "Local".
 	ExternalTrafficPolicy corev1.ServiceExternalTrafficPolicyType
+
+	// SessionAffinity is the session affinity of the Envoy service.
+	// If unset, defaults to "None".
+	SessionAffinity corev1.ServiceAffinity
+
+	// SessionAffinityTimeoutSeconds is the timeout of the "ClientIP"
+	// session affinity. If zero, the Kubernetes default is used.
+	SessionAffinityTimeoutSeconds int32
 }
 
 type NetworkPublishingType = contourv1alpha1.NetworkPublishingType
@@ -545,8 +720,13 @@ type GCPLoadBalancerParameters struct {
 }
 
 type Port struct {
-	// Name is the name to use for the port on the Envoy service and workload.
+	// Name identifies the port as Envoy's "http" or "https" listener port.
+	// Unless CustomName is set, it is also the name to use for the port on the
+	// Envoy service and workload.
 	Name string
+	// CustomName, if set, is the name to use for the port on the Envoy service
+	// and workload instead of Name.
+	CustomName string
 	// ServicePort is the port to expose on the Envoy service.
 	ServicePort int32
 	// ContainerPort is the port to expose on the Envoy container(s).
@@ -565,6 +745,15 @@ type Port struct {
 	NodePort int32
 }
 
+// ServicePortName returns the name to use for the port on the Envoy
+// service and workload.
+func (p Port) ServicePortName() string {
+	if p.CustomName != "" {
+		return p.CustomName
+	}
+	return p.Name
+}
+
 const (
 	// ContourAvailableConditionType indicates that the contour is running
 	// and available.
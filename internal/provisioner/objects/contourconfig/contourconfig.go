@@ -19,25 +19,30 @@ import (
 	contour_api_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
 	"github.com/projectcontour/contour/internal/provisioner/model"
 	"github.com/projectcontour/contour/internal/provisioner/objects"
+	"github.com/projectcontour/contour/internal/ref"
 
 	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // EnsureContourConfig ensures that a ContourConfiguration exists for the given contour.
+// It is controlled by the contour's owner references, so that it is garbage
+// collected with the Gateway even when the Gateway's deletion is missed.
 func EnsureContourConfig(ctx context.Context, cli client.Client, contour *model.Contour) error {
 	desired := &contour_api_v1alpha1.ContourConfiguration{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: contour.Namespace,
-			Name:      contour.ContourConfigurationName(),
-			Labels:    model.CommonLabels(contour),
+			Namespace:       contour.Namespace,
+			Name:            contour.ContourConfigurationName(),
+			Labels:          model.CommonLabels(contour),
+			OwnerReferences: contour.OwnerReferences,
 		},
 	}
 
 	// Take any user-provided Config as a base.
 	if contour.Spec.RuntimeSettings != nil {
-		desired.Spec = *contour.Spec.RuntimeSettings
+		desired.Spec = *contour.Spec.RuntimeSettings.DeepCopy()
 	}
 
 	// Override Gateway-specific settings to ensure the Contour is
@@ -46,6 +51,18 @@ func EnsureContourConfig(ctx context.Context, cli client.Client, contour *model.
 
 	updater := func(ctx context.Context, cli client.Client, current, desired *contour_api_v1alpha1.ContourConfiguration) error {
 		maybeUpdated := current.DeepCopy()
+		maybeUpdated.OwnerReferences = desired.OwnerReferences
+
+		// PROXY protocol may have been disabled since the
+		// ContourConfiguration was created, so reset it to the desired
+		// value before applying the Gateway-specific settings.
+		if envoy := maybeUpdated.Spec.Envoy; envoy != nil && envoy.Listener != nil {
+			var useProxyProto *bool
+			if desired.Spec.Envoy != nil && desired.Spec.Envoy.Listener != nil {
+				useProxyProto = desired.Spec.Envoy.Listener.UseProxyProto
+			}
+			envoy.Listener.UseProxyProto = useProxyProto
+		}
 		setGatewayConfig(maybeUpdated, contour)
 
 		if !equality.Semantic.DeepEqual(current, maybeUpdated) {
@@ -63,6 +80,16 @@ func setGatewayConfig(config *contour_api_v1alpha1.ContourConfiguration, contour
 			Namespace: contour.Namespace,
 			Name:      contour.Name,
 		},
+		AllowedHostnameSuffixes: contour.Spec.ContourAllowedHostnameSuffixes,
+		DisabledRouteKinds:      contour.Spec.ContourDisabledRouteKinds,
+	}
+
+	if backend := contour.Spec.EnvoyDefaultBackend; backend != nil {
+		config.Spec.Gateway.DefaultBackend = &contour_api_v1alpha1.GatewayDefaultBackend{
+			Namespace: contour.Namespace,
+			Name:      backend.Name,
+			Port:      backend.Port,
+		}
 	}
 
 	if config.Spec.Envoy == nil {
@@ -72,6 +99,248 @@ func setGatewayConfig(config *contour_api_v1alpha1.ContourConfiguration, contour
 		Namespace: contour.Namespace,
 		Name:      contour.EnvoyServiceName(),
 	}
+
+	// PROXY protocol is enabled through the network publishing settings,
+	// but the user-provided runtime settings are respected when it is not.
+	if contour.Spec.NetworkPublishing.Envoy.ProxyProtocol {
+		if config.Spec.Envoy.Listener == nil {
+			config.Spec.Envoy.Listener = &contour_api_v1alpha1.EnvoyListenerConfig{}
+		}
+		config.Spec.Envoy.Listener.UseProxyProto = ref.To(true)
+	}
+
+	// EndpointSlices are used unless disabled, either explicitly or
+	// through the user-provided runtime settings.
+	var runtimeUseEndpointSlices *bool
+	if rs := contour.Spec.RuntimeSettings; rs != nil {
+		runtimeUseEndpointSlices = rs.UseEndpointSlices
+	}
+	switch {
+	case contour.Spec.ContourUseEndpointSlices != nil:
+		config.Spec.UseEndpointSlices = ref.To(*contour.Spec.ContourUseEndpointSlices)
+	case runtimeUseEndpointSlices != nil:
+		config.Spec.UseEndpointSlices = ref.To(*runtimeUseEndpointSlices)
+	default:
+		config.Spec.UseEndpointSlices = ref.To(true)
+	}
+
+	// ExternalName Services are disabled unless enabled, either
+	// explicitly or through the user-provided runtime settings. Leaving
+	// the field unset keeps Contour's default, which disables them.
+	var runtimeEnableExternalNameService *bool
+	if rs := contour.Spec.RuntimeSettings; rs != nil {
+		runtimeEnableExternalNameService = rs.EnableExternalNameService
+	}
+	switch {
+	case contour.Spec.EnableExternalNameService != nil:
+		config.Spec.EnableExternalNameService = ref.To(*contour.Spec.EnableExternalNameService)
+	case runtimeEnableExternalNameService != nil:
+		config.Spec.EnableExternalNameService = ref.To(*runtimeEnableExternalNameService)
+	default:
+		config.Spec.EnableExternalNameService = nil
+	}
+
+	setStatusAddress(config, contour)
+	setListenerPorts(config, contour)
+	setDefaultResponseHeaders(config, contour)
+	setDefaultLoadBalancerPolicy(config, contour)
+	setListenerKeepAlive(config, contour)
+	setListenerHTTP2MaxConcurrentStreams(config, contour)
+}
+
+// setStatusAddress makes Contour advertise the external hostname in the
+// Gateway's status, in place of the Envoy service's address.
+func setStatusAddress(config *contour_api_v1alpha1.ContourConfiguration, contour *model.Contour) {
+	var runtimeStatusAddress string
+	if rs := contour.Spec.RuntimeSettings; rs != nil && rs.Ingress != nil {
+		runtimeStatusAddress = rs.Ingress.StatusAddress
+	}
+	switch {
+	case contour.Spec.NetworkPublishing.Envoy.ExternalHostname != "":
+		if config.Spec.Ingress == nil {
+			config.Spec.Ingress = &contour_api_v1alpha1.IngressConfig{}
+		}
+		config.Spec.Ingress.StatusAddress = contour.Spec.NetworkPublishing.Envoy.ExternalHostname
+	case config.Spec.Ingress != nil:
+		config.Spec.Ingress.StatusAddress = runtimeStatusAddress
+	}
+}
+
+// setListenerPorts binds Envoy's listeners to the container ports the
+// Envoy service targets. When the default container ports are used, the
+// listener ports from the user-provided runtime settings are kept.
+func setListenerPorts(config *contour_api_v1alpha1.ContourConfiguration, contour *model.Contour) {
+	var runtimeEnvoy *contour_api_v1alpha1.EnvoyConfig
+	if contour.Spec.RuntimeSettings != nil && contour.Spec.RuntimeSettings.Envoy != nil {
+		runtimeEnvoy = contour.Spec.RuntimeSettings.Envoy
+	}
+
+	for _, port := range contour.Spec.NetworkPublishing.Envoy.Ports {
+		switch port.Name {
+		case "http":
+			var runtimeListener *contour_api_v1alpha1.EnvoyListener
+			if runtimeEnvoy != nil {
+				runtimeListener = runtimeEnvoy.HTTPListener
+			}
+			config.Spec.Envoy.HTTPListener = listenerWithPort(config.Spec.Envoy.HTTPListener, runtimeListener, port.ContainerPort, objects.EnvoyInsecureContainerPort)
+		case "https":
+			var runtimeListener *contour_api_v1alpha1.EnvoyListener
+			if runtimeEnvoy != nil {
+				runtimeListener = runtimeEnvoy.HTTPSListener
+			}
+			config.Spec.Envoy.HTTPSListener = listenerWithPort(config.Spec.Envoy.HTTPSListener, runtimeListener, port.ContainerPort, objects.EnvoySecureContainerPort)
+		}
+	}
+}
+
+func listenerWithPort(current, runtime *contour_api_v1alpha1.EnvoyListener, containerPort, defaultPort int32) *contour_api_v1alpha1.EnvoyListener {
+	if containerPort != defaultPort {
+		if current == nil {
+			current = &contour_api_v1alpha1.EnvoyListener{}
+		}
+		current.Port = int(containerPort)
+		return current
+	}
+
+	// Restore the runtime settings in case the container port was reset.
+	if current != nil {
+		current.Port = 0
+		if runtime != nil {
+			current.Port = runtime.Port
+		}
+	}
+	return current
+}
+
+// setDefaultResponseHeaders renders the Envoy default response headers
+// into the global response headers policy, on top of any policy from
+// the user-provided runtime settings, and applies it to Gateway API routes.
+func setDefaultResponseHeaders(config *contour_api_v1alpha1.ContourConfiguration, contour *model.Contour) {
+	var runtimePolicy *contour_api_v1alpha1.PolicyConfig
+	if contour.Spec.RuntimeSettings != nil {
+		runtimePolicy = contour.Spec.RuntimeSettings.Policy
+	}
+
+	defaults := contour.Spec.EnvoyDefaultResponseHeaders
+	if defaults == nil {
+		// Restore the runtime settings in case the defaults were removed.
+		if config.Spec.Policy != nil {
+			if runtimePolicy != nil {
+				config.Spec.Policy.ResponseHeadersPolicy = runtimePolicy.ResponseHeadersPolicy.DeepCopy()
+				config.Spec.Policy.ApplyToGatewayAPI = runtimePolicy.ApplyToGatewayAPI
+			} else {
+				config.Spec.Policy.ResponseHeadersPolicy = nil
+				config.Spec.Policy.ApplyToGatewayAPI = nil
+			}
+		}
+		return
+	}
+
+	policy := &contour_api_v1alpha1.HeadersPolicy{}
+	if runtimePolicy != nil && runtimePolicy.ResponseHeadersPolicy != nil {
+		policy = runtimePolicy.ResponseHeadersPolicy.DeepCopy()
+	}
+
+	for k, v := range defaults.Set {
+		if policy.Set == nil {
+			policy.Set = map[string]string{}
+		}
+		policy.Set[k] = v
+	}
+	for k, v := range defaults.Add {
+		if policy.Add == nil {
+			policy.Add = map[string]string{}
+		}
+		policy.Add[k] = v
+	}
+	removed := sets.New(policy.Remove...)
+	for _, header := range defaults.Remove {
+		if !removed.Has(header) {
+			policy.Remove = append(policy.Remove, header)
+			removed.Insert(header)
+		}
+	}
+
+	if config.Spec.Policy == nil {
+		config.Spec.Policy = &contour_api_v1alpha1.PolicyConfig{}
+	}
+	config.Spec.Policy.ResponseHeadersPolicy = policy
+	config.Spec.Policy.ApplyToGatewayAPI = ref.To(true)
+}
+
+// setDefaultLoadBalancerPolicy renders the Envoy default load balancer
+// policy into the cluster parameters, falling back to the policy from
+// the user-provided runtime settings when none is set.
+func setDefaultLoadBalancerPolicy(config *contour_api_v1alpha1.ContourConfiguration, contour *model.Contour) {
+	policy := contour.Spec.EnvoyDefaultLoadBalancerPolicy
+	if policy == "" {
+		if config.Spec.Envoy.Cluster == nil {
+			return
+		}
+
+		// Restore the runtime settings in case the default was removed.
+		var runtimePolicy string
+		if rs := contour.Spec.RuntimeSettings; rs != nil && rs.Envoy != nil && rs.Envoy.Cluster != nil {
+			runtimePolicy = rs.Envoy.Cluster.DefaultLoadBalancerPolicy
+		}
+		config.Spec.Envoy.Cluster.DefaultLoadBalancerPolicy = runtimePolicy
+		return
+	}
+
+	if config.Spec.Envoy.Cluster == nil {
+		config.Spec.Envoy.Cluster = &contour_api_v1alpha1.ClusterParameters{}
+	}
+	config.Spec.Envoy.Cluster.DefaultLoadBalancerPolicy = policy
+}
+
+// setListenerKeepAlive renders the keepalive of downstream connections
+// into the listener parameters, falling back to the keepalive from the
+// user-provided runtime settings when none is set.
+func setListenerKeepAlive(config *contour_api_v1alpha1.ContourConfiguration, contour *model.Contour) {
+	keepAlive := contour.Spec.EnvoyListenerKeepAlive
+	if keepAlive == nil {
+		if config.Spec.Envoy.Listener == nil {
+			return
+		}
+
+		// Restore the runtime settings in case the keepalive was removed.
+		var runtimeKeepAlive *contour_api_v1alpha1.EnvoyListenerKeepAlive
+		if rs := contour.Spec.RuntimeSettings; rs != nil && rs.Envoy != nil && rs.Envoy.Listener != nil {
+			runtimeKeepAlive = rs.Envoy.Listener.KeepAlive.DeepCopy()
+		}
+		config.Spec.Envoy.Listener.KeepAlive = runtimeKeepAlive
+		return
+	}
+
+	if config.Spec.Envoy.Listener == nil {
+		config.Spec.Envoy.Listener = &contour_api_v1alpha1.EnvoyListenerConfig{}
+	}
+	config.Spec.Envoy.Listener.KeepAlive = keepAlive.DeepCopy()
+}
+
+// setListenerHTTP2MaxConcurrentStreams renders the cap on concurrent
+// streams of downstream HTTP/2 connections into the listener parameters,
+// falling back to the user-provided runtime settings when none is set.
+func setListenerHTTP2MaxConcurrentStreams(config *contour_api_v1alpha1.ContourConfiguration, contour *model.Contour) {
+	streams := contour.Spec.EnvoyListenerHTTP2MaxConcurrentStreams
+	if streams == nil {
+		if config.Spec.Envoy.Listener == nil {
+			return
+		}
+
+		// Restore the runtime settings in case the cap was removed.
+		var runtimeStreams *uint32
+		if rs := contour.Spec.RuntimeSettings; rs != nil && rs.Envoy != nil && rs.Envoy.Listener != nil && rs.Envoy.Listener.HTTP2MaxConcurrentStreams != nil {
+			runtimeStreams = ref.To(*rs.Envoy.Listener.HTTP2MaxConcurrentStreams)
+		}
+		config.Spec.Envoy.Listener.HTTP2MaxConcurrentStreams = runtimeStreams
+		return
+	}
+
+	if config.Spec.Envoy.Listener == nil {
+		config.Spec.Envoy.Listener = &contour_api_v1alpha1.EnvoyListenerConfig{}
+	}
+	config.Spec.Envoy.Listener.HTTP2MaxConcurrentStreams = ref.To(*streams)
 }
 
 // EnsureContourConfigDeleted deletes a ContourConfig for the provided contour, if the configured owner labels exist.
@@ -19,6 +19,7 @@ import (
 
 	contour_api_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
 	"github.com/projectcontour/contour/internal/provisioner/model"
+	"github.com/projectcontour/contour/internal/ref"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -42,6 +43,7 @@ func TestEnsureContourConfig(t *testing.T) {
 				},
 			},
 			want: contour_api_v1alpha1.ContourConfigurationSpec{
+				UseEndpointSlices: ref.To(true),
 				Gateway: &contour_api_v1alpha1.GatewayConfig{
 					GatewayRef: &contour_api_v1alpha1.NamespacedName{
 						Namespace: "contour-namespace-1",
@@ -69,6 +71,7 @@ func TestEnsureContourConfig(t *testing.T) {
 					Name:      "contourconfig-contour-1",
 				},
 				Spec: contour_api_v1alpha1.ContourConfigurationSpec{
+					UseEndpointSlices: ref.To(true),
 					Gateway: &contour_api_v1alpha1.GatewayConfig{
 						GatewayRef: &contour_api_v1alpha1.NamespacedName{
 							Namespace: "contour-namespace-1",
@@ -84,6 +87,7 @@ func TestEnsureContourConfig(t *testing.T) {
 				},
 			},
 			want: contour_api_v1alpha1.ContourConfigurationSpec{
+				UseEndpointSlices: ref.To(true),
 				Gateway: &contour_api_v1alpha1.GatewayConfig{
 					GatewayRef: &contour_api_v1alpha1.NamespacedName{
 						Namespace: "contour-namespace-1",
@@ -126,6 +130,7 @@ func TestEnsureContourConfig(t *testing.T) {
 				},
 			},
 			want: contour_api_v1alpha1.ContourConfigurationSpec{
+				UseEndpointSlices: ref.To(true),
 				Gateway: &contour_api_v1alpha1.GatewayConfig{
 					GatewayRef: &contour_api_v1alpha1.NamespacedName{
 						Namespace: "contour-namespace-1",
@@ -175,6 +180,7 @@ func TestEnsureContourConfig(t *testing.T) {
 				},
 			},
 			want: contour_api_v1alpha1.ContourConfigurationSpec{
+				UseEndpointSlices: ref.To(true),
 				Gateway: &contour_api_v1alpha1.GatewayConfig{
 					GatewayRef: &contour_api_v1alpha1.NamespacedName{
 						Namespace: "contour-namespace-1",
@@ -196,6 +202,912 @@ func TestEnsureContourConfig(t *testing.T) {
 				},
 			},
 		},
+		"no existing ContourConfiguration, PROXY protocol enabled": {
+			contour: &model.Contour{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contour-1",
+				},
+				Spec: model.ContourSpec{
+					NetworkPublishing: model.NetworkPublishing{
+						Envoy: model.EnvoyNetworkPublishing{
+							ProxyProtocol: true,
+						},
+					},
+				},
+			},
+			want: contour_api_v1alpha1.ContourConfigurationSpec{
+				UseEndpointSlices: ref.To(true),
+				Gateway: &contour_api_v1alpha1.GatewayConfig{
+					GatewayRef: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "contour-1",
+					},
+				},
+				Envoy: &contour_api_v1alpha1.EnvoyConfig{
+					Service: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "envoy-contour-1",
+					},
+					Listener: &contour_api_v1alpha1.EnvoyListenerConfig{
+						UseProxyProto: ref.To(true),
+					},
+				},
+			},
+		},
+		"existing ContourConfiguration found, PROXY protocol disabled": {
+			contour: &model.Contour{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contour-1",
+				},
+			},
+			existing: &contour_api_v1alpha1.ContourConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contourconfig-contour-1",
+				},
+				Spec: contour_api_v1alpha1.ContourConfigurationSpec{
+					Gateway: &contour_api_v1alpha1.GatewayConfig{
+						GatewayRef: &contour_api_v1alpha1.NamespacedName{
+							Namespace: "contour-namespace-1",
+							Name:      "contour-1",
+						},
+					},
+					Envoy: &contour_api_v1alpha1.EnvoyConfig{
+						Service: &contour_api_v1alpha1.NamespacedName{
+							Namespace: "contour-namespace-1",
+							Name:      "envoy-contour-1",
+						},
+						Listener: &contour_api_v1alpha1.EnvoyListenerConfig{
+							UseProxyProto: ref.To(true),
+						},
+					},
+				},
+			},
+			want: contour_api_v1alpha1.ContourConfigurationSpec{
+				UseEndpointSlices: ref.To(true),
+				Gateway: &contour_api_v1alpha1.GatewayConfig{
+					GatewayRef: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "contour-1",
+					},
+				},
+				Envoy: &contour_api_v1alpha1.EnvoyConfig{
+					Service: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "envoy-contour-1",
+					},
+					Listener: &contour_api_v1alpha1.EnvoyListenerConfig{},
+				},
+			},
+		},
+		"existing ContourConfiguration found, PROXY protocol enabled by runtime settings": {
+			contour: &model.Contour{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contour-1",
+				},
+				Spec: model.ContourSpec{
+					RuntimeSettings: &contour_api_v1alpha1.ContourConfigurationSpec{
+						Envoy: &contour_api_v1alpha1.EnvoyConfig{
+							Listener: &contour_api_v1alpha1.EnvoyListenerConfig{
+								UseProxyProto: ref.To(true),
+							},
+						},
+					},
+				},
+			},
+			existing: &contour_api_v1alpha1.ContourConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contourconfig-contour-1",
+				},
+				Spec: contour_api_v1alpha1.ContourConfigurationSpec{
+					Envoy: &contour_api_v1alpha1.EnvoyConfig{
+						Listener: &contour_api_v1alpha1.EnvoyListenerConfig{
+							UseProxyProto: ref.To(true),
+						},
+					},
+				},
+			},
+			want: contour_api_v1alpha1.ContourConfigurationSpec{
+				UseEndpointSlices: ref.To(true),
+				Gateway: &contour_api_v1alpha1.GatewayConfig{
+					GatewayRef: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "contour-1",
+					},
+				},
+				Envoy: &contour_api_v1alpha1.EnvoyConfig{
+					Service: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "envoy-contour-1",
+					},
+					Listener: &contour_api_v1alpha1.EnvoyListenerConfig{
+						UseProxyProto: ref.To(true),
+					},
+				},
+			},
+		},
+		"no existing ContourConfiguration, external hostname set": {
+			contour: &model.Contour{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contour-1",
+				},
+				Spec: model.ContourSpec{
+					NetworkPublishing: model.NetworkPublishing{
+						Envoy: model.EnvoyNetworkPublishing{
+							ExternalHostname: "gateway.projectcontour.io",
+						},
+					},
+				},
+			},
+			want: contour_api_v1alpha1.ContourConfigurationSpec{
+				UseEndpointSlices: ref.To(true),
+				Ingress: &contour_api_v1alpha1.IngressConfig{
+					StatusAddress: "gateway.projectcontour.io",
+				},
+				Gateway: &contour_api_v1alpha1.GatewayConfig{
+					GatewayRef: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "contour-1",
+					},
+				},
+				Envoy: &contour_api_v1alpha1.EnvoyConfig{
+					Service: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "envoy-contour-1",
+					},
+				},
+			},
+		},
+		"existing ContourConfiguration found, external hostname removed": {
+			contour: &model.Contour{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contour-1",
+				},
+			},
+			existing: &contour_api_v1alpha1.ContourConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contourconfig-contour-1",
+				},
+				Spec: contour_api_v1alpha1.ContourConfigurationSpec{
+					Ingress: &contour_api_v1alpha1.IngressConfig{
+						StatusAddress: "gateway.projectcontour.io",
+					},
+					Gateway: &contour_api_v1alpha1.GatewayConfig{
+						GatewayRef: &contour_api_v1alpha1.NamespacedName{
+							Namespace: "contour-namespace-1",
+							Name:      "contour-1",
+						},
+					},
+					Envoy: &contour_api_v1alpha1.EnvoyConfig{
+						Service: &contour_api_v1alpha1.NamespacedName{
+							Namespace: "contour-namespace-1",
+							Name:      "envoy-contour-1",
+						},
+					},
+				},
+			},
+			want: contour_api_v1alpha1.ContourConfigurationSpec{
+				UseEndpointSlices: ref.To(true),
+				Ingress:           &contour_api_v1alpha1.IngressConfig{},
+				Gateway: &contour_api_v1alpha1.GatewayConfig{
+					GatewayRef: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "contour-1",
+					},
+				},
+				Envoy: &contour_api_v1alpha1.EnvoyConfig{
+					Service: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "envoy-contour-1",
+					},
+				},
+			},
+		},
+		"no existing ContourConfiguration, EndpointSlices disabled": {
+			contour: &model.Contour{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contour-1",
+				},
+				Spec: model.ContourSpec{
+					ContourUseEndpointSlices: ref.To(false),
+					RuntimeSettings: &contour_api_v1alpha1.ContourConfigurationSpec{
+						UseEndpointSlices: ref.To(true),
+					},
+				},
+			},
+			want: contour_api_v1alpha1.ContourConfigurationSpec{
+				UseEndpointSlices: ref.To(false),
+				Gateway: &contour_api_v1alpha1.GatewayConfig{
+					GatewayRef: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "contour-1",
+					},
+				},
+				Envoy: &contour_api_v1alpha1.EnvoyConfig{
+					Service: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "envoy-contour-1",
+					},
+				},
+			},
+		},
+		"existing ContourConfiguration found, EndpointSlices disabled by runtime settings": {
+			contour: &model.Contour{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contour-1",
+				},
+				Spec: model.ContourSpec{
+					RuntimeSettings: &contour_api_v1alpha1.ContourConfigurationSpec{
+						UseEndpointSlices: ref.To(false),
+					},
+				},
+			},
+			existing: &contour_api_v1alpha1.ContourConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contourconfig-contour-1",
+				},
+				Spec: contour_api_v1alpha1.ContourConfigurationSpec{
+					UseEndpointSlices: ref.To(true),
+				},
+			},
+			want: contour_api_v1alpha1.ContourConfigurationSpec{
+				UseEndpointSlices: ref.To(false),
+				Gateway: &contour_api_v1alpha1.GatewayConfig{
+					GatewayRef: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "contour-1",
+					},
+				},
+				Envoy: &contour_api_v1alpha1.EnvoyConfig{
+					Service: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "envoy-contour-1",
+					},
+				},
+			},
+		},
+		"no existing ContourConfiguration, ExternalName Services enabled": {
+			contour: &model.Contour{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contour-1",
+				},
+				Spec: model.ContourSpec{
+					EnableExternalNameService: ref.To(true),
+				},
+			},
+			want: contour_api_v1alpha1.ContourConfigurationSpec{
+				EnableExternalNameService: ref.To(true),
+				UseEndpointSlices:         ref.To(true),
+				Gateway: &contour_api_v1alpha1.GatewayConfig{
+					GatewayRef: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "contour-1",
+					},
+				},
+				Envoy: &contour_api_v1alpha1.EnvoyConfig{
+					Service: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "envoy-contour-1",
+					},
+				},
+			},
+		},
+		"existing ContourConfiguration found, ExternalName Services disabled over runtime settings": {
+			contour: &model.Contour{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contour-1",
+				},
+				Spec: model.ContourSpec{
+					EnableExternalNameService: ref.To(false),
+					RuntimeSettings: &contour_api_v1alpha1.ContourConfigurationSpec{
+						EnableExternalNameService: ref.To(true),
+					},
+				},
+			},
+			existing: &contour_api_v1alpha1.ContourConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contourconfig-contour-1",
+				},
+				Spec: contour_api_v1alpha1.ContourConfigurationSpec{
+					EnableExternalNameService: ref.To(true),
+				},
+			},
+			want: contour_api_v1alpha1.ContourConfigurationSpec{
+				EnableExternalNameService: ref.To(false),
+				UseEndpointSlices:         ref.To(true),
+				Gateway: &contour_api_v1alpha1.GatewayConfig{
+					GatewayRef: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "contour-1",
+					},
+				},
+				Envoy: &contour_api_v1alpha1.EnvoyConfig{
+					Service: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "envoy-contour-1",
+					},
+				},
+			},
+		},
+		"existing ContourConfiguration found, ExternalName Services enabled by runtime settings": {
+			contour: &model.Contour{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contour-1",
+				},
+				Spec: model.ContourSpec{
+					RuntimeSettings: &contour_api_v1alpha1.ContourConfigurationSpec{
+						EnableExternalNameService: ref.To(true),
+					},
+				},
+			},
+			existing: &contour_api_v1alpha1.ContourConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contourconfig-contour-1",
+				},
+			},
+			want: contour_api_v1alpha1.ContourConfigurationSpec{
+				EnableExternalNameService: ref.To(true),
+				UseEndpointSlices:         ref.To(true),
+				Gateway: &contour_api_v1alpha1.GatewayConfig{
+					GatewayRef: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "contour-1",
+					},
+				},
+				Envoy: &contour_api_v1alpha1.EnvoyConfig{
+					Service: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "envoy-contour-1",
+					},
+				},
+			},
+		},
+		"existing ContourConfiguration found, ExternalName Services setting removed": {
+			contour: &model.Contour{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contour-1",
+				},
+			},
+			existing: &contour_api_v1alpha1.ContourConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contourconfig-contour-1",
+				},
+				Spec: contour_api_v1alpha1.ContourConfigurationSpec{
+					EnableExternalNameService: ref.To(true),
+				},
+			},
+			want: contour_api_v1alpha1.ContourConfigurationSpec{
+				UseEndpointSlices: ref.To(true),
+				Gateway: &contour_api_v1alpha1.GatewayConfig{
+					GatewayRef: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "contour-1",
+					},
+				},
+				Envoy: &contour_api_v1alpha1.EnvoyConfig{
+					Service: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "envoy-contour-1",
+					},
+				},
+			},
+		},
+		"no existing ContourConfiguration, allowed hostname suffixes set": {
+			contour: &model.Contour{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contour-1",
+				},
+				Spec: model.ContourSpec{
+					ContourAllowedHostnameSuffixes: []string{"example.com", "projectcontour.io"},
+				},
+			},
+			want: contour_api_v1alpha1.ContourConfigurationSpec{
+				UseEndpointSlices: ref.To(true),
+				Gateway: &contour_api_v1alpha1.GatewayConfig{
+					GatewayRef: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "contour-1",
+					},
+					AllowedHostnameSuffixes: []string{"example.com", "projectcontour.io"},
+				},
+				Envoy: &contour_api_v1alpha1.EnvoyConfig{
+					Service: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "envoy-contour-1",
+					},
+				},
+			},
+		},
+		"no existing ContourConfiguration, disabled route kinds set": {
+			contour: &model.Contour{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contour-1",
+				},
+				Spec: model.ContourSpec{
+					ContourDisabledRouteKinds: []string{"TLSRoute"},
+				},
+			},
+			want: contour_api_v1alpha1.ContourConfigurationSpec{
+				UseEndpointSlices: ref.To(true),
+				Gateway: &contour_api_v1alpha1.GatewayConfig{
+					GatewayRef: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "contour-1",
+					},
+					DisabledRouteKinds: []string{"TLSRoute"},
+				},
+				Envoy: &contour_api_v1alpha1.EnvoyConfig{
+					Service: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "envoy-contour-1",
+					},
+				},
+			},
+		},
+		"no existing ContourConfiguration, default backend set": {
+			contour: &model.Contour{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contour-1",
+				},
+				Spec: model.ContourSpec{
+					EnvoyDefaultBackend: &contour_api_v1alpha1.EnvoyDefaultBackend{
+						Name: "default-backend",
+						Port: 80,
+					},
+				},
+			},
+			want: contour_api_v1alpha1.ContourConfigurationSpec{
+				UseEndpointSlices: ref.To(true),
+				Gateway: &contour_api_v1alpha1.GatewayConfig{
+					GatewayRef: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "contour-1",
+					},
+					DefaultBackend: &contour_api_v1alpha1.GatewayDefaultBackend{
+						Namespace: "contour-namespace-1",
+						Name:      "default-backend",
+						Port:      80,
+					},
+				},
+				Envoy: &contour_api_v1alpha1.EnvoyConfig{
+					Service: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "envoy-contour-1",
+					},
+				},
+			},
+		},
+		"no existing ContourConfiguration, default response headers set": {
+			contour: &model.Contour{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contour-1",
+				},
+				Spec: model.ContourSpec{
+					RuntimeSettings: &contour_api_v1alpha1.ContourConfigurationSpec{
+						Policy: &contour_api_v1alpha1.PolicyConfig{
+							ResponseHeadersPolicy: &contour_api_v1alpha1.HeadersPolicy{
+								Set:    map[string]string{"X-Frame-Options": "DENY"},
+								Remove: []string{"Server"},
+							},
+						},
+					},
+					EnvoyDefaultResponseHeaders: &contour_api_v1alpha1.HeadersPolicy{
+						Set:    map[string]string{"Strict-Transport-Security": "max-age=31536000"},
+						Add:    map[string]string{"X-Served-By": "contour"},
+						Remove: []string{"Server", "X-Powered-By"},
+					},
+				},
+			},
+			want: contour_api_v1alpha1.ContourConfigurationSpec{
+				UseEndpointSlices: ref.To(true),
+				Gateway: &contour_api_v1alpha1.GatewayConfig{
+					GatewayRef: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "contour-1",
+					},
+				},
+				Envoy: &contour_api_v1alpha1.EnvoyConfig{
+					Service: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "envoy-contour-1",
+					},
+				},
+				Policy: &contour_api_v1alpha1.PolicyConfig{
+					ResponseHeadersPolicy: &contour_api_v1alpha1.HeadersPolicy{
+						Set: map[string]string{
+							"X-Frame-Options":           "DENY",
+							"Strict-Transport-Security": "max-age=31536000",
+						},
+						Add:    map[string]string{"X-Served-By": "contour"},
+						Remove: []string{"Server", "X-Powered-By"},
+					},
+					ApplyToGatewayAPI: ref.To(true),
+				},
+			},
+		},
+		"existing ContourConfiguration found, default response headers removed": {
+			contour: &model.Contour{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contour-1",
+				},
+			},
+			existing: &contour_api_v1alpha1.ContourConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contourconfig-contour-1",
+				},
+				Spec: contour_api_v1alpha1.ContourConfigurationSpec{
+					Policy: &contour_api_v1alpha1.PolicyConfig{
+						ResponseHeadersPolicy: &contour_api_v1alpha1.HeadersPolicy{
+							Set: map[string]string{"Strict-Transport-Security": "max-age=31536000"},
+						},
+						ApplyToGatewayAPI: ref.To(true),
+					},
+				},
+			},
+			want: contour_api_v1alpha1.ContourConfigurationSpec{
+				UseEndpointSlices: ref.To(true),
+				Gateway: &contour_api_v1alpha1.GatewayConfig{
+					GatewayRef: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "contour-1",
+					},
+				},
+				Envoy: &contour_api_v1alpha1.EnvoyConfig{
+					Service: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "envoy-contour-1",
+					},
+				},
+				Policy: &contour_api_v1alpha1.PolicyConfig{},
+			},
+		},
+		"no existing ContourConfiguration, default load balancer policy set": {
+			contour: &model.Contour{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contour-1",
+				},
+				Spec: model.ContourSpec{
+					RuntimeSettings: &contour_api_v1alpha1.ContourConfigurationSpec{
+						Envoy: &contour_api_v1alpha1.EnvoyConfig{
+							Cluster: &contour_api_v1alpha1.ClusterParameters{
+								DNSLookupFamily:           contour_api_v1alpha1.IPv4ClusterDNSFamily,
+								DefaultLoadBalancerPolicy: "Random",
+							},
+						},
+					},
+					EnvoyDefaultLoadBalancerPolicy: "WeightedLeastRequest",
+				},
+			},
+			want: contour_api_v1alpha1.ContourConfigurationSpec{
+				UseEndpointSlices: ref.To(true),
+				Gateway: &contour_api_v1alpha1.GatewayConfig{
+					GatewayRef: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "contour-1",
+					},
+				},
+				Envoy: &contour_api_v1alpha1.EnvoyConfig{
+					Service: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "envoy-contour-1",
+					},
+					Cluster: &contour_api_v1alpha1.ClusterParameters{
+						DNSLookupFamily:           contour_api_v1alpha1.IPv4ClusterDNSFamily,
+						DefaultLoadBalancerPolicy: "WeightedLeastRequest",
+					},
+				},
+			},
+		},
+		"existing ContourConfiguration found, default load balancer policy removed": {
+			contour: &model.Contour{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contour-1",
+				},
+			},
+			existing: &contour_api_v1alpha1.ContourConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contourconfig-contour-1",
+				},
+				Spec: contour_api_v1alpha1.ContourConfigurationSpec{
+					Envoy: &contour_api_v1alpha1.EnvoyConfig{
+						Cluster: &contour_api_v1alpha1.ClusterParameters{
+							DefaultLoadBalancerPolicy: "WeightedLeastRequest",
+						},
+					},
+				},
+			},
+			want: contour_api_v1alpha1.ContourConfigurationSpec{
+				UseEndpointSlices: ref.To(true),
+				Gateway: &contour_api_v1alpha1.GatewayConfig{
+					GatewayRef: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "contour-1",
+					},
+				},
+				Envoy: &contour_api_v1alpha1.EnvoyConfig{
+					Service: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "envoy-contour-1",
+					},
+					Cluster: &contour_api_v1alpha1.ClusterParameters{},
+				},
+			},
+		},
+		"no existing ContourConfiguration, listener keepalive set": {
+			contour: &model.Contour{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contour-1",
+				},
+				Spec: model.ContourSpec{
+					RuntimeSettings: &contour_api_v1alpha1.ContourConfigurationSpec{
+						Envoy: &contour_api_v1alpha1.EnvoyConfig{
+							Listener: &contour_api_v1alpha1.EnvoyListenerConfig{
+								ConnectionBalancer: "exact",
+								KeepAlive: &contour_api_v1alpha1.EnvoyListenerKeepAlive{
+									TCP: &contour_api_v1alpha1.TCPKeepAlive{IdleTime: 60},
+								},
+							},
+						},
+					},
+					EnvoyListenerKeepAlive: &contour_api_v1alpha1.EnvoyListenerKeepAlive{
+						HTTP2: &contour_api_v1alpha1.HTTP2KeepAlive{Interval: "30s"},
+					},
+				},
+			},
+			want: contour_api_v1alpha1.ContourConfigurationSpec{
+				UseEndpointSlices: ref.To(true),
+				Gateway: &contour_api_v1alpha1.GatewayConfig{
+					GatewayRef: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "contour-1",
+					},
+				},
+				Envoy: &contour_api_v1alpha1.EnvoyConfig{
+					Service: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "envoy-contour-1",
+					},
+					Listener: &contour_api_v1alpha1.EnvoyListenerConfig{
+						ConnectionBalancer: "exact",
+						KeepAlive: &contour_api_v1alpha1.EnvoyListenerKeepAlive{
+							HTTP2: &contour_api_v1alpha1.HTTP2KeepAlive{Interval: "30s"},
+						},
+					},
+				},
+			},
+		},
+		"existing ContourConfiguration found, listener keepalive removed": {
+			contour: &model.Contour{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contour-1",
+				},
+			},
+			existing: &contour_api_v1alpha1.ContourConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contourconfig-contour-1",
+				},
+				Spec: contour_api_v1alpha1.ContourConfigurationSpec{
+					Envoy: &contour_api_v1alpha1.EnvoyConfig{
+						Listener: &contour_api_v1alpha1.EnvoyListenerConfig{
+							KeepAlive: &contour_api_v1alpha1.EnvoyListenerKeepAlive{
+								HTTP2: &contour_api_v1alpha1.HTTP2KeepAlive{Interval: "30s"},
+							},
+						},
+					},
+				},
+			},
+			want: contour_api_v1alpha1.ContourConfigurationSpec{
+				UseEndpointSlices: ref.To(true),
+				Gateway: &contour_api_v1alpha1.GatewayConfig{
+					GatewayRef: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "contour-1",
+					},
+				},
+				Envoy: &contour_api_v1alpha1.EnvoyConfig{
+					Service: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "envoy-contour-1",
+					},
+					Listener: &contour_api_v1alpha1.EnvoyListenerConfig{},
+				},
+			},
+		},
+		"no existing ContourConfiguration, listener HTTP/2 max concurrent streams set": {
+			contour: &model.Contour{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contour-1",
+				},
+				Spec: model.ContourSpec{
+					RuntimeSettings: &contour_api_v1alpha1.ContourConfigurationSpec{
+						Envoy: &contour_api_v1alpha1.EnvoyConfig{
+							Listener: &contour_api_v1alpha1.EnvoyListenerConfig{
+								HTTP2MaxConcurrentStreams: ref.To(uint32(1000)),
+							},
+						},
+					},
+					EnvoyListenerHTTP2MaxConcurrentStreams: ref.To(uint32(100)),
+				},
+			},
+			want: contour_api_v1alpha1.ContourConfigurationSpec{
+				UseEndpointSlices: ref.To(true),
+				Gateway: &contour_api_v1alpha1.GatewayConfig{
+					GatewayRef: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "contour-1",
+					},
+				},
+				Envoy: &contour_api_v1alpha1.EnvoyConfig{
+					Service: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "envoy-contour-1",
+					},
+					Listener: &contour_api_v1alpha1.EnvoyListenerConfig{
+						HTTP2MaxConcurrentStreams: ref.To(uint32(100)),
+					},
+				},
+			},
+		},
+		"existing ContourConfiguration found, listener HTTP/2 max concurrent streams removed": {
+			contour: &model.Contour{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contour-1",
+				},
+				Spec: model.ContourSpec{
+					RuntimeSettings: &contour_api_v1alpha1.ContourConfigurationSpec{
+						Envoy: &contour_api_v1alpha1.EnvoyConfig{
+							Listener: &contour_api_v1alpha1.EnvoyListenerConfig{
+								HTTP2MaxConcurrentStreams: ref.To(uint32(1000)),
+							},
+						},
+					},
+				},
+			},
+			existing: &contour_api_v1alpha1.ContourConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contourconfig-contour-1",
+				},
+				Spec: contour_api_v1alpha1.ContourConfigurationSpec{
+					Envoy: &contour_api_v1alpha1.EnvoyConfig{
+						Listener: &contour_api_v1alpha1.EnvoyListenerConfig{
+							HTTP2MaxConcurrentStreams: ref.To(uint32(100)),
+						},
+					},
+				},
+			},
+			want: contour_api_v1alpha1.ContourConfigurationSpec{
+				UseEndpointSlices: ref.To(true),
+				Gateway: &contour_api_v1alpha1.GatewayConfig{
+					GatewayRef: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "contour-1",
+					},
+				},
+				Envoy: &contour_api_v1alpha1.EnvoyConfig{
+					Service: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "envoy-contour-1",
+					},
+					Listener: &contour_api_v1alpha1.EnvoyListenerConfig{
+						HTTP2MaxConcurrentStreams: ref.To(uint32(1000)),
+					},
+				},
+			},
+		},
+		"no existing ContourConfiguration, custom container port for the http listener": {
+			contour: &model.Contour{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contour-1",
+				},
+				Spec: model.ContourSpec{
+					NetworkPublishing: model.NetworkPublishing{
+						Envoy: model.EnvoyNetworkPublishing{
+							Ports: []model.Port{
+								{Name: "http", ServicePort: 80, ContainerPort: 8081},
+								{Name: "https", ServicePort: 443, ContainerPort: 8443},
+							},
+						},
+					},
+				},
+			},
+			want: contour_api_v1alpha1.ContourConfigurationSpec{
+				UseEndpointSlices: ref.To(true),
+				Gateway: &contour_api_v1alpha1.GatewayConfig{
+					GatewayRef: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "contour-1",
+					},
+				},
+				Envoy: &contour_api_v1alpha1.EnvoyConfig{
+					Service: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "envoy-contour-1",
+					},
+					HTTPListener: &contour_api_v1alpha1.EnvoyListener{
+						Port: 8081,
+					},
+				},
+			},
+		},
+		"existing ContourConfiguration found, custom container port removed": {
+			contour: &model.Contour{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contour-1",
+				},
+				Spec: model.ContourSpec{
+					NetworkPublishing: model.NetworkPublishing{
+						Envoy: model.EnvoyNetworkPublishing{
+							Ports: []model.Port{
+								{Name: "http", ServicePort: 80, ContainerPort: 8080},
+							},
+						},
+					},
+				},
+			},
+			existing: &contour_api_v1alpha1.ContourConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "contour-namespace-1",
+					Name:      "contourconfig-contour-1",
+				},
+				Spec: contour_api_v1alpha1.ContourConfigurationSpec{
+					Envoy: &contour_api_v1alpha1.EnvoyConfig{
+						HTTPListener: &contour_api_v1alpha1.EnvoyListener{
+							Port: 8081,
+						},
+					},
+				},
+			},
+			want: contour_api_v1alpha1.ContourConfigurationSpec{
+				UseEndpointSlices: ref.To(true),
+				Gateway: &contour_api_v1alpha1.GatewayConfig{
+					GatewayRef: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "contour-1",
+					},
+				},
+				Envoy: &contour_api_v1alpha1.EnvoyConfig{
+					Service: &contour_api_v1alpha1.NamespacedName{
+						Namespace: "contour-namespace-1",
+						Name:      "envoy-contour-1",
+					},
+					HTTPListener: &contour_api_v1alpha1.EnvoyListener{},
+				},
+			},
+		},
 	}
 
 	for name, tc := range tests {
@@ -16,7 +16,10 @@ package dataplane
 import (
 	"context"
 	"fmt"
+	"math"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/projectcontour/contour/internal/provisioner/equality"
 	"github.com/projectcontour/contour/internal/provisioner/labels"
@@ -36,12 +39,14 @@ const (
 	EnvoyContainerName = "envoy"
 	// ShutdownContainerName is the name of the Shutdown Manager container.
 	ShutdownContainerName = "shutdown-manager"
-	// envoyInitContainerName is the name of the Envoy init container.
-	envoyInitContainerName = "envoy-initconfig"
+	// EnvoyInitContainerName is the name of the Envoy init container.
+	EnvoyInitContainerName = "envoy-initconfig"
 	// envoyNsEnvVar is the name of the contour namespace environment variable.
 	envoyNsEnvVar = "CONTOUR_NAMESPACE"
 	// envoyPodEnvVar is the name of the Envoy pod name environment variable.
 	envoyPodEnvVar = "ENVOY_POD_NAME"
+	// envoyNodeNameEnvVar is the name of the Envoy node name environment variable.
+	envoyNodeNameEnvVar = "ENVOY_NODE_NAME"
 	// envoyCertsVolName is the name of the contour certificates volume.
 	envoyCertsVolName = "envoycert"
 	// envoyCertsVolMntDir is the directory name of the Envoy certificates volume.
@@ -54,14 +59,53 @@ const (
 	envoyAdminVolName = "envoy-admin"
 	// envoyAdminVolMntDir is the directory name of the Envoy admin volume.
 	envoyAdminVolMntDir = "admin"
+	// envoyTmpVolName is the name of the Envoy temporary files volume.
+	envoyTmpVolName = "envoy-tmp"
+	// envoyTmpVolMntDir is the directory name of the Envoy temporary files volume.
+	envoyTmpVolMntDir = "tmp"
+	// envoyAPIAccessVolName is the name of the volume that gives the
+	// Envoy init container access to the Kubernetes API.
+	envoyAPIAccessVolName = "envoy-api-access"
+	// envoyAPIAccessVolMntDir is where Kubernetes clients look for
+	// the service account token and CA certificate.
+	envoyAPIAccessVolMntDir = "/var/run/secrets/kubernetes.io/serviceaccount"
 	// envoyCfgFileName is the name of the Envoy configuration file.
 	envoyCfgFileName = "envoy.json"
 	// xdsResourceVersion is the version of the Envoy xdS resource types.
 	xdsResourceVersion = "v3"
+
+	// DefaultTerminationGracePeriod is how long envoy's pods are given
+	// to drain when no drain timeout is configured.
+	DefaultTerminationGracePeriod = 300 * time.Second
 )
 
+// managedEnvoyArgs are the Envoy flags set by the provisioner, or that
+// would conflict with how it runs Envoy, and can't be given as extra args.
+var managedEnvoyArgs = map[string]struct{}{
+	"-c":                    {},
+	"--config-path":         {},
+	"--config-yaml":         {},
+	"--service-cluster":     {},
+	"--service-node":        {},
+	"-l":                    {},
+	"--log-level":           {},
+	"--drain-strategy":      {},
+	"--drain-time-s":        {},
+	"--base-id":             {},
+	"--base-id-path":        {},
+	"--use-dynamic-base-id": {},
+}
+
+// IsManagedEnvoyArg returns whether the Envoy flag is managed by the
+// provisioner.
+func IsManagedEnvoyArg(flag string) bool {
+	_, ok := managedEnvoyArgs[flag]
+	return ok
+}
+
 // EnsureDataPlane ensures an Envoy data plane (daemonset or deployment) exists for the given contour.
-func EnsureDataPlane(ctx context.Context, cli client.Client, contour *model.Contour, contourImage, envoyImage string) error {
+// The changes that roll out the pods of an existing data plane are passed to recordRollout, if set.
+func EnsureDataPlane(ctx context.Context, cli client.Client, contour *model.Contour, contourImage, envoyImage string, recordRollout objects.RolloutRecorder) error {
 
 	switch contour.Spec.EnvoyWorkloadType {
 	// If a Deployment was specified, provision a Deployment.
@@ -74,10 +118,21 @@ func EnsureDataPlane(ctx context.Context, cli client.Client, contour *model.Cont
 				return EnsureDataPlaneDeleted(ctx, cli, contour)
 			}
 
-			return updateDeploymentIfNeeded(ctx, cli, contour, current, desired)
+			return updateDeploymentIfNeeded(ctx, cli, contour, current, desired, recordRollout)
+		}
+
+		if err := objects.EnsureObject(ctx, cli, desired, updater, &appsv1.Deployment{}); err != nil {
+			return err
 		}
 
-		return objects.EnsureObject(ctx, cli, desired, updater, &appsv1.Deployment{})
+		// The DaemonSet of a data plane that was switched to a
+		// Deployment selects the same pods, so it is pruned.
+		return objects.EnsureObjectPruned(ctx, cli, &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: contour.Namespace,
+				Name:      contour.EnvoyDataPlaneName(),
+			},
+		}, contour)
 
 	// The default workload type is a DaemonSet.
 	default:
@@ -89,10 +144,21 @@ func EnsureDataPlane(ctx context.Context, cli client.Client, contour *model.Cont
 				return EnsureDataPlaneDeleted(ctx, cli, contour)
 			}
 
-			return updateDaemonSetIfNeeded(ctx, cli, contour, current, desired)
+			return updateDaemonSetIfNeeded(ctx, cli, contour, current, desired, recordRollout)
+		}
+
+		if err := objects.EnsureObject(ctx, cli, desired, updater, &appsv1.DaemonSet{}); err != nil {
+			return err
 		}
 
-		return objects.EnsureObject(ctx, cli, desired, updater, &appsv1.DaemonSet{})
+		// The Deployment of a data plane that was switched to a
+		// DaemonSet selects the same pods, so it is pruned.
+		return objects.EnsureObjectPruned(ctx, cli, &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: contour.Namespace,
+				Name:      contour.EnvoyDataPlaneName(),
+			},
+		}, contour)
 	}
 }
 
@@ -129,7 +195,7 @@ func desiredContainers(contour *model.Contour, contourImage, envoyImage string)
 	var ports []corev1.ContainerPort
 	for _, port := range contour.Spec.NetworkPublishing.Envoy.Ports {
 		p := corev1.ContainerPort{
-			Name:          port.Name,
+			Name:          port.ServicePortName(),
 			ContainerPort: port.ContainerPort,
 			Protocol:      corev1.ProtocolTCP,
 		}
@@ -144,117 +210,116 @@ func desiredContainers(contour *model.Contour, contourImage, envoyImage string)
 		healthPort = contour.Spec.RuntimeSettings.Envoy.Health.Port
 	}
 
-	containers := []corev1.Container{
-		{
-			Name:            ShutdownContainerName,
-			Image:           contourImage,
-			ImagePullPolicy: corev1.PullIfNotPresent,
-			Command: []string{
-				"/bin/contour",
-			},
-			Args: []string{
-				"envoy",
-				"shutdown-manager",
-			},
-			Lifecycle: &corev1.Lifecycle{
-				PreStop: &corev1.LifecycleHandler{
-					Exec: &corev1.ExecAction{
-						Command: []string{"/bin/contour", "envoy", "shutdown"},
-					},
-				},
-			},
-			TerminationMessagePolicy: corev1.TerminationMessageReadFile,
-			TerminationMessagePath:   "/dev/termination-log",
-			VolumeMounts: []corev1.VolumeMount{
-				{
-					Name:      envoyAdminVolName,
-					MountPath: filepath.Join("/", envoyAdminVolMntDir),
+	shutdownContainer := corev1.Container{
+		Name:            ShutdownContainerName,
+		Image:           contourImage,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Command: []string{
+			"/bin/contour",
+		},
+		Args: []string{
+			"envoy",
+			"shutdown-manager",
+		},
+		Lifecycle: &corev1.Lifecycle{
+			PreStop: &corev1.LifecycleHandler{
+				Exec: &corev1.ExecAction{
+					Command: []string{"/bin/contour", "envoy", "shutdown"},
 				},
 			},
 		},
-		{
-			Name:            EnvoyContainerName,
-			Image:           envoyImage,
-			ImagePullPolicy: corev1.PullIfNotPresent,
-			Command: []string{
-				"envoy",
+		TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+		TerminationMessagePath:   "/dev/termination-log",
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      envoyAdminVolName,
+				MountPath: filepath.Join("/", envoyAdminVolMntDir),
 			},
-			Args: []string{
-				"-c",
-				filepath.Join("/", envoyCfgVolMntDir, envoyCfgFileName),
-				fmt.Sprintf("--service-cluster $(%s)", envoyNsEnvVar),
-				fmt.Sprintf("--service-node $(%s)", envoyPodEnvVar),
-				fmt.Sprintf("--log-level %s", contour.Spec.EnvoyLogLevel),
-			},
-			Env: []corev1.EnvVar{
-				{
-					Name: envoyNsEnvVar,
-					ValueFrom: &corev1.EnvVarSource{
-						FieldRef: &corev1.ObjectFieldSelector{
-							APIVersion: "v1",
-							FieldPath:  "metadata.namespace",
-						},
-					},
-				},
-				{
-					Name: envoyPodEnvVar,
-					ValueFrom: &corev1.EnvVarSource{
-						FieldRef: &corev1.ObjectFieldSelector{
-							APIVersion: "v1",
-							FieldPath:  "metadata.name",
-						},
+		},
+	}
+
+	envoyContainer := corev1.Container{
+		Name:            EnvoyContainerName,
+		Image:           envoyImage,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Command: []string{
+			"envoy",
+		},
+		Args: []string{
+			"-c",
+			filepath.Join("/", envoyCfgVolMntDir, envoyCfgFileName),
+			fmt.Sprintf("--service-cluster $(%s)", envoyNsEnvVar),
+			fmt.Sprintf("--service-node $(%s)", envoyPodEnvVar),
+			fmt.Sprintf("--log-level %s", contour.Spec.EnvoyLogLevel),
+		},
+		Env: []corev1.EnvVar{
+			{
+				Name: envoyNsEnvVar,
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{
+						APIVersion: "v1",
+						FieldPath:  "metadata.namespace",
 					},
 				},
 			},
-			ReadinessProbe: &corev1.Probe{
-				FailureThreshold: int32(3),
-				ProbeHandler: corev1.ProbeHandler{
-					HTTPGet: &corev1.HTTPGetAction{
-						Scheme: corev1.URISchemeHTTP,
-						Path:   "/ready",
-						Port:   intstr.IntOrString{IntVal: int32(healthPort)},
+			{
+				Name: envoyPodEnvVar,
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{
+						APIVersion: "v1",
+						FieldPath:  "metadata.name",
 					},
 				},
-				InitialDelaySeconds: int32(3),
-				PeriodSeconds:       int32(4),
-				SuccessThreshold:    int32(1),
-				TimeoutSeconds:      int32(1),
 			},
-			Ports: ports,
-			VolumeMounts: []corev1.VolumeMount{
-				{
-					Name:      envoyCertsVolName,
-					MountPath: filepath.Join("/", envoyCertsVolMntDir),
-					ReadOnly:  true,
-				},
-				{
-					Name:      envoyCfgVolName,
-					MountPath: filepath.Join("/", envoyCfgVolMntDir),
-					ReadOnly:  true,
-				},
-				{
-					Name:      envoyAdminVolName,
-					MountPath: filepath.Join("/", envoyAdminVolMntDir),
+		},
+		ReadinessProbe: &corev1.Probe{
+			FailureThreshold: int32(3),
+			ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Scheme: corev1.URISchemeHTTP,
+					Path:   "/ready",
+					Port:   intstr.IntOrString{IntVal: int32(healthPort)},
 				},
 			},
-			Lifecycle: &corev1.Lifecycle{
-				PreStop: &corev1.LifecycleHandler{
-					HTTPGet: &corev1.HTTPGetAction{
-						Path:   "/shutdown",
-						Port:   intstr.FromInt(8090),
-						Scheme: "HTTP",
-					},
+			InitialDelaySeconds: int32(3),
+			PeriodSeconds:       int32(4),
+			SuccessThreshold:    int32(1),
+			TimeoutSeconds:      int32(1),
+		},
+		Ports: ports,
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      envoyCertsVolName,
+				MountPath: filepath.Join("/", envoyCertsVolMntDir),
+				ReadOnly:  true,
+			},
+			{
+				Name:      envoyCfgVolName,
+				MountPath: filepath.Join("/", envoyCfgVolMntDir),
+				ReadOnly:  true,
+			},
+			{
+				Name:      envoyAdminVolName,
+				MountPath: filepath.Join("/", envoyAdminVolMntDir),
+			},
+		},
+		Lifecycle: &corev1.Lifecycle{
+			PreStop: &corev1.LifecycleHandler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Path:   "/shutdown",
+					Port:   intstr.FromInt(8090),
+					Scheme: "HTTP",
 				},
 			},
-			TerminationMessagePolicy: corev1.TerminationMessageReadFile,
-			TerminationMessagePath:   "/dev/termination-log",
-			Resources:                contour.Spec.EnvoyResources,
 		},
+		TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+		TerminationMessagePath:   "/dev/termination-log",
+		Resources:                contour.Spec.EnvoyResources,
 	}
 
 	initContainers := []corev1.Container{
 		{
-			Name:            envoyInitContainerName,
+			Name:            EnvoyInitContainerName,
 			Image:           contourImage,
 			ImagePullPolicy: corev1.PullIfNotPresent,
 			Command: []string{
@@ -299,12 +364,230 @@ func desiredContainers(contour *model.Contour, contourImage, envoyImage string)
 		},
 	}
 
+	// The shutdown-manager has to drain Envoy in a way that matches
+	// its drain strategy, see the shutdown command.
+	if drainStrategy := contour.Spec.EnvoyDrainStrategy; drainStrategy != "" {
+		shutdownContainer.Lifecycle.PreStop.Exec.Command = append(shutdownContainer.Lifecycle.PreStop.Exec.Command,
+			fmt.Sprintf("--drain-strategy=%s", drainStrategy))
+		envoyContainer.Args = append(envoyContainer.Args, fmt.Sprintf("--drain-strategy %s", drainStrategy))
+	}
+
+	shutdown := contour.Spec.EnvoyShutdown
+	if shutdown.DrainTimeout > 0 {
+		envoyContainer.Args = append(envoyContainer.Args, fmt.Sprintf("--drain-time-s %d", terminationGracePeriodSeconds(contour)))
+	}
+	shutdownContainer.Lifecycle.PreStop.Exec.Command = append(shutdownContainer.Lifecycle.PreStop.Exec.Command, shutdownFlags(shutdown)...)
+
+	// Zone aware routing needs the local cluster in the bootstrap and
+	// Envoy's zone. Unless the extra args set the zone, the init
+	// container looks it up from the node's zone label, which needs
+	// access to the Kubernetes API that the other containers don't get.
+	if topologyAwareRouting(contour) {
+		initContainers[0].Args = append(initContainers[0].Args, "--topology-aware-routing")
+		if zoneFromNode(contour) {
+			initContainers[0].Env = append(initContainers[0].Env, corev1.EnvVar{
+				Name: envoyNodeNameEnvVar,
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{
+						APIVersion: "v1",
+						FieldPath:  "spec.nodeName",
+					},
+				},
+			})
+			initContainers[0].Args = append(initContainers[0].Args, fmt.Sprintf("--node-name=$(%s)", envoyNodeNameEnvVar))
+			initContainers[0].VolumeMounts = append(initContainers[0].VolumeMounts, corev1.VolumeMount{
+				Name:      envoyAPIAccessVolName,
+				MountPath: envoyAPIAccessVolMntDir,
+				ReadOnly:  true,
+			})
+		}
+	}
+
+	// Envoy's stats settings are only read from its bootstrap.
+	if interval := contour.Spec.EnvoyStatsFlushInterval; interval > 0 {
+		initContainers[0].Args = append(initContainers[0].Args, fmt.Sprintf("--stats-flush-interval=%s", interval))
+	}
+	for _, hb := range contour.Spec.EnvoyHistogramBuckets {
+		initContainers[0].Args = append(initContainers[0].Args,
+			fmt.Sprintf("--stats-histogram-buckets=%s=%s", hb.Regex, strings.Join(hb.Buckets, ",")))
+	}
+
+	envoyContainer.Args = append(envoyContainer.Args, contour.Spec.EnvoyExtraArgs...)
+
+	// Envoy's preStop hook waits on the shutdown-manager, so it
+	// goes away along with the sidecar.
+	var containers []corev1.Container
+	if contour.Spec.EnvoyShutdownManagerDisabled {
+		envoyContainer.Lifecycle = nil
+		containers = []corev1.Container{envoyContainer}
+	} else {
+		containers = []corev1.Container{shutdownContainer, envoyContainer}
+	}
+
 	for j := range containers {
 		containers[j].VolumeMounts = append(containers[j].VolumeMounts, contour.Spec.EnvoyExtraVolumeMounts...)
 	}
+
+	// With a read-only root filesystem, everything the containers write
+	// goes to their volumes. Envoy also gets a writable /tmp, unless an
+	// extra volume is already mounted there.
+	if contour.Spec.EnvoyReadOnlyRootFilesystem {
+		for j := range initContainers {
+			initContainers[j].SecurityContext = &corev1.SecurityContext{ReadOnlyRootFilesystem: ref.To(true)}
+		}
+		for j := range containers {
+			containers[j].SecurityContext = &corev1.SecurityContext{ReadOnlyRootFilesystem: ref.To(true)}
+
+			tmpDir := filepath.Join("/", envoyTmpVolMntDir)
+			if containers[j].Name == EnvoyContainerName && !hasVolumeMountAt(containers[j].VolumeMounts, tmpDir) {
+				containers[j].VolumeMounts = append(containers[j].VolumeMounts, corev1.VolumeMount{
+					Name:      envoyTmpVolName,
+					MountPath: tmpDir,
+				})
+			}
+		}
+	}
+
+	// The extra init containers are run first, they are not changed
+	// to run with a read-only root filesystem.
+	initContainers = objects.InitContainers(contour.Spec.EnvoyExtraInitContainers, initContainers...)
+
 	return initContainers, containers
 }
 
+// hasVolumeMountAt returns whether one of mounts is at path.
+func hasVolumeMountAt(mounts []corev1.VolumeMount, path string) bool {
+	for _, m := range mounts {
+		if filepath.Clean(m.MountPath) == path {
+			return true
+		}
+	}
+	return false
+}
+
+// envoyVolumes returns the volumes of envoy's pods, followed by the
+// extra volumes of the ContourDeployment.
+func envoyVolumes(contour *model.Contour) []corev1.Volume {
+	volumes := []corev1.Volume{
+		{
+			Name: envoyCertsVolName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					DefaultMode: ref.To(int32(420)),
+					SecretName:  contour.EnvoyCertsSecretName(),
+				},
+			},
+		},
+		{
+			Name: envoyCfgVolName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+		{
+			Name: envoyAdminVolName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+	}
+
+	if contour.Spec.EnvoyReadOnlyRootFilesystem {
+		volumes = append(volumes, corev1.Volume{
+			Name: envoyTmpVolName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		})
+	}
+
+	// The service account token is not mounted into the pods, only
+	// this volume is, into the init container that needs it. The
+	// defaults are set to match the volume the API server returns.
+	if zoneFromNode(contour) {
+		volumes = append(volumes, corev1.Volume{
+			Name: envoyAPIAccessVolName,
+			VolumeSource: corev1.VolumeSource{
+				Projected: &corev1.ProjectedVolumeSource{
+					Sources: []corev1.VolumeProjection{
+						{
+							ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+								ExpirationSeconds: ref.To(int64(3607)),
+								Path:              "token",
+							},
+						},
+						{
+							ConfigMap: &corev1.ConfigMapProjection{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "kube-root-ca.crt"},
+								Items:                []corev1.KeyToPath{{Key: "ca.crt", Path: "ca.crt"}},
+							},
+						},
+					},
+					DefaultMode: ref.To(int32(420)),
+				},
+			},
+		})
+	}
+
+	return append(volumes, contour.Spec.EnvoyExtraVolumes...)
+}
+
+// topologyAwareRouting returns whether the runtime settings of contour
+// enable topology aware routing.
+func topologyAwareRouting(contour *model.Contour) bool {
+	settings := contour.Spec.RuntimeSettings
+	return settings != nil &&
+		settings.Envoy != nil &&
+		settings.Envoy.Cluster != nil &&
+		settings.Envoy.Cluster.TopologyAwareRouting != nil
+}
+
+// zoneFromNode returns whether Envoy's zone for topology aware routing
+// is looked up from the node it runs on, which is the case unless the
+// extra args of contour set it.
+func zoneFromNode(contour *model.Contour) bool {
+	return topologyAwareRouting(contour) && !hasEnvoyArg(contour.Spec.EnvoyExtraArgs, "--service-zone")
+}
+
+// hasEnvoyArg returns whether args set the Envoy flag, either alone or
+// followed by its value.
+func hasEnvoyArg(args []string, flag string) bool {
+	for _, arg := range args {
+		if arg == flag || strings.HasPrefix(arg, flag+" ") || strings.HasPrefix(arg, flag+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+// terminationGracePeriodSeconds returns the termination grace period of
+// envoy's pods, which is the longest time they are given to drain.
+func terminationGracePeriodSeconds(contour *model.Contour) int64 {
+	if timeout := contour.Spec.EnvoyShutdown.DrainTimeout; timeout > 0 {
+		return int64(math.Ceil(timeout.Seconds()))
+	}
+	return int64(DefaultTerminationGracePeriod.Seconds())
+}
+
+// shutdownFlags returns the flags of the shutdown command for the
+// settings in shutdown that are not left at their defaults.
+func shutdownFlags(shutdown model.EnvoyShutdownSettings) []string {
+	var flags []string
+	if shutdown.DrainDelay > 0 {
+		flags = append(flags, fmt.Sprintf("--drain-delay=%s", shutdown.DrainDelay))
+	}
+	if shutdown.CheckDelay > 0 {
+		flags = append(flags, fmt.Sprintf("--check-delay=%s", shutdown.CheckDelay))
+	}
+	if shutdown.CheckInterval > 0 {
+		flags = append(flags, fmt.Sprintf("--check-interval=%s", shutdown.CheckInterval))
+	}
+	if shutdown.MinOpenConnections != nil {
+		flags = append(flags, fmt.Sprintf("--min-open-connections=%d", *shutdown.MinOpenConnections))
+	}
+	return flags
+}
+
 // DesiredDaemonSet returns the desired DaemonSet for the provided contour using
 // contourImage as the shutdown-manager/envoy-initconfig container images and
 // envoyImage as Envoy's container image.
@@ -313,15 +596,17 @@ func DesiredDaemonSet(contour *model.Contour, contourImage, envoyImage string) *
 
 	ds := &appsv1.DaemonSet{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: contour.Namespace,
-			Name:      contour.EnvoyDataPlaneName(),
-			Labels:    contour.AppLabels(),
+			Namespace:       contour.Namespace,
+			Name:            contour.EnvoyDataPlaneName(),
+			Labels:          contour.AppLabels(),
+			OwnerReferences: contour.OwnerReferences,
 		},
 		Spec: appsv1.DaemonSetSpec{
 			RevisionHistoryLimit: ref.To(int32(10)),
 			// Ensure the deamonset adopts only its own pods.
-			Selector:       EnvoyPodSelector(contour),
-			UpdateStrategy: contour.Spec.EnvoyDaemonSetUpdateStrategy,
+			Selector:        EnvoyPodSelector(contour),
+			UpdateStrategy:  contour.Spec.EnvoyDaemonSetUpdateStrategy,
+			MinReadySeconds: contour.Spec.EnvoyMinReadySeconds,
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					// TODO [danehans]: Remove the prometheus annotations when Contour is updated to
@@ -330,35 +615,13 @@ func DesiredDaemonSet(contour *model.Contour, contourImage, envoyImage string) *
 					Labels:      envoyPodLabels(contour),
 				},
 				Spec: corev1.PodSpec{
-					Containers:     containers,
-					InitContainers: initContainers,
-					Volumes: []corev1.Volume{
-						{
-							Name: envoyCertsVolName,
-							VolumeSource: corev1.VolumeSource{
-								Secret: &corev1.SecretVolumeSource{
-									DefaultMode: ref.To(int32(420)),
-									SecretName:  contour.EnvoyCertsSecretName(),
-								},
-							},
-						},
-						{
-							Name: envoyCfgVolName,
-							VolumeSource: corev1.VolumeSource{
-								EmptyDir: &corev1.EmptyDirVolumeSource{},
-							},
-						},
-						{
-							Name: envoyAdminVolName,
-							VolumeSource: corev1.VolumeSource{
-								EmptyDir: &corev1.EmptyDirVolumeSource{},
-							},
-						},
-					},
+					Containers:                    containers,
+					InitContainers:                initContainers,
+					Volumes:                       envoyVolumes(contour),
 					ServiceAccountName:            contour.EnvoyRBACNames().ServiceAccount,
 					AutomountServiceAccountToken:  ref.To(false),
-					TerminationGracePeriodSeconds: ref.To(int64(300)),
-					SecurityContext:               objects.NewUnprivilegedPodSecurity(),
+					TerminationGracePeriodSeconds: ref.To(terminationGracePeriodSeconds(contour)),
+					SecurityContext:               envoyPodSecurityContext(contour),
 					DNSPolicy:                     corev1.DNSClusterFirst,
 					RestartPolicy:                 corev1.RestartPolicyAlways,
 					SchedulerName:                 "default-scheduler",
@@ -367,8 +630,6 @@ func DesiredDaemonSet(contour *model.Contour, contourImage, envoyImage string) *
 		},
 	}
 
-	ds.Spec.Template.Spec.Volumes = append(ds.Spec.Template.Spec.Volumes, contour.Spec.EnvoyExtraVolumes...)
-
 	if contour.EnvoyNodeSelectorExists() {
 		ds.Spec.Template.Spec.NodeSelector = contour.Spec.NodePlacement.Envoy.NodeSelector
 	}
@@ -377,6 +638,11 @@ func DesiredDaemonSet(contour *model.Contour, contourImage, envoyImage string) *
 		ds.Spec.Template.Spec.Tolerations = contour.Spec.NodePlacement.Envoy.Tolerations
 	}
 
+	if contour.EnvoyTopologySpreadConstraintsExist() {
+		ds.Spec.Template.Spec.TopologySpreadConstraints = objects.TopologySpreadConstraints(
+			contour.Spec.NodePlacement.Envoy.TopologySpreadConstraints, EnvoyPodSelector(contour))
+	}
+
 	return ds
 }
 
@@ -385,16 +651,18 @@ func desiredDeployment(contour *model.Contour, contourImage, envoyImage string)
 
 	deployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: contour.Namespace,
-			Name:      contour.EnvoyDataPlaneName(),
-			Labels:    contour.AppLabels(),
+			Namespace:       contour.Namespace,
+			Name:            contour.EnvoyDataPlaneName(),
+			Labels:          contour.AppLabels(),
+			OwnerReferences: contour.OwnerReferences,
 		},
 		Spec: appsv1.DeploymentSpec{
 			Replicas:             ref.To(contour.Spec.EnvoyReplicas),
 			RevisionHistoryLimit: ref.To(int32(10)),
 			// Ensure the deamonset adopts only its own pods.
-			Selector: EnvoyPodSelector(contour),
-			Strategy: contour.Spec.EnvoyDeploymentStrategy,
+			Selector:        EnvoyPodSelector(contour),
+			Strategy:        contour.Spec.EnvoyDeploymentStrategy,
+			MinReadySeconds: contour.Spec.EnvoyMinReadySeconds,
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					// TODO [danehans]: Remove the prometheus annotations when Contour is updated to
@@ -404,36 +672,14 @@ func desiredDeployment(contour *model.Contour, contourImage, envoyImage string)
 				},
 				Spec: corev1.PodSpec{
 					// TODO anti-affinity
-					Affinity:       nil,
-					Containers:     containers,
-					InitContainers: initContainers,
-					Volumes: []corev1.Volume{
-						{
-							Name: envoyCertsVolName,
-							VolumeSource: corev1.VolumeSource{
-								Secret: &corev1.SecretVolumeSource{
-									DefaultMode: ref.To(int32(420)),
-									SecretName:  contour.EnvoyCertsSecretName(),
-								},
-							},
-						},
-						{
-							Name: envoyCfgVolName,
-							VolumeSource: corev1.VolumeSource{
-								EmptyDir: &corev1.EmptyDirVolumeSource{},
-							},
-						},
-						{
-							Name: envoyAdminVolName,
-							VolumeSource: corev1.VolumeSource{
-								EmptyDir: &corev1.EmptyDirVolumeSource{},
-							},
-						},
-					},
+					Affinity:                      nil,
+					Containers:                    containers,
+					InitContainers:                initContainers,
+					Volumes:                       envoyVolumes(contour),
 					ServiceAccountName:            contour.EnvoyRBACNames().ServiceAccount,
 					AutomountServiceAccountToken:  ref.To(false),
-					TerminationGracePeriodSeconds: ref.To(int64(300)),
-					SecurityContext:               objects.NewUnprivilegedPodSecurity(),
+					TerminationGracePeriodSeconds: ref.To(terminationGracePeriodSeconds(contour)),
+					SecurityContext:               envoyPodSecurityContext(contour),
 					DNSPolicy:                     corev1.DNSClusterFirst,
 					RestartPolicy:                 corev1.RestartPolicyAlways,
 					SchedulerName:                 "default-scheduler",
@@ -442,8 +688,6 @@ func desiredDeployment(contour *model.Contour, contourImage, envoyImage string)
 		},
 	}
 
-	deployment.Spec.Template.Spec.Volumes = append(deployment.Spec.Template.Spec.Volumes, contour.Spec.EnvoyExtraVolumes...)
-
 	if contour.EnvoyNodeSelectorExists() {
 		deployment.Spec.Template.Spec.NodeSelector = contour.Spec.NodePlacement.Envoy.NodeSelector
 	}
@@ -452,18 +696,26 @@ func desiredDeployment(contour *model.Contour, contourImage, envoyImage string)
 		deployment.Spec.Template.Spec.Tolerations = contour.Spec.NodePlacement.Envoy.Tolerations
 	}
 
+	if contour.EnvoyTopologySpreadConstraintsExist() {
+		deployment.Spec.Template.Spec.TopologySpreadConstraints = objects.TopologySpreadConstraints(
+			contour.Spec.NodePlacement.Envoy.TopologySpreadConstraints, EnvoyPodSelector(contour))
+	}
+
 	return deployment
 }
 
 // updateDaemonSetIfNeeded updates a DaemonSet if current does not match desired,
 // using contour to verify the existence of owner labels.
-func updateDaemonSetIfNeeded(ctx context.Context, cli client.Client, contour *model.Contour, current, desired *appsv1.DaemonSet) error {
+func updateDaemonSetIfNeeded(ctx context.Context, cli client.Client, contour *model.Contour, current, desired *appsv1.DaemonSet, recordRollout objects.RolloutRecorder) error {
 	if labels.Exist(current, model.OwnerLabels(contour)) {
 		ds, updated := equality.DaemonsetConfigChanged(current, desired)
 		if updated {
 			if err := cli.Update(ctx, ds); err != nil {
 				return fmt.Errorf("failed to update daemonset %s/%s: %w", ds.Namespace, ds.Name, err)
 			}
+			if changes := equality.PodTemplateChanges(&current.Spec.Template, &ds.Spec.Template); len(changes) > 0 && recordRollout != nil {
+				recordRollout(ds, changes)
+			}
 			return nil
 		}
 	}
@@ -472,13 +724,16 @@ func updateDaemonSetIfNeeded(ctx context.Context, cli client.Client, contour *mo
 
 // updateDeploymentIfNeeded updates a Deployment if current does not match desired,
 // using contour to verify the existence of owner labels.
-func updateDeploymentIfNeeded(ctx context.Context, cli client.Client, contour *model.Contour, current, desired *appsv1.Deployment) error {
+func updateDeploymentIfNeeded(ctx context.Context, cli client.Client, contour *model.Contour, current, desired *appsv1.Deployment, recordRollout objects.RolloutRecorder) error {
 	if labels.Exist(current, model.OwnerLabels(contour)) {
 		ds, updated := equality.DeploymentConfigChanged(current, desired)
 		if updated {
 			if err := cli.Update(ctx, ds); err != nil {
 				return fmt.Errorf("failed to update deployment %s/%s: %w", ds.Namespace, ds.Name, err)
 			}
+			if changes := equality.PodTemplateChanges(&current.Spec.Template, &ds.Spec.Template); len(changes) > 0 && recordRollout != nil {
+				recordRollout(ds, changes)
+			}
 			return nil
 		}
 	}
@@ -490,14 +745,21 @@ func updateDeploymentIfNeeded(ctx context.Context, cli client.Client, contour *m
 func EnvoyPodSelector(contour *model.Contour) *metav1.LabelSelector {
 	return &metav1.LabelSelector{
 		MatchLabels: map[string]string{
-			"app": contour.EnvoyDataPlaneName(),
+			model.PodSelectorLabel: contour.EnvoyDataPlaneName(),
 		},
 	}
 }
 
-// envoyPodLabels returns the labels for envoy's pods
+// envoyPodLabels returns the labels for envoy's pods, the user's pod labels
+// are overridden by the pod selector & app labels.
 func envoyPodLabels(contour *model.Contour) map[string]string {
-	labels := EnvoyPodSelector(contour).MatchLabels
+	labels := map[string]string{}
+	for k, v := range contour.Spec.EnvoyPodLabels {
+		labels[k] = v
+	}
+	for k, v := range EnvoyPodSelector(contour).MatchLabels {
+		labels[k] = v
+	}
 	for k, v := range contour.AppLabels() {
 		labels[k] = v
 	}
@@ -525,3 +787,38 @@ func envoyPodAnnotations(contour *model.Contour) map[string]string {
 
 	return annotations
 }
+
+// envoyPodSecurityContext returns the security context for envoy's pods.
+// The unprivileged defaults, plus an fsGroup matching envoy's group so the
+// mounted certificate secrets are readable, are overlaid with any fields
+// set in the ContourDeployment.
+func envoyPodSecurityContext(contour *model.Contour) *corev1.PodSecurityContext {
+	sc := objects.NewUnprivilegedPodSecurity()
+	sc.FSGroup = ref.To(int64(65534))
+
+	custom := contour.Spec.EnvoyPodSecurityContext
+	if custom == nil {
+		return sc
+	}
+
+	if custom.RunAsUser != nil {
+		sc.RunAsUser = custom.RunAsUser
+	}
+	if custom.RunAsGroup != nil {
+		sc.RunAsGroup = custom.RunAsGroup
+	}
+	if custom.RunAsNonRoot != nil {
+		sc.RunAsNonRoot = custom.RunAsNonRoot
+	}
+	if custom.FSGroup != nil {
+		sc.FSGroup = custom.FSGroup
+	}
+	sc.FSGroupChangePolicy = custom.FSGroupChangePolicy
+	sc.SupplementalGroups = custom.SupplementalGroups
+	sc.SELinuxOptions = custom.SELinuxOptions
+	sc.WindowsOptions = custom.WindowsOptions
+	sc.Sysctls = custom.Sysctls
+	sc.SeccompProfile = custom.SeccompProfile
+
+	return sc
+}
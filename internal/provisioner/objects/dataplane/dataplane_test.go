@@ -15,22 +15,27 @@ package dataplane
 
 import (
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
 	"github.com/projectcontour/contour/internal/provisioner/model"
+	"github.com/projectcontour/contour/internal/ref"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func checkDaemonSetHasEnvVar(t *testing.T, ds *appsv1.DaemonSet, container, name string) {
 	t.Helper()
 
-	if container == envoyInitContainerName {
+	if container == EnvoyInitContainerName {
 		for i, c := range ds.Spec.Template.Spec.InitContainers {
 			if c.Name == container {
 				for _, envVar := range ds.Spec.Template.Spec.InitContainers[i].Env {
@@ -62,7 +67,7 @@ func checkDaemonSetHasContainer(t *testing.T, ds *appsv1.DaemonSet, name string,
 		t.Error("daemonset has no containers")
 	}
 
-	if name == envoyInitContainerName {
+	if name == EnvoyInitContainerName {
 		for _, container := range ds.Spec.Template.Spec.InitContainers {
 			if container.Name == name {
 				if expect {
@@ -213,6 +218,7 @@ func checkDaemonSecurityContext(t *testing.T, ds *appsv1.DaemonSet) {
 		RunAsUser:    &user,
 		RunAsGroup:   &group,
 		RunAsNonRoot: &nonRoot,
+		FSGroup:      &group,
 	}
 	if apiequality.Semantic.DeepEqual(ds.Spec.Template.Spec.SecurityContext, expected) {
 		return
@@ -304,11 +310,11 @@ func TestDesiredDaemonSet(t *testing.T) {
 
 	container = checkDaemonSetHasContainer(t, ds, ShutdownContainerName, true)
 	checkContainerHasImage(t, container, testContourImage)
-	container = checkDaemonSetHasContainer(t, ds, envoyInitContainerName, true)
+	container = checkDaemonSetHasContainer(t, ds, EnvoyInitContainerName, true)
 	checkContainerHasImage(t, container, testContourImage)
 	checkDaemonSetHasEnvVar(t, ds, EnvoyContainerName, envoyNsEnvVar)
 	checkDaemonSetHasEnvVar(t, ds, EnvoyContainerName, envoyPodEnvVar)
-	checkDaemonSetHasEnvVar(t, ds, envoyInitContainerName, envoyNsEnvVar)
+	checkDaemonSetHasEnvVar(t, ds, EnvoyInitContainerName, envoyNsEnvVar)
 	checkDaemonSetHasLabels(t, ds, cntr.AppLabels())
 	for _, port := range cntr.Spec.NetworkPublishing.Envoy.Ports {
 		checkContainerHasPort(t, ds, port.ContainerPort)
@@ -363,6 +369,404 @@ func TestNodePlacementDaemonSet(t *testing.T) {
 	checkDaemonSetHasTolerations(t, ds, tolerations)
 }
 
+func TestEnvoyTopologySpreadConstraints(t *testing.T) {
+	name := "topology-spread-test"
+	cntr := model.Default(fmt.Sprintf("%s-ns", name), name)
+
+	constraints := []corev1.TopologySpreadConstraint{
+		{
+			MaxSkew:           1,
+			TopologyKey:       "kubernetes.io/hostname",
+			WhenUnsatisfiable: corev1.DoNotSchedule,
+		},
+		{
+			MaxSkew:           2,
+			TopologyKey:       "topology.kubernetes.io/zone",
+			WhenUnsatisfiable: corev1.ScheduleAnyway,
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "envoy"},
+			},
+		},
+	}
+
+	cntr.Spec.NodePlacement = &model.NodePlacement{
+		Envoy: &model.EnvoyNodePlacement{
+			TopologySpreadConstraints: constraints,
+		},
+	}
+
+	// The constraint without a label selector spreads the
+	// workload's own pods, the other is left as it is.
+	want := []corev1.TopologySpreadConstraint{
+		{
+			MaxSkew:           1,
+			TopologyKey:       "kubernetes.io/hostname",
+			WhenUnsatisfiable: corev1.DoNotSchedule,
+			LabelSelector:     EnvoyPodSelector(cntr),
+		},
+		constraints[1],
+	}
+
+	testContourImage := "ghcr.io/projectcontour/contour:test"
+	testEnvoyImage := "docker.io/envoyproxy/envoy:test"
+
+	ds := DesiredDaemonSet(cntr, testContourImage, testEnvoyImage)
+	assert.Equal(t, want, ds.Spec.Template.Spec.TopologySpreadConstraints)
+
+	cntr.Spec.EnvoyWorkloadType = model.WorkloadTypeDeployment
+	deploy := desiredDeployment(cntr, testContourImage, testEnvoyImage)
+	assert.Equal(t, want, deploy.Spec.Template.Spec.TopologySpreadConstraints)
+
+	// The model is not modified.
+	assert.Nil(t, cntr.Spec.NodePlacement.Envoy.TopologySpreadConstraints[0].LabelSelector)
+}
+
+func TestEnvoyPodLabels(t *testing.T) {
+	name := "pod-labels-test"
+	cntr := model.Default(fmt.Sprintf("%s-ns", name), name)
+	cntr.Spec.EnvoyPodLabels = map[string]string{
+		"logging.example.com/collect": "true",
+		"app.kubernetes.io/name":      "not-contour",
+	}
+
+	testContourImage := "ghcr.io/projectcontour/contour:test"
+	testEnvoyImage := "docker.io/envoyproxy/envoy:test"
+
+	// The pod labels are on the pods, but Contour's own labels
+	// win and the selector is left alone.
+	ds := DesiredDaemonSet(cntr, testContourImage, testEnvoyImage)
+	assert.Equal(t, "true", ds.Spec.Template.Labels["logging.example.com/collect"])
+	assert.Equal(t, "contour", ds.Spec.Template.Labels["app.kubernetes.io/name"])
+	assert.Equal(t, EnvoyPodSelector(cntr), ds.Spec.Selector)
+
+	cntr.Spec.EnvoyWorkloadType = model.WorkloadTypeDeployment
+	deploy := desiredDeployment(cntr, testContourImage, testEnvoyImage)
+	assert.Equal(t, "true", deploy.Spec.Template.Labels["logging.example.com/collect"])
+	assert.Equal(t, EnvoyPodSelector(cntr), deploy.Spec.Selector)
+}
+
+func TestEnvoyDrainStrategy(t *testing.T) {
+	name := "envoy-drain-strategy"
+	cntr := model.Default(fmt.Sprintf("%s-ns", name), name)
+
+	testContourImage := "ghcr.io/projectcontour/contour:test"
+	testEnvoyImage := "docker.io/envoyproxy/envoy:test"
+
+	// Unset, neither Envoy nor the shutdown command are told.
+	ds := DesiredDaemonSet(cntr, testContourImage, testEnvoyImage)
+	container := checkDaemonSetHasContainer(t, ds, ShutdownContainerName, true)
+	assert.Equal(t, []string{"/bin/contour", "envoy", "shutdown"}, container.Lifecycle.PreStop.Exec.Command)
+	container = checkDaemonSetHasContainer(t, ds, EnvoyContainerName, true)
+	assert.NotContains(t, container.Args, "--drain-strategy gradual")
+
+	cntr.Spec.EnvoyDrainStrategy = v1alpha1.DrainStrategyGradual
+	ds = DesiredDaemonSet(cntr, testContourImage, testEnvoyImage)
+	container = checkDaemonSetHasContainer(t, ds, ShutdownContainerName, true)
+	assert.Equal(t, []string{"/bin/contour", "envoy", "shutdown", "--drain-strategy=gradual"}, container.Lifecycle.PreStop.Exec.Command)
+	container = checkDaemonSetHasContainer(t, ds, EnvoyContainerName, true)
+	checkContainerHasArg(t, container, "--drain-strategy gradual")
+}
+
+func TestEnvoyShutdownSettings(t *testing.T) {
+	name := "envoy-shutdown"
+	cntr := model.Default(fmt.Sprintf("%s-ns", name), name)
+
+	testContourImage := "ghcr.io/projectcontour/contour:test"
+	testEnvoyImage := "docker.io/envoyproxy/envoy:test"
+
+	// Unset, the pods keep the default grace period.
+	ds := DesiredDaemonSet(cntr, testContourImage, testEnvoyImage)
+	assert.Equal(t, ref.To(int64(300)), ds.Spec.Template.Spec.TerminationGracePeriodSeconds)
+	container := checkDaemonSetHasContainer(t, ds, ShutdownContainerName, true)
+	assert.Equal(t, []string{"/bin/contour", "envoy", "shutdown"}, container.Lifecycle.PreStop.Exec.Command)
+
+	cntr.Spec.EnvoyShutdown = model.EnvoyShutdownSettings{
+		DrainTimeout:       90500 * time.Millisecond,
+		DrainDelay:         5 * time.Second,
+		CheckDelay:         10 * time.Second,
+		CheckInterval:      time.Second,
+		MinOpenConnections: ref.To(int32(2)),
+	}
+
+	ds = DesiredDaemonSet(cntr, testContourImage, testEnvoyImage)
+	assert.Equal(t, ref.To(int64(91)), ds.Spec.Template.Spec.TerminationGracePeriodSeconds)
+	container = checkDaemonSetHasContainer(t, ds, ShutdownContainerName, true)
+	assert.Equal(t, []string{
+		"/bin/contour", "envoy", "shutdown",
+		"--drain-delay=5s",
+		"--check-delay=10s",
+		"--check-interval=1s",
+		"--min-open-connections=2",
+	}, container.Lifecycle.PreStop.Exec.Command)
+	container = checkDaemonSetHasContainer(t, ds, EnvoyContainerName, true)
+	checkContainerHasArg(t, container, "--drain-time-s 91")
+
+	cntr.Spec.EnvoyWorkloadType = model.WorkloadTypeDeployment
+	deploy := desiredDeployment(cntr, testContourImage, testEnvoyImage)
+	assert.Equal(t, ref.To(int64(91)), deploy.Spec.Template.Spec.TerminationGracePeriodSeconds)
+}
+
+func TestEnvoyShutdownManagerDisabled(t *testing.T) {
+	name := "envoy-no-shutdown-manager"
+	cntr := model.Default(fmt.Sprintf("%s-ns", name), name)
+	cntr.Spec.EnvoyShutdownManagerDisabled = true
+	cntr.Spec.EnvoyDrainStrategy = v1alpha1.DrainStrategyGradual
+
+	testContourImage := "ghcr.io/projectcontour/contour:test"
+	testEnvoyImage := "docker.io/envoyproxy/envoy:test"
+
+	ds := DesiredDaemonSet(cntr, testContourImage, testEnvoyImage)
+	checkDaemonSetHasContainer(t, ds, ShutdownContainerName, false)
+	assert.Len(t, ds.Spec.Template.Spec.Containers, 1)
+	container := checkDaemonSetHasContainer(t, ds, EnvoyContainerName, true)
+	assert.Nil(t, container.Lifecycle)
+	checkContainerHasArg(t, container, "--drain-strategy gradual")
+
+	cntr.Spec.EnvoyWorkloadType = model.WorkloadTypeDeployment
+	deploy := desiredDeployment(cntr, testContourImage, testEnvoyImage)
+	require.Len(t, deploy.Spec.Template.Spec.Containers, 1)
+	assert.Equal(t, EnvoyContainerName, deploy.Spec.Template.Spec.Containers[0].Name)
+	assert.Nil(t, deploy.Spec.Template.Spec.Containers[0].Lifecycle)
+}
+
+func TestEnvoyPodSecurityContext(t *testing.T) {
+	name := "envoy-security-context"
+	cntr := model.Default(fmt.Sprintf("%s-ns", name), name)
+	cntr.Spec.EnvoyPodSecurityContext = &corev1.PodSecurityContext{
+		FSGroup:             ref.To(int64(2000)),
+		FSGroupChangePolicy: ref.To(corev1.FSGroupChangeOnRootMismatch),
+		SupplementalGroups:  []int64{3000},
+	}
+
+	testContourImage := "ghcr.io/projectcontour/contour:test"
+	testEnvoyImage := "docker.io/envoyproxy/envoy:test"
+
+	// Fields that aren't set keep the unprivileged defaults.
+	want := &corev1.PodSecurityContext{
+		RunAsUser:           ref.To(int64(65534)),
+		RunAsGroup:          ref.To(int64(65534)),
+		RunAsNonRoot:        ref.To(true),
+		FSGroup:             ref.To(int64(2000)),
+		FSGroupChangePolicy: ref.To(corev1.FSGroupChangeOnRootMismatch),
+		SupplementalGroups:  []int64{3000},
+	}
+
+	ds := DesiredDaemonSet(cntr, testContourImage, testEnvoyImage)
+	assert.Equal(t, want, ds.Spec.Template.Spec.SecurityContext)
+
+	deploy := desiredDeployment(cntr, testContourImage, testEnvoyImage)
+	assert.Equal(t, want, deploy.Spec.Template.Spec.SecurityContext)
+}
+
+func TestEnvoyReadOnlyRootFilesystem(t *testing.T) {
+	name := "envoy-read-only-root-filesystem"
+	cntr := model.Default(fmt.Sprintf("%s-ns", name), name)
+	cntr.Spec.EnvoyReadOnlyRootFilesystem = true
+
+	testContourImage := "ghcr.io/projectcontour/contour:test"
+	testEnvoyImage := "docker.io/envoyproxy/envoy:test"
+
+	tmpVol := corev1.Volume{
+		Name: envoyTmpVolName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	}
+	tmpMount := corev1.VolumeMount{
+		Name:      envoyTmpVolName,
+		MountPath: "/tmp",
+	}
+
+	// Every container has a read-only root filesystem, and Envoy
+	// writes its temporary files to an emptyDir.
+	ds := DesiredDaemonSet(cntr, testContourImage, testEnvoyImage)
+	podSpec := ds.Spec.Template.Spec
+	for _, c := range append(podSpec.InitContainers, podSpec.Containers...) {
+		require.NotNil(t, c.SecurityContext, c.Name)
+		assert.Equal(t, ref.To(true), c.SecurityContext.ReadOnlyRootFilesystem, c.Name)
+	}
+	assert.Contains(t, podSpec.Volumes, tmpVol)
+	envoyContainer := checkDaemonSetHasContainer(t, ds, EnvoyContainerName, true)
+	assert.Contains(t, envoyContainer.VolumeMounts, tmpMount)
+	shutdownContainer := checkDaemonSetHasContainer(t, ds, ShutdownContainerName, true)
+	assert.NotContains(t, shutdownContainer.VolumeMounts, tmpMount)
+
+	deploy := desiredDeployment(cntr, testContourImage, testEnvoyImage)
+	assert.Contains(t, deploy.Spec.Template.Spec.Volumes, tmpVol)
+
+	// An extra volume mounted at /tmp is used instead.
+	extraMount := corev1.VolumeMount{Name: "extra-tmp", MountPath: "/tmp/"}
+	cntr.Spec.EnvoyExtraVolumes = []corev1.Volume{{Name: "extra-tmp"}}
+	cntr.Spec.EnvoyExtraVolumeMounts = []corev1.VolumeMount{extraMount}
+	ds = DesiredDaemonSet(cntr, testContourImage, testEnvoyImage)
+	envoyContainer = checkDaemonSetHasContainer(t, ds, EnvoyContainerName, true)
+	assert.Contains(t, envoyContainer.VolumeMounts, extraMount)
+	assert.NotContains(t, envoyContainer.VolumeMounts, tmpMount)
+
+	// By default, the containers' root filesystems are writable.
+	cntr = model.Default(fmt.Sprintf("%s-ns", name), name)
+	ds = DesiredDaemonSet(cntr, testContourImage, testEnvoyImage)
+	podSpec = ds.Spec.Template.Spec
+	for _, c := range append(podSpec.InitContainers, podSpec.Containers...) {
+		assert.Nil(t, c.SecurityContext, c.Name)
+	}
+	assert.NotContains(t, podSpec.Volumes, tmpVol)
+}
+
+func TestEnvoyExtraInitContainers(t *testing.T) {
+	name := "envoy-extra-init-containers"
+	cntr := model.Default(fmt.Sprintf("%s-ns", name), name)
+	cntr.Spec.EnvoyReadOnlyRootFilesystem = true
+	cntr.Spec.EnvoyExtraInitContainers = []corev1.Container{{
+		Name:    "fetch-secrets",
+		Image:   "docker.io/library/busybox:1.36",
+		Command: []string{"sh", "-c", "echo secret > /secrets/token"},
+	}}
+
+	testContourImage := "ghcr.io/projectcontour/contour:test"
+	testEnvoyImage := "docker.io/envoyproxy/envoy:test"
+
+	// The extra init containers run before the managed one, and are
+	// not changed to run with a read-only root filesystem.
+	for _, podSpec := range []corev1.PodSpec{
+		DesiredDaemonSet(cntr, testContourImage, testEnvoyImage).Spec.Template.Spec,
+		desiredDeployment(cntr, testContourImage, testEnvoyImage).Spec.Template.Spec,
+	} {
+		require.Len(t, podSpec.InitContainers, 2)
+		assert.Equal(t, "fetch-secrets", podSpec.InitContainers[0].Name)
+		assert.Equal(t, corev1.PullIfNotPresent, podSpec.InitContainers[0].ImagePullPolicy)
+		assert.Nil(t, podSpec.InitContainers[0].SecurityContext)
+		assert.Equal(t, EnvoyInitContainerName, podSpec.InitContainers[1].Name)
+	}
+}
+
+func TestEnvoyMinReadySeconds(t *testing.T) {
+	name := "envoy-min-ready-seconds"
+	cntr := model.Default(fmt.Sprintf("%s-ns", name), name)
+	cntr.Spec.EnvoyMinReadySeconds = 10
+
+	testContourImage := "ghcr.io/projectcontour/contour:test"
+	testEnvoyImage := "docker.io/envoyproxy/envoy:test"
+
+	ds := DesiredDaemonSet(cntr, testContourImage, testEnvoyImage)
+	assert.Equal(t, int32(10), ds.Spec.MinReadySeconds)
+
+	deploy := desiredDeployment(cntr, testContourImage, testEnvoyImage)
+	assert.Equal(t, int32(10), deploy.Spec.MinReadySeconds)
+}
+
+func TestEnvoyExtraArgs(t *testing.T) {
+	name := "envoy-extra-args"
+	cntr := model.Default(fmt.Sprintf("%s-ns", name), name)
+	cntr.Spec.EnvoyExtraArgs = []string{"--log-format %v", "--component-log-level upstream:debug"}
+
+	testContourImage := "ghcr.io/projectcontour/contour:test"
+	testEnvoyImage := "docker.io/envoyproxy/envoy:test"
+
+	// The extra args come after the ones set by the provisioner.
+	ds := DesiredDaemonSet(cntr, testContourImage, testEnvoyImage)
+	container := checkDaemonSetHasContainer(t, ds, EnvoyContainerName, true)
+	assert.Equal(t, []string{"--log-format %v", "--component-log-level upstream:debug"}, container.Args[len(container.Args)-2:])
+	checkContainerHasArg(t, container, "--log-level info")
+}
+
+func TestIsManagedEnvoyArg(t *testing.T) {
+	assert.True(t, IsManagedEnvoyArg("--config-path"))
+	assert.True(t, IsManagedEnvoyArg("--base-id"))
+	assert.True(t, IsManagedEnvoyArg("-l"))
+	assert.False(t, IsManagedEnvoyArg("--log-format"))
+}
+
+func TestEnvoyTopologyAwareRouting(t *testing.T) {
+	name := "envoy-topology-aware-routing"
+	cntr := model.Default(fmt.Sprintf("%s-ns", name), name)
+	cntr.Spec.RuntimeSettings = &v1alpha1.ContourConfigurationSpec{
+		Envoy: &v1alpha1.EnvoyConfig{
+			Cluster: &v1alpha1.ClusterParameters{
+				TopologyAwareRouting: &v1alpha1.TopologyAwareRoutingConfig{},
+			},
+		},
+	}
+
+	testContourImage := "ghcr.io/projectcontour/contour:test"
+	testEnvoyImage := "docker.io/envoyproxy/envoy:test"
+
+	hasVolume := func(ds *appsv1.DaemonSet, name string) bool {
+		for _, v := range ds.Spec.Template.Spec.Volumes {
+			if v.Name == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	// The bootstrap adds the local cluster and looks up Envoy's zone
+	// from its node, which only the init container has access to.
+	ds := DesiredDaemonSet(cntr, testContourImage, testEnvoyImage)
+	initContainer := checkDaemonSetHasContainer(t, ds, EnvoyInitContainerName, true)
+	checkContainerHasArg(t, initContainer, "--topology-aware-routing")
+	checkContainerHasArg(t, initContainer, "--node-name=$(ENVOY_NODE_NAME)")
+	checkDaemonSetHasEnvVar(t, ds, EnvoyInitContainerName, envoyNodeNameEnvVar)
+	assert.Contains(t, initContainer.VolumeMounts, corev1.VolumeMount{
+		Name:      envoyAPIAccessVolName,
+		MountPath: "/var/run/secrets/kubernetes.io/serviceaccount",
+		ReadOnly:  true,
+	})
+	assert.True(t, hasVolume(ds, envoyAPIAccessVolName))
+	assert.False(t, *ds.Spec.Template.Spec.AutomountServiceAccountToken)
+	container := checkDaemonSetHasContainer(t, ds, EnvoyContainerName, true)
+	for _, m := range container.VolumeMounts {
+		assert.NotEqual(t, envoyAPIAccessVolName, m.Name)
+	}
+
+	// A zone set by the extra args replaces the node's zone.
+	cntr.Spec.EnvoyExtraArgs = []string{"--service-zone=zone-a"}
+	ds = DesiredDaemonSet(cntr, testContourImage, testEnvoyImage)
+	initContainer = checkDaemonSetHasContainer(t, ds, EnvoyInitContainerName, true)
+	checkContainerHasArg(t, initContainer, "--topology-aware-routing")
+	assert.NotContains(t, initContainer.Args, "--node-name=$(ENVOY_NODE_NAME)")
+	assert.False(t, hasVolume(ds, envoyAPIAccessVolName))
+	container = checkDaemonSetHasContainer(t, ds, EnvoyContainerName, true)
+	checkContainerHasArg(t, container, "--service-zone=zone-a")
+
+	// Without topology aware routing, neither is set.
+	cntr.Spec.RuntimeSettings = nil
+	cntr.Spec.EnvoyExtraArgs = nil
+	ds = DesiredDaemonSet(cntr, testContourImage, testEnvoyImage)
+	initContainer = checkDaemonSetHasContainer(t, ds, EnvoyInitContainerName, true)
+	assert.NotContains(t, initContainer.Args, "--topology-aware-routing")
+	assert.NotContains(t, initContainer.Args, "--node-name=$(ENVOY_NODE_NAME)")
+	assert.False(t, hasVolume(ds, envoyAPIAccessVolName))
+}
+
+func TestEnvoyMetrics(t *testing.T) {
+	name := "envoy-metrics"
+	cntr := model.Default(fmt.Sprintf("%s-ns", name), name)
+	cntr.Spec.EnvoyStatsFlushInterval = 10 * time.Second
+	cntr.Spec.EnvoyHistogramBuckets = []v1alpha1.EnvoyHistogramBuckets{
+		{Regex: `cluster\..*\.upstream_rq_time`, Buckets: []string{"0.5", "1", "5"}},
+		{Regex: "http\\..*", Buckets: []string{"10"}},
+	}
+
+	testContourImage := "ghcr.io/projectcontour/contour:test"
+	testEnvoyImage := "docker.io/envoyproxy/envoy:test"
+
+	// The stats settings are rendered into Envoy's bootstrap.
+	ds := DesiredDaemonSet(cntr, testContourImage, testEnvoyImage)
+	initContainer := &ds.Spec.Template.Spec.InitContainers[0]
+	checkContainerHasArg(t, initContainer, "--stats-flush-interval=10s")
+	checkContainerHasArg(t, initContainer, `--stats-histogram-buckets=cluster\..*\.upstream_rq_time=0.5,1,5`)
+	checkContainerHasArg(t, initContainer, `--stats-histogram-buckets=http\..*=10`)
+
+	// Without them, Envoy's defaults are used.
+	cntr.Spec.EnvoyStatsFlushInterval = 0
+	cntr.Spec.EnvoyHistogramBuckets = nil
+	ds = DesiredDaemonSet(cntr, testContourImage, testEnvoyImage)
+	for _, arg := range ds.Spec.Template.Spec.InitContainers[0].Args {
+		assert.False(t, strings.HasPrefix(arg, "--stats-"), arg)
+	}
+}
+
 func TestEnvoyCustomPorts(t *testing.T) {
 	name := "envoy-runtime-ports"
 	cntr := model.Default(fmt.Sprintf("%s-ns", name), name)
@@ -33,8 +33,8 @@ import (
 )
 
 const (
-	// contourContainerName is the name of the Contour container.
-	contourContainerName = "contour"
+	// ContourContainerName is the name of the Contour container.
+	ContourContainerName = "contour"
 	// contourNsEnvVar is the name of the contour namespace environment variable.
 	contourNsEnvVar = "CONTOUR_NAMESPACE"
 	// contourPodEnvVar is the name of the contour pod name environment variable.
@@ -50,7 +50,8 @@ const (
 )
 
 // EnsureDeployment ensures a deployment using image exists for the given contour.
-func EnsureDeployment(ctx context.Context, cli client.Client, contour *model.Contour, image string) error {
+// The changes that roll out the pods of an existing deployment are passed to recordRollout, if set.
+func EnsureDeployment(ctx context.Context, cli client.Client, contour *model.Contour, image string, recordRollout objects.RolloutRecorder) error {
 	desired := DesiredDeployment(contour, image)
 
 	updater := func(ctx context.Context, cli client.Client, current, desired *appsv1.Deployment) error {
@@ -59,7 +60,7 @@ func EnsureDeployment(ctx context.Context, cli client.Client, contour *model.Con
 			return EnsureDeploymentDeleted(ctx, cli, contour)
 		}
 
-		return updateDeploymentIfNeeded(ctx, cli, contour, current, desired)
+		return updateDeploymentIfNeeded(ctx, cli, contour, current, desired, recordRollout)
 	}
 
 	return objects.EnsureObject(ctx, cli, desired, updater, &appsv1.Deployment{})
@@ -100,6 +101,13 @@ func DesiredDeployment(contour *model.Contour, image string) *appsv1.Deployment
 		args = append(args, "--debug")
 	}
 
+	if qps := contour.Spec.KubernetesClientQPS; qps > 0 {
+		args = append(args, fmt.Sprintf("--kubernetes-client-qps=%d", qps))
+	}
+	if burst := contour.Spec.KubernetesClientBurst; burst > 0 {
+		args = append(args, fmt.Sprintf("--kubernetes-client-burst=%d", burst))
+	}
+
 	// Pass the insecure/secure flags to Contour if using non-default ports.
 	for _, port := range contour.Spec.NetworkPublishing.Envoy.Ports {
 		switch {
@@ -113,7 +121,7 @@ func DesiredDeployment(contour *model.Contour, image string) *appsv1.Deployment
 		args = append(args, fmt.Sprintf("--ingress-class-name=%s", *contour.Spec.IngressClassName))
 	}
 	container := corev1.Container{
-		Name:            contourContainerName,
+		Name:            ContourContainerName,
 		Image:           image,
 		ImagePullPolicy: corev1.PullIfNotPresent,
 		Command:         []string{"contour"},
@@ -234,7 +242,8 @@ func DesiredDeployment(contour *model.Contour, image string) *appsv1.Deployment
 							},
 						},
 					},
-					Containers: []corev1.Container{container},
+					InitContainers: objects.InitContainers(contour.Spec.ContourExtraInitContainers),
+					Containers:     []corev1.Container{container},
 					Volumes: []corev1.Volume{
 						{
 							Name: contourCertsVolName,
@@ -265,18 +274,26 @@ func DesiredDeployment(contour *model.Contour, image string) *appsv1.Deployment
 		deploy.Spec.Template.Spec.Tolerations = contour.Spec.NodePlacement.Contour.Tolerations
 	}
 
+	if contour.ContourTopologySpreadConstraintsExist() {
+		deploy.Spec.Template.Spec.TopologySpreadConstraints = objects.TopologySpreadConstraints(
+			contour.Spec.NodePlacement.Contour.TopologySpreadConstraints, ContourDeploymentPodSelector(contour))
+	}
+
 	return deploy
 }
 
 // updateDeploymentIfNeeded updates a Deployment if current does not match desired,
 // using contour to verify the existence of owner labels.
-func updateDeploymentIfNeeded(ctx context.Context, cli client.Client, contour *model.Contour, current, desired *appsv1.Deployment) error {
+func updateDeploymentIfNeeded(ctx context.Context, cli client.Client, contour *model.Contour, current, desired *appsv1.Deployment, recordRollout objects.RolloutRecorder) error {
 	if labels.Exist(current, model.OwnerLabels(contour)) {
 		deploy, updated := equality.DeploymentConfigChanged(current, desired)
 		if updated {
 			if err := cli.Update(ctx, deploy); err != nil {
 				return fmt.Errorf("failed to update deployment %s/%s: %w", deploy.Namespace, deploy.Name, err)
 			}
+			if changes := equality.PodTemplateChanges(&current.Spec.Template, &deploy.Spec.Template); len(changes) > 0 && recordRollout != nil {
+				recordRollout(deploy, changes)
+			}
 		}
 	}
 	return nil
@@ -287,15 +304,21 @@ func updateDeploymentIfNeeded(ctx context.Context, cli client.Client, contour *m
 func ContourDeploymentPodSelector(contour *model.Contour) *metav1.LabelSelector {
 	return &metav1.LabelSelector{
 		MatchLabels: map[string]string{
-			"app": contour.ContourDeploymentName(),
+			model.PodSelectorLabel: contour.ContourDeploymentName(),
 		},
 	}
 }
 
-// contourPodLabels returns the labels for contour's pods, there are pod selector &
-// app labels
+// contourPodLabels returns the labels for contour's pods, there are the user's
+// pod labels, overridden by the pod selector & app labels
 func contourPodLabels(contour *model.Contour) map[string]string {
-	labels := ContourDeploymentPodSelector(contour).MatchLabels
+	labels := map[string]string{}
+	for k, v := range contour.Spec.ContourPodLabels {
+		labels[k] = v
+	}
+	for k, v := range ContourDeploymentPodSelector(contour).MatchLabels {
+		labels[k] = v
+	}
 	for k, v := range contour.AppLabels() {
 		labels[k] = v
 	}
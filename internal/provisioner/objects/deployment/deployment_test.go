@@ -19,6 +19,7 @@ import (
 
 	"github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
 	"github.com/projectcontour/contour/internal/provisioner/model"
+	"github.com/stretchr/testify/assert"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -145,6 +146,11 @@ func TestDesiredDeployment(t *testing.T) {
 	// Change the Kubernetes log level to test --kubernetes-debug.
 	cntr.Spec.KubernetesLogLevel = 7
 
+	// Set the Kubernetes client rate limits to test --kubernetes-client-qps
+	// and --kubernetes-client-burst.
+	cntr.Spec.KubernetesClientQPS = 50
+	cntr.Spec.KubernetesClientBurst = 100
+
 	// Change the Contour log level to test --debug.
 	cntr.Spec.ContourLogLevel = v1alpha1.DebugLog
 
@@ -162,7 +168,7 @@ func TestDesiredDeployment(t *testing.T) {
 	testContourImage := "ghcr.io/projectcontour/contour:test"
 	deploy := DesiredDeployment(cntr, testContourImage)
 
-	container := checkDeploymentHasContainer(t, deploy, contourContainerName, true)
+	container := checkDeploymentHasContainer(t, deploy, ContourContainerName, true)
 	checkContainerHasImage(t, container, testContourImage)
 	checkDeploymentHasEnvVar(t, deploy, contourNsEnvVar)
 	checkDeploymentHasEnvVar(t, deploy, contourPodEnvVar)
@@ -189,6 +195,9 @@ func TestDesiredDeployment(t *testing.T) {
 	arg = fmt.Sprintf("--kubernetes-debug=%d", cntr.Spec.KubernetesLogLevel)
 	checkContainerHasArg(t, container, arg)
 
+	checkContainerHasArg(t, container, "--kubernetes-client-qps=50")
+	checkContainerHasArg(t, container, "--kubernetes-client-burst=100")
+
 	checkDeploymentHasNodeSelector(t, deploy, nil)
 	checkDeploymentHasTolerations(t, deploy, nil)
 	checkDeploymentHasResourceRequirements(t, deploy, resQutoa)
@@ -222,3 +231,64 @@ func TestNodePlacementDeployment(t *testing.T) {
 	checkDeploymentHasTolerations(t, deploy, tolerations)
 
 }
+
+func TestContourPodLabels(t *testing.T) {
+	name := "pod-labels-test"
+	cntr := model.Default(fmt.Sprintf("%s-ns", name), name)
+	cntr.Spec.ContourPodLabels = map[string]string{
+		"logging.example.com/collect": "true",
+	}
+
+	deploy := DesiredDeployment(cntr, "ghcr.io/projectcontour/contour:test")
+	assert.Equal(t, "true", deploy.Spec.Template.Labels["logging.example.com/collect"])
+	assert.Equal(t, ContourDeploymentPodSelector(cntr), deploy.Spec.Selector)
+}
+
+func TestContourExtraInitContainers(t *testing.T) {
+	name := "extra-init-containers-test"
+	cntr := model.Default(fmt.Sprintf("%s-ns", name), name)
+
+	deploy := DesiredDeployment(cntr, "ghcr.io/projectcontour/contour:test")
+	assert.Nil(t, deploy.Spec.Template.Spec.InitContainers)
+
+	cntr.Spec.ContourExtraInitContainers = []corev1.Container{{
+		Name:  "fetch-secrets",
+		Image: "docker.io/library/busybox",
+	}}
+
+	deploy = DesiredDeployment(cntr, "ghcr.io/projectcontour/contour:test")
+	assert.Equal(t, []corev1.Container{{
+		Name:                     "fetch-secrets",
+		Image:                    "docker.io/library/busybox",
+		ImagePullPolicy:          corev1.PullAlways,
+		TerminationMessagePath:   corev1.TerminationMessagePathDefault,
+		TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+	}}, deploy.Spec.Template.Spec.InitContainers)
+}
+
+func TestTopologySpreadConstraintsDeployment(t *testing.T) {
+	name := "topology-spread-test"
+	cntr := model.Default(fmt.Sprintf("%s-ns", name), name)
+
+	cntr.Spec.NodePlacement = &model.NodePlacement{
+		Contour: &model.ContourNodePlacement{
+			TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+				{
+					MaxSkew:           1,
+					TopologyKey:       "kubernetes.io/hostname",
+					WhenUnsatisfiable: corev1.ScheduleAnyway,
+				},
+			},
+		},
+	}
+
+	deploy := DesiredDeployment(cntr, "ghcr.io/projectcontour/contour:test")
+	assert.Equal(t, []corev1.TopologySpreadConstraint{
+		{
+			MaxSkew:           1,
+			TopologyKey:       "kubernetes.io/hostname",
+			WhenUnsatisfiable: corev1.ScheduleAnyway,
+			LabelSelector:     ContourDeploymentPodSelector(cntr),
+		},
+	}, deploy.Spec.Template.Spec.TopologySpreadConstraints)
+}
@@ -16,12 +16,14 @@ package objects
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/projectcontour/contour/internal/provisioner/labels"
 	"github.com/projectcontour/contour/internal/provisioner/model"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -47,6 +49,77 @@ func NewUnprivilegedPodSecurity() *corev1.PodSecurityContext {
 	}
 }
 
+// TopologySpreadConstraints returns a copy of constraints in which the
+// constraints without a label selector select the pods matched by selector.
+func TopologySpreadConstraints(constraints []corev1.TopologySpreadConstraint, selector *metav1.LabelSelector) []corev1.TopologySpreadConstraint {
+	if len(constraints) == 0 {
+		return nil
+	}
+
+	result := make([]corev1.TopologySpreadConstraint, len(constraints))
+	for i, constraint := range constraints {
+		constraint.DeepCopyInto(&result[i])
+		if result[i].LabelSelector == nil {
+			result[i].LabelSelector = selector.DeepCopy()
+		}
+	}
+
+	return result
+}
+
+// InitContainers returns the init containers of a pod that runs the
+// user-provided extra init containers before the managed ones. The
+// extra init containers are copied, and the fields the API server
+// defaults are set on them, so that the pod template of the workload
+// does not appear to change on every reconcile.
+func InitContainers(extra []corev1.Container, managed ...corev1.Container) []corev1.Container {
+	if len(extra)+len(managed) == 0 {
+		return nil
+	}
+
+	result := make([]corev1.Container, 0, len(extra)+len(managed))
+	for i := range extra {
+		container := *extra[i].DeepCopy()
+		if container.TerminationMessagePath == "" {
+			container.TerminationMessagePath = corev1.TerminationMessagePathDefault
+		}
+		if container.TerminationMessagePolicy == "" {
+			container.TerminationMessagePolicy = corev1.TerminationMessageReadFile
+		}
+		if container.ImagePullPolicy == "" {
+			container.ImagePullPolicy = defaultPullPolicy(container.Image)
+		}
+		result = append(result, container)
+	}
+
+	return append(result, managed...)
+}
+
+// defaultPullPolicy returns the pull policy the API server defaults
+// a container using image to.
+func defaultPullPolicy(image string) corev1.PullPolicy {
+	// Digests are not tags.
+	if strings.Contains(image, "@") {
+		return corev1.PullIfNotPresent
+	}
+
+	// A colon after the last slash separates the tag, colons
+	// before it are part of the registry host's port.
+	tag := ""
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		tag = image[i+1:]
+	}
+	if tag == "" || tag == "latest" {
+		return corev1.PullAlways
+	}
+
+	return corev1.PullIfNotPresent
+}
+
+// RolloutRecorder records the changes that roll out the pods of a
+// workload when the workload is updated.
+type RolloutRecorder func(workload client.Object, changes []string)
+
 // EnsureObject ensures that object "desired" is created or updated.
 // If it does not already exist, it will be created as specified in
 // "desired". If it does already exist, the "updateObject" function
@@ -104,3 +177,47 @@ func EnsureObjectDeleted[T client.Object](ctx context.Context, cli client.Client
 
 	return nil
 }
+
+// EnsureObjectPruned ensures that object "obj", which is no longer
+// desired for the given contour, is deleted. Unlike EnsureObjectDeleted,
+// it only deletes an object controlled by one of contour's owner
+// references, so that objects the provisioner did not create for the
+// Gateway are left alone. No error will be returned if the object
+// already does not exist.
+func EnsureObjectPruned[T client.Object](ctx context.Context, cli client.Client, obj T, contour *model.Contour) error {
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if !isControlledBy(obj, contour.OwnerReferences) {
+		return nil
+	}
+
+	if err := cli.Delete(ctx, obj); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isControlledBy returns whether the controller of obj is one of the
+// owners.
+func isControlledBy(obj metav1.Object, owners []metav1.OwnerReference) bool {
+	controller := metav1.GetControllerOfNoCopy(obj)
+	if controller == nil {
+		return false
+	}
+
+	for _, owner := range owners {
+		if owner.UID == controller.UID {
+			return true
+		}
+	}
+
+	return false
+}
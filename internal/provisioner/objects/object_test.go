@@ -20,6 +20,7 @@ import (
 
 	"github.com/projectcontour/contour/internal/provisioner"
 	"github.com/projectcontour/contour/internal/provisioner/model"
+	"github.com/projectcontour/contour/internal/ref"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -279,3 +280,117 @@ func TestEnsureObjectDeleted_ObjectExistsWithMatchingLabels(t *testing.T) {
 	res := &corev1.Service{}
 	require.True(t, apierrors.IsNotFound(client.Get(context.Background(), pkgclient.ObjectKeyFromObject(existing), res)))
 }
+
+func TestEnsureObjectPruned(t *testing.T) {
+	scheme, err := provisioner.CreateScheme()
+	require.NoError(t, err)
+
+	contour := model.Default("projectcontour", "contour")
+	contour.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: "gateway.networking.k8s.io/v1beta1",
+		Kind:       "Gateway",
+		Name:       "contour",
+		UID:        "gateway-uid",
+		Controller: ref.To(true),
+	}}
+
+	tests := map[string]struct {
+		owners      []metav1.OwnerReference
+		wantDeleted bool
+	}{
+		"object without owner references": {},
+		"object controlled by another owner": {
+			owners: []metav1.OwnerReference{{
+				APIVersion: "gateway.networking.k8s.io/v1beta1",
+				Kind:       "Gateway",
+				Name:       "contour",
+				UID:        "other-uid",
+				Controller: ref.To(true),
+			}},
+		},
+		"object owned but not controlled by the Gateway": {
+			owners: []metav1.OwnerReference{{
+				APIVersion: "gateway.networking.k8s.io/v1beta1",
+				Kind:       "Gateway",
+				Name:       "contour",
+				UID:        "gateway-uid",
+			}},
+		},
+		"object controlled by the Gateway": {
+			owners:      contour.OwnerReferences,
+			wantDeleted: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			existing := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:       "obj-ns",
+					Name:            "obj-name",
+					OwnerReferences: tc.owners,
+				},
+			}
+
+			client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()
+
+			require.NoError(t, EnsureObjectPruned(context.Background(), client, existing.DeepCopy(), contour))
+
+			err := client.Get(context.Background(), pkgclient.ObjectKeyFromObject(existing), &corev1.Service{})
+			if tc.wantDeleted {
+				require.True(t, apierrors.IsNotFound(err))
+			} else {
+				require.NoError(t, err)
+			}
+
+			// Pruning an object that does not exist is a no-op.
+			require.NoError(t, EnsureObjectPruned(context.Background(), client, &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "obj-ns", Name: "missing"},
+			}, contour))
+		})
+	}
+}
+
+func TestInitContainers(t *testing.T) {
+	managed := corev1.Container{Name: "managed", Image: "ghcr.io/projectcontour/contour:test"}
+
+	assert.Nil(t, InitContainers(nil))
+	assert.Equal(t, []corev1.Container{managed}, InitContainers(nil, managed))
+
+	extra := []corev1.Container{
+		{Name: "untagged", Image: "busybox"},
+		{Name: "latest", Image: "busybox:latest"},
+		{Name: "tagged", Image: "registry.example.com:5000/busybox:1.36"},
+		{Name: "digest", Image: "busybox@sha256:2376a0c12759aa1214ba83e771ff252c7b1663216b192fbe5e0fb364e952f85c"},
+		{
+			Name:                     "explicit",
+			Image:                    "busybox",
+			ImagePullPolicy:          corev1.PullNever,
+			TerminationMessagePath:   "/tmp/termination-log",
+			TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+		},
+	}
+
+	got := InitContainers(extra, managed)
+	require.Len(t, got, len(extra)+1)
+
+	// The extra init containers run first, with the API server defaults.
+	for i, want := range []corev1.PullPolicy{
+		corev1.PullAlways,
+		corev1.PullAlways,
+		corev1.PullIfNotPresent,
+		corev1.PullIfNotPresent,
+		corev1.PullNever,
+	} {
+		assert.Equal(t, extra[i].Name, got[i].Name)
+		assert.Equal(t, want, got[i].ImagePullPolicy, got[i].Name)
+	}
+	assert.Equal(t, corev1.TerminationMessagePathDefault, got[0].TerminationMessagePath)
+	assert.Equal(t, corev1.TerminationMessageReadFile, got[0].TerminationMessagePolicy)
+	assert.Equal(t, "/tmp/termination-log", got[4].TerminationMessagePath)
+	assert.Equal(t, corev1.TerminationMessageFallbackToLogsOnError, got[4].TerminationMessagePolicy)
+	assert.Equal(t, managed, got[5])
+
+	// The user-provided containers are not changed.
+	assert.Empty(t, extra[0].ImagePullPolicy)
+}
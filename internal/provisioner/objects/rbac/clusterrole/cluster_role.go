@@ -22,6 +22,7 @@ import (
 	"github.com/projectcontour/contour/internal/provisioner/model"
 	"github.com/projectcontour/contour/internal/provisioner/objects"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -73,7 +74,8 @@ func desiredClusterRole(name string, contour *model.Contour) *rbacv1.ClusterRole
 		},
 		Rules: []rbacv1.PolicyRule{
 			// Core Contour-watched resources.
-			policyRuleFor(corev1.GroupName, getListWatch, "secrets", "endpoints", "services", "namespaces"),
+			policyRuleFor(corev1.GroupName, getListWatch, "secrets", "endpoints", "services", "namespaces", "nodes", "pods"),
+			policyRuleFor(discoveryv1.GroupName, getListWatch, "endpointslices"),
 
 			// Gateway API resources.
 			// Note, ReferenceGrant does not currently have a .status field so it's omitted from the status rule.
@@ -91,6 +93,43 @@ func desiredClusterRole(name string, contour *model.Contour) *rbacv1.ClusterRole
 	}
 }
 
+// EnsureEnvoyClusterRole ensures a ClusterRole resource for Envoy exists
+// with the provided name and contour namespace/name for the owning
+// contour labels.
+func EnsureEnvoyClusterRole(ctx context.Context, cli client.Client, name string, contour *model.Contour) error {
+	desired := desiredEnvoyClusterRole(name, contour)
+
+	// Enclose contour.
+	updater := func(ctx context.Context, cli client.Client, current, desired *rbacv1.ClusterRole) error {
+		return updateClusterRoleIfNeeded(ctx, cli, contour, current, desired)
+	}
+
+	return objects.EnsureObject(ctx, cli, desired, updater, &rbacv1.ClusterRole{})
+}
+
+// desiredEnvoyClusterRole constructs an instance of the desired ClusterRole
+// resource for Envoy with the provided name and contour namespace/name for
+// the owning contour labels. Envoy's init container gets its node to look up
+// the zone for topology aware routing.
+func desiredEnvoyClusterRole(name string, contour *model.Contour) *rbacv1.ClusterRole {
+	return &rbacv1.ClusterRole{
+		TypeMeta: metav1.TypeMeta{
+			Kind: "Role",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: model.CommonLabels(contour),
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				Verbs:     []string{"get"},
+				APIGroups: []string{corev1.GroupName},
+				Resources: []string{"nodes"},
+			},
+		},
+	}
+}
+
 // updateClusterRoleIfNeeded updates a ClusterRole resource if current does not match desired,
 // using contour to verify the existence of owner labels.
 func updateClusterRoleIfNeeded(ctx context.Context, cli client.Client, contour *model.Contour, current, desired *rbacv1.ClusterRole) error {
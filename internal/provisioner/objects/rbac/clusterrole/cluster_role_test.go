@@ -53,3 +53,23 @@ func TestDesiredClusterRole(t *testing.T) {
 	}
 	checkClusterRoleLabels(t, cr, ownerLabels)
 }
+
+func TestDesiredEnvoyClusterRole(t *testing.T) {
+	name := "test-cr"
+	cntr := model.Default(fmt.Sprintf("%s-ns", name), name)
+	cr := desiredEnvoyClusterRole(name, cntr)
+	checkClusterRoleName(t, cr, name)
+	ownerLabels := map[string]string{
+		model.OwningGatewayNameLabel: cntr.Name,
+	}
+	checkClusterRoleLabels(t, cr, ownerLabels)
+
+	want := []rbacv1.PolicyRule{{
+		Verbs:     []string{"get"},
+		APIGroups: []string{""},
+		Resources: []string{"nodes"},
+	}}
+	if !apiequality.Semantic.DeepEqual(cr.Rules, want) {
+		t.Errorf("cluster role has unexpected %v rules", cr.Rules)
+	}
+}
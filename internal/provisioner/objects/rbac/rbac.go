@@ -78,6 +78,14 @@ func ensureEnvoyRBAC(ctx context.Context, cli client.Client, contour *model.Cont
 	if err := serviceaccount.EnsureServiceAccount(ctx, cli, names.ServiceAccount, contour); err != nil {
 		return fmt.Errorf("failed to ensure service account %s/%s: %w", contour.Namespace, names.ServiceAccount, err)
 	}
+
+	// Ensure cluster role & binding.
+	if err := clusterrole.EnsureEnvoyClusterRole(ctx, cli, names.ClusterRole, contour); err != nil {
+		return fmt.Errorf("failed to ensure cluster role %s: %w", names.ClusterRole, err)
+	}
+	if err := clusterrolebinding.EnsureClusterRoleBinding(ctx, cli, names.ClusterRoleBinding, names.ClusterRole, names.ServiceAccount, contour); err != nil {
+		return fmt.Errorf("failed to ensure cluster role binding %s: %w", names.ClusterRoleBinding, err)
+	}
 	return nil
 }
 
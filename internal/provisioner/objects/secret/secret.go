@@ -15,8 +15,12 @@ package secret
 
 import (
 	"context"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/projectcontour/contour/internal/certgen"
 	"github.com/projectcontour/contour/internal/provisioner/model"
@@ -32,31 +36,48 @@ import (
 // the version of Contour that the TLS secrets were generated by.
 const generatedByVersionAnnotation = "projectcontour.io/generated-by-version"
 
+// keyTypeAnnotation and certificateLifetimeAnnotation are the keys for
+// the annotations that store the type of key and the lifetime in days
+// that the TLS secrets were generated with.
+const (
+	keyTypeAnnotation             = "projectcontour.io/xds-key-type"
+	certificateLifetimeAnnotation = "projectcontour.io/xds-certificate-lifetime"
+)
+
 // EnsureXDSSecrets ensures that mTLS secrets for Contour and Envoy exist
-// and are up-to-date, i.e. have been generated with the current version.
-func EnsureXDSSecrets(ctx context.Context, cli client.Client, contour *model.Contour, image string) error {
-	desiredVersion := tagFromImage(image)
+// and are up-to-date, i.e. have been generated with the current version
+// and xDS TLS settings, and are not close to expiring. It returns the
+// time at which the secrets must be rotated.
+func EnsureXDSSecrets(ctx context.Context, cli client.Client, contour *model.Contour, image string) (time.Time, error) {
+	desired := map[string]string{
+		generatedByVersionAnnotation:  tagFromImage(image),
+		keyTypeAnnotation:             xdsKeyType(contour),
+		certificateLifetimeAnnotation: strconv.Itoa(xdsCertificateLifetime(contour)),
+	}
 
-	if tlsSecretsExist(contour, cli, desiredVersion) {
-		return nil
+	if rotation, ok := tlsSecretsRotation(contour, cli, desired); ok && time.Now().Before(rotation) {
+		return rotation, nil
 	}
 
 	certs, err := certs.GenerateCerts(
 		&certs.Configuration{
-			Lifetime:  365,
+			Lifetime:  uint(xdsCertificateLifetime(contour)),
 			Namespace: contour.Namespace,
+			KeyType:   xdsKeyType(contour),
 		},
 	)
 	if err != nil {
-		return fmt.Errorf("error generating xDS TLS certificates: %w", err)
+		return time.Time{}, fmt.Errorf("error generating xDS TLS certificates: %w", err)
 	}
 
 	secrets, errs := certgen.AsSecrets(contour.Namespace, "-"+contour.Name, certs)
 	if len(errs) > 0 {
-		return utilerrors.NewAggregate(errs)
+		return time.Time{}, utilerrors.NewAggregate(errs)
 	}
 
-	for _, secret := range secrets {
+	var rotation time.Time
+
+	for i, secret := range secrets {
 		// Add owner & user-defined labels.
 		if secret.Labels == nil {
 			secret.Labels = model.CommonLabels(contour)
@@ -66,26 +87,32 @@ func EnsureXDSSecrets(ctx context.Context, cli client.Client, contour *model.Con
 			}
 		}
 
-		// Add annotation indicating the version the secret was
-		// generated by, to ensure that we're rotating the secrets
-		// on upgrade.
+		// Add annotations indicating the version and settings the
+		// secret was generated with, to ensure that we're rotating
+		// the secrets on upgrade or when the settings change.
 		if secret.Annotations == nil {
 			secret.Annotations = map[string]string{}
 		}
-		secret.Annotations[generatedByVersionAnnotation] = tagFromImage(image)
+		for k, v := range desired {
+			secret.Annotations[k] = v
+		}
 
 		if err := cli.Create(ctx, secret); err != nil {
 			if !errors.IsAlreadyExists(err) {
-				return fmt.Errorf("error creating secret: %w", err)
+				return time.Time{}, fmt.Errorf("error creating secret: %w", err)
 			}
 
 			if err := cli.Update(ctx, secret); err != nil {
-				return fmt.Errorf("error updating secret: %w", err)
+				return time.Time{}, fmt.Errorf("error updating secret: %w", err)
 			}
 		}
+
+		if r := certificateRotation(contour, secret); i == 0 || r.Before(rotation) {
+			rotation = r
+		}
 	}
 
-	return nil
+	return rotation, nil
 }
 
 // tagFromImage returns the tag from the provided image or an
@@ -98,8 +125,30 @@ func tagFromImage(image string) string {
 	return ""
 }
 
-func tlsSecretsExist(contour *model.Contour, cli client.Client, generatedByVersion string) bool {
-	for _, secretName := range []string{
+// xdsKeyType returns the type of key to generate the xDS certificates
+// with.
+func xdsKeyType(contour *model.Contour) string {
+	if contour.Spec.XDSServerKeyType != "" {
+		return string(contour.Spec.XDSServerKeyType)
+	}
+	return certs.KeyTypeRSA
+}
+
+// xdsCertificateLifetime returns the number of days the xDS
+// certificates are valid for.
+func xdsCertificateLifetime(contour *model.Contour) int {
+	if contour.Spec.XDSServerCertificateLifetime > 0 {
+		return int(contour.Spec.XDSServerCertificateLifetime)
+	}
+	return certs.DefaultCertificateLifetime
+}
+
+// tlsSecretsRotation returns the earliest time at which the TLS secrets
+// must be rotated, and whether they all exist with the given annotations.
+func tlsSecretsRotation(contour *model.Contour, cli client.Client, annotations map[string]string) (time.Time, bool) {
+	var rotation time.Time
+
+	for i, secretName := range []string{
 		contour.ContourCertsSecretName(),
 		contour.EnvoyCertsSecretName(),
 	} {
@@ -111,15 +160,50 @@ func tlsSecretsExist(contour *model.Contour, cli client.Client, generatedByVersi
 		}
 
 		if err := cli.Get(context.Background(), key, s); err != nil {
-			return false
+			return time.Time{}, false
+		}
+
+		for k, v := range annotations {
+			if s.Annotations[k] != v {
+				return time.Time{}, false
+			}
 		}
 
-		if s.Annotations[generatedByVersionAnnotation] != generatedByVersion {
-			return false
+		if r := certificateRotation(contour, s); i == 0 || r.Before(rotation) {
+			rotation = r
 		}
 	}
 
-	return true
+	return rotation, true
+}
+
+// certificateRotation returns the time at which the certificate in s
+// must be rotated, which is once less than a third of its lifetime
+// remains.
+func certificateRotation(contour *model.Contour, s *corev1.Secret) time.Time {
+	expiry := certificateExpiry(s)
+	if expiry.IsZero() {
+		return expiry
+	}
+
+	lifetime := 24 * time.Hour * time.Duration(xdsCertificateLifetime(contour))
+	return expiry.Add(-lifetime / 3)
+}
+
+// certificateExpiry returns the expiry time of the certificate in s,
+// or the zero time if it can't be parsed.
+func certificateExpiry(s *corev1.Secret) time.Time {
+	block, _ := pem.Decode(s.Data[corev1.TLSCertKey])
+	if block == nil {
+		return time.Time{}
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return cert.NotAfter
 }
 
 func EnsureXDSSecretsDeleted(ctx context.Context, cli client.Client, contour *model.Contour) error {
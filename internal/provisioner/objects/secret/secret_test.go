@@ -0,0 +1,98 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/tls"
+	"testing"
+	"time"
+
+	contourv1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
+	"github.com/projectcontour/contour/internal/provisioner"
+	"github.com/projectcontour/contour/internal/provisioner/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestEnsureXDSSecrets(t *testing.T) {
+	scheme, err := provisioner.CreateScheme()
+	require.NoError(t, err)
+
+	cli := fake.NewClientBuilder().WithScheme(scheme).Build()
+	contour := model.Default("projectcontour", "contour")
+	image := "ghcr.io/projectcontour/contour:main"
+
+	getSecrets := func() []*corev1.Secret {
+		var secrets []*corev1.Secret
+		for _, name := range []string{contour.ContourCertsSecretName(), contour.EnvoyCertsSecretName()} {
+			s := &corev1.Secret{}
+			require.NoError(t, cli.Get(context.Background(), client.ObjectKey{Namespace: contour.Namespace, Name: name}, s))
+			secrets = append(secrets, s)
+		}
+		return secrets
+	}
+
+	requireKeyType := func(want interface{}) {
+		for _, s := range getSecrets() {
+			pair, err := tls.X509KeyPair(s.Data[corev1.TLSCertKey], s.Data[corev1.TLSPrivateKeyKey])
+			require.NoError(t, err)
+			assert.IsType(t, want, pair.PrivateKey)
+		}
+	}
+
+	// RSA keys are generated by default.
+	rotation, err := EnsureXDSSecrets(context.Background(), cli, contour, image)
+	require.NoError(t, err)
+	requireKeyType(&rsa.PrivateKey{})
+	assert.Equal(t, "RSA", getSecrets()[0].Annotations[keyTypeAnnotation])
+	assert.Equal(t, "365", getSecrets()[0].Annotations[certificateLifetimeAnnotation])
+
+	// Up-to-date secrets are left alone.
+	before := getSecrets()
+	got, err := EnsureXDSSecrets(context.Background(), cli, contour, image)
+	require.NoError(t, err)
+	assert.Equal(t, before[0].Data, getSecrets()[0].Data)
+	assert.Equal(t, rotation, got)
+
+	// Changing the key type regenerates the secrets.
+	contour.Spec.XDSServerKeyType = contourv1alpha1.XDSServerKeyTypeECDSA
+	_, err = EnsureXDSSecrets(context.Background(), cli, contour, image)
+	require.NoError(t, err)
+	requireKeyType(&ecdsa.PrivateKey{})
+
+	// Changing the lifetime regenerates the secrets, which are
+	// rotated once less than a third of their lifetime remains.
+	contour.Spec.XDSServerCertificateLifetime = 3
+	rotation, err = EnsureXDSSecrets(context.Background(), cli, contour, image)
+	require.NoError(t, err)
+	expiry := certificateExpiry(getSecrets()[0])
+	assert.WithinDuration(t, time.Now().Add(3*24*time.Hour), expiry, time.Minute)
+	assert.WithinDuration(t, time.Now().Add(2*24*time.Hour), rotation, time.Minute)
+
+	annotations := getSecrets()[0].Annotations
+	got, ok := tlsSecretsRotation(contour, cli, annotations)
+	assert.True(t, ok)
+	assert.Equal(t, rotation, got)
+
+	// Secrets with other annotations are regenerated.
+	annotations[keyTypeAnnotation] = "RSA"
+	_, ok = tlsSecretsRotation(contour, cli, annotations)
+	assert.False(t, ok)
+}
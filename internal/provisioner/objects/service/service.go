@@ -24,6 +24,7 @@ import (
 	"github.com/projectcontour/contour/internal/provisioner/objects"
 	"github.com/projectcontour/contour/internal/provisioner/objects/dataplane"
 	"github.com/projectcontour/contour/internal/provisioner/objects/deployment"
+	"github.com/projectcontour/contour/internal/ref"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -184,7 +185,7 @@ func DesiredEnvoyService(contour *model.Contour) *corev1.Service {
 
 	for _, port := range contour.Spec.NetworkPublishing.Envoy.Ports {
 		ports = append(ports, corev1.ServicePort{
-			Name:       port.Name,
+			Name:       port.ServicePortName(),
 			Protocol:   corev1.ProtocolTCP,
 			Port:       port.ServicePort,
 			TargetPort: intstr.IntOrString{IntVal: port.ContainerPort},
@@ -250,6 +251,19 @@ func DesiredEnvoyService(contour *model.Contour) *corev1.Service {
 		}
 	}
 
+	// The API server defaults the timeout of the ClientIP session
+	// affinity, set it so the service does not appear to change.
+	if contour.Spec.NetworkPublishing.Envoy.SessionAffinity == corev1.ServiceAffinityClientIP {
+		timeoutSeconds := contour.Spec.NetworkPublishing.Envoy.SessionAffinityTimeoutSeconds
+		if timeoutSeconds == 0 {
+			timeoutSeconds = corev1.DefaultClientIPServiceAffinitySeconds
+		}
+		svc.Spec.SessionAffinity = corev1.ServiceAffinityClientIP
+		svc.Spec.SessionAffinityConfig = &corev1.SessionAffinityConfig{
+			ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: ref.To(timeoutSeconds)},
+		}
+	}
+
 	epType := contour.Spec.NetworkPublishing.Envoy.Type
 	if epType == model.LoadBalancerServicePublishingType ||
 		epType == model.NodePortServicePublishingType {
@@ -274,7 +288,7 @@ func DesiredEnvoyService(contour *model.Contour) *corev1.Service {
 				continue
 			}
 			for i, q := range svc.Spec.Ports {
-				if q.Name == p.Name {
+				if q.Name == p.ServicePortName() {
 					svc.Spec.Ports[i].NodePort = p.NodePort
 				}
 			}
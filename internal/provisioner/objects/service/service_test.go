@@ -139,6 +139,22 @@ func checkServiceHasLoadBalancerAddress(t *testing.T, svc *corev1.Service, addre
 	}
 }
 
+func checkServiceHasSessionAffinity(t *testing.T, svc *corev1.Service, affinity corev1.ServiceAffinity, timeoutSeconds int32) {
+	t.Helper()
+
+	if svc.Spec.SessionAffinity != affinity {
+		t.Errorf("service has session affinity %s, expected %s", svc.Spec.SessionAffinity, affinity)
+	}
+
+	var got int32
+	if cfg := svc.Spec.SessionAffinityConfig; cfg != nil && cfg.ClientIP != nil && cfg.ClientIP.TimeoutSeconds != nil {
+		got = *cfg.ClientIP.TimeoutSeconds
+	}
+	if got != timeoutSeconds {
+		t.Errorf("service has session affinity timeout %d, expected %d", got, timeoutSeconds)
+	}
+}
+
 func TestDesiredContourService(t *testing.T) {
 	name := "svc-test"
 	cntr := model.Default(fmt.Sprintf("%s-ns", name), name)
@@ -188,6 +204,18 @@ func TestDesiredEnvoyService(t *testing.T) {
 	cntr.Spec.NetworkPublishing.Envoy.Type = model.ClusterIPServicePublishingType
 	svc = DesiredEnvoyService(cntr)
 	checkServiceHasNoExternalTrafficPolicy(t, svc)
+	checkServiceHasSessionAffinity(t, svc, corev1.ServiceAffinityNone, 0)
+
+	// The ClientIP session affinity uses the Kubernetes default timeout
+	// unless one is set.
+	cntr.Spec.NetworkPublishing.Envoy.SessionAffinity = corev1.ServiceAffinityClientIP
+	svc = DesiredEnvoyService(cntr)
+	checkServiceHasSessionAffinity(t, svc, corev1.ServiceAffinityClientIP, corev1.DefaultClientIPServiceAffinitySeconds)
+	cntr.Spec.NetworkPublishing.Envoy.SessionAffinityTimeoutSeconds = 600
+	svc = DesiredEnvoyService(cntr)
+	checkServiceHasSessionAffinity(t, svc, corev1.ServiceAffinityClientIP, 600)
+	cntr.Spec.NetworkPublishing.Envoy.SessionAffinity = ""
+	cntr.Spec.NetworkPublishing.Envoy.SessionAffinityTimeoutSeconds = 0
 
 	// Check LB annotations for the different provider types, starting with AWS ELB (the default
 	// if AWS provider params are not passed).
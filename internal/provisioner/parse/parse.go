@@ -21,6 +21,7 @@ import (
 	_ "crypto/sha512"
 	"fmt"
 	"os/exec"
+	"regexp"
 	"strings"
 
 	"github.com/docker/distribution/reference"
@@ -37,6 +38,21 @@ func Image(s string) error {
 	return nil
 }
 
+// gatewayControllerNameRegexp is the pattern of the GatewayClass
+// controllerName field in the Gateway API CRDs.
+var gatewayControllerNameRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*\/[A-Za-z0-9\/\-._~%!$&'()*+,;=:]+$`)
+
+// GatewayControllerName parses s, returning an error if s is not a
+// valid GatewayClass controllerName, i.e. a domain prefixed path such
+// as "projectcontour.io/gateway-controller".
+func GatewayControllerName(s string) error {
+	if len(s) > 253 || !gatewayControllerNameRegexp.MatchString(s) {
+		return fmt.Errorf("invalid gateway controller name %q: must be a domain prefixed path of at most 253 characters, e.g. example.com/gateway-controller", s)
+	}
+
+	return nil
+}
+
 // StringInPodExec parses the output of cmd for expectedString executed in the specified
 // pod ns/name, returning an error if expectedString was not found.
 func StringInPodExec(ns, name, expectedString string, cmd []string) error {
@@ -14,6 +14,7 @@
 package parse
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -71,3 +72,25 @@ func TestImage(t *testing.T) {
 		}
 	}
 }
+
+func TestGatewayControllerName(t *testing.T) {
+	testCases := map[string]bool{
+		"projectcontour.io/gateway-controller":    true,
+		"example.com/contour/fork":                true,
+		"example.com":                             false,
+		"/gateway-controller":                     false,
+		"Example.com/gateway-controller":          false,
+		"example.com/":                            false,
+		"example.com/" + strings.Repeat("a", 242): false,
+	}
+
+	for name, expected := range testCases {
+		err := GatewayControllerName(name)
+		switch {
+		case err != nil && expected:
+			t.Fatalf("%q: %v", name, err)
+		case err == nil && !expected:
+			t.Fatalf("%q: expected an error but received nil", name)
+		}
+	}
+}
@@ -20,7 +20,7 @@ package rbac
 
 // RBAC for Gateway API.
 // +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gatewayclasses;gateways,verbs=get;list;watch
-// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gatewayclasses/status;gateways/status,verbs=update
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gatewayclasses/status;gateways/status;gateways/finalizers,verbs=update
 // +kubebuilder:rbac:groups=projectcontour.io,resources=contourdeployments,verbs=get;list;watch
 // ---
 
@@ -31,6 +31,10 @@ package rbac
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles;clusterrolebindings;roles;rolebindings,verbs=get;list;watch;create;update;delete
 // ---
 
+// RBAC for recording events on the provisioned Gateways.
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// ---
+
 // RBAC for leader election for the provisioner.
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;get;update,namespace=projectcontour
 // +kubebuilder:rbac:groups="coordination.k8s.io",resources=leases,verbs=create;get;update,namespace=projectcontour
@@ -208,9 +208,9 @@ func (s queryParamMatchConditionSorter) Less(i, j int) bool {
 	}
 }
 
-// longestRouteByHeaderAndQueryParamConditions compares the HeaderMatchConditions
-// and QueryParamMatchConditions slices for lhs and rhs and returns true if lhs is
-// longer.
+// longestRouteByHeaderAndQueryParamConditions compares the HeaderMatchConditions,
+// QueryParamMatchConditions and SourceFilters slices for lhs and rhs and
+// returns true if lhs is longer.
 func longestRouteByHeaderAndQueryParamConditions(lhs, rhs *dag.Route) bool {
 	// One route has a longer HeaderMatchConditions slice.
 	if len(lhs.HeaderMatchConditions) != len(rhs.HeaderMatchConditions) {
@@ -222,6 +222,11 @@ func longestRouteByHeaderAndQueryParamConditions(lhs, rhs *dag.Route) bool {
 		return len(lhs.QueryParamMatchConditions) > len(rhs.QueryParamMatchConditions)
 	}
 
+	// One route has a longer SourceFilters slice.
+	if len(lhs.SourceFilters) != len(rhs.SourceFilters) {
+		return len(lhs.SourceFilters) > len(rhs.SourceFilters)
+	}
+
 	// If there are the same number of header and query parameter matches, sort
 	// based on the priority of the route.
 	// Note: lower values mean a higher priority.
@@ -251,6 +256,13 @@ func longestRouteByHeaderAndQueryParamConditions(lhs, rhs *dag.Route) bool {
 		}
 	}
 
+	// SourceFilters are equal length: compare item by item.
+	for i := 0; i < len(lhs.SourceFilters); i++ {
+		if cmp := strings.Compare(lhs.SourceFilters[i].String(), rhs.SourceFilters[i].String()); cmp != 0 {
+			return cmp < 0
+		}
+	}
+
 	return false
 }
 
@@ -16,6 +16,7 @@ package sorter
 import (
 	"math"
 	"math/rand"
+	"net"
 	"sort"
 	"testing"
 
@@ -503,6 +504,40 @@ func TestSortRoutesQueryParams(t *testing.T) {
 	shuffleAndCheckSort(t, want)
 }
 
+func TestSortRoutesSourceConditions(t *testing.T) {
+	_, internal, _ := net.ParseCIDR("10.0.0.0/8")
+	_, office, _ := net.ParseCIDR("192.168.0.0/16")
+
+	want := []*dag.Route{
+		{
+			PathMatchCondition: matchPrefixString("/"),
+			// More source conditions sort higher.
+			SourceFilters: []dag.SourceFilter{
+				{CIDRs: []*net.IPNet{internal}},
+				{CIDRs: []*net.IPNet{office}},
+			},
+		},
+		{
+			PathMatchCondition: matchPrefixString("/"),
+			// If same number of conditions, sort on element-by-element
+			// comparison of the ranges.
+			SourceFilters: []dag.SourceFilter{
+				{CIDRs: []*net.IPNet{internal}},
+			},
+		},
+		{
+			PathMatchCondition: matchPrefixString("/"),
+			SourceFilters: []dag.SourceFilter{
+				{CIDRs: []*net.IPNet{office}},
+			},
+		},
+		{
+			PathMatchCondition: matchPrefixString("/"),
+		},
+	}
+	shuffleAndCheckSort(t, want)
+}
+
 func TestSortSecrets(t *testing.T) {
 	want := []*envoy_tls_v3.Secret{
 		{Name: "first"},
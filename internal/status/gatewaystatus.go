@@ -26,6 +26,23 @@ import (
 
 const MessageValidGateway = "Valid Gateway"
 
+const (
+	// ListenerConditionTLSOptionsValid is a Contour-specific listener
+	// condition that is false when some of the listener's TLS options
+	// were ignored. It does not affect whether the listener is programmed.
+	ListenerConditionTLSOptionsValid gatewayapi_v1beta1.ListenerConditionType = "TLSOptionsValid"
+
+	ListenerReasonTLSOptionsIgnored gatewayapi_v1beta1.ListenerConditionReason = "TLSOptionsIgnored"
+
+	// GatewayConditionDefaultBackendResolved is a Contour-specific gateway
+	// condition that is false when the configured default backend can not
+	// be resolved, so requests that no route matches are not routed to it.
+	// It does not affect whether the gateway is programmed.
+	GatewayConditionDefaultBackendResolved gatewayapi_v1beta1.GatewayConditionType = "DefaultBackendResolved"
+
+	GatewayReasonDefaultBackendInvalid gatewayapi_v1beta1.GatewayConditionReason = "DefaultBackendInvalid"
+)
+
 // GatewayStatusUpdate represents an atomic update to a
 // Gateway's status.
 type GatewayStatusUpdate struct {
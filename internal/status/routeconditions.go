@@ -28,6 +28,10 @@ import (
 const (
 	ConditionValidBackendRefs gatewayapi_v1beta1.RouteConditionType = "ValidBackendRefs"
 	ConditionValidMatches     gatewayapi_v1beta1.RouteConditionType = "ValidMatches"
+
+	// ConditionRequestHedging is set to false when some requests
+	// to an HTTPRoute that requests hedging are not hedged.
+	ConditionRequestHedging gatewayapi_v1beta1.RouteConditionType = "RequestHedging"
 )
 
 const (
@@ -36,6 +40,10 @@ const (
 	ReasonInvalidPathMatch              gatewayapi_v1beta1.RouteConditionReason = "InvalidPathMatch"
 	ReasonInvalidMethodMatch            gatewayapi_v1beta1.RouteConditionReason = "InvalidMethodMatch"
 	ReasonInvalidGateway                gatewayapi_v1beta1.RouteConditionReason = "InvalidGateway"
+	ReasonHostnameNotAllowed            gatewayapi_v1beta1.RouteConditionReason = "HostnameNotAllowed"
+	ReasonRouteKindDisabled             gatewayapi_v1beta1.RouteConditionReason = "RouteKindDisabled"
+	ReasonIncompatibleFilters           gatewayapi_v1beta1.RouteConditionReason = "IncompatibleFilters"
+	ReasonNonIdempotentMethod           gatewayapi_v1beta1.RouteConditionReason = "NonIdempotentMethod"
 )
 
 // RouteStatusUpdate represents an atomic update to a
@@ -156,7 +164,7 @@ func (r *RouteStatusUpdate) Mutate(obj client.Object) client.Object {
 
 		// Get all the RouteParentStatuses that are for other Gateways.
 		for _, rps := range o.Status.Parents {
-			if !gatewayapi.IsRefToGateway(rps.ParentRef, r.GatewayRef) {
+			if r.isOtherParent(rps) {
 				newRouteParentStatuses = append(newRouteParentStatuses, rps)
 			}
 		}
@@ -169,7 +177,7 @@ func (r *RouteStatusUpdate) Mutate(obj client.Object) client.Object {
 
 		// Get all the RouteParentStatuses that are for other Gateways.
 		for _, rps := range o.Status.Parents {
-			if !gatewayapi.IsRefToGateway(rps.ParentRef, r.GatewayRef) {
+			if r.isOtherParent(rps) {
 				newRouteParentStatuses = append(newRouteParentStatuses, rps)
 			}
 		}
@@ -182,7 +190,7 @@ func (r *RouteStatusUpdate) Mutate(obj client.Object) client.Object {
 
 		// Get all the RouteParentStatuses that are for other Gateways.
 		for _, rps := range o.Status.Parents {
-			if !gatewayapi.IsRefToGateway(rps.ParentRef, r.GatewayRef) {
+			if r.isOtherParent(rps) {
 				newRouteParentStatuses = append(newRouteParentStatuses, rps)
 			}
 		}
@@ -195,3 +203,14 @@ func (r *RouteStatusUpdate) Mutate(obj client.Object) client.Object {
 		panic(fmt.Sprintf("Unsupported %T object %s/%s in RouteConditionsUpdate status mutator", obj, r.FullName.Namespace, r.FullName.Name))
 	}
 }
+
+// isOtherParent returns whether the existing RouteParentStatus is for
+// another parent, and so is kept as is. Statuses for this Gateway and the
+// statuses this controller sets for Service parent refs are replaced.
+func (r *RouteStatusUpdate) isOtherParent(rps gatewayapi_v1beta1.RouteParentStatus) bool {
+	if gatewayapi.IsRefToGateway(rps.ParentRef, r.GatewayRef) {
+		return false
+	}
+
+	return !(gatewayapi.IsRefToService(rps.ParentRef) && rps.ControllerName == r.GatewayController)
+}
@@ -19,6 +19,7 @@ import (
 
 	"github.com/projectcontour/contour/internal/gatewayapi"
 	"github.com/projectcontour/contour/internal/k8s"
+	"github.com/projectcontour/contour/internal/ref"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -47,6 +48,42 @@ func TestHTTPRouteAddCondition(t *testing.T) {
 	assert.EqualValues(t, 7, got.ObservedGeneration)
 }
 
+func TestHTTPRouteMutateServiceParentRef(t *testing.T) {
+	gatewayRef := gatewayapi.GatewayParentRef("projectcontour", "contour")
+	serviceRef := gatewayapi_v1beta1.ParentReference{
+		Group: ref.To(gatewayapi_v1beta1.Group("")),
+		Kind:  ref.To(gatewayapi_v1beta1.Kind("Service")),
+		Name:  "kuard",
+	}
+
+	update := RouteStatusUpdate{
+		FullName:          k8s.NamespacedNameFrom("test/test"),
+		GatewayRef:        k8s.NamespacedNameFrom("projectcontour/contour"),
+		GatewayController: "projectcontour.io/contour",
+	}
+	update.StatusUpdateFor(serviceRef).AddCondition(gatewayapi_v1beta1.RouteConditionAccepted, metav1.ConditionFalse, "UnsupportedValue", "not supported")
+
+	// The existing status set by this controller for the Service is
+	// replaced, the one set by a mesh implementation is kept.
+	route := &gatewayapi_v1beta1.HTTPRoute{
+		Status: gatewayapi_v1beta1.HTTPRouteStatus{
+			RouteStatus: gatewayapi_v1beta1.RouteStatus{
+				Parents: []gatewayapi_v1beta1.RouteParentStatus{
+					{ParentRef: gatewayRef, ControllerName: "projectcontour.io/contour"},
+					{ParentRef: serviceRef, ControllerName: "projectcontour.io/contour"},
+					{ParentRef: serviceRef, ControllerName: "example.com/mesh"},
+				},
+			},
+		},
+	}
+
+	got := update.Mutate(route).(*gatewayapi_v1beta1.HTTPRoute).Status.Parents
+	require.Len(t, got, 2)
+	assert.Equal(t, gatewayapi_v1beta1.GatewayController("projectcontour.io/contour"), got[0].ControllerName)
+	assert.Len(t, got[0].Conditions, 1)
+	assert.Equal(t, gatewayapi_v1beta1.GatewayController("example.com/mesh"), got[1].ControllerName)
+}
+
 func newCondition(t string, status metav1.ConditionStatus, reason, msg string, lt time.Time) metav1.Condition {
 	return metav1.Condition{
 		Type:               t,
@@ -30,6 +30,16 @@ import (
 
 // ClusterCache manages the contents of the gRPC CDS cache.
 type ClusterCache struct {
+	// LocalityWeightedLB enables locality weighted load
+	// balancing on the service clusters.
+	LocalityWeightedLB bool
+
+	// TopologyAwareRouting enables zone aware routing on the
+	// service clusters, for those with at least
+	// TopologyAwareMinClusterSize endpoints.
+	TopologyAwareRouting        bool
+	TopologyAwareMinClusterSize uint32
+
 	mu     sync.Mutex
 	values map[string]*envoy_cluster_v3.Cluster
 	contour.Cond
@@ -83,6 +93,12 @@ func (c *ClusterCache) OnChange(root *dag.DAG) {
 		name := envoy.Clustername(cluster)
 		if _, ok := clusters[name]; !ok {
 			clusters[name] = envoy_v3.Cluster(cluster)
+			if c.LocalityWeightedLB {
+				envoy_v3.LocalityWeightedLB(clusters[name])
+			}
+			if c.TopologyAwareRouting {
+				envoy_v3.ZoneAwareLB(clusters[name], c.TopologyAwareMinClusterSize)
+			}
 		}
 	}
 
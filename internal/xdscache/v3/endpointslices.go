@@ -0,0 +1,192 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3
+
+import (
+	"sort"
+
+	"github.com/projectcontour/contour/internal/ref"
+	v1 "k8s.io/api/core/v1"
+	discovery_v1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// UpdateEndpointSlice adds slice to the cache, or replaces it if it
+// is already cached, and rebuilds the Endpoints of the Service that
+// slice belongs to. Any ServiceClusters that are backed by that
+// Service become stale. Returns a boolean indicating whether any
+// ServiceClusters use slice or not.
+func (c *EndpointsCache) UpdateEndpointSlice(slice *discovery_v1.EndpointSlice) bool {
+	name, ok := endpointSliceServiceName(slice)
+	if !ok {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	slices := c.endpointSlices[name]
+	if slices == nil {
+		slices = map[string]*discovery_v1.EndpointSlice{}
+		c.endpointSlices[name] = slices
+	}
+	slices[slice.Name] = slice.DeepCopy()
+	c.endpoints[name] = endpointsFromSlices(name, slices)
+	c.endpointZones[name] = endpointZonesFromSlices(slices)
+
+	// If any service clusters include this endpoint, mark them
+	// all as stale.
+	if affected := c.services[name]; len(affected) > 0 {
+		c.stale = append(c.stale, affected...)
+		return true
+	}
+
+	return false
+}
+
+// DeleteEndpointSlice deletes slice from the cache and rebuilds the
+// Endpoints of the Service that slice belongs to. Any ServiceClusters
+// that are backed by that Service become stale. Returns a boolean
+// indicating whether any ServiceClusters use slice or not.
+func (c *EndpointsCache) DeleteEndpointSlice(slice *discovery_v1.EndpointSlice) bool {
+	name, ok := endpointSliceServiceName(slice)
+	if !ok {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	slices := c.endpointSlices[name]
+	delete(slices, slice.Name)
+	if len(slices) == 0 {
+		delete(c.endpointSlices, name)
+		delete(c.endpoints, name)
+		delete(c.endpointZones, name)
+	} else {
+		c.endpoints[name] = endpointsFromSlices(name, slices)
+		c.endpointZones[name] = endpointZonesFromSlices(slices)
+	}
+
+	// If any service clusters include this endpoint, mark them
+	// all as stale.
+	if affected := c.services[name]; len(affected) > 0 {
+		c.stale = append(c.stale, affected...)
+		return true
+	}
+
+	return false
+}
+
+// endpointSliceServiceName returns the name of the Service that
+// slice belongs to. Slices that are not managed for a Service have no
+// name.
+func endpointSliceServiceName(slice *discovery_v1.EndpointSlice) (types.NamespacedName, bool) {
+	service, ok := slice.Labels[discovery_v1.LabelServiceName]
+	if !ok || service == "" {
+		return types.NamespacedName{}, false
+	}
+
+	return types.NamespacedName{Namespace: slice.Namespace, Name: service}, true
+}
+
+// endpointsFromSlices converts the EndpointSlices of a Service to the
+// equivalent Endpoints, with one subset per slice, so that the rest
+// of the cache only has to deal with one representation.
+func endpointsFromSlices(name types.NamespacedName, slices map[string]*discovery_v1.EndpointSlice) *v1.Endpoints {
+	names := make([]string, 0, len(slices))
+	for n := range slices {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	ep := &v1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: name.Namespace,
+			Name:      name.Name,
+		},
+	}
+
+	for _, n := range names {
+		slice := slices[n]
+
+		// Envoy is given IP addresses, FQDN slices have nothing
+		// we can use.
+		if slice.AddressType != discovery_v1.AddressTypeIPv4 && slice.AddressType != discovery_v1.AddressTypeIPv6 {
+			continue
+		}
+
+		var subset v1.EndpointSubset
+		for _, p := range slice.Ports {
+			subset.Ports = append(subset.Ports, v1.EndpointPort{
+				Name:        ref.Val(p.Name, ""),
+				Port:        ref.Val(p.Port, 0),
+				Protocol:    ref.Val(p.Protocol, v1.ProtocolTCP),
+				AppProtocol: p.AppProtocol,
+			})
+		}
+
+		for _, e := range slice.Endpoints {
+			for _, ip := range e.Addresses {
+				addr := v1.EndpointAddress{
+					IP:        ip,
+					Hostname:  ref.Val(e.Hostname, ""),
+					NodeName:  e.NodeName,
+					TargetRef: e.TargetRef,
+				}
+
+				// A nil ready condition is to be interpreted as ready.
+				if ref.Val(e.Conditions.Ready, true) {
+					subset.Addresses = append(subset.Addresses, addr)
+				} else {
+					subset.NotReadyAddresses = append(subset.NotReadyAddresses, addr)
+				}
+			}
+		}
+
+		ep.Subsets = append(ep.Subsets, subset)
+	}
+
+	return ep
+}
+
+// endpointZonesFromSlices returns the zone of each endpoint address of
+// slices, indexed by address. Endpoints without a zone are left out.
+func endpointZonesFromSlices(slices map[string]*discovery_v1.EndpointSlice) map[string]string {
+	zones := map[string]string{}
+	for _, slice := range slices {
+		for _, e := range slice.Endpoints {
+			zone := endpointZone(e)
+			if zone == "" {
+				continue
+			}
+			for _, ip := range e.Addresses {
+				zones[ip] = zone
+			}
+		}
+	}
+	return zones
+}
+
+// endpointZone returns the zone that should be served by e. That is
+// the first zone of its topology hints, since those are the zones the
+// EndpointSlice controller allocated the endpoint to, or else the zone
+// it runs in.
+func endpointZone(e discovery_v1.Endpoint) string {
+	if e.Hints != nil && len(e.Hints.ForZones) > 0 {
+		return e.Hints.ForZones[0].Name
+	}
+	return ref.Val(e.Zone, "")
+}
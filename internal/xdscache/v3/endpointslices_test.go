@@ -0,0 +1,185 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3
+
+import (
+	"testing"
+
+	envoy_endpoint_v3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	"github.com/projectcontour/contour/internal/dag"
+	envoy_v3 "github.com/projectcontour/contour/internal/envoy/v3"
+	"github.com/projectcontour/contour/internal/fixture"
+	"github.com/projectcontour/contour/internal/protobuf"
+	"github.com/projectcontour/contour/internal/ref"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	v1 "k8s.io/api/core/v1"
+	discovery_v1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestEndpointsFromSlices(t *testing.T) {
+	tests := map[string]struct {
+		slices map[string]*discovery_v1.EndpointSlice
+		want   []v1.EndpointSubset
+	}{
+		"ready and not ready endpoints": {
+			slices: map[string]*discovery_v1.EndpointSlice{
+				"simple-abc": endpointSlice("default", "simple-abc", "simple", discovery_v1.AddressTypeIPv4,
+					[]discovery_v1.EndpointPort{{Name: ref.To("http"), Port: ref.To(int32(8080))}},
+					discovery_v1.Endpoint{
+						Addresses: []string{"192.168.183.24"},
+						NodeName:  ref.To("node-1"),
+					},
+					discovery_v1.Endpoint{
+						Addresses:  []string{"192.168.183.25"},
+						Conditions: discovery_v1.EndpointConditions{Ready: ref.To(false)},
+					},
+				),
+			},
+			want: []v1.EndpointSubset{{
+				Addresses: []v1.EndpointAddress{{
+					IP:       "192.168.183.24",
+					NodeName: ref.To("node-1"),
+				}},
+				NotReadyAddresses: []v1.EndpointAddress{{
+					IP: "192.168.183.25",
+				}},
+				Ports: []v1.EndpointPort{{Name: "http", Port: 8080, Protocol: v1.ProtocolTCP}},
+			}},
+		},
+		"slices are merged in name order": {
+			slices: map[string]*discovery_v1.EndpointSlice{
+				"simple-b": endpointSlice("default", "simple-b", "simple", discovery_v1.AddressTypeIPv6,
+					[]discovery_v1.EndpointPort{{Port: ref.To(int32(8080))}},
+					discovery_v1.Endpoint{Addresses: []string{"fe80::1"}},
+				),
+				"simple-a": endpointSlice("default", "simple-a", "simple", discovery_v1.AddressTypeIPv4,
+					[]discovery_v1.EndpointPort{{Port: ref.To(int32(8080))}},
+					discovery_v1.Endpoint{Addresses: []string{"192.168.183.24"}},
+				),
+			},
+			want: []v1.EndpointSubset{{
+				Addresses: []v1.EndpointAddress{{IP: "192.168.183.24"}},
+				Ports:     []v1.EndpointPort{{Port: 8080, Protocol: v1.ProtocolTCP}},
+			}, {
+				Addresses: []v1.EndpointAddress{{IP: "fe80::1"}},
+				Ports:     []v1.EndpointPort{{Port: 8080, Protocol: v1.ProtocolTCP}},
+			}},
+		},
+		"FQDN slices are skipped": {
+			slices: map[string]*discovery_v1.EndpointSlice{
+				"simple-abc": endpointSlice("default", "simple-abc", "simple", discovery_v1.AddressTypeFQDN,
+					[]discovery_v1.EndpointPort{{Port: ref.To(int32(8080))}},
+					discovery_v1.Endpoint{Addresses: []string{"example.com"}},
+				),
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := endpointsFromSlices(types.NamespacedName{Namespace: "default", Name: "simple"}, tc.slices)
+			assert.Equal(t, "default", got.Namespace)
+			assert.Equal(t, "simple", got.Name)
+			assert.Equal(t, tc.want, got.Subsets)
+		})
+	}
+}
+
+func TestEndpointsTranslatorEndpointSlices(t *testing.T) {
+	et := NewEndpointsTranslator(fixture.NewTestLogger(t))
+
+	require.NoError(t, et.cache.SetClusters([]*dag.ServiceCluster{
+		{
+			ClusterName: "default/simple",
+			Services: []dag.WeightedService{{
+				Weight:           1,
+				ServiceName:      "simple",
+				ServiceNamespace: "default",
+				ServicePort:      v1.ServicePort{},
+			}},
+		},
+	}))
+
+	ports := []discovery_v1.EndpointPort{{Port: ref.To(int32(8080))}}
+	s1 := endpointSlice("default", "simple-a", "simple", discovery_v1.AddressTypeIPv4, ports,
+		discovery_v1.Endpoint{Addresses: []string{"192.168.183.24"}},
+	)
+	s2 := endpointSlice("default", "simple-b", "simple", discovery_v1.AddressTypeIPv4, ports,
+		discovery_v1.Endpoint{Addresses: []string{"192.168.183.25"}},
+	)
+	et.OnAdd(s1)
+	et.OnAdd(s2)
+
+	// Endpoints from every slice of the service are used.
+	want := []proto.Message{
+		&envoy_endpoint_v3.ClusterLoadAssignment{
+			ClusterName: "default/simple",
+			Endpoints: envoy_v3.WeightedEndpoints(1,
+				envoy_v3.SocketAddress("192.168.183.24", 8080),
+				envoy_v3.SocketAddress("192.168.183.25", 8080),
+			),
+		},
+	}
+	protobuf.RequireEqual(t, want, et.Contents())
+
+	// Endpoints that are no longer ready are removed.
+	s3 := endpointSlice("default", "simple-b", "simple", discovery_v1.AddressTypeIPv4, ports,
+		discovery_v1.Endpoint{
+			Addresses:  []string{"192.168.183.25"},
+			Conditions: discovery_v1.EndpointConditions{Ready: ref.To(false)},
+		},
+	)
+	et.OnUpdate(s2, s3)
+
+	want = []proto.Message{
+		&envoy_endpoint_v3.ClusterLoadAssignment{
+			ClusterName: "default/simple",
+			Endpoints:   envoy_v3.WeightedEndpoints(1, envoy_v3.SocketAddress("192.168.183.24", 8080)),
+		},
+	}
+	protobuf.RequireEqual(t, want, et.Contents())
+
+	// Deleting every slice removes the endpoints.
+	et.OnDelete(s1)
+	et.OnDelete(s3)
+
+	want = []proto.Message{
+		&envoy_endpoint_v3.ClusterLoadAssignment{ClusterName: "default/simple"},
+	}
+	protobuf.RequireEqual(t, want, et.Contents())
+	assert.Empty(t, et.cache.endpointSlices)
+
+	// Slices that do not belong to a service are ignored.
+	assert.False(t, et.cache.UpdateEndpointSlice(endpointSlice("default", "orphan", "", discovery_v1.AddressTypeIPv4, ports)))
+}
+
+func endpointSlice(ns, name, service string, addressType discovery_v1.AddressType, ports []discovery_v1.EndpointPort, eps ...discovery_v1.Endpoint) *discovery_v1.EndpointSlice {
+	slice := &discovery_v1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      name,
+		},
+		AddressType: addressType,
+		Endpoints:   eps,
+		Ports:       ports,
+	}
+	if service != "" {
+		slice.Labels = map[string]string{discovery_v1.LabelServiceName: service}
+	}
+	return slice
+}
@@ -15,13 +15,17 @@ package v3
 
 import (
 	"fmt"
+	"math"
 	"sort"
+	"strconv"
 	"sync"
 
+	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoy_endpoint_v3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
 	resource "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
 	"github.com/projectcontour/contour/internal/contour"
 	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/envoy"
 	envoy_v3 "github.com/projectcontour/contour/internal/envoy/v3"
 	"github.com/projectcontour/contour/internal/k8s"
 	"github.com/projectcontour/contour/internal/protobuf"
@@ -29,6 +33,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"google.golang.org/protobuf/proto"
 	v1 "k8s.io/api/core/v1"
+	discovery_v1 "k8s.io/api/discovery/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/cache"
 )
@@ -108,6 +113,36 @@ type EndpointsCache struct {
 
 	// Cache of endpoints, indexed by name.
 	endpoints map[types.NamespacedName]*v1.Endpoints
+
+	// Cache of EndpointSlices, indexed by the name of their Service
+	// and then by their own name. The slices of a Service are merged
+	// into its entry in endpoints.
+	endpointSlices map[types.NamespacedName]map[string]*discovery_v1.EndpointSlice
+
+	// localityWeighted groups the endpoints of each service into
+	// a locality per zone, weighted by zoneWeights.
+	localityWeighted bool
+	zoneWeights      map[string]uint32
+
+	// topologyAware groups the endpoints of each service into a
+	// locality per zone for zone aware routing, and adds the local
+	// cluster of the Envoy pods, backed by localService.
+	topologyAware bool
+	localService  types.NamespacedName
+
+	// Zones of the cached nodes, indexed by node name.
+	nodeZones map[string]string
+
+	// Zones of the endpoints of the cached EndpointSlices, indexed by
+	// the name of their Service and then by address.
+	endpointZones map[types.NamespacedName]map[string]string
+
+	// endpointWeightLabel is the pod label holding the load
+	// balancing weight of the pod's endpoints.
+	endpointWeightLabel string
+
+	// Load balancing weights of the cached pods, indexed by name.
+	podWeights map[types.NamespacedName]uint32
 }
 
 // Recalculate regenerates all the ClusterLoadAssignments from the
@@ -133,11 +168,26 @@ func (c *EndpointsCache) Recalculate() map[string]*envoy_endpoint_v3.ClusterLoad
 			Policy:      nil,
 		}
 
+		// Priority levels must be contiguous, so clusters that fail over
+		// to other services keep a level even when it has no endpoints.
+		prioritized := false
+		for _, w := range cluster.Services {
+			prioritized = prioritized || w.Priority > 0
+		}
+
 		// Look up each service, and if we have endpoints for that service,
 		// attach them as a new LocalityEndpoints resource2.
 		for _, w := range cluster.Services {
 			n := types.NamespacedName{Namespace: w.ServiceNamespace, Name: w.ServiceName}
-			if lb := RecalculateEndpoints(w.ServicePort, w.HealthPort, c.endpoints[n]); lb != nil {
+			lb := RecalculateEndpoints(w.ServicePort, w.HealthPort, c.endpoints[n])
+			if lb != nil && c.endpointWeightLabel != "" {
+				c.setEndpointWeights(lb, c.endpoints[n])
+			}
+			if lb != nil && (c.localityWeighted || c.topologyAware) {
+				cla.Endpoints = append(cla.Endpoints, c.zoneLocalities(w, lb, c.endpoints[n])...)
+				continue
+			}
+			if lb != nil || prioritized {
 				// Append the new set of endpoints. Users are allowed to set the load
 				// balancing weight to 0, which we reflect to Envoy as nil in order to
 				// assign no load to that locality.
@@ -146,6 +196,7 @@ func (c *EndpointsCache) Recalculate() map[string]*envoy_endpoint_v3.ClusterLoad
 					&LocalityEndpoints{
 						LbEndpoints:         lb,
 						LoadBalancingWeight: protobuf.UInt32OrNil(w.Weight),
+						Priority:            w.Priority,
 					},
 				)
 			}
@@ -158,12 +209,109 @@ func (c *EndpointsCache) Recalculate() map[string]*envoy_endpoint_v3.ClusterLoad
 	return assignments
 }
 
+// zoneLocalities groups the load balancing endpoints of a service into
+// one locality per zone. With locality weighting, the weight of each
+// locality is the weight of the service scaled by the zone's weight,
+// which defaults to the number of endpoints in the zone. With topology
+// aware routing, the zone of an endpoint is taken from its EndpointSlice
+// when it has one, and Envoy does not use the locality weights.
+func (c *EndpointsCache) zoneLocalities(w dag.WeightedService, lb []*LoadBalancingEndpoint, ep *v1.Endpoints) []*LocalityEndpoints {
+	var sliceZones map[string]string
+	if c.topologyAware {
+		sliceZones = c.endpointZones[k8s.NamespacedNameOf(ep)]
+	}
+
+	addressZones := map[string]string{}
+	for _, s := range ep.Subsets {
+		for _, a := range s.Addresses {
+			if zone := sliceZones[a.IP]; zone != "" {
+				addressZones[a.IP] = zone
+			} else if a.NodeName != nil {
+				addressZones[a.IP] = c.nodeZones[*a.NodeName]
+			}
+		}
+	}
+
+	zones := map[string][]*LoadBalancingEndpoint{}
+	for _, e := range lb {
+		zone := addressZones[e.GetEndpoint().GetAddress().GetSocketAddress().GetAddress()]
+		zones[zone] = append(zones[zone], e)
+	}
+
+	names := make([]string, 0, len(zones))
+	for zone := range zones {
+		names = append(names, zone)
+	}
+	sort.Strings(names)
+
+	localities := make([]*LocalityEndpoints, 0, len(names))
+	for _, zone := range names {
+		zoneWeight := uint32(1)
+		if c.localityWeighted {
+			weight, ok := c.zoneWeights[zone]
+			if !ok {
+				weight = uint32(len(zones[zone]))
+			}
+			zoneWeight = weight
+		}
+
+		// Both weights can be large enough for their product
+		// to overflow, so it is capped at the largest weight.
+		weight := uint64(w.Weight) * uint64(zoneWeight)
+		if weight > math.MaxUint32 {
+			weight = math.MaxUint32
+		}
+
+		localities = append(localities, &LocalityEndpoints{
+			Locality:            &envoy_core_v3.Locality{Zone: zone},
+			LbEndpoints:         zones[zone],
+			LoadBalancingWeight: protobuf.UInt32OrNil(uint32(weight)),
+			Priority:            w.Priority,
+		})
+	}
+
+	return localities
+}
+
+// setEndpointWeights sets the load balancing weight of each endpoint
+// backed by a pod with a weight. Endpoints of other pods keep Envoy's
+// default weight of 1.
+func (c *EndpointsCache) setEndpointWeights(lb []*LoadBalancingEndpoint, ep *v1.Endpoints) {
+	addressWeights := map[string]uint32{}
+	for _, s := range ep.Subsets {
+		for _, a := range s.Addresses {
+			if a.TargetRef == nil || a.TargetRef.Kind != "Pod" {
+				continue
+			}
+			pod := types.NamespacedName{Namespace: ep.Namespace, Name: a.TargetRef.Name}
+			if weight, ok := c.podWeights[pod]; ok {
+				addressWeights[a.IP] = weight
+			}
+		}
+	}
+
+	for _, e := range lb {
+		if weight, ok := addressWeights[e.GetEndpoint().GetAddress().GetSocketAddress().GetAddress()]; ok {
+			e.LoadBalancingWeight = protobuf.UInt32OrNil(weight)
+		}
+	}
+}
+
 // SetClusters replaces the cache of ServiceCluster resources. All
 // the added clusters will be marked stale.
 func (c *EndpointsCache) SetClusters(clusters []*dag.ServiceCluster) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	// The local cluster is not in the DAG, so it is added to every
+	// set of clusters. Envoy only uses it to find how its own pods
+	// are spread across zones, so every port of the Service will do.
+	if c.topologyAware {
+		local := &dag.ServiceCluster{ClusterName: envoy.LocalClusterName}
+		local.AddService(c.localService, v1.ServicePort{})
+		clusters = append(clusters[:len(clusters):len(clusters)], local)
+	}
+
 	// Keep a local index to start with so that errors don't cause
 	// partial failure.
 	serviceIndex := map[types.NamespacedName][]*dag.ServiceCluster{}
@@ -220,6 +368,145 @@ func (c *EndpointsCache) UpdateEndpoint(ep *v1.Endpoints) bool {
 	return false
 }
 
+// UpdateNode records the zone of node in the cache. If locality
+// weighting is enabled and the zone of node changed, all the
+// ServiceClusters become stale, since any of them may have endpoints
+// on node. Returns a boolean indicating whether any ServiceClusters
+// became stale.
+func (c *EndpointsCache) UpdateNode(node *v1.Node) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	zone := node.Labels[v1.LabelTopologyZone]
+	if current, ok := c.nodeZones[node.Name]; ok && current == zone {
+		return false
+	}
+	c.nodeZones[node.Name] = zone
+
+	return c.markAllStale()
+}
+
+// DeleteNode removes node from the cache. If locality weighting is
+// enabled, all the ServiceClusters become stale. Returns a boolean
+// indicating whether any ServiceClusters became stale.
+func (c *EndpointsCache) DeleteNode(node *v1.Node) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.nodeZones[node.Name]; !ok {
+		return false
+	}
+	delete(c.nodeZones, node.Name)
+
+	return c.markAllStale()
+}
+
+// UpdatePod records the load balancing weight of pod in the cache.
+// If the weight of pod changed, the ServiceClusters with endpoints
+// on pod become stale. Returns a boolean indicating whether any
+// ServiceClusters became stale, and an error if the weight label
+// of pod is not a valid weight, in which case pod is treated as
+// having no weight.
+func (c *EndpointsCache) UpdatePod(pod *v1.Pod) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	name := k8s.NamespacedNameOf(pod)
+	current, cached := c.podWeights[name]
+
+	weight, err := parseEndpointWeight(pod.Labels, c.endpointWeightLabel)
+	if err != nil || weight == 0 {
+		if !cached {
+			return false, err
+		}
+		delete(c.podWeights, name)
+		return c.markPodStale(name), err
+	}
+
+	if cached && current == weight {
+		return false, nil
+	}
+	c.podWeights[name] = weight
+
+	return c.markPodStale(name), nil
+}
+
+// DeletePod removes pod from the cache. The ServiceClusters with
+// endpoints on pod become stale if pod had a weight. Returns a
+// boolean indicating whether any ServiceClusters became stale.
+func (c *EndpointsCache) DeletePod(pod *v1.Pod) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	name := k8s.NamespacedNameOf(pod)
+	if _, ok := c.podWeights[name]; !ok {
+		return false
+	}
+	delete(c.podWeights, name)
+
+	return c.markPodStale(name)
+}
+
+// markPodStale marks the ServiceClusters whose endpoints include
+// pod stale. The caller must hold c.mu.
+func (c *EndpointsCache) markPodStale(pod types.NamespacedName) bool {
+	stale := false
+	for name, ep := range c.endpoints {
+		if name.Namespace != pod.Namespace || len(c.services[name]) == 0 {
+			continue
+		}
+		if endpointsTargetPod(ep, pod.Name) {
+			c.stale = append(c.stale, c.services[name]...)
+			stale = true
+		}
+	}
+	return stale
+}
+
+// endpointsTargetPod returns true if any ready address of ep
+// targets the named pod.
+func endpointsTargetPod(ep *v1.Endpoints, pod string) bool {
+	for _, s := range ep.Subsets {
+		for _, a := range s.Addresses {
+			if a.TargetRef != nil && a.TargetRef.Kind == "Pod" && a.TargetRef.Name == pod {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseEndpointWeight returns the load balancing weight held by
+// the label of labels, or 0 if the label is not set. The weight
+// must be an integer between 1 and 65535, which keeps the sum of
+// the weights of a locality within Envoy's limit.
+func parseEndpointWeight(labels map[string]string, label string) (uint32, error) {
+	value, ok := labels[label]
+	if !ok {
+		return 0, nil
+	}
+
+	weight, err := strconv.ParseUint(value, 10, 16)
+	if err != nil || weight == 0 {
+		return 0, fmt.Errorf("invalid endpoint weight %q in label %q: must be an integer between 1 and 65535", value, label)
+	}
+	return uint32(weight), nil
+}
+
+// markAllStale marks every ServiceCluster stale when locality
+// weighting or topology aware routing is enabled. The caller must
+// hold c.mu.
+func (c *EndpointsCache) markAllStale() bool {
+	if !c.localityWeighted && !c.topologyAware {
+		return false
+	}
+
+	for _, affected := range c.services {
+		c.stale = append(c.stale, affected...)
+	}
+	return len(c.services) > 0
+}
+
 // DeleteEndpoint deletes ep from the cache. Any ServiceClusters
 // that are backed by a Service that ep belongs become stale. Returns
 // a boolean indicating whether any ServiceClusters use ep or not.
@@ -247,13 +534,52 @@ func NewEndpointsTranslator(log logrus.FieldLogger) *EndpointsTranslator {
 		FieldLogger: log,
 		entries:     map[string]*envoy_endpoint_v3.ClusterLoadAssignment{},
 		cache: EndpointsCache{
-			stale:     nil,
-			services:  map[types.NamespacedName][]*dag.ServiceCluster{},
-			endpoints: map[types.NamespacedName]*v1.Endpoints{},
+			stale:          nil,
+			services:       map[types.NamespacedName][]*dag.ServiceCluster{},
+			endpoints:      map[types.NamespacedName]*v1.Endpoints{},
+			endpointSlices: map[types.NamespacedName]map[string]*discovery_v1.EndpointSlice{},
+			nodeZones:      map[string]string{},
+			endpointZones:  map[types.NamespacedName]map[string]string{},
+			podWeights:     map[types.NamespacedName]uint32{},
 		},
 	}
 }
 
+// EnableLocalityWeighting groups the endpoints of each service into a
+// locality per zone of the nodes they run on. zoneWeights sets the
+// load balancing weight of a zone; zones without a weight are weighted
+// by their number of endpoints.
+func (e *EndpointsTranslator) EnableLocalityWeighting(zoneWeights map[string]uint32) {
+	e.cache.mu.Lock()
+	defer e.cache.mu.Unlock()
+
+	e.cache.localityWeighted = true
+	e.cache.zoneWeights = zoneWeights
+}
+
+// EnableTopologyAwareRouting groups the endpoints of each service into
+// a locality per zone, taken from their EndpointSlice topology hints,
+// so that Envoy can route requests zone aware. The endpoints of the
+// Envoy pods' localService are served as Envoy's local cluster.
+func (e *EndpointsTranslator) EnableTopologyAwareRouting(localService types.NamespacedName) {
+	e.cache.mu.Lock()
+	defer e.cache.mu.Unlock()
+
+	e.cache.topologyAware = true
+	e.cache.localService = localService
+}
+
+// EnableEndpointWeights sets the load balancing weight of each endpoint
+// from the label of its pod named by label. Envoy then balances
+// requests across the endpoints of a cluster in proportion to their
+// weights.
+func (e *EndpointsTranslator) EnableEndpointWeights(label string) {
+	e.cache.mu.Lock()
+	defer e.cache.mu.Unlock()
+
+	e.cache.endpointWeightLabel = label
+}
+
 // A EndpointsTranslator translates Kubernetes Endpoints objects into Envoy
 // ClusterLoadAssignment resources.
 type EndpointsTranslator struct {
@@ -361,6 +687,43 @@ func (e *EndpointsTranslator) OnAdd(obj interface{}) {
 		if e.Observer != nil {
 			e.Observer.Refresh()
 		}
+	case *discovery_v1.EndpointSlice:
+		if !e.cache.UpdateEndpointSlice(obj) {
+			return
+		}
+
+		e.WithField("endpointslice", k8s.NamespacedNameOf(obj)).Debug("EndpointSlice is in use by a ServiceCluster, recalculating ClusterLoadAssignments")
+		e.Merge(e.cache.Recalculate())
+		e.Notify()
+		if e.Observer != nil {
+			e.Observer.Refresh()
+		}
+	case *v1.Node:
+		if !e.cache.UpdateNode(obj) {
+			return
+		}
+
+		e.WithField("node", obj.Name).Debug("Node zone changed, recalculating ClusterLoadAssignments")
+		e.Merge(e.cache.Recalculate())
+		e.Notify()
+		if e.Observer != nil {
+			e.Observer.Refresh()
+		}
+	case *v1.Pod:
+		stale, err := e.cache.UpdatePod(obj)
+		if err != nil {
+			e.WithError(err).WithField("pod", k8s.NamespacedNameOf(obj)).Warn("ignoring endpoint weight of pod")
+		}
+		if !stale {
+			return
+		}
+
+		e.WithField("pod", k8s.NamespacedNameOf(obj)).Debug("Pod endpoint weight changed, recalculating ClusterLoadAssignments")
+		e.Merge(e.cache.Recalculate())
+		e.Notify()
+		if e.Observer != nil {
+			e.Observer.Refresh()
+		}
 	default:
 		e.Errorf("OnAdd unexpected type %T: %#v", obj, obj)
 	}
@@ -398,6 +761,26 @@ func (e *EndpointsTranslator) OnUpdate(oldObj, newObj interface{}) {
 		if e.Observer != nil {
 			e.Observer.Refresh()
 		}
+	case *discovery_v1.EndpointSlice:
+		oldObj, ok := oldObj.(*discovery_v1.EndpointSlice)
+		if !ok {
+			e.Errorf("OnUpdate endpointslice %#v received invalid oldObj %T; %#v", newObj, oldObj, oldObj)
+			return
+		}
+
+		if oldObj == newObj {
+			return
+		}
+
+		// As for Endpoints, ignore updates between two empty
+		// slices to avoid sending a noop notification to watchers.
+		if len(oldObj.Endpoints) == 0 && len(newObj.Endpoints) == 0 {
+			return
+		}
+
+		e.OnAdd(newObj)
+	case *v1.Node, *v1.Pod:
+		e.OnAdd(newObj)
 	default:
 		e.Errorf("OnUpdate unexpected type %T: %#v", newObj, newObj)
 	}
@@ -416,6 +799,39 @@ func (e *EndpointsTranslator) OnDelete(obj interface{}) {
 		if e.Observer != nil {
 			e.Observer.Refresh()
 		}
+	case *discovery_v1.EndpointSlice:
+		if !e.cache.DeleteEndpointSlice(obj) {
+			return
+		}
+
+		e.WithField("endpointslice", k8s.NamespacedNameOf(obj)).Debug("EndpointSlice was in use by a ServiceCluster, recalculating ClusterLoadAssignments")
+		e.Merge(e.cache.Recalculate())
+		e.Notify()
+		if e.Observer != nil {
+			e.Observer.Refresh()
+		}
+	case *v1.Node:
+		if !e.cache.DeleteNode(obj) {
+			return
+		}
+
+		e.WithField("node", obj.Name).Debug("Node was removed, recalculating ClusterLoadAssignments")
+		e.Merge(e.cache.Recalculate())
+		e.Notify()
+		if e.Observer != nil {
+			e.Observer.Refresh()
+		}
+	case *v1.Pod:
+		if !e.cache.DeletePod(obj) {
+			return
+		}
+
+		e.WithField("pod", k8s.NamespacedNameOf(obj)).Debug("Weighted pod was removed, recalculating ClusterLoadAssignments")
+		e.Merge(e.cache.Recalculate())
+		e.Notify()
+		if e.Observer != nil {
+			e.Observer.Refresh()
+		}
 	case cache.DeletedFinalStateUnknown:
 		e.OnDelete(obj.Obj) // recurse into ourselves with the tombstoned value
 	default:
@@ -14,6 +14,7 @@
 package v3
 
 import (
+	"math"
 	"testing"
 
 	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
@@ -22,11 +23,15 @@ import (
 	envoy_v3 "github.com/projectcontour/contour/internal/envoy/v3"
 	"github.com/projectcontour/contour/internal/fixture"
 	"github.com/projectcontour/contour/internal/protobuf"
+	"github.com/projectcontour/contour/internal/ref"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 	v1 "k8s.io/api/core/v1"
+	discovery_v1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 func TestEndpointsTranslatorContents(t *testing.T) {
@@ -972,6 +977,436 @@ func TestEndpointsTranslatorDefaultWeightedService(t *testing.T) {
 	protobuf.ExpectEqual(t, want, et.Contents())
 }
 
+// Test that the services of a cluster are assigned their priority
+// levels, and that a level without endpoints is kept so that the
+// levels stay contiguous.
+func TestEndpointsTranslatorPriorityService(t *testing.T) {
+	et := NewEndpointsTranslator(fixture.NewTestLogger(t))
+	clusters := []*dag.ServiceCluster{
+		{
+			ClusterName: "default/primary,default/secondary/1",
+			Services: []dag.WeightedService{
+				{
+					Weight:           1,
+					ServiceName:      "primary",
+					ServiceNamespace: "default",
+					ServicePort:      v1.ServicePort{},
+				},
+				{
+					Weight:           1,
+					ServiceName:      "secondary",
+					ServiceNamespace: "default",
+					ServicePort:      v1.ServicePort{},
+					Priority:         1,
+				},
+			},
+		},
+	}
+
+	require.NoError(t, et.cache.SetClusters(clusters))
+
+	et.OnAdd(endpoints("default", "secondary", v1.EndpointSubset{
+		Addresses: addresses("192.168.183.25"),
+		Ports:     ports(port("", 8080)),
+	}))
+
+	primary := envoy_v3.WeightedEndpoints(1, envoy_v3.SocketAddress("192.168.183.24", 8080))
+	secondary := envoy_v3.WeightedEndpoints(1, envoy_v3.SocketAddress("192.168.183.25", 8080))
+	secondary[0].Priority = 1
+
+	want := []proto.Message{
+		&envoy_endpoint_v3.ClusterLoadAssignment{
+			ClusterName: "default/primary,default/secondary/1",
+			Endpoints: []*envoy_endpoint_v3.LocalityLbEndpoints{
+				{
+					LoadBalancingWeight: wrapperspb.UInt32(1),
+				},
+				secondary[0],
+			},
+		},
+	}
+
+	protobuf.ExpectEqual(t, want, et.Contents())
+
+	et.OnAdd(endpoints("default", "primary", v1.EndpointSubset{
+		Addresses: addresses("192.168.183.24"),
+		Ports:     ports(port("", 8080)),
+	}))
+
+	want = []proto.Message{
+		&envoy_endpoint_v3.ClusterLoadAssignment{
+			ClusterName: "default/primary,default/secondary/1",
+			Endpoints: []*envoy_endpoint_v3.LocalityLbEndpoints{
+				primary[0], secondary[0],
+			},
+		},
+	}
+
+	protobuf.ExpectEqual(t, want, et.Contents())
+}
+
+func TestEndpointsTranslatorLocalityWeighting(t *testing.T) {
+	et := NewEndpointsTranslator(fixture.NewTestLogger(t))
+	et.EnableLocalityWeighting(map[string]uint32{"zone-a": 3})
+
+	clusters := []*dag.ServiceCluster{
+		{
+			ClusterName: "default/httpbin",
+			Services: []dag.WeightedService{
+				{
+					Weight:           1,
+					ServiceName:      "httpbin",
+					ServiceNamespace: "default",
+					ServicePort:      v1.ServicePort{},
+				},
+			},
+		},
+	}
+	require.NoError(t, et.cache.SetClusters(clusters))
+
+	node := func(name, zone string) *v1.Node {
+		return &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{v1.LabelTopologyZone: zone},
+			},
+		}
+	}
+	et.OnAdd(node("node-1", "zone-a"))
+	et.OnAdd(node("node-2", "zone-b"))
+
+	et.OnAdd(endpoints("default", "httpbin", v1.EndpointSubset{
+		Addresses: []v1.EndpointAddress{
+			{IP: "192.168.183.24", NodeName: ref.To("node-1")},
+			{IP: "192.168.183.25", NodeName: ref.To("node-2")},
+			{IP: "192.168.183.26", NodeName: ref.To("node-2")},
+		},
+		Ports: ports(port("", 8080)),
+	}))
+
+	lbEndpoint := func(addr string) *envoy_endpoint_v3.LbEndpoint {
+		return envoy_v3.LBEndpoint(envoy_v3.SocketAddress(addr, 8080))
+	}
+
+	// zone-a uses its configured weight, zone-b is weighted
+	// by its number of endpoints.
+	want := []proto.Message{
+		&envoy_endpoint_v3.ClusterLoadAssignment{
+			ClusterName: "default/httpbin",
+			Endpoints: []*envoy_endpoint_v3.LocalityLbEndpoints{
+				{
+					Locality:            &envoy_core_v3.Locality{Zone: "zone-a"},
+					LbEndpoints:         []*envoy_endpoint_v3.LbEndpoint{lbEndpoint("192.168.183.24")},
+					LoadBalancingWeight: wrapperspb.UInt32(3),
+				},
+				{
+					Locality:            &envoy_core_v3.Locality{Zone: "zone-b"},
+					LbEndpoints:         []*envoy_endpoint_v3.LbEndpoint{lbEndpoint("192.168.183.25"), lbEndpoint("192.168.183.26")},
+					LoadBalancingWeight: wrapperspb.UInt32(2),
+				},
+			},
+		},
+	}
+	protobuf.ExpectEqual(t, want, et.Contents())
+
+	// Moving a node to another zone regroups its endpoints.
+	et.OnUpdate(node("node-1", "zone-a"), node("node-1", "zone-b"))
+
+	want = []proto.Message{
+		&envoy_endpoint_v3.ClusterLoadAssignment{
+			ClusterName: "default/httpbin",
+			Endpoints: []*envoy_endpoint_v3.LocalityLbEndpoints{
+				{
+					Locality: &envoy_core_v3.Locality{Zone: "zone-b"},
+					LbEndpoints: []*envoy_endpoint_v3.LbEndpoint{
+						lbEndpoint("192.168.183.24"), lbEndpoint("192.168.183.25"), lbEndpoint("192.168.183.26"),
+					},
+					LoadBalancingWeight: wrapperspb.UInt32(3),
+				},
+			},
+		},
+	}
+	protobuf.ExpectEqual(t, want, et.Contents())
+}
+
+func TestEndpointsTranslatorLocalityWeightOverflow(t *testing.T) {
+	et := NewEndpointsTranslator(fixture.NewTestLogger(t))
+	et.EnableLocalityWeighting(map[string]uint32{"zone-a": 1 << 20})
+
+	clusters := []*dag.ServiceCluster{
+		{
+			ClusterName: "default/httpbin",
+			Services: []dag.WeightedService{
+				{
+					Weight:           1 << 20,
+					ServiceName:      "httpbin",
+					ServiceNamespace: "default",
+					ServicePort:      v1.ServicePort{},
+				},
+			},
+		},
+	}
+	require.NoError(t, et.cache.SetClusters(clusters))
+
+	et.OnAdd(&v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "node-1",
+			Labels: map[string]string{v1.LabelTopologyZone: "zone-a"},
+		},
+	})
+	et.OnAdd(endpoints("default", "httpbin", v1.EndpointSubset{
+		Addresses: []v1.EndpointAddress{
+			{IP: "192.168.183.24", NodeName: ref.To("node-1")},
+		},
+		Ports: ports(port("", 8080)),
+	}))
+
+	// The product of the service and zone weights does not
+	// fit in a uint32, so it is capped instead of wrapping to 0.
+	want := []proto.Message{
+		&envoy_endpoint_v3.ClusterLoadAssignment{
+			ClusterName: "default/httpbin",
+			Endpoints: []*envoy_endpoint_v3.LocalityLbEndpoints{
+				{
+					Locality:            &envoy_core_v3.Locality{Zone: "zone-a"},
+					LbEndpoints:         []*envoy_endpoint_v3.LbEndpoint{envoy_v3.LBEndpoint(envoy_v3.SocketAddress("192.168.183.24", 8080))},
+					LoadBalancingWeight: wrapperspb.UInt32(math.MaxUint32),
+				},
+			},
+		},
+	}
+	protobuf.ExpectEqual(t, want, et.Contents())
+}
+
+func TestEndpointsTranslatorTopologyAwareRouting(t *testing.T) {
+	et := NewEndpointsTranslator(fixture.NewTestLogger(t))
+	et.EnableTopologyAwareRouting(types.NamespacedName{Namespace: "projectcontour", Name: "envoy"})
+
+	clusters := []*dag.ServiceCluster{
+		{
+			ClusterName: "default/httpbin",
+			Services: []dag.WeightedService{
+				{
+					Weight:           1,
+					ServiceName:      "httpbin",
+					ServiceNamespace: "default",
+					ServicePort:      v1.ServicePort{},
+				},
+			},
+		},
+	}
+	require.NoError(t, et.cache.SetClusters(clusters))
+
+	et.OnAdd(&v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "node-3",
+			Labels: map[string]string{v1.LabelTopologyZone: "zone-c"},
+		},
+	})
+
+	ports := []discovery_v1.EndpointPort{{Port: ref.To(int32(8080))}}
+	et.OnAdd(endpointSlice("default", "httpbin-abc", "httpbin", discovery_v1.AddressTypeIPv4, ports,
+		discovery_v1.Endpoint{
+			Addresses: []string{"192.168.183.24"},
+			Zone:      ref.To("zone-a"),
+		},
+		discovery_v1.Endpoint{
+			Addresses: []string{"192.168.183.25"},
+			Zone:      ref.To("zone-a"),
+			Hints: &discovery_v1.EndpointHints{
+				ForZones: []discovery_v1.ForZone{{Name: "zone-b"}},
+			},
+		},
+		discovery_v1.Endpoint{
+			Addresses: []string{"192.168.183.26"},
+			NodeName:  ref.To("node-3"),
+		},
+	))
+	et.OnAdd(endpointSlice("projectcontour", "envoy-abc", "envoy", discovery_v1.AddressTypeIPv4, ports,
+		discovery_v1.Endpoint{
+			Addresses: []string{"10.0.0.10"},
+			Zone:      ref.To("zone-a"),
+		},
+		discovery_v1.Endpoint{
+			Addresses: []string{"10.0.0.11"},
+			Zone:      ref.To("zone-b"),
+		},
+	))
+
+	lbEndpoint := func(addr string) *envoy_endpoint_v3.LbEndpoint {
+		return envoy_v3.LBEndpoint(envoy_v3.SocketAddress(addr, 8080))
+	}
+	locality := func(zone string, addrs ...string) *envoy_endpoint_v3.LocalityLbEndpoints {
+		l := &envoy_endpoint_v3.LocalityLbEndpoints{
+			Locality:            &envoy_core_v3.Locality{Zone: zone},
+			LoadBalancingWeight: wrapperspb.UInt32(1),
+		}
+		for _, addr := range addrs {
+			l.LbEndpoints = append(l.LbEndpoints, lbEndpoint(addr))
+		}
+		return l
+	}
+
+	// Endpoints are grouped by their hinted zone, then by their own
+	// zone, then by the zone of their node. The endpoints of the
+	// Envoy service make up the local cluster.
+	want := []proto.Message{
+		&envoy_endpoint_v3.ClusterLoadAssignment{
+			ClusterName: "default/httpbin",
+			Endpoints: []*envoy_endpoint_v3.LocalityLbEndpoints{
+				locality("zone-a", "192.168.183.24"),
+				locality("zone-b", "192.168.183.25"),
+				locality("zone-c", "192.168.183.26"),
+			},
+		},
+		&envoy_endpoint_v3.ClusterLoadAssignment{
+			ClusterName: "envoy-local",
+			Endpoints: []*envoy_endpoint_v3.LocalityLbEndpoints{
+				locality("zone-a", "10.0.0.10"),
+				locality("zone-b", "10.0.0.11"),
+			},
+		},
+	}
+	protobuf.ExpectEqual(t, want, et.Contents())
+}
+
+func TestEndpointsTranslatorEndpointWeights(t *testing.T) {
+	et := NewEndpointsTranslator(fixture.NewTestLogger(t))
+	et.EnableEndpointWeights("projectcontour.io/weight")
+
+	clusters := []*dag.ServiceCluster{
+		{
+			ClusterName: "default/httpbin",
+			Services: []dag.WeightedService{
+				{
+					Weight:           1,
+					ServiceName:      "httpbin",
+					ServiceNamespace: "default",
+					ServicePort:      v1.ServicePort{},
+				},
+			},
+		},
+	}
+	require.NoError(t, et.cache.SetClusters(clusters))
+
+	pod := func(name, weight string) *v1.Pod {
+		p := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+		}
+		if weight != "" {
+			p.Labels = map[string]string{"projectcontour.io/weight": weight}
+		}
+		return p
+	}
+	target := func(name string) *v1.ObjectReference {
+		return &v1.ObjectReference{Kind: "Pod", Namespace: "default", Name: name}
+	}
+
+	et.OnAdd(pod("large", "4"))
+	et.OnAdd(pod("small", ""))
+	et.OnAdd(pod("invalid", "big"))
+
+	et.OnAdd(endpoints("default", "httpbin", v1.EndpointSubset{
+		Addresses: []v1.EndpointAddress{
+			{IP: "192.168.183.24", TargetRef: target("large")},
+			{IP: "192.168.183.25", TargetRef: target("small")},
+			{IP: "192.168.183.26", TargetRef: target("invalid")},
+		},
+		Ports: ports(port("", 8080)),
+	}))
+
+	lbEndpoint := func(addr string, weight uint32) *envoy_endpoint_v3.LbEndpoint {
+		e := envoy_v3.LBEndpoint(envoy_v3.SocketAddress(addr, 8080))
+		if weight > 0 {
+			e.LoadBalancingWeight = wrapperspb.UInt32(weight)
+		}
+		return e
+	}
+
+	// Only the pod with a valid weight label sets an endpoint weight.
+	want := []proto.Message{
+		&envoy_endpoint_v3.ClusterLoadAssignment{
+			ClusterName: "default/httpbin",
+			Endpoints: []*envoy_endpoint_v3.LocalityLbEndpoints{{
+				LbEndpoints: []*envoy_endpoint_v3.LbEndpoint{
+					lbEndpoint("192.168.183.24", 4),
+					lbEndpoint("192.168.183.25", 0),
+					lbEndpoint("192.168.183.26", 0),
+				},
+				LoadBalancingWeight: wrapperspb.UInt32(1),
+			}},
+		},
+	}
+	protobuf.ExpectEqual(t, want, et.Contents())
+
+	// Relabelling a pod updates the weight of its endpoint.
+	et.OnUpdate(pod("small", ""), pod("small", "2"))
+
+	want = []proto.Message{
+		&envoy_endpoint_v3.ClusterLoadAssignment{
+			ClusterName: "default/httpbin",
+			Endpoints: []*envoy_endpoint_v3.LocalityLbEndpoints{{
+				LbEndpoints: []*envoy_endpoint_v3.LbEndpoint{
+					lbEndpoint("192.168.183.24", 4),
+					lbEndpoint("192.168.183.25", 2),
+					lbEndpoint("192.168.183.26", 0),
+				},
+				LoadBalancingWeight: wrapperspb.UInt32(1),
+			}},
+		},
+	}
+	protobuf.ExpectEqual(t, want, et.Contents())
+
+	// Removing a pod drops the weight of its endpoint.
+	et.OnDelete(pod("large", "4"))
+
+	want = []proto.Message{
+		&envoy_endpoint_v3.ClusterLoadAssignment{
+			ClusterName: "default/httpbin",
+			Endpoints: []*envoy_endpoint_v3.LocalityLbEndpoints{{
+				LbEndpoints: []*envoy_endpoint_v3.LbEndpoint{
+					lbEndpoint("192.168.183.24", 0),
+					lbEndpoint("192.168.183.25", 2),
+					lbEndpoint("192.168.183.26", 0),
+				},
+				LoadBalancingWeight: wrapperspb.UInt32(1),
+			}},
+		},
+	}
+	protobuf.ExpectEqual(t, want, et.Contents())
+}
+
+func TestParseEndpointWeight(t *testing.T) {
+	tests := map[string]struct {
+		labels  map[string]string
+		want    uint32
+		wantErr bool
+	}{
+		"no label":    {labels: map[string]string{"app": "httpbin"}, want: 0},
+		"valid":       {labels: map[string]string{"weight": "10"}, want: 10},
+		"max":         {labels: map[string]string{"weight": "65535"}, want: 65535},
+		"zero":        {labels: map[string]string{"weight": "0"}, wantErr: true},
+		"negative":    {labels: map[string]string{"weight": "-1"}, wantErr: true},
+		"too large":   {labels: map[string]string{"weight": "65536"}, wantErr: true},
+		"not integer": {labels: map[string]string{"weight": "1.5"}, wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseEndpointWeight(tc.labels, "weight")
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
 func TestEqual(t *testing.T) {
 	tests := map[string]struct {
 		a, b map[string]*envoy_endpoint_v3.ClusterLoadAssignment
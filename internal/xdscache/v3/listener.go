@@ -16,6 +16,7 @@ package v3
 import (
 	"sort"
 	"sync"
+	"time"
 
 	envoy_accesslog_v3 "github.com/envoyproxy/go-control-plane/envoy/config/accesslog/v3"
 	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
@@ -109,6 +110,10 @@ type ListenerConfig struct {
 	// MergeSlashes toggles Envoy's non-standard merge_slashes path transformation option for all listeners.
 	MergeSlashes bool
 
+	// SetRequestIDInResponse returns the request ID in the X-Request-Id
+	// response header on all listeners.
+	SetRequestIDInResponse bool
+
 	// ServerHeaderTransformation defines the action to be applied to the Server header on the response path.
 	ServerHeaderTransformation contour_api_v1alpha1.ServerHeaderTransformationType
 
@@ -121,6 +126,26 @@ type ListenerConfig struct {
 	// If no configuration is specified, Envoy will not attempt to balance active connections between worker threads
 	// If specified, the listener will use the exact connection balancer.
 	ConnectionBalancer string
+
+	// TCPKeepalive configures the TCP keepalive of downstream
+	// connections. Unset values default to Contour's TCP
+	// keepalive settings.
+	TCPKeepalive envoy_v3.TCPKeepalive
+
+	// HTTP2KeepaliveInterval is how often a PING frame is sent on
+	// downstream HTTP/2 connections.
+	// If not set, no PING frames are sent.
+	HTTP2KeepaliveInterval time.Duration
+
+	// HTTP2KeepaliveTimeout is how long to wait for the
+	// acknowledgement of a PING frame before closing the connection.
+	HTTP2KeepaliveTimeout time.Duration
+
+	// HTTP2MaxConcurrentStreams is the maximum number of concurrent
+	// streams on a downstream HTTP/2 connection.
+	// If not set, Envoy's default is used.
+	HTTP2MaxConcurrentStreams uint32
+
 	// RateLimitConfig optionally configures the global Rate Limit Service to be
 	// used.
 	RateLimitConfig *RateLimitConfig
@@ -327,12 +352,18 @@ func (c *ListenerCache) OnChange(root *dag.DAG) {
 				DelayedCloseTimeout(cfg.Timeouts.DelayedClose).
 				MaxConnectionDuration(cfg.Timeouts.MaxConnectionDuration).
 				ConnectionShutdownGracePeriod(cfg.Timeouts.ConnectionShutdownGracePeriod).
+				TimeoutResponse(cfg.Timeouts.TimeoutResponse).
+				HTTP2Keepalive(cfg.HTTP2KeepaliveInterval, cfg.HTTP2KeepaliveTimeout).
+				HTTP2MaxConcurrentStreams(cfg.HTTP2MaxConcurrentStreams).
 				AllowChunkedLength(cfg.AllowChunkedLength).
 				MergeSlashes(cfg.MergeSlashes).
+				SetRequestIDInResponse(cfg.SetRequestIDInResponse).
 				ServerHeaderTransformation(cfg.ServerHeaderTransformation).
 				NumTrustedHops(cfg.XffNumTrustedHops).
+				AddFilter(envoy_v3.FilterRBAC(hasSourceFilter(listener.VirtualHosts...))).
 				AddFilter(envoy_v3.GlobalRateLimitFilter(envoyGlobalRateLimitConfig(cfg.RateLimitConfig))).
 				AddFilter(httpGlobalExternalAuthConfig(cfg.GlobalExternalAuthConfig)).
+				AddFilter(envoy_v3.FilterBuffer(hasRequestBodyLimit(listener.VirtualHosts...))).
 				Get()
 
 			listeners[listener.Name] = envoy_v3.Listener(
@@ -394,11 +425,17 @@ func (c *ListenerCache) OnChange(root *dag.DAG) {
 					DelayedCloseTimeout(cfg.Timeouts.DelayedClose).
 					MaxConnectionDuration(cfg.Timeouts.MaxConnectionDuration).
 					ConnectionShutdownGracePeriod(cfg.Timeouts.ConnectionShutdownGracePeriod).
+					TimeoutResponse(cfg.Timeouts.TimeoutResponse).
+					HTTP2Keepalive(cfg.HTTP2KeepaliveInterval, cfg.HTTP2KeepaliveTimeout).
+					HTTP2MaxConcurrentStreams(cfg.HTTP2MaxConcurrentStreams).
 					AllowChunkedLength(cfg.AllowChunkedLength).
 					MergeSlashes(cfg.MergeSlashes).
+					SetRequestIDInResponse(cfg.SetRequestIDInResponse).
 					ServerHeaderTransformation(cfg.ServerHeaderTransformation).
 					NumTrustedHops(cfg.XffNumTrustedHops).
+					AddFilter(envoy_v3.FilterRBAC(hasSourceFilter(&vh.VirtualHost))).
 					AddFilter(envoy_v3.GlobalRateLimitFilter(envoyGlobalRateLimitConfig(cfg.RateLimitConfig))).
+					AddFilter(envoy_v3.FilterBuffer(hasRequestBodyLimit(&vh.VirtualHost))).
 					ForwardClientCertificate(forwardClientCertificate).
 					Get()
 
@@ -426,6 +463,12 @@ func (c *ListenerCache) OnChange(root *dag.DAG) {
 					cfg.CipherSuites,
 					vh.DownstreamValidation,
 					alpnProtos...)
+				downstreamTLS.OcspStaplePolicy = envoy_v3.ParseOCSPStaplePolicy(vh.OCSPStaplePolicy)
+				if vh.DisableSessionTickets {
+					downstreamTLS.SessionTicketKeysType = &envoy_tls_v3.DownstreamTlsContext_DisableStatelessSessionResumption{
+						DisableStatelessSessionResumption: true,
+					}
+				}
 			}
 
 			listeners[listener.Name].FilterChains = append(listeners[listener.Name].FilterChains, envoy_v3.FilterChainTLS(vh.VirtualHost.Name, downstreamTLS, filters))
@@ -446,6 +489,17 @@ func (c *ListenerCache) OnChange(root *dag.DAG) {
 					alpnProtos...,
 				)
 
+				// The default certificate of a Gateway is the vhost's own
+				// certificate, so it is served with the same TLS options.
+				if vh.FallbackCertificate == vh.Secret {
+					downstreamTLS.OcspStaplePolicy = envoy_v3.ParseOCSPStaplePolicy(vh.OCSPStaplePolicy)
+					if vh.DisableSessionTickets {
+						downstreamTLS.SessionTicketKeysType = &envoy_tls_v3.DownstreamTlsContext_DisableStatelessSessionResumption{
+							DisableStatelessSessionResumption: true,
+						}
+					}
+				}
+
 				cm := envoy_v3.HTTPConnectionManagerBuilder().
 					DefaultFilters().
 					RouteConfigName(fallbackCertRouteConfigName(listener)).
@@ -457,11 +511,17 @@ func (c *ListenerCache) OnChange(root *dag.DAG) {
 					DelayedCloseTimeout(cfg.Timeouts.DelayedClose).
 					MaxConnectionDuration(cfg.Timeouts.MaxConnectionDuration).
 					ConnectionShutdownGracePeriod(cfg.Timeouts.ConnectionShutdownGracePeriod).
+					TimeoutResponse(cfg.Timeouts.TimeoutResponse).
+					HTTP2Keepalive(cfg.HTTP2KeepaliveInterval, cfg.HTTP2KeepaliveTimeout).
+					HTTP2MaxConcurrentStreams(cfg.HTTP2MaxConcurrentStreams).
 					AllowChunkedLength(cfg.AllowChunkedLength).
 					MergeSlashes(cfg.MergeSlashes).
+					SetRequestIDInResponse(cfg.SetRequestIDInResponse).
 					ServerHeaderTransformation(cfg.ServerHeaderTransformation).
 					NumTrustedHops(cfg.XffNumTrustedHops).
+					AddFilter(envoy_v3.FilterRBAC(hasFallbackSourceFilter(listener.SecureVirtualHosts))).
 					AddFilter(envoy_v3.GlobalRateLimitFilter(envoyGlobalRateLimitConfig(cfg.RateLimitConfig))).
+					AddFilter(envoy_v3.FilterBuffer(hasFallbackRequestBodyLimit(listener.SecureVirtualHosts))).
 					ForwardClientCertificate(forwardClientCertificate).
 					Get()
 
@@ -495,6 +555,13 @@ func (c *ListenerCache) OnChange(root *dag.DAG) {
 		}
 	}
 
+	// 2. TCP keepalive
+	if cfg.TCPKeepalive != (envoy_v3.TCPKeepalive{}) {
+		for _, listener := range listeners {
+			listener.SocketOptions = envoy_v3.TCPKeepaliveSocketOptionsFor(cfg.TCPKeepalive)
+		}
+	}
+
 	c.Update(listeners)
 }
 
@@ -531,6 +598,30 @@ func envoyGlobalRateLimitConfig(config *RateLimitConfig) *envoy_v3.GlobalRateLim
 	}
 }
 
+// hasFallbackRequestBodyLimit returns true if any of the supplied secure
+// virtual hosts that serve the fallback certificate route configuration
+// limits the size of request bodies.
+func hasFallbackRequestBodyLimit(vhosts []*dag.SecureVirtualHost) bool {
+	for _, vh := range vhosts {
+		if vh.FallbackCertificate != nil && hasRequestBodyLimit(&vh.VirtualHost) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasFallbackSourceFilter returns true if any of the supplied secure
+// virtual hosts that serve the fallback certificate route configuration
+// has a route with a source filter.
+func hasFallbackSourceFilter(vhosts []*dag.SecureVirtualHost) bool {
+	for _, vh := range vhosts {
+		if vh.FallbackCertificate != nil && hasSourceFilter(&vh.VirtualHost) {
+			return true
+		}
+	}
+	return false
+}
+
 func proxyProtocol(useProxy bool) []*envoy_listener_v3.ListenerFilter {
 	if useProxy {
 		return envoy_v3.ListenerFilters(
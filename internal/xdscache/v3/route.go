@@ -120,6 +120,10 @@ func (c *RouteCache) OnChange(root *dag.DAG) {
 					envoy_v3.VirtualHostAndRoutes(vhost, routes, false),
 				)
 			}
+
+			if hasRequestBodyLimit(dagListener.VirtualHosts...) {
+				envoy_v3.DisableBufferFilter(routeConfigs[routeConfigName])
+			}
 		}
 
 		if len(dagListener.SecureVirtualHosts) > 0 {
@@ -144,6 +148,10 @@ func (c *RouteCache) OnChange(root *dag.DAG) {
 				routeConfigs[routeConfigName].VirtualHosts = append(routeConfigs[routeConfigName].VirtualHosts,
 					envoy_v3.VirtualHostAndRoutes(&vhost.VirtualHost, routes, true))
 
+				if hasRequestBodyLimit(&vhost.VirtualHost) {
+					envoy_v3.DisableBufferFilter(routeConfigs[routeConfigName])
+				}
+
 				// A fallback route configuration contains routes for all the vhosts that have the fallback certificate enabled.
 				// When a request is received, the default TLS filterchain will accept the connection,
 				// and this routing table in RDS defines where the request proxies next.
@@ -156,6 +164,10 @@ func (c *RouteCache) OnChange(root *dag.DAG) {
 
 					routeConfigs[routeConfigName].VirtualHosts = append(routeConfigs[routeConfigName].VirtualHosts,
 						envoy_v3.VirtualHostAndRoutes(&vhost.VirtualHost, routes, true))
+
+					if hasRequestBodyLimit(&vhost.VirtualHost) {
+						envoy_v3.DisableBufferFilter(routeConfigs[routeConfigName])
+					}
 				}
 			}
 		}
@@ -188,6 +200,35 @@ func sortRoutes(routes []*dag.Route) {
 	sort.Stable(sorter.For(routes))
 }
 
+// hasRequestBodyLimit returns true if any route of the supplied
+// virtual hosts limits the size of request bodies, in which case
+// the buffer filter must be configured for the connection manager
+// and route configuration serving them.
+func hasRequestBodyLimit(vhosts ...*dag.VirtualHost) bool {
+	for _, vhost := range vhosts {
+		for _, route := range vhost.Routes {
+			if route.MaxRequestBodyBytes > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasSourceFilter returns true if any route of the supplied virtual
+// hosts has a source filter, in which case the RBAC filter
+// must be configured for the connection manager serving them.
+func hasSourceFilter(vhosts ...*dag.VirtualHost) bool {
+	for _, vhost := range vhosts {
+		for _, route := range vhost.Routes {
+			if len(route.SourceFilters) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func httpRouteConfigName(listener *dag.Listener) string {
 	if len(listener.RouteConfigName) > 0 {
 		return listener.RouteConfigName
@@ -14,6 +14,9 @@
 package certs
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha1" // nolint:gosec
@@ -50,6 +53,12 @@ const (
 	// configuring Subject Alt Names on the certificates.
 	DefaultDNSName = "cluster.local"
 
+	// KeyTypeRSA generates 2048-bit RSA keys. This is the default.
+	KeyTypeRSA = "RSA"
+
+	// KeyTypeECDSA generates ECDSA keys on the P-256 curve.
+	KeyTypeECDSA = "ECDSA"
+
 	// keySize sets the RSA key size to 2048 bits. This is minimum recommended size
 	// for RSA keys.
 	keySize = 2048
@@ -75,6 +84,10 @@ type Configuration struct {
 
 	// EnvoyServiceName holds the name of the Envoy service name.
 	EnvoyServiceName string
+
+	// KeyType is the type of the generated private keys, one of
+	// KeyTypeRSA or KeyTypeECDSA. Defaults to KeyTypeRSA.
+	KeyType string
 }
 
 // Certificates contains a set of Certificates as []byte each holding
@@ -98,7 +111,8 @@ func GenerateCerts(config *Configuration) (*Certificates, error) {
 
 	now := time.Now()
 	expiry := now.Add(24 * time.Duration(uint32OrDefault(config.Lifetime, DefaultCertificateLifetime)) * time.Hour)
-	caCertPEM, caKeyPEM, err := newCA("Project Contour", expiry)
+	keyType := stringOrDefault(config.KeyType, KeyTypeRSA)
+	caCertPEM, caKeyPEM, err := newCA("Project Contour", expiry, keyType)
 	if err != nil {
 		return nil, err
 	}
@@ -109,6 +123,7 @@ func GenerateCerts(config *Configuration) (*Certificates, error) {
 		stringOrDefault(config.ContourServiceName, DefaultContourServiceName),
 		stringOrDefault(config.Namespace, DefaultNamespace),
 		stringOrDefault(config.DNSName, DefaultDNSName),
+		keyType,
 	)
 	if err != nil {
 		return nil, err
@@ -120,6 +135,7 @@ func GenerateCerts(config *Configuration) (*Certificates, error) {
 		stringOrDefault(config.EnvoyServiceName, DefaultEnvoyServiceName),
 		stringOrDefault(config.Namespace, DefaultNamespace),
 		stringOrDefault(config.DNSName, DefaultDNSName),
+		keyType,
 	)
 	if err != nil {
 		return nil, err
@@ -135,10 +151,10 @@ func GenerateCerts(config *Configuration) (*Certificates, error) {
 }
 
 // newCert generates a new keypair given the CA keypair, the expiry time, the service name
-// ("contour" or "envoy"), and the Kubernetes namespace the service will run in (because
-// of the Kubernetes DNS schema.)
+// ("contour" or "envoy"), the Kubernetes namespace the service will run in (because
+// of the Kubernetes DNS schema) and the type of key to generate.
 // The return values are cert, key, err.
-func newCert(caCertPEM, caKeyPEM []byte, expiry time.Time, service, namespace, dnsname string) ([]byte, []byte, error) {
+func newCert(caCertPEM, caKeyPEM []byte, expiry time.Time, service, namespace, dnsname, keyType string) ([]byte, []byte, error) {
 
 	caKeyPair, err := tls.X509KeyPair(caCertPEM, caKeyPEM)
 	if err != nil {
@@ -148,16 +164,28 @@ func newCert(caCertPEM, caKeyPEM []byte, expiry time.Time, service, namespace, d
 	if err != nil {
 		return nil, nil, err
 	}
-	caKey, ok := caKeyPair.PrivateKey.(*rsa.PrivateKey)
+	caKey, ok := caKeyPair.PrivateKey.(crypto.Signer)
 	if !ok {
 		return nil, nil, fmt.Errorf("CA private key has unexpected type %T", caKeyPair.PrivateKey)
 	}
 
-	newKey, err := rsa.GenerateKey(rand.Reader, keySize)
+	newKey, err := newPrivateKey(keyType)
 	if err != nil {
 		return nil, nil, fmt.Errorf("cannot generate key: %v", err)
 	}
 
+	subjectKeyID, err := subjectKeyID(newKey.Public())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Only RSA keys are used to encipher the TLS key exchange,
+	// ECDSA keys are only used for signatures.
+	keyUsage := x509.KeyUsageDigitalSignature | x509.KeyUsageContentCommitment
+	if _, ok := newKey.(*rsa.PrivateKey); ok {
+		keyUsage |= x509.KeyUsageDataEncipherment | x509.KeyUsageKeyEncipherment
+	}
+
 	now := time.Now()
 	template := &x509.Certificate{
 		SerialNumber: newSerial(now),
@@ -166,22 +194,19 @@ func newCert(caCertPEM, caKeyPEM []byte, expiry time.Time, service, namespace, d
 		},
 		NotBefore:    now.UTC().AddDate(0, 0, -1),
 		NotAfter:     expiry.UTC(),
-		SubjectKeyId: bigIntHash(newKey.N),
-		KeyUsage: x509.KeyUsageDigitalSignature |
-			x509.KeyUsageDataEncipherment |
-			x509.KeyUsageKeyEncipherment |
-			x509.KeyUsageContentCommitment,
-		DNSNames: serviceNames(service, namespace, dnsname),
+		SubjectKeyId: subjectKeyID,
+		KeyUsage:     keyUsage,
+		DNSNames:     serviceNames(service, namespace, dnsname),
 	}
-	newCert, err := x509.CreateCertificate(rand.Reader, template, caCert, &newKey.PublicKey, caKey)
+	newCert, err := x509.CreateCertificate(rand.Reader, template, caCert, newKey.Public(), caKey)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	newKeyPEM := pem.EncodeToMemory(&pem.Block{
-		Type:  "RSA PRIVATE KEY",
-		Bytes: x509.MarshalPKCS1PrivateKey(newKey),
-	})
+	newKeyPEM, err := encodePrivateKey(newKey)
+	if err != nil {
+		return nil, nil, err
+	}
 	newCertPEM := pem.EncodeToMemory(&pem.Block{
 		Type:  "CERTIFICATE",
 		Bytes: newCert,
@@ -190,15 +215,26 @@ func newCert(caCertPEM, caKeyPEM []byte, expiry time.Time, service, namespace, d
 
 }
 
-// newCA generates a new CA, given the CA's CN and an expiry time.
+// newCA generates a new CA, given the CA's CN, an expiry time and the
+// type of key to generate.
 // The return order is cacert, cakey, error.
-func newCA(cn string, expiry time.Time) ([]byte, []byte, error) {
+func newCA(cn string, expiry time.Time, keyType string) ([]byte, []byte, error) {
 
-	key, err := rsa.GenerateKey(rand.Reader, keySize)
+	key, err := newPrivateKey(keyType)
 	if err != nil {
 		return nil, nil, err
 	}
 
+	subjectKeyID, err := subjectKeyID(key.Public())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyUsage := x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign
+	if _, ok := key.(*rsa.PrivateKey); ok {
+		keyUsage |= x509.KeyUsageKeyEncipherment
+	}
+
 	now := time.Now()
 	serial := newSerial(now)
 	template := &x509.Certificate{
@@ -209,12 +245,12 @@ func newCA(cn string, expiry time.Time) ([]byte, []byte, error) {
 		},
 		NotBefore:             now.UTC().AddDate(0, 0, -1),
 		NotAfter:              expiry.UTC(),
-		SubjectKeyId:          bigIntHash(key.N),
-		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		SubjectKeyId:          subjectKeyID,
+		KeyUsage:              keyUsage,
 		IsCA:                  true,
 		BasicConstraintsValid: true,
 	}
-	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -222,13 +258,47 @@ func newCA(cn string, expiry time.Time) ([]byte, []byte, error) {
 		Type:  "CERTIFICATE",
 		Bytes: certDER,
 	})
-	keyPEMData := pem.EncodeToMemory(&pem.Block{
-		Type:  "RSA PRIVATE KEY",
-		Bytes: x509.MarshalPKCS1PrivateKey(key),
-	})
+	keyPEMData, err := encodePrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
 	return certPEMData, keyPEMData, nil
 }
 
+// newPrivateKey generates a private key of the given type.
+func newPrivateKey(keyType string) (crypto.Signer, error) {
+	switch keyType {
+	case KeyTypeRSA:
+		return rsa.GenerateKey(rand.Reader, keySize)
+	case KeyTypeECDSA:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", keyType)
+	}
+}
+
+// encodePrivateKey returns the PEM encoding of key.
+func encodePrivateKey(key crypto.Signer) ([]byte, error) {
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		return pem.EncodeToMemory(&pem.Block{
+			Type:  "RSA PRIVATE KEY",
+			Bytes: x509.MarshalPKCS1PrivateKey(key),
+		}), nil
+	case *ecdsa.PrivateKey:
+		der, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+		return pem.EncodeToMemory(&pem.Block{
+			Type:  "EC PRIVATE KEY",
+			Bytes: der,
+		}), nil
+	default:
+		return nil, fmt.Errorf("private key has unexpected type %T", key)
+	}
+}
+
 func newSerial(now time.Time) *big.Int {
 	return big.NewInt(int64(now.Nanosecond()))
 }
@@ -247,6 +317,22 @@ func bigIntHash(n *big.Int) []byte {
 	return h.Sum(nil)
 }
 
+// subjectKeyID generates a SubjectKeyId for pub. RSA keys keep using
+// bigIntHash, other keys use the SHA-1 hash of their encoded form.
+func subjectKeyID(pub crypto.PublicKey) ([]byte, error) {
+	if key, ok := pub.(*rsa.PublicKey); ok {
+		return bigIntHash(key.N), nil
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha1.Sum(der) // nolint:gosec
+	return sum[:], nil
+}
+
 func serviceNames(service, namespace, dnsname string) []string {
 	return []string{
 		service,
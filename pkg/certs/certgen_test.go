@@ -14,6 +14,9 @@
 package certs
 
 import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/tls"
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
@@ -104,6 +107,15 @@ func TestGenerateCerts(t *testing.T) {
 		wantError:          nil,
 	})
 
+	run(t, "ECDSA keys", testcase{
+		config: &Configuration{
+			KeyType: KeyTypeECDSA,
+		},
+		wantContourDNSName: "contour",
+		wantEnvoyDNSName:   "envoy",
+		wantError:          nil,
+	})
+
 	run(t, "custom dns name", testcase{
 		config: &Configuration{
 			DNSName: "project.contour",
@@ -114,22 +126,60 @@ func TestGenerateCerts(t *testing.T) {
 	})
 }
 
+func TestGenerateCertsKeyType(t *testing.T) {
+	tests := map[string]struct {
+		keyType string
+		want    interface{}
+	}{
+		"default": {
+			want: &rsa.PrivateKey{},
+		},
+		"RSA": {
+			keyType: KeyTypeRSA,
+			want:    &rsa.PrivateKey{},
+		},
+		"ECDSA": {
+			keyType: KeyTypeECDSA,
+			want:    &ecdsa.PrivateKey{},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := GenerateCerts(&Configuration{KeyType: tc.keyType})
+			require.NoError(t, err)
+
+			for _, pair := range [][2][]byte{
+				{got.ContourCertificate, got.ContourPrivateKey},
+				{got.EnvoyCertificate, got.EnvoyPrivateKey},
+			} {
+				cert, err := tls.X509KeyPair(pair[0], pair[1])
+				require.NoError(t, err)
+				assert.IsType(t, tc.want, cert.PrivateKey)
+			}
+		})
+	}
+
+	_, err := GenerateCerts(&Configuration{KeyType: "DSA"})
+	assert.EqualError(t, err, `unsupported key type "DSA"`)
+}
+
 func TestGeneratedCertsValid(t *testing.T) {
 
 	now := time.Now()
 	expiry := now.Add(24 * 365 * time.Hour)
 
-	cacert, cakey, err := newCA("contour", expiry)
+	cacert, cakey, err := newCA("contour", expiry, KeyTypeRSA)
 	require.NoErrorf(t, err, "Failed to generate CA cert")
 
-	contourcert, _, err := newCert(cacert, cakey, expiry, "contour", "projectcontour", "cluster.local")
+	contourcert, _, err := newCert(cacert, cakey, expiry, "contour", "projectcontour", "cluster.local", KeyTypeRSA)
 	require.NoErrorf(t, err, "Failed to generate Contour cert")
 
 	roots := x509.NewCertPool()
 	ok := roots.AppendCertsFromPEM(cacert)
 	require.Truef(t, ok, "Failed to set up CA cert for testing, maybe it's an invalid PEM")
 
-	envoycert, _, err := newCert(cacert, cakey, expiry, "envoy", "projectcontour", "cluster.local")
+	envoycert, _, err := newCert(cacert, cakey, expiry, "envoy", "projectcontour", "cluster.local", KeyTypeRSA)
 	require.NoErrorf(t, err, "Failed to generate Envoy cert")
 
 	tests := map[string]struct {
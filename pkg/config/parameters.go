@@ -22,6 +22,7 @@ import (
 	"strings"
 	"time"
 
+	contour_api_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
 	contour_api_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
 	"gopkg.in/yaml.v3"
 	"k8s.io/apimachinery/pkg/util/validation"
@@ -240,6 +241,26 @@ type GatewayParameters struct {
 	// classes.
 	// Exactly one of ControllerName or GatewayRef must be set.
 	GatewayRef *NamespacedName `yaml:"gatewayRef,omitempty"`
+
+	// AllowedHostnameSuffixes restricts the hostnames of the routes
+	// attached to the Gateway to those under one of the suffixes.
+	// If unset, routes may match any hostname.
+	AllowedHostnameSuffixes []string `yaml:"allowedHostnameSuffixes,omitempty"`
+
+	// DisabledRouteKinds are the route kinds that are not supported.
+	// Routes of these kinds are not accepted.
+	DisabledRouteKinds []string `yaml:"disabledRouteKinds,omitempty"`
+
+	// DefaultBackend is the Service that requests to the Gateway's HTTP
+	// listeners are routed to when no route matches them.
+	DefaultBackend *GatewayDefaultBackend `yaml:"defaultBackend,omitempty"`
+}
+
+// GatewayDefaultBackend is a reference to a port of a Service.
+type GatewayDefaultBackend struct {
+	Namespace string `yaml:"namespace"`
+	Name      string `yaml:"name"`
+	Port      int32  `yaml:"port"`
 }
 
 // TimeoutParameters holds various configurable proxy timeout values.
@@ -305,6 +326,41 @@ type TimeoutParameters struct {
 	// for more information.
 	// +optional
 	ConnectTimeout string `yaml:"connect-timeout,omitempty"`
+
+	// TimeoutResponse customizes the response Envoy sends to the client
+	// when a request to the upstream service times out. If not set, Envoy
+	// returns its default 504 response.
+	//
+	// See https://www.envoyproxy.io/docs/envoy/latest/configuration/http/http_conn_man/local_reply
+	// for more information.
+	TimeoutResponse *TimeoutResponse `yaml:"timeout-response,omitempty"`
+}
+
+// TimeoutResponse defines the local reply returned by Envoy when an
+// upstream request times out.
+type TimeoutResponse struct {
+	// StatusCode is the HTTP status code of the response.
+	// Defaults to 504.
+	StatusCode int `yaml:"status-code,omitempty"`
+
+	// Body is the content of the response body.
+	Body string `yaml:"body,omitempty"`
+
+	// ContentType is the value of the Content-Type header of the
+	// response. Defaults to "text/plain".
+	ContentType string `yaml:"content-type,omitempty"`
+}
+
+// Validate the timeout response.
+func (t *TimeoutResponse) Validate() error {
+	response := &contour_api_v1alpha1.TimeoutResponse{
+		Body:        t.Body,
+		ContentType: t.ContentType,
+	}
+	if t.StatusCode != 0 {
+		response.StatusCode = &t.StatusCode
+	}
+	return response.Validate()
 }
 
 // Validate the timeout parameters.
@@ -354,11 +410,18 @@ func (t TimeoutParameters) Validate() error {
 		}
 	}
 
+	if t.TimeoutResponse != nil {
+		if err := t.TimeoutResponse.Validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 type HeadersPolicy struct {
 	Set    map[string]string `yaml:"set,omitempty"`
+	Add    map[string]string `yaml:"add,omitempty"`
 	Remove []string          `yaml:"remove,omitempty"`
 }
 
@@ -368,6 +431,11 @@ func (h HeadersPolicy) Validate() error {
 			return fmt.Errorf("invalid header name %q: %v", key, msgs)
 		}
 	}
+	for key := range h.Add {
+		if msgs := validation.IsHTTPHeaderName(key); len(msgs) != 0 {
+			return fmt.Errorf("invalid header name %q: %v", key, msgs)
+		}
+	}
 	for _, val := range h.Remove {
 		if msgs := validation.IsHTTPHeaderName(val); len(msgs) != 0 {
 			return fmt.Errorf("invalid header name %q: %v", val, msgs)
@@ -386,14 +454,85 @@ type PolicyParameters struct {
 
 	// ApplyToIngress determines if the Policies will apply to ingress objects
 	ApplyToIngress bool `yaml:"applyToIngress,omitempty"`
+
+	// ApplyToGatewayAPI determines if the Policies will apply to Gateway API routes
+	ApplyToGatewayAPI bool `yaml:"applyToGatewayAPI,omitempty"`
+
+	// HSTSPolicy defines the HTTP Strict Transport Security policy
+	// applied to HTTPS responses from HTTPProxy virtual hosts that do
+	// not set their own.
+	HSTSPolicy *HSTSPolicy `yaml:"hsts,omitempty"`
+
+	// CSPPolicy defines the Content Security Policy returned on
+	// responses from HTTPProxy routes that do not set their own.
+	CSPPolicy *CSPPolicy `yaml:"csp,omitempty"`
+
+	// UpstreamClusterHeader is the name of a response header set to
+	// the name of the upstream cluster that served the request.
+	UpstreamClusterHeader string `yaml:"upstream-cluster-header,omitempty"`
+}
+
+// CSPPolicy defines the Content-Security-Policy response header
+// returned to clients.
+type CSPPolicy struct {
+	// Directives is the header value, e.g. "default-src 'self'".
+	Directives string `yaml:"directives"`
+}
+
+// Validate the CSP policy.
+func (c *CSPPolicy) Validate() error {
+	return (&contour_api_v1.CSPPolicy{
+		Directives: c.Directives,
+	}).Validate()
 }
 
-// Validate the header parameters.
+// HSTSPolicy defines the Strict-Transport-Security response header
+// advertised to clients.
+type HSTSPolicy struct {
+	// MaxAge is how long clients should only access the virtual
+	// host over HTTPS, expressed as a Go duration string.
+	MaxAge string `yaml:"max-age"`
+
+	// IncludeSubDomains extends the policy to all subdomains.
+	IncludeSubDomains bool `yaml:"include-subdomains,omitempty"`
+
+	// Preload consents to inclusion in browser HSTS preload lists.
+	Preload bool `yaml:"preload,omitempty"`
+}
+
+// Validate the HSTS policy.
+func (h *HSTSPolicy) Validate() error {
+	return (&contour_api_v1.HSTSPolicy{
+		MaxAge:            h.MaxAge,
+		IncludeSubDomains: h.IncludeSubDomains,
+		Preload:           h.Preload,
+	}).Validate()
+}
+
+// Validate the policy parameters.
 func (h PolicyParameters) Validate() error {
 	if err := h.RequestHeadersPolicy.Validate(); err != nil {
 		return err
 	}
-	return h.ResponseHeadersPolicy.Validate()
+	if err := h.ResponseHeadersPolicy.Validate(); err != nil {
+		return err
+	}
+	if h.HSTSPolicy != nil {
+		if err := h.HSTSPolicy.Validate(); err != nil {
+			return fmt.Errorf("invalid HSTS policy: %w", err)
+		}
+	}
+	if h.CSPPolicy != nil {
+		if err := h.CSPPolicy.Validate(); err != nil {
+			return fmt.Errorf("invalid CSP policy: %w", err)
+		}
+	}
+	if h.UpstreamClusterHeader != "" {
+		if msgs := validation.IsHTTPHeaderName(h.UpstreamClusterHeader); len(msgs) != 0 {
+			return fmt.Errorf("invalid upstream cluster header %q: %v", h.UpstreamClusterHeader, msgs)
+		}
+	}
+	return nil
 }
 
 // ClusterParameters holds various configurable cluster values.
@@ -413,6 +552,71 @@ type ClusterParameters struct {
 	// See https://www.envoyproxy.io/docs/envoy/latest/api-v3/config/cluster/v3/cluster.proto.html#envoy-v3-api-enum-config-cluster-v3-cluster-dnslookupfamily
 	// for more information.
 	DNSLookupFamily ClusterDNSFamilyType `yaml:"dns-lookup-family"`
+
+	// LocalityWeightedLB configures locality weighted load balancing
+	// across the zones of service endpoints.
+	LocalityWeightedLB LocalityWeightedLBParameters `yaml:"locality-weighted-lb,omitempty"`
+
+	// TopologyAwareRouting configures zone aware routing to the
+	// service endpoints in the zone of each Envoy.
+	TopologyAwareRouting TopologyAwareRoutingParameters `yaml:"topology-aware-routing,omitempty"`
+
+	// EndpointWeightLabel is the name of a pod label holding the load
+	// balancing weight of the pod's endpoints.
+	EndpointWeightLabel string `yaml:"endpoint-weight-label,omitempty"`
+
+	// DefaultLoadBalancerPolicy is the load balancer strategy of the
+	// clusters whose route or service does not set one.
+	//
+	// Values: `RoundRobin` (default), `WeightedLeastRequest`, `Random`.
+	DefaultLoadBalancerPolicy string `yaml:"default-load-balancer-policy,omitempty"`
+}
+
+// LocalityWeightedLBParameters holds the locality weighted load balancing settings.
+type LocalityWeightedLBParameters struct {
+	// Enabled groups the endpoints of service clusters by the
+	// topology.kubernetes.io/zone label of their node, and enables
+	// locality weighted load balancing across the zones.
+	Enabled bool `yaml:"enabled,omitempty"`
+
+	// ZoneWeights sets the load balancing weight of each zone. Zones
+	// without a weight are weighted by their number of endpoints.
+	ZoneWeights map[string]uint32 `yaml:"zone-weights,omitempty"`
+}
+
+// Validate ensures that zone weights are only set when locality
+// weighted load balancing is enabled, and are greater than 0.
+func (l LocalityWeightedLBParameters) Validate() error {
+	if !l.Enabled && len(l.ZoneWeights) > 0 {
+		return fmt.Errorf("invalid locality weighted load balancing parameters: zone weights require enabled to be true")
+	}
+	for zone, weight := range l.ZoneWeights {
+		if weight == 0 {
+			return fmt.Errorf("invalid locality weighted load balancing weight for zone %q: must be greater than 0", zone)
+		}
+	}
+	return nil
+}
+
+// TopologyAwareRoutingParameters holds the topology aware routing settings.
+type TopologyAwareRoutingParameters struct {
+	// Enabled groups the endpoints of service clusters by their
+	// EndpointSlice topology hints, and enables zone aware routing
+	// across the zones.
+	Enabled bool `yaml:"enabled,omitempty"`
+
+	// MinClusterSize is the number of endpoints a cluster needs for
+	// Envoy to route its requests zone aware. Envoy's default is 6.
+	MinClusterSize uint32 `yaml:"min-cluster-size,omitempty"`
+}
+
+// Validate ensures that the minimum cluster size is only set when
+// topology aware routing is enabled.
+func (t TopologyAwareRoutingParameters) Validate() error {
+	if !t.Enabled && t.MinClusterSize > 0 {
+		return fmt.Errorf("invalid topology aware routing parameters: min cluster size requires enabled to be true")
+	}
+	return nil
 }
 
 // NetworkParameters hold various configurable network values.
@@ -507,6 +711,11 @@ type Parameters struct {
 	// which strips duplicate slashes from request URL paths.
 	DisableMergeSlashes bool `yaml:"disableMergeSlashes,omitempty"`
 
+	// SetRequestIDInResponse returns the request ID, which Envoy
+	// logs as %REQ(X-REQUEST-ID)%, to clients in the X-Request-Id
+	// response header.
+	SetRequestIDInResponse bool `yaml:"setRequestIDInResponse,omitempty"`
+
 	// Defines the action to be applied to the Server header on the response path.
 	// When configured as overwrite, overwrites any Server header with "envoy".
 	// When configured as append_if_absent, if a Server header is present, pass it through, otherwise set it to "envoy".
@@ -520,6 +729,10 @@ type Parameters struct {
 	// TODO(youngnick): put a link to the issue and CVE here.
 	EnableExternalNameService bool `yaml:"enableExternalNameService,omitempty"`
 
+	// UseEndpointSlices watches EndpointSlices, rather than Endpoints,
+	// to discover the endpoints of Services.
+	UseEndpointSlices bool `yaml:"useEndpointSlices,omitempty"`
+
 	// Timeouts holds various configurable timeouts that can
 	// be set in the config file.
 	Timeouts TimeoutParameters `yaml:"timeouts,omitempty"`
@@ -720,10 +933,42 @@ const LogLevelDisabled AccessLogLevel = "disabled"
 
 // Validate verifies that the parameter values do not have any syntax errors.
 func (p *Parameters) Validate() error {
+	if p.KubeClientQPS < 0 {
+		return fmt.Errorf("invalid Kubernetes client QPS %v, must not be negative", p.KubeClientQPS)
+	}
+
+	if p.KubeClientBurst < 0 {
+		return fmt.Errorf("invalid Kubernetes client burst %d, must not be negative", p.KubeClientBurst)
+	}
+
 	if err := p.Cluster.DNSLookupFamily.Validate(); err != nil {
 		return err
 	}
 
+	if err := p.Cluster.LocalityWeightedLB.Validate(); err != nil {
+		return err
+	}
+
+	if err := p.Cluster.TopologyAwareRouting.Validate(); err != nil {
+		return err
+	}
+
+	if p.Cluster.TopologyAwareRouting.Enabled && p.Cluster.LocalityWeightedLB.Enabled {
+		return fmt.Errorf("invalid cluster parameters: topology aware routing and locality weighted load balancing cannot both be enabled")
+	}
+
+	if label := p.Cluster.EndpointWeightLabel; label != "" {
+		if msgs := validation.IsQualifiedName(label); len(msgs) != 0 {
+			return fmt.Errorf("invalid cluster endpoint weight label %q: %v", label, msgs)
+		}
+	}
+
+	switch p.Cluster.DefaultLoadBalancerPolicy {
+	case "", "RoundRobin", "WeightedLeastRequest", "Random":
+	default:
+		return fmt.Errorf("invalid cluster default load balancer policy %q", p.Cluster.DefaultLoadBalancerPolicy)
+	}
+
 	if err := p.Server.XDSServerType.Validate(); err != nil {
 		return err
 	}
@@ -792,6 +1037,7 @@ func Defaults() Parameters {
 		DisablePermitInsecure:      false,
 		DisableAllowChunkedLength:  false,
 		DisableMergeSlashes:        false,
+		SetRequestIDInResponse:     false,
 		ServerHeaderTransformation: OverwriteServerHeader,
 		Timeouts: TimeoutParameters{
 			// This is chosen as a rough default to stop idle connections wasting resources,
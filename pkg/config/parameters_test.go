@@ -129,6 +129,39 @@ func TestValidateClusterDNSFamilyType(t *testing.T) {
 	assert.NoError(t, IPv6ClusterDNSFamily.Validate())
 	assert.NoError(t, AllClusterDNSFamily.Validate())
 }
+
+func TestValidateLocalityWeightedLBParameters(t *testing.T) {
+	assert.NoError(t, LocalityWeightedLBParameters{}.Validate())
+	assert.NoError(t, LocalityWeightedLBParameters{Enabled: true}.Validate())
+	assert.NoError(t, LocalityWeightedLBParameters{
+		Enabled:     true,
+		ZoneWeights: map[string]uint32{"zone-a": 2},
+	}.Validate())
+
+	assert.Error(t, LocalityWeightedLBParameters{
+		ZoneWeights: map[string]uint32{"zone-a": 2},
+	}.Validate())
+	assert.Error(t, LocalityWeightedLBParameters{
+		Enabled:     true,
+		ZoneWeights: map[string]uint32{"zone-a": 0},
+	}.Validate())
+}
+
+func TestValidateTopologyAwareRoutingParameters(t *testing.T) {
+	assert.NoError(t, TopologyAwareRoutingParameters{}.Validate())
+	assert.NoError(t, TopologyAwareRoutingParameters{Enabled: true}.Validate())
+	assert.NoError(t, TopologyAwareRoutingParameters{Enabled: true, MinClusterSize: 1}.Validate())
+
+	assert.Error(t, TopologyAwareRoutingParameters{MinClusterSize: 1}.Validate())
+
+	p := Defaults()
+	p.Cluster.TopologyAwareRouting.Enabled = true
+	assert.NoError(t, p.Validate())
+
+	p.Cluster.LocalityWeightedLB.Enabled = true
+	assert.Error(t, p.Validate())
+}
+
 func TestValidateServerHeaderTranformationType(t *testing.T) {
 	assert.Error(t, ServerHeaderTransformationType("").Validate())
 	assert.Error(t, ServerHeaderTransformationType("foo").Validate())
@@ -169,6 +202,42 @@ func TestValidateHeadersPolicy(t *testing.T) {
 	}.Validate())
 }
 
+func TestValidateUpstreamClusterHeader(t *testing.T) {
+	assert.NoError(t, PolicyParameters{UpstreamClusterHeader: "x-upstream-cluster"}.Validate())
+	assert.Error(t, PolicyParameters{UpstreamClusterHeader: "x-upstream-cluster:"}.Validate())
+}
+
+func TestValidateHSTSPolicy(t *testing.T) {
+	assert.NoError(t, PolicyParameters{}.Validate())
+	assert.NoError(t, PolicyParameters{
+		HSTSPolicy: &HSTSPolicy{MaxAge: "24h"},
+	}.Validate())
+	assert.NoError(t, PolicyParameters{
+		HSTSPolicy: &HSTSPolicy{MaxAge: "8760h", IncludeSubDomains: true, Preload: true},
+	}.Validate())
+	assert.Error(t, PolicyParameters{
+		HSTSPolicy: &HSTSPolicy{MaxAge: "forever"},
+	}.Validate())
+	assert.Error(t, PolicyParameters{
+		HSTSPolicy: &HSTSPolicy{MaxAge: "8760h", Preload: true},
+	}.Validate())
+	assert.Error(t, PolicyParameters{
+		HSTSPolicy: &HSTSPolicy{MaxAge: "24h", IncludeSubDomains: true, Preload: true},
+	}.Validate())
+}
+
+func TestValidateCSPPolicy(t *testing.T) {
+	assert.NoError(t, PolicyParameters{
+		CSPPolicy: &CSPPolicy{Directives: "default-src 'self'"},
+	}.Validate())
+	assert.Error(t, PolicyParameters{
+		CSPPolicy: &CSPPolicy{},
+	}.Validate())
+	assert.Error(t, PolicyParameters{
+		CSPPolicy: &CSPPolicy{Directives: "default-src 'self'\nX-Injected: true"},
+	}.Validate())
+}
+
 func TestValidateNamespacedName(t *testing.T) {
 	assert.NoErrorf(t, NamespacedName{}.Validate(), "empty name should be OK")
 	assert.NoError(t, NamespacedName{Name: "name", Namespace: "ns"}.Validate())
@@ -195,6 +264,20 @@ func TestValidateGatewayParameters(t *testing.T) {
 	assert.Equal(t, nil, gw.Validate())
 }
 
+func TestValidateKubernetesClientRateLimits(t *testing.T) {
+	params := Defaults()
+	params.KubeClientQPS = 50
+	params.KubeClientBurst = 100
+	assert.NoError(t, params.Validate())
+
+	params.KubeClientQPS = -1
+	assert.Error(t, params.Validate())
+
+	params.KubeClientQPS = 50
+	params.KubeClientBurst = -1
+	assert.Error(t, params.Validate())
+}
+
 func TestValidateHTTPVersionType(t *testing.T) {
 	assert.Error(t, HTTPVersionType("").Validate())
 	assert.Error(t, HTTPVersionType("foo").Validate())
@@ -234,6 +317,12 @@ func TestValidateTimeoutParams(t *testing.T) {
 	assert.Error(t, TimeoutParameters{ConnectionShutdownGracePeriod: "bong"}.Validate())
 	assert.Error(t, TimeoutParameters{ConnectTimeout: "infinite"}.Validate())
 
+	assert.NoError(t, TimeoutParameters{
+		TimeoutResponse: &TimeoutResponse{StatusCode: 503, Body: "<h1>Timed out</h1>", ContentType: "text/html"},
+	}.Validate())
+	assert.Error(t, TimeoutParameters{TimeoutResponse: &TimeoutResponse{StatusCode: 302}}.Validate())
+	assert.Error(t, TimeoutParameters{TimeoutResponse: &TimeoutResponse{ContentType: "text/html"}}.Validate())
+
 }
 
 func TestTLSParametersValidation(t *testing.T) {
@@ -296,6 +385,18 @@ cluster:
   dns-lookup-family: stone
 `)
 
+	check(`
+cluster:
+  dns-lookup-family: auto
+  default-load-balancer-policy: Cookie
+`)
+
+	check(`
+cluster:
+  dns-lookup-family: auto
+  endpoint-weight-label: "-weight"
+`)
+
 	check(`
 server:
   xds-server-type: magic
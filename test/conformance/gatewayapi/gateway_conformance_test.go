@@ -18,7 +18,9 @@ package gatewayapi
 import (
 	"testing"
 
+	"github.com/projectcontour/contour/internal/gatewayapi"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/gateway-api/apis/v1alpha2"
@@ -38,12 +40,17 @@ func TestGatewayConformance(t *testing.T) {
 	require.NoError(t, v1alpha2.AddToScheme(client.Scheme()))
 	require.NoError(t, v1beta1.AddToScheme(client.Scheme()))
 
+	supportedFeatures := sets.New[suite.SupportedFeature]()
+	for _, f := range gatewayapi.SupportedFeatures {
+		supportedFeatures.Insert(suite.SupportedFeature(f))
+	}
+
 	cSuite := suite.New(suite.Options{
-		Client:                     client,
-		GatewayClassName:           *flags.GatewayClassName,
-		Debug:                      *flags.ShowDebug,
-		CleanupBaseResources:       *flags.CleanupBaseResources,
-		EnableAllSupportedFeatures: true,
+		Client:               client,
+		GatewayClassName:     *flags.GatewayClassName,
+		Debug:                *flags.ShowDebug,
+		CleanupBaseResources: *flags.CleanupBaseResources,
+		SupportedFeatures:    supportedFeatures,
 		// Keep the list of skipped features in sync with
 		// test/scripts/run-gateway-conformance.sh.
 		SkipTests: []string{
@@ -17,6 +17,7 @@ package e2e
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"os"
 
@@ -91,73 +92,121 @@ func (e *Echo) DeployN(ns, name string, replicas int32) func() {
 			Selector: &metav1.LabelSelector{
 				MatchLabels: map[string]string{"app.kubernetes.io/name": name},
 			},
-			Template: corev1.PodTemplateSpec{
-				ObjectMeta: metav1.ObjectMeta{
-					Labels: map[string]string{"app.kubernetes.io/name": name},
+			Template: echoPodTemplate(name),
+		},
+	}
+	require.NoError(e.t, e.client.Create(context.TODO(), deployment))
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      name,
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "http",
+					Port:       80,
+					TargetPort: intstr.FromString("http-api"),
 				},
-				Spec: corev1.PodSpec{
-					TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
-						{
-							// Attempt to spread pods across different nodes if possible.
-							TopologyKey:       "kubernetes.io/hostname",
-							MaxSkew:           1,
-							WhenUnsatisfiable: corev1.ScheduleAnyway,
-							LabelSelector: &metav1.LabelSelector{
-								MatchLabels: map[string]string{"app.kubernetes.io/name": name},
-							},
-						},
-					},
-					Containers: []corev1.Container{
-						{
-							Name:  "conformance-echo",
-							Image: EchoServerImage,
-							Env: []corev1.EnvVar{
-								{
-									Name:  "INGRESS_NAME",
-									Value: name,
-								},
-								{
-									Name:  "SERVICE_NAME",
-									Value: name,
-								},
-								{
-									Name: "POD_NAME",
-									ValueFrom: &corev1.EnvVarSource{
-										FieldRef: &corev1.ObjectFieldSelector{
-											FieldPath: "metadata.name",
-										},
-									},
-								},
-								{
-									Name: "NAMESPACE",
-									ValueFrom: &corev1.EnvVarSource{
-										FieldRef: &corev1.ObjectFieldSelector{
-											FieldPath: "metadata.namespace",
-										},
-									},
-								},
-							},
-							Ports: []corev1.ContainerPort{
-								{
-									Name:          "http-api",
-									ContainerPort: 3000,
-								},
-							},
-							ReadinessProbe: &corev1.Probe{
-								ProbeHandler: corev1.ProbeHandler{
-									HTTPGet: &corev1.HTTPGetAction{
-										Path: "/health",
-										Port: intstr.FromInt(3000),
-									},
-								},
-							},
-						},
-					},
+			},
+			Selector: map[string]string{"app.kubernetes.io/name": name},
+		},
+	}
+	require.NoError(e.t, e.client.Create(context.TODO(), service))
+
+	return func() {
+		require.NoError(e.t, e.client.Delete(context.TODO(), service))
+		require.NoError(e.t, e.client.Delete(context.TODO(), deployment))
+	}
+}
+
+// DeployHeadlessStatefulSetN creates the ingress-conformance-echo fixture
+// as a StatefulSet with the given number of replicas behind a headless
+// service, in the given namespace and with the given name, or fails the
+// test if it encounters an error. Namespace is defaulted to "default"
+// and name is defaulted to "ingress-conformance-echo" if not provided.
+// Returns a cleanup function.
+func (e *Echo) DeployHeadlessStatefulSetN(ns, name string, replicas int32) func() {
+	ns = valOrDefault(ns, "default")
+	name = valOrDefault(name, "ingress-conformance-echo")
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      name,
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: corev1.ClusterIPNone,
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "http",
+					Port:       80,
+					TargetPort: intstr.FromString("http-api"),
 				},
 			},
+			Selector: map[string]string{"app.kubernetes.io/name": name},
 		},
 	}
-	require.NoError(e.t, e.client.Create(context.TODO(), deployment))
+	require.NoError(e.t, e.client.Create(context.TODO(), service))
+
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      name,
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:    ref.To(replicas),
+			ServiceName: name,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app.kubernetes.io/name": name},
+			},
+			// The pods don't depend on each other, start them all at once.
+			PodManagementPolicy: appsv1.ParallelPodManagement,
+			Template:            echoPodTemplate(name),
+		},
+	}
+	require.NoError(e.t, e.client.Create(context.TODO(), statefulSet))
+
+	return func() {
+		require.NoError(e.t, e.client.Delete(context.TODO(), statefulSet))
+		require.NoError(e.t, e.client.Delete(context.TODO(), service))
+	}
+}
+
+// DeployInZones creates the ingress-conformance-echo fixture with one
+// pod in each of the given zones behind a single service, in the given
+// namespace and with the given name, or fails the test if it encounters
+// an error. Each pod is run by its own Deployment, named after the
+// index of its zone. Namespace is defaulted to "default" and name is
+// defaulted to "ingress-conformance-echo" if not provided. Returns a
+// cleanup function.
+func (e *Echo) DeployInZones(ns, name string, zones ...string) func() {
+	ns = valOrDefault(ns, "default")
+	name = valOrDefault(name, "ingress-conformance-echo")
+
+	var deployments []*appsv1.Deployment
+	for i, zone := range zones {
+		template := echoPodTemplate(name)
+		template.Labels["zone"] = zone
+		template.Spec.NodeSelector = map[string]string{corev1.LabelTopologyZone: zone}
+
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: ns,
+				Name:      fmt.Sprintf("%s-%d", name, i),
+			},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ref.To(int32(1)),
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app.kubernetes.io/name": name, "zone": zone},
+				},
+				Template: template,
+			},
+		}
+		require.NoError(e.t, e.client.Create(context.TODO(), deployment))
+		deployments = append(deployments, deployment)
+	}
 
 	service := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
@@ -179,7 +228,78 @@ func (e *Echo) DeployN(ns, name string, replicas int32) func() {
 
 	return func() {
 		require.NoError(e.t, e.client.Delete(context.TODO(), service))
-		require.NoError(e.t, e.client.Delete(context.TODO(), deployment))
+		for _, deployment := range deployments {
+			require.NoError(e.t, e.client.Delete(context.TODO(), deployment))
+		}
+	}
+}
+
+// echoPodTemplate returns the pod template of the
+// ingress-conformance-echo fixture with the given name.
+func echoPodTemplate(name string) corev1.PodTemplateSpec {
+	return corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"app.kubernetes.io/name": name},
+		},
+		Spec: corev1.PodSpec{
+			TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+				{
+					// Attempt to spread pods across different nodes if possible.
+					TopologyKey:       "kubernetes.io/hostname",
+					MaxSkew:           1,
+					WhenUnsatisfiable: corev1.ScheduleAnyway,
+					LabelSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"app.kubernetes.io/name": name},
+					},
+				},
+			},
+			Containers: []corev1.Container{
+				{
+					Name:  "conformance-echo",
+					Image: EchoServerImage,
+					Env: []corev1.EnvVar{
+						{
+							Name:  "INGRESS_NAME",
+							Value: name,
+						},
+						{
+							Name:  "SERVICE_NAME",
+							Value: name,
+						},
+						{
+							Name: "POD_NAME",
+							ValueFrom: &corev1.EnvVarSource{
+								FieldRef: &corev1.ObjectFieldSelector{
+									FieldPath: "metadata.name",
+								},
+							},
+						},
+						{
+							Name: "NAMESPACE",
+							ValueFrom: &corev1.EnvVarSource{
+								FieldRef: &corev1.ObjectFieldSelector{
+									FieldPath: "metadata.namespace",
+								},
+							},
+						},
+					},
+					Ports: []corev1.ContainerPort{
+						{
+							Name:          "http-api",
+							ContainerPort: 3000,
+						},
+					},
+					ReadinessProbe: &corev1.Probe{
+						ProbeHandler: corev1.ProbeHandler{
+							HTTPGet: &corev1.HTTPGetAction{
+								Path: "/health",
+								Port: intstr.FromInt(3000),
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 }
 
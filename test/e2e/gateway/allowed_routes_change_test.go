@@ -0,0 +1,123 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build e2e
+
+package gateway
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/projectcontour/contour/internal/gatewayapi"
+	"github.com/projectcontour/contour/internal/ref"
+	"github.com/projectcontour/contour/test/e2e"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func testAllowedRoutesChange(namespace string, gateway types.NamespacedName) {
+	Specify("routes become attached when the listener starts allowing their namespace", func() {
+		t := f.T()
+
+		// The route lives outside the Gateway's namespace, which
+		// the listener's "Same" policy initially rejects.
+		otherNamespace := namespace + "-routes"
+		f.CreateNamespace(otherNamespace)
+		defer f.DeleteNamespace(otherNamespace, false)
+
+		f.Fixtures.Echo.Deploy(otherNamespace, "echo")
+
+		route := &gatewayapi_v1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: otherNamespace,
+				Name:      "allowed-routes-change",
+			},
+			Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+				Hostnames: []gatewayapi_v1beta1.Hostname{"allowedroutes.gateway.projectcontour.io"},
+				CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+					ParentRefs: []gatewayapi_v1beta1.ParentReference{
+						gatewayapi.GatewayParentRef(gateway.Namespace, gateway.Name),
+					},
+				},
+				Rules: []gatewayapi_v1beta1.HTTPRouteRule{
+					{
+						Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
+						BackendRefs: gatewayapi.HTTPBackendRef("echo", 80, 1),
+					},
+				},
+			},
+		}
+		_, ok := f.CreateHTTPRouteAndWaitFor(route, httpRouteNotAllowedByListeners)
+		require.True(t, ok, "route was not rejected by the listener")
+
+		res, err := f.HTTP.Request(&e2e.HTTPRequestOpts{
+			Host: string(route.Spec.Hostnames[0]),
+		})
+		require.NoError(t, err)
+		require.Equal(t, 404, res.StatusCode)
+
+		// Allow routes from all namespaces on the existing listener.
+		require.NoError(t, retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+			gw := &gatewayapi_v1beta1.Gateway{}
+			if err := f.Client.Get(context.TODO(), client.ObjectKey{Namespace: gateway.Namespace, Name: gateway.Name}, gw); err != nil {
+				return err
+			}
+
+			gw.Spec.Listeners[0].AllowedRoutes.Namespaces.From = ref.To(gatewayapi_v1beta1.NamespacesFromAll)
+
+			return f.Client.Update(context.TODO(), gw)
+		}))
+
+		require.Eventually(t, func() bool {
+			current := &gatewayapi_v1beta1.HTTPRoute{}
+			if err := f.Client.Get(context.TODO(), client.ObjectKeyFromObject(route), current); err != nil {
+				return false
+			}
+			return httpRouteAccepted(current)
+		}, f.RetryTimeout, f.RetryInterval, "route was not accepted after the listener allowed all namespaces")
+
+		res, ok = f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+			Host:      string(route.Spec.Hostnames[0]),
+			Condition: e2e.HasStatusCode(200),
+		})
+		require.NotNil(t, res, "request never succeeded")
+		require.Truef(t, ok, "expected 200 response code, got %d", res.StatusCode)
+		require.Equal(t, "echo", f.GetEchoResponseBody(res.Body).Service)
+	})
+}
+
+// httpRouteNotAllowedByListeners returns true if the route has a
+// .status.conditions entry of "Accepted: false" with reason
+// "NotAllowedByListeners".
+func httpRouteNotAllowedByListeners(route *gatewayapi_v1beta1.HTTPRoute) bool {
+	if route == nil {
+		return false
+	}
+
+	for _, gw := range route.Status.Parents {
+		for _, cond := range gw.Conditions {
+			if cond.Type == string(gatewayapi_v1beta1.RouteConditionAccepted) &&
+				cond.Status == metav1.ConditionFalse &&
+				cond.Reason == string(gatewayapi_v1beta1.RouteReasonNotAllowedByListeners) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,110 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build e2e
+
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/projectcontour/contour/internal/gatewayapi"
+	"github.com/projectcontour/contour/internal/ref"
+	"github.com/projectcontour/contour/test/e2e"
+	"github.com/projectcontour/yages/yages"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func testMixedBackendProtocols(namespace string, gateway types.NamespacedName) {
+	Specify("each backend of a split rule is proxied to with the protocol of its Service port", func() {
+		t := f.T()
+
+		// The gRPC server only speaks h2c, the echo server only HTTP/1.1.
+		f.Fixtures.Echo.Deploy(namespace, "echo")
+		f.Fixtures.GRPC.Deploy(namespace, "grpc-echo")
+
+		grpcService := &corev1.Service{}
+		require.NoError(t, f.Client.Get(context.TODO(), client.ObjectKey{Namespace: namespace, Name: "grpc-echo"}, grpcService))
+		grpcService.Spec.Ports[0].AppProtocol = ref.To("kubernetes.io/h2c")
+		require.NoError(t, f.Client.Update(context.TODO(), grpcService))
+
+		route := &gatewayapi_v1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      "mixed-protocols",
+			},
+			Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+				Hostnames: []gatewayapi_v1beta1.Hostname{"mixed-protocols.gateway.projectcontour.io"},
+				CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+					ParentRefs: []gatewayapi_v1beta1.ParentReference{
+						gatewayapi.GatewayParentRef(gateway.Namespace, gateway.Name),
+					},
+				},
+				Rules: []gatewayapi_v1beta1.HTTPRouteRule{
+					{
+						Matches: gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
+						BackendRefs: gatewayapi.HTTPBackendRefs(
+							gatewayapi.HTTPBackendRef("grpc-echo", 9000, 1),
+							gatewayapi.HTTPBackendRef("echo", 80, 1),
+						),
+					},
+				},
+			},
+		}
+		_, ok := f.CreateHTTPRouteAndWaitFor(route, httpRouteAccepted)
+		require.True(t, ok)
+
+		// Plain HTTP requests that land on the echo server succeed,
+		// so its cluster is not using h2c.
+		res, ok := f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+			Host: string(route.Spec.Hostnames[0]),
+			Condition: func(res *e2e.HTTPResponse) bool {
+				var body e2e.EchoResponseBody
+				return res.StatusCode == 200 && json.Unmarshal(res.Body, &body) == nil && body.Service == "echo"
+			},
+		})
+		require.NotNil(t, res, "request never succeeded")
+		require.Truef(t, ok, "expected a 200 response from echo, got %d", res.StatusCode)
+
+		// gRPC requests that land on the gRPC server succeed, so
+		// its cluster is using h2c.
+		dialCtx, dialCancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer dialCancel()
+		conn, err := grpc.DialContext(dialCtx, strings.TrimPrefix(f.HTTP.HTTPURLBase, "http://"),
+			grpc.WithBlock(),
+			grpc.WithAuthority(string(route.Spec.Hostnames[0])),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		echoClient := yages.NewEchoClient(conn)
+		require.Eventually(t, func() bool {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			resp, err := echoClient.Ping(ctx, &yages.Empty{})
+			return err == nil && resp.Text == "pong"
+		}, f.RetryTimeout, f.RetryInterval, "gRPC request never succeeded")
+	})
+}
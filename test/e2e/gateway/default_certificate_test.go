@@ -0,0 +1,98 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build e2e
+
+package gateway
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/projectcontour/contour/internal/gatewayapi"
+	"github.com/projectcontour/contour/test/e2e"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func testDefaultCertificate(namespace string) {
+	Specify("clients without SNI are served the default certificate", func() {
+		t := f.T()
+
+		for _, tc := range []string{"1", "2"} {
+			f.Fixtures.Echo.Deploy(namespace, "echo-"+tc)
+
+			route := &gatewayapi_v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "httproute-" + tc,
+				},
+				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1beta1.ParentReference{
+							gatewayapi.GatewayListenerParentRef("", "default-certificate", "https-"+tc, 0),
+						},
+					},
+					Rules: []gatewayapi_v1beta1.HTTPRouteRule{
+						{
+							Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
+							BackendRefs: gatewayapi.HTTPBackendRef("echo-"+tc, 80, 1),
+						},
+					},
+				},
+			}
+			_, ok := f.CreateHTTPRouteAndWaitFor(route, httpRouteAccepted)
+			require.True(t, ok, "expected HTTPRoute to be accepted")
+		}
+
+		// The requests are sent to an IP address without SNI, the
+		// handshake only succeeds if the certificate of the "https-2"
+		// listener is served.
+		noSNI := func(c *tls.Config) {
+			c.ServerName = ""
+			c.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+				cert, err := x509.ParseCertificate(rawCerts[0])
+				if err != nil {
+					return err
+				}
+				for _, name := range cert.DNSNames {
+					if name == "https-2.gateway.projectcontour.io" {
+						return nil
+					}
+				}
+				return fmt.Errorf("expected the default certificate, got one for %v", cert.DNSNames)
+			}
+		}
+
+		res, ok := f.HTTP.SecureRequestUntil(&e2e.HTTPSRequestOpts{
+			Host:          "https-2.gateway.projectcontour.io",
+			TLSConfigOpts: []func(*tls.Config){noSNI},
+			Condition:     e2e.HasStatusCode(200),
+		})
+		require.NotNil(t, res, "request never succeeded")
+		require.Truef(t, ok, "expected 200 response code, got %d", res.StatusCode)
+		require.Equal(t, "echo-2", f.GetEchoResponseBody(res.Body).Service)
+
+		// The other listener's hostnames can't be reached without SNI.
+		res, ok = f.HTTP.SecureRequestUntil(&e2e.HTTPSRequestOpts{
+			Host:          "https-1.gateway.projectcontour.io",
+			TLSConfigOpts: []func(*tls.Config){noSNI},
+			Condition:     e2e.HasStatusCode(404),
+		})
+		require.NotNil(t, res, "request never succeeded")
+		require.Truef(t, ok, "expected 404 response code, got %d", res.StatusCode)
+	})
+}
@@ -0,0 +1,93 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build e2e
+
+package gateway
+
+import (
+	"net/http"
+	"net/url"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/projectcontour/contour/internal/gatewayapi"
+	"github.com/projectcontour/contour/internal/ref"
+	"github.com/projectcontour/contour/test/e2e"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func testHTTPRouteFilterOrdering(namespace string, gateway types.NamespacedName) {
+	Specify("header modification listed before a redirect is applied first", func() {
+		t := f.T()
+
+		route := &gatewayapi_v1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      "httproute-filter-ordering",
+			},
+			Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+				Hostnames: []gatewayapi_v1beta1.Hostname{"filterordering.gateway.projectcontour.io"},
+				CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+					ParentRefs: []gatewayapi_v1beta1.ParentReference{
+						gatewayapi.GatewayParentRef(gateway.Namespace, gateway.Name),
+					},
+				},
+				Rules: []gatewayapi_v1beta1.HTTPRouteRule{
+					{
+						Matches: gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/ordering"),
+						Filters: []gatewayapi_v1beta1.HTTPRouteFilter{
+							{
+								Type: gatewayapi_v1beta1.HTTPRouteFilterRequestHeaderModifier,
+								RequestHeaderModifier: &gatewayapi_v1beta1.HTTPHeaderFilter{
+									Set: []gatewayapi_v1beta1.HTTPHeader{
+										{Name: "Host", Value: "modified.projectcontour.io"},
+									},
+								},
+							},
+							{
+								Type: gatewayapi_v1beta1.HTTPRouteFilterRequestRedirect,
+								RequestRedirect: &gatewayapi_v1beta1.HTTPRequestRedirectFilter{
+									StatusCode: ref.To(302),
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		f.CreateHTTPRouteAndWaitFor(route, httpRouteAccepted)
+
+		// The redirect doesn't specify a host name, so the
+		// client is redirected to the Host set by the
+		// RequestHeaderModifier filter.
+		res, ok := f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+			Host: string(route.Spec.Hostnames[0]),
+			Path: "/ordering",
+			ClientOpts: []func(*http.Client){
+				e2e.OptDontFollowRedirects,
+			},
+			Condition: e2e.HasStatusCode(302),
+		})
+		require.NotNil(t, res, "request never succeeded")
+		require.Truef(t, ok, "expected 302 response code, got %d", res.StatusCode)
+
+		location, err := url.Parse(res.Headers.Get("Location"))
+		require.NoError(t, err)
+		assert.Equal(t, "modified.projectcontour.io", location.Hostname())
+		assert.Equal(t, "/ordering", location.Path)
+	})
+}
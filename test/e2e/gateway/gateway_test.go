@@ -34,6 +34,7 @@ import (
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	gatewayapi_v1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
@@ -199,19 +200,45 @@ var _ = Describe("Gateway API", func() {
 
 		f.NamespacedTest("gateway-query-param-match", testWithHTTPGateway(testGatewayMultipleQueryParamMatch))
 
+		f.NamespacedTest("gateway-header-canary", testWithHTTPGateway(testHeaderCanary))
+
 		f.NamespacedTest("gateway-request-header-modifier-backendref-filter", testWithHTTPGateway(testRequestHeaderModifierBackendRef))
 
+		f.NamespacedTest("gateway-request-header-modifier-dynamic-values", testWithHTTPGateway(testRequestHeaderModifierDynamicValues))
+
 		f.NamespacedTest("gateway-response-header-modifier-backendref-filter", testWithHTTPGateway(testResponseHeaderModifierBackendRef))
 
 		f.NamespacedTest("gateway-host-rewrite", testWithHTTPGateway(testHostRewrite))
 
 		f.NamespacedTest("gateway-request-redirect-rule", testWithHTTPGateway(testRequestRedirectRule))
 
+		f.NamespacedTest("gateway-httproute-filter-ordering", testWithHTTPGateway(testHTTPRouteFilterOrdering))
+
 		f.NamespacedTest("gateway-request-mirror-rule", testWithHTTPGateway(testRequestMirrorRule))
+
+		f.NamespacedTest("gateway-request-mirror-percentage", testWithHTTPGateway(testRequestMirrorPercentage))
+
+		f.NamespacedTest("gateway-request-body-limit", testWithHTTPGateway(testRequestBodyLimit))
+
+		f.NamespacedTest("gateway-request-hedging", testWithHTTPGateway(testRequestHedging))
+
+		f.NamespacedTest("gateway-retry-budget", testWithHTTPGateway(testRetryBudget))
+
+		f.NamespacedTest("gateway-allowed-routes-change", testWithHTTPGateway(testAllowedRoutesChange))
+
+		f.NamespacedTest("gateway-service-parent-ref", testWithHTTPGateway(testServiceParentRef))
+
+		f.NamespacedTest("gateway-httproute-mixed-backend-protocols", testWithHTTPGateway(testMixedBackendProtocols))
+
+		f.NamespacedTest("gateway-grpcroute-filters", testWithHTTPGateway(testGRPCRouteFilters))
+
+		f.NamespacedTest("gateway-max-requests-per-connection", testWithHTTPGateway(testMaxRequestsPerConnection))
+
+		f.NamespacedTest("gateway-invalid-listener-port", testWithHTTPGateway(testInvalidListenerPort))
 	})
 
 	Describe("Gateway with one HTTP listener and one HTTPS listener", func() {
-		testWithHTTPSGateway := func(hostname string, body e2e.NamespacedGatewayTestBody) e2e.NamespacedTestBody {
+		testWithHTTPSGatewayOptions := func(hostname string, options map[gatewayapi_v1beta1.AnnotationKey]gatewayapi_v1beta1.AnnotationValue, body e2e.NamespacedGatewayTestBody) e2e.NamespacedTestBody {
 			gatewayClass := getGatewayClass()
 
 			gw := &gatewayapi_v1beta1.Gateway{
@@ -242,6 +269,7 @@ var _ = Describe("Gateway API", func() {
 								CertificateRefs: []gatewayapi_v1beta1.SecretObjectReference{
 									gatewayapi.CertificateRef("tlscert", ""),
 								},
+								Options: options,
 							},
 							AllowedRoutes: &gatewayapi_v1beta1.AllowedRoutes{
 								Kinds: []gatewayapi_v1beta1.RouteGroupKind{
@@ -266,9 +294,20 @@ var _ = Describe("Gateway API", func() {
 			})
 		}
 
+		testWithHTTPSGateway := func(hostname string, body e2e.NamespacedGatewayTestBody) e2e.NamespacedTestBody {
+			return testWithHTTPSGatewayOptions(hostname, nil, body)
+		}
+
 		f.NamespacedTest("gateway-httproute-tls-gateway", testWithHTTPSGateway("tls-gateway.projectcontour.io", testTLSGateway))
 
 		f.NamespacedTest("gateway-httproute-tls-wildcard-host", testWithHTTPSGateway("*.wildcardhost.gateway.projectcontour.io", testTLSWildcardHost))
+
+		f.NamespacedTest("gateway-httproute-tls-options", testWithHTTPSGatewayOptions("tls-options.projectcontour.io",
+			map[gatewayapi_v1beta1.AnnotationKey]gatewayapi_v1beta1.AnnotationValue{
+				gatewayapi.TLSOptionOCSPStaplePolicy: gatewayapi.OCSPStaplePolicyMustStaple,
+				gatewayapi.TLSOptionSessionTickets:   "disabled",
+			},
+			testTLSOptions))
 	})
 
 	Describe("Gateway with multiple HTTPS listeners, each with a different hostname and TLS cert", func() {
@@ -355,6 +394,120 @@ var _ = Describe("Gateway API", func() {
 
 		f.NamespacedTest("gateway-multiple-https-listeners", testWithMultipleHTTPSListenersGateway(testMultipleHTTPSListeners))
 	})
+
+	Describe("Gateway with multiple HTTPS listeners, one of them with the default certificate", func() {
+		testWithDefaultCertificateGateway := func(body e2e.NamespacedTestBody) e2e.NamespacedTestBody {
+			gatewayClass := getGatewayClass()
+			gateway := &gatewayapi_v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "default-certificate",
+				},
+				Spec: gatewayapi_v1beta1.GatewaySpec{
+					GatewayClassName: gatewayapi_v1beta1.ObjectName(gatewayClass.Name),
+					Listeners: []gatewayapi_v1beta1.Listener{
+						{
+							Name:     "https-1",
+							Protocol: gatewayapi_v1beta1.HTTPSProtocolType,
+							Port:     gatewayapi_v1beta1.PortNumber(443),
+							Hostname: ref.To(gatewayapi_v1beta1.Hostname("https-1.gateway.projectcontour.io")),
+							TLS: &gatewayapi_v1beta1.GatewayTLSConfig{
+								CertificateRefs: []gatewayapi_v1beta1.SecretObjectReference{
+									gatewayapi.CertificateRef("tlscert-1", ""),
+								},
+							},
+							AllowedRoutes: &gatewayapi_v1beta1.AllowedRoutes{
+								Kinds: []gatewayapi_v1beta1.RouteGroupKind{
+									{Kind: "HTTPRoute"},
+								},
+								Namespaces: &gatewayapi_v1beta1.RouteNamespaces{
+									From: ref.To(gatewayapi_v1beta1.NamespacesFromSame),
+								},
+							},
+						},
+						{
+							Name:     "https-2",
+							Protocol: gatewayapi_v1beta1.HTTPSProtocolType,
+							Port:     gatewayapi_v1beta1.PortNumber(443),
+							Hostname: ref.To(gatewayapi_v1beta1.Hostname("https-2.gateway.projectcontour.io")),
+							TLS: &gatewayapi_v1beta1.GatewayTLSConfig{
+								CertificateRefs: []gatewayapi_v1beta1.SecretObjectReference{
+									gatewayapi.CertificateRef("tlscert-2", ""),
+								},
+								Options: map[gatewayapi_v1beta1.AnnotationKey]gatewayapi_v1beta1.AnnotationValue{
+									gatewayapi.TLSOptionDefaultCertificate: "true",
+								},
+							},
+							AllowedRoutes: &gatewayapi_v1beta1.AllowedRoutes{
+								Kinds: []gatewayapi_v1beta1.RouteGroupKind{
+									{Kind: "HTTPRoute"},
+								},
+								Namespaces: &gatewayapi_v1beta1.RouteNamespaces{
+									From: ref.To(gatewayapi_v1beta1.NamespacesFromSame),
+								},
+							},
+						},
+					},
+				},
+			}
+
+			return testWithGateway(gateway, gatewayClass, func(namespace string, gateway types.NamespacedName) {
+				BeforeEach(func() {
+					f.Certs.CreateSelfSignedCert(namespace, "tlscert-1", "tlscert-1", "https-1.gateway.projectcontour.io")
+					f.Certs.CreateSelfSignedCert(namespace, "tlscert-2", "tlscert-2", "https-2.gateway.projectcontour.io")
+				})
+
+				body(namespace)
+			})
+		}
+
+		f.NamespacedTest("gateway-default-certificate", testWithDefaultCertificateGateway(testDefaultCertificate))
+	})
+
+	Describe("Gateway with one TLS listener in Terminate mode", func() {
+		testWithTLSTerminateGateway := func(body e2e.NamespacedGatewayTestBody) e2e.NamespacedTestBody {
+			gatewayClass := getGatewayClass()
+
+			gw := &gatewayapi_v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "tls-terminate",
+				},
+				Spec: gatewayapi_v1beta1.GatewaySpec{
+					GatewayClassName: gatewayapi_v1beta1.ObjectName(gatewayClass.Name),
+					Listeners: []gatewayapi_v1beta1.Listener{
+						{
+							Name:     "tls",
+							Protocol: gatewayapi_v1beta1.TLSProtocolType,
+							Port:     gatewayapi_v1beta1.PortNumber(443),
+							TLS: &gatewayapi_v1beta1.GatewayTLSConfig{
+								Mode: ref.To(gatewayapi_v1beta1.TLSModeTerminate),
+								CertificateRefs: []gatewayapi_v1beta1.SecretObjectReference{
+									gatewayapi.CertificateRef("tlscert", ""),
+								},
+							},
+							AllowedRoutes: &gatewayapi_v1beta1.AllowedRoutes{
+								Kinds: []gatewayapi_v1beta1.RouteGroupKind{
+									{Kind: "TLSRoute"},
+								},
+								Namespaces: &gatewayapi_v1beta1.RouteNamespaces{
+									From: ref.To(gatewayapi_v1beta1.NamespacesFromSame),
+								},
+							},
+						},
+					},
+				},
+			}
+
+			return testWithGateway(gw, gatewayClass, func(namespace string, gateway types.NamespacedName) {
+				BeforeEach(func() {
+					f.Certs.CreateSelfSignedCert(namespace, "tlscert", "tlscert", "tls-terminate.gateway.projectcontour.io")
+				})
+
+				body(namespace, gateway)
+			})
+		}
+
+		f.NamespacedTest("gateway-tlsroute-terminate", testWithTLSTerminateGateway(testTLSRouteTerminate))
+	})
 })
 
 // httpRouteAccepted returns true if the route has a .status.conditions
@@ -375,6 +528,42 @@ func httpRouteAccepted(route *gatewayapi_v1beta1.HTTPRoute) bool {
 	return false
 }
 
+// grpcRouteAccepted returns true if the route has a .status.conditions
+// entry of "Accepted: true".
+func grpcRouteAccepted(route *gatewayapi_v1alpha2.GRPCRoute) bool {
+	if route == nil {
+		return false
+	}
+
+	for _, gw := range route.Status.Parents {
+		for _, cond := range gw.Conditions {
+			if cond.Type == string(gatewayapi_v1beta1.RouteConditionAccepted) && cond.Status == metav1.ConditionTrue {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// tlsRouteAccepted returns true if the route has a .status.conditions
+// entry of "Accepted: true".
+func tlsRouteAccepted(route *gatewayapi_v1alpha2.TLSRoute) bool {
+	if route == nil {
+		return false
+	}
+
+	for _, gw := range route.Status.Parents {
+		for _, cond := range gw.Conditions {
+			if cond.Type == string(gatewayapi_v1beta1.RouteConditionAccepted) && cond.Status == metav1.ConditionTrue {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 // gatewayProgrammed returns true if the gateway has a .status.conditions
 // entry of "Programmed: true".
 func gatewayProgrammed(gateway *gatewayapi_v1beta1.Gateway) bool {
@@ -0,0 +1,136 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build e2e
+
+package gateway
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/projectcontour/contour/internal/gatewayapi"
+	"github.com/projectcontour/contour/test/e2e"
+	"github.com/projectcontour/yages/yages"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayapi_v1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func testGRPCRouteFilters(namespace string, gateway types.NamespacedName) {
+	Specify("GRPCRoute header modifier and request mirror filters are applied", func() {
+		t := f.T()
+
+		f.Fixtures.GRPC.Deploy(namespace, "grpc-echo")
+		f.Fixtures.GRPC.Deploy(namespace, "grpc-echo-shadow")
+
+		route := &gatewayapi_v1alpha2.GRPCRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      "grpcroute-filters",
+			},
+			Spec: gatewayapi_v1alpha2.GRPCRouteSpec{
+				Hostnames: []gatewayapi_v1beta1.Hostname{"grpcroute-filters.gateway.projectcontour.io"},
+				CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+					ParentRefs: []gatewayapi_v1beta1.ParentReference{
+						gatewayapi.GatewayParentRef(gateway.Namespace, gateway.Name),
+					},
+				},
+				Rules: []gatewayapi_v1alpha2.GRPCRouteRule{
+					{
+						Matches: []gatewayapi_v1alpha2.GRPCRouteMatch{
+							{Method: gatewayapi.GRPCMethodMatch(gatewayapi_v1alpha2.GRPCMethodMatchExact, "yages.Echo", "Ping")},
+						},
+						Filters: []gatewayapi_v1alpha2.GRPCRouteFilter{
+							{
+								Type: gatewayapi_v1alpha2.GRPCRouteFilterRequestHeaderModifier,
+								RequestHeaderModifier: &gatewayapi_v1beta1.HTTPHeaderFilter{
+									Set: []gatewayapi_v1beta1.HTTPHeader{{Name: "x-request-metadata", Value: "set"}},
+								},
+							},
+							{
+								Type: gatewayapi_v1alpha2.GRPCRouteFilterResponseHeaderModifier,
+								ResponseHeaderModifier: &gatewayapi_v1beta1.HTTPHeaderFilter{
+									Add: []gatewayapi_v1beta1.HTTPHeader{{Name: "x-response-metadata", Value: "added"}},
+								},
+							},
+							{
+								Type: gatewayapi_v1alpha2.GRPCRouteFilterRequestMirror,
+								RequestMirror: &gatewayapi_v1beta1.HTTPRequestMirrorFilter{
+									BackendRef: gatewayapi.ServiceBackendObjectRef("grpc-echo-shadow", 9000),
+								},
+							},
+						},
+						BackendRefs: gatewayapi.GRPCRouteBackendRef("grpc-echo", 9000, 1),
+					},
+				},
+			},
+		}
+		_, ok := f.CreateGRPCRouteAndWaitFor(route, grpcRouteAccepted)
+		require.True(t, ok)
+
+		dialCtx, dialCancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer dialCancel()
+		conn, err := grpc.DialContext(dialCtx, strings.TrimPrefix(f.HTTP.HTTPURLBase, "http://"),
+			grpc.WithBlock(),
+			grpc.WithAuthority(string(route.Spec.Hostnames[0])),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		// The call succeeds, so the gRPC status in the response trailers
+		// is proxied unchanged, and the response metadata is added.
+		echoClient := yages.NewEchoClient(conn)
+		require.Eventually(t, func() bool {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			var header metadata.MD
+			resp, err := echoClient.Ping(ctx, &yages.Empty{}, grpc.Header(&header))
+			if err != nil || resp.Text != "pong" {
+				return false
+			}
+			values := header.Get("x-response-metadata")
+			return len(values) == 1 && values[0] == "added"
+		}, f.RetryTimeout, f.RetryInterval, "gRPC request never succeeded with the added response metadata")
+
+		// Envoy counts the successful requests it mirrored to the shadow
+		// service, which is only reachable over h2c.
+		stat := "cluster." + namespace + "_grpc-echo-shadow_9000.upstream_rq_2xx"
+		mirrored := regexp.MustCompile(regexp.QuoteMeta(stat) + `: (\d+)`)
+
+		res, ok := f.HTTP.MetricsRequestUntil(&e2e.HTTPRequestOpts{
+			Path: "/stats?filter=" + stat,
+			Condition: func(res *e2e.HTTPResponse) bool {
+				m := mirrored.FindSubmatch(res.Body)
+				if m == nil {
+					return false
+				}
+				n, err := strconv.Atoi(string(m[1]))
+				return err == nil && n > 0
+			},
+		})
+		require.NotNil(t, res, "request never succeeded")
+		require.Truef(t, ok, "expected requests to be mirrored to grpc-echo-shadow, got %q", res.Body)
+	})
+}
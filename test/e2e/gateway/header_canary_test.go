@@ -0,0 +1,120 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build e2e
+
+package gateway
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/projectcontour/contour/internal/gatewayapi"
+	"github.com/projectcontour/contour/internal/ref"
+	"github.com/projectcontour/contour/test/e2e"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func testHeaderCanary(namespace string, gateway types.NamespacedName) {
+	Specify("a header steers requests to the canary HTTPRoute's backend", func() {
+		t := f.T()
+
+		f.Fixtures.Echo.Deploy(namespace, "echo-stable")
+		f.Fixtures.Echo.Deploy(namespace, "echo-canary")
+
+		stable := &gatewayapi_v1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      "stable",
+			},
+			Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+				Hostnames: []gatewayapi_v1beta1.Hostname{"headercanary.gateway.projectcontour.io"},
+				CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+					ParentRefs: []gatewayapi_v1beta1.ParentReference{
+						gatewayapi.GatewayParentRef(gateway.Namespace, gateway.Name),
+					},
+				},
+				Rules: []gatewayapi_v1beta1.HTTPRouteRule{
+					{
+						Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
+						BackendRefs: gatewayapi.HTTPBackendRef("echo-stable", 80, 1),
+					},
+				},
+			},
+		}
+		_, ok := f.CreateHTTPRouteAndWaitFor(stable, httpRouteAccepted)
+		require.True(t, ok)
+
+		canary := &gatewayapi_v1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      "canary",
+			},
+			Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+				Hostnames: stable.Spec.Hostnames,
+				CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+					ParentRefs: []gatewayapi_v1beta1.ParentReference{
+						gatewayapi.GatewayParentRef(gateway.Namespace, gateway.Name),
+					},
+				},
+				Rules: []gatewayapi_v1beta1.HTTPRouteRule{
+					{
+						Matches: []gatewayapi_v1beta1.HTTPRouteMatch{
+							{
+								Path: &gatewayapi_v1beta1.HTTPPathMatch{
+									Type:  ref.To(gatewayapi_v1beta1.PathMatchPathPrefix),
+									Value: ref.To("/"),
+								},
+								Headers: gatewayapi.HTTPHeaderMatch(gatewayapi_v1beta1.HeaderMatchExact, "canary", "true"),
+							},
+						},
+						BackendRefs: gatewayapi.HTTPBackendRef("echo-canary", 80, 1),
+					},
+				},
+			},
+		}
+		_, ok = f.CreateHTTPRouteAndWaitFor(canary, httpRouteAccepted)
+		require.True(t, ok)
+
+		// Requests with the header are routed to the canary backend.
+		res, ok := f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+			Host:        string(stable.Spec.Hostnames[0]),
+			RequestOpts: []func(*http.Request){e2e.OptSetHeaders(map[string]string{"Canary": "true"})},
+			Condition: func(res *e2e.HTTPResponse) bool {
+				return e2e.HasStatusCode(200)(res) && f.GetEchoResponseBody(res.Body).Service == "echo-canary"
+			},
+		})
+		require.NotNil(t, res)
+		require.True(t, ok, "expected requests with the canary header to be routed to echo-canary")
+
+		// Requests without the header, or with another value, are
+		// routed to the stable backend.
+		for _, headers := range []map[string]string{nil, {"Canary": "false"}} {
+			res, ok := f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+				Host:        string(stable.Spec.Hostnames[0]),
+				RequestOpts: []func(*http.Request){e2e.OptSetHeaders(headers)},
+				Condition:   e2e.HasStatusCode(200),
+			})
+			require.NotNil(t, res)
+			require.Truef(t, ok, "expected 200 response code, got %d", res.StatusCode)
+
+			body := f.GetEchoResponseBody(res.Body)
+			assert.Equal(t, namespace, body.Namespace)
+			assert.Equal(t, "echo-stable", body.Service)
+		}
+	})
+}
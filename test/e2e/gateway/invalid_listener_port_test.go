@@ -0,0 +1,72 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build e2e
+
+package gateway
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func testInvalidListenerPort(namespace string, gateway types.NamespacedName) {
+	Specify("listeners with a port outside 1-65535 are not provisioned", func() {
+		t := f.T()
+
+		gw := &gatewayapi_v1beta1.Gateway{}
+		require.NoError(t, f.Client.Get(context.Background(), gateway, gw))
+
+		// The Gateway API CRDs reject the ports outright. Contour
+		// marks the listeners as invalid if they get past the
+		// schema validation.
+		for _, port := range []gatewayapi_v1beta1.PortNumber{0, 65536} {
+			invalid := &gatewayapi_v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "invalid-listener-port",
+				},
+				Spec: gatewayapi_v1beta1.GatewaySpec{
+					GatewayClassName: gw.Spec.GatewayClassName,
+					Listeners: []gatewayapi_v1beta1.Listener{
+						{
+							Name:     "http",
+							Protocol: gatewayapi_v1beta1.HTTPProtocolType,
+							Port:     port,
+						},
+					},
+				},
+			}
+			err := f.Client.Create(context.Background(), invalid)
+			require.Errorf(t, err, "expected a Gateway with listener port %d to be rejected", port)
+			assert.Contains(t, err.Error(), "spec.listeners[0].port")
+		}
+
+		// Updating the listener of a programmed Gateway to an
+		// invalid port is rejected too, and the Gateway stays
+		// programmed.
+		gw.Spec.Listeners[0].Port = 0
+		err := f.Client.Update(context.Background(), gw)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "spec.listeners[0].port")
+
+		require.NoError(t, f.Client.Get(context.Background(), gateway, gw))
+		assert.True(t, gatewayProgrammed(gw))
+	})
+}
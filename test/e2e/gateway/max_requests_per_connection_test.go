@@ -0,0 +1,99 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build e2e
+
+package gateway
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/projectcontour/contour/internal/gatewayapi"
+	"github.com/projectcontour/contour/test/e2e"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func testMaxRequestsPerConnection(namespace string, gateway types.NamespacedName) {
+	Specify("upstream connections are closed after the Service's max requests per connection", func() {
+		t := f.T()
+
+		f.Fixtures.Echo.Deploy(namespace, "echo")
+
+		echoService := &corev1.Service{}
+		require.NoError(t, f.Client.Get(context.TODO(), client.ObjectKey{Namespace: namespace, Name: "echo"}, echoService))
+		if echoService.Annotations == nil {
+			echoService.Annotations = map[string]string{}
+		}
+		echoService.Annotations["projectcontour.io/max-requests-per-connection"] = "2"
+		require.NoError(t, f.Client.Update(context.TODO(), echoService))
+
+		route := &gatewayapi_v1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      "max-requests-per-connection",
+			},
+			Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+				Hostnames: []gatewayapi_v1beta1.Hostname{"max-requests-per-connection.gateway.projectcontour.io"},
+				CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+					ParentRefs: []gatewayapi_v1beta1.ParentReference{
+						gatewayapi.GatewayParentRef(gateway.Namespace, gateway.Name),
+					},
+				},
+				Rules: []gatewayapi_v1beta1.HTTPRouteRule{
+					{
+						Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
+						BackendRefs: gatewayapi.HTTPBackendRef("echo", 80, 1),
+					},
+				},
+			},
+		}
+		_, ok := f.CreateHTTPRouteAndWaitFor(route, httpRouteAccepted)
+		require.True(t, ok)
+
+		for i := 0; i < 10; i++ {
+			res, ok := f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+				Host:      string(route.Spec.Hostnames[0]),
+				Condition: e2e.HasStatusCode(200),
+			})
+			require.NotNil(t, res, "request never succeeded")
+			require.Truef(t, ok, "expected 200 response code, got %d", res.StatusCode)
+		}
+
+		// Envoy counts the upstream connections it closed because
+		// they reached their maximum number of requests.
+		stat := "cluster." + namespace + "_echo_80.upstream_cx_max_requests"
+		closed := regexp.MustCompile(regexp.QuoteMeta(stat) + `: (\d+)`)
+
+		res, ok := f.HTTP.MetricsRequestUntil(&e2e.HTTPRequestOpts{
+			Path: "/stats?filter=" + stat,
+			Condition: func(res *e2e.HTTPResponse) bool {
+				m := closed.FindSubmatch(res.Body)
+				if m == nil {
+					return false
+				}
+				n, err := strconv.Atoi(string(m[1]))
+				return err == nil && n > 0
+			},
+		})
+		require.NotNil(t, res, "request never succeeded")
+		require.Truef(t, ok, "expected upstream connections to be closed after 2 requests, got %q", res.Body)
+	})
+}
@@ -0,0 +1,104 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build e2e
+
+package gateway
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/projectcontour/contour/internal/gatewayapi"
+	"github.com/projectcontour/contour/test/e2e"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func testRequestBodyLimit(namespace string, gateway types.NamespacedName) {
+	Specify("request bodies larger than the route limit are rejected", func() {
+		t := f.T()
+
+		f.Fixtures.Echo.Deploy(namespace, "echo")
+
+		route := &gatewayapi_v1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      "request-body-limit",
+				Annotations: map[string]string{
+					"projectcontour.io/max-request-body-bytes": "1024",
+				},
+			},
+			Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+				Hostnames: []gatewayapi_v1beta1.Hostname{"requestbodylimit.gateway.projectcontour.io"},
+				CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+					ParentRefs: []gatewayapi_v1beta1.ParentReference{
+						gatewayapi.GatewayParentRef(gateway.Namespace, gateway.Name),
+					},
+				},
+				Rules: []gatewayapi_v1beta1.HTTPRouteRule{
+					{
+						Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
+						BackendRefs: gatewayapi.HTTPBackendRef("echo", 80, 1),
+					},
+				},
+			},
+		}
+		f.CreateHTTPRouteAndWaitFor(route, httpRouteAccepted)
+
+		// Wait for the route to be programmed. The body limit is
+		// part of the same configuration update.
+		res, ok := f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+			Host:      string(route.Spec.Hostnames[0]),
+			Condition: e2e.HasStatusCode(200),
+		})
+		require.NotNil(t, res, "request never succeeded")
+		require.Truef(t, ok, "expected 200 response code, got %d", res.StatusCode)
+
+		post := e2e.OptSetMethod(http.MethodPost)
+
+		// A body under the limit is proxied to the backend.
+		res, err := f.HTTP.Request(&e2e.HTTPRequestOpts{
+			Host:        string(route.Spec.Hostnames[0]),
+			Body:        bytes.NewReader(bytes.Repeat([]byte("a"), 512)),
+			RequestOpts: []func(*http.Request){post},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 200, res.StatusCode)
+		assert.Equal(t, "echo", f.GetEchoResponseBody(res.Body).Service)
+
+		// A body over the limit is rejected.
+		res, err = f.HTTP.Request(&e2e.HTTPRequestOpts{
+			Host:        string(route.Spec.Hostnames[0]),
+			Body:        bytes.NewReader(bytes.Repeat([]byte("a"), 2048)),
+			RequestOpts: []func(*http.Request){post},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 413, res.StatusCode)
+
+		// A chunked upload with no Content-Length is rejected once
+		// the limit has been crossed.
+		res, err = f.HTTP.Request(&e2e.HTTPRequestOpts{
+			Host:        string(route.Spec.Hostnames[0]),
+			Body:        io.NopCloser(bytes.NewReader(bytes.Repeat([]byte("a"), 64*1024))),
+			RequestOpts: []func(*http.Request){post},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 413, res.StatusCode)
+	})
+}
@@ -16,6 +16,7 @@
 package gateway
 
 import (
+	"net"
 	"net/http"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -126,3 +127,75 @@ func testRequestHeaderModifierBackendRef(namespace string, gateway types.Namespa
 		assert.False(t, found, "My-Header was found on the response")
 	})
 }
+
+func testRequestHeaderModifierDynamicValues(namespace string, gateway types.NamespacedName) {
+	Specify("request header modifiers support dynamic values", func() {
+		t := f.T()
+
+		f.Fixtures.Echo.Deploy(namespace, "echo-header-dynamic")
+
+		route := &gatewayapi_v1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      "http-filter-dynamic",
+			},
+			Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+				Hostnames: []gatewayapi_v1beta1.Hostname{"requestheadermodifierdynamic.gateway.projectcontour.io"},
+				CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+					ParentRefs: []gatewayapi_v1beta1.ParentReference{
+						gatewayapi.GatewayParentRef(gateway.Namespace, gateway.Name),
+					},
+				},
+				Rules: []gatewayapi_v1beta1.HTTPRouteRule{
+					{
+						Filters: []gatewayapi_v1beta1.HTTPRouteFilter{
+							{
+								Type: gatewayapi_v1beta1.HTTPRouteFilterRequestHeaderModifier,
+								RequestHeaderModifier: &gatewayapi_v1beta1.HTTPHeaderFilter{
+									Set: []gatewayapi_v1beta1.HTTPHeader{
+										{Name: gatewayapi_v1beta1.HTTPHeaderName("Client-Address"), Value: "%DOWNSTREAM_REMOTE_ADDRESS_WITHOUT_PORT%"},
+										{Name: gatewayapi_v1beta1.HTTPHeaderName("Literal-Header"), Value: "100%"},
+									},
+								},
+							},
+						},
+						BackendRefs: []gatewayapi_v1beta1.HTTPBackendRef{
+							{
+								BackendRef: gatewayapi_v1beta1.BackendRef{
+									BackendObjectReference: gatewayapi.ServiceBackendObjectRef("echo-header-dynamic", 80),
+								},
+								Filters: []gatewayapi_v1beta1.HTTPRouteFilter{
+									{
+										Type: gatewayapi_v1beta1.HTTPRouteFilterRequestHeaderModifier,
+										RequestHeaderModifier: &gatewayapi_v1beta1.HTTPHeaderFilter{
+											Set: []gatewayapi_v1beta1.HTTPHeader{
+												{Name: gatewayapi_v1beta1.HTTPHeaderName("Upstream-Service"), Value: "%CONTOUR_SERVICE_NAME%.%CONTOUR_NAMESPACE%:%CONTOUR_SERVICE_PORT%"},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		f.CreateHTTPRouteAndWaitFor(route, httpRouteAccepted)
+
+		res, ok := f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+			Host:      string(route.Spec.Hostnames[0]),
+			Condition: e2e.HasStatusCode(200),
+		})
+		require.NotNil(t, res, "request never succeeded")
+		require.Truef(t, ok, "expected 200 response code, got %d", res.StatusCode)
+		body := f.GetEchoResponseBody(res.Body)
+		assert.Equal(t, "echo-header-dynamic", body.Service)
+
+		// Envoy fills in command operators with the client's address,
+		// while literal values are passed through unchanged.
+		clientAddress := body.RequestHeaders.Get("Client-Address")
+		assert.NotNil(t, net.ParseIP(clientAddress), "Client-Address %q is not an IP address", clientAddress)
+		assert.Equal(t, "100%", body.RequestHeaders.Get("Literal-Header"))
+		assert.Equal(t, "echo-header-dynamic."+namespace+":80", body.RequestHeaders.Get("Upstream-Service"))
+	})
+}
@@ -0,0 +1,153 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build e2e
+
+package gateway
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/projectcontour/contour/internal/gatewayapi"
+	"github.com/projectcontour/contour/internal/ref"
+	"github.com/projectcontour/contour/test/e2e"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func testRequestHedging(namespace string, gateway types.NamespacedName) {
+	Specify("requests to a slow backend are raced by a hedged request", func() {
+		t := f.T()
+
+		f.Fixtures.Echo.Deploy(namespace, "echo")
+
+		// Add a pod to the echo Service that accepts connections
+		// but never responds, so that every request it gets times
+		// out and is only answered by the hedged request.
+		slow := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      "echo-slow",
+			},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ref.To(int32(1)),
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app.kubernetes.io/name": "echo", "app.kubernetes.io/component": "slow"},
+				},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{"app.kubernetes.io/name": "echo", "app.kubernetes.io/component": "slow"},
+					},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{
+							Name:    "slow",
+							Image:   "docker.io/library/busybox:1.36",
+							Command: []string{"nc", "-lk", "-p", "3000", "-e", "sleep", "3600"},
+							Ports: []corev1.ContainerPort{{
+								Name:          "http-api",
+								ContainerPort: 3000,
+							}},
+						}},
+					},
+				},
+			},
+		}
+		require.NoError(t, f.Client.Create(context.Background(), slow))
+		require.Eventually(t, func() bool {
+			if err := f.Client.Get(context.Background(), client.ObjectKeyFromObject(slow), slow); err != nil {
+				return false
+			}
+			return slow.Status.ReadyReplicas == 1
+		}, f.RetryTimeout, f.RetryInterval)
+
+		route := &gatewayapi_v1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      "request-hedging",
+				Annotations: map[string]string{
+					"projectcontour.io/hedge-per-try-timeout": "500ms",
+				},
+			},
+			Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+				Hostnames: []gatewayapi_v1beta1.Hostname{"requesthedging.gateway.projectcontour.io"},
+				CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+					ParentRefs: []gatewayapi_v1beta1.ParentReference{
+						gatewayapi.GatewayParentRef(gateway.Namespace, gateway.Name),
+					},
+				},
+				Rules: []gatewayapi_v1beta1.HTTPRouteRule{
+					{
+						Matches: []gatewayapi_v1beta1.HTTPRouteMatch{{
+							Path: &gatewayapi_v1beta1.HTTPPathMatch{
+								Type:  ref.To(gatewayapi_v1beta1.PathMatchPathPrefix),
+								Value: ref.To("/"),
+							},
+							Method: ref.To(gatewayapi_v1beta1.HTTPMethodGet),
+						}},
+						BackendRefs: gatewayapi.HTTPBackendRef("echo", 80, 1),
+					},
+				},
+			},
+		}
+		f.CreateHTTPRouteAndWaitFor(route, httpRouteAccepted)
+
+		res, ok := f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+			Host:      string(route.Spec.Hostnames[0]),
+			Condition: e2e.HasStatusCode(200),
+		})
+		require.NotNil(t, res, "request never succeeded")
+		require.Truef(t, ok, "expected 200 response code, got %d", res.StatusCode)
+
+		// The requests are balanced over both pods. Without hedging,
+		// the ones sent to the slow pod would time out with a 504.
+		for i := 0; i < 10; i++ {
+			start := time.Now()
+			res, err := f.HTTP.Request(&e2e.HTTPRequestOpts{
+				Host: string(route.Spec.Hostnames[0]),
+			})
+			require.NoError(t, err)
+			assert.Equal(t, 200, res.StatusCode)
+			assert.Equal(t, "echo", f.GetEchoResponseBody(res.Body).Service)
+			assert.Less(t, time.Since(start), 5*time.Second)
+		}
+
+		// The slow first attempts reached their per-try timeout
+		// and were hedged.
+		stat := "cluster." + namespace + "_echo_80.upstream_rq_per_try_timeout"
+		perTryTimeouts := regexp.MustCompile(regexp.QuoteMeta(stat) + `: (\d+)`)
+
+		res, ok = f.HTTP.MetricsRequestUntil(&e2e.HTTPRequestOpts{
+			Path: "/stats?filter=" + stat,
+			Condition: func(res *e2e.HTTPResponse) bool {
+				m := perTryTimeouts.FindSubmatch(res.Body)
+				if m == nil {
+					return false
+				}
+				n, err := strconv.Atoi(string(m[1]))
+				return err == nil && n > 0
+			},
+		})
+		require.NotNil(t, res, "request never succeeded")
+		require.Truef(t, ok, "expected requests to be hedged on their per-try timeout, got %q", res.Body)
+	})
+}
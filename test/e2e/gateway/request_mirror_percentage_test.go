@@ -0,0 +1,116 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build e2e
+
+package gateway
+
+import (
+	"regexp"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/projectcontour/contour/internal/gatewayapi"
+	"github.com/projectcontour/contour/test/e2e"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func testRequestMirrorPercentage(namespace string, gateway types.NamespacedName) {
+	Specify("a percentage of requests can be mirrored", func() {
+		t := f.T()
+
+		f.Fixtures.Echo.Deploy(namespace, "echo-primary")
+		f.Fixtures.Echo.Deploy(namespace, "echo-shadow")
+
+		route := &gatewayapi_v1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      "httproute-mirror-percentage",
+				Annotations: map[string]string{
+					"projectcontour.io/request-mirror-percentage": "20",
+				},
+			},
+			Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+				Hostnames: []gatewayapi_v1beta1.Hostname{"requestmirrorpercentage.gateway.projectcontour.io"},
+				CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+					ParentRefs: []gatewayapi_v1beta1.ParentReference{
+						gatewayapi.GatewayParentRef(gateway.Namespace, gateway.Name),
+					},
+				},
+				Rules: []gatewayapi_v1beta1.HTTPRouteRule{
+					{
+						Matches: gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/mirror-percentage"),
+						Filters: []gatewayapi_v1beta1.HTTPRouteFilter{
+							{
+								Type: gatewayapi_v1beta1.HTTPRouteFilterRequestMirror,
+								RequestMirror: &gatewayapi_v1beta1.HTTPRequestMirrorFilter{
+									BackendRef: gatewayapi.ServiceBackendObjectRef("echo-shadow", 80),
+								},
+							},
+						},
+						BackendRefs: gatewayapi.HTTPBackendRef("echo-primary", 80, 1),
+					},
+				},
+			},
+		}
+		f.CreateHTTPRouteAndWaitFor(route, httpRouteAccepted)
+
+		res, ok := f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+			Host:      string(route.Spec.Hostnames[0]),
+			Path:      "/mirror-percentage",
+			Condition: e2e.HasStatusCode(200),
+		})
+		require.NotNil(t, res, "request never succeeded")
+		require.Truef(t, ok, "expected 200 response code, got %d", res.StatusCode)
+
+		// Send enough requests that the share reaching
+		// "echo-shadow" settles close to the configured 20%.
+		const requests = 500
+		for i := 0; i < requests; i++ {
+			res, err := f.HTTP.Request(&e2e.HTTPRequestOpts{
+				Host: string(route.Spec.Hostnames[0]),
+				Path: "/mirror-percentage",
+			})
+			require.NoError(t, err)
+			require.Equal(t, 200, res.StatusCode)
+		}
+
+		mirrorLogRegexp := regexp.MustCompile(`Echoing back request made to \/mirror-percentage to client`)
+		countMirrored := func() int {
+			logs, err := f.Fixtures.Echo.DumpEchoLogs(namespace, "echo-shadow")
+			if err != nil {
+				return 0
+			}
+
+			var mirrored int
+			for _, log := range logs {
+				mirrored += len(mirrorLogRegexp.FindAll(log, -1))
+			}
+			return mirrored
+		}
+
+		// Mirrored requests are sent asynchronously, so wait for
+		// the count to reach the lower bound before checking it.
+		require.Eventually(t, func() bool {
+			return countMirrored() >= requests/10
+		}, f.RetryTimeout, f.RetryInterval)
+
+		// The requests made before the loop may also have been
+		// mirrored, so allow for them in the upper bound.
+		mirrored := countMirrored()
+		assert.LessOrEqualf(t, mirrored, requests*3/10, "expected roughly 20%% of %d requests to be mirrored, got %d", requests, mirrored)
+	})
+}
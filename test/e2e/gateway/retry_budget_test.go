@@ -0,0 +1,171 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build e2e
+
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/projectcontour/contour/internal/gatewayapi"
+	"github.com/projectcontour/contour/internal/ref"
+	"github.com/projectcontour/contour/test/e2e"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func testRetryBudget(namespace string, gateway types.NamespacedName) {
+	Specify("hedged requests are throttled once the retry budget is exceeded", func() {
+		t := f.T()
+
+		// A backend that accepts connections but never responds,
+		// so that every request reaches its per-try timeout and
+		// is hedged.
+		slow := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      "slow",
+			},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ref.To(int32(1)),
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app.kubernetes.io/name": "slow"},
+				},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{"app.kubernetes.io/name": "slow"},
+					},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{
+							Name:    "slow",
+							Image:   "docker.io/library/busybox:1.36",
+							Command: []string{"nc", "-lk", "-p", "3000", "-e", "sleep", "3600"},
+							Ports: []corev1.ContainerPort{{
+								Name:          "http",
+								ContainerPort: 3000,
+							}},
+						}},
+					},
+				},
+			},
+		}
+		require.NoError(t, f.Client.Create(context.Background(), slow))
+		require.Eventually(t, func() bool {
+			if err := f.Client.Get(context.Background(), client.ObjectKeyFromObject(slow), slow); err != nil {
+				return false
+			}
+			return slow.Status.ReadyReplicas == 1
+		}, f.RetryTimeout, f.RetryInterval)
+
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      "slow",
+			},
+			Spec: corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{{
+					Name:       "http",
+					Port:       80,
+					TargetPort: intstr.FromString("http"),
+				}},
+				Selector: map[string]string{"app.kubernetes.io/name": "slow"},
+			},
+		}
+		require.NoError(t, f.Client.Create(context.Background(), svc))
+
+		route := &gatewayapi_v1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      "retry-budget",
+				Annotations: map[string]string{
+					"projectcontour.io/hedge-per-try-timeout":              "100ms",
+					"projectcontour.io/retry-budget-percent":               "1",
+					"projectcontour.io/retry-budget-min-retry-concurrency": "1",
+				},
+			},
+			Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+				Hostnames: []gatewayapi_v1beta1.Hostname{"retrybudget.gateway.projectcontour.io"},
+				CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+					ParentRefs: []gatewayapi_v1beta1.ParentReference{
+						gatewayapi.GatewayParentRef(gateway.Namespace, gateway.Name),
+					},
+				},
+				Rules: []gatewayapi_v1beta1.HTTPRouteRule{
+					{
+						Matches: []gatewayapi_v1beta1.HTTPRouteMatch{{
+							Path: &gatewayapi_v1beta1.HTTPPathMatch{
+								Type:  ref.To(gatewayapi_v1beta1.PathMatchPathPrefix),
+								Value: ref.To("/"),
+							},
+							Method: ref.To(gatewayapi_v1beta1.HTTPMethodGet),
+						}},
+						BackendRefs: gatewayapi.HTTPBackendRef(svc.Name, 80, 1),
+					},
+				},
+			},
+		}
+		f.CreateHTTPRouteAndWaitFor(route, httpRouteAccepted)
+
+		// Concurrent requests to the slow backend are all hedged,
+		// which needs more concurrent retries than the budget allows.
+		httpClient := &http.Client{Timeout: 2 * time.Second}
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				req, err := http.NewRequest(http.MethodGet, f.HTTP.HTTPURLBase, nil)
+				if err != nil {
+					return
+				}
+				req.Host = string(route.Spec.Hostnames[0])
+
+				if res, err := httpClient.Do(req); err == nil {
+					res.Body.Close()
+				}
+			}()
+		}
+		wg.Wait()
+
+		stat := "cluster." + namespace + "_slow_80.upstream_rq_retry_overflow"
+		overflow := regexp.MustCompile(regexp.QuoteMeta(stat) + `: (\d+)`)
+
+		res, ok := f.HTTP.MetricsRequestUntil(&e2e.HTTPRequestOpts{
+			Path: "/stats?filter=" + stat,
+			Condition: func(res *e2e.HTTPResponse) bool {
+				m := overflow.FindSubmatch(res.Body)
+				if m == nil {
+					return false
+				}
+				n, err := strconv.Atoi(string(m[1]))
+				return err == nil && n > 0
+			},
+		})
+		require.NotNil(t, res, "request never succeeded")
+		require.Truef(t, ok, "expected hedged requests to overflow the retry budget, got %q", res.Body)
+	})
+}
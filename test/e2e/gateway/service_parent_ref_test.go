@@ -0,0 +1,95 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build e2e
+
+package gateway
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/projectcontour/contour/internal/gatewayapi"
+	"github.com/projectcontour/contour/internal/ref"
+	"github.com/projectcontour/contour/test/e2e"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func testServiceParentRef(namespace string, gateway types.NamespacedName) {
+	Specify("a Service parent ref is rejected while the route still attaches to the Gateway", func() {
+		t := f.T()
+
+		f.Fixtures.Echo.Deploy(namespace, "echo")
+
+		serviceParentRef := gatewayapi_v1beta1.ParentReference{
+			Group: ref.To(gatewayapi_v1beta1.Group("")),
+			Kind:  ref.To(gatewayapi_v1beta1.Kind("Service")),
+			Name:  "echo",
+		}
+
+		route := &gatewayapi_v1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      "http-route-1",
+			},
+			Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+				Hostnames: []gatewayapi_v1beta1.Hostname{"service-parent-ref.gateway.projectcontour.io"},
+				CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+					ParentRefs: []gatewayapi_v1beta1.ParentReference{
+						gatewayapi.GatewayParentRef(gateway.Namespace, gateway.Name),
+						serviceParentRef,
+					},
+				},
+				Rules: []gatewayapi_v1beta1.HTTPRouteRule{
+					{
+						Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
+						BackendRefs: gatewayapi.HTTPBackendRef("echo", 80, 1),
+					},
+				},
+			},
+		}
+
+		var serviceParentStatus *gatewayapi_v1beta1.RouteParentStatus
+		route, ok := f.CreateHTTPRouteAndWaitFor(route, func(route *gatewayapi_v1beta1.HTTPRoute) bool {
+			for i, parent := range route.Status.Parents {
+				if parent.ParentRef.Kind != nil && *parent.ParentRef.Kind == "Service" {
+					serviceParentStatus = &route.Status.Parents[i]
+					return httpRouteAccepted(route)
+				}
+			}
+			return false
+		})
+		require.True(t, ok, "route never got a status for the Service parent ref")
+		require.NotNil(t, serviceParentStatus)
+
+		var accepted *metav1.Condition
+		for i, cond := range serviceParentStatus.Conditions {
+			if cond.Type == string(gatewayapi_v1beta1.RouteConditionAccepted) {
+				accepted = &serviceParentStatus.Conditions[i]
+			}
+		}
+		require.NotNil(t, accepted, "Service parent ref has no Accepted condition")
+		assert.Equal(t, metav1.ConditionFalse, accepted.Status)
+		assert.Equal(t, string(gatewayapi_v1beta1.RouteReasonUnsupportedValue), accepted.Reason)
+
+		// The Gateway parent ref is unaffected.
+		res, ok := f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+			Host:      string(route.Spec.Hostnames[0]),
+			Condition: e2e.HasStatusCode(200),
+		})
+		require.NotNil(t, res, "request never succeeded")
+		assert.Truef(t, ok, "expected 200 response code, got %d", res.StatusCode)
+	})
+}
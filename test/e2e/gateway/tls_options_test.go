@@ -0,0 +1,130 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build e2e
+
+package gateway
+
+import (
+	"context"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/gatewayapi"
+	"github.com/projectcontour/contour/internal/ref"
+	"github.com/projectcontour/contour/test/e2e"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func testTLSOptions(namespace string, gateway types.NamespacedName) {
+	Specify("the OCSP response in the TLS secret is stapled to the TLS handshake", func() {
+		t := f.T()
+
+		f.Fixtures.Echo.Deploy(namespace, "echo")
+
+		// Wait for cert-manager to issue the certificate, then add
+		// an OCSP response for it to the secret. The listener uses
+		// the MustStaple policy, so Envoy won't serve the certificate
+		// without it.
+		certSecret := &corev1.Secret{}
+		require.Eventually(t, func() bool {
+			if err := f.Client.Get(context.TODO(), client.ObjectKey{Namespace: namespace, Name: "tlscert"}, certSecret); err != nil {
+				return false
+			}
+			return len(certSecret.Data[corev1.TLSCertKey]) > 0
+		}, f.RetryTimeout, f.RetryInterval)
+
+		cert, err := tls.X509KeyPair(certSecret.Data[corev1.TLSCertKey], certSecret.Data[corev1.TLSPrivateKeyKey])
+		require.NoError(t, err)
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		require.NoError(t, err)
+
+		// The certificate is self-signed, so it is its own issuer
+		// and the OCSP responder.
+		staple, err := ocsp.CreateResponse(leaf, leaf, ocsp.Response{
+			Status:       ocsp.Good,
+			SerialNumber: leaf.SerialNumber,
+			ThisUpdate:   time.Now().Add(-time.Hour),
+			NextUpdate:   time.Now().Add(24 * time.Hour),
+		}, cert.PrivateKey.(crypto.Signer))
+		require.NoError(t, err)
+
+		certSecret.Data[dag.OCSPStapleKey] = staple
+		require.NoError(t, f.Client.Update(context.TODO(), certSecret))
+
+		route := &gatewayapi_v1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      "http-route-1",
+			},
+			Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+				Hostnames: []gatewayapi_v1beta1.Hostname{"tls-options.projectcontour.io"},
+				CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+					ParentRefs: []gatewayapi_v1beta1.ParentReference{
+						{
+							Namespace:   ref.To(gatewayapi_v1beta1.Namespace(gateway.Namespace)),
+							Name:        gatewayapi_v1beta1.ObjectName(gateway.Name),
+							SectionName: ref.To(gatewayapi_v1beta1.SectionName("secure")),
+						},
+					},
+				},
+				Rules: []gatewayapi_v1beta1.HTTPRouteRule{
+					{
+						Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
+						BackendRefs: gatewayapi.HTTPBackendRef("echo", 80, 1),
+					},
+				},
+			},
+		}
+		f.CreateHTTPRouteAndWaitFor(route, httpRouteAccepted)
+
+		// Fail the handshake unless the server staples a good OCSP
+		// response for its certificate.
+		verifyStaple := func(c *tls.Config) {
+			c.VerifyConnection = func(cs tls.ConnectionState) error {
+				if len(cs.OCSPResponse) == 0 {
+					return errors.New("no OCSP response stapled")
+				}
+				resp, err := ocsp.ParseResponseForCert(cs.OCSPResponse, cs.PeerCertificates[0], leaf)
+				if err != nil {
+					return err
+				}
+				if resp.Status != ocsp.Good {
+					return errors.New("stapled OCSP response is not good")
+				}
+				return nil
+			}
+		}
+
+		res, ok := f.HTTP.SecureRequestUntil(&e2e.HTTPSRequestOpts{
+			Host:          "tls-options.projectcontour.io",
+			TLSConfigOpts: []func(*tls.Config){verifyStaple},
+			Condition:     e2e.HasStatusCode(200),
+		})
+		require.NotNil(t, res, "request never succeeded")
+		assert.Truef(t, ok, "expected 200 response code, got %d", res.StatusCode)
+		assert.Equal(t, "echo", f.GetEchoResponseBody(res.Body).Service)
+	})
+}
@@ -0,0 +1,79 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build e2e
+
+package gateway
+
+import (
+	"context"
+	"crypto/tls"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/projectcontour/contour/internal/gatewayapi"
+	"github.com/projectcontour/contour/test/e2e"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapi_v1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+func testTLSRouteTerminate(namespace string, gateway types.NamespacedName) {
+	Specify("TLS is terminated at Envoy and traffic is proxied as TCP to the backend", func() {
+		t := f.T()
+
+		f.Fixtures.Echo.Deploy(namespace, "echo")
+
+		route := &gatewayapi_v1alpha2.TLSRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      "tls-route-1",
+			},
+			Spec: gatewayapi_v1alpha2.TLSRouteSpec{
+				CommonRouteSpec: gatewayapi_v1alpha2.CommonRouteSpec{
+					ParentRefs: []gatewayapi_v1alpha2.ParentReference{
+						gatewayapi.GatewayParentRef(gateway.Namespace, gateway.Name),
+					},
+				},
+				Hostnames: []gatewayapi_v1alpha2.Hostname{"tls-terminate.gateway.projectcontour.io"},
+				Rules: []gatewayapi_v1alpha2.TLSRouteRule{{
+					BackendRefs: gatewayapi.TLSRouteBackendRef("echo", 80, nil),
+				}},
+			},
+		}
+		f.CreateTLSRouteAndWaitFor(route, tlsRouteAccepted)
+
+		certSecret := &corev1.Secret{}
+		require.NoError(t, f.Client.Get(context.TODO(), client.ObjectKey{Namespace: namespace, Name: "tlscert"}, certSecret))
+
+		// The echo backend only speaks plaintext, so a successful
+		// response means TLS was terminated by Envoy and the decrypted
+		// stream was forwarded to the backend.
+		res, ok := f.HTTP.SecureRequestUntil(&e2e.HTTPSRequestOpts{
+			Host: string(route.Spec.Hostnames[0]),
+			TLSConfigOpts: []func(*tls.Config){
+				e2e.VerifyTLSServerCert(certSecret.Data["ca.crt"]),
+			},
+			Condition: e2e.HasStatusCode(200),
+		})
+		require.NotNil(t, res, "request never succeeded")
+		require.Truef(t, ok, "expected 200 response code, got %d", res.StatusCode)
+
+		body := f.GetEchoResponseBody(res.Body)
+		assert.Equal(t, namespace, body.Namespace)
+		assert.Equal(t, "echo", body.Service)
+	})
+}
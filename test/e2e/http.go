@@ -16,8 +16,11 @@
 package e2e
 
 import (
+	"context"
 	"crypto/tls"
+	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"time"
 
@@ -81,6 +84,12 @@ func OptSetHeaders(headers map[string]string) func(*http.Request) {
 	}
 }
 
+func OptSetMethod(method string) func(*http.Request) {
+	return func(r *http.Request) {
+		r.Method = method
+	}
+}
+
 func OptSetQueryParams(queryParams map[string]string) func(*http.Request) {
 	return func(r *http.Request) {
 		q := r.URL.Query()
@@ -134,6 +143,72 @@ func (h *HTTP) RequestUntil(opts *HTTPRequestOpts) (*HTTPResponse, bool) {
 	return h.requestUntil(makeRequest, opts.Condition)
 }
 
+// Request makes a single HTTP request with the provided parameters
+// and returns the HTTP response or an error. Note that opts.Condition is
+// ignored by this method.
+//
+// In general, E2E's should use RequestUntil instead of this method since
+// RequestUntil will retry requests to account for eventual consistency and
+// other ephemeral issues.
+func (h *HTTP) Request(opts *HTTPRequestOpts) (*HTTPResponse, error) {
+	req, err := http.NewRequest(http.MethodGet, opts.requestURLBase(h.HTTPURLBase)+opts.Path, opts.Body)
+	require.NoError(h.t, err, "error creating HTTP request")
+
+	req.Host = opts.Host
+	for _, opt := range opts.RequestOpts {
+		opt(req)
+	}
+
+	r, err := httpClient(opts.ClientOpts...).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	bodyBytes, err := io.ReadAll(r.Body)
+	require.NoError(h.t, err)
+
+	return &HTTPResponse{
+		StatusCode: r.StatusCode,
+		Headers:    r.Header,
+		Body:       bodyBytes,
+	}, nil
+}
+
+// OptProxyProtocol returns a client option that sends a PROXY protocol
+// v1 header, claiming the connection originates from sourceIP, at the
+// start of every connection the client opens. This emulates a load
+// balancer that prepends the PROXY protocol.
+func OptProxyProtocol(sourceIP string) func(*http.Client) {
+	return func(c *http.Client) {
+		transport := c.Transport.(*http.Transport)
+		dialer := &net.Dialer{}
+
+		transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := dialer.DialContext(ctx, network, addr)
+			if err != nil {
+				return nil, err
+			}
+
+			family := "TCP4"
+			if ip := net.ParseIP(sourceIP); ip != nil && ip.To4() == nil {
+				family = "TCP6"
+			}
+			dst := conn.RemoteAddr().(*net.TCPAddr)
+
+			// The source port is arbitrary since it is only
+			// reported, never connected to.
+			header := fmt.Sprintf("PROXY %s %s %s %d %d\r\n", family, sourceIP, dst.IP, 54321, dst.Port)
+			if _, err := io.WriteString(conn, header); err != nil {
+				conn.Close()
+				return nil, err
+			}
+
+			return conn, nil
+		}
+	}
+}
+
 func OptDontFollowRedirects(c *http.Client) {
 	// Per CheckRedirect godoc: "As a special case, if
 	// CheckRedirect returns ErrUseLastResponse, then
@@ -0,0 +1,131 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build e2e
+
+package httpproxy
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strconv"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	contour_api_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"github.com/projectcontour/contour/test/e2e"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func testRetryBudget(namespace string) {
+	Specify("retries are throttled once the retry budget is exceeded", func() {
+		t := f.T()
+
+		f.Fixtures.Echo.Deploy(namespace, "echo")
+
+		// Target a port the echo server does not listen on, so
+		// that every request fails to connect and is retried.
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      "echo-refused",
+			},
+			Spec: corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{{
+					Name:       "http",
+					Port:       80,
+					TargetPort: intstr.FromInt(3001),
+				}},
+				Selector: map[string]string{"app.kubernetes.io/name": "echo"},
+			},
+		}
+		require.NoError(t, f.Client.Create(context.TODO(), svc))
+
+		p := &contour_api_v1.HTTPProxy{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      "retry-budget",
+			},
+			Spec: contour_api_v1.HTTPProxySpec{
+				VirtualHost: &contour_api_v1.VirtualHost{
+					Fqdn: "retry-budget.projectcontour.io",
+				},
+				Routes: []contour_api_v1.Route{{
+					Services: []contour_api_v1.Service{{
+						Name:        svc.Name,
+						Port:        80,
+						AltStatName: namespace + "_retry_budget",
+					}},
+					RetryPolicy: &contour_api_v1.RetryPolicy{
+						NumRetries: 10,
+						RetryOn:    []contour_api_v1.RetryOn{"connect-failure"},
+						Budget: &contour_api_v1.RetryBudget{
+							Percent:             1,
+							MinRetryConcurrency: 1,
+						},
+					},
+				}},
+			},
+		}
+		f.CreateHTTPProxyAndWaitFor(p, e2e.HTTPProxyValid)
+
+		res, ok := f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+			Host:      p.Spec.VirtualHost.Fqdn,
+			Condition: e2e.HasStatusCode(503),
+		})
+		require.NotNil(t, res, "request never succeeded")
+		require.Truef(t, ok, "expected 503 response code, got %d", res.StatusCode)
+
+		// Concurrent failing requests need more concurrent retries
+		// than the budget allows.
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				req, err := http.NewRequest(http.MethodGet, f.HTTP.HTTPURLBase, nil)
+				if err != nil {
+					return
+				}
+				req.Host = p.Spec.VirtualHost.Fqdn
+
+				if res, err := http.DefaultClient.Do(req); err == nil {
+					res.Body.Close()
+				}
+			}()
+		}
+		wg.Wait()
+
+		stat := "cluster." + namespace + "_retry_budget.upstream_rq_retry_overflow"
+		overflow := regexp.MustCompile(regexp.QuoteMeta(stat) + `: (\d+)`)
+
+		res, ok = f.HTTP.MetricsRequestUntil(&e2e.HTTPRequestOpts{
+			Path: "/stats?filter=" + stat,
+			Condition: func(res *e2e.HTTPResponse) bool {
+				m := overflow.FindSubmatch(res.Body)
+				if m == nil {
+					return false
+				}
+				n, err := strconv.Atoi(string(m[1]))
+				return err == nil && n > 0
+			},
+		})
+		require.NotNil(t, res, "request never succeeded")
+		require.Truef(t, ok, "expected retries to overflow the retry budget, got %q", res.Body)
+	})
+}
@@ -54,3 +54,22 @@ func (k *Kubectl) StopKubectlPortForward(cmd *gexec.Session) {
 	// a minute should be more than enough to avoid them.
 	cmd.Terminate().Wait(time.Minute)
 }
+
+// Exec runs command in the container of a pod of object, and returns
+// its standard output.
+func (k *Kubectl) Exec(namespace, object, container string, command ...string) (string, error) {
+	args := append([]string{
+		"exec",
+		"-n",
+		namespace,
+		object,
+		"-c",
+		container,
+		"--",
+	}, command...)
+
+	cmd := exec.Command("kubectl", args...) // nolint:gosec
+	cmd.Stderr = k.cmdOutputWriter
+	out, err := cmd.Output()
+	return string(out), err
+}
@@ -27,13 +27,17 @@ import (
 	"strings"
 	"time"
 
+	contour_api_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
+	"github.com/projectcontour/contour/internal/ref"
 	apps_v1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	rbac_v1 "k8s.io/api/rbac/v1"
 	api_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	apimachinery_util_yaml "k8s.io/apimachinery/pkg/util/yaml"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
 type Provisioner struct {
@@ -227,3 +231,94 @@ func (p *Provisioner) EnsureDeleted(obj client.Object) error {
 
 	return nil
 }
+
+// NewGatewayWithParams creates the provided ContourDeployment in namespace,
+// a GatewayClass referencing it and a Gateway named name with a single
+// HTTP listener on port 80 using that class. It waits for the GatewayClass
+// to be accepted and the Gateway to be programmed, returning both so the
+// caller can inspect and clean them up. The GatewayClass is named after
+// the Gateway's namespace and name since GatewayClasses are cluster-scoped.
+func (p *Provisioner) NewGatewayWithParams(namespace, name string, params *contour_api_v1alpha1.ContourDeployment) (*gatewayapi_v1beta1.Gateway, *gatewayapi_v1beta1.GatewayClass, error) {
+	params.Namespace = namespace
+	if err := p.client.Create(context.TODO(), params); err != nil {
+		return nil, nil, fmt.Errorf("error creating ContourDeployment %s/%s: %v", params.Namespace, params.Name, err)
+	}
+
+	gatewayClass := &gatewayapi_v1beta1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: namespace + "-" + name,
+		},
+		Spec: gatewayapi_v1beta1.GatewayClassSpec{
+			ControllerName: gatewayapi_v1beta1.GatewayController("projectcontour.io/gateway-controller"),
+			ParametersRef: &gatewayapi_v1beta1.ParametersReference{
+				Group:     "projectcontour.io",
+				Kind:      "ContourDeployment",
+				Namespace: ref.To(gatewayapi_v1beta1.Namespace(params.Namespace)),
+				Name:      params.Name,
+			},
+		},
+	}
+	if err := p.client.Create(context.TODO(), gatewayClass); err != nil {
+		return nil, nil, fmt.Errorf("error creating GatewayClass %s: %v", gatewayClass.Name, err)
+	}
+
+	if err := p.waitFor(gatewayClass, func() bool {
+		return conditionTrue(gatewayClass.Status.Conditions, string(gatewayapi_v1beta1.GatewayClassConditionStatusAccepted))
+	}); err != nil {
+		return nil, gatewayClass, fmt.Errorf("error waiting for GatewayClass %s to be accepted: %v", gatewayClass.Name, err)
+	}
+
+	gateway := &gatewayapi_v1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+		Spec: gatewayapi_v1beta1.GatewaySpec{
+			GatewayClassName: gatewayapi_v1beta1.ObjectName(gatewayClass.Name),
+			Listeners: []gatewayapi_v1beta1.Listener{
+				{
+					Name:     "http",
+					Protocol: gatewayapi_v1beta1.HTTPProtocolType,
+					Port:     gatewayapi_v1beta1.PortNumber(80),
+					AllowedRoutes: &gatewayapi_v1beta1.AllowedRoutes{
+						Namespaces: &gatewayapi_v1beta1.RouteNamespaces{
+							From: ref.To(gatewayapi_v1beta1.NamespacesFromSame),
+						},
+					},
+				},
+			},
+		},
+	}
+	if err := p.client.Create(context.TODO(), gateway); err != nil {
+		return nil, gatewayClass, fmt.Errorf("error creating Gateway %s/%s: %v", gateway.Namespace, gateway.Name, err)
+	}
+
+	if err := p.waitFor(gateway, func() bool {
+		return conditionTrue(gateway.Status.Conditions, string(gatewayapi_v1beta1.GatewayConditionProgrammed))
+	}); err != nil {
+		return gateway, gatewayClass, fmt.Errorf("error waiting for Gateway %s/%s to be programmed: %v", gateway.Namespace, gateway.Name, err)
+	}
+
+	return gateway, gatewayClass, nil
+}
+
+// waitFor polls obj until condition returns true, refreshing obj
+// from the API server before each check.
+func (p *Provisioner) waitFor(obj client.Object, condition func() bool) error {
+	return wait.PollImmediate(time.Second, 2*time.Minute, func() (bool, error) {
+		if err := p.client.Get(context.TODO(), client.ObjectKeyFromObject(obj), obj); err != nil {
+			// Keep retrying on errors.
+			return false, nil
+		}
+		return condition(), nil
+	})
+}
+
+func conditionTrue(conditions []metav1.Condition, conditionType string) bool {
+	for _, cond := range conditions {
+		if cond.Type == conditionType && cond.Status == metav1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
@@ -16,9 +16,18 @@
 package provisioner
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/tls"
+	"encoding/json"
+	"io"
 	"net"
+	"net/http"
 	"os"
+	"sort"
+	"sync"
 	"testing"
 	"time"
 
@@ -31,7 +40,16 @@ import (
 	"github.com/projectcontour/contour/test/e2e"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	api_errors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapi_v1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
@@ -293,6 +311,2723 @@ var _ = Describe("Gateway provisioner", func() {
 			assert.Equal(f.T(), "echo", body.Service)
 		})
 	})
+
+	f.NamespacedTest("provisioner-contour-deployment-strategy", func(namespace string) {
+		Specify("The Contour Deployment strategy from the ContourDeployment is honored during a rollout", func() {
+			params := &contour_api_v1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "contour-params",
+				},
+				Spec: contour_api_v1alpha1.ContourDeploymentSpec{
+					Contour: &contour_api_v1alpha1.ContourSettings{
+						Deployment: &contour_api_v1alpha1.DeploymentSettings{
+							Replicas: 2,
+							Strategy: &appsv1.DeploymentStrategy{
+								Type: appsv1.RollingUpdateDeploymentStrategyType,
+								RollingUpdate: &appsv1.RollingUpdateDeployment{
+									MaxSurge:       ref.To(intstr.FromInt(1)),
+									MaxUnavailable: ref.To(intstr.FromInt(0)),
+								},
+							},
+						},
+					},
+					RuntimeSettings: contourDeploymentRuntimeSettings(),
+				},
+			}
+			require.NoError(f.T(), f.Client.Create(context.Background(), params))
+
+			gatewayClass := &gatewayapi_v1beta1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "contour-with-deployment-strategy",
+				},
+				Spec: gatewayapi_v1beta1.GatewayClassSpec{
+					ControllerName: gatewayapi_v1beta1.GatewayController("projectcontour.io/gateway-controller"),
+					ParametersRef: &gatewayapi_v1beta1.ParametersReference{
+						Group:     "projectcontour.io",
+						Kind:      "ContourDeployment",
+						Namespace: ref.To(gatewayapi_v1beta1.Namespace(namespace)),
+						Name:      params.Name,
+					},
+				},
+			}
+			_, ok := f.CreateGatewayClassAndWaitFor(gatewayClass, gatewayClassAccepted)
+			require.True(f.T(), ok)
+
+			gateway := &gatewayapi_v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "http",
+					Namespace: namespace,
+				},
+				Spec: gatewayapi_v1beta1.GatewaySpec{
+					GatewayClassName: gatewayapi_v1beta1.ObjectName(gatewayClass.Name),
+					Listeners: []gatewayapi_v1beta1.Listener{
+						{
+							Name:     "http",
+							Protocol: gatewayapi_v1beta1.HTTPProtocolType,
+							Port:     gatewayapi_v1beta1.PortNumber(80),
+							AllowedRoutes: &gatewayapi_v1beta1.AllowedRoutes{
+								Namespaces: &gatewayapi_v1beta1.RouteNamespaces{
+									From: ref.To(gatewayapi_v1beta1.NamespacesFromSame),
+								},
+							},
+						},
+					},
+				},
+			}
+			gateway, ok = f.CreateGatewayAndWaitFor(gateway, func(gw *gatewayapi_v1beta1.Gateway) bool {
+				return gatewayProgrammed(gw) && gatewayHasAddress(gw)
+			})
+			require.True(f.T(), ok)
+
+			// The Contour Deployment must carry the configured strategy.
+			deploy := &appsv1.Deployment{}
+			deployKey := client.ObjectKey{Namespace: namespace, Name: "contour-" + gateway.Name}
+			require.Eventually(f.T(), func() bool {
+				if err := f.Client.Get(context.Background(), deployKey, deploy); err != nil {
+					return false
+				}
+				return deploy.Status.AvailableReplicas == 2
+			}, time.Minute, time.Second)
+
+			require.Equal(f.T(), appsv1.RollingUpdateDeploymentStrategyType, deploy.Spec.Strategy.Type)
+			require.NotNil(f.T(), deploy.Spec.Strategy.RollingUpdate)
+			assert.Equal(f.T(), intstr.FromInt(1), *deploy.Spec.Strategy.RollingUpdate.MaxSurge)
+			assert.Equal(f.T(), intstr.FromInt(0), *deploy.Spec.Strategy.RollingUpdate.MaxUnavailable)
+
+			f.Fixtures.Echo.Deploy(namespace, "echo")
+
+			route := &gatewayapi_v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "httproute-1",
+				},
+				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+					Hostnames: []gatewayapi_v1beta1.Hostname{"deployment-strategy.provisioner.projectcontour.io"},
+					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1beta1.ParentReference{
+							gatewayapi.GatewayParentRef("", gateway.Name),
+						},
+					},
+					Rules: []gatewayapi_v1beta1.HTTPRouteRule{
+						{
+							BackendRefs: gatewayapi.HTTPBackendRef("echo", 80, 1),
+						},
+					},
+				},
+			}
+			_, ok = f.CreateHTTPRouteAndWaitFor(route, httpRouteAccepted)
+			require.True(f.T(), ok)
+
+			requestOpts := &e2e.HTTPRequestOpts{
+				OverrideURL: "http://" + net.JoinHostPort(gateway.Status.Addresses[0].Value, "80"),
+				Host:        string(route.Spec.Hostnames[0]),
+				Condition:   e2e.HasStatusCode(200),
+			}
+			res, ok := f.HTTP.RequestUntil(requestOpts)
+			require.NotNil(f.T(), res)
+			require.Truef(f.T(), ok, "expected 200 response code, got %d", res.StatusCode)
+
+			// Trigger a rollout of the Contour Deployment by changing
+			// its pod template, the same way "kubectl rollout restart" does.
+			patch := client.MergeFrom(deploy.DeepCopy())
+			if deploy.Spec.Template.Annotations == nil {
+				deploy.Spec.Template.Annotations = map[string]string{}
+			}
+			deploy.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"] = time.Now().Format(time.RFC3339)
+			require.NoError(f.T(), f.Client.Patch(context.Background(), deploy, patch))
+
+			// With maxUnavailable=0 the number of available Contour replicas
+			// must never drop below the desired count, and traffic must keep
+			// flowing while the rollout progresses.
+			deadline := time.Now().Add(3 * time.Minute)
+			for {
+				require.True(f.T(), time.Now().Before(deadline), "timed out waiting for the Contour Deployment rollout to complete")
+
+				res, err := f.HTTP.Request(requestOpts)
+				require.NoError(f.T(), err)
+				require.Equal(f.T(), 200, res.StatusCode)
+
+				current := &appsv1.Deployment{}
+				require.NoError(f.T(), f.Client.Get(context.Background(), deployKey, current))
+				require.GreaterOrEqual(f.T(), current.Status.AvailableReplicas, int32(2))
+
+				if current.Status.ObservedGeneration >= current.Generation &&
+					current.Status.UpdatedReplicas == 2 &&
+					current.Status.Replicas == 2 {
+					break
+				}
+
+				time.Sleep(time.Second)
+			}
+
+			require.NoError(f.T(), f.DeleteGatewayClass(gatewayClass, false))
+		})
+	})
+
+	f.NamespacedTest("provisioner-envoy-proxy-protocol", func(namespace string) {
+		Specify("Envoy decodes the PROXY protocol when enabled in the ContourDeployment", func() {
+			params := &contour_api_v1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "contour-params",
+				},
+				Spec: contour_api_v1alpha1.ContourDeploymentSpec{
+					Envoy: &contour_api_v1alpha1.EnvoySettings{
+						NetworkPublishing: &contour_api_v1alpha1.NetworkPublishing{
+							ProxyProtocol: true,
+						},
+					},
+					RuntimeSettings: contourDeploymentRuntimeSettings(),
+				},
+			}
+			require.NoError(f.T(), f.Client.Create(context.Background(), params))
+
+			gatewayClass := &gatewayapi_v1beta1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "contour-with-proxy-protocol",
+				},
+				Spec: gatewayapi_v1beta1.GatewayClassSpec{
+					ControllerName: gatewayapi_v1beta1.GatewayController("projectcontour.io/gateway-controller"),
+					ParametersRef: &gatewayapi_v1beta1.ParametersReference{
+						Group:     "projectcontour.io",
+						Kind:      "ContourDeployment",
+						Namespace: ref.To(gatewayapi_v1beta1.Namespace(namespace)),
+						Name:      params.Name,
+					},
+				},
+			}
+			_, ok := f.CreateGatewayClassAndWaitFor(gatewayClass, gatewayClassAccepted)
+			require.True(f.T(), ok)
+
+			gateway := &gatewayapi_v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "http",
+					Namespace: namespace,
+				},
+				Spec: gatewayapi_v1beta1.GatewaySpec{
+					GatewayClassName: gatewayapi_v1beta1.ObjectName(gatewayClass.Name),
+					Listeners: []gatewayapi_v1beta1.Listener{
+						{
+							Name:     "http",
+							Protocol: gatewayapi_v1beta1.HTTPProtocolType,
+							Port:     gatewayapi_v1beta1.PortNumber(80),
+							AllowedRoutes: &gatewayapi_v1beta1.AllowedRoutes{
+								Namespaces: &gatewayapi_v1beta1.RouteNamespaces{
+									From: ref.To(gatewayapi_v1beta1.NamespacesFromSame),
+								},
+							},
+						},
+					},
+				},
+			}
+			gateway, ok = f.CreateGatewayAndWaitFor(gateway, func(gw *gatewayapi_v1beta1.Gateway) bool {
+				return gatewayProgrammed(gw) && gatewayHasAddress(gw)
+			})
+			require.True(f.T(), ok)
+
+			// The Envoy readiness probe targets the health port, so the
+			// pods only become ready if it does not expect PROXY protocol.
+			envoyDaemonSet := &appsv1.DaemonSet{}
+			require.Eventually(f.T(), func() bool {
+				key := client.ObjectKey{Namespace: namespace, Name: "envoy-" + gateway.Name}
+				if err := f.Client.Get(context.Background(), key, envoyDaemonSet); err != nil {
+					return false
+				}
+				return envoyDaemonSet.Status.DesiredNumberScheduled > 0 &&
+					envoyDaemonSet.Status.NumberReady == envoyDaemonSet.Status.DesiredNumberScheduled
+			}, time.Minute, time.Second)
+
+			f.Fixtures.Echo.Deploy(namespace, "echo")
+
+			route := &gatewayapi_v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "httproute-1",
+				},
+				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+					Hostnames: []gatewayapi_v1beta1.Hostname{"proxy-protocol.provisioner.projectcontour.io"},
+					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1beta1.ParentReference{
+							gatewayapi.GatewayParentRef("", gateway.Name),
+						},
+					},
+					Rules: []gatewayapi_v1beta1.HTTPRouteRule{
+						{
+							BackendRefs: gatewayapi.HTTPBackendRef("echo", 80, 1),
+						},
+					},
+				},
+			}
+			_, ok = f.CreateHTTPRouteAndWaitFor(route, httpRouteAccepted)
+			require.True(f.T(), ok)
+
+			// The client pretends to be a load balancer relaying a
+			// connection from a documentation-range address.
+			const clientIP = "192.0.2.10"
+			gatewayURL := "http://" + net.JoinHostPort(gateway.Status.Addresses[0].Value, "80")
+
+			res, ok := f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+				OverrideURL: gatewayURL,
+				Host:        string(route.Spec.Hostnames[0]),
+				ClientOpts:  []func(*http.Client){e2e.OptProxyProtocol(clientIP)},
+				Condition:   e2e.HasStatusCode(200),
+			})
+			require.NotNil(f.T(), res)
+			require.Truef(f.T(), ok, "expected 200 response code, got %d", res.StatusCode)
+
+			body := f.GetEchoResponseBody(res.Body)
+			assert.Equal(f.T(), clientIP, body.RequestHeaders.Get("X-Forwarded-For"))
+
+			// Connections without a PROXY protocol header are rejected.
+			_, err := f.HTTP.Request(&e2e.HTTPRequestOpts{
+				OverrideURL: gatewayURL,
+				Host:        string(route.Spec.Hostnames[0]),
+			})
+			require.Error(f.T(), err)
+
+			require.NoError(f.T(), f.DeleteGatewayClass(gatewayClass, false))
+		})
+	})
+
+	f.NamespacedTest("provisioner-default-response-headers", func(namespace string) {
+		Specify("Envoy default response headers from the ContourDeployment are set on every response", func() {
+			const hsts = "max-age=31536000; includeSubDomains"
+
+			params := &contour_api_v1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "contour-params",
+				},
+				Spec: contour_api_v1alpha1.ContourDeploymentSpec{
+					Envoy: &contour_api_v1alpha1.EnvoySettings{
+						DefaultResponseHeaders: &contour_api_v1alpha1.HeadersPolicy{
+							Set: map[string]string{
+								"Strict-Transport-Security": hsts,
+							},
+						},
+					},
+					RuntimeSettings: contourDeploymentRuntimeSettings(),
+				},
+			}
+			require.NoError(f.T(), f.Client.Create(context.Background(), params))
+
+			gatewayClass := &gatewayapi_v1beta1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "contour-with-default-response-headers",
+				},
+				Spec: gatewayapi_v1beta1.GatewayClassSpec{
+					ControllerName: gatewayapi_v1beta1.GatewayController("projectcontour.io/gateway-controller"),
+					ParametersRef: &gatewayapi_v1beta1.ParametersReference{
+						Group:     "projectcontour.io",
+						Kind:      "ContourDeployment",
+						Namespace: ref.To(gatewayapi_v1beta1.Namespace(namespace)),
+						Name:      params.Name,
+					},
+				},
+			}
+			_, ok := f.CreateGatewayClassAndWaitFor(gatewayClass, gatewayClassAccepted)
+			require.True(f.T(), ok)
+
+			gateway := &gatewayapi_v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "http",
+					Namespace: namespace,
+				},
+				Spec: gatewayapi_v1beta1.GatewaySpec{
+					GatewayClassName: gatewayapi_v1beta1.ObjectName(gatewayClass.Name),
+					Listeners: []gatewayapi_v1beta1.Listener{
+						{
+							Name:     "http",
+							Protocol: gatewayapi_v1beta1.HTTPProtocolType,
+							Port:     gatewayapi_v1beta1.PortNumber(80),
+							AllowedRoutes: &gatewayapi_v1beta1.AllowedRoutes{
+								Namespaces: &gatewayapi_v1beta1.RouteNamespaces{
+									From: ref.To(gatewayapi_v1beta1.NamespacesFromSame),
+								},
+							},
+						},
+					},
+				},
+			}
+			gateway, ok = f.CreateGatewayAndWaitFor(gateway, func(gw *gatewayapi_v1beta1.Gateway) bool {
+				return gatewayProgrammed(gw) && gatewayHasAddress(gw)
+			})
+			require.True(f.T(), ok)
+
+			f.Fixtures.Echo.Deploy(namespace, "echo")
+			f.Fixtures.Echo.Deploy(namespace, "echo-other")
+
+			route := &gatewayapi_v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "httproute-1",
+				},
+				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+					Hostnames: []gatewayapi_v1beta1.Hostname{"default-headers.provisioner.projectcontour.io"},
+					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1beta1.ParentReference{
+							gatewayapi.GatewayParentRef("", gateway.Name),
+						},
+					},
+					Rules: []gatewayapi_v1beta1.HTTPRouteRule{
+						{
+							Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/other"),
+							BackendRefs: gatewayapi.HTTPBackendRef("echo-other", 80, 1),
+						},
+						{
+							BackendRefs: gatewayapi.HTTPBackendRef("echo", 80, 1),
+						},
+					},
+				},
+			}
+			_, ok = f.CreateHTTPRouteAndWaitFor(route, httpRouteAccepted)
+			require.True(f.T(), ok)
+
+			gatewayURL := "http://" + net.JoinHostPort(gateway.Status.Addresses[0].Value, "80")
+
+			// Responses from every route rule carry the header.
+			for path, service := range map[string]string{"/": "echo", "/other/path": "echo-other"} {
+				res, ok := f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+					OverrideURL: gatewayURL,
+					Host:        string(route.Spec.Hostnames[0]),
+					Path:        path,
+					Condition:   e2e.HasStatusCode(200),
+				})
+				require.NotNil(f.T(), res)
+				require.Truef(f.T(), ok, "expected 200 response code, got %d", res.StatusCode)
+
+				body := f.GetEchoResponseBody(res.Body)
+				assert.Equal(f.T(), service, body.Service)
+				assert.Equal(f.T(), hsts, res.Headers.Get("Strict-Transport-Security"))
+			}
+
+			require.NoError(f.T(), f.DeleteGatewayClass(gatewayClass, false))
+		})
+	})
+
+	f.NamespacedTest("provisioner-default-load-balancer-policy", func(namespace string) {
+		Specify("The default load balancer policy from the ContourDeployment applies to the Gateway's clusters", func() {
+			params := &contour_api_v1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "contour-params",
+				},
+				Spec: contour_api_v1alpha1.ContourDeploymentSpec{
+					Envoy: &contour_api_v1alpha1.EnvoySettings{
+						DefaultLoadBalancerPolicy: "WeightedLeastRequest",
+					},
+					RuntimeSettings: contourDeploymentRuntimeSettings(),
+				},
+			}
+			require.NoError(f.T(), f.Client.Create(context.Background(), params))
+
+			gatewayClass := &gatewayapi_v1beta1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "contour-with-default-lb-policy",
+				},
+				Spec: gatewayapi_v1beta1.GatewayClassSpec{
+					ControllerName: gatewayapi_v1beta1.GatewayController("projectcontour.io/gateway-controller"),
+					ParametersRef: &gatewayapi_v1beta1.ParametersReference{
+						Group:     "projectcontour.io",
+						Kind:      "ContourDeployment",
+						Namespace: ref.To(gatewayapi_v1beta1.Namespace(namespace)),
+						Name:      params.Name,
+					},
+				},
+			}
+			_, ok := f.CreateGatewayClassAndWaitFor(gatewayClass, gatewayClassAccepted)
+			require.True(f.T(), ok)
+
+			gateway := &gatewayapi_v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "http",
+					Namespace: namespace,
+				},
+				Spec: gatewayapi_v1beta1.GatewaySpec{
+					GatewayClassName: gatewayapi_v1beta1.ObjectName(gatewayClass.Name),
+					Listeners: []gatewayapi_v1beta1.Listener{
+						{
+							Name:     "http",
+							Protocol: gatewayapi_v1beta1.HTTPProtocolType,
+							Port:     gatewayapi_v1beta1.PortNumber(80),
+							AllowedRoutes: &gatewayapi_v1beta1.AllowedRoutes{
+								Namespaces: &gatewayapi_v1beta1.RouteNamespaces{
+									From: ref.To(gatewayapi_v1beta1.NamespacesFromSame),
+								},
+							},
+						},
+					},
+				},
+			}
+			gateway, ok = f.CreateGatewayAndWaitFor(gateway, func(gw *gatewayapi_v1beta1.Gateway) bool {
+				return gatewayProgrammed(gw) && gatewayHasAddress(gw)
+			})
+			require.True(f.T(), ok)
+
+			// The policy is rendered into the generated ContourConfiguration.
+			contourConfig := &contour_api_v1alpha1.ContourConfiguration{}
+			require.NoError(f.T(), f.Client.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: "contourconfig-" + gateway.Name}, contourConfig))
+			require.NotNil(f.T(), contourConfig.Spec.Envoy.Cluster)
+			assert.Equal(f.T(), "WeightedLeastRequest", contourConfig.Spec.Envoy.Cluster.DefaultLoadBalancerPolicy)
+
+			f.Fixtures.Echo.DeployN(namespace, "echo", 3)
+
+			route := &gatewayapi_v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "httproute-1",
+				},
+				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+					Hostnames: []gatewayapi_v1beta1.Hostname{"default-lb-policy.provisioner.projectcontour.io"},
+					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1beta1.ParentReference{
+							gatewayapi.GatewayParentRef("", gateway.Name),
+						},
+					},
+					Rules: []gatewayapi_v1beta1.HTTPRouteRule{
+						{
+							BackendRefs: gatewayapi.HTTPBackendRef("echo", 80, 1),
+						},
+					},
+				},
+			}
+			_, ok = f.CreateHTTPRouteAndWaitFor(route, httpRouteAccepted)
+			require.True(f.T(), ok)
+
+			gatewayURL := "http://" + net.JoinHostPort(gateway.Status.Addresses[0].Value, "80")
+
+			res, ok := f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+				OverrideURL: gatewayURL,
+				Host:        string(route.Spec.Hostnames[0]),
+				Condition:   e2e.HasStatusCode(200),
+			})
+			require.NotNil(f.T(), res)
+			require.Truef(f.T(), ok, "expected 200 response code, got %d", res.StatusCode)
+
+			// Under concurrent load, least request balancing
+			// spreads the requests over every echo pod.
+			require.Eventually(f.T(), func() bool {
+				var (
+					mu   sync.Mutex
+					wg   sync.WaitGroup
+					pods = map[string]int{}
+				)
+				for i := 0; i < 60; i++ {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+
+						req, err := http.NewRequest(http.MethodGet, gatewayURL, nil)
+						if err != nil {
+							return
+						}
+						req.Host = string(route.Spec.Hostnames[0])
+
+						res, err := http.DefaultClient.Do(req)
+						if err != nil {
+							return
+						}
+						defer res.Body.Close()
+
+						var body e2e.EchoResponseBody
+						if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+							return
+						}
+
+						mu.Lock()
+						pods[body.Pod]++
+						mu.Unlock()
+					}()
+				}
+				wg.Wait()
+
+				return len(pods) == 3
+			}, time.Minute, time.Second)
+
+			require.NoError(f.T(), f.DeleteGatewayClass(gatewayClass, false))
+		})
+	})
+
+	f.NamespacedTest("provisioner-envoy-service-ports", func(namespace string) {
+		Specify("Envoy service ports can be given custom names and target ports", func() {
+			params := &contour_api_v1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "contour-params",
+				},
+				Spec: contour_api_v1alpha1.ContourDeploymentSpec{
+					Envoy: &contour_api_v1alpha1.EnvoySettings{
+						NetworkPublishing: &contour_api_v1alpha1.NetworkPublishing{
+							Ports: []contour_api_v1alpha1.EnvoyServicePort{
+								{
+									Listener:   "http",
+									Name:       "web",
+									TargetPort: 8081,
+								},
+							},
+						},
+					},
+					RuntimeSettings: contourDeploymentRuntimeSettings(),
+				},
+			}
+			require.NoError(f.T(), f.Client.Create(context.Background(), params))
+
+			gatewayClass := &gatewayapi_v1beta1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "contour-with-envoy-service-ports",
+				},
+				Spec: gatewayapi_v1beta1.GatewayClassSpec{
+					ControllerName: gatewayapi_v1beta1.GatewayController("projectcontour.io/gateway-controller"),
+					ParametersRef: &gatewayapi_v1beta1.ParametersReference{
+						Group:     "projectcontour.io",
+						Kind:      "ContourDeployment",
+						Namespace: ref.To(gatewayapi_v1beta1.Namespace(namespace)),
+						Name:      params.Name,
+					},
+				},
+			}
+			_, ok := f.CreateGatewayClassAndWaitFor(gatewayClass, gatewayClassAccepted)
+			require.True(f.T(), ok)
+
+			gateway := &gatewayapi_v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "http",
+					Namespace: namespace,
+				},
+				Spec: gatewayapi_v1beta1.GatewaySpec{
+					GatewayClassName: gatewayapi_v1beta1.ObjectName(gatewayClass.Name),
+					Listeners: []gatewayapi_v1beta1.Listener{
+						{
+							Name:     "http",
+							Protocol: gatewayapi_v1beta1.HTTPProtocolType,
+							Port:     gatewayapi_v1beta1.PortNumber(80),
+							AllowedRoutes: &gatewayapi_v1beta1.AllowedRoutes{
+								Namespaces: &gatewayapi_v1beta1.RouteNamespaces{
+									From: ref.To(gatewayapi_v1beta1.NamespacesFromSame),
+								},
+							},
+						},
+					},
+				},
+			}
+			gateway, ok = f.CreateGatewayAndWaitFor(gateway, func(gw *gatewayapi_v1beta1.Gateway) bool {
+				return gatewayProgrammed(gw) && gatewayHasAddress(gw)
+			})
+			require.True(f.T(), ok)
+
+			envoyService := &corev1.Service{}
+			require.NoError(f.T(), f.Client.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: "envoy-" + gateway.Name}, envoyService))
+			require.Len(f.T(), envoyService.Spec.Ports, 1)
+			assert.Equal(f.T(), "web", envoyService.Spec.Ports[0].Name)
+			assert.Equal(f.T(), int32(80), envoyService.Spec.Ports[0].Port)
+			assert.Equal(f.T(), intstr.FromInt(8081), envoyService.Spec.Ports[0].TargetPort)
+
+			f.Fixtures.Echo.Deploy(namespace, "echo")
+
+			route := &gatewayapi_v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "httproute-1",
+				},
+				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+					Hostnames: []gatewayapi_v1beta1.Hostname{"service-ports.provisioner.projectcontour.io"},
+					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1beta1.ParentReference{
+							gatewayapi.GatewayParentRef("", gateway.Name),
+						},
+					},
+					Rules: []gatewayapi_v1beta1.HTTPRouteRule{
+						{
+							BackendRefs: gatewayapi.HTTPBackendRef("echo", 80, 1),
+						},
+					},
+				},
+			}
+			_, ok = f.CreateHTTPRouteAndWaitFor(route, httpRouteAccepted)
+			require.True(f.T(), ok)
+
+			// Envoy listens on the custom target port, so traffic
+			// through the service still reaches the backend.
+			res, ok := f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+				OverrideURL: "http://" + net.JoinHostPort(gateway.Status.Addresses[0].Value, "80"),
+				Host:        string(route.Spec.Hostnames[0]),
+				Condition:   e2e.HasStatusCode(200),
+			})
+			require.NotNil(f.T(), res)
+			require.Truef(f.T(), ok, "expected 200 response code, got %d", res.StatusCode)
+
+			body := f.GetEchoResponseBody(res.Body)
+			assert.Equal(f.T(), "echo", body.Service)
+
+			require.NoError(f.T(), f.DeleteGatewayClass(gatewayClass, false))
+		})
+	})
+
+	f.NamespacedTest("provisioner-internal-load-balancer", func(namespace string) {
+		Specify("Envoy service annotations from the ContourDeployment are set for every Gateway of the class", func() {
+			params := &contour_api_v1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "internal-params",
+				},
+				Spec: contour_api_v1alpha1.ContourDeploymentSpec{
+					Envoy: &contour_api_v1alpha1.EnvoySettings{
+						NetworkPublishing: &contour_api_v1alpha1.NetworkPublishing{
+							Type: contour_api_v1alpha1.LoadBalancerServicePublishingType,
+							ServiceAnnotations: map[string]string{
+								"service.beta.kubernetes.io/aws-load-balancer-internal": "true",
+							},
+						},
+					},
+					RuntimeSettings: contourDeploymentRuntimeSettings(),
+				},
+			}
+			require.NoError(f.T(), f.Client.Create(context.Background(), params))
+
+			gatewayClass := &gatewayapi_v1beta1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "internal",
+				},
+				Spec: gatewayapi_v1beta1.GatewayClassSpec{
+					ControllerName: gatewayapi_v1beta1.GatewayController("projectcontour.io/gateway-controller"),
+					ParametersRef: &gatewayapi_v1beta1.ParametersReference{
+						Group:     "projectcontour.io",
+						Kind:      "ContourDeployment",
+						Namespace: ref.To(gatewayapi_v1beta1.Namespace(namespace)),
+						Name:      params.Name,
+					},
+				},
+			}
+			_, ok := f.CreateGatewayClassAndWaitFor(gatewayClass, gatewayClassAccepted)
+			require.True(f.T(), ok)
+
+			gateway := &gatewayapi_v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "internal",
+					Namespace: namespace,
+				},
+				Spec: gatewayapi_v1beta1.GatewaySpec{
+					GatewayClassName: gatewayapi_v1beta1.ObjectName(gatewayClass.Name),
+					Listeners: []gatewayapi_v1beta1.Listener{
+						{
+							Name:     "http",
+							Protocol: gatewayapi_v1beta1.HTTPProtocolType,
+							Port:     gatewayapi_v1beta1.PortNumber(80),
+							AllowedRoutes: &gatewayapi_v1beta1.AllowedRoutes{
+								Namespaces: &gatewayapi_v1beta1.RouteNamespaces{
+									From: ref.To(gatewayapi_v1beta1.NamespacesFromSame),
+								},
+							},
+						},
+					},
+				},
+			}
+			_, ok = f.CreateGatewayAndWaitFor(gateway, gatewayAccepted)
+			require.True(f.T(), ok)
+
+			// The Gateway sets no annotations of its own, so the
+			// Envoy service only carries the class defaults.
+			require.Eventually(f.T(), func() bool {
+				envoyService := &corev1.Service{}
+				if err := f.Client.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: "envoy-" + gateway.Name}, envoyService); err != nil {
+					return false
+				}
+
+				return envoyService.Annotations["service.beta.kubernetes.io/aws-load-balancer-internal"] == "true"
+			}, time.Minute, time.Second)
+
+			require.NoError(f.T(), f.DeleteGatewayClass(gatewayClass, false))
+		})
+	})
+	f.NamespacedTest("provisioner-nodeport-service", func(namespace string) {
+		Specify("Envoy can be published with a NodePort service", func() {
+			gateway, gatewayClass, err := f.Provisioner.NewGatewayWithParams(namespace, "nodeport", &contour_api_v1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "nodeport-params",
+				},
+				Spec: contour_api_v1alpha1.ContourDeploymentSpec{
+					Envoy: &contour_api_v1alpha1.EnvoySettings{
+						NetworkPublishing: &contour_api_v1alpha1.NetworkPublishing{
+							Type: contour_api_v1alpha1.NodePortServicePublishingType,
+						},
+					},
+					RuntimeSettings: contourDeploymentRuntimeSettings(),
+				},
+			})
+			require.NoError(f.T(), err)
+
+			envoyService := &corev1.Service{}
+			require.NoError(f.T(), f.Client.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: "envoy-" + gateway.Name}, envoyService))
+			assert.Equal(f.T(), corev1.ServiceTypeNodePort, envoyService.Spec.Type)
+			require.Len(f.T(), envoyService.Spec.Ports, 1)
+			assert.NotZero(f.T(), envoyService.Spec.Ports[0].NodePort)
+
+			require.NoError(f.T(), f.DeleteGatewayClass(gatewayClass, false))
+		})
+	})
+
+	f.NamespacedTest("provisioner-clusterip-service", func(namespace string) {
+		Specify("Envoy can be published with a ClusterIP service", func() {
+			gateway, gatewayClass, err := f.Provisioner.NewGatewayWithParams(namespace, "clusterip", &contour_api_v1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "clusterip-params",
+				},
+				Spec: contour_api_v1alpha1.ContourDeploymentSpec{
+					Envoy: &contour_api_v1alpha1.EnvoySettings{
+						NetworkPublishing: &contour_api_v1alpha1.NetworkPublishing{
+							Type: contour_api_v1alpha1.ClusterIPServicePublishingType,
+						},
+					},
+					RuntimeSettings: contourDeploymentRuntimeSettings(),
+				},
+			})
+			require.NoError(f.T(), err)
+
+			envoyService := &corev1.Service{}
+			require.NoError(f.T(), f.Client.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: "envoy-" + gateway.Name}, envoyService))
+			assert.Equal(f.T(), corev1.ServiceTypeClusterIP, envoyService.Spec.Type)
+			require.Len(f.T(), envoyService.Spec.Ports, 1)
+			assert.Zero(f.T(), envoyService.Spec.Ports[0].NodePort)
+
+			require.NoError(f.T(), f.DeleteGatewayClass(gatewayClass, false))
+		})
+	})
+
+	f.NamespacedTest("provisioner-pod-labels", func(namespace string) {
+		Specify("Pod labels from the ContourDeployment are set on the pods but not the selectors", func() {
+			gateway, gatewayClass, err := f.Provisioner.NewGatewayWithParams(namespace, "pod-labels", &contour_api_v1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "pod-labels-params",
+				},
+				Spec: contour_api_v1alpha1.ContourDeploymentSpec{
+					Contour: &contour_api_v1alpha1.ContourSettings{
+						PodLabels: map[string]string{"logging.projectcontour.io/collect": "contour"},
+					},
+					Envoy: &contour_api_v1alpha1.EnvoySettings{
+						PodLabels: map[string]string{"logging.projectcontour.io/collect": "envoy"},
+					},
+					RuntimeSettings: contourDeploymentRuntimeSettings(),
+				},
+			})
+			require.NoError(f.T(), err)
+
+			contourDeployment := &appsv1.Deployment{}
+			require.NoError(f.T(), f.Client.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: "contour-" + gateway.Name}, contourDeployment))
+			assert.NotContains(f.T(), contourDeployment.Spec.Selector.MatchLabels, "logging.projectcontour.io/collect")
+
+			envoyDaemonSet := &appsv1.DaemonSet{}
+			require.NoError(f.T(), f.Client.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: "envoy-" + gateway.Name}, envoyDaemonSet))
+			assert.NotContains(f.T(), envoyDaemonSet.Spec.Selector.MatchLabels, "logging.projectcontour.io/collect")
+
+			// Every pod selected by the workloads has its pod labels.
+			podsHaveLabel := func(selector *metav1.LabelSelector, value string) func() bool {
+				return func() bool {
+					pods := &corev1.PodList{}
+					if err := f.Client.List(context.Background(), pods, client.InNamespace(namespace), client.MatchingLabels(selector.MatchLabels)); err != nil {
+						return false
+					}
+					if len(pods.Items) == 0 {
+						return false
+					}
+					for _, pod := range pods.Items {
+						if pod.Labels["logging.projectcontour.io/collect"] != value {
+							return false
+						}
+					}
+					return true
+				}
+			}
+			require.Eventually(f.T(), podsHaveLabel(contourDeployment.Spec.Selector, "contour"), f.RetryTimeout, f.RetryInterval)
+			require.Eventually(f.T(), podsHaveLabel(envoyDaemonSet.Spec.Selector, "envoy"), f.RetryTimeout, f.RetryInterval)
+
+			require.NoError(f.T(), f.DeleteGatewayClass(gatewayClass, false))
+		})
+	})
+
+	f.NamespacedTest("provisioner-without-shutdown-manager", func(namespace string) {
+		Specify("Envoy pods run without the shutdown-manager sidecar and still terminate", func() {
+			gateway, gatewayClass, err := f.Provisioner.NewGatewayWithParams(namespace, "no-shutdown-manager", &contour_api_v1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "no-shutdown-manager-params",
+				},
+				Spec: contour_api_v1alpha1.ContourDeploymentSpec{
+					Envoy: &contour_api_v1alpha1.EnvoySettings{
+						ShutdownManager: &contour_api_v1alpha1.ShutdownManagerSettings{
+							Enabled: ref.To(false),
+						},
+					},
+					RuntimeSettings: contourDeploymentRuntimeSettings(),
+				},
+			})
+			require.NoError(f.T(), err)
+
+			envoyDaemonSet := &appsv1.DaemonSet{}
+			require.NoError(f.T(), f.Client.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: "envoy-" + gateway.Name}, envoyDaemonSet))
+			require.Len(f.T(), envoyDaemonSet.Spec.Template.Spec.Containers, 1)
+			assert.Nil(f.T(), envoyDaemonSet.Spec.Template.Spec.Containers[0].Lifecycle)
+
+			// Wait for a ready Envoy pod with a single container.
+			var envoyPod corev1.Pod
+			require.Eventually(f.T(), func() bool {
+				pods := &corev1.PodList{}
+				if err := f.Client.List(context.Background(), pods, client.InNamespace(namespace), client.MatchingLabels(envoyDaemonSet.Spec.Selector.MatchLabels)); err != nil {
+					return false
+				}
+				for _, pod := range pods.Items {
+					if len(pod.Spec.Containers) == 1 && len(pod.Status.ContainerStatuses) == 1 && pod.Status.ContainerStatuses[0].Ready {
+						envoyPod = pod
+						return true
+					}
+				}
+				return false
+			}, f.RetryTimeout, f.RetryInterval)
+
+			// Without a preStop hook to wait on, the pod goes away
+			// well before its termination grace period.
+			require.NoError(f.T(), f.Client.Delete(context.Background(), &envoyPod))
+			require.Eventually(f.T(), func() bool {
+				err := f.Client.Get(context.Background(), client.ObjectKeyFromObject(&envoyPod), &corev1.Pod{})
+				return api_errors.IsNotFound(err)
+			}, time.Minute, f.RetryInterval)
+
+			require.NoError(f.T(), f.DeleteGatewayClass(gatewayClass, false))
+		})
+	})
+
+	f.NamespacedTest("provisioner-external-hostname", func(namespace string) {
+		Specify("The Gateway advertises the external hostname in its status addresses", func() {
+			_, gatewayClass, err := f.Provisioner.NewGatewayWithParams(namespace, "external-hostname", &contour_api_v1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "external-hostname-params",
+				},
+				Spec: contour_api_v1alpha1.ContourDeploymentSpec{
+					Envoy: &contour_api_v1alpha1.EnvoySettings{
+						NetworkPublishing: &contour_api_v1alpha1.NetworkPublishing{
+							ExternalHostname: "gateway.projectcontour.io",
+						},
+					},
+					RuntimeSettings: contourDeploymentRuntimeSettings(),
+				},
+			})
+			require.NoError(f.T(), err)
+
+			gateway := &gatewayapi_v1beta1.Gateway{}
+			require.Eventually(f.T(), func() bool {
+				if err := f.Client.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: "external-hostname"}, gateway); err != nil {
+					return false
+				}
+				return gatewayHasAddress(gateway)
+			}, f.RetryTimeout, f.RetryInterval)
+
+			assert.Equal(f.T(), []gatewayapi_v1beta1.GatewayAddress{{
+				Type:  ref.To(gatewayapi_v1beta1.HostnameAddressType),
+				Value: "gateway.projectcontour.io",
+			}}, gateway.Status.Addresses)
+
+			require.NoError(f.T(), f.DeleteGatewayClass(gatewayClass, false))
+		})
+	})
+
+	f.NamespacedTest("provisioner-without-endpoint-slices", func(namespace string) {
+		Specify("A Contour watching Endpoints instead of EndpointSlices routes traffic correctly", func() {
+			// Contour falls back to Endpoints, so only run where
+			// the cluster still serves them.
+			if err := f.Client.List(context.Background(), &corev1.EndpointsList{}, client.InNamespace(namespace)); err != nil {
+				Skip("cluster does not serve Endpoints: " + err.Error())
+			}
+
+			gateway, gatewayClass, err := f.Provisioner.NewGatewayWithParams(namespace, "without-endpoint-slices", &contour_api_v1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "without-endpoint-slices-params",
+				},
+				Spec: contour_api_v1alpha1.ContourDeploymentSpec{
+					Contour: &contour_api_v1alpha1.ContourSettings{
+						UseEndpointSlices: ref.To(false),
+					},
+					RuntimeSettings: contourDeploymentRuntimeSettings(),
+				},
+			})
+			require.NoError(f.T(), err)
+
+			contourConfig := &contour_api_v1alpha1.ContourConfiguration{}
+			require.NoError(f.T(), f.Client.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: "contourconfig-" + gateway.Name}, contourConfig))
+			assert.Equal(f.T(), ref.To(false), contourConfig.Spec.UseEndpointSlices)
+
+			require.Eventually(f.T(), func() bool {
+				if err := f.Client.Get(context.Background(), client.ObjectKeyFromObject(gateway), gateway); err != nil {
+					return false
+				}
+				return gatewayHasAddress(gateway)
+			}, f.RetryTimeout, f.RetryInterval)
+
+			f.Fixtures.Echo.Deploy(namespace, "echo")
+
+			route := &gatewayapi_v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "httproute-1",
+				},
+				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+					Hostnames: []gatewayapi_v1beta1.Hostname{"without-endpoint-slices.provisioner.projectcontour.io"},
+					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1beta1.ParentReference{
+							gatewayapi.GatewayParentRef("", gateway.Name),
+						},
+					},
+					Rules: []gatewayapi_v1beta1.HTTPRouteRule{
+						{
+							BackendRefs: gatewayapi.HTTPBackendRef("echo", 80, 1),
+						},
+					},
+				},
+			}
+			_, ok := f.CreateHTTPRouteAndWaitFor(route, httpRouteAccepted)
+			require.True(f.T(), ok)
+
+			res, ok := f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+				OverrideURL: "http://" + net.JoinHostPort(gateway.Status.Addresses[0].Value, "80"),
+				Host:        string(route.Spec.Hostnames[0]),
+				Condition:   e2e.HasStatusCode(200),
+			})
+			require.NotNil(f.T(), res)
+			require.Truef(f.T(), ok, "expected 200 response code, got %d", res.StatusCode)
+
+			body := f.GetEchoResponseBody(res.Body)
+			assert.Equal(f.T(), namespace, body.Namespace)
+			assert.Equal(f.T(), "echo", body.Service)
+
+			require.NoError(f.T(), f.DeleteGatewayClass(gatewayClass, false))
+		})
+	})
+
+	f.NamespacedTest("provisioner-contourdeployment-admission", func(namespace string) {
+		Specify("The API server rejects invalid ContourDeployments", func() {
+			invalid := &contour_api_v1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "invalid-params",
+				},
+				Spec: contour_api_v1alpha1.ContourDeploymentSpec{
+					Envoy: &contour_api_v1alpha1.EnvoySettings{
+						WorkloadType: contour_api_v1alpha1.WorkloadTypeDeployment,
+						NetworkPublishing: &contour_api_v1alpha1.NetworkPublishing{
+							Type:                  contour_api_v1alpha1.NodePortServicePublishingType,
+							ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeLocal,
+						},
+					},
+				},
+			}
+			err := f.Client.Create(context.Background(), invalid)
+			require.Error(f.T(), err)
+			assert.Contains(f.T(), err.Error(), "a NodePortService with a Local externalTrafficPolicy requires spec.envoy.workloadType DaemonSet")
+
+			valid := &contour_api_v1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "valid-params",
+				},
+				Spec: contour_api_v1alpha1.ContourDeploymentSpec{
+					Envoy: &contour_api_v1alpha1.EnvoySettings{
+						WorkloadType: contour_api_v1alpha1.WorkloadTypeDeployment,
+					},
+				},
+			}
+			require.NoError(f.T(), f.Client.Create(context.Background(), valid))
+
+			// Updates that make it invalid are rejected too.
+			valid.Spec.Envoy.DaemonSet = &contour_api_v1alpha1.DaemonSetSettings{}
+			err = f.Client.Update(context.Background(), valid)
+			require.Error(f.T(), err)
+			assert.Contains(f.T(), err.Error(), "spec.envoy.daemonSet, must not be set when spec.envoy.workloadType is Deployment")
+		})
+	})
+
+	f.NamespacedTest("provisioner-xds-ecdsa-certificates", func(namespace string) {
+		Specify("Envoy gets its configuration from Contour over xDS with ECDSA certificates", func() {
+			gateway, gatewayClass, err := f.Provisioner.NewGatewayWithParams(namespace, "xds-ecdsa", &contour_api_v1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "xds-ecdsa-params",
+				},
+				Spec: contour_api_v1alpha1.ContourDeploymentSpec{
+					XDSServer: &contour_api_v1alpha1.XDSServerSettings{
+						TLS: &contour_api_v1alpha1.XDSServerTLSSettings{
+							KeyType:             contour_api_v1alpha1.XDSServerKeyTypeECDSA,
+							CertificateLifetime: 30,
+						},
+					},
+					RuntimeSettings: contourDeploymentRuntimeSettings(),
+				},
+			})
+			require.NoError(f.T(), err)
+
+			// The generated certificates have ECDSA keys.
+			for _, name := range []string{"contourcert-" + gateway.Name, "envoycert-" + gateway.Name} {
+				secret := &corev1.Secret{}
+				require.NoError(f.T(), f.Client.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: name}, secret))
+
+				pair, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+				require.NoError(f.T(), err)
+				assert.IsType(f.T(), &ecdsa.PrivateKey{}, pair.PrivateKey)
+			}
+
+			require.Eventually(f.T(), func() bool {
+				if err := f.Client.Get(context.Background(), client.ObjectKeyFromObject(gateway), gateway); err != nil {
+					return false
+				}
+				return gatewayHasAddress(gateway)
+			}, f.RetryTimeout, f.RetryInterval)
+
+			f.Fixtures.Echo.Deploy(namespace, "echo")
+
+			route := &gatewayapi_v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "httproute-1",
+				},
+				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+					Hostnames: []gatewayapi_v1beta1.Hostname{"xds-ecdsa.provisioner.projectcontour.io"},
+					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1beta1.ParentReference{
+							gatewayapi.GatewayParentRef("", gateway.Name),
+						},
+					},
+					Rules: []gatewayapi_v1beta1.HTTPRouteRule{
+						{
+							BackendRefs: gatewayapi.HTTPBackendRef("echo", 80, 1),
+						},
+					},
+				},
+			}
+			_, ok := f.CreateHTTPRouteAndWaitFor(route, httpRouteAccepted)
+			require.True(f.T(), ok)
+
+			// Envoy can only route the request if the xDS connection
+			// to Contour was established.
+			res, ok := f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+				OverrideURL: "http://" + net.JoinHostPort(gateway.Status.Addresses[0].Value, "80"),
+				Host:        string(route.Spec.Hostnames[0]),
+				Condition:   e2e.HasStatusCode(200),
+			})
+			require.NotNil(f.T(), res)
+			require.Truef(f.T(), ok, "expected 200 response code, got %d", res.StatusCode)
+			assert.Equal(f.T(), "echo", f.GetEchoResponseBody(res.Body).Service)
+
+			require.NoError(f.T(), f.DeleteGatewayClass(gatewayClass, false))
+		})
+	})
+
+	f.NamespacedTest("provisioner-envoy-min-ready-seconds", func(namespace string) {
+		Specify("Envoy rollouts wait for new pods to be ready for minReadySeconds", func() {
+			const minReadySeconds = 15
+
+			_, gatewayClass, err := f.Provisioner.NewGatewayWithParams(namespace, "min-ready-seconds", &contour_api_v1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "min-ready-seconds-params",
+				},
+				Spec: contour_api_v1alpha1.ContourDeploymentSpec{
+					Envoy: &contour_api_v1alpha1.EnvoySettings{
+						WorkloadType: contour_api_v1alpha1.WorkloadTypeDeployment,
+						Deployment: &contour_api_v1alpha1.DeploymentSettings{
+							Replicas: 2,
+							Strategy: &appsv1.DeploymentStrategy{
+								Type: appsv1.RollingUpdateDeploymentStrategyType,
+								RollingUpdate: &appsv1.RollingUpdateDeployment{
+									MaxSurge:       ref.To(intstr.FromInt(1)),
+									MaxUnavailable: ref.To(intstr.FromInt(0)),
+								},
+							},
+						},
+						MinReadySeconds: minReadySeconds,
+					},
+					RuntimeSettings: contourDeploymentRuntimeSettings(),
+				},
+			})
+			require.NoError(f.T(), err)
+
+			gateway := &gatewayapi_v1beta1.Gateway{}
+			require.Eventually(f.T(), func() bool {
+				if err := f.Client.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: "min-ready-seconds"}, gateway); err != nil {
+					return false
+				}
+				return gatewayHasAddress(gateway)
+			}, f.RetryTimeout, f.RetryInterval)
+
+			deploy := &appsv1.Deployment{}
+			deployKey := client.ObjectKey{Namespace: namespace, Name: "envoy-" + gateway.Name}
+			require.Eventually(f.T(), func() bool {
+				if err := f.Client.Get(context.Background(), deployKey, deploy); err != nil {
+					return false
+				}
+				return deploy.Status.AvailableReplicas == 2
+			}, 2*time.Minute, time.Second)
+			require.EqualValues(f.T(), minReadySeconds, deploy.Spec.MinReadySeconds)
+
+			f.Fixtures.Echo.Deploy(namespace, "echo")
+
+			route := &gatewayapi_v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "httproute-1",
+				},
+				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+					Hostnames: []gatewayapi_v1beta1.Hostname{"min-ready-seconds.provisioner.projectcontour.io"},
+					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1beta1.ParentReference{
+							gatewayapi.GatewayParentRef("", gateway.Name),
+						},
+					},
+					Rules: []gatewayapi_v1beta1.HTTPRouteRule{
+						{
+							BackendRefs: gatewayapi.HTTPBackendRef("echo", 80, 1),
+						},
+					},
+				},
+			}
+			_, ok := f.CreateHTTPRouteAndWaitFor(route, httpRouteAccepted)
+			require.True(f.T(), ok)
+
+			requestOpts := &e2e.HTTPRequestOpts{
+				OverrideURL: "http://" + net.JoinHostPort(gateway.Status.Addresses[0].Value, "80"),
+				Host:        string(route.Spec.Hostnames[0]),
+				Condition:   e2e.HasStatusCode(200),
+			}
+			res, ok := f.HTTP.RequestUntil(requestOpts)
+			require.NotNil(f.T(), res)
+			require.Truef(f.T(), ok, "expected 200 response code, got %d", res.StatusCode)
+
+			// Trigger a rollout of the Envoy Deployment by changing
+			// its pod template, the same way "kubectl rollout restart" does.
+			patch := client.MergeFrom(deploy.DeepCopy())
+			if deploy.Spec.Template.Annotations == nil {
+				deploy.Spec.Template.Annotations = map[string]string{}
+			}
+			deploy.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"] = time.Now().Format(time.RFC3339)
+			require.NoError(f.T(), f.Client.Patch(context.Background(), deploy, patch))
+			rolloutStart := time.Now()
+
+			// Traffic must keep flowing while the rollout progresses.
+			deadline := time.Now().Add(5 * time.Minute)
+			for {
+				require.True(f.T(), time.Now().Before(deadline), "timed out waiting for the Envoy Deployment rollout to complete")
+
+				res, err := f.HTTP.Request(requestOpts)
+				require.NoError(f.T(), err)
+				require.Equal(f.T(), 200, res.StatusCode)
+
+				current := &appsv1.Deployment{}
+				require.NoError(f.T(), f.Client.Get(context.Background(), deployKey, current))
+				require.GreaterOrEqual(f.T(), current.Status.AvailableReplicas, int32(2))
+
+				if current.Status.ObservedGeneration >= current.Generation &&
+					current.Status.UpdatedReplicas == 2 &&
+					current.Status.AvailableReplicas == 2 &&
+					current.Status.Replicas == 2 {
+					break
+				}
+
+				time.Sleep(time.Second)
+			}
+
+			// With a surge of one pod, the two new pods are rolled out one
+			// after the other, and each must be ready for minReadySeconds
+			// before the rollout continues.
+			assert.GreaterOrEqual(f.T(), time.Since(rolloutStart), 2*minReadySeconds*time.Second)
+
+			require.NoError(f.T(), f.DeleteGatewayClass(gatewayClass, false))
+		})
+	})
+
+	f.NamespacedTest("provisioner-allowed-hostname-suffixes", func(namespace string) {
+		Specify("Routes with hostnames outside of the allowed suffixes are not accepted", func() {
+			_, gatewayClass, err := f.Provisioner.NewGatewayWithParams(namespace, "allowed-hostname-suffixes", &contour_api_v1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "allowed-hostname-suffixes-params",
+				},
+				Spec: contour_api_v1alpha1.ContourDeploymentSpec{
+					Contour: &contour_api_v1alpha1.ContourSettings{
+						AllowedHostnameSuffixes: []string{"allowed.provisioner.projectcontour.io"},
+					},
+					RuntimeSettings: contourDeploymentRuntimeSettings(),
+				},
+			})
+			require.NoError(f.T(), err)
+
+			gateway := &gatewayapi_v1beta1.Gateway{}
+			require.Eventually(f.T(), func() bool {
+				if err := f.Client.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: "allowed-hostname-suffixes"}, gateway); err != nil {
+					return false
+				}
+				return gatewayHasAddress(gateway)
+			}, f.RetryTimeout, f.RetryInterval)
+
+			f.Fixtures.Echo.Deploy(namespace, "echo")
+
+			newRoute := func(name string, hostname gatewayapi_v1beta1.Hostname) *gatewayapi_v1beta1.HTTPRoute {
+				return &gatewayapi_v1beta1.HTTPRoute{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: namespace,
+						Name:      name,
+					},
+					Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+						Hostnames: []gatewayapi_v1beta1.Hostname{hostname},
+						CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+							ParentRefs: []gatewayapi_v1beta1.ParentReference{
+								gatewayapi.GatewayParentRef("", gateway.Name),
+							},
+						},
+						Rules: []gatewayapi_v1beta1.HTTPRouteRule{
+							{
+								BackendRefs: gatewayapi.HTTPBackendRef("echo", 80, 1),
+							},
+						},
+					},
+				}
+			}
+
+			allowed := newRoute("allowed", "echo.allowed.provisioner.projectcontour.io")
+			_, ok := f.CreateHTTPRouteAndWaitFor(allowed, httpRouteAccepted)
+			require.True(f.T(), ok)
+
+			disallowed := newRoute("disallowed", "echo.provisioner.projectcontour.io")
+			_, ok = f.CreateHTTPRouteAndWaitFor(disallowed, func(route *gatewayapi_v1beta1.HTTPRoute) bool {
+				for _, parent := range route.Status.Parents {
+					for _, cond := range parent.Conditions {
+						if cond.Type == string(gatewayapi_v1beta1.RouteConditionAccepted) &&
+							cond.Status == metav1.ConditionFalse &&
+							cond.Reason == "HostnameNotAllowed" {
+							return true
+						}
+					}
+				}
+				return false
+			})
+			require.True(f.T(), ok, "route with a disallowed hostname was not rejected")
+
+			addr := "http://" + net.JoinHostPort(gateway.Status.Addresses[0].Value, "80")
+			res, ok := f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+				OverrideURL: addr,
+				Host:        string(allowed.Spec.Hostnames[0]),
+				Condition:   e2e.HasStatusCode(200),
+			})
+			require.NotNil(f.T(), res)
+			require.Truef(f.T(), ok, "expected 200 response code, got %d", res.StatusCode)
+
+			// The disallowed route is not programmed, so Envoy has no
+			// virtual host for its hostname.
+			res, ok = f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+				OverrideURL: addr,
+				Host:        string(disallowed.Spec.Hostnames[0]),
+				Condition:   e2e.HasStatusCode(404),
+			})
+			require.NotNil(f.T(), res)
+			require.Truef(f.T(), ok, "expected 404 response code, got %d", res.StatusCode)
+
+			require.NoError(f.T(), f.DeleteGatewayClass(gatewayClass, false))
+		})
+	})
+
+	f.NamespacedTest("provisioner-listener-keepalive", func(namespace string) {
+		Specify("Envoy sends HTTP/2 keepalive pings on idle connections", func() {
+			_, gatewayClass, err := f.Provisioner.NewGatewayWithParams(namespace, "listener-keepalive", &contour_api_v1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "listener-keepalive-params",
+				},
+				Spec: contour_api_v1alpha1.ContourDeploymentSpec{
+					Envoy: &contour_api_v1alpha1.EnvoySettings{
+						Listener: &contour_api_v1alpha1.EnvoyListenerSettings{
+							KeepAlive: &contour_api_v1alpha1.EnvoyListenerKeepAlive{
+								TCP: &contour_api_v1alpha1.TCPKeepAlive{
+									IdleTime: 30,
+								},
+								HTTP2: &contour_api_v1alpha1.HTTP2KeepAlive{
+									Interval: "1s",
+									Timeout:  "5s",
+								},
+							},
+						},
+					},
+					RuntimeSettings: contourDeploymentRuntimeSettings(),
+				},
+			})
+			require.NoError(f.T(), err)
+
+			gateway := &gatewayapi_v1beta1.Gateway{}
+			require.Eventually(f.T(), func() bool {
+				if err := f.Client.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: "listener-keepalive"}, gateway); err != nil {
+					return false
+				}
+				return gatewayHasAddress(gateway)
+			}, f.RetryTimeout, f.RetryInterval)
+
+			f.Fixtures.Echo.Deploy(namespace, "echo")
+
+			route := &gatewayapi_v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "httproute-1",
+				},
+				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+					Hostnames: []gatewayapi_v1beta1.Hostname{"keepalive.provisioner.projectcontour.io"},
+					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1beta1.ParentReference{
+							gatewayapi.GatewayParentRef("", gateway.Name),
+						},
+					},
+					Rules: []gatewayapi_v1beta1.HTTPRouteRule{
+						{
+							BackendRefs: gatewayapi.HTTPBackendRef("echo", 80, 1),
+						},
+					},
+				},
+			}
+			_, ok := f.CreateHTTPRouteAndWaitFor(route, httpRouteAccepted)
+			require.True(f.T(), ok)
+
+			addr := net.JoinHostPort(gateway.Status.Addresses[0].Value, "80")
+			res, ok := f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+				OverrideURL: "http://" + addr,
+				Host:        string(route.Spec.Hostnames[0]),
+				Condition:   e2e.HasStatusCode(200),
+			})
+			require.NotNil(f.T(), res)
+			require.Truef(f.T(), ok, "expected 200 response code, got %d", res.StatusCode)
+
+			// Open an HTTP/2 (prior knowledge) connection and leave it
+			// idle, acknowledging the PING frames Envoy sends on it.
+			conn, err := net.Dial("tcp", addr)
+			require.NoError(f.T(), err)
+			defer conn.Close()
+
+			_, err = io.WriteString(conn, http2.ClientPreface)
+			require.NoError(f.T(), err)
+
+			framer := http2.NewFramer(conn, conn)
+			require.NoError(f.T(), framer.WriteSettings())
+
+			pings := 0
+			require.NoError(f.T(), conn.SetReadDeadline(time.Now().Add(10*time.Second)))
+			for pings < 3 {
+				frame, err := framer.ReadFrame()
+				require.NoError(f.T(), err, "expected PING frames on the idle connection")
+
+				switch frame := frame.(type) {
+				case *http2.SettingsFrame:
+					if !frame.IsAck() {
+						require.NoError(f.T(), framer.WriteSettingsAck())
+					}
+				case *http2.PingFrame:
+					if !frame.IsAck() {
+						pings++
+						require.NoError(f.T(), framer.WritePing(true, frame.Data))
+					}
+				case *http2.GoAwayFrame:
+					require.Failf(f.T(), "connection closed", "received GOAWAY with error code %s", frame.ErrCode)
+				}
+			}
+
+			require.NoError(f.T(), f.DeleteGatewayClass(gatewayClass, false))
+		})
+	})
+
+	f.NamespacedTest("provisioner-listener-http2-max-concurrent-streams", func(namespace string) {
+		Specify("Envoy refuses the streams opened beyond the limit on an HTTP/2 connection", func() {
+			const maxStreams = 2
+
+			gateway, gatewayClass, err := f.Provisioner.NewGatewayWithParams(namespace, "http2-max-streams", &contour_api_v1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "http2-max-streams-params",
+				},
+				Spec: contour_api_v1alpha1.ContourDeploymentSpec{
+					Envoy: &contour_api_v1alpha1.EnvoySettings{
+						Listener: &contour_api_v1alpha1.EnvoyListenerSettings{
+							HTTP2MaxConcurrentStreams: ref.To(uint32(maxStreams)),
+						},
+					},
+					RuntimeSettings: contourDeploymentRuntimeSettings(),
+				},
+			})
+			require.NoError(f.T(), err)
+
+			require.Eventually(f.T(), func() bool {
+				if err := f.Client.Get(context.Background(), client.ObjectKeyFromObject(gateway), gateway); err != nil {
+					return false
+				}
+				return gatewayHasAddress(gateway)
+			}, f.RetryTimeout, f.RetryInterval)
+
+			f.Fixtures.Echo.Deploy(namespace, "echo")
+
+			route := &gatewayapi_v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "httproute-1",
+				},
+				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+					Hostnames: []gatewayapi_v1beta1.Hostname{"http2-max-streams.provisioner.projectcontour.io"},
+					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1beta1.ParentReference{
+							gatewayapi.GatewayParentRef("", gateway.Name),
+						},
+					},
+					Rules: []gatewayapi_v1beta1.HTTPRouteRule{
+						{
+							BackendRefs: gatewayapi.HTTPBackendRef("echo", 80, 1),
+						},
+					},
+				},
+			}
+			_, ok := f.CreateHTTPRouteAndWaitFor(route, httpRouteAccepted)
+			require.True(f.T(), ok)
+
+			addr := net.JoinHostPort(gateway.Status.Addresses[0].Value, "80")
+			res, ok := f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+				OverrideURL: "http://" + addr,
+				Host:        string(route.Spec.Hostnames[0]),
+				Condition:   e2e.HasStatusCode(200),
+			})
+			require.NotNil(f.T(), res)
+			require.Truef(f.T(), ok, "expected 200 response code, got %d", res.StatusCode)
+
+			// Open an HTTP/2 (prior knowledge) connection and wait for
+			// Envoy to advertise the limit.
+			conn, err := net.Dial("tcp", addr)
+			require.NoError(f.T(), err)
+			defer conn.Close()
+
+			_, err = io.WriteString(conn, http2.ClientPreface)
+			require.NoError(f.T(), err)
+
+			framer := http2.NewFramer(conn, conn)
+			require.NoError(f.T(), framer.WriteSettings())
+			require.NoError(f.T(), conn.SetReadDeadline(time.Now().Add(10*time.Second)))
+
+			for {
+				frame, err := framer.ReadFrame()
+				require.NoError(f.T(), err, "expected a SETTINGS frame from Envoy")
+
+				settings, ok := frame.(*http2.SettingsFrame)
+				if !ok || settings.IsAck() {
+					continue
+				}
+				streams, ok := settings.Value(http2.SettingMaxConcurrentStreams)
+				require.True(f.T(), ok, "expected Envoy to advertise SETTINGS_MAX_CONCURRENT_STREAMS")
+				assert.Equal(f.T(), uint32(maxStreams), streams)
+				require.NoError(f.T(), framer.WriteSettingsAck())
+				break
+			}
+
+			// Open one stream more than the limit. The requests do not end
+			// their stream, so that the streams stay open.
+			var headers bytes.Buffer
+			encoder := hpack.NewEncoder(&headers)
+			for _, field := range []hpack.HeaderField{
+				{Name: ":method", Value: "POST"},
+				{Name: ":scheme", Value: "http"},
+				{Name: ":authority", Value: string(route.Spec.Hostnames[0])},
+				{Name: ":path", Value: "/"},
+			} {
+				require.NoError(f.T(), encoder.WriteField(field))
+			}
+
+			excessStream := uint32(2*maxStreams + 1)
+			for stream := uint32(1); stream <= excessStream; stream += 2 {
+				require.NoError(f.T(), framer.WriteHeaders(http2.HeadersFrameParam{
+					StreamID:      stream,
+					BlockFragment: headers.Bytes(),
+					EndHeaders:    true,
+				}))
+			}
+
+			// Only the excess stream is reset.
+			var reset *http2.RSTStreamFrame
+			for reset == nil {
+				frame, err := framer.ReadFrame()
+				require.NoError(f.T(), err, "expected the excess stream to be refused")
+
+				switch frame := frame.(type) {
+				case *http2.SettingsFrame:
+					if !frame.IsAck() {
+						require.NoError(f.T(), framer.WriteSettingsAck())
+					}
+				case *http2.RSTStreamFrame:
+					reset = frame
+				case *http2.GoAwayFrame:
+					require.Failf(f.T(), "connection closed", "received GOAWAY with error code %s", frame.ErrCode)
+				}
+			}
+			require.Equalf(f.T(), excessStream, reset.StreamID, "stream %d reset with error code %s", reset.StreamID, reset.ErrCode)
+			assert.Contains(f.T(), []http2.ErrCode{http2.ErrCodeRefusedStream, http2.ErrCodeProtocol}, reset.ErrCode)
+
+			require.NoError(f.T(), f.DeleteGatewayClass(gatewayClass, false))
+		})
+	})
+
+	f.NamespacedTest("provisioner-envoy-extra-args", func(namespace string) {
+		Specify("Extra args from the ContourDeployment are passed to Envoy", func() {
+			gateway, gatewayClass, err := f.Provisioner.NewGatewayWithParams(namespace, "envoy-extra-args", &contour_api_v1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "envoy-extra-args-params",
+				},
+				Spec: contour_api_v1alpha1.ContourDeploymentSpec{
+					Envoy: &contour_api_v1alpha1.EnvoySettings{
+						ExtraArgs: []string{"--log-format [%Y-%m-%d %T.%e][%l] %v"},
+					},
+					RuntimeSettings: contourDeploymentRuntimeSettings(),
+				},
+			})
+			require.NoError(f.T(), err)
+
+			envoyDaemonSet := &appsv1.DaemonSet{}
+			require.NoError(f.T(), f.Client.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: "envoy-" + gateway.Name}, envoyDaemonSet))
+
+			var envoyContainer *corev1.Container
+			for i := range envoyDaemonSet.Spec.Template.Spec.Containers {
+				if envoyDaemonSet.Spec.Template.Spec.Containers[i].Name == "envoy" {
+					envoyContainer = &envoyDaemonSet.Spec.Template.Spec.Containers[i]
+				}
+			}
+			require.NotNil(f.T(), envoyContainer)
+			assert.Contains(f.T(), envoyContainer.Args, "--log-format [%Y-%m-%d %T.%e][%l] %v")
+
+			// Envoy starts with the extra arg and becomes ready.
+			require.Eventually(f.T(), func() bool {
+				if err := f.Client.Get(context.Background(), client.ObjectKeyFromObject(envoyDaemonSet), envoyDaemonSet); err != nil {
+					return false
+				}
+				return envoyDaemonSet.Status.DesiredNumberScheduled > 0 &&
+					envoyDaemonSet.Status.NumberReady == envoyDaemonSet.Status.DesiredNumberScheduled
+			}, f.RetryTimeout, f.RetryInterval)
+
+			// Managed args are rejected.
+			invalid := &contour_api_v1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "envoy-managed-args-params",
+				},
+				Spec: contour_api_v1alpha1.ContourDeploymentSpec{
+					Envoy: &contour_api_v1alpha1.EnvoySettings{
+						ExtraArgs: []string{"--base-id 10"},
+					},
+				},
+			}
+			err = f.Client.Create(context.Background(), invalid)
+			require.Error(f.T(), err)
+			assert.Contains(f.T(), err.Error(), "--base-id is managed by the provisioner")
+
+			require.NoError(f.T(), f.DeleteGatewayClass(gatewayClass, false))
+		})
+	})
+
+	f.NamespacedTest("provisioner-headless-service", func(namespace string) {
+		Specify("Requests to a headless Service are load balanced across its pods", func() {
+			gateway, gatewayClass, err := f.Provisioner.NewGatewayWithParams(namespace, "headless-service", &contour_api_v1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "headless-service-params",
+				},
+				Spec: contour_api_v1alpha1.ContourDeploymentSpec{
+					RuntimeSettings: contourDeploymentRuntimeSettings(),
+				},
+			})
+			require.NoError(f.T(), err)
+
+			require.Eventually(f.T(), func() bool {
+				if err := f.Client.Get(context.Background(), client.ObjectKeyFromObject(gateway), gateway); err != nil {
+					return false
+				}
+				return gatewayHasAddress(gateway)
+			}, f.RetryTimeout, f.RetryInterval)
+
+			f.Fixtures.Echo.DeployHeadlessStatefulSetN(namespace, "echo", 2)
+
+			route := &gatewayapi_v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "httproute-1",
+				},
+				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+					Hostnames: []gatewayapi_v1beta1.Hostname{"headless-service.provisioner.projectcontour.io"},
+					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1beta1.ParentReference{
+							gatewayapi.GatewayParentRef("", gateway.Name),
+						},
+					},
+					Rules: []gatewayapi_v1beta1.HTTPRouteRule{
+						{
+							BackendRefs: gatewayapi.HTTPBackendRef("echo", 80, 1),
+						},
+					},
+				},
+			}
+			_, ok := f.CreateHTTPRouteAndWaitFor(route, httpRouteAccepted)
+			require.True(f.T(), ok)
+
+			// Requests keep being made until each pod has served
+			// at least one of them.
+			pods := map[string]bool{}
+			servedByPods := func(n int) func(*e2e.HTTPResponse) bool {
+				return func(res *e2e.HTTPResponse) bool {
+					if res.StatusCode != http.StatusOK {
+						return false
+					}
+					pods[f.GetEchoResponseBody(res.Body).Pod] = true
+					return len(pods) >= n
+				}
+			}
+
+			res, ok := f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+				OverrideURL: "http://" + net.JoinHostPort(gateway.Status.Addresses[0].Value, "80"),
+				Host:        string(route.Spec.Hostnames[0]),
+				Condition:   servedByPods(2),
+			})
+			require.NotNil(f.T(), res)
+			require.Truef(f.T(), ok, "expected requests to be served by 2 pods, got %v", pods)
+			assert.Equal(f.T(), map[string]bool{"echo-0": true, "echo-1": true}, pods)
+
+			// Scaling up the StatefulSet adds its new pod to the
+			// endpoints of the route.
+			statefulSet := &appsv1.StatefulSet{}
+			require.NoError(f.T(), f.Client.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: "echo"}, statefulSet))
+			statefulSet.Spec.Replicas = ref.To(int32(3))
+			require.NoError(f.T(), f.Client.Update(context.Background(), statefulSet))
+
+			res, ok = f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+				OverrideURL: "http://" + net.JoinHostPort(gateway.Status.Addresses[0].Value, "80"),
+				Host:        string(route.Spec.Hostnames[0]),
+				Condition:   servedByPods(3),
+			})
+			require.NotNil(f.T(), res)
+			require.Truef(f.T(), ok, "expected requests to be served by 3 pods, got %v", pods)
+			assert.True(f.T(), pods["echo-2"])
+
+			require.NoError(f.T(), f.DeleteGatewayClass(gatewayClass, false))
+		})
+	})
+
+	f.NamespacedTest("provisioner-topology-aware-routing", func(namespace string) {
+		Specify("Requests are routed to the endpoints in the zone of the Envoy serving them", func() {
+			nodes := &corev1.NodeList{}
+			require.NoError(f.T(), f.Client.List(context.Background(), nodes))
+			nodeZones := map[string]bool{}
+			for _, node := range nodes.Items {
+				if zone := node.Labels[corev1.LabelTopologyZone]; zone != "" {
+					nodeZones[zone] = true
+				}
+			}
+			if len(nodeZones) < 2 {
+				Skip("topology aware routing requires nodes in at least two zones")
+			}
+
+			runtimeSettings := contourDeploymentRuntimeSettings()
+			if runtimeSettings == nil {
+				runtimeSettings = &contour_api_v1alpha1.ContourConfigurationSpec{}
+			}
+			if runtimeSettings.Envoy == nil {
+				runtimeSettings.Envoy = &contour_api_v1alpha1.EnvoyConfig{}
+			}
+			runtimeSettings.Envoy.Cluster = &contour_api_v1alpha1.ClusterParameters{
+				DNSLookupFamily: contour_api_v1alpha1.AutoClusterDNSFamily,
+				TopologyAwareRouting: &contour_api_v1alpha1.TopologyAwareRoutingConfig{
+					MinClusterSize: 1,
+				},
+			}
+
+			gateway, gatewayClass, err := f.Provisioner.NewGatewayWithParams(namespace, "topology-aware-routing", &contour_api_v1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "topology-aware-routing-params",
+				},
+				Spec: contour_api_v1alpha1.ContourDeploymentSpec{
+					Envoy: &contour_api_v1alpha1.EnvoySettings{
+						WorkloadType: contour_api_v1alpha1.WorkloadTypeDeployment,
+						Deployment: &contour_api_v1alpha1.DeploymentSettings{
+							Replicas: 2,
+						},
+						// Run one Envoy in each of two zones.
+						NodePlacement: &contour_api_v1alpha1.NodePlacement{
+							TopologySpreadConstraints: []corev1.TopologySpreadConstraint{{
+								TopologyKey:       corev1.LabelTopologyZone,
+								MaxSkew:           1,
+								WhenUnsatisfiable: corev1.DoNotSchedule,
+							}},
+						},
+					},
+					RuntimeSettings: runtimeSettings,
+				},
+			})
+			require.NoError(f.T(), err)
+
+			require.Eventually(f.T(), func() bool {
+				if err := f.Client.Get(context.Background(), client.ObjectKeyFromObject(gateway), gateway); err != nil {
+					return false
+				}
+				return gatewayHasAddress(gateway)
+			}, f.RetryTimeout, f.RetryInterval)
+
+			// Envoy's init container looks up the zone label of the
+			// node the pod is bound to.
+			envoyZones := map[string]string{}
+			require.Eventually(f.T(), func() bool {
+				pods := &corev1.PodList{}
+				if err := f.Client.List(context.Background(), pods,
+					client.InNamespace(namespace), client.MatchingLabels{"app": "envoy-" + gateway.Name}); err != nil {
+					return false
+				}
+				envoyZones = map[string]string{}
+				for _, pod := range pods.Items {
+					node := &corev1.Node{}
+					if err := f.Client.Get(context.Background(), client.ObjectKey{Name: pod.Spec.NodeName}, node); err != nil {
+						return false
+					}
+					envoyZones[pod.Name] = node.Labels[corev1.LabelTopologyZone]
+				}
+				return len(envoyZones) == 2
+			}, f.RetryTimeout, f.RetryInterval)
+
+			var zones []string
+			for _, zone := range envoyZones {
+				zones = append(zones, zone)
+			}
+			sort.Strings(zones)
+			require.NotEqual(f.T(), zones[0], zones[1], "expected the Envoy pods to run in different zones")
+
+			f.Fixtures.Echo.DeployInZones(namespace, "echo", zones...)
+
+			route := &gatewayapi_v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "httproute-1",
+				},
+				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+					Hostnames: []gatewayapi_v1beta1.Hostname{"topology-aware-routing.provisioner.projectcontour.io"},
+					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1beta1.ParentReference{
+							gatewayapi.GatewayParentRef("", gateway.Name),
+						},
+					},
+					Rules: []gatewayapi_v1beta1.HTTPRouteRule{
+						{
+							BackendRefs: gatewayapi.HTTPBackendRef("echo", 80, 1),
+							// Identify the Envoy pod that served each request.
+							Filters: []gatewayapi_v1beta1.HTTPRouteFilter{
+								{
+									Type: gatewayapi_v1beta1.HTTPRouteFilterResponseHeaderModifier,
+									ResponseHeaderModifier: &gatewayapi_v1beta1.HTTPHeaderFilter{
+										Set: []gatewayapi_v1beta1.HTTPHeader{
+											{Name: "X-Envoy-Pod", Value: "%HOSTNAME%"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			_, ok := f.CreateHTTPRouteAndWaitFor(route, httpRouteAccepted)
+			require.True(f.T(), ok)
+
+			echoZones := map[string]string{}
+			echoZone := func(name string) string {
+				if zone, ok := echoZones[name]; ok {
+					return zone
+				}
+				pod := &corev1.Pod{}
+				if err := f.Client.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: name}, pod); err != nil {
+					return ""
+				}
+				echoZones[name] = pod.Labels["zone"]
+				return echoZones[name]
+			}
+
+			// Once Envoy has the zones of the endpoints, every request
+			// is served by the echo pod in the zone of its Envoy.
+			matches := 0
+			res, ok := f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+				OverrideURL: "http://" + net.JoinHostPort(gateway.Status.Addresses[0].Value, "80"),
+				Host:        string(route.Spec.Hostnames[0]),
+				Condition: func(res *e2e.HTTPResponse) bool {
+					if res.StatusCode != http.StatusOK {
+						matches = 0
+						return false
+					}
+					envoyZone := envoyZones[res.Headers.Get("X-Envoy-Pod")]
+					if envoyZone == "" || envoyZone != echoZone(f.GetEchoResponseBody(res.Body).Pod) {
+						matches = 0
+						return false
+					}
+					matches++
+					return matches >= 20
+				},
+			})
+			require.NotNil(f.T(), res)
+			require.True(f.T(), ok, "expected requests to be served in the zone of their Envoy")
+
+			// Without a ready endpoint in its zone, an Envoy falls back
+			// to the endpoints in the other zone.
+			require.NoError(f.T(), f.Client.Delete(context.Background(), &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "echo-0"},
+			}))
+
+			res, ok = f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+				OverrideURL: "http://" + net.JoinHostPort(gateway.Status.Addresses[0].Value, "80"),
+				Host:        string(route.Spec.Hostnames[0]),
+				Condition: func(res *e2e.HTTPResponse) bool {
+					if res.StatusCode != http.StatusOK {
+						return false
+					}
+					return envoyZones[res.Headers.Get("X-Envoy-Pod")] == zones[0] &&
+						echoZone(f.GetEchoResponseBody(res.Body).Pod) == zones[1]
+				},
+			})
+			require.NotNil(f.T(), res)
+			require.True(f.T(), ok, "expected the Envoy in zone %s to fall back to zone %s", zones[0], zones[1])
+
+			require.NoError(f.T(), f.DeleteGatewayClass(gatewayClass, false))
+		})
+	})
+
+	f.NamespacedTest("provisioner-envoy-workload-type-cleanup", func(namespace string) {
+		Specify("The Envoy DaemonSet is deleted once the workload type is switched to a Deployment", func() {
+			params := &contour_api_v1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "workload-type-params",
+				},
+				Spec: contour_api_v1alpha1.ContourDeploymentSpec{
+					Envoy: &contour_api_v1alpha1.EnvoySettings{
+						WorkloadType: contour_api_v1alpha1.WorkloadTypeDaemonSet,
+					},
+					RuntimeSettings: contourDeploymentRuntimeSettings(),
+				},
+			}
+			gateway, gatewayClass, err := f.Provisioner.NewGatewayWithParams(namespace, "workload-type", params)
+			require.NoError(f.T(), err)
+
+			require.Eventually(f.T(), func() bool {
+				if err := f.Client.Get(context.Background(), client.ObjectKeyFromObject(gateway), gateway); err != nil {
+					return false
+				}
+				return gatewayHasAddress(gateway)
+			}, f.RetryTimeout, f.RetryInterval)
+
+			// The first reconcile creates an Envoy DaemonSet, controlled
+			// by the Gateway.
+			dataPlaneKey := client.ObjectKey{Namespace: namespace, Name: "envoy-" + gateway.Name}
+			daemonSet := &appsv1.DaemonSet{}
+			require.Eventually(f.T(), func() bool {
+				return f.Client.Get(context.Background(), dataPlaneKey, daemonSet) == nil
+			}, f.RetryTimeout, f.RetryInterval)
+			require.NotNil(f.T(), metav1.GetControllerOf(daemonSet))
+			assert.Equal(f.T(), gateway.UID, metav1.GetControllerOf(daemonSet).UID)
+
+			// Switch to a Deployment, and annotate the Gateway to have it
+			// reconciled again.
+			require.NoError(f.T(), f.Client.Get(context.Background(), client.ObjectKeyFromObject(params), params))
+			params.Spec.Envoy.WorkloadType = contour_api_v1alpha1.WorkloadTypeDeployment
+			require.NoError(f.T(), f.Client.Update(context.Background(), params))
+
+			require.NoError(f.T(), retry.RetryOnConflict(retry.DefaultRetry, func() error {
+				if err := f.Client.Get(context.Background(), client.ObjectKeyFromObject(gateway), gateway); err != nil {
+					return err
+				}
+				if gateway.Annotations == nil {
+					gateway.Annotations = map[string]string{}
+				}
+				gateway.Annotations["projectcontour.io/e2e-reconcile"] = "workload-type-deployment"
+				return f.Client.Update(context.Background(), gateway)
+			}))
+
+			// The second reconcile creates the Deployment and deletes the
+			// orphaned DaemonSet.
+			require.Eventually(f.T(), func() bool {
+				return f.Client.Get(context.Background(), dataPlaneKey, &appsv1.Deployment{}) == nil
+			}, f.RetryTimeout, f.RetryInterval)
+			require.Eventually(f.T(), func() bool {
+				return api_errors.IsNotFound(f.Client.Get(context.Background(), dataPlaneKey, &appsv1.DaemonSet{}))
+			}, f.RetryTimeout, f.RetryInterval)
+
+			require.NoError(f.T(), f.DeleteGatewayClass(gatewayClass, false))
+		})
+	})
+
+	f.NamespacedTest("provisioner-external-name-service", func(namespace string) {
+		Specify("ExternalName Services are only routed to when enabled in the ContourDeployment", func() {
+			f.Fixtures.Echo.Deploy(namespace, "echo")
+
+			externalNameService := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "external-echo",
+				},
+				Spec: corev1.ServiceSpec{
+					Type:         corev1.ServiceTypeExternalName,
+					ExternalName: "echo." + namespace,
+					Ports: []corev1.ServicePort{
+						{
+							Name: "http",
+							Port: 80,
+						},
+					},
+				},
+			}
+			require.NoError(f.T(), f.Client.Create(context.Background(), externalNameService))
+
+			// newGatewayAndRoute provisions a Gateway with the given Contour
+			// settings, and routes its requests to the ExternalName Service.
+			newGatewayAndRoute := func(name string, contour *contour_api_v1alpha1.ContourSettings, condition func(*gatewayapi_v1beta1.HTTPRoute) bool) (*gatewayapi_v1beta1.Gateway, *gatewayapi_v1beta1.GatewayClass, *gatewayapi_v1beta1.HTTPRoute) {
+				gateway, gatewayClass, err := f.Provisioner.NewGatewayWithParams(namespace, name, &contour_api_v1alpha1.ContourDeployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: name + "-params",
+					},
+					Spec: contour_api_v1alpha1.ContourDeploymentSpec{
+						Contour:         contour,
+						RuntimeSettings: contourDeploymentRuntimeSettings(),
+					},
+				})
+				require.NoError(f.T(), err)
+
+				require.Eventually(f.T(), func() bool {
+					if err := f.Client.Get(context.Background(), client.ObjectKeyFromObject(gateway), gateway); err != nil {
+						return false
+					}
+					return gatewayHasAddress(gateway)
+				}, f.RetryTimeout, f.RetryInterval)
+
+				route := &gatewayapi_v1beta1.HTTPRoute{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: namespace,
+						Name:      name,
+					},
+					Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+						Hostnames: []gatewayapi_v1beta1.Hostname{gatewayapi_v1beta1.Hostname(name + ".provisioner.projectcontour.io")},
+						CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+							ParentRefs: []gatewayapi_v1beta1.ParentReference{
+								gatewayapi.GatewayParentRef("", gateway.Name),
+							},
+						},
+						Rules: []gatewayapi_v1beta1.HTTPRouteRule{
+							{
+								BackendRefs: gatewayapi.HTTPBackendRef(externalNameService.Name, 80, 1),
+							},
+						},
+					},
+				}
+				route, ok := f.CreateHTTPRouteAndWaitFor(route, condition)
+				require.True(f.T(), ok)
+
+				return gateway, gatewayClass, route
+			}
+
+			// By default, the route's reference to the ExternalName
+			// Service is not resolved, and its requests get 500s.
+			gateway, gatewayClass, route := newGatewayAndRoute("external-name-disabled", nil, httpRouteRefsNotResolved)
+
+			res, ok := f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+				OverrideURL: "http://" + net.JoinHostPort(gateway.Status.Addresses[0].Value, "80"),
+				Host:        string(route.Spec.Hostnames[0]),
+				Condition:   e2e.HasStatusCode(500),
+			})
+			require.NotNil(f.T(), res)
+			require.Truef(f.T(), ok, "expected 500 response code, got %d", res.StatusCode)
+
+			require.NoError(f.T(), f.DeleteGatewayClass(gatewayClass, false))
+
+			// Once enabled, the requests are routed to the Service's
+			// external name.
+			gateway, gatewayClass, route = newGatewayAndRoute("external-name-enabled", &contour_api_v1alpha1.ContourSettings{
+				EnableExternalNameService: ref.To(true),
+			}, httpRouteAccepted)
+
+			res, ok = f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+				OverrideURL: "http://" + net.JoinHostPort(gateway.Status.Addresses[0].Value, "80"),
+				Host:        string(route.Spec.Hostnames[0]),
+				Condition:   e2e.HasStatusCode(200),
+			})
+			require.NotNil(f.T(), res)
+			require.Truef(f.T(), ok, "expected 200 response code, got %d", res.StatusCode)
+			assert.Equal(f.T(), "echo", f.GetEchoResponseBody(res.Body).Service)
+
+			require.NoError(f.T(), f.DeleteGatewayClass(gatewayClass, false))
+		})
+	})
+
+	f.NamespacedTest("provisioner-envoy-stats-flush-interval", func(namespace string) {
+		Specify("Envoy's stats flush interval and histogram buckets are set in its bootstrap", func() {
+			_, gatewayClass, err := f.Provisioner.NewGatewayWithParams(namespace, "stats-flush-interval", &contour_api_v1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "stats-flush-interval-params",
+				},
+				Spec: contour_api_v1alpha1.ContourDeploymentSpec{
+					Envoy: &contour_api_v1alpha1.EnvoySettings{
+						Metrics: &contour_api_v1alpha1.EnvoyMetricsSettings{
+							FlushInterval: "10s",
+							HistogramBuckets: []contour_api_v1alpha1.EnvoyHistogramBuckets{{
+								Regex:   `cluster\..*\.upstream_rq_time`,
+								Buckets: []string{"0.5", "1", "5"},
+							}},
+						},
+					},
+					RuntimeSettings: contourDeploymentRuntimeSettings(),
+				},
+			})
+			require.NoError(f.T(), err)
+
+			gateway := &gatewayapi_v1beta1.Gateway{}
+			require.Eventually(f.T(), func() bool {
+				if err := f.Client.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: "stats-flush-interval"}, gateway); err != nil {
+					return false
+				}
+				return gatewayHasAddress(gateway)
+			}, f.RetryTimeout, f.RetryInterval)
+
+			envoyDaemonSet := &appsv1.DaemonSet{}
+			require.Eventually(f.T(), func() bool {
+				key := client.ObjectKey{Namespace: namespace, Name: "envoy-" + gateway.Name}
+				if err := f.Client.Get(context.Background(), key, envoyDaemonSet); err != nil {
+					return false
+				}
+				return envoyDaemonSet.Status.DesiredNumberScheduled > 0 &&
+					envoyDaemonSet.Status.NumberReady == envoyDaemonSet.Status.DesiredNumberScheduled
+			}, time.Minute, time.Second)
+
+			kubectlCmd, err := f.Kubectl.StartKubectlPortForward(19001, 9001, namespace, "daemonset/"+envoyDaemonSet.Name)
+			require.NoError(f.T(), err)
+			defer f.Kubectl.StopKubectlPortForward(kubectlCmd)
+
+			res, ok := f.HTTP.AdminRequestUntil(&e2e.HTTPRequestOpts{
+				Path:      "/config_dump",
+				Condition: e2e.HasStatusCode(200),
+			})
+			require.NotNil(f.T(), res)
+			require.Truef(f.T(), ok, "expected 200 response code, got %d", res.StatusCode)
+
+			// The bootstrap is the first config in Envoy's config dump.
+			var dump struct {
+				Configs []struct {
+					Type      string `json:"@type"`
+					Bootstrap struct {
+						StatsFlushInterval string `json:"stats_flush_interval"`
+						StatsConfig        struct {
+							HistogramBucketSettings []struct {
+								Match struct {
+									SafeRegex struct {
+										Regex string `json:"regex"`
+									} `json:"safe_regex"`
+								} `json:"match"`
+								Buckets []float64 `json:"buckets"`
+							} `json:"histogram_bucket_settings"`
+						} `json:"stats_config"`
+					} `json:"bootstrap"`
+				} `json:"configs"`
+			}
+			require.NoError(f.T(), json.Unmarshal(res.Body, &dump))
+			require.NotEmpty(f.T(), dump.Configs)
+
+			bootstrap := dump.Configs[0]
+			assert.Equal(f.T(), "type.googleapis.com/envoy.admin.v3.BootstrapConfigDump", bootstrap.Type)
+			assert.Equal(f.T(), "10s", bootstrap.Bootstrap.StatsFlushInterval)
+			require.Len(f.T(), bootstrap.Bootstrap.StatsConfig.HistogramBucketSettings, 1)
+			buckets := bootstrap.Bootstrap.StatsConfig.HistogramBucketSettings[0]
+			assert.Equal(f.T(), `cluster\..*\.upstream_rq_time`, buckets.Match.SafeRegex.Regex)
+			assert.Equal(f.T(), []float64{0.5, 1, 5}, buckets.Buckets)
+
+			require.NoError(f.T(), f.DeleteGatewayClass(gatewayClass, false))
+		})
+	})
+
+	f.NamespacedTest("provisioner-disabled-route-kinds", func(namespace string) {
+		Specify("Routes of a disabled kind are not accepted", func() {
+			gateway, gatewayClass, err := f.Provisioner.NewGatewayWithParams(namespace, "disabled-route-kinds", &contour_api_v1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "disabled-route-kinds-params",
+				},
+				Spec: contour_api_v1alpha1.ContourDeploymentSpec{
+					Contour: &contour_api_v1alpha1.ContourSettings{
+						DisabledRouteKinds: []string{"TLSRoute"},
+					},
+					RuntimeSettings: contourDeploymentRuntimeSettings(),
+				},
+			})
+			require.NoError(f.T(), err)
+
+			// Add a TLS passthrough listener for the disabled TLSRoutes.
+			require.NoError(f.T(), retry.RetryOnConflict(retry.DefaultRetry, func() error {
+				if err := f.Client.Get(context.Background(), client.ObjectKeyFromObject(gateway), gateway); err != nil {
+					return err
+				}
+				gateway.Spec.Listeners = append(gateway.Spec.Listeners[:1], gatewayapi_v1beta1.Listener{
+					Name:     "tls-passthrough",
+					Protocol: gatewayapi_v1beta1.TLSProtocolType,
+					Port:     gatewayapi_v1beta1.PortNumber(443),
+					TLS: &gatewayapi_v1beta1.GatewayTLSConfig{
+						Mode: ref.To(gatewayapi_v1beta1.TLSModePassthrough),
+					},
+					AllowedRoutes: &gatewayapi_v1beta1.AllowedRoutes{
+						Namespaces: &gatewayapi_v1beta1.RouteNamespaces{
+							From: ref.To(gatewayapi_v1beta1.NamespacesFromSame),
+						},
+					},
+				})
+				return f.Client.Update(context.Background(), gateway)
+			}))
+
+			require.Eventually(f.T(), func() bool {
+				if err := f.Client.Get(context.Background(), client.ObjectKeyFromObject(gateway), gateway); err != nil {
+					return false
+				}
+				return len(gateway.Status.Listeners) == 2 && gatewayProgrammed(gateway) && gatewayHasAddress(gateway)
+			}, f.RetryTimeout, f.RetryInterval)
+
+			f.Fixtures.Echo.Deploy(namespace, "echo")
+
+			tlsRoute := &gatewayapi_v1alpha2.TLSRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "tlsroute-1",
+				},
+				Spec: gatewayapi_v1alpha2.TLSRouteSpec{
+					Hostnames: []gatewayapi_v1alpha2.Hostname{"tls.disabled-route-kinds.provisioner.projectcontour.io"},
+					CommonRouteSpec: gatewayapi_v1alpha2.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1alpha2.ParentReference{
+							gatewayapi.GatewayParentRef("", gateway.Name),
+						},
+					},
+					Rules: []gatewayapi_v1alpha2.TLSRouteRule{{
+						BackendRefs: gatewayapi.TLSRouteBackendRef("echo", 80, nil),
+					}},
+				},
+			}
+			_, ok := f.CreateTLSRouteAndWaitFor(tlsRoute, func(route *gatewayapi_v1alpha2.TLSRoute) bool {
+				for _, parent := range route.Status.Parents {
+					for _, cond := range parent.Conditions {
+						if cond.Type == string(gatewayapi_v1beta1.RouteConditionAccepted) &&
+							cond.Status == metav1.ConditionFalse &&
+							cond.Reason == "RouteKindDisabled" {
+							return true
+						}
+					}
+				}
+				return false
+			})
+			require.True(f.T(), ok, "TLSRoute was not rejected")
+
+			// The TLS listener does not support any route kind, while
+			// the HTTP listener still supports HTTPRoutes.
+			require.NoError(f.T(), f.Client.Get(context.Background(), client.ObjectKeyFromObject(gateway), gateway))
+			for _, listener := range gateway.Status.Listeners {
+				switch listener.Name {
+				case "tls-passthrough":
+					assert.Empty(f.T(), listener.SupportedKinds)
+				case "http":
+					assert.NotEmpty(f.T(), listener.SupportedKinds)
+				}
+			}
+
+			httpRoute := &gatewayapi_v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "httproute-1",
+				},
+				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+					Hostnames: []gatewayapi_v1beta1.Hostname{"http.disabled-route-kinds.provisioner.projectcontour.io"},
+					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1beta1.ParentReference{
+							gatewayapi.GatewayParentRef("", gateway.Name),
+						},
+					},
+					Rules: []gatewayapi_v1beta1.HTTPRouteRule{
+						{
+							BackendRefs: gatewayapi.HTTPBackendRef("echo", 80, 1),
+						},
+					},
+				},
+			}
+			_, ok = f.CreateHTTPRouteAndWaitFor(httpRoute, httpRouteAccepted)
+			require.True(f.T(), ok)
+
+			res, ok := f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+				OverrideURL: "http://" + net.JoinHostPort(gateway.Status.Addresses[0].Value, "80"),
+				Host:        string(httpRoute.Spec.Hostnames[0]),
+				Condition:   e2e.HasStatusCode(200),
+			})
+			require.NotNil(f.T(), res)
+			require.Truef(f.T(), ok, "expected 200 response code, got %d", res.StatusCode)
+
+			require.NoError(f.T(), f.DeleteGatewayClass(gatewayClass, false))
+		})
+	})
+
+	f.NamespacedTest("provisioner-envoy-default-backend", func(namespace string) {
+		Specify("Requests that no route matches are routed to the default backend", func() {
+			gateway, gatewayClass, err := f.Provisioner.NewGatewayWithParams(namespace, "default-backend", &contour_api_v1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "default-backend-params",
+				},
+				Spec: contour_api_v1alpha1.ContourDeploymentSpec{
+					Envoy: &contour_api_v1alpha1.EnvoySettings{
+						DefaultBackend: &contour_api_v1alpha1.EnvoyDefaultBackend{
+							Name: "default-backend",
+							Port: 80,
+						},
+					},
+					RuntimeSettings: contourDeploymentRuntimeSettings(),
+				},
+			})
+			require.NoError(f.T(), err)
+
+			require.Eventually(f.T(), func() bool {
+				if err := f.Client.Get(context.Background(), client.ObjectKeyFromObject(gateway), gateway); err != nil {
+					return false
+				}
+				return gatewayHasAddress(gateway)
+			}, f.RetryTimeout, f.RetryInterval)
+
+			f.Fixtures.Echo.Deploy(namespace, "echo")
+			f.Fixtures.Echo.Deploy(namespace, "default-backend")
+
+			route := &gatewayapi_v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "httproute-1",
+				},
+				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+					Hostnames: []gatewayapi_v1beta1.Hostname{"default-backend.provisioner.projectcontour.io"},
+					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1beta1.ParentReference{
+							gatewayapi.GatewayParentRef("", gateway.Name),
+						},
+					},
+					Rules: []gatewayapi_v1beta1.HTTPRouteRule{
+						{
+							BackendRefs: gatewayapi.HTTPBackendRef("echo", 80, 1),
+						},
+					},
+				},
+			}
+			_, ok := f.CreateHTTPRouteAndWaitFor(route, httpRouteAccepted)
+			require.True(f.T(), ok)
+
+			for host, service := range map[string]string{
+				string(route.Spec.Hostnames[0]):           "echo",
+				"unmatched.provisioner.projectcontour.io": "default-backend",
+			} {
+				res, ok := f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+					OverrideURL: "http://" + net.JoinHostPort(gateway.Status.Addresses[0].Value, "80"),
+					Host:        host,
+					Condition:   e2e.HasStatusCode(200),
+				})
+				require.NotNil(f.T(), res)
+				require.Truef(f.T(), ok, "expected 200 response code for host %q, got %d", host, res.StatusCode)
+
+				body := f.GetEchoResponseBody(res.Body)
+				assert.Equal(f.T(), service, body.Service)
+			}
+
+			require.NoError(f.T(), f.DeleteGatewayClass(gatewayClass, false))
+		})
+	})
+
+	f.NamespacedTest("provisioner-envoy-read-only-root-filesystem", func(namespace string) {
+		Specify("Envoy starts and serves traffic with a read-only root filesystem", func() {
+			gateway, gatewayClass, err := f.Provisioner.NewGatewayWithParams(namespace, "read-only-root-filesystem", &contour_api_v1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "read-only-root-filesystem-params",
+				},
+				Spec: contour_api_v1alpha1.ContourDeploymentSpec{
+					Envoy: &contour_api_v1alpha1.EnvoySettings{
+						ReadOnlyRootFilesystem: true,
+					},
+					RuntimeSettings: contourDeploymentRuntimeSettings(),
+				},
+			})
+			require.NoError(f.T(), err)
+
+			require.Eventually(f.T(), func() bool {
+				if err := f.Client.Get(context.Background(), client.ObjectKeyFromObject(gateway), gateway); err != nil {
+					return false
+				}
+				return gatewayHasAddress(gateway)
+			}, f.RetryTimeout, f.RetryInterval)
+
+			// The Envoy pods only become ready if none of their
+			// containers needs to write outside of its volumes.
+			envoyDaemonSet := &appsv1.DaemonSet{}
+			require.Eventually(f.T(), func() bool {
+				key := client.ObjectKey{Namespace: namespace, Name: "envoy-" + gateway.Name}
+				if err := f.Client.Get(context.Background(), key, envoyDaemonSet); err != nil {
+					return false
+				}
+				return envoyDaemonSet.Status.DesiredNumberScheduled > 0 &&
+					envoyDaemonSet.Status.NumberReady == envoyDaemonSet.Status.DesiredNumberScheduled
+			}, time.Minute, time.Second)
+
+			for _, c := range envoyDaemonSet.Spec.Template.Spec.Containers {
+				require.NotNil(f.T(), c.SecurityContext, c.Name)
+				assert.Equal(f.T(), ref.To(true), c.SecurityContext.ReadOnlyRootFilesystem, c.Name)
+			}
+
+			f.Fixtures.Echo.Deploy(namespace, "echo")
+
+			route := &gatewayapi_v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "httproute-1",
+				},
+				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+					Hostnames: []gatewayapi_v1beta1.Hostname{"read-only-root-filesystem.provisioner.projectcontour.io"},
+					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1beta1.ParentReference{
+							gatewayapi.GatewayParentRef("", gateway.Name),
+						},
+					},
+					Rules: []gatewayapi_v1beta1.HTTPRouteRule{
+						{
+							BackendRefs: gatewayapi.HTTPBackendRef("echo", 80, 1),
+						},
+					},
+				},
+			}
+			_, ok := f.CreateHTTPRouteAndWaitFor(route, httpRouteAccepted)
+			require.True(f.T(), ok)
+
+			res, ok := f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+				OverrideURL: "http://" + net.JoinHostPort(gateway.Status.Addresses[0].Value, "80"),
+				Host:        string(route.Spec.Hostnames[0]),
+				Condition:   e2e.HasStatusCode(200),
+			})
+			require.NotNil(f.T(), res)
+			require.Truef(f.T(), ok, "expected 200 response code, got %d", res.StatusCode)
+
+			require.NoError(f.T(), f.DeleteGatewayClass(gatewayClass, false))
+		})
+	})
+
+	f.NamespacedTest("provisioner-envoy-extra-init-containers", func(namespace string) {
+		Specify("Extra init containers run before Envoy starts", func() {
+			gateway, gatewayClass, err := f.Provisioner.NewGatewayWithParams(namespace, "extra-init-containers", &contour_api_v1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "extra-init-containers-params",
+				},
+				Spec: contour_api_v1alpha1.ContourDeploymentSpec{
+					Envoy: &contour_api_v1alpha1.EnvoySettings{
+						ExtraVolumes: []corev1.Volume{{
+							Name: "secrets",
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{},
+							},
+						}},
+						ExtraVolumeMounts: []corev1.VolumeMount{{
+							Name:      "secrets",
+							MountPath: "/secrets",
+						}},
+						ExtraInitContainers: []corev1.Container{{
+							Name:    "fetch-secrets",
+							Image:   "docker.io/library/busybox:1.36",
+							Command: []string{"sh", "-c", "echo -n fetched > /secrets/token"},
+							VolumeMounts: []corev1.VolumeMount{{
+								Name:      "secrets",
+								MountPath: "/secrets",
+							}},
+						}},
+					},
+					RuntimeSettings: contourDeploymentRuntimeSettings(),
+				},
+			})
+			require.NoError(f.T(), err)
+
+			require.Eventually(f.T(), func() bool {
+				if err := f.Client.Get(context.Background(), client.ObjectKeyFromObject(gateway), gateway); err != nil {
+					return false
+				}
+				return gatewayHasAddress(gateway)
+			}, f.RetryTimeout, f.RetryInterval)
+
+			envoyDaemonSet := &appsv1.DaemonSet{}
+			require.Eventually(f.T(), func() bool {
+				key := client.ObjectKey{Namespace: namespace, Name: "envoy-" + gateway.Name}
+				if err := f.Client.Get(context.Background(), key, envoyDaemonSet); err != nil {
+					return false
+				}
+				return envoyDaemonSet.Status.DesiredNumberScheduled > 0 &&
+					envoyDaemonSet.Status.NumberReady == envoyDaemonSet.Status.DesiredNumberScheduled
+			}, time.Minute, time.Second)
+
+			initContainers := envoyDaemonSet.Spec.Template.Spec.InitContainers
+			require.Len(f.T(), initContainers, 2)
+			assert.Equal(f.T(), "fetch-secrets", initContainers[0].Name)
+
+			// The Envoy container sees the file written by the
+			// extra init container.
+			out, err := f.Kubectl.Exec(namespace, "daemonset/"+envoyDaemonSet.Name, "envoy", "cat", "/secrets/token")
+			require.NoError(f.T(), err)
+			assert.Equal(f.T(), "fetched", out)
+
+			require.NoError(f.T(), f.DeleteGatewayClass(gatewayClass, false))
+		})
+	})
+
+	f.NamespacedTest("provisioner-envoy-service-session-affinity", func(namespace string) {
+		Specify("The Envoy service has the ClientIP session affinity", func() {
+			gateway, gatewayClass, err := f.Provisioner.NewGatewayWithParams(namespace, "session-affinity", &contour_api_v1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "session-affinity-params",
+				},
+				Spec: contour_api_v1alpha1.ContourDeploymentSpec{
+					Envoy: &contour_api_v1alpha1.EnvoySettings{
+						NetworkPublishing: &contour_api_v1alpha1.NetworkPublishing{
+							SessionAffinity:               corev1.ServiceAffinityClientIP,
+							SessionAffinityTimeoutSeconds: 600,
+						},
+					},
+					RuntimeSettings: contourDeploymentRuntimeSettings(),
+				},
+			})
+			require.NoError(f.T(), err)
+
+			require.Eventually(f.T(), func() bool {
+				if err := f.Client.Get(context.Background(), client.ObjectKeyFromObject(gateway), gateway); err != nil {
+					return false
+				}
+				return gatewayHasAddress(gateway)
+			}, f.RetryTimeout, f.RetryInterval)
+
+			envoyService := &corev1.Service{}
+			key := client.ObjectKey{Namespace: namespace, Name: "envoy-" + gateway.Name}
+			require.NoError(f.T(), f.Client.Get(context.Background(), key, envoyService))
+
+			assert.Equal(f.T(), corev1.ServiceAffinityClientIP, envoyService.Spec.SessionAffinity)
+			require.NotNil(f.T(), envoyService.Spec.SessionAffinityConfig)
+			require.NotNil(f.T(), envoyService.Spec.SessionAffinityConfig.ClientIP)
+			assert.Equal(f.T(), ref.To(int32(600)), envoyService.Spec.SessionAffinityConfig.ClientIP.TimeoutSeconds)
+
+			require.NoError(f.T(), f.DeleteGatewayClass(gatewayClass, false))
+		})
+	})
+
+	f.NamespacedTest("provisioner-shared-params-gateway-deletion", func(namespace string) {
+		Specify("Deleting one Gateway of a shared ContourDeployment keeps the other Gateway working", func() {
+			gateway, gatewayClass, err := f.Provisioner.NewGatewayWithParams(namespace, "shared-1", &contour_api_v1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "shared-params",
+				},
+				Spec: contour_api_v1alpha1.ContourDeploymentSpec{
+					RuntimeSettings: contourDeploymentRuntimeSettings(),
+				},
+			})
+			require.NoError(f.T(), err)
+
+			// A second Gateway of the same class shares its ContourDeployment.
+			other := &gatewayapi_v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "shared-2",
+				},
+				Spec: *gateway.Spec.DeepCopy(),
+			}
+			_, ok := f.CreateGatewayAndWaitFor(other, gatewayProgrammed)
+			require.True(f.T(), ok)
+
+			f.Fixtures.Echo.Deploy(namespace, "echo")
+
+			gateways := []*gatewayapi_v1beta1.Gateway{gateway, other}
+			for _, gateway := range gateways {
+				require.Eventually(f.T(), func() bool {
+					if err := f.Client.Get(context.Background(), client.ObjectKeyFromObject(gateway), gateway); err != nil {
+						return false
+					}
+					return gatewayHasAddress(gateway)
+				}, f.RetryTimeout, f.RetryInterval)
+
+				route := &gatewayapi_v1beta1.HTTPRoute{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: namespace,
+						Name:      gateway.Name,
+					},
+					Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+						Hostnames: []gatewayapi_v1beta1.Hostname{"provisioner.projectcontour.io"},
+						CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+							ParentRefs: []gatewayapi_v1beta1.ParentReference{
+								gatewayapi.GatewayParentRef("", gateway.Name),
+							},
+						},
+						Rules: []gatewayapi_v1beta1.HTTPRouteRule{
+							{
+								Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
+								BackendRefs: gatewayapi.HTTPBackendRef("echo", 80, 1),
+							},
+						},
+					},
+				}
+				_, ok = f.CreateHTTPRouteAndWaitFor(route, httpRouteAccepted)
+				require.True(f.T(), ok)
+			}
+
+			requestGateway := func(gateway *gatewayapi_v1beta1.Gateway) {
+				res, ok := f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+					OverrideURL: "http://" + net.JoinHostPort(gateway.Status.Addresses[0].Value, "80"),
+					Host:        "provisioner.projectcontour.io",
+					Condition:   e2e.HasStatusCode(200),
+				})
+				require.NotNil(f.T(), res)
+				require.Truef(f.T(), ok, "expected 200 response code from gateway %s, got %d", gateway.Name, res.StatusCode)
+			}
+			for _, gateway := range gateways {
+				requestGateway(gateway)
+			}
+
+			// Deleting the first Gateway deletes the resources generated
+			// for it, including its ContourConfiguration.
+			require.NoError(f.T(), f.DeleteGateway(gateways[0], true))
+
+			require.Eventually(f.T(), func() bool {
+				for _, obj := range []client.Object{
+					&contour_api_v1alpha1.ContourConfiguration{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "contourconfig-" + gateways[0].Name}},
+					&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "contour-" + gateways[0].Name}},
+					&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "envoy-" + gateways[0].Name}},
+					&corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "envoy-" + gateways[0].Name}},
+				} {
+					if err := f.Client.Get(context.Background(), client.ObjectKeyFromObject(obj), obj); !api_errors.IsNotFound(err) {
+						return false
+					}
+				}
+				return true
+			}, f.RetryTimeout, f.RetryInterval)
+
+			// The second Gateway keeps its resources and keeps routing
+			// traffic.
+			contourConfig := &contour_api_v1alpha1.ContourConfiguration{}
+			require.NoError(f.T(), f.Client.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: "contourconfig-" + gateways[1].Name}, contourConfig))
+			requestGateway(gateways[1])
+
+			require.NoError(f.T(), f.DeleteGatewayClass(gatewayClass, false))
+		})
+	})
+
+	f.NamespacedTest("provisioner-envoy-gradual-drain", func(namespace string) {
+		Specify("Envoy closes connections gradually over its drain time when shutting down", func() {
+			_, gatewayClass, err := f.Provisioner.NewGatewayWithParams(namespace, "gradual-drain", &contour_api_v1alpha1.ContourDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "gradual-drain-params",
+				},
+				Spec: contour_api_v1alpha1.ContourDeploymentSpec{
+					Envoy: &contour_api_v1alpha1.EnvoySettings{
+						DrainStrategy: contour_api_v1alpha1.DrainStrategyGradual,
+					},
+					RuntimeSettings: contourDeploymentRuntimeSettings(),
+				},
+			})
+			require.NoError(f.T(), err)
+
+			gateway := &gatewayapi_v1beta1.Gateway{}
+			require.Eventually(f.T(), func() bool {
+				if err := f.Client.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: "gradual-drain"}, gateway); err != nil {
+					return false
+				}
+				return gatewayHasAddress(gateway)
+			}, f.RetryTimeout, f.RetryInterval)
+
+			f.Fixtures.Echo.Deploy(namespace, "echo")
+
+			route := &gatewayapi_v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "httproute-1",
+				},
+				Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+					Hostnames: []gatewayapi_v1beta1.Hostname{"gradual-drain.provisioner.projectcontour.io"},
+					CommonRouteSpec: gatewayapi_v1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1beta1.ParentReference{
+							gatewayapi.GatewayParentRef("", gateway.Name),
+						},
+					},
+					Rules: []gatewayapi_v1beta1.HTTPRouteRule{
+						{
+							Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1beta1.PathMatchPathPrefix, "/"),
+							BackendRefs: gatewayapi.HTTPBackendRef("echo", 80, 1),
+						},
+					},
+				},
+			}
+			_, ok := f.CreateHTTPRouteAndWaitFor(route, httpRouteAccepted)
+			require.True(f.T(), ok)
+
+			addr := net.JoinHostPort(gateway.Status.Addresses[0].Value, "80")
+			res, ok := f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+				OverrideURL: "http://" + addr,
+				Host:        string(route.Spec.Hostnames[0]),
+				Condition:   e2e.HasStatusCode(200),
+			})
+			require.NotNil(f.T(), res)
+			require.Truef(f.T(), ok, "expected 200 response code, got %d", res.StatusCode)
+
+			// request sends a request on a kept-alive connection, and
+			// returns whether Envoy asked for the connection to be closed.
+			type keepAliveConn struct {
+				net.Conn
+				r *bufio.Reader
+			}
+			request := func(c *keepAliveConn) bool {
+				req, err := http.NewRequest(http.MethodGet, "http://"+string(route.Spec.Hostnames[0])+"/", nil)
+				require.NoError(f.T(), err)
+				require.NoError(f.T(), c.SetDeadline(time.Now().Add(5*time.Second)))
+				require.NoError(f.T(), req.Write(c))
+				resp, err := http.ReadResponse(c.r, req)
+				require.NoError(f.T(), err)
+				_, err = io.Copy(io.Discard, resp.Body)
+				require.NoError(f.T(), err)
+				require.NoError(f.T(), resp.Body.Close())
+				require.Equal(f.T(), http.StatusOK, resp.StatusCode)
+				return resp.Close
+			}
+
+			const numConns = 20
+			var conns []*keepAliveConn
+			for i := 0; i < numConns; i++ {
+				conn, err := net.Dial("tcp", addr)
+				require.NoError(f.T(), err)
+				defer conn.Close()
+
+				c := &keepAliveConn{Conn: conn, r: bufio.NewReader(conn)}
+				require.False(f.T(), request(c), "connection closed before draining")
+				conns = append(conns, c)
+			}
+
+			// Deleting the Envoy pods runs their preStop hooks, which
+			// start draining. Pods are given a long termination grace
+			// period, so they keep serving while we watch.
+			require.NoError(f.T(), f.Client.DeleteAllOf(context.Background(), &corev1.Pod{},
+				client.InNamespace(namespace), client.MatchingLabels{"app": "envoy-" + gateway.Name}))
+			drainStart := time.Now()
+
+			// With gradual draining, the chance of a request closing its
+			// connection grows over Envoy's drain time (600s by default),
+			// so over half a minute only some of the connections should
+			// close, and not all at the start.
+			var closedAt []time.Duration
+			for time.Since(drainStart) < 30*time.Second {
+				var open []*keepAliveConn
+				for _, c := range conns {
+					if request(c) {
+						closedAt = append(closedAt, time.Since(drainStart))
+						continue
+					}
+					open = append(open, c)
+				}
+				conns = open
+				time.Sleep(time.Second)
+			}
+
+			var closedEarly int
+			for _, d := range closedAt {
+				if d < 5*time.Second {
+					closedEarly++
+				}
+			}
+			assert.Less(f.T(), closedEarly, numConns/2, "too many connections closed as soon as draining started: %v", closedAt)
+			assert.Greater(f.T(), len(closedAt), closedEarly, "no connections closed after draining started: %v", closedAt)
+			assert.NotEmpty(f.T(), conns, "all connections closed within the drain window")
+
+			require.NoError(f.T(), f.DeleteGatewayClass(gatewayClass, false))
+		})
+	})
 })
 
 // gatewayClassAccepted returns true if the gateway has a .status.conditions
@@ -379,6 +3114,22 @@ func httpRouteAccepted(route *gatewayapi_v1beta1.HTTPRoute) bool {
 	return false
 }
 
+// httpRouteRefsNotResolved returns true if the route has a
+// .status.conditions entry of "ResolvedRefs: false".
+func httpRouteRefsNotResolved(route *gatewayapi_v1beta1.HTTPRoute) bool {
+	if route == nil {
+		return false
+	}
+
+	for _, gw := range route.Status.Parents {
+		if conditionExists(gw.Conditions, string(gatewayapi_v1beta1.RouteConditionResolvedRefs), metav1.ConditionFalse) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func conditionExists(conditions []metav1.Condition, conditionType string, conditionStatus metav1.ConditionStatus) bool {
 	for _, cond := range conditions {
 		if cond.Type == conditionType && cond.Status == conditionStatus {
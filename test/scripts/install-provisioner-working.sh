@@ -47,6 +47,10 @@ ${KUBECTL} apply -f <(cat examples/gateway-provisioner/03-gateway-provisioner.ya
     yq eval '.spec.template.spec.containers[0].image = env(CONTOUR_IMG)' - | \
     yq eval '.spec.template.spec.containers[0].imagePullPolicy = "IfNotPresent"' - | \
     yq eval '.spec.template.spec.containers[0].args += "--contour-image="+env(CONTOUR_IMG)' -)
+${KUBECTL} apply -f examples/gateway-provisioner/04-admission-webhook.yaml
+
+# Wait for the ContourDeployment webhook's certificate to be generated.
+${KUBECTL} wait --timeout="${WAITTIME}" -n projectcontour -l app=contour-gateway-provisioner-webhook-certgen jobs --for=condition=Complete
 
 # Wait for the provisioner to report "Ready" status.
 ${KUBECTL} wait --timeout="${WAITTIME}" -n projectcontour -l control-plane=contour-gateway-provisioner deployments --for=condition=Available
@@ -31,6 +31,7 @@ ${KUBECTL} apply -f <(cat examples/gateway-provisioner/03-gateway-provisioner.ya
     yq eval '.spec.template.spec.containers[0].image = env(CONTOUR_IMG)' - | \
     yq eval '.spec.template.spec.containers[0].imagePullPolicy = "IfNotPresent"' - | \
     yq eval '.spec.template.spec.containers[0].args += "--contour-image="+env(CONTOUR_IMG)' -)
+${KUBECTL} apply -f examples/gateway-provisioner/04-admission-webhook.yaml
 
 ${KUBECTL} apply -f - <<EOF
 kind: GatewayClass